@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Quick action IDs accepted by RunAction.
+const (
+	ActionConvertClipboard = "convert-clipboard"
+	ActionOpenLastOutput   = "open-last-output"
+	ActionRerunLastJob     = "rerun-last-job"
+	ActionCheckUpdates     = "check-updates"
+	ActionOpenLogFolder    = "open-log-folder"
+)
+
+// Action describes a quick action the frontend can list and run by ID.
+type Action struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// ActionResult is the outcome of a quick action.
+type ActionResult struct {
+	// Message is a short, user-facing description of what the action did
+	Message string `json:"message"`
+	// JobID is set by actions that started a conversion (see GetJobStatus)
+	JobID string `json:"jobId,omitempty"`
+	// Data carries action-specific details, e.g. the UpdateInfo of check-updates
+	Data any `json:"data,omitempty"`
+}
+
+// quickAction is an Action with its implementation.
+type quickAction struct {
+	Action
+	run func(a *App) (ActionResult, error)
+}
+
+// quickActions is the registry behind ListActions and RunAction.
+// Why: A command palette lists and runs actions by ID, so a new action is one entry
+// here instead of a new binding and frontend wiring.
+var quickActions = []quickAction{
+	{
+		Action: Action{
+			ID:          ActionConvertClipboard,
+			Title:       "Convert clipboard",
+			Description: "Convert legacy text on the clipboard to Unicode",
+		},
+		run: (*App).convertClipboard,
+	},
+	{
+		Action: Action{
+			ID:          ActionOpenLastOutput,
+			Title:       "Open last output",
+			Description: "Show the last converted workbook in its folder",
+		},
+		run: (*App).openLastOutput,
+	},
+	{
+		Action: Action{
+			ID:          ActionRerunLastJob,
+			Title:       "Re-run last job",
+			Description: "Convert the last files again with the same settings",
+		},
+		run: (*App).rerunLastJob,
+	},
+	{
+		Action: Action{
+			ID:          ActionOpenLogFolder,
+			Title:       "Open log folder",
+			Description: "Show the log files to attach to a bug report",
+		},
+		run: func(a *App) (ActionResult, error) {
+			if err := a.OpenLogFolder(); err != nil {
+				return ActionResult{}, err
+			}
+			return ActionResult{Message: "Opened the log folder."}, nil
+		},
+	},
+	{
+		Action: Action{
+			ID:          ActionCheckUpdates,
+			Title:       "Check for updates",
+			Description: "Look for a newer release",
+		},
+		run: func(a *App) (ActionResult, error) {
+			info := a.CheckForUpdate()
+			msg := "You are running the latest version."
+			if info.Available {
+				msg = fmt.Sprintf("Version %s is available.", info.LatestVer)
+			}
+			return ActionResult{Message: msg, Data: info}, nil
+		},
+	},
+}
+
+// ListActions returns the quick actions, sorted by title.
+func (a *App) ListActions() []Action {
+	list := make([]Action, 0, len(quickActions))
+	for _, qa := range quickActions {
+		list = append(list, qa.Action)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Title < list[j].Title })
+	return list
+}
+
+// RunAction runs the quick action with the given ID.
+func (a *App) RunAction(id string) (ActionResult, error) {
+	for _, qa := range quickActions {
+		if qa.ID == id {
+			return qa.run(a)
+		}
+	}
+	return ActionResult{}, fmt.Errorf("unknown action %q", id)
+}
+
+// rememberJob records cfg as the last job for the re-run action.
+func (a *App) rememberJob(cfg Config) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lastConfig = &cfg
+}
+
+// convertClipboard replaces legacy text on the clipboard with its Unicode conversion.
+func (a *App) convertClipboard() (ActionResult, error) {
+	text, converted, enc, err := a.replaceClipboard()
+	if err != nil {
+		return ActionResult{}, err
+	}
+	if converted == text {
+		return ActionResult{Message: "The clipboard holds no legacy text."}, nil
+	}
+	return ActionResult{Message: fmt.Sprintf("Converted the clipboard from %s.", enc), Data: converted}, nil
+}
+
+// openLastOutput shows the last output of this session in the file explorer.
+func (a *App) openLastOutput() (ActionResult, error) {
+	a.mu.Lock()
+	path := a.lastOutput
+	a.mu.Unlock()
+	if path == "" {
+		return ActionResult{}, fmt.Errorf("no file has been converted yet")
+	}
+	a.ShowInFolder(path)
+	return ActionResult{Message: "Opened " + path, Data: path}, nil
+}
+
+// rerunLastJob starts the last job of this session again with the same settings.
+func (a *App) rerunLastJob() (ActionResult, error) {
+	a.mu.Lock()
+	cfg := a.lastConfig
+	a.mu.Unlock()
+	if cfg == nil {
+		return ActionResult{}, fmt.Errorf("no job has been run yet")
+	}
+	jobID, err := a.StartJob(*cfg)
+	if err != nil {
+		return ActionResult{}, err
+	}
+	return ActionResult{Message: "Started " + jobID, JobID: jobID}, nil
+}
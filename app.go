@@ -3,32 +3,334 @@ package main
 
 import (
 	"context"
+	"convert-vni-to-unicode/internal/cache"
+	"convert-vni-to-unicode/internal/converter"
 	"convert-vni-to-unicode/internal/engine"
+	"convert-vni-to-unicode/internal/history"
+	"convert-vni-to-unicode/internal/i18n"
+	"convert-vni-to-unicode/internal/notify"
+	"convert-vni-to-unicode/internal/pdf"
+	"convert-vni-to-unicode/internal/settings"
+	"convert-vni-to-unicode/internal/spell"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// progressTextInterval throttles "progressText" events.
+const progressTextInterval = time.Second
+
+// shutdownTimeout bounds how long closing the window waits for a cancelled run to stop.
+const shutdownTimeout = 10 * time.Second
+
 // App struct
 type App struct {
-	ctx context.Context
+	ctx       context.Context
+	buildInfo engine.BuildInfo
+	settings  *settings.Store // nil when the config dir is unavailable
+
+	// Queued, running and recently finished conversions
+	jobs *JobManager
+
+	mu sync.Mutex
+	// Shared across jobs so the limit applies to the whole app, guarded by mu
+	throttle    *engine.IOThrottle
+	throttleKey string
+
+	// Opened lazily next to the settings file, guarded by mu
+	results *cache.ResultCache
+	history *history.Store
+
+	// Cancels the running update download, guarded by mu
+	updateCancel context.CancelFunc
+	// Swap script to run on exit (see ScheduleUpdateOnExit), guarded by mu
+	pendingUpdate string
+	// Release tag from the last update check, guarded by mu
+	latestVersion string
+
+	// Last job and output of this session for the quick actions, guarded by mu
+	lastConfig *Config
+	lastOutput string
+
+	// The watch folder started from the GUI, guarded by watchMu (see StartWatch)
+	watchMu     sync.Mutex
+	watchCancel context.CancelFunc
+	watchDone   chan struct{}
+	watchStatus WatchStatus
+
+	// startLink is the conversion link the app was launched with, opened once the page loaded
+	startLink string
+	// startFile is the file the app was launched on, opened once the page loaded; domLoaded
+	// is set then. Both are guarded by mu (macOS may hand over files before the page loads).
+	startFile string
+	domLoaded bool
 }
 
 // NewApp creates a new App application struct
-func NewApp() *App {
-	return &App{}
+func NewApp(store *settings.Store) *App {
+	a := &App{
+		buildInfo: engine.NewBuildInfo(CurrentVersion),
+		settings:  store,
+	}
+	a.jobs = newJobManager(
+		func() int { return a.loadSettings().MaxJobs },
+		func(name string, data any) { runtime.EventsEmit(a.ctx, name, data) },
+	)
+	return a
 }
 
 // startup is called when the app starts
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	// Log header so support can see which build produced a log
+	slog.Info("starting", a.buildInfo.LogAttrs()...)
+	a.restoreWindowPosition()
+	if err := registerLinkScheme(); err != nil {
+		slog.Warn("conversion links unavailable", "error", err)
+	}
+	if err := registerOpenWith(); err != nil {
+		slog.Warn("open with unavailable", "error", err)
+	}
+}
+
+// domReady is called once the frontend loaded, so it receives the events of a start link
+// or file.
+func (a *App) domReady(_ context.Context) {
+	a.mu.Lock()
+	a.domLoaded = true
+	startFile := a.startFile
+	a.startFile = ""
+	a.mu.Unlock()
+	if a.startLink != "" {
+		a.openLink(a.startLink)
+	} else if startFile != "" {
+		a.openFile(startFile)
+	}
+}
+
+// shutdown is called when the app is terminating
+func (a *App) shutdown(_ context.Context) {
+	a.StopWatch()
+	a.closeHistory()
+	a.runPendingUpdate()
+}
+
+// loadSettings returns the persisted settings or defaults.
+func (a *App) loadSettings() settings.Settings {
+	if a.settings == nil {
+		return settings.Default()
+	}
+	prefs, err := a.settings.Load()
+	if err != nil {
+		slog.Warn("failed to load settings", "error", err)
+	}
+	return prefs
+}
+
+// restoreWindowPosition moves the window to its last saved position.
+// Why: Wails options only carry the size; the position must be applied at runtime.
+func (a *App) restoreWindowPosition() {
+	prefs := a.loadSettings()
+	if prefs.Window.HasPos && !prefs.Window.Maximized {
+		runtime.WindowSetPosition(a.ctx, prefs.Window.X, prefs.Window.Y)
+	}
+}
+
+// saveWindowState persists the current window geometry.
+func (a *App) saveWindowState(ctx context.Context) {
+	if a.settings == nil {
+		return
+	}
+	maximized := runtime.WindowIsMaximised(ctx)
+	width, height := runtime.WindowGetSize(ctx)
+	x, y := runtime.WindowGetPosition(ctx)
+
+	err := a.settings.Update(func(s *settings.Settings) {
+		s.Window.Maximized = maximized
+		// Keep the restored geometry when maximized so un-maximizing still works
+		if !maximized {
+			s.Window.Width, s.Window.Height = width, height
+			s.Window.X, s.Window.Y = x, y
+			s.Window.HasPos = true
+		}
+	})
+	if err != nil {
+		slog.Error("failed to save window state", "error", err)
+	}
+}
+
+// GetTheme returns the persisted UI theme ("dark" or "light").
+func (a *App) GetTheme() string {
+	return a.loadSettings().Theme
+}
+
+// ioThrottle returns the shared I/O throttle, or nil when throttling is disabled.
+// Why: Network shares need a limit across all running jobs, not per job.
+func (a *App) ioThrottle() *engine.IOThrottle {
+	prefs := a.loadSettings()
+	if !prefs.ThrottleIO {
+		return nil
+	}
+	key := fmt.Sprintf("%d/%d", prefs.MaxOpenFiles, prefs.InterFileDelayMs)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.throttle == nil || a.throttleKey != key {
+		delay := time.Duration(prefs.InterFileDelayMs) * time.Millisecond
+		a.throttle = engine.NewIOThrottle(prefs.MaxOpenFiles, delay)
+		a.throttleKey = key
+	}
+	return a.throttle
+}
+
+// resultCache returns the conversion result cache, or nil when it is unavailable.
+func (a *App) resultCache() *cache.ResultCache {
+	if a.settings == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.results == nil {
+		path := filepath.Join(filepath.Dir(a.settings.Path()), "results.json")
+		results, err := cache.Open(path)
+		if err != nil {
+			slog.Warn("failed to open result cache", "error", err)
+			return nil
+		}
+		a.results = results
+	}
+	return a.results
+}
+
+// cacheKey returns the input hash and a fingerprint of every setting that affects the output.
+// An empty hash disables caching for this run.
+func (a *App) cacheKey(cfg Config, prefs settings.Settings) (string, string) {
+	inputHash, err := cache.HashFile(cfg.InputPath)
+	if err != nil {
+		return "", ""
+	}
+	settingsKey := fmt.Sprintf("sheet=%s;range=%s;columns=%s;encoding=%s;font=%s;detector=%s;vni=%s;"+
+		"normalization=%s;maxlen=%d;keepsheetnames=%t;remapstylefonts=%t;plaincells=%t;large=%t;version=%s",
+		cfg.SheetName, cfg.CellRange, cfg.Columns, cfg.Encoding, prefs.FontPolicy, prefs.Detector, prefs.VNIStrictness,
+		prefs.OutputNormalization, prefs.MaxCellLength, prefs.KeepSheetNames, prefs.RemapStyleFonts, prefs.PlainCells,
+		cfg.LargeFileMode, a.buildInfo.AppVersion)
+	return inputHash, settingsKey
+}
+
+// GetFontPolicy returns the output font policy of the settings, "map" when none is set.
+func (a *App) GetFontPolicy() string {
+	if name := a.loadSettings().FontPolicy; name != "" {
+		return name
+	}
+	return engine.FontPolicyMapOrDefault
+}
+
+// SetFontPolicy persists the output font policy ("keep", "map", "default", "per-encoding").
+func (a *App) SetFontPolicy(name string) error {
+	if _, err := engine.NewFontPolicy(name); err != nil {
+		return err
+	}
+	if a.settings == nil {
+		return fmt.Errorf("settings are unavailable")
+	}
+	return a.settings.Update(func(s *settings.Settings) { s.FontPolicy = name })
+}
+
+// SetDetector persists the auto-detect implementation ("rules" or "ngram").
+func (a *App) SetDetector(name string) error {
+	if _, err := engine.NewDetector(name); err != nil {
+		return err
+	}
+	if a.settings == nil {
+		return fmt.Errorf("settings are unavailable")
+	}
+	return a.settings.Update(func(s *settings.Settings) { s.Detector = name })
+}
+
+// GetTimestampFormat returns the persisted output name suffix format.
+func (a *App) GetTimestampFormat() string {
+	if format := a.loadSettings().TimestampFormat; format != "" {
+		return format
+	}
+	return engine.DefaultTimestampFormat
+}
+
+// GetTimestampPresets returns the suggested output name suffix formats.
+func (a *App) GetTimestampPresets() []string {
+	return engine.TimestampPresets
+}
+
+// SetTimestampFormat persists the output name suffix format (e.g. "yyyyMMdd_HHmmss").
+func (a *App) SetTimestampFormat(format string) error {
+	if _, err := engine.ParseTimestampFormat(format); err != nil {
+		return err
+	}
+	if a.settings == nil {
+		return fmt.Errorf("settings are unavailable")
+	}
+	return a.settings.Update(func(s *settings.Settings) { s.TimestampFormat = format })
+}
+
+// SetTheme persists the UI theme and updates the native title bar.
+func (a *App) SetTheme(theme string) error {
+	if theme != settings.ThemeDark && theme != settings.ThemeLight {
+		return fmt.Errorf("unknown theme %q", theme)
+	}
+	if theme == settings.ThemeLight {
+		runtime.WindowSetLightTheme(a.ctx)
+	} else {
+		runtime.WindowSetDarkTheme(a.ctx)
+	}
+	if a.settings == nil {
+		return nil
+	}
+	return a.settings.Update(func(s *settings.Settings) { s.Theme = theme })
 }
 
 // Config holds the processing configuration from Frontend
 // Why: Standard DTO for passing parameters.
 type Config struct {
 	InputPath string `json:"inputPath"`
+	// InputPaths converts several files in one job; takes precedence over InputPath
+	InputPaths []string `json:"inputPaths"`
+	// Parallel is the number of files converted at once in a batch (0 or 1: sequential)
+	Parallel int `json:"parallel"`
+	// Workers is the number of workers converting the cells of each file (0: one per CPU, fewer for small files)
+	Workers   int    `json:"workers"`
 	SheetName string `json:"sheetName"` // Optional
+	// CellRange converts only the cells of a range such as "A1:D500" (empty: all cells)
+	CellRange string `json:"cellRange"`
+	// Columns converts only these columns, such as "C" or "C,E:F" (empty: all columns)
+	Columns string `json:"columns"`
+	// Encoding forces the source encoding ("VNI", "TCVN3", "VIQR", ...); empty or "AUTO" detects per cell
+	Encoding string `json:"encoding"`
+	// TimingReport writes a per-cell CSV timing report next to the output (support diagnostics).
+	TimingReport bool `json:"timingReport"`
+	// DetectionTrace writes a CSV next to the output naming the detection rule used for every run (support diagnostics).
+	DetectionTrace bool `json:"detectionTrace"`
+	// FontReport writes <output>_fonts.csv counting the text cells of every font before and after conversion.
+	FontReport bool `json:"fontReport"`
+	// SpellCheck writes <output>_spelling.csv listing converted cells with words that are not Vietnamese.
+	SpellCheck bool `json:"spellCheck"`
+	// ExportPDF also saves the output as <output>.pdf with a headless LibreOffice.
+	ExportPDF bool `json:"exportPDF"`
+	// ChangeReport writes <output>_changes.<format> listing every modified cell ("xlsx", "csv", "json"; empty disables).
+	ChangeReport string `json:"changeReport"`
+	// InPlace overwrites the input after keeping the original ("bak": <name>.xlsx.bak,
+	// "folder": backup/<name>.xlsx; empty writes a new file)
+	InPlace string `json:"inPlace"`
+	// LargeFileMode writes the output sheet by sheet with bounded memory, dropping comments, charts and images.
+	LargeFileMode bool `json:"largeFileMode"`
+	// Force reconverts even when an identical input was already converted with the same settings.
+	Force bool `json:"force"`
 }
 
 // ProcessResult holds the result to send back to Frontend
@@ -36,15 +338,39 @@ type ProcessResult struct {
 	Success    bool   `json:"success"`
 	Message    string `json:"message"`
 	OutputPath string `json:"outputPath"`
+	// BackupPath is where an in-place run kept the original workbook
+	BackupPath string `json:"backupPath,omitempty"`
+	// SkippedCells lists cells over the maximum length that were left unconverted
+	SkippedCells []engine.SkippedCell `json:"skippedCells,omitempty"`
+	// TruncatedCells lists cells cut to Excel's 32,767-character limit
+	TruncatedCells []engine.TruncatedCell `json:"truncatedCells,omitempty"`
+	// RenamedSheets lists sheet tabs converted from a legacy encoding
+	RenamedSheets []engine.RenamedSheet `json:"renamedSheets,omitempty"`
+	// Files holds per-file outcomes of a batch run
+	Files []engine.FileResult `json:"files,omitempty"`
+	// Errors lists the cells, rows and sheets that failed and were left unconverted
+	Errors []engine.CellError `json:"errors,omitempty"`
 }
 
-// SelectFile opens a file dialog to select the Excel file
+// SelectFile opens a file dialog to select the Excel or text file
 // Why: Native dialog for better UX.
 func (a *App) SelectFile() (string, error) {
 	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
-		Title: "Select Excel File",
+		Title: "Select Excel or Text File",
 		Filters: []runtime.FileFilter{
-			{DisplayName: "Excel Files", Pattern: "*.xlsx"},
+			{DisplayName: "Spreadsheets, Text, Word and PowerPoint Files", Pattern: "*.xlsx;*.ods;*.txt;*.docx;*.pptx"},
+			{DisplayName: "Old Excel and Lotus 1-2-3 Files", Pattern: legacyWorkbookPattern},
+		},
+	})
+}
+
+// SelectFiles opens a file dialog allowing several Excel or text files to be selected
+func (a *App) SelectFiles() ([]string, error) {
+	return runtime.OpenMultipleFilesDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Select Excel or Text Files",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Spreadsheets, Text, Word and PowerPoint Files", Pattern: "*.xlsx;*.ods;*.txt;*.docx;*.pptx"},
+			{DisplayName: "Old Excel and Lotus 1-2-3 Files", Pattern: legacyWorkbookPattern},
 		},
 	})
 }
@@ -52,36 +378,723 @@ func (a *App) SelectFile() (string, error) {
 // Process runs the conversion
 // Why: Main entry point for the frontend to trigger logic.
 func (a *App) Process(cfg Config) ProcessResult {
-	if cfg.InputPath == "" {
+	if cfg.InputPath == "" && len(cfg.InputPaths) == 0 {
 		return ProcessResult{Success: false, Message: "Please select an input file"}
 	}
 
-	// Create processor
-	p := engine.NewProcessor(cfg.InputPath, cfg.SheetName)
+	// Queued like StartJob, so closing the window can cancel it
+	j := a.submitJob(cfg)
+	<-j.done
+	return *j.snapshot().Result
+}
 
-	// Setup progress tracing
-	progressChan := make(chan float64, 100)
-	p.SetProgressChan(progressChan)
+// execute runs cfg as a batch or a single-file conversion, notifying the GUI and the
+// notifications configured in the settings.
+func (a *App) execute(j *job, cfg Config) ProcessResult {
+	j.notifier = a.notifier()
+	files := cfg.InputPaths
+	if len(files) == 0 {
+		files = []string{cfg.InputPath}
+	}
+	j.notifier.OnStart(notify.Start{JobID: j.id, Files: files})
+	started := time.Now()
 
-	// Stream progress to frontend
-	go func() {
-		for prog := range progressChan {
-			runtime.EventsEmit(a.ctx, "progress", prog)
+	var res ProcessResult
+	var failures []notify.Failure
+	if len(cfg.InputPaths) > 0 {
+		res = a.runBatch(j, cfg)
+		for _, f := range res.Files {
+			if f.Error != "" {
+				failures = append(failures, notify.Failure{JobID: j.id, File: f.InputPath, Error: f.Error})
+			}
+		}
+	} else {
+		res = a.runJob(j, cfg)
+		if !res.Success {
+			failure := notify.Failure{JobID: j.id, File: cfg.InputPath, Error: res.Message}
+			failures = append(failures, failure)
+			j.notifier.OnError(failure)
+		}
+	}
+	j.notifier.OnComplete(notify.Summary{
+		JobID:    j.id,
+		Files:    len(files),
+		Failures: failures,
+		Duration: time.Since(started),
+	})
+	return res
+}
+
+// notifier returns the GUI's notify events plus the notifications configured in the settings.
+func (a *App) notifier() notify.Notifier {
+	prefs := a.loadSettings()
+	n, err := openNotifier(prefs.NotifyWebhook, prefs.NotifyToast)
+	if err != nil {
+		slog.Warn("notifications unavailable", "error", err)
+	}
+	events := notify.Events{Emit: func(name string, data any) { runtime.EventsEmit(a.ctx, name, data) }}
+	return append(notify.Multi{events}, n...)
+}
+
+// wantsReports reports whether the job writes reports or a PDF next to its output,
+// which need a real run rather than a cached or copied output.
+func (c Config) wantsReports() bool {
+	return c.DetectionTrace || c.FontReport || c.SpellCheck || c.ExportPDF || c.ChangeReport != ""
+}
+
+// runBatch converts every file of cfg.InputPaths, up to cfg.Parallel at a time, emitting
+// "job:file" events per file and "job:progress" events for the whole batch.
+// Files are ordered by the persisted queue order and each is converted like a single run.
+func (a *App) runBatch(j *job, cfg Config) ProcessResult {
+	order, err := engine.ParseQueueOrder(a.loadSettings().QueueOrder)
+	if err != nil {
+		order = engine.OrderManual
+	}
+	paths := engine.OrderPaths(cfg.InputPaths, order)
+	j.updateStatus(func(s *JobStatus) { s.FilesTotal = len(paths) })
+	j.batch = engine.NewBatchProgress(paths)
+
+	convert := func(_ context.Context, path string) (string, error) {
+		fileCfg := cfg
+		fileCfg.InputPath, fileCfg.InputPaths = path, nil
+		res := a.runJob(j, fileCfg)
+		if !res.Success {
+			j.notifier.OnError(notify.Failure{JobID: j.id, File: path, Error: res.Message})
+			return "", fmt.Errorf("%s", res.Message)
+		}
+		return res.OutputPath, nil
+	}
+
+	progress := make(chan engine.FileProgress, 100)
+	go a.emitBatchProgress(j, progress)
+	files := engine.RunBatchDeduped(j.ctx, paths, cfg.Parallel, convert, a.batchCopyOutput(j, cfg), progress)
+	close(progress)
+
+	failed, duplicates := 0, 0
+	for _, f := range files {
+		switch {
+		case f.Error != "":
+			failed++
+		case f.DuplicateOf != "":
+			duplicates++
 		}
-	}()
+	}
+	message := fmt.Sprintf("%d of %d file(s) converted.", len(files)-failed, len(files))
+	if duplicates > 0 {
+		message += fmt.Sprintf(" %d identical file(s) were converted once and the output copied.", duplicates)
+	}
+	return ProcessResult{
+		Success: failed == 0,
+		Message: message,
+		Files:   files,
+	}
+}
+
+// emitBatchProgress emits "job:file" for every file event of a batch and "job:progress"
+// for the whole batch whenever a file is done, until progress is closed.
+func (a *App) emitBatchProgress(j *job, progress <-chan engine.FileProgress) {
+	for fp := range progress {
+		if fp.State == engine.FileDone || fp.State == engine.FileFailed {
+			st := j.batch.Finish(fp.InputPath)
+			update := ProgressUpdate{Percent: st.Percent, ETASeconds: st.ETA.Seconds()}
+			j.updateStatus(func(s *JobStatus) {
+				s.FilesDone++
+				s.ProgressUpdate, s.Processed, s.Total = update, st.Processed, st.Total
+			})
+			runtime.EventsEmit(a.ctx, "job:progress",
+				JobProgress{JobID: j.id, ProgressUpdate: update, Processed: st.Processed, Total: st.Total})
+		}
+		runtime.EventsEmit(a.ctx, "job:file", JobFileProgress{JobID: j.id, FileProgress: fp})
+	}
+}
+
+// batchCopyOutput returns how a batch copies the output of a byte-identical file, or nil
+// when each file needs a real run (reports, or in place).
+func (a *App) batchCopyOutput(j *job, cfg Config) engine.CopyOutputFunc {
+	backupMode, err := engine.ParseBackupMode(cfg.InPlace)
+	if err != nil || backupMode != engine.BackupNone || cfg.wantsReports() || cfg.TimingReport {
+		return nil
+	}
+	return func(input, original, originalOutput string) (string, error) {
+		outputPath, err := engine.CopyDuplicateOutput(input, original, originalOutput)
+		if err != nil {
+			return "", err
+		}
+		j.recordConverted(input, outputPath, "")
+		return outputPath, nil
+	}
+}
+
+// runJob executes a single conversion, streaming progress tagged with the job ID.
+func (a *App) runJob(j *job, cfg Config) ProcessResult {
+	prefs := a.loadSettings()
+	backupMode, err := engine.ParseBackupMode(cfg.InPlace)
+	if err != nil {
+		return ProcessResult{Success: false, Message: err.Error()}
+	}
+
+	// Skip files that were already converted with identical settings (reports need a real run,
+	// and an in-place output replaces its own input, so it is never cached)
+	inputHash, settingsKey := "", ""
+	if backupMode == engine.BackupNone {
+		inputHash, settingsKey = a.cacheKey(cfg, prefs)
+	}
+	if outputPath, ok := a.cachedOutput(cfg, inputHash, settingsKey); ok {
+		return ProcessResult{
+			Success:    true,
+			Message:    "File was already converted with these settings; returning the existing output.",
+			OutputPath: outputPath,
+		}
+	}
+
+	// Old formats are converted from an .xlsx copy imported with LibreOffice
+	input, legacyFormat, cleanup, err := importLegacyWorkbook(j.ctx, cfg.InputPath, prefs.ConvertLegacyFormats,
+		prefs.OfficePath, backupMode)
+	if err != nil {
+		return ProcessResult{Success: false, Message: err.Error()}
+	}
+	defer cleanup()
+	processCfg := cfg
+	processCfg.InputPath = input
+
+	p, err := a.newProcessor(processCfg, prefs)
+	if err != nil {
+		return ProcessResult{Success: false, Message: err.Error()}
+	}
+	if legacyFormat != "" {
+		p.SetOutputDir(filepath.Dir(cfg.InputPath))
+	}
+	reports, err := a.attachReports(p, cfg, prefs)
+	if err != nil {
+		return ProcessResult{Success: false, Message: err.Error()}
+	}
+	p.SetInPlace(backupMode)
+	p.SetLargeFileMode(cfg.LargeFileMode)
+
+	// Stream progress to frontend
+	statusChan := make(chan engine.Status, 100)
+	p.SetStatusChan(statusChan)
+	defer close(statusChan) // Run no longer sends once it returns
+	go a.streamProgress(j, cfg.InputPath, statusChan)
 
 	// Run conversion
 	// Note: Run blocks until completion.
-	outputPath, err := p.Run(a.ctx)
+	started := time.Now()
+	outputPath, err := p.Run(j.ctx)
+	duration := time.Since(started)
+	cellErrors := p.CellErrors()
+	if len(cellErrors) > 0 {
+		runtime.EventsEmit(a.ctx, "errors", JobErrors{JobID: j.id, File: cfg.InputPath, Errors: cellErrors})
+	}
 	if err != nil {
-		return ProcessResult{Success: false, Message: err.Error()}
+		return ProcessResult{Success: false, Message: err.Error(), Errors: cellErrors}
 	}
+	reports.write(j.ctx, outputPath)
+
+	if results := a.resultCache(); results != nil && inputHash != "" {
+		if err := results.Store(inputHash, settingsKey, outputPath); err != nil {
+			slog.Warn("failed to update result cache", "error", err)
+		}
+	}
+	a.recordHistory(cfg.InputPath, outputPath, p.Stats(), duration)
+	j.recordConverted(cfg.InputPath, outputPath, p.BackupPath())
 
 	return ProcessResult{
-		Success:    true,
-		Message:    "Conversion completed successfully!",
-		OutputPath: outputPath,
+		Success:        true,
+		Message:        completionMessage(p, cfg.InputPath, legacyFormat, reports),
+		OutputPath:     outputPath,
+		BackupPath:     p.BackupPath(),
+		SkippedCells:   p.SkippedCells(),
+		TruncatedCells: p.TruncatedCells(),
+		RenamedSheets:  p.RenamedSheets(),
+		Errors:         cellErrors,
+	}
+}
+
+// cachedOutput returns the output of an earlier conversion of the same input with the
+// same settings, unless the job is forced or writes reports.
+func (a *App) cachedOutput(cfg Config, inputHash, settingsKey string) (string, bool) {
+	if cfg.Force || cfg.wantsReports() || inputHash == "" {
+		return "", false
+	}
+	results := a.resultCache()
+	if results == nil {
+		return "", false
+	}
+	return results.Lookup(inputHash, settingsKey)
+}
+
+// jobReports are the reports a job writes next to its output.
+type jobReports struct {
+	timing     *engine.TimingRecorder
+	detections *engine.DetectionRecorder
+	changes    *engine.ChangeRecorder
+	format     engine.ReportFormat
+	fonts      *engine.FontReport
+	spelling   *engine.SpellReport
+	exporter   *pdf.Exporter
+	pdfPath    string
+	pdfErr     error
+}
+
+// attachReports creates the reports cfg asks for and attaches them to p.
+func (a *App) attachReports(p *engine.Processor, cfg Config, prefs settings.Settings) (*jobReports, error) {
+	reports := &jobReports{}
+	var err error
+	if cfg.TimingReport {
+		reports.timing = engine.NewTimingRecorder()
+		reports.timing.SetBuildInfo(a.buildInfo)
+		p.SetTracer(reports.timing)
+	}
+	if cfg.DetectionTrace {
+		reports.detections = engine.NewDetectionRecorder()
+		reports.detections.SetBuildInfo(a.buildInfo)
+		p.SetDetectionTrace(reports.detections)
+	}
+	if cfg.ChangeReport != "" {
+		if reports.format, err = engine.ParseReportFormat(cfg.ChangeReport); err != nil {
+			return nil, err
+		}
+		reports.changes = engine.NewChangeRecorder()
+		p.SetChangeReport(reports.changes)
+	}
+	if cfg.FontReport {
+		reports.fonts = engine.NewFontReport()
+		p.SetFontReport(reports.fonts)
+	}
+	if cfg.SpellCheck {
+		var checker *spell.Checker
+		if checker, err = loadSpellChecker(prefs.SpellDictionary); err != nil {
+			return nil, err
+		}
+		reports.spelling = engine.NewSpellReport(checker)
+		p.SetSpellReport(reports.spelling)
+	}
+	// Fail before converting when LibreOffice is missing, not after
+	if cfg.ExportPDF {
+		if reports.exporter, err = pdf.NewExporter(prefs.OfficePath); err != nil {
+			return nil, err
+		}
+	}
+	return reports, nil
+}
+
+// write saves the reports and the PDF next to outputPath, logging failures.
+func (reports *jobReports) write(ctx context.Context, outputPath string) {
+	if reports.timing != nil {
+		if err := writeCSVReport(reports.timing, outputPath, "_timing.csv"); err != nil {
+			slog.Error("failed to write timing report", "error", err)
+		}
+	}
+	if reports.detections != nil {
+		if err := writeCSVReport(reports.detections, outputPath, "_detection.csv"); err != nil {
+			slog.Error("failed to write detection trace", "error", err)
+		}
+	}
+	if reports.fonts != nil {
+		if err := writeCSVReport(reports.fonts, outputPath, "_fonts.csv"); err != nil {
+			slog.Error("failed to write font report", "error", err)
+		}
+	}
+	if reports.spelling != nil {
+		if err := writeCSVReport(reports.spelling, outputPath, "_spelling.csv"); err != nil {
+			slog.Error("failed to write spelling report", "error", err)
+		}
+	}
+	if reports.changes != nil {
+		if err := writeChangeReport(reports.changes, outputPath, reports.format); err != nil {
+			slog.Error("failed to write change report", "error", err)
+		}
+	}
+	if reports.exporter != nil {
+		if reports.pdfPath, reports.pdfErr = reports.exporter.Export(ctx, outputPath); reports.pdfErr != nil {
+			slog.Error("failed to export pdf", "error", reports.pdfErr)
+		}
+	}
+}
+
+// completionMessage describes a finished conversion of input for the user.
+func completionMessage(p *engine.Processor, input string, legacyFormat engine.LegacyFormat,
+	reports *jobReports) string {
+	message := "Conversion completed successfully!"
+	skipped, truncated := p.SkippedCells(), p.TruncatedCells()
+	if len(skipped) > 0 || len(truncated) > 0 {
+		message = fmt.Sprintf("Conversion completed; %d oversized cell(s) were left unconverted "+
+			"and %d cell(s) were truncated to Excel's limit.", len(skipped), len(truncated))
+	}
+	if n := len(p.CellErrors()); n > 0 {
+		message += fmt.Sprintf(" %d cell(s) could not be converted.", n)
+	}
+	if reports.spelling != nil && len(reports.spelling.Issues()) > 0 {
+		message += fmt.Sprintf(" %d converted cell(s) contain improbable words; "+
+			"check the spelling report for a wrong encoding.", len(reports.spelling.Issues()))
+	}
+	switch enc := p.TextEncoding(); enc {
+	case "":
+	case converter.EncodingUnknown:
+		message += " No legacy text was found; the file was saved as UTF-8."
+	default:
+		message += fmt.Sprintf(" The text was converted from %s.", enc)
+	}
+	if engine.IsDocument(input) || engine.IsPresentation(input) {
+		message += fmt.Sprintf(" %d run(s) of text were converted.", p.ConvertedRuns())
+	}
+	if engine.IsOpenDocument(input) {
+		message += fmt.Sprintf(" %d cell(s) were converted.", p.ConvertedRuns())
+	}
+	if backup := p.BackupPath(); backup != "" {
+		message += fmt.Sprintf(" The original was kept as %s.", backup)
+	}
+	if legacyFormat != "" {
+		message += fmt.Sprintf(" The %s file was imported with LibreOffice first.", legacyFormat)
+	}
+	switch {
+	case reports.exporter == nil:
+	case reports.pdfErr != nil:
+		message += " The PDF export failed: " + reports.pdfErr.Error()
+	default:
+		message += fmt.Sprintf(" The PDF was saved as %s.", reports.pdfPath)
+	}
+	return message
+}
+
+// streamProgress emits "progress"/"job:progress" and notifies the job's notifier whenever
+// the whole percentage of file changes, plus localized, human-readable "progressText" for
+// screen readers. Every update is kept in the job's status for GetJobStatus. In a batch
+// job the percentages and counts are those of the whole batch (see engine.BatchProgress).
+// Why: Announcing every cell would flood assistive tech, so text is throttled
+// and always emitted when the sheet changes.
+func (a *App) streamProgress(j *job, file string, statusChan <-chan engine.Status) {
+	jobID := j.id
+	lang := i18n.Parse(a.loadSettings().Language)
+	var lastSheet string
+	var lastEmit time.Time
+	lastPercent := -1
+	for st := range statusChan {
+		if j.batch != nil {
+			st = j.batch.Update(file, st)
+		}
+		j.updateStatus(func(s *JobStatus) {
+			s.ProgressUpdate = ProgressUpdate{Percent: st.Percent, ETASeconds: st.ETA.Seconds()}
+			s.SheetName, s.Processed, s.Total = st.SheetName, st.Processed, st.Total
+		})
+		if pct := int(st.Percent); pct != lastPercent {
+			lastPercent = pct
+			update := ProgressUpdate{Percent: st.Percent, ETASeconds: st.ETA.Seconds()}
+			runtime.EventsEmit(a.ctx, "progress", update)
+			runtime.EventsEmit(a.ctx, "job:progress", JobProgress{
+				JobID:          jobID,
+				ProgressUpdate: update,
+				Processed:      st.Processed,
+				Total:          st.Total,
+			})
+			j.notifier.OnProgress(notify.Progress{
+				JobID: j.id, File: file, Percent: st.Percent, Processed: st.Processed, Total: st.Total,
+			})
+		}
+
+		if st.SheetName == lastSheet && time.Since(lastEmit) < progressTextInterval {
+			continue
+		}
+		lastSheet, lastEmit = st.SheetName, time.Now()
+		runtime.EventsEmit(a.ctx, "progressText", JobProgressText{
+			JobID: jobID,
+			Text:  i18n.ProgressText(lang, st.SheetName, st.Processed, st.Total),
+		})
+	}
+}
+
+// beforeClose is called when the window is about to close.
+// Why: Closing mid-run used to abandon goroutines; we confirm, cancel and wait instead.
+// Returning true prevents the window from closing.
+func (a *App) beforeClose(ctx context.Context) bool {
+	active := a.jobs.Active()
+	if len(active) == 0 {
+		a.saveWindowState(ctx)
+		return false
+	}
+
+	choice, err := runtime.MessageDialog(ctx, runtime.MessageDialogOptions{
+		Type:          runtime.QuestionDialog,
+		Title:         "Conversion in progress",
+		Message:       fmt.Sprintf("%d conversion(s) still running. Cancel and quit?", len(active)),
+		Buttons:       []string{"Yes", "No"},
+		DefaultButton: "No",
+	})
+	if err == nil && choice != "Yes" {
+		return true
+	}
+
+	// Cancelled runs never save, so no partial output is left behind
+	for _, j := range active {
+		a.jobs.Cancel(j.id)
+	}
+	deadline := time.After(shutdownTimeout)
+	for _, j := range active {
+		select {
+		case <-j.done:
+		case <-deadline:
+			slog.Warn("timed out waiting for job to stop", "job", j.id)
+		}
+	}
+	slog.Info("active conversions cancelled on window close")
+	a.saveWindowState(ctx)
+	return false
+}
+
+// newProcessor creates a processor for cfg.InputPath configured from cfg and the saved settings.
+func (a *App) newProcessor(cfg Config, prefs settings.Settings) (*engine.Processor, error) {
+	p := engine.NewProcessor(cfg.InputPath, cfg.SheetName)
+	p.SetBuildInfo(a.buildInfo)
+
+	policy, err := engine.NewFontPolicy(prefs.FontPolicy)
+	if err != nil {
+		return nil, err
+	}
+	p.SetFontPolicy(policy)
+	strictness, err := converter.ParseVNIStrictness(prefs.VNIStrictness)
+	if err != nil {
+		return nil, err
+	}
+	p.SetVNIStrictness(strictness)
+	normalization, err := converter.ParseNormalization(prefs.OutputNormalization)
+	if err != nil {
+		return nil, err
+	}
+	p.SetNormalization(normalization)
+	detector, err := engine.NewDetector(prefs.Detector)
+	if err != nil {
+		return nil, err
+	}
+	p.SetDetector(detector)
+	if err := p.SetSourceEncoding(converter.EncodingType(cfg.Encoding)); err != nil {
+		return nil, err
+	}
+	if err := p.SetTimestampFormat(prefs.TimestampFormat); err != nil {
+		return nil, err
+	}
+	p.SetIOThrottle(a.ioThrottle())
+	cellFilter, err := engine.ParseCellFilter(cfg.CellRange, cfg.Columns)
+	if err != nil {
+		return nil, err
+	}
+	p.SetCellFilter(cellFilter)
+	p.SetMaxCellLength(prefs.MaxCellLength)
+	p.SetWorkerCount(cfg.Workers)
+	p.SetConvertSheetNames(!prefs.KeepSheetNames)
+	p.SetRemapStyleFonts(prefs.RemapStyleFonts)
+	p.SetPlainCells(prefs.PlainCells)
+	p.SetSharedOutput(prefs.SharedOutput)
+	return p, nil
+}
+
+// Preview converts the selected workbook in memory with the same settings as Process and
+// returns the changed cells (before/after) without saving anything.
+// Why: The frontend shows a diff table so users can check the result before converting.
+func (a *App) Preview(cfg Config) (*engine.PreviewReport, error) {
+	if cfg.InputPath == "" {
+		return nil, fmt.Errorf("please select an input file")
+	}
+	p, err := a.newProcessor(cfg, a.loadSettings())
+	if err != nil {
+		return nil, err
+	}
+	return p.Preview(a.ctx)
+}
+
+// CheckFile scans a workbook for legacy-encoded text without producing any output.
+// Why: Lets archivists triage files before scheduling real conversions.
+func (a *App) CheckFile(path, sheetName string) (*engine.CheckReport, error) {
+	if path == "" {
+		return nil, fmt.Errorf("please select an input file")
+	}
+	return engine.NewProcessor(path, sheetName).Check(a.ctx)
+}
+
+// GetSheets lists the sheets of a workbook with their row counts and whether they contain
+// legacy-encoded text.
+// Why: The frontend shows a sheet picker instead of asking for a sheet name.
+func (a *App) GetSheets(path string) ([]engine.SheetInfo, error) {
+	if path == "" {
+		return nil, fmt.Errorf("please select an input file")
+	}
+	return engine.NewProcessor(path, "").Sheets(a.ctx)
+}
+
+// SelectFolder opens a directory dialog
+func (a *App) SelectFolder() (string, error) {
+	return runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Select Folder",
+	})
+}
+
+// ScanFolder checks every workbook under root and writes a triage spreadsheet into root.
+// Returns the path of the triage report.
+func (a *App) ScanFolder(root string) (string, error) {
+	if root == "" {
+		return "", fmt.Errorf("please select a folder")
+	}
+	filter, err := fileFilter(a.loadSettings())
+	if err != nil {
+		return "", err
+	}
+	entries, err := engine.ScanFolder(a.ctx, root, filter, a.ioThrottle())
+	if err != nil {
+		return "", err
 	}
+	reportPath := filepath.Join(root, "triage_report_"+time.Now().Format("2006_01_02_15_04_05")+".xlsx")
+	if err := engine.WriteTriageReport(reportPath, entries); err != nil {
+		return "", err
+	}
+	return reportPath, nil
+}
+
+// fileFilter builds the folder scan filter from the settings.
+func fileFilter(prefs settings.Settings) (engine.FileFilter, error) {
+	patterns := prefs.IgnorePatterns
+	if patterns == nil {
+		patterns = engine.DefaultIgnorePatterns
+	}
+	return engine.NewFileFilter(patterns, prefs.MinFileSize)
+}
+
+// ConvertText converts pasted text to Unicode, from encoding (e.g. "VIQR") or, when it is
+// empty or "AUTO", from the encoding the saved detector finds in the whole text. Lines
+// already in Unicode are kept.
+func (a *App) ConvertText(text, encoding string) (string, error) {
+	converted, _, err := a.convertSnippet(text, converter.EncodingType(encoding))
+	return converted, err
+}
+
+// ConvertClipboard converts the text on the clipboard like ConvertText with a detected
+// encoding, puts the Unicode result back on the clipboard and returns it.
+func (a *App) ConvertClipboard() (string, error) {
+	_, converted, _, err := a.replaceClipboard()
+	return converted, err
+}
+
+// ConvertPastedRange converts cells copied from Excel, as the tab-separated text or the
+// HTML table of the clipboard, from encoding or, when it is empty or "AUTO", from the
+// detected encoding. The result keeps the rows and columns, so it pastes back over the
+// same range.
+func (a *App) ConvertPastedRange(content, encoding string) (engine.PastedRange, error) {
+	prefs := a.loadSettings()
+	detector, err := engine.NewDetector(prefs.Detector)
+	if err != nil {
+		return engine.PastedRange{}, err
+	}
+	policy, err := engine.NewFontPolicy(prefs.FontPolicy)
+	if err != nil {
+		return engine.PastedRange{}, err
+	}
+	strictness, err := converter.ParseVNIStrictness(prefs.VNIStrictness)
+	if err != nil {
+		return engine.PastedRange{}, err
+	}
+	normalization, err := converter.ParseNormalization(prefs.OutputNormalization)
+	if err != nil {
+		return engine.PastedRange{}, err
+	}
+	return engine.ConvertPastedRange(content, engine.WithSourceEncoding(converter.EncodingType(encoding)),
+		engine.WithDetector(detector), engine.WithFontPolicy(policy), engine.WithVNIStrictness(strictness),
+		engine.WithNormalization(normalization))
+}
+
+// replaceClipboard converts the text on the clipboard and, when anything changed, replaces
+// it with the conversion. It returns the original, the conversion and the source encoding.
+func (a *App) replaceClipboard() (string, string, converter.EncodingType, error) {
+	text, err := runtime.ClipboardGetText(a.ctx)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to read the clipboard: %w", err)
+	}
+	converted, enc, err := a.convertSnippet(text, converter.EncodingAuto)
+	if err != nil {
+		return "", "", "", err
+	}
+	if converted != text {
+		if err := runtime.ClipboardSetText(a.ctx, converted); err != nil {
+			return "", "", "", fmt.Errorf("failed to write the clipboard: %w", err)
+		}
+	}
+	return text, converted, enc, nil
+}
+
+// convertSnippet converts text with the saved detector (see engine.ConvertString).
+func (a *App) convertSnippet(text string, encoding converter.EncodingType) (string, converter.EncodingType, error) {
+	prefs := a.loadSettings()
+	detector, err := engine.NewDetector(prefs.Detector)
+	if err != nil {
+		return "", "", err
+	}
+	strictness, err := converter.ParseVNIStrictness(prefs.VNIStrictness)
+	if err != nil {
+		return "", "", err
+	}
+	normalization, err := converter.ParseNormalization(prefs.OutputNormalization)
+	if err != nil {
+		return "", "", err
+	}
+	return engine.ConvertString(text, engine.WithSourceEncoding(encoding), engine.WithDetector(detector),
+		engine.WithVNIStrictness(strictness), engine.WithNormalization(normalization))
+}
+
+// TraceText converts text and returns, character by character, which input produced which
+// output, with the reading of every ambiguous VNI character. An empty or "AUTO" encoding
+// is detected from the text.
+// Why: The QA view highlights exactly which characters changed in a cell's before/after text.
+func (a *App) TraceText(text, encoding string) ([]converter.Mapping, error) {
+	enc := converter.EncodingType(encoding)
+	if enc == "" || enc == converter.EncodingAuto {
+		enc = engine.DetectEncoding("", text)
+	}
+	c := converter.NewConverterOrNoop(enc)
+	if enc == converter.EncodingVNI {
+		strictness, err := converter.ParseVNIStrictness(a.loadSettings().VNIStrictness)
+		if err != nil {
+			return nil, err
+		}
+		c = converter.NewVNIConverterWithStrictness(strictness)
+	}
+	return converter.Trace(c, text), nil
+}
+
+// TranscodeText converts text between two legacy encodings (e.g. TCVN3 -> VNI). The
+// result counts and lists the characters the target encoding cannot write.
+// Why: Some downstream systems still require a specific legacy encoding.
+func (a *App) TranscodeText(text, from, to string) (converter.TranscodeResult, error) {
+	result, err := converter.Transcode(converter.EncodingType(from), converter.EncodingType(to), text)
+	if err == nil && result.Unencodable > 0 {
+		slog.Warn("characters left as Unicode by the target encoding",
+			"target", to, "count", result.Unencodable, "characters", result.Characters)
+	}
+	return result, err
+}
+
+// csvReport is a diagnostics recorder that can export itself as CSV.
+type csvReport interface {
+	WriteCSV(w io.Writer) error
+}
+
+// writeCSVReport saves the recorder's CSV next to the converted file, replacing the
+// output's extension with suffix.
+func writeCSVReport(rec csvReport, outputPath, suffix string) error {
+	reportPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + suffix
+	f, err := os.Create(reportPath) //nolint:gosec // path derived from our own output path
+	if err != nil {
+		return fmt.Errorf("failed to create report %s: %w", reportPath, err)
+	}
+	writeErr := rec.WriteCSV(f)
+	if closeErr := f.Close(); closeErr != nil && writeErr == nil {
+		writeErr = closeErr
+	}
+	return writeErr
+}
+
+// writeChangeReport saves the change report next to the converted file as
+// <output>_changes.<format>.
+func writeChangeReport(rec *engine.ChangeRecorder, outputPath string, format engine.ReportFormat) error {
+	reportPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "_changes." + string(format)
+	return rec.WriteFile(reportPath, format)
 }
 
 // ShowInFolder opens the file explorer and selects the file.
@@ -96,3 +1109,17 @@ func (a *App) ShowInFolder(path string) {
 	cmd := exec.CommandContext(a.ctx, "explorer", "/select,", path)
 	_ = cmd.Start() // Fire and forget, error is non-critical
 }
+
+// OpenLogFolder opens the folder of the log files in the file explorer.
+// Why: Support asks for the log; users should not have to find the app data folder.
+func (a *App) OpenLogFolder() error {
+	dir, err := logFolder()
+	if err != nil {
+		return fmt.Errorf("log folder unavailable: %w", err)
+	}
+	cmd := exec.CommandContext(a.ctx, "explorer", dir)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", dir, err)
+	}
+	return nil
+}
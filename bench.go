@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"convert-vni-to-unicode/internal/converter"
+	"convert-vni-to-unicode/internal/engine"
+)
+
+// runBench implements the "bench" subcommand and returns the process exit code.
+// Why: Gives users a repeatable way to compare machines and report field performance.
+func runBench(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	iterations := fs.Int("n", 10, "number of passes over the samples")
+	encodings := fs.String("encodings", "", "comma-separated converters to run (default: all)")
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: VniConverter bench [-n N] [-encodings VNI,TCVN3] <file.txt|file.xlsx>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	encs := engine.BenchEncodings
+	if *encodings != "" {
+		encs = nil
+		for _, name := range strings.Split(*encodings, ",") {
+			encs = append(encs, converter.EncodingType(strings.ToUpper(strings.TrimSpace(name))))
+		}
+	}
+
+	samples, err := engine.LoadBenchSamples(fs.Arg(0))
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	results, err := engine.RunBenchmark(context.Background(), samples, encs, *iterations)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+
+	_, _ = fmt.Fprintf(stdout, "%s\n%d samples x %d passes\n\n",
+		engine.NewBuildInfo(CurrentVersion), len(samples), *iterations)
+	if err := engine.WriteBenchResults(stdout, results); err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
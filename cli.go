@@ -0,0 +1,738 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"convert-vni-to-unicode/internal/converter"
+	"convert-vni-to-unicode/internal/engine"
+	"convert-vni-to-unicode/internal/eventlog"
+	"convert-vni-to-unicode/internal/notify"
+	"convert-vni-to-unicode/internal/pdf"
+	"convert-vni-to-unicode/internal/settings"
+	"convert-vni-to-unicode/internal/spell"
+	"convert-vni-to-unicode/internal/storage"
+)
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
+
+// isCLIInvocation reports whether the arguments ask for headless conversion instead of the GUI.
+// Why: macOS adds "-psn_*" when launching bundles from Finder; that must still open the GUI.
+func isCLIInvocation(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if args[0] == "convert" {
+		return true
+	}
+	return strings.HasPrefix(args[0], "-") && !strings.HasPrefix(args[0], "-psn_")
+}
+
+// convertFlags are the flags of the convert command, as given.
+type convertFlags struct {
+	inputs          stringList
+	sheet           string
+	cellRange       string
+	columns         string
+	outDir          string
+	inPlace         string
+	encoding        string
+	fontPolicy      string
+	detector        string
+	vniStrictness   string
+	normalization   string
+	timestampFormat string
+	dictionary      string
+	officePath      string
+	changeReport    string
+	notifyWebhook   string
+	order           string
+	sharedOutput    bool
+	keepSheetNames  bool
+	plainCells      bool
+	remapStyleFonts bool
+	detectionTrace  bool
+	fontReport      bool
+	spellCheck      bool
+	exportPDF       bool
+	importLegacy    bool
+	largeFile       bool
+	noVerify        bool
+	resume          bool
+	eventLog        bool
+	notifyToast     bool
+	maxCellLength   int
+	workers         int
+	parallel        int
+	retention       retentionFlags
+}
+
+// newConvertFlags registers the convert flags on fs, with the defaults of settings.Default.
+func newConvertFlags(fs *flag.FlagSet) *convertFlags {
+	defaults := settings.Default()
+	f := &convertFlags{}
+	fs.Var(&f.inputs, "input", "workbook, .ods, .txt, .docx or .pptx file to convert "+
+		"(repeatable; extra files may also be given as arguments)")
+	fs.StringVar(&f.sheet, "sheet", "", "only convert this sheet (default: all sheets)")
+	fs.StringVar(&f.cellRange, "range", "", "only convert cells in this range, e.g. A1:D500 (default: all cells)")
+	fs.StringVar(&f.columns, "columns", "", "only convert these columns, e.g. C or C,E:F (default: all columns)")
+	fs.StringVar(&f.outDir, "out", "", "output directory (default: next to each input)")
+	fs.StringVar(&f.inPlace, "in-place", "",
+		"overwrite each input, keeping the original as <name>.xlsx.bak (bak) or in backup/ (folder)")
+	fs.StringVar(&f.encoding, "encoding", string(converter.EncodingAuto),
+		"source encoding: AUTO, VNI, TCVN3, VNI-DOS, VNU, VISCII, VIQR")
+	fs.StringVar(&f.fontPolicy, "font-policy", defaults.FontPolicy, "output font policy: keep, map, default, per-encoding")
+	fs.StringVar(&f.detector, "detector", defaults.Detector, "auto-detect implementation: rules (default) or ngram")
+	fs.StringVar(&f.vniStrictness, "vni-strictness", defaults.VNIStrictness, "how VNI reads the ambiguous \u00d6/\u00f6: "+
+		"auto, prefer-tcvn3, prefer-vni, require-dictionary-confirmation")
+	fs.StringVar(&f.normalization, "normalization", defaults.OutputNormalization,
+		"Unicode form of converted text: nfc (precomposed, default) or nfd (combining marks)")
+	fs.StringVar(&f.timestampFormat, "timestamp-format", engine.DefaultTimestampFormat,
+		"output name suffix using yyyy, yy, MM, dd, HH, mm, ss and _ - .")
+	fs.BoolVar(&f.sharedOutput, "shared-output", defaults.SharedOutput,
+		"give outputs the output folder's permissions (ACL inheritance on Windows, group-writable elsewhere)")
+	fs.BoolVar(&f.keepSheetNames, "keep-sheet-names", defaults.KeepSheetNames, "do not convert legacy-encoded sheet names")
+	fs.BoolVar(&f.plainCells, "plain-cells", defaults.PlainCells,
+		"write plain cells back as plain strings with the font in the cell style, not as rich text")
+	fs.BoolVar(&f.remapStyleFonts, "remap-style-fonts", defaults.RemapStyleFonts,
+		"replace legacy fonts on empty styled cells so the output is safe to keep editing")
+	fs.IntVar(&f.maxCellLength, "max-cell-length", defaults.MaxCellLength, "skip cells longer than this (0 disables)")
+	fs.IntVar(&f.workers, "workers", engine.WorkerCountAuto,
+		"workers converting the cells of a file (0: one per CPU, fewer for small files)")
+	fs.BoolVar(&f.detectionTrace, "detection-trace", false,
+		"write <output>_detection.csv naming the detection rule used for every converted run")
+	fs.BoolVar(&f.fontReport, "font-report", false,
+		"write <output>_fonts.csv counting the text cells of every font before and after conversion")
+	fs.BoolVar(&f.spellCheck, "spell-check", false, "write <output>_spelling.csv listing converted cells "+
+		"with words that are not Vietnamese, a sign of a wrong encoding")
+	fs.StringVar(&f.dictionary, "dictionary", defaults.SpellDictionary,
+		"hunspell .dic file for --spell-check (default: built-in Vietnamese syllable rules)")
+	fs.BoolVar(&f.exportPDF, "pdf", false, "also save each output as <output>.pdf with a headless LibreOffice")
+	fs.StringVar(&f.officePath, "office", defaults.OfficePath, "LibreOffice soffice binary for --pdf and --import-legacy "+
+		"(default: found in PATH or the default install folder)")
+	fs.BoolVar(&f.importLegacy, "import-legacy", defaults.ConvertLegacyFormats,
+		"import Excel 95 and older and Lotus 1-2-3 files to .xlsx with LibreOffice before converting them")
+	fs.StringVar(&f.changeReport, "report", "",
+		"write <output>_changes.<format> listing every modified cell: xlsx, csv or json")
+	fs.BoolVar(&f.largeFile, "large-file", false, "write each sheet with bounded memory for very large local workbooks; "+
+		"drops comments, charts, images and conditional formats")
+	fs.BoolVar(&f.noVerify, "no-verify", false, "skip re-opening each saved workbook to check that it is not corrupt")
+	fs.BoolVar(&f.resume, "resume", false,
+		"save a checkpoint after every sheet and resume from it when re-run after a crash or Ctrl+C")
+	fs.BoolVar(&f.eventLog, "event-log", false,
+		"write start/stop/error summaries to the Windows Event Log (syslog elsewhere)")
+	fs.StringVar(&f.notifyWebhook, "notify-webhook", "",
+		"POST a JSON summary to this URL when all files are done (Slack and Teams incoming webhooks)")
+	fs.BoolVar(&f.notifyToast, "notify-toast", false, "show a Windows notification when all files are done")
+	fs.IntVar(&f.parallel, "parallel", 1, "files converted at once; each file still uses its own --workers")
+	fs.StringVar(&f.order, "order", string(engine.OrderManual), "processing order: manual, smallest-first, newest-first")
+	f.retention = addRetentionFlags(fs)
+	return f
+}
+
+// convertConfig is a validated convert command line.
+type convertConfig struct {
+	*convertFlags
+	policy          engine.FontPolicy
+	detector        engine.Detector
+	strictness      converter.VNIStrictness
+	normalization   converter.Normalization
+	sourceEncoding  converter.EncodingType
+	timestampLayout string
+	cellFilter      *engine.CellFilter
+	reportFormat    engine.ReportFormat
+	backupMode      engine.BackupMode
+	checker         *spell.Checker
+	exporter        *pdf.Exporter
+	queueOrder      engine.QueueOrder
+	retention       engine.RetentionPolicy
+	// remote is set when an input or the output folder is a storage URL
+	remote bool
+}
+
+// newConvertConfig parses the values of f and rejects flags that cannot be combined.
+func newConvertConfig(f *convertFlags) (*convertConfig, error) {
+	c := &convertConfig{convertFlags: f}
+	if err := c.parseConversion(); err != nil {
+		return nil, err
+	}
+	if err := c.parseOutput(); err != nil {
+		return nil, err
+	}
+	return c, c.validate()
+}
+
+// parseConversion parses the flags that decide how text is converted.
+func (c *convertConfig) parseConversion() error {
+	var err error
+	if c.policy, err = engine.NewFontPolicy(c.convertFlags.fontPolicy); err != nil {
+		return err
+	}
+	if c.detector, err = engine.NewDetector(c.convertFlags.detector); err != nil {
+		return err
+	}
+	if c.strictness, err = converter.ParseVNIStrictness(c.vniStrictness); err != nil {
+		return err
+	}
+	if c.normalization, err = converter.ParseNormalization(c.convertFlags.normalization); err != nil {
+		return err
+	}
+	c.sourceEncoding = converter.EncodingType(strings.ToUpper(c.encoding))
+	if c.sourceEncoding != converter.EncodingAuto {
+		if _, err = converter.NewConverter(c.sourceEncoding); err != nil {
+			return err
+		}
+	}
+	if c.timestampLayout, err = engine.ParseTimestampFormat(c.timestampFormat); err != nil {
+		return err
+	}
+	c.cellFilter, err = engine.ParseCellFilter(c.cellRange, c.columns)
+	return err
+}
+
+// parseOutput parses the flags that decide where outputs go and what is written with them.
+func (c *convertConfig) parseOutput() error {
+	var err error
+	if c.changeReport != "" {
+		if c.reportFormat, err = engine.ParseReportFormat(c.changeReport); err != nil {
+			return err
+		}
+	}
+	if c.backupMode, err = engine.ParseBackupMode(c.inPlace); err != nil {
+		return err
+	}
+	if c.spellCheck {
+		if c.checker, err = loadSpellChecker(c.dictionary); err != nil {
+			return err
+		}
+	}
+	if c.exportPDF {
+		if c.exporter, err = pdf.NewExporter(c.officePath); err != nil {
+			return err
+		}
+	}
+	if c.queueOrder, err = engine.ParseQueueOrder(c.order); err != nil {
+		return err
+	}
+	if c.retention, err = c.convertFlags.retention.policy(); err != nil {
+		return err
+	}
+	c.retention.TimestampLayout = c.timestampLayout
+	c.remote = anyURI(c.outDir, c.inputs)
+	return nil
+}
+
+// validate rejects flags that cannot be combined.
+func (c *convertConfig) validate() error {
+	if c.backupMode != engine.BackupNone && c.outDir != "" {
+		return errors.New("--in-place cannot be combined with --out")
+	}
+	if c.largeFile && (c.backupMode != engine.BackupNone || c.resume || c.fontReport) {
+		return errors.New("--large-file cannot be combined with --in-place, --resume or --font-report")
+	}
+	// Outputs next to their inputs would share a folder with the user's own workbooks
+	if c.retention.Enabled() && c.outDir == "" {
+		return errors.New("--keep-last and --max-age-days need --out, a folder for the outputs only")
+	}
+	if c.remote && (c.perFileRun() || c.resume || c.retention.Enabled()) {
+		return errors.New("--in-place, --report, --detection-trace, --font-report, --spell-check, --pdf, --resume, " +
+			"--keep-last and --max-age-days only work with local files")
+	}
+	if c.retention.Enabled() && c.backupMode != engine.BackupNone {
+		return errors.New("--keep-last and --max-age-days cannot be combined with --in-place")
+	}
+	return nil
+}
+
+// perFileRun reports whether every input needs a conversion of its own: it is converted
+// in place or gets reports, so byte-identical inputs cannot share one output.
+func (c *convertConfig) perFileRun() bool {
+	return c.backupMode != engine.BackupNone || c.reportFormat != "" || c.detectionTrace || c.fontReport ||
+		c.spellCheck || c.exportPDF
+}
+
+// anyURI reports whether outDir or one of inputs is a storage URL.
+func anyURI(outDir string, inputs []string) bool {
+	if storage.IsURI(outDir) {
+		return true
+	}
+	for _, input := range inputs {
+		if storage.IsURI(input) {
+			return true
+		}
+	}
+	return false
+}
+
+// runConvert converts workbooks without starting the GUI and returns the process exit code.
+// Why: Batch conversions run on servers where no desktop session exists.
+func runConvert(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "convert" {
+		args = args[1:]
+	}
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	flags := newConvertFlags(fs)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr,
+			"Usage: VniConverter --input file.xlsx [--input more.xlsx ...] [--sheet Sheet1] [--out dir]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	flags.inputs = append(flags.inputs, fs.Args()...)
+	if len(flags.inputs) == 0 {
+		fs.Usage()
+		return 2
+	}
+	cfg, err := newConvertConfig(flags)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	if cfg.outDir != "" && !storage.IsURI(cfg.outDir) {
+		if err := os.MkdirAll(cfg.outDir, 0750); err != nil {
+			_, _ = fmt.Fprintln(stderr, "Error: failed to create output directory:", err)
+			return 1
+		}
+	}
+
+	// Ctrl+C cancels the current file; cancelled runs never leave partial output
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	run := newConvertRun(cfg, stdout, stderr)
+	defer func() { _ = run.sysLog.Close() }()
+	return run.run(ctx)
+}
+
+// convertRun is one command line batch.
+type convertRun struct {
+	cfg       *convertConfig
+	stdout    io.Writer
+	stderr    io.Writer
+	buildInfo engine.BuildInfo
+	sysLog    eventlog.Logger
+	notifier  notify.Multi
+	started   time.Time
+	// convertRemote streams remote inputs and outputs through their storage backend
+	convertRemote engine.ConvertFunc
+
+	// mu guards the fields below and the output of files converted in parallel, which
+	// print their lines once done so they never interleave
+	mu         sync.Mutex
+	attempted  map[string]bool
+	outputDirs map[string]bool
+	failed     int
+	duplicates int
+	failures   []notify.Failure
+}
+
+// newConvertRun prints the build, opens the event log and notifiers, and announces the batch.
+func newConvertRun(cfg *convertConfig, stdout, stderr io.Writer) *convertRun {
+	r := &convertRun{
+		cfg:        cfg,
+		stdout:     stdout,
+		stderr:     stderr,
+		buildInfo:  engine.NewBuildInfo(CurrentVersion),
+		sysLog:     openEventLog(cfg.eventLog, stderr),
+		started:    time.Now(),
+		attempted:  make(map[string]bool),
+		outputDirs: make(map[string]bool),
+	}
+	_, _ = fmt.Fprintln(stdout, r.buildInfo)
+	logEvent(r.sysLog, false, fmt.Sprintf("Conversion started: %d file(s). %s", len(cfg.inputs), r.buildInfo), stderr)
+	notifier, err := openNotifier(cfg.notifyWebhook, cfg.notifyToast)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Warning: notifications unavailable:", err)
+	}
+	r.notifier = notifier
+	r.notifier.OnStart(notify.Start{JobID: cliJobID, Files: cfg.inputs})
+
+	r.convertRemote = engine.NewStorageConverter(cfg.outDir,
+		engine.WithSheet(cfg.sheet),
+		engine.WithSourceEncoding(cfg.sourceEncoding),
+		engine.WithFontPolicy(cfg.policy),
+		engine.WithVNIStrictness(cfg.strictness),
+		engine.WithNormalization(cfg.normalization),
+		engine.WithDetector(cfg.detector),
+		engine.WithMaxCellLength(cfg.maxCellLength),
+		engine.WithWorkerCount(cfg.workers),
+		engine.WithCellFilter(cfg.cellFilter),
+		engine.WithConvertSheetNames(!cfg.keepSheetNames),
+		engine.WithRemapStyleFonts(cfg.remapStyleFonts),
+		engine.WithPlainCells(cfg.plainCells),
+		engine.WithBuildInfo(r.buildInfo),
+	)
+	return r
+}
+
+// run converts every input, cleans up the output folders and returns the exit code.
+func (r *convertRun) run(ctx context.Context) int {
+	// Byte-identical local files are converted once and the output copied for the others,
+	// unless each needs a real run
+	var copyOutput engine.CopyOutputFunc
+	if !r.cfg.remote && !r.cfg.perFileRun() {
+		copyOutput = r.copyOutput
+	}
+	inputs := engine.OrderPaths(r.cfg.inputs, r.cfg.queueOrder)
+	for _, res := range engine.RunBatchDeduped(ctx, inputs, r.cfg.parallel, r.convert, copyOutput, nil) {
+		// Copies of a failed file fail with it
+		if res.DuplicateOf != "" && !r.attempted[res.InputPath] {
+			r.fail(res.InputPath, errors.New(res.Error))
+			continue
+		}
+		// Files not started before a Ctrl+C are skipped
+		if res.Error != "" && !r.attempted[res.InputPath] {
+			r.failed++
+			_, _ = fmt.Fprintf(r.stderr, "SKIP %s: cancelled\n", res.InputPath)
+			r.failures = append(r.failures, notify.Failure{JobID: cliJobID, File: res.InputPath, Error: "cancelled"})
+		}
+	}
+
+	// Scheduled runs keep their output folders from growing without bound
+	if r.cfg.retention.Enabled() {
+		r.prune()
+	}
+	return r.finish()
+}
+
+// fail reports a failed input. Callers converting in parallel hold mu.
+func (r *convertRun) fail(input string, err error) {
+	r.failed++
+	_, _ = fmt.Fprintf(r.stderr, "FAIL %s: %v\n", input, err)
+	logEvent(r.sysLog, true, fmt.Sprintf("Failed to convert %s: %v", input, err), r.stderr)
+	failure := notify.Failure{JobID: cliJobID, File: input, Error: err.Error()}
+	r.failures = append(r.failures, failure)
+	r.notifier.OnError(failure)
+}
+
+// convert is the engine.ConvertFunc of the batch.
+func (r *convertRun) convert(ctx context.Context, input string) (string, error) {
+	var out, errOut bytes.Buffer
+	outputPath, err := r.convertFile(ctx, input, &out, &errOut)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempted[input] = true
+	_, _ = io.Copy(r.stdout, &out)
+	_, _ = io.Copy(r.stderr, &errOut)
+	if err != nil {
+		r.fail(input, err)
+	} else {
+		r.outputDirs[filepath.Dir(outputPath)] = true
+	}
+	return outputPath, err
+}
+
+// copyOutput is the engine.CopyOutputFunc of the batch.
+func (r *convertRun) copyOutput(input, original, originalOutput string) (string, error) {
+	outputPath, err := engine.CopyDuplicateOutput(input, original, originalOutput)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempted[input] = true
+	if err != nil {
+		r.fail(input, err)
+		return "", err
+	}
+	r.duplicates++
+	r.outputDirs[filepath.Dir(outputPath)] = true
+	_, _ = fmt.Fprintf(r.stdout, "OK   %s -> %s\n", input, outputPath)
+	_, _ = fmt.Fprintf(r.stdout, "     same content as %s; output copied\n", original)
+	return outputPath, nil
+}
+
+// convertFile converts one input, writing its result lines to out and errOut.
+func (r *convertRun) convertFile(ctx context.Context, input string, out, errOut io.Writer) (string, error) {
+	if storage.IsURI(input) || storage.IsURI(r.cfg.outDir) {
+		outputPath, err := r.convertRemote(ctx, input)
+		if err != nil {
+			return "", err
+		}
+		_, _ = fmt.Fprintf(out, "OK   %s -> %s\n", input, outputPath)
+		return outputPath, nil
+	}
+	workbook, legacyFormat, cleanup, err := importLegacyWorkbook(ctx, input, r.cfg.importLegacy, r.cfg.officePath,
+		r.cfg.backupMode)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	p, err := r.newProcessor(input, workbook, legacyFormat)
+	if err != nil {
+		return "", err
+	}
+	reports := r.attachReports(p)
+
+	outputPath, err := p.Run(ctx)
+	if err != nil {
+		return "", err
+	}
+	_, _ = fmt.Fprintf(out, "OK   %s -> %s\n", input, outputPath)
+	printConversion(out, p, input, legacyFormat)
+	reports.write(ctx, outputPath, out, errOut)
+	printCellNotes(out, errOut, p)
+	return outputPath, nil
+}
+
+// newProcessor configures a processor for workbook, the input or its imported copy.
+func (r *convertRun) newProcessor(input, workbook string, legacyFormat engine.LegacyFormat) (*engine.Processor, error) {
+	c := r.cfg
+	p := engine.NewProcessor(workbook, c.sheet)
+	p.SetBuildInfo(r.buildInfo)
+	p.SetFontPolicy(c.policy)
+	p.SetVNIStrictness(c.strictness)
+	p.SetNormalization(c.normalization)
+	p.SetDetector(c.detector)
+	if err := p.SetSourceEncoding(c.sourceEncoding); err != nil {
+		return nil, err
+	}
+	p.SetMaxCellLength(c.maxCellLength)
+	p.SetWorkerCount(c.workers)
+	p.SetCellFilter(c.cellFilter)
+	p.SetConvertSheetNames(!c.keepSheetNames)
+	p.SetRemapStyleFonts(c.remapStyleFonts)
+	p.SetPlainCells(c.plainCells)
+	p.SetSharedOutput(c.sharedOutput)
+	p.SetOutputDir(c.outDir)
+	if legacyFormat != "" && c.outDir == "" {
+		p.SetOutputDir(filepath.Dir(input))
+	}
+	p.SetInPlace(c.backupMode)
+	p.SetCheckpoint(c.resume)
+	p.SetLargeFileMode(c.largeFile)
+	p.SetVerifyOutput(!c.noVerify)
+	_ = p.SetTimestampFormat(c.timestampFormat) // validated by newConvertConfig
+	return p, nil
+}
+
+// cliReports are the per-file reports requested on the command line.
+type cliReports struct {
+	detections *engine.DetectionRecorder
+	changes    *engine.ChangeRecorder
+	fonts      *engine.FontReport
+	spelling   *engine.SpellReport
+	format     engine.ReportFormat
+	exporter   *pdf.Exporter
+}
+
+// attachReports creates the requested reports and attaches them to p.
+func (r *convertRun) attachReports(p *engine.Processor) *cliReports {
+	reports := &cliReports{format: r.cfg.reportFormat, exporter: r.cfg.exporter}
+	if r.cfg.detectionTrace {
+		reports.detections = engine.NewDetectionRecorder()
+		reports.detections.SetBuildInfo(r.buildInfo)
+		p.SetDetectionTrace(reports.detections)
+	}
+	if r.cfg.reportFormat != "" {
+		reports.changes = engine.NewChangeRecorder()
+		p.SetChangeReport(reports.changes)
+	}
+	if r.cfg.fontReport {
+		reports.fonts = engine.NewFontReport()
+		p.SetFontReport(reports.fonts)
+	}
+	if r.cfg.checker != nil {
+		reports.spelling = engine.NewSpellReport(r.cfg.checker)
+		p.SetSpellReport(reports.spelling)
+	}
+	return reports
+}
+
+// write saves the reports and the PDF next to outputPath; failures are printed, not returned.
+func (reports *cliReports) write(ctx context.Context, outputPath string, out, errOut io.Writer) {
+	if reports.detections != nil {
+		if err := writeCSVReport(reports.detections, outputPath, "_detection.csv"); err != nil {
+			_, _ = fmt.Fprintln(errOut, "     failed to write detection trace:", err)
+		}
+	}
+	if reports.changes != nil {
+		if err := writeChangeReport(reports.changes, outputPath, reports.format); err != nil {
+			_, _ = fmt.Fprintln(errOut, "     failed to write change report:", err)
+		}
+	}
+	if reports.fonts != nil {
+		if err := writeCSVReport(reports.fonts, outputPath, "_fonts.csv"); err != nil {
+			_, _ = fmt.Fprintln(errOut, "     failed to write font report:", err)
+		}
+		if n := reports.fonts.LegacyAfter(); n > 0 {
+			_, _ = fmt.Fprintf(out, "     %d text cell(s) still use a legacy font\n", n)
+		}
+	}
+	if reports.spelling != nil {
+		if err := writeCSVReport(reports.spelling, outputPath, "_spelling.csv"); err != nil {
+			_, _ = fmt.Fprintln(errOut, "     failed to write spelling report:", err)
+		}
+		if n := len(reports.spelling.Issues()); n > 0 {
+			_, _ = fmt.Fprintf(out, "     %d of %d converted cell(s) contain improbable words\n", n, reports.spelling.Checked())
+		}
+	}
+	if reports.exporter != nil {
+		if pdfPath, err := reports.exporter.Export(ctx, outputPath); err != nil {
+			_, _ = fmt.Fprintln(errOut, "     failed to export pdf:", err)
+		} else {
+			_, _ = fmt.Fprintf(out, "     PDF saved as %s\n", pdfPath)
+		}
+	}
+}
+
+// printConversion prints what was converted in input and how it was saved.
+func printConversion(out io.Writer, p *engine.Processor, input string, legacyFormat engine.LegacyFormat) {
+	switch enc := p.TextEncoding(); enc {
+	case "":
+	case converter.EncodingUnknown:
+		_, _ = fmt.Fprintln(out, "     no legacy text found; saved as UTF-8")
+	default:
+		_, _ = fmt.Fprintf(out, "     converted text from %s\n", enc)
+	}
+	if engine.IsDocument(input) || engine.IsPresentation(input) {
+		_, _ = fmt.Fprintf(out, "     converted %d run(s)\n", p.ConvertedRuns())
+	}
+	if engine.IsOpenDocument(input) {
+		_, _ = fmt.Fprintf(out, "     converted %d cell(s)\n", p.ConvertedRuns())
+	}
+	if backup := p.BackupPath(); backup != "" {
+		_, _ = fmt.Fprintf(out, "     original kept as %s\n", backup)
+	}
+	if legacyFormat != "" {
+		_, _ = fmt.Fprintf(out, "     imported from %s with LibreOffice\n", legacyFormat)
+	}
+}
+
+// printCellNotes prints the sheets and cells the conversion changed or left alone.
+func printCellNotes(out, errOut io.Writer, p *engine.Processor) {
+	for _, s := range p.BrokenSheets() {
+		_, _ = fmt.Fprintf(errOut, "     kept sheet %q unchanged: it could not be parsed\n", s)
+	}
+	for _, s := range p.RenamedSheets() {
+		_, _ = fmt.Fprintf(out, "     renamed sheet %q -> %q\n", s.From, s.To)
+	}
+	for _, c := range p.SkippedCells() {
+		_, _ = fmt.Fprintf(out, "     skipped %s!%s (%d characters)\n", c.SheetName, c.Axis, c.Length)
+	}
+	for _, c := range p.TruncatedCells() {
+		_, _ = fmt.Fprintf(out, "     truncated %s!%s (%d characters)\n", c.SheetName, c.Axis, c.Length)
+	}
+	for _, e := range p.CellErrors() {
+		_, _ = fmt.Fprintln(errOut, "     failed", e)
+	}
+}
+
+// prune applies the retention policy to the folders outputs were written to, skipping
+// any folder that also holds inputs.
+func (r *convertRun) prune() {
+	inputDirs := make(map[string]bool, len(r.cfg.inputs))
+	for _, input := range r.cfg.inputs {
+		if abs, err := filepath.Abs(filepath.Dir(input)); err == nil {
+			inputDirs[abs] = true
+		}
+	}
+	dirs := make([]string, 0, len(r.outputDirs))
+	for dir := range r.outputDirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		if abs, err := filepath.Abs(dir); err != nil || inputDirs[abs] {
+			_, _ = fmt.Fprintf(r.stderr, "Warning: not cleaning up %s, it also holds inputs\n", dir)
+			continue
+		}
+		if err := pruneOutputs(dir, r.cfg.retention, r.stdout); err != nil {
+			_, _ = fmt.Fprintln(r.stderr, "Warning:", err)
+		}
+	}
+}
+
+// finish prints and logs the summary, notifies and returns the exit code.
+func (r *convertRun) finish() int {
+	converted := len(r.cfg.inputs) - r.failed
+	summary := fmt.Sprintf("%d converted, %d failed", converted, r.failed)
+	if r.duplicates > 0 {
+		summary = fmt.Sprintf("%d converted (%d copied from identical files), %d failed",
+			converted, r.duplicates, r.failed)
+	}
+	_, _ = fmt.Fprintln(r.stdout, summary)
+	elapsed := time.Since(r.started)
+	logEvent(r.sysLog, r.failed > 0,
+		fmt.Sprintf("Conversion finished in %s: %s.", elapsed.Round(time.Second), summary), r.stderr)
+	r.notifier.OnComplete(notify.Summary{
+		JobID:    cliJobID,
+		Files:    len(r.cfg.inputs),
+		Failures: r.failures,
+		Duration: elapsed,
+	})
+	if r.failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// loadSpellChecker loads the hunspell dictionary at path, or returns the built-in
+// syllable rules when path is empty.
+func loadSpellChecker(path string) (*spell.Checker, error) {
+	if path == "" {
+		return spell.New(), nil
+	}
+	return spell.LoadDictionary(path)
+}
+
+// openEventLog returns the OS log requested by --event-log, or a no-op logger.
+// Why: Failing to reach the OS log must never stop the conversion itself.
+func openEventLog(enabled bool, stderr io.Writer) eventlog.Logger {
+	if !enabled {
+		return eventlog.Nop{}
+	}
+	l, err := eventlog.Open(eventlog.Source)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Warning: event log unavailable:", err)
+		return eventlog.Nop{}
+	}
+	return l
+}
+
+// cliJobID is the job ID of command line runs in notifications.
+const cliJobID = "cli"
+
+// openNotifier returns the notifications requested by the webhook URL and toast flag.
+// An unavailable toast is returned as the error, next to the notifiers that work.
+// Why: Like the OS log, an unavailable notification channel must never stop the conversion.
+func openNotifier(webhookURL string, toast bool) (notify.Multi, error) {
+	var n notify.Multi
+	if webhookURL != "" {
+		n = append(n, notify.NewWebhook(webhookURL))
+	}
+	if !toast {
+		return n, nil
+	}
+	t, err := notify.NewToast()
+	if err != nil {
+		return n, err
+	}
+	return append(n, t), nil
+}
+
+// logEvent writes one entry, reporting (but otherwise ignoring) write failures.
+func logEvent(l eventlog.Logger, isError bool, msg string, stderr io.Writer) {
+	write := l.Info
+	if isError {
+		write = l.Error
+	}
+	if err := write(msg); err != nil {
+		_, _ = fmt.Fprintln(stderr, "Warning:", err)
+	}
+}
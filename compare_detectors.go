@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"convert-vni-to-unicode/internal/engine"
+)
+
+// runCompareDetectors implements the "compare-detectors" subcommand and returns the process exit code.
+// Why: The rule-based and n-gram detectors are A/B compared on a labelled QA corpus before
+// changing the default.
+func runCompareDetectors(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("compare-detectors", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: VniConverter compare-detectors corpus.jsonl [more.jsonl ...]")
+		_, _ = fmt.Fprintln(stderr, "Each line is a sample from the dataset subcommand; its encoding is the expected answer.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	var samples []engine.DatasetSample
+	for _, path := range fs.Args() {
+		f, err := os.Open(path) //nolint:gosec // user-supplied corpus
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, "Error:", err)
+			return 1
+		}
+		more, err := engine.ReadDataset(f)
+		_ = f.Close()
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "Error: %s: %v\n", path, err)
+			return 1
+		}
+		samples = append(samples, more...)
+	}
+
+	var results []engine.DetectorResult
+	for _, name := range []string{engine.DetectorRules, engine.DetectorNGram} {
+		d, err := engine.NewDetector(name)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, "Error:", err)
+			return 1
+		}
+		results = append(results, engine.EvaluateDetector(name, d, samples))
+	}
+	if err := engine.WriteDetectorResults(stdout, results); err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"convert-vni-to-unicode/internal/converter"
+	"convert-vni-to-unicode/internal/engine"
+)
+
+// runDataset implements the "dataset" subcommand and returns the process exit code.
+// Why: Tuples of legacy text, converted text, encoding and font from real workbooks are
+// the training data for a better statistical detector.
+func runDataset(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("dataset", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("out", "", "JSON Lines file to write (required)")
+	sheet := fs.String("sheet", "", "only sample this sheet (default: all sheets)")
+	encoding := fs.String("encoding", string(converter.EncodingAuto),
+		"source encoding: AUTO, VNI, TCVN3, VNI-DOS, VNU, VIQR")
+	perFile := fs.Int("per-file", 10000, "maximum samples taken from one workbook (0: unlimited)")
+	limit := fs.Int("limit", 0, "maximum samples in the dataset (0: unlimited)")
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr,
+			"Usage: VniConverter dataset -out samples.jsonl [-per-file 10000] [-limit N] file.xlsx [more.xlsx ...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *out == "" || fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	sourceEncoding, err := parseDatasetFlags(*encoding, *perFile, *limit)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error: failed to create dataset:", err)
+		return 1
+	}
+	defer func() { _ = f.Close() }()
+	w := engine.NewDatasetWriter(f)
+	w.SetMaxSamples(*limit)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	failed := 0
+	for _, input := range fs.Args() {
+		if w.Full() || ctx.Err() != nil {
+			break
+		}
+		p := engine.NewProcessor(input, *sheet)
+		_ = p.SetSourceEncoding(sourceEncoding) // validated above
+		taken, writeErr, err := sampleWorkbook(ctx, p, w, *perFile)
+		if writeErr != nil {
+			_, _ = fmt.Fprintln(stderr, "Error:", writeErr)
+			return 1
+		}
+		if err != nil {
+			failed++
+			_, _ = fmt.Fprintf(stderr, "FAIL %s: %v\n", input, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(stdout, "OK   %s: %d sample(s)\n", input, taken)
+	}
+	if err := f.Close(); err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error: failed to write dataset:", err)
+		return 1
+	}
+
+	_, _ = fmt.Fprintf(stdout, "%d sample(s) written to %s\n", w.Written(), *out)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// parseDatasetFlags checks the sample limits and returns the source encoding.
+func parseDatasetFlags(encoding string, perFile, limit int) (converter.EncodingType, error) {
+	if perFile < 0 || limit < 0 {
+		return "", errors.New("-per-file and -limit cannot be negative")
+	}
+	sourceEncoding := converter.EncodingType(strings.ToUpper(encoding))
+	if sourceEncoding != converter.EncodingAuto {
+		if _, err := converter.NewConverter(sourceEncoding); err != nil {
+			return "", err
+		}
+	}
+	return sourceEncoding, nil
+}
+
+// sampleWorkbook adds up to perFile samples of p's workbook to w (0: unlimited) and
+// returns how many were written. writeErr is a failure to write the dataset, err a
+// failure to read the workbook.
+func sampleWorkbook(ctx context.Context, p *engine.Processor, w *engine.DatasetWriter,
+	perFile int) (taken int, writeErr, err error) {
+	err = p.Samples(ctx, func(s engine.DatasetSample) bool {
+		before := w.Written()
+		more, addErr := w.Add(s)
+		if addErr != nil {
+			writeErr = addErr
+			return false
+		}
+		taken += w.Written() - before
+		return more && (perFile == 0 || taken < perFile)
+	})
+	return taken, writeErr, err
+}
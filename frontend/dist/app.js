@@ -7,18 +7,107 @@ const dropZone = document.getElementById('dropZone');
 const fileInfo = document.getElementById('fileInfo');
 const fileNameDisplay = document.getElementById('fileName');
 const convertBtn = document.getElementById('convertBtn');
+const previewBtn = document.getElementById('previewBtn');
+const previewCard = document.getElementById('previewCard');
 const progressContainer = document.getElementById('progressContainer');
 const progressFill = document.getElementById('progressFill');
 const progressText = document.getElementById('progressText');
+const progressEta = document.getElementById('progressEta');
+const cancelBtn = document.getElementById('cancelBtn');
+const undoBtn = document.getElementById('undoBtn');
+const sheetSelect = document.getElementById('sheetName');
 
 let selectedPath = "";
+// currentJobId is the conversion started with the convert button, while it runs
+let currentJobId = null;
+// lastJobId is the last successful conversion of the convert button, which UNDO reverts
+let lastJobId = null;
+// pendingJobs resolves the results of the jobs this page waits for (see "job:done")
+const pendingJobs = new Map();
 
 // Initialize
 document.addEventListener('DOMContentLoaded', () => {
+    // Apply persisted theme
+    loadTheme();
+    loadTimestampFormat();
+    loadFontPolicy();
     // Check for updates
     checkForUpdates();
+    // A reloaded page re-attaches to a conversion still running in the backend
+    reattachJobs();
 });
 
+// Theme Logic
+async function loadTheme() {
+    if (!window.go || !window.go.main) return;
+    try {
+        document.body.dataset.theme = await window.go.main.App.GetTheme();
+    } catch (e) {
+        console.error("Theme load failed:", e);
+    }
+}
+
+window.toggleTheme = async () => {
+    const next = document.body.dataset.theme === 'light' ? 'dark' : 'light';
+    document.body.dataset.theme = next;
+    if (!window.go || !window.go.main) return;
+    try {
+        await window.go.main.App.SetTheme(next);
+    } catch (e) {
+        showToast("Failed to save theme: " + e, "error");
+    }
+};
+
+// Output Timestamp Format
+async function loadTimestampFormat() {
+    if (!window.go || !window.go.main) return;
+    try {
+        const input = document.getElementById('timestampFormat');
+        const presets = await window.go.main.App.GetTimestampPresets();
+        document.getElementById('timestampPresets').innerHTML =
+            presets.map((p) => `<option value="${p}"></option>`).join('');
+        input.value = await window.go.main.App.GetTimestampFormat();
+        input.dataset.saved = input.value;
+    } catch (e) {
+        console.error("Timestamp format load failed:", e);
+    }
+}
+
+window.saveTimestampFormat = async () => {
+    const input = document.getElementById('timestampFormat');
+    try {
+        await window.go.main.App.SetTimestampFormat(input.value.trim());
+        input.dataset.saved = input.value.trim();
+    } catch (e) {
+        // Invalid formats are rejected by the backend; restore the last saved one
+        showToast("" + e, "error");
+        input.value = input.dataset.saved || "";
+    }
+};
+
+// Output Font Policy
+async function loadFontPolicy() {
+    if (!window.go || !window.go.main) return;
+    try {
+        const select = document.getElementById('fontPolicy');
+        select.value = await window.go.main.App.GetFontPolicy();
+        select.dataset.saved = select.value;
+    } catch (e) {
+        console.error("Font policy load failed:", e);
+    }
+}
+
+window.saveFontPolicy = async () => {
+    const select = document.getElementById('fontPolicy');
+    try {
+        await window.go.main.App.SetFontPolicy(select.value);
+        select.dataset.saved = select.value;
+    } catch (e) {
+        showToast("Failed to save font policy: " + e, "error");
+        select.value = select.dataset.saved || "map";
+    }
+};
+
 // Update Logic
 let updateUrl = "";
 
@@ -27,7 +116,10 @@ async function checkForUpdates() {
 
     try {
         const info = await window.go.main.App.CheckForUpdate();
-        if (info.available) {
+        if (info.available && info.error) {
+            // A newer release exists but has no build for this machine
+            showToast(`Version ${info.latestVersion} is available but cannot be installed automatically: ${info.error}`, "error");
+        } else if (info.available) {
             document.getElementById('new-version').textContent = info.latestVersion;
             document.getElementById('update-bar').style.display = 'flex';
             updateUrl = info.downloadUrl;
@@ -53,7 +145,29 @@ window.performUpdate = async () => {
     }
 };
 
+// Download now, install when the app is closed (no restart mid-work)
+window.updateOnExit = async () => {
+    if (!updateUrl) return;
+
+    const btn = document.querySelector('.btn-update-exit');
+    btn.textContent = "Downloading...";
+    btn.disabled = true;
+
+    try {
+        await window.go.main.App.ScheduleUpdateOnExit(updateUrl);
+        btn.textContent = "Installs on Exit";
+    } catch (e) {
+        showToast("Update failed: " + e, "error");
+        btn.textContent = "Update on Exit";
+        btn.disabled = false;
+    }
+};
+
 window.hideUpdate = () => {
+    // Closing the bar also cancels a download in progress (no-op otherwise)
+    if (window.go && window.go.main) {
+        window.go.main.App.CancelUpdate();
+    }
     document.getElementById('update-bar').style.display = 'none';
 };
 
@@ -68,12 +182,59 @@ function updateUIFileSelected(path) {
 
         fileInfo.style.display = 'flex';
         convertBtn.disabled = false;
+        // Text files and documents have no sheets or cells to preview
+        previewBtn.disabled = !hasSheets(path);
 
         // Hide "browse" button text somewhat? No stays same.
     } else {
         selectedPath = "";
         fileInfo.style.display = 'none';
         convertBtn.disabled = true;
+        previewBtn.disabled = true;
+    }
+    loadSheets(hasSheets(selectedPath) ? selectedPath : "");
+    // A preview belongs to the file (and settings) it was made for
+    previewCard.style.display = 'none';
+}
+
+// isTextFile reports whether path is a plain .txt file, converted without sheets
+function isTextFile(path) {
+    return path.toLowerCase().endsWith('.txt');
+}
+
+// isDocumentFile reports whether path is a Word .docx document, a PowerPoint .pptx
+// presentation or an OpenDocument .ods spreadsheet, converted without sheet selection
+function isDocumentFile(path) {
+    const lower = path.toLowerCase();
+    return lower.endsWith('.docx') || lower.endsWith('.pptx') || lower.endsWith('.ods');
+}
+
+// isLegacyWorkbook reports whether path is an Excel 95 or older or a Lotus 1-2-3 file, which
+// the backend imports with LibreOffice (when enabled) or refuses with advice
+function isLegacyWorkbook(path) {
+    return /\.(xls|wk1|wk3|wk4|wks|123)$/i.test(path);
+}
+
+// hasSheets reports whether path is an Excel workbook, whose sheets can be picked and previewed
+function hasSheets(path) {
+    return path !== "" && !isTextFile(path) && !isDocumentFile(path) && !isLegacyWorkbook(path);
+}
+
+// loadSheets fills the sheet picker with the sheets of path ("All sheets" only when empty)
+async function loadSheets(path) {
+    sheetSelect.length = 1;
+    if (!path) return;
+    try {
+        const sheets = await window.go.main.App.GetSheets(path);
+        if (path !== selectedPath) return; // another file was picked meanwhile
+        for (const sheet of sheets || []) {
+            const option = document.createElement('option');
+            option.value = sheet.name;
+            option.textContent = `${sheet.name} (${sheet.rows} rows${sheet.hasLegacy ? ", legacy text" : ""})`;
+            sheetSelect.appendChild(option);
+        }
+    } catch (e) {
+        showToast("Error: " + e, "error");
     }
 }
 
@@ -94,6 +255,241 @@ window.clearFile = (event) => {
     updateUIFileSelected("");
 };
 
+// currentConfig builds the backend Config from the form
+function currentConfig() {
+    const outputMode = document.getElementById('inPlace').value;
+    return {
+        inputPath: selectedPath,
+        sheetName: document.getElementById('sheetName').value,
+        // Only cells inside the range and columns are converted (both optional)
+        cellRange: document.getElementById('cellRange').value,
+        columns: document.getElementById('columns').value,
+        // "AUTO" detects per cell; anything else forces that encoding for every cell
+        encoding: document.getElementById('encoding').value,
+        // Writes <output>_changes.<format> listing every modified cell (audit evidence)
+        changeReport: document.getElementById('changeReport').value,
+        // Writes <output>_spelling.csv listing converted cells with improbable words
+        spellCheck: document.getElementById('spellCheck').value === 'on',
+        // Also saves <output>.pdf with LibreOffice, for printing or archiving
+        exportPDF: document.getElementById('exportPDF').value === 'on',
+        // "bak" or "folder" overwrites the input after backing it up
+        inPlace: outputMode === 'large' ? '' : outputMode,
+        // Writes the output sheet by sheet; it always goes to a new file
+        largeFileMode: outputMode === 'large',
+    };
+}
+
+// Preview: convert in memory and show a before/after table; nothing is saved
+window.startPreview = async () => {
+    if (!selectedPath) return;
+
+    try {
+        previewBtn.disabled = true;
+        previewBtn.textContent = "PREVIEWING...";
+        const report = await window.go.main.App.Preview(currentConfig());
+        renderPreview(report);
+    } catch (e) {
+        showToast("Error: " + e, "error");
+    } finally {
+        previewBtn.disabled = false;
+        previewBtn.textContent = "PREVIEW CHANGES";
+    }
+};
+
+function renderPreview(report) {
+    const rows = document.getElementById('previewRows');
+    rows.replaceChildren();
+    for (const change of report.changes) {
+        const tr = document.createElement('tr');
+        for (const value of [change.sheetName, change.axis, change.before, change.after, change.encoding]) {
+            const td = document.createElement('td');
+            // textContent: cell text must never be interpreted as HTML
+            td.textContent = value;
+            tr.appendChild(td);
+        }
+        tr.children[2].className = 'preview-before';
+        tr.children[3].className = 'preview-after';
+        rows.appendChild(tr);
+    }
+
+    let summary = `${report.cellsChanged} of ${report.cellsScanned} cell(s) will change.`;
+    if (report.changesCapped) {
+        summary += ` Showing the first ${report.changes.length}.`;
+    }
+    for (const sheet of report.renamedSheets || []) {
+        summary += ` Sheet "${sheet.from}" will be renamed to "${sheet.to}".`;
+    }
+    if (report.skippedCells && report.skippedCells.length > 0) {
+        summary += ` ${report.skippedCells.length} oversized cell(s) will be left unconverted.`;
+    }
+    document.getElementById('previewSummary').textContent = summary;
+    previewCard.style.display = 'block';
+}
+
+// History Logic
+const historyCard = document.getElementById('historyCard');
+
+window.toggleHistory = async function () {
+    if (historyCard.style.display === 'block') {
+        historyCard.style.display = 'none';
+        return;
+    }
+    await searchHistory();
+    historyCard.style.display = 'block';
+};
+
+window.searchHistory = async function () {
+    if (!window.go || !window.go.main) return;
+    try {
+        const query = document.getElementById('historySearch').value;
+        renderHistory(await window.go.main.App.SearchHistory(query) || []);
+    } catch (e) {
+        showToast("History unavailable: " + e, "error");
+    }
+};
+
+function renderHistory(entries) {
+    const rows = document.getElementById('historyRows');
+    rows.replaceChildren();
+    for (const entry of entries) {
+        const encodings = Object.entries(entry.byEncoding || {}).map(([enc, n]) => `${enc} ${n}`).join(', ');
+        const tr = document.createElement('tr');
+        for (const value of [
+            new Date(entry.timestamp).toLocaleString(),
+            entry.inputPath.split(/[\\/]/).pop(),
+            (entry.sheets || []).join(', '),
+            entry.cellsConverted,
+            encodings,
+            `${(entry.durationMs / 1000).toFixed(1)} s`,
+        ]) {
+            const td = document.createElement('td');
+            // textContent: paths and sheet names must never be interpreted as HTML
+            td.textContent = value;
+            tr.appendChild(td);
+        }
+        tr.title = `${entry.inputPath}\n→ ${entry.outputPath}`;
+        tr.addEventListener('dblclick', async () => {
+            try {
+                await window.go.main.App.OpenHistoryOutput(entry.id);
+            } catch (e) {
+                showToast("Cannot open the output: " + e, "error");
+            }
+        });
+        rows.appendChild(tr);
+    }
+}
+
+// Watch Folder Logic
+const watchCard = document.getElementById('watchCard');
+let watching = false;
+
+window.toggleWatch = async function () {
+    if (watchCard.style.display === 'block') {
+        watchCard.style.display = 'none';
+        return;
+    }
+    if (window.go && window.go.main) {
+        const status = await window.go.main.App.GetWatchStatus();
+        document.getElementById('watchInput').value = status.inputDir || '';
+        document.getElementById('watchOutput').value = status.outputDir || '';
+        renderWatchStatus(status);
+    }
+    watchCard.style.display = 'block';
+};
+
+// browseWatchFolder fills the input with id from the folder picker
+window.browseWatchFolder = async function (id) {
+    try {
+        const dir = await window.go.main.App.SelectFolder();
+        if (dir) document.getElementById(id).value = dir;
+    } catch (e) {
+        showToast("System Error: " + e, "error");
+    }
+};
+
+window.toggleWatchFolder = async function () {
+    try {
+        if (watching) {
+            await window.go.main.App.StopWatch();
+            return;
+        }
+        await window.go.main.App.StartWatch(
+            document.getElementById('watchInput').value.trim(),
+            document.getElementById('watchOutput').value.trim());
+    } catch (e) {
+        showToast("Watch folder: " + e, "error");
+    }
+};
+
+function renderWatchStatus(status) {
+    watching = status.running;
+    document.getElementById('watchToggleBtn').textContent = watching ? "STOP WATCHING" : "START WATCHING";
+    document.getElementById('watchInput').disabled = watching;
+    document.getElementById('watchOutput').disabled = watching;
+    let text = watching ? `Watching: ${status.converted} converted, ${status.failed} failed` : "Not watching";
+    if (status.lastInput) {
+        const name = status.lastInput.split(/[\\/]/).pop();
+        text += status.lastError ? ` — ${name} failed: ${status.lastError}` : ` — last: ${name}`;
+    }
+    // textContent: file names and errors must never be interpreted as HTML
+    document.getElementById('watchStatusText').textContent = text;
+}
+
+// JOB_POLL_MS is how often a re-attached job is polled for its result
+const JOB_POLL_MS = 1000;
+
+// reattachJobs restores the progress bar of a running job after a webview reload and
+// reports its result when it finishes (progress events keep updating the bar meanwhile)
+async function reattachJobs() {
+    let ids;
+    try {
+        ids = await window.go.main.App.ListJobs();
+    } catch (e) {
+        console.error(e);
+        return;
+    }
+    if (!ids || ids.length === 0) return;
+
+    const jobId = ids[0];
+    convertBtn.disabled = true;
+    convertBtn.textContent = "CONVERTING...";
+    progressContainer.style.display = 'block';
+    try {
+        for (;;) {
+            const status = await window.go.main.App.GetJobStatus(jobId);
+            if (status.stage === "done") {
+                showResult(status.result);
+                break;
+            }
+            progressFill.style.width = `${status.percent.toFixed(0)}%`;
+            if (status.sheetName) {
+                progressText.textContent = `${status.sheetName}: ${status.processed} / ${status.total}`;
+            }
+            await new Promise((resolve) => setTimeout(resolve, JOB_POLL_MS));
+        }
+    } catch (e) {
+        showToast("System Error: " + e, "error");
+    } finally {
+        convertBtn.disabled = !selectedPath;
+        convertBtn.textContent = "START CONVERSION";
+    }
+}
+
+// showResult updates the progress bar and notifies the user of a finished conversion
+function showResult(result) {
+    if (result.success) {
+        progressFill.style.width = '100%';
+        progressText.textContent = "Completed!";
+        progressEta.textContent = "";
+        showToast(result.message, "success");
+        // Optional: Show "Open Folder" button
+    } else {
+        progressFill.style.background = 'var(--danger)';
+        progressText.textContent = "Failed";
+        showToast("Error: " + result.message, "error");
+    }
+}
+
 // Start Conversion
 window.startConversion = async () => {
     if (!selectedPath) return;
@@ -103,68 +499,176 @@ window.startConversion = async () => {
         convertBtn.textContent = "CONVERTING...";
         progressContainer.style.display = 'block';
         progressFill.style.width = '0%';
+        progressEta.textContent = "";
         progressText.textContent = "Initializing...";
 
-        const sheetName = document.getElementById('sheetName').value;
-        const encoding = document.getElementById('encoding').value;
-
-        // Reset progress monitoring
-        // We listen to "progress" event
-
-        // Call Go
-        const config = {
-            inputPath: selectedPath,
-            sheetName: sheetName,
-            // Encoding is handled by Auto-detect usually, but if we passed it to config?
-            // Updated App.go to include Encoding in Config?
-            // Current Config struct in App.go: InputPath, SheetName.
-            // Encoding logic is in Processor.DetectEncoding.
-            // Ideally we should pass encoding preference if user forced it.
-            // For now, let's assume Auto. If user selects VNI, we might want to force it.
-            // TODO: Update backend to accept encoding override if needed.
-        };
-
-        const result = await window.go.main.App.Process(config);
+        undoBtn.style.display = 'none';
 
+        // The job runs in the backend queue; progress arrives as "progress" events
+        currentJobId = await window.go.main.App.StartJob(currentConfig());
+        cancelBtn.style.display = 'block';
+        const result = await waitForJob(currentJobId);
+        showResult(result);
         if (result.success) {
-            progressFill.style.width = '100%';
-            progressText.textContent = "Completed!";
-            showToast(result.message, "success");
-            // Optional: Show "Open Folder" button
-        } else {
-            progressFill.style.background = 'var(--danger)';
-            progressText.textContent = "Failed";
-            showToast("Error: " + result.message, "error");
+            lastJobId = currentJobId;
+            undoBtn.style.display = 'block';
         }
     } catch (e) {
         showToast("System Error: " + e, "error");
     } finally {
+        currentJobId = null;
+        cancelBtn.style.display = 'none';
         convertBtn.disabled = false;
         convertBtn.textContent = "START CONVERSION";
     }
 };
 
+// revertConversion undoes the last conversion: the original comes back from its backup
+// (in place) or the new output is deleted
+window.revertConversion = async () => {
+    if (!lastJobId) return;
+    try {
+        await window.go.main.App.RevertConversion(lastJobId);
+        lastJobId = null;
+        undoBtn.style.display = 'none';
+        progressText.textContent = "Reverted";
+        showToast("The conversion was undone.", "success");
+    } catch (e) {
+        showToast("Undo failed: " + e, "error");
+    }
+};
+
+// waitForJob resolves with the result of a job once it is done
+async function waitForJob(jobId) {
+    const done = new Promise((resolve) => pendingJobs.set(jobId, resolve));
+    // The job may have finished (or started) before this page knew its ID
+    const status = await window.go.main.App.GetJobStatus(jobId);
+    if (status.stage === "done") {
+        pendingJobs.delete(jobId);
+        return status.result;
+    }
+    if (status.stage === "queued") {
+        progressText.textContent = "Waiting for other conversions to finish...";
+    }
+    return done;
+}
+
+// Cancel the conversion started with the convert button (queued or running)
+window.cancelConversion = async () => {
+    if (!currentJobId) return;
+    try {
+        await window.go.main.App.CancelJob(currentJobId);
+        progressText.textContent = "Cancelling...";
+    } catch (e) {
+        showToast("System Error: " + e, "error");
+    }
+};
+
 // Events from Backend
 if (window.runtime) {
-    window.runtime.EventsOn("progress", (count) => {
-        // We don't know total, so we animate based on count or just show activity
-        // Or if we implemented percentage in backend.
-        // Current backend sends "processed count" (float64).
-        // Let's just create a moving bar or display count.
-        progressText.textContent = `Processed ${count} cells...`;
-
-        // Fake visual progress if we don't know total:
-        // Use an indeterminate animation or just fill slowly.
-        // For now, let's just show the text and keep bar at 50% pulsing?
-        // Or simpler: just text.
-        progressFill.style.width = '50%';
+    window.runtime.EventsOn("progress", (update) => {
+        // Percentage comes from a backend pre-scan; text is provided by the "progressText" event
+        progressFill.style.width = `${update.percent.toFixed(0)}%`;
+        progressEta.textContent = update.etaSeconds > 0
+            ? `About ${Math.ceil(update.etaSeconds)}s remaining`
+            : "";
+    });
+
+    // Localized, throttled progress sentence from the backend (announced by screen readers)
+    window.runtime.EventsOn("progressText", (payload) => {
+        progressText.textContent = payload.text;
+    });
+
+    // Cells, rows or sheets that failed during a conversion and were left unconverted
+    window.runtime.EventsOn("errors", (payload) => {
+        const refs = payload.errors.slice(0, 3).map((e) => e.axis ? `${e.sheetName}!${e.axis}` : e.sheetName);
+        const more = payload.errors.length > refs.length ? ", ..." : "";
+        showToast(`${payload.errors.length} cell(s) could not be converted: ${refs.join(", ")}${more}`, "error");
     });
 
     window.runtime.EventsOn("updateProgress", (msg) => {
         showToast(msg, "info");
     });
+
+    // A vniconv:// link started a conversion; its result arrives as "job:done"
+    const linkJobs = new Set();
+    window.runtime.EventsOn("link", (payload) => {
+        if (payload.error) {
+            showToast("Link not converted: " + payload.error, "error");
+            return;
+        }
+        linkJobs.add(payload.jobId);
+        showToast("Converting " + payload.path.split(/[\\/]/).pop() + " from a link", "info");
+    });
+    // A file opened from the file manager ("Open with", or a launch while the app runs)
+    window.runtime.EventsOn("openFile", (payload) => {
+        if (payload.error) {
+            showToast("File not opened: " + payload.error, "error");
+            return;
+        }
+        updateUIFileSelected(payload.path);
+        showToast("Opened " + payload.path.split(/[\\/]/).pop(), "info");
+    });
+    window.runtime.EventsOn("job:started", (payload) => {
+        if (payload.jobId === currentJobId) {
+            progressText.textContent = "Initializing...";
+        }
+    });
+    window.runtime.EventsOn("job:done", (payload) => {
+        const resolve = pendingJobs.get(payload.jobId);
+        if (resolve) {
+            pendingJobs.delete(payload.jobId);
+            resolve(payload.result);
+        }
+        if (linkJobs.delete(payload.jobId)) {
+            showResult(payload.result);
+        }
+        if (historyCard.style.display === 'block') {
+            searchHistory();
+        }
+    });
+
+    // The watch folder started, stopped or converted a file
+    window.runtime.EventsOn("watch:status", renderWatchStatus);
+    window.runtime.EventsOn("watch:file", (file) => {
+        const name = file.input.split(/[\\/]/).pop();
+        if (file.error) {
+            showToast(`Watch folder: ${name} failed: ${file.error}`, "error");
+        } else {
+            showToast(`Watch folder: ${name} converted`, "success");
+        }
+    });
 }
 
+// Cells copied in Excel and pasted on the page are converted and put back on the
+// clipboard, as HTML (with fonts) and as text, ready to paste over the same range
+document.addEventListener('paste', async (event) => {
+    const target = event.target;
+    if (target && (target.tagName === 'INPUT' || target.tagName === 'TEXTAREA')) return;
+    const html = event.clipboardData.getData('text/html');
+    const text = event.clipboardData.getData('text/plain');
+    if (!html.includes('<table') && !text.includes('\t')) return;
+    event.preventDefault();
+    try {
+        const [fromHTML, fromText] = await Promise.all([
+            html ? window.go.main.App.ConvertPastedRange(html, "") : null,
+            text ? window.go.main.App.ConvertPastedRange(text, "") : null,
+        ]);
+        const converted = fromHTML || fromText;
+        if (!converted || converted.converted === 0) {
+            showToast("The copied cells hold no legacy text.", "info");
+            return;
+        }
+        const item = {};
+        if (fromHTML) item['text/html'] = new Blob([fromHTML.content], { type: 'text/html' });
+        if (fromText) item['text/plain'] = new Blob([fromText.content], { type: 'text/plain' });
+        await navigator.clipboard.write([new ClipboardItem(item)]);
+        showToast(`Converted the ${converted.cells} copied cell(s); paste them back into Excel.`, "success");
+    } catch (e) {
+        showToast("Paste not converted: " + e, "error");
+    }
+});
+
 // Toast Notification
 function showToast(message, type = "info") {
     const container = document.getElementById('toast-container');
@@ -198,7 +702,7 @@ dropZone.addEventListener('drop', (e) => {
     const files = e.dataTransfer.files;
     if (files.length > 0) {
         const file = files[0];
-        if (file.name.endsWith('.xlsx')) {
+        if (file.name.endsWith('.xlsx') || isTextFile(file.name) || isDocumentFile(file.name) || isLegacyWorkbook(file.name)) {
             // We need the full path. Browser security might block this in pure web,
             // but Wails WebView usually allows getting path if dropped?
             // Actually, Chrome/WebView DnD often gives File object but NOT full path.
@@ -223,7 +727,7 @@ dropZone.addEventListener('drop', (e) => {
                 showToast("Drag & Drop path detection not supported; please use Browse.", "error");
             }
         } else {
-            showToast("Please select an .xlsx file", "error");
+            showToast("Please select an .xlsx, .ods, .txt, .docx or .pptx file", "error");
         }
     }
 });
@@ -1,15 +1,90 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {converter} from '../models';
+import {engine} from '../models';
+import {history} from '../models';
 import {main} from '../models';
 
+export function CancelJob(arg1:string):Promise<boolean>;
+
+export function CancelUpdate():Promise<boolean>;
+
+export function CheckFile(arg1:string,arg2:string):Promise<engine.CheckReport>;
+
 export function CheckForUpdate():Promise<main.UpdateInfo>;
 
+export function ConvertClipboard():Promise<string>;
+
+export function ConvertPastedRange(arg1:string,arg2:string):Promise<engine.PastedRange>;
+
+export function ConvertText(arg1:string,arg2:string):Promise<string>;
+
 export function GetCurrentVersion():Promise<string>;
 
+export function GetFontPolicy():Promise<string>;
+
+export function GetHistory():Promise<Array<history.Entry>>;
+
+export function GetJobStatus(arg1:string):Promise<main.JobStatus>;
+
+export function GetJobs():Promise<Array<main.JobStatus>>;
+
+export function GetSheets(arg1:string):Promise<Array<engine.SheetInfo>>;
+
+export function GetTheme():Promise<string>;
+
+export function GetTimestampFormat():Promise<string>;
+
+export function GetTimestampPresets():Promise<Array<string>>;
+
+export function GetWatchStatus():Promise<main.WatchStatus>;
+
+export function ListActions():Promise<Array<main.Action>>;
+
+export function ListJobs():Promise<Array<string>>;
+
+export function OpenHistoryOutput(arg1:number):Promise<void>;
+
+export function OpenLogFolder():Promise<void>;
+
 export function PerformUpdate(arg1:string):Promise<boolean>;
 
+export function Preview(arg1:main.Config):Promise<engine.PreviewReport>;
+
 export function Process(arg1:main.Config):Promise<main.ProcessResult>;
 
+export function RevertConversion(arg1:string):Promise<void>;
+
+export function RunAction(arg1:string):Promise<main.ActionResult>;
+
+export function ScanFolder(arg1:string):Promise<string>;
+
+export function ScheduleUpdateOnExit(arg1:string):Promise<void>;
+
+export function SearchHistory(arg1:string):Promise<Array<history.Entry>>;
+
 export function SelectFile():Promise<string>;
 
+export function SelectFiles():Promise<Array<string>>;
+
+export function SelectFolder():Promise<string>;
+
+export function SetDetector(arg1:string):Promise<void>;
+
+export function SetFontPolicy(arg1:string):Promise<void>;
+
+export function SetTheme(arg1:string):Promise<void>;
+
+export function SetTimestampFormat(arg1:string):Promise<void>;
+
 export function ShowInFolder(arg1:string):Promise<void>;
+
+export function StartJob(arg1:main.Config):Promise<string>;
+
+export function StartWatch(arg1:string,arg2:string):Promise<void>;
+
+export function StopWatch():Promise<void>;
+
+export function TraceText(arg1:string,arg2:string):Promise<Array<converter.Mapping>>;
+
+export function TranscodeText(arg1:string,arg2:string,arg3:string):Promise<converter.TranscodeResult>;
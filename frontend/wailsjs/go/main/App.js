@@ -2,26 +2,170 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CancelJob(arg1) {
+  return window['go']['main']['App']['CancelJob'](arg1);
+}
+
+export function CancelUpdate() {
+  return window['go']['main']['App']['CancelUpdate']();
+}
+
+export function CheckFile(arg1,arg2) {
+  return window['go']['main']['App']['CheckFile'](arg1,arg2);
+}
+
 export function CheckForUpdate() {
   return window['go']['main']['App']['CheckForUpdate']();
 }
 
+export function ConvertClipboard() {
+  return window['go']['main']['App']['ConvertClipboard']();
+}
+
+export function ConvertPastedRange(arg1, arg2) {
+  return window['go']['main']['App']['ConvertPastedRange'](arg1, arg2);
+}
+
+export function ConvertText(arg1,arg2) {
+  return window['go']['main']['App']['ConvertText'](arg1,arg2);
+}
+
 export function GetCurrentVersion() {
   return window['go']['main']['App']['GetCurrentVersion']();
 }
 
+export function GetFontPolicy() {
+  return window['go']['main']['App']['GetFontPolicy']();
+}
+
+export function GetHistory() {
+  return window['go']['main']['App']['GetHistory']();
+}
+
+export function GetJobStatus(arg1) {
+  return window['go']['main']['App']['GetJobStatus'](arg1);
+}
+
+export function GetJobs() {
+  return window['go']['main']['App']['GetJobs']();
+}
+
+export function GetSheets(arg1) {
+  return window['go']['main']['App']['GetSheets'](arg1);
+}
+
+export function GetTheme() {
+  return window['go']['main']['App']['GetTheme']();
+}
+
+export function GetTimestampFormat() {
+  return window['go']['main']['App']['GetTimestampFormat']();
+}
+
+export function GetTimestampPresets() {
+  return window['go']['main']['App']['GetTimestampPresets']();
+}
+
+export function GetWatchStatus() {
+  return window['go']['main']['App']['GetWatchStatus']();
+}
+
+export function ListActions() {
+  return window['go']['main']['App']['ListActions']();
+}
+
+export function ListJobs() {
+  return window['go']['main']['App']['ListJobs']();
+}
+
+export function OpenHistoryOutput(arg1) {
+  return window['go']['main']['App']['OpenHistoryOutput'](arg1);
+}
+
+export function OpenLogFolder() {
+  return window['go']['main']['App']['OpenLogFolder']();
+}
+
 export function PerformUpdate(arg1) {
   return window['go']['main']['App']['PerformUpdate'](arg1);
 }
 
+export function Preview(arg1) {
+  return window['go']['main']['App']['Preview'](arg1);
+}
+
 export function Process(arg1) {
   return window['go']['main']['App']['Process'](arg1);
 }
 
+export function RevertConversion(arg1) {
+  return window['go']['main']['App']['RevertConversion'](arg1);
+}
+
+export function RunAction(arg1) {
+  return window['go']['main']['App']['RunAction'](arg1);
+}
+
+export function ScanFolder(arg1) {
+  return window['go']['main']['App']['ScanFolder'](arg1);
+}
+
+export function ScheduleUpdateOnExit(arg1) {
+  return window['go']['main']['App']['ScheduleUpdateOnExit'](arg1);
+}
+
+export function SearchHistory(arg1) {
+  return window['go']['main']['App']['SearchHistory'](arg1);
+}
+
 export function SelectFile() {
   return window['go']['main']['App']['SelectFile']();
 }
 
+export function SelectFiles() {
+  return window['go']['main']['App']['SelectFiles']();
+}
+
+export function SelectFolder() {
+  return window['go']['main']['App']['SelectFolder']();
+}
+
+export function SetDetector(arg1) {
+  return window['go']['main']['App']['SetDetector'](arg1);
+}
+
+export function SetFontPolicy(arg1) {
+  return window['go']['main']['App']['SetFontPolicy'](arg1);
+}
+
+export function SetTheme(arg1) {
+  return window['go']['main']['App']['SetTheme'](arg1);
+}
+
+export function SetTimestampFormat(arg1) {
+  return window['go']['main']['App']['SetTimestampFormat'](arg1);
+}
+
 export function ShowInFolder(arg1) {
   return window['go']['main']['App']['ShowInFolder'](arg1);
 }
+
+export function StartJob(arg1) {
+  return window['go']['main']['App']['StartJob'](arg1);
+}
+
+export function StartWatch(arg1, arg2) {
+  return window['go']['main']['App']['StartWatch'](arg1, arg2);
+}
+
+export function StopWatch() {
+  return window['go']['main']['App']['StopWatch']();
+}
+
+export function TraceText(arg1,arg2) {
+  return window['go']['main']['App']['TraceText'](arg1,arg2);
+}
+
+export function TranscodeText(arg1,arg2,arg3) {
+  return window['go']['main']['App']['TranscodeText'](arg1,arg2,arg3);
+}
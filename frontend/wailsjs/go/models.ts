@@ -1,8 +1,436 @@
+export namespace converter {
+	
+	export class Guess {
+	    inputOffset: number;
+	    input: string;
+	    output: string;
+	    alternative: string;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Guess(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.inputOffset = source["inputOffset"];
+	        this.input = source["input"];
+	        this.output = source["output"];
+	        this.alternative = source["alternative"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class Mapping {
+	    input: string;
+	    output: string;
+	    rule: string;
+	    inputOffset: number;
+	    outputOffset: number;
+	    guess?: Guess;
+	
+	    static createFrom(source: any = {}) {
+	        return new Mapping(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.input = source["input"];
+	        this.output = source["output"];
+	        this.rule = source["rule"];
+	        this.inputOffset = source["inputOffset"];
+	        this.outputOffset = source["outputOffset"];
+	        this.guess = this.convertValues(source["guess"], Guess);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TranscodeResult {
+	    text: string;
+	    unencodable: number;
+	    characters?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TranscodeResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.unencodable = source["unencodable"];
+	        this.characters = source["characters"];
+	    }
+	}
+}
+
+export namespace engine {
+	
+	export class CellError {
+	    sheetName: string;
+	    axis?: string;
+	    stage: string;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CellError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sheetName = source["sheetName"];
+	        this.axis = source["axis"];
+	        this.stage = source["stage"];
+	        this.error = source["error"];
+	    }
+	}
+	export class FileResult {
+	    inputPath: string;
+	    outputPath?: string;
+	    error?: string;
+	    duplicateOf?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.inputPath = source["inputPath"];
+	        this.outputPath = source["outputPath"];
+	        this.error = source["error"];
+	        this.duplicateOf = source["duplicateOf"];
+	    }
+	}
+	export class LegacyCell {
+	    sheetName: string;
+	    axis: string;
+	    encoding: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LegacyCell(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sheetName = source["sheetName"];
+	        this.axis = source["axis"];
+	        this.encoding = source["encoding"];
+	    }
+	}
+	export class PastedRange {
+	    content: string;
+	    format: string;
+	    encoding: string;
+	    cells: number;
+	    converted: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PastedRange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.content = source["content"];
+	        this.format = source["format"];
+	        this.encoding = source["encoding"];
+	        this.cells = source["cells"];
+	        this.converted = source["converted"];
+	    }
+	}
+	export class SkippedCell {
+	    sheetName: string;
+	    axis: string;
+	    length: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SkippedCell(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sheetName = source["sheetName"];
+	        this.axis = source["axis"];
+	        this.length = source["length"];
+	    }
+	}
+	export class TruncatedCell {
+	    sheetName: string;
+	    axis: string;
+	    length: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TruncatedCell(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sheetName = source["sheetName"];
+	        this.axis = source["axis"];
+	        this.length = source["length"];
+	    }
+	}
+	export class RenamedSheet {
+	    from: string;
+	    to: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RenamedSheet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from = source["from"];
+	        this.to = source["to"];
+	    }
+	}
+	export class CheckReport {
+	    inputPath: string;
+	    sheets: string[];
+	    cellsScanned: number;
+	    legacyCells: number;
+	    byEncoding: Record<string, number>;
+	    bySheet: Record<string, number>;
+	    locations: LegacyCell[];
+	    locationsCapped: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CheckReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.inputPath = source["inputPath"];
+	        this.sheets = source["sheets"];
+	        this.cellsScanned = source["cellsScanned"];
+	        this.legacyCells = source["legacyCells"];
+	        this.byEncoding = source["byEncoding"];
+	        this.bySheet = source["bySheet"];
+	        this.locations = this.convertValues(source["locations"], LegacyCell);
+	        this.locationsCapped = source["locationsCapped"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CellChange {
+	    sheetName: string;
+	    axis: string;
+	    before: string;
+	    after: string;
+	    encoding: string;
+	    font?: string;
+	    convertedFont?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CellChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sheetName = source["sheetName"];
+	        this.axis = source["axis"];
+	        this.before = source["before"];
+	        this.after = source["after"];
+	        this.encoding = source["encoding"];
+	        this.font = source["font"];
+	        this.convertedFont = source["convertedFont"];
+	    }
+	}
+	export class PreviewReport {
+	    inputPath: string;
+	    sheets: string[];
+	    cellsScanned: number;
+	    cellsChanged: number;
+	    changes: CellChange[];
+	    changesCapped: boolean;
+	    renamedSheets?: RenamedSheet[];
+	    skippedCells?: SkippedCell[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PreviewReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.inputPath = source["inputPath"];
+	        this.sheets = source["sheets"];
+	        this.cellsScanned = source["cellsScanned"];
+	        this.cellsChanged = source["cellsChanged"];
+	        this.changes = this.convertValues(source["changes"], CellChange);
+	        this.changesCapped = source["changesCapped"];
+	        this.renamedSheets = this.convertValues(source["renamedSheets"], RenamedSheet);
+	        this.skippedCells = this.convertValues(source["skippedCells"], SkippedCell);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SheetInfo {
+	    name: string;
+	    rows: number;
+	    hasLegacy: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SheetInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.rows = source["rows"];
+	        this.hasLegacy = source["hasLegacy"];
+	    }
+	}
+
+}
+
+export namespace history {
+	
+	export class Entry {
+	    id: number;
+	    inputPath: string;
+	    outputPath: string;
+	    sheets?: string[];
+	    cellsConverted: number;
+	    durationMs: number;
+	    byEncoding?: Record<string, number>;
+	    // Go type: time
+	    timestamp: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.inputPath = source["inputPath"];
+	        this.outputPath = source["outputPath"];
+	        this.sheets = source["sheets"];
+	        this.cellsConverted = source["cellsConverted"];
+	        this.durationMs = source["durationMs"];
+	        this.byEncoding = source["byEncoding"];
+	        this.timestamp = this.convertValues(source["timestamp"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace main {
 	
+	export class Action {
+	    id: string;
+	    title: string;
+	    description: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Action(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.title = source["title"];
+	        this.description = source["description"];
+	    }
+	}
+	export class ActionResult {
+	    message: string;
+	    jobId?: string;
+	    data?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ActionResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.message = source["message"];
+	        this.jobId = source["jobId"];
+	        this.data = source["data"];
+	    }
+	}
 	export class Config {
 	    inputPath: string;
+	    inputPaths: string[];
+	    parallel: number;
+	    workers: number;
 	    sheetName: string;
+	    cellRange: string;
+	    columns: string;
+	    encoding: string;
+	    timingReport: boolean;
+	    detectionTrace: boolean;
+	    fontReport: boolean;
+	    spellCheck: boolean;
+	    exportPDF: boolean;
+	    changeReport: string;
+	    inPlace: string;
+	    largeFileMode: boolean;
+	    force: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new Config(source);
@@ -11,13 +439,84 @@ export namespace main {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.inputPath = source["inputPath"];
+	        this.inputPaths = source["inputPaths"];
+	        this.parallel = source["parallel"];
+	        this.workers = source["workers"];
+	        this.sheetName = source["sheetName"];
+	        this.cellRange = source["cellRange"];
+	        this.columns = source["columns"];
+	        this.encoding = source["encoding"];
+	        this.timingReport = source["timingReport"];
+	        this.detectionTrace = source["detectionTrace"];
+	        this.fontReport = source["fontReport"];
+	        this.spellCheck = source["spellCheck"];
+	        this.exportPDF = source["exportPDF"];
+	        this.changeReport = source["changeReport"];
+	        this.inPlace = source["inPlace"];
+	        this.largeFileMode = source["largeFileMode"];
+	        this.force = source["force"];
+	    }
+	}
+	export class JobStatus {
+	    jobId: string;
+	    stage: string;
+	    percent: number;
+	    etaSeconds: number;
+	    sheetName: string;
+	    processed: number;
+	    total: number;
+	    filesDone: number;
+	    filesTotal: number;
+	    result?: ProcessResult;
+	    reverted?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.stage = source["stage"];
+	        this.percent = source["percent"];
+	        this.etaSeconds = source["etaSeconds"];
 	        this.sheetName = source["sheetName"];
+	        this.processed = source["processed"];
+	        this.total = source["total"];
+	        this.filesDone = source["filesDone"];
+	        this.filesTotal = source["filesTotal"];
+	        this.result = this.convertValues(source["result"], ProcessResult);
+	        this.reverted = source["reverted"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class ProcessResult {
 	    success: boolean;
 	    message: string;
 	    outputPath: string;
+	    backupPath?: string;
+	    skippedCells?: engine.SkippedCell[];
+	    truncatedCells?: engine.TruncatedCell[];
+	    renamedSheets?: engine.RenamedSheet[];
+	    files?: engine.FileResult[];
+	    errors?: engine.CellError[];
 	
 	    static createFrom(source: any = {}) {
 	        return new ProcessResult(source);
@@ -28,7 +527,31 @@ export namespace main {
 	        this.success = source["success"];
 	        this.message = source["message"];
 	        this.outputPath = source["outputPath"];
+	        this.backupPath = source["backupPath"];
+	        this.skippedCells = this.convertValues(source["skippedCells"], engine.SkippedCell);
+	        this.truncatedCells = this.convertValues(source["truncatedCells"], engine.TruncatedCell);
+	        this.renamedSheets = this.convertValues(source["renamedSheets"], engine.RenamedSheet);
+	        this.files = this.convertValues(source["files"], engine.FileResult);
+	        this.errors = this.convertValues(source["errors"], engine.CellError);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class UpdateInfo {
 	    available: boolean;
@@ -36,6 +559,7 @@ export namespace main {
 	    latestVersion: string;
 	    downloadUrl: string;
 	    releaseUrl: string;
+	    error?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new UpdateInfo(source);
@@ -48,7 +572,55 @@ export namespace main {
 	        this.latestVersion = source["latestVersion"];
 	        this.downloadUrl = source["downloadUrl"];
 	        this.releaseUrl = source["releaseUrl"];
+	        this.error = source["error"];
+	    }
+	}
+	export class WatchStatus {
+	    running: boolean;
+	    inputDir: string;
+	    outputDir: string;
+	    converted: number;
+	    failed: number;
+	    lastInput?: string;
+	    lastOutput?: string;
+	    lastError?: string;
+	    // Go type: time
+	    startedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.inputDir = source["inputDir"];
+	        this.outputDir = source["outputDir"];
+	        this.converted = source["converted"];
+	        this.failed = source["failed"];
+	        this.lastInput = source["lastInput"];
+	        this.lastOutput = source["lastOutput"];
+	        this.lastError = source["lastError"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 
 }
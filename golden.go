@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"convert-vni-to-unicode/internal/converter"
+	"convert-vni-to-unicode/internal/engine"
+	"convert-vni-to-unicode/internal/settings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// runGolden implements the "golden" subcommand and returns the process exit code:
+// 0 when the conversion matches the golden output, 1 when it differs, 2 on usage errors.
+// Why: Before rolling out a new version, teams re-convert reference workbooks and check
+// that every cell still converts exactly as the approved output.
+func runGolden(args []string, stdout, stderr io.Writer) int {
+	defaults := settings.Default()
+	fs := flag.NewFlagSet("golden", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	golden := fs.String("golden", "", "the approved output to compare with (required)")
+	update := fs.Bool("update", false, "save the conversion as the new golden output instead of comparing")
+	reportPath := fs.String("report", "", "write the differences to this CSV file")
+	encoding := fs.String("encoding", string(converter.EncodingAuto),
+		"source encoding: AUTO, VNI, TCVN3, VNI-DOS, VNU, VIQR")
+	fontPolicy := fs.String("font-policy", defaults.FontPolicy, "output font policy: keep, map, default, per-encoding")
+	detectorName := fs.String("detector", defaults.Detector, "auto-detect implementation: rules (default) or ngram")
+	plainCells := fs.Bool("plain-cells", defaults.PlainCells,
+		"write plain cells back as plain strings with the font in the cell style, not as rich text")
+	keepSheetNames := fs.Bool("keep-sheet-names", defaults.KeepSheetNames, "do not convert legacy-encoded sheet names")
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr,
+			"Usage: VniConverter golden -golden expected.xlsx [-update] [-report diff.csv] input.xlsx")
+		_, _ = fmt.Fprintln(stderr, "Converts input in memory and reports every cell that differs from the golden output.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *golden == "" || fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	input := fs.Arg(0)
+
+	policy, err := engine.NewFontPolicy(*fontPolicy)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	detector, err := engine.NewDetector(*detectorName)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	sourceEncoding := converter.EncodingType(strings.ToUpper(*encoding))
+	if sourceEncoding != converter.EncodingAuto {
+		if _, err := converter.NewConverter(sourceEncoding); err != nil {
+			_, _ = fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+	}
+	opts := []engine.Option{
+		engine.WithSourceEncoding(sourceEncoding),
+		engine.WithFontPolicy(policy),
+		engine.WithDetector(detector),
+		engine.WithPlainCells(*plainCells),
+		engine.WithConvertSheetNames(!*keepSheetNames),
+		engine.WithBuildInfo(engine.NewBuildInfo(CurrentVersion)),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *update {
+		if err := saveGolden(ctx, input, *golden, opts); err != nil {
+			_, _ = fmt.Fprintln(stderr, "Error:", err)
+			return 1
+		}
+		_, _ = fmt.Fprintf(stdout, "Saved %s as the golden output of %s\n", *golden, input)
+		return 0
+	}
+
+	report, err := engine.CompareGolden(ctx, input, *golden, opts...)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	if *reportPath != "" {
+		if err := writeGoldenReport(report, *reportPath); err != nil {
+			_, _ = fmt.Fprintln(stderr, "Error:", err)
+			return 1
+		}
+	}
+	return printGoldenReport(stdout, input, report)
+}
+
+// printGoldenReport prints every difference of report and returns the exit code.
+func printGoldenReport(stdout io.Writer, input string, report *engine.GoldenReport) int {
+	for _, sheet := range report.MissingSheets {
+		_, _ = fmt.Fprintf(stdout, "missing sheet %q\n", sheet)
+	}
+	for _, sheet := range report.ExtraSheets {
+		_, _ = fmt.Fprintf(stdout, "unexpected sheet %q\n", sheet)
+	}
+	for _, d := range report.Differences {
+		_, _ = fmt.Fprintf(stdout, "%s!%s %s: expected %q, got %q\n", d.SheetName, d.Axis, d.Field, d.Expected, d.Actual)
+	}
+	if !report.Passed() {
+		differences := len(report.Differences) + len(report.MissingSheets) + len(report.ExtraSheets)
+		_, _ = fmt.Fprintf(stdout, "FAIL %s: %d difference(s) in %d cell(s) compared\n",
+			input, differences, report.CellsCompared)
+		return 1
+	}
+	_, _ = fmt.Fprintf(stdout, "OK   %s: %d cell(s) match\n", input, report.CellsCompared)
+	return 0
+}
+
+// saveGolden converts input in memory and saves the result as the golden output.
+func saveGolden(ctx context.Context, input, golden string, opts []engine.Option) error {
+	f, err := excelize.OpenFile(input)
+	if err != nil {
+		return fmt.Errorf("failed to open input: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := engine.ProcessWorkbook(ctx, f, opts...); err != nil {
+		return err
+	}
+	if err := f.SaveAs(golden); err != nil {
+		return fmt.Errorf("failed to save golden output: %w", err)
+	}
+	return nil
+}
+
+// writeGoldenReport saves the differences as CSV.
+func writeGoldenReport(report *engine.GoldenReport, path string) error {
+	f, err := os.Create(path) //nolint:gosec // path chosen by the user
+	if err != nil {
+		return fmt.Errorf("failed to create report %s: %w", path, err)
+	}
+	writeErr := report.WriteCSV(f)
+	if closeErr := f.Close(); closeErr != nil && writeErr == nil {
+		writeErr = closeErr
+	}
+	return writeErr
+}
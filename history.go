@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"convert-vni-to-unicode/internal/engine"
+	"convert-vni-to-unicode/internal/history"
+)
+
+// historyLimit caps the entries GetHistory and SearchHistory return.
+const historyLimit = 500
+
+// conversionHistory returns the conversion history, or nil when it is unavailable.
+func (a *App) conversionHistory() *history.Store {
+	if a.settings == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.history == nil {
+		dir := filepath.Dir(a.settings.Path())
+		store, err := history.Open(filepath.Join(dir, "history.db"))
+		if err != nil {
+			slog.Warn("failed to open conversion history", "error", err)
+			return nil
+		}
+		importOldHistory(store, filepath.Join(dir, "history.jsonl"))
+		a.history = store
+	}
+	return a.history
+}
+
+// importOldHistory moves the entries of the history.jsonl file earlier versions kept into
+// store once, renaming the file to history.jsonl.imported afterwards.
+func importOldHistory(store *history.Store, path string) {
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+	n, err := store.ImportJSONL(path)
+	if err != nil {
+		slog.Warn("failed to import old conversion history", "path", path, "error", err)
+		return
+	}
+	if err = os.Rename(path, path+".imported"); err != nil {
+		slog.Warn("failed to rename old conversion history", "path", path, "error", err)
+	}
+	slog.Info("imported old conversion history", "entries", n)
+}
+
+// closeHistory closes the conversion history database, if it was opened.
+func (a *App) closeHistory() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.history == nil {
+		return
+	}
+	if err := a.history.Close(); err != nil {
+		slog.Warn("failed to close conversion history", "error", err)
+	}
+	a.history = nil
+}
+
+// recordHistory adds a completed conversion of input to the history.
+func (a *App) recordHistory(input, output string, stats engine.ConversionStats, duration time.Duration) {
+	store := a.conversionHistory()
+	if store == nil {
+		return
+	}
+	byEncoding := make(map[string]int, len(stats.ByEncoding))
+	for enc, n := range stats.ByEncoding {
+		byEncoding[string(enc)] = n
+	}
+	_, err := store.Add(history.Entry{
+		InputPath:      input,
+		OutputPath:     output,
+		Sheets:         stats.Sheets,
+		CellsConverted: stats.Converted,
+		DurationMs:     duration.Milliseconds(),
+		ByEncoding:     byEncoding,
+	})
+	if err != nil {
+		slog.Warn("failed to update conversion history", "error", err)
+	}
+}
+
+// GetHistory returns the latest completed conversions, newest first, for the History tab.
+func (a *App) GetHistory() []history.Entry {
+	return a.SearchHistory("")
+}
+
+// SearchHistory returns the completed conversions whose input or output path, or one of
+// whose sheets, contains query, newest first.
+func (a *App) SearchHistory(query string) []history.Entry {
+	store := a.conversionHistory()
+	if store == nil {
+		return nil
+	}
+	entries, err := store.Search(query, historyLimit)
+	if err != nil {
+		slog.Warn("failed to search conversion history", "error", err)
+	}
+	return entries
+}
+
+// OpenHistoryOutput opens the output of a past conversion with its default application.
+// Why: Users come back for a file converted days ago without remembering where it went.
+func (a *App) OpenHistoryOutput(id int) error {
+	store := a.conversionHistory()
+	if store == nil {
+		return fmt.Errorf("conversion history unavailable")
+	}
+	e, err := store.Get(id)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(e.OutputPath); err != nil {
+		return fmt.Errorf("output no longer available: %w", err)
+	}
+	cmd := exec.CommandContext(a.ctx, "explorer", e.OutputPath)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", e.OutputPath, err)
+	}
+	return nil
+}
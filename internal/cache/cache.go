@@ -0,0 +1,132 @@
+// Package cache remembers finished conversions keyed by input file hash.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry is a cached conversion result.
+type Entry struct {
+	InputHash   string    `json:"inputHash"`
+	SettingsKey string    `json:"settingsKey"`
+	OutputPath  string    `json:"outputPath"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// ResultCache maps (input hash, settings) to an existing output file.
+// Why: In watch-folder setups the same file gets re-dropped; re-converting it is wasted work.
+// It is safe for concurrent use.
+type ResultCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]Entry
+}
+
+// Open loads the cache stored at path. A missing file yields an empty cache.
+func Open(path string) (*ResultCache, error) {
+	c := &ResultCache{path: path, entries: make(map[string]Entry)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+	var list []Entry
+	if err := json.Unmarshal(data, &list); err != nil {
+		// A corrupt cache is only a lost optimization; start over
+		return c, nil
+	}
+	for _, e := range list {
+		c.entries[key(e.InputHash, e.SettingsKey)] = e
+	}
+	return c, nil
+}
+
+// Lookup returns the output of an earlier identical conversion, if that output still exists.
+func (c *ResultCache) Lookup(inputHash, settingsKey string) (string, bool) {
+	c.mu.Lock()
+	e, ok := c.entries[key(inputHash, settingsKey)]
+	c.mu.Unlock()
+	if !ok {
+		return "", false
+	}
+	if _, err := os.Stat(e.OutputPath); err != nil {
+		return "", false
+	}
+	return e.OutputPath, true
+}
+
+// Seen reports whether an identical conversion was recorded, whether or not its output
+// still exists.
+// Why: The watch folder must not regenerate outputs that retention or a user deleted.
+func (c *ResultCache) Seen(inputHash, settingsKey string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[key(inputHash, settingsKey)]
+	return ok
+}
+
+// Store records a finished conversion and persists the cache.
+func (c *ResultCache) Store(inputHash, settingsKey, outputPath string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key(inputHash, settingsKey)] = Entry{
+		InputHash:   inputHash,
+		SettingsKey: settingsKey,
+		OutputPath:  outputPath,
+		CreatedAt:   time.Now(),
+	}
+	return c.save()
+}
+
+// save writes the cache atomically. Caller must hold mu.
+func (c *ResultCache) save() error {
+	list := make([]Entry, 0, len(c.entries))
+	for _, e := range c.entries {
+		list = append(list, e)
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0750); err != nil {
+		return fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to replace cache: %w", err)
+	}
+	return nil
+}
+
+func key(inputHash, settingsKey string) string {
+	return inputHash + "|" + settingsKey
+}
+
+// HashFile returns the hex SHA-256 of the file at path.
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path) //nolint:gosec // path is a user-selected input file
+	if err != nil {
+		return "", fmt.Errorf("failed to open file for hashing: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResultCache_StoreLookup(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "out.xlsx")
+	if err := os.WriteFile(output, []byte("x"), 0600); err != nil {
+		t.Fatalf("failed to write output: %v", err)
+	}
+
+	cachePath := filepath.Join(dir, "cache", "results.json")
+	c, err := Open(cachePath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := c.Store("abc", "sheet=", output); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	// Reopen to verify persistence
+	c2, err := Open(cachePath)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	tests := []struct {
+		name     string
+		hash     string
+		settings string
+		wantHit  bool
+	}{
+		{name: "Hit", hash: "abc", settings: "sheet=", wantHit: true},
+		{name: "Different settings", hash: "abc", settings: "sheet=Sheet2", wantHit: false},
+		{name: "Different hash", hash: "def", settings: "sheet=", wantHit: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := c2.Lookup(tt.hash, tt.settings)
+			if ok != tt.wantHit {
+				t.Fatalf("Lookup() hit = %v, want %v", ok, tt.wantHit)
+			}
+			if ok && got != output {
+				t.Errorf("Lookup() = %q, want %q", got, output)
+			}
+		})
+	}
+}
+
+func TestResultCache_MissingOutputIsMiss(t *testing.T) {
+	c, err := Open(filepath.Join(t.TempDir(), "results.json"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := c.Store("abc", "", filepath.Join(t.TempDir(), "deleted.xlsx")); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if _, ok := c.Lookup("abc", ""); ok {
+		t.Error("deleted output must not be a cache hit")
+	}
+	if !c.Seen("abc", "") {
+		t.Error("Seen() = false for a conversion whose output was deleted")
+	}
+	if c.Seen("abd", "") {
+		t.Error("Seen() = true for an input never converted")
+	}
+}
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	got, err := HashFile(path)
+	if err != nil {
+		t.Fatalf("HashFile failed: %v", err)
+	}
+	want := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if got != want {
+		t.Errorf("HashFile() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,44 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DecodeBytes turns raw legacy file bytes into the rune form the converters expect.
+// Why: txt/csv/xls inputs give us bytes, while the converters work on strings where
+// each rune is the legacy byte value (the same shape Excel produces for legacy fonts).
+func DecodeBytes(encoding EncodingType, data []byte) (string, error) {
+	switch encoding {
+	case EncodingVNI, EncodingTCVN3, EncodingVNIDOS, EncodingVNU:
+		var b strings.Builder
+		b.Grow(len(data) * 2)
+		for _, c := range data {
+			b.WriteRune(rune(c))
+		}
+		return b.String(), nil
+	case EncodingVISCII:
+		// VISCII letters fill 0x80-0x9F, which Windows-1252 reads differently from Latin-1
+		var b strings.Builder
+		b.Grow(len(data) * 2)
+		for _, c := range data {
+			b.WriteRune(windows1252Rune(c))
+		}
+		return b.String(), nil
+	default:
+		return "", fmt.Errorf("encoding %s is not byte-oriented", encoding)
+	}
+}
+
+// ConvertBytes decodes legacy bytes and converts them to Unicode in one step.
+func ConvertBytes(encoding EncodingType, data []byte) (string, error) {
+	text, err := DecodeBytes(encoding, data)
+	if err != nil {
+		return "", err
+	}
+	c, err := NewConverter(encoding)
+	if err != nil {
+		return "", err
+	}
+	return c.ToUnicode(text), nil
+}
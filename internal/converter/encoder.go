@@ -0,0 +1,177 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Encoder is implemented by converters that can also write a legacy encoding.
+// Why: Some downstream systems still require a specific legacy encoding, so we
+// offer legacy -> legacy conversion by composing ToUnicode with FromUnicode.
+type Encoder interface {
+	// FromUnicode converts the given Unicode string to the legacy encoding.
+	FromUnicode(text string) string
+}
+
+// NewEncoder creates an encoder for the given legacy encoding.
+func NewEncoder(encoding EncodingType) (Encoder, error) {
+	switch encoding {
+	case EncodingVNI:
+		return NewVNIEncoder(), nil
+	case EncodingTCVN3:
+		return NewTCVN3Encoder(), nil
+	case EncodingVISCII:
+		return NewVISCIIEncoder(), nil
+	default:
+		return nil, fmt.Errorf("unsupported target encoding: %s", encoding)
+	}
+}
+
+// TranscodeResult is text converted between two legacy encodings.
+type TranscodeResult struct {
+	Text string `json:"text"`
+	// Unencodable counts the characters the target encoding has no bytes for, which are
+	// left in Text as Unicode; Characters lists each of them once, in order of appearance.
+	Unencodable int      `json:"unencodable"`
+	Characters  []string `json:"characters,omitempty"`
+}
+
+// Transcode converts text between two legacy encodings via Unicode and reports the
+// characters the target encoding cannot write.
+// Why: Characters without a legacy byte (other scripts, symbols, decomposed marks) would
+// otherwise reach the downstream system silently as Unicode.
+func Transcode(from, to EncodingType, text string) (TranscodeResult, error) {
+	dec, err := NewConverter(from)
+	if err != nil {
+		return TranscodeResult{}, err
+	}
+	enc, err := NewEncoder(to)
+	if err != nil {
+		return TranscodeResult{}, err
+	}
+	result := TranscodeResult{Text: enc.FromUnicode(dec.ToUnicode(text))}
+	seen := make(map[rune]bool)
+	for _, r := range result.Text {
+		if legacyRunes[r] {
+			continue
+		}
+		result.Unencodable++
+		if !seen[r] {
+			seen[r] = true
+			result.Characters = append(result.Characters, string(r))
+		}
+	}
+	return result, nil
+}
+
+// legacyRunes holds every character legacy text can contain: each of the 256 bytes as
+// Excel shows it in Windows-1252.
+var legacyRunes = func() map[rune]bool {
+	runes := make(map[rune]bool, 256)
+	for b := 0; b < 256; b++ {
+		runes[windows1252Rune(byte(b))] = true
+	}
+	return runes
+}()
+
+// TCVN3Encoder converts Unicode to TCVN3 by inverting the decoding table.
+type TCVN3Encoder struct {
+	replacer *strings.Replacer
+}
+
+// NewTCVN3Encoder creates a new instance.
+func NewTCVN3Encoder() *TCVN3Encoder {
+	pairs := make([]string, 0, len(tcvn3Pairs))
+	for i := 0; i+1 < len(tcvn3Pairs); i += 2 {
+		pairs = append(pairs, tcvn3Pairs[i+1], tcvn3Pairs[i])
+	}
+	return &TCVN3Encoder{replacer: strings.NewReplacer(pairs...)}
+}
+
+// FromUnicode converts Unicode text to TCVN3.
+func (e *TCVN3Encoder) FromUnicode(text string) string {
+	return e.replacer.Replace(text)
+}
+
+// VISCIIEncoder converts Unicode to VISCII by inverting the decoding table.
+type VISCIIEncoder struct {
+	replacer *strings.Replacer
+}
+
+// NewVISCIIEncoder creates a new instance.
+func NewVISCIIEncoder() *VISCIIEncoder {
+	pairs := make([]string, 0, len(visciiPairs))
+	for i := 0; i+1 < len(visciiPairs); i += 2 {
+		pairs = append(pairs, visciiPairs[i+1], visciiPairs[i])
+	}
+	return &VISCIIEncoder{replacer: strings.NewReplacer(pairs...)}
+}
+
+// FromUnicode converts Unicode text to VISCII.
+func (e *VISCIIEncoder) FromUnicode(text string) string {
+	return e.replacer.Replace(text)
+}
+
+// vniToneMarkerOut is the marker written for each tone, indexed by [tone][isUpper].
+var vniToneMarkerOut = map[string][2]rune{
+	"grave": {'ø', 'Ø'},
+	"acute": {'ù', 'Ù'},
+	"hook":  {'û', 'Û'},
+	"tilde": {'õ', 'Õ'},
+	"dot":   {'ï', 'Ï'},
+}
+
+// VNIEncoder converts Unicode to VNI "base letter + marker" sequences.
+// The table is the inverse of the decoder's mark and letter maps, so output
+// decodes back to the same text with VNIConverter.
+type VNIEncoder struct {
+	table map[rune]string
+}
+
+// NewVNIEncoder creates a new instance.
+func NewVNIEncoder() *VNIEncoder {
+	table := map[rune]string{'ư': "ö", 'Ư': "Ö"}
+	for base := range vowelCombinations {
+		for marker, mark := range vniMarks {
+			if unicode.IsUpper(marker) != unicode.IsUpper(base) {
+				continue
+			}
+			if combined, ok := applyVNIMark(base, mark); ok {
+				table[combined] = string(base) + string(marker)
+			}
+		}
+	}
+	// Letters with a byte of their own win over a base letter and a mark ("æ", not "iû")
+	for legacy, letter := range vniLetters {
+		table[letter] = string(legacy)
+	}
+	for _, horn := range []rune{'ơ', 'Ơ', 'ư', 'Ư'} {
+		for tone, combined := range combinedVowelTones[horn] {
+			table[combined] = table[horn] + string(vniMarker(tone, horn))
+		}
+	}
+	return &VNIEncoder{table: table}
+}
+
+// FromUnicode converts Unicode text to VNI.
+func (e *VNIEncoder) FromUnicode(text string) string {
+	var b strings.Builder
+	b.Grow(len(text) * 2)
+	for _, r := range text {
+		if seq, ok := e.table[r]; ok {
+			b.WriteString(seq)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func vniMarker(tone string, letter rune) rune {
+	markers := vniToneMarkerOut[tone]
+	if unicode.IsUpper(letter) {
+		return markers[1]
+	}
+	return markers[0]
+}
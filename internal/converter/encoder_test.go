@@ -0,0 +1,96 @@
+package converter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVNIEncoder_RoundTrip(t *testing.T) {
+	enc := NewVNIEncoder()
+	dec := NewVNIConverter()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Sentence", input: "Việt Nam"},
+		{name: "Circumflex with tones", input: "ấ ầ ẩ ẫ ậ"},
+		{name: "Breve", input: "Đặng Thị Hằng"},
+		{name: "Horn u", input: "thứ tư"},
+		{name: "Uppercase", input: "VIỆT NAM"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dec.ToUnicode(enc.FromUnicode(tt.input)); got != tt.input {
+				t.Errorf("round trip = %q, want %q", got, tt.input)
+			}
+		})
+	}
+}
+
+func TestTCVN3Encoder_FromUnicode(t *testing.T) {
+	enc := NewTCVN3Encoder()
+	if got := enc.FromUnicode("Công ty"); got != "C«ng ty" {
+		t.Errorf("FromUnicode() = %q, want %q", got, "C«ng ty")
+	}
+}
+
+func TestTranscode(t *testing.T) {
+	tests := []struct {
+		name            string
+		from            EncodingType
+		to              EncodingType
+		input           string
+		expected        string
+		wantUnencodable int
+		wantCharacters  []string
+		wantErr         bool
+	}{
+		{
+			name:     "TCVN3 to VNI",
+			from:     EncodingTCVN3,
+			to:       EncodingVNI,
+			input:    "C«ng ty",
+			expected: "Coâng ty",
+		},
+		{
+			name:     "VNI to TCVN3",
+			from:     EncodingVNI,
+			to:       EncodingTCVN3,
+			input:    "Coâng ty",
+			expected: "C«ng ty",
+		},
+		{
+			name:            "Characters without a legacy byte reported",
+			from:            EncodingVNI,
+			to:              EncodingTCVN3,
+			input:           "Coâng ty \u4E2D\u6587\u4E2D",
+			expected:        "C«ng ty \u4E2D\u6587\u4E2D",
+			wantUnencodable: 3,
+			wantCharacters:  []string{"\u4E2D", "\u6587"},
+		},
+		{
+			name:    "Unsupported target",
+			from:    EncodingVNI,
+			to:      EncodingVNU,
+			input:   "abc",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Transcode(tt.from, tt.to, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Transcode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Text != tt.expected {
+				t.Errorf("Transcode() = %q, want %q", got.Text, tt.expected)
+			}
+			if got.Unencodable != tt.wantUnencodable || !reflect.DeepEqual(got.Characters, tt.wantCharacters) {
+				t.Errorf("Transcode() unencodable = %d %q, want %d %q", got.Unencodable, got.Characters, tt.wantUnencodable, tt.wantCharacters)
+			}
+		})
+	}
+}
@@ -10,6 +10,14 @@ func NewConverter(encoding EncodingType) (Converter, error) {
 		return NewVNIConverter(), nil
 	case EncodingTCVN3:
 		return NewTCVN3Converter(), nil
+	case EncodingVNIDOS:
+		return NewVNIDOSConverter(), nil
+	case EncodingVNU:
+		return NewVNUConverter(), nil
+	case EncodingVISCII:
+		return NewVISCIIConverter(), nil
+	case EncodingVIQR:
+		return NewVIQRConverter(), nil
 	default:
 		return nil, fmt.Errorf("unsupported encoding type: %s", encoding)
 	}
@@ -0,0 +1,51 @@
+package converter
+
+import (
+	"fmt"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalization is the Unicode normalization form of converted text.
+type Normalization string
+
+const (
+	// NormalizationNFC writes precomposed letters, as the converters do (the default)
+	NormalizationNFC Normalization = "nfc"
+	// NormalizationNFD writes each letter as its base letter followed by combining marks
+	NormalizationNFD Normalization = "nfd"
+)
+
+// ParseNormalization returns the normalization named s (case-insensitive); "" selects
+// NormalizationNFC.
+func ParseNormalization(s string) (Normalization, error) {
+	switch n := Normalization(s); n {
+	case "", NormalizationNFC, "NFC":
+		return NormalizationNFC, nil
+	case NormalizationNFD, "NFD":
+		return NormalizationNFD, nil
+	default:
+		return "", fmt.Errorf("unknown normalization %q (want %s or %s)", s, NormalizationNFC, NormalizationNFD)
+	}
+}
+
+// Apply returns converted text in the normalization form n. Converters already write NFC,
+// so NFC (and the zero value) returns text unchanged.
+// Why: Some downstream systems (older Java applications) only read decomposed Unicode.
+func (n Normalization) Apply(text string) string {
+	if n == NormalizationNFD {
+		return norm.NFD.String(text)
+	}
+	return text
+}
+
+// NormalizedConverter writes the output of Converter in the normalization form Form.
+type NormalizedConverter struct {
+	Converter
+	Form Normalization
+}
+
+// ToUnicode converts text with the wrapped converter and normalizes the result.
+func (c NormalizedConverter) ToUnicode(text string) string {
+	return c.Form.Apply(c.Converter.ToUnicode(text))
+}
@@ -0,0 +1,33 @@
+package converter
+
+import "testing"
+
+func TestNormalizedConverter_ToUnicode(t *testing.T) {
+	tests := []struct {
+		name     string
+		form     string
+		input    string
+		expected string
+	}{
+		{name: "Default NFC", form: "", input: "Vi\u00D6t Nam", expected: "Việt Nam"},
+		{name: "NFC", form: "NFC", input: "Vi\u00D6t Nam", expected: "Việt Nam"},
+		{name: "NFD", form: "nfd", input: "Vi\u00D6t Nam", expected: "Vie\u0323\u0302t Nam"},
+		{name: "NFD plain text", form: "nfd", input: "Hello", expected: "Hello"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form, err := ParseNormalization(tt.form)
+			if err != nil {
+				t.Fatalf("ParseNormalization(%q) failed: %v", tt.form, err)
+			}
+			c := NormalizedConverter{Converter: NewVNIConverter(), Form: form}
+			if got := c.ToUnicode(tt.input); got != tt.expected {
+				t.Errorf("ToUnicode(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := ParseNormalization("nfkc"); err == nil {
+		t.Error("ParseNormalization() accepted an unknown form")
+	}
+}
@@ -8,82 +8,105 @@ type TCVN3Converter struct {
 	replacer *strings.Replacer
 }
 
+// tcvn3Pairs is the TCVN3 -> Unicode table as old/new pairs.
+// Why: Shared by the decoder (replacer) and the reverse encoder.
+// Each line is a byte as Excel shows it (Windows-1252) and the letter it encodes.
+var tcvn3Pairs = []string{
+	"\u00B8", "á", // ¸
+	"\u00B5", "à", // µ
+	"\u00B6", "ả", // ¶
+	"\u00B7", "ã", // ·
+	"\u00B9", "ạ", // ¹
+
+	"\u00A8", "ă", // ¨
+	"\u00BE", "ắ", // ¾
+	"\u00BB", "ằ", // »
+	"\u00BC", "ẳ", // ¼
+	"\u00BD", "ẵ", // ½
+	"\u00C6", "ặ", // Æ
+
+	"\u00A9", "â", // ©
+	"\u00CA", "ấ", // Ê
+	"\u00C7", "ầ", // Ç
+	"\u00C8", "ẩ", // È
+	"\u00C9", "ẫ", // É
+	"\u00CB", "ậ", // Ë
+
+	"\u00D0", "é", // Ð
+	"\u00CC", "è", // Ì
+	"\u00CE", "ẻ", // Î
+	"\u00CF", "ẽ", // Ï
+	"\u00D1", "ẹ", // Ñ
+
+	"\u00AA", "ê", // ª
+	"\u00D5", "ế", // Õ
+	"\u00D2", "ề", // Ò
+	"\u00D3", "ể", // Ó
+	"\u00D4", "ễ", // Ô
+	"\u00D6", "ệ", // Ö
+
+	"\u00DD", "í", // Ý
+	"\u00D7", "ì", // ×
+	"\u00D8", "ỉ", // Ø
+	"\u00DC", "ĩ", // Ü
+	"\u00DE", "ị", // Þ
+
+	"\u00E3", "ó", // ã
+	"\u00DF", "ò", // ß
+	"\u00E1", "ỏ", // á
+	"\u00E2", "õ", // â
+	"\u00E4", "ọ", // ä
+
+	"\u00AB", "ô", // «
+	"\u00E8", "ố", // è
+	"\u00E5", "ồ", // å
+	"\u00E6", "ổ", // æ
+	"\u00E7", "ỗ", // ç
+	"\u00E9", "ộ", // é
+
+	"\u00AC", "ơ", // ¬
+	"\u00ED", "ớ", // í
+	"\u00EA", "ờ", // ê
+	"\u00EB", "ở", // ë
+	"\u00EC", "ỡ", // ì
+	"\u00EE", "ợ", // î
+
+	"\u00F3", "ú", // ó
+	"\u00EF", "ù", // ï
+	"\u00F1", "ủ", // ñ
+	"\u00F2", "ũ", // ò
+	"\u00F4", "ụ", // ô
+
+	"\u00AD", "ư", // ­
+	"\u00F8", "ứ", // ø
+	"\u00F5", "ừ", // õ
+	"\u00F6", "ử", // ö
+	"\u00F7", "ữ", // ÷
+	"\u00F9", "ự", // ù
+
+	"\u00FD", "ý", // ý
+	"\u00FA", "ỳ", // ú
+	"\u00FB", "ỷ", // û
+	"\u00FC", "ỹ", // ü
+	"\u00FE", "ỵ", // þ
+
+	"\u00AE", "đ", // ®
+
+	// The capitals with a byte of their own; the others are written with the lowercase
+	// codes in the capital-only fonts (.VnTimeH, .VnArialH, ...), see ToUnicodeUpper.
+	"\u00A1", "Ă", // ¡
+	"\u00A2", "Â", // ¢
+	"\u00A3", "Ê", // £
+	"\u00A4", "Ô", // ¤
+	"\u00A5", "Ơ", // ¥
+	"\u00A6", "Ư", // ¦
+	"\u00A7", "Đ", // §
+}
+
 // NewTCVN3Converter creates a new instance.
 func NewTCVN3Converter() *TCVN3Converter {
 	return &TCVN3Converter{
-		replacer: strings.NewReplacer(
-			// Lowercase
-			"\u00B8", "á", // ¸
-			"\u00B5", "à", // µ
-			"\u00B6", "ả", // ¶
-			"\u00B7", "ã", // ·
-			"\u00B9", "ạ", // ¹
-
-			"\u00A2", "â", // ¢
-			"\u00CA", "ấ", // Ê -> Wait. TCVN3 map is tricky.
-			// Let's use the standard "ABC" table sequence.
-			// a, á, à, ả, ã, ạ
-			// ă, ắ, ằ, ẳ, ẵ, ặ
-			// ...
-
-			// Revised TCVN3 Table (ABC):
-			"\u00B8", "á",
-			"\u00B5", "à",
-			"\u00B6", "ả",
-			"\u00B7", "ã",
-			"\u00B9", "ạ",
-
-			"\u00A8", "ă",
-			"\u00BE", "ắ",
-			"\u00BB", "ằ",
-			"\u00BC", "ẳ",
-			"\u00BD", "ẵ",
-			"\u00C6", "ặ",
-
-			"\u00A2", "â",
-			"\u00CA", "ấ",
-			"\u00C7", "ầ",
-			"\u00C8", "ẩ",
-			"\u00C9", "ẫ",
-			"\u00CB", "ậ",
-
-			"\u00D1", "é", // Ñ
-			"\u00CC", "è", // Ì
-			"\u00D0", "ẻ", // Ð
-			"\u00CE", "ẽ", // Î
-			"\u00CF", "ẹ", // Ï
-
-			"\u00A3", "ê", // £
-			"\u00D5", "ế", // Õ
-			"\u00D2", "ề", // Ò
-			"\u00D3", "ể", // Ó
-			"\u00D4", "ễ", // Ô
-			"\u00D6", "ệ", // Ö
-
-			"\u00DD", "í", // Ý
-			"\u00D8", "ì", // Ø
-			"\u00DC", "ỉ", // Ü
-			"\u00DE", "ĩ", // Þ
-			"\u00DF", "ị", // ß
-
-			"\u00F3", "ó",
-			"\u00F2", "ò",
-			"\u00F4", "õ",
-			"\u00F5", "ọ",
-			"\u00F6", "ô", // ö
-
-			// Uppercase vowels in TCVN3 are often mapped to specific other chars or handled by
-			// .VnTimeH font (which maps standard ASCII A-Z to localized A-Z).
-			// However, mixed chars like 'ố' exist.
-			// TCVN3 Uppercase is typically dependent on using the UPPERCASE FONT (.VnTimeH).
-			// If the user uses .VnTimeH, then typing 'A' produces 'A', 'B' produces 'B'.
-			// But 'á' (input) -> '¸' -> displays 'Á' in .VnTimeH.
-			// So, if the font is .VnTimeH, we should convert '¸' to 'Á'.
-			// For now, let's strictly handle the lowercase logic which is universally mapped in the standard font.
-
-			// d
-			"\u00AE", "đ", // ®
-		),
+		replacer: strings.NewReplacer(tcvn3Pairs...),
 	}
 }
 
@@ -91,3 +114,24 @@ func NewTCVN3Converter() *TCVN3Converter {
 func (c *TCVN3Converter) ToUnicode(text string) string {
 	return c.replacer.Replace(text)
 }
+
+// ToUnicodeUpper converts text written in a TCVN3 capital-only font (e.g. .VnTimeH).
+// Why: Those fonts draw the lowercase codes (and ASCII letters) as capitals, so
+// "\u00B8" means "Á" there; once the font is switched the case must be in the text.
+func (c *TCVN3Converter) ToUnicodeUpper(text string) string {
+	return strings.ToUpper(c.ToUnicode(text))
+}
+
+// IsTCVN3UpperFont reports whether font is a TCVN3 capital-only variant such as ".VnTimeH".
+func IsTCVN3UpperFont(font string) bool {
+	return len(font) > len(".VnH") && strings.HasPrefix(font, ".Vn") && strings.HasSuffix(font, "H")
+}
+
+// TCVN3BaseFont returns the regular font for a capital-only variant (".VnTimeH" -> ".VnTime").
+// Other fonts are returned unchanged.
+func TCVN3BaseFont(font string) string {
+	if !IsTCVN3UpperFont(font) {
+		return font
+	}
+	return strings.TrimSuffix(font, "H")
+}
@@ -19,7 +19,7 @@ func TestTCVN3Converter_ToUnicode(t *testing.T) {
 		},
 		{
 			name:     "TCVN3 Sample Word",
-			input:    "C\u00F6ng ty", // "Cöng ty" in TCVN3 font displays as "Công ty"
+			input:    "C\u00ABng ty", // "C«ng ty" in TCVN3 font displays as "Công ty"
 			expected: "Công ty",
 		},
 	}
@@ -33,3 +33,49 @@ func TestTCVN3Converter_ToUnicode(t *testing.T) {
 		})
 	}
 }
+
+func TestTCVN3Converter_ToUnicodeUpper(t *testing.T) {
+	c := NewTCVN3Converter()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Toned a", input: "\u00B8 \u00B5 \u00B6 \u00B7 \u00B9", expected: "Á À Ả Ã Ạ"},
+		{name: "Word with d stroke", input: "\u00AE\u00B9i n\u00B8o", expected: "ĐẠI NÁO"},
+		{name: "ASCII letters drawn as capitals", input: "C\u00ABng ty", expected: "CÔNG TY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.ToUnicodeUpper(tt.input); got != tt.expected {
+				t.Errorf("ToUnicodeUpper() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTCVN3UpperFont(t *testing.T) {
+	tests := []struct {
+		font      string
+		wantUpper bool
+		wantBase  string
+	}{
+		{font: ".VnTimeH", wantUpper: true, wantBase: ".VnTime"},
+		{font: ".VnArialH", wantUpper: true, wantBase: ".VnArial"},
+		{font: ".VnTime", wantUpper: false, wantBase: ".VnTime"},
+		{font: ".VnH", wantUpper: false, wantBase: ".VnH"},
+		{font: "VNI-TimeH", wantUpper: false, wantBase: "VNI-TimeH"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.font, func(t *testing.T) {
+			if got := IsTCVN3UpperFont(tt.font); got != tt.wantUpper {
+				t.Errorf("IsTCVN3UpperFont(%q) = %v, want %v", tt.font, got, tt.wantUpper)
+			}
+			if got := TCVN3BaseFont(tt.font); got != tt.wantBase {
+				t.Errorf("TCVN3BaseFont(%q) = %q, want %q", tt.font, got, tt.wantBase)
+			}
+		})
+	}
+}
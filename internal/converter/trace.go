@@ -0,0 +1,193 @@
+package converter
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MappingRule identifies how a Mapping turned its input into its output.
+type MappingRule string
+
+const (
+	// RuleUnchanged means the input was copied as is.
+	RuleUnchanged MappingRule = "unchanged"
+	// RuleMapped means one character was replaced by another (e.g. TCVN3 "¸" -> "á").
+	RuleMapped MappingRule = "mapped"
+	// RuleCombined means several characters became one (e.g. VNI "aù" -> "á", VIQR "dd" -> "đ").
+	RuleCombined MappingRule = "combined"
+	// RuleExpanded means one character became several.
+	RuleExpanded MappingRule = "expanded"
+	// RuleRewritten means several characters were replaced by several others (e.g. VNI "ÖÔ" -> "ƯƠ").
+	RuleRewritten MappingRule = "rewritten"
+	// RuleRemoved means the input produced no output.
+	RuleRemoved MappingRule = "removed"
+	// RuleUnaligned means the text could not be split into steps; the mapping covers all of it.
+	RuleUnaligned MappingRule = "unaligned"
+)
+
+// Mapping is one step of a conversion: the Input runes produced the Output runes.
+// Offsets count runes (not bytes) so they can index the before/after strings in a UI.
+type Mapping struct {
+	Input        string      `json:"input"`
+	Output       string      `json:"output"`
+	Rule         MappingRule `json:"rule"`
+	InputOffset  int         `json:"inputOffset"`
+	OutputOffset int         `json:"outputOffset"`
+	// Guess is set when the step read an ambiguous character (see Guesser)
+	Guess *Guess `json:"guess,omitempty"`
+}
+
+// Guess is a choice a converter made between two readings of an ambiguous character.
+type Guess struct {
+	// InputOffset is the rune offset of the character in the converted text
+	InputOffset int    `json:"inputOffset"`
+	Input       string `json:"input"`
+	Output      string `json:"output"`
+	// Alternative is the reading not chosen
+	Alternative string `json:"alternative"`
+	// Reason is the rule that decided, e.g. "after a vowel" or "prefer-vni"
+	Reason string `json:"reason"`
+}
+
+// Guesser is implemented by converters that guess between readings of ambiguous
+// characters (see VNIStrictness).
+type Guesser interface {
+	// Guesses returns the guesses made converting text, in input order
+	Guesses(text string) []Guess
+}
+
+// Changed reports whether the mapping altered its input.
+func (m Mapping) Changed() bool {
+	return m.Rule != RuleUnchanged
+}
+
+// Trace converts text with c and explains the result character by character.
+// Concatenating the Output of every mapping gives c.ToUnicode(text).
+// Why: QA highlights exactly which characters a conversion changed. Tracing works with any
+// Converter by converting growing prefixes and attributing each change to the runes that
+// caused it, so normal conversions pay nothing for it.
+func Trace(c Converter, text string) []Mapping {
+	want := c.ToUnicode(text)
+	var (
+		mappings []Mapping
+		output   strings.Builder
+	)
+	inOffset, outOffset := 0, 0
+	// Legacy marks never combine across whitespace, so each word is traced on its own;
+	// this keeps the prefix conversions quadratic in the word length, not the text length
+	for _, word := range splitWords(text) {
+		steps := traceWord(c, []rune(word), inOffset, outOffset)
+		for _, m := range steps {
+			output.WriteString(m.Output)
+			outOffset += len([]rune(m.Output))
+		}
+		inOffset += len([]rune(word))
+		mappings = append(mappings, steps...)
+	}
+	if output.String() != want {
+		// The converter did not behave word by word; report the text as a single step
+		return []Mapping{{Input: text, Output: want, Rule: RuleUnaligned}}
+	}
+	if g, ok := c.(Guesser); ok {
+		attachGuesses(mappings, g.Guesses(text))
+	}
+	return mappings
+}
+
+// attachGuesses sets the Guess of the mappings whose input holds a guessed character.
+// Why: Users hitting systematic misguesses see which rule read each character, and can
+// pick a VNIStrictness that reads them right.
+func attachGuesses(mappings []Mapping, guesses []Guess) {
+	for _, guess := range guesses {
+		for i := range mappings {
+			m := &mappings[i]
+			if guess.InputOffset >= m.InputOffset && guess.InputOffset < m.InputOffset+len([]rune(m.Input)) {
+				m.Guess = &guess
+				break
+			}
+		}
+	}
+}
+
+// traceWord aligns one word by converting each of its prefixes. When adding a rune changes
+// output that earlier runes produced, those runes and the new one merge into one mapping.
+func traceWord(c Converter, word []rune, inOffset, outOffset int) []Mapping {
+	type span struct{ inStart, inEnd, outStart, outEnd int }
+	var (
+		spans []span
+		prev  []rune
+	)
+	for i := range word {
+		cur := []rune(c.ToUnicode(string(word[:i+1])))
+		common := commonPrefix(prev, cur)
+		merged := span{inStart: i, inEnd: i + 1, outStart: common, outEnd: len(cur)}
+		for len(spans) > 0 && spans[len(spans)-1].outEnd > common {
+			last := spans[len(spans)-1]
+			spans = spans[:len(spans)-1]
+			merged.inStart, merged.outStart = last.inStart, min(merged.outStart, last.outStart)
+		}
+		spans = append(spans, merged)
+		prev = cur
+	}
+
+	mappings := make([]Mapping, 0, len(spans))
+	for _, s := range spans {
+		in, out := string(word[s.inStart:s.inEnd]), string(prev[s.outStart:s.outEnd])
+		mappings = append(mappings, Mapping{
+			Input:        in,
+			Output:       out,
+			Rule:         classify(in, out),
+			InputOffset:  inOffset + s.inStart,
+			OutputOffset: outOffset + s.outStart,
+		})
+	}
+	return mappings
+}
+
+// classify picks the rule describing how in became out.
+func classify(in, out string) MappingRule {
+	inLen, outLen := len([]rune(in)), len([]rune(out))
+	switch {
+	case in == out:
+		return RuleUnchanged
+	case outLen == 0:
+		return RuleRemoved
+	case inLen == 1 && outLen == 1:
+		return RuleMapped
+	case outLen == 1:
+		return RuleCombined
+	case inLen == 1:
+		return RuleExpanded
+	default:
+		return RuleRewritten
+	}
+}
+
+// splitWords splits text into runs of non-space characters and single whitespace runes.
+func splitWords(text string) []string {
+	var words []string
+	start := 0
+	for i, r := range text {
+		if !unicode.IsSpace(r) {
+			continue
+		}
+		if start < i {
+			words = append(words, text[start:i])
+		}
+		words = append(words, string(r))
+		start = i + len(string(r))
+	}
+	if start < len(text) {
+		words = append(words, text[start:])
+	}
+	return words
+}
+
+// commonPrefix returns the number of leading runes a and b share.
+func commonPrefix(a, b []rune) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
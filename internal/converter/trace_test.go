@@ -0,0 +1,105 @@
+package converter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// shoutConverter upper-cases the whole text when it ends in "!", so it does not work word by word.
+type shoutConverter struct{}
+
+func (shoutConverter) ToUnicode(text string) string {
+	if strings.HasSuffix(text, "!") {
+		return strings.ToUpper(text)
+	}
+	return text
+}
+
+func TestTrace(t *testing.T) {
+	tests := []struct {
+		name string
+		conv Converter
+		text string
+		want []Mapping // changed mappings only
+	}{
+		{
+			name: "VNI tone marks combine with their vowel",
+			conv: NewVNIConverter(),
+			text: "Ba\u00F9o ca\u00F9o",
+			want: []Mapping{
+				{Input: "a\u00F9", Output: "á", Rule: RuleCombined, InputOffset: 1, OutputOffset: 1},
+				{Input: "a\u00F9", Output: "á", Rule: RuleCombined, InputOffset: 6, OutputOffset: 5},
+			},
+		},
+		{
+			name: "VNI horn and stacked tone",
+			conv: NewVNIConverter(),
+			text: "\u00D1\u00D6\u00D4\u00D8NG",
+			want: []Mapping{
+				{Input: "\u00D1", Output: "Đ", Rule: RuleMapped, InputOffset: 0, OutputOffset: 0},
+				{Input: "\u00D6", Output: "Ư", Rule: RuleMapped, InputOffset: 1, OutputOffset: 1},
+				{Input: "\u00D4\u00D8", Output: "Ờ", Rule: RuleCombined, InputOffset: 2, OutputOffset: 2},
+			},
+		},
+		{
+			name: "VNI guess",
+			conv: NewVNIConverter(),
+			text: "Vi\u00D6t",
+			want: []Mapping{{
+				Input: "\u00D6", Output: "ệ", Rule: RuleMapped, InputOffset: 2, OutputOffset: 2,
+				Guess: &Guess{InputOffset: 2, Input: "\u00D6", Output: "ệ", Alternative: "Ư", Reason: "after a vowel"},
+			}},
+		},
+		{
+			name: "TCVN3 single character",
+			conv: NewTCVN3Converter(),
+			text: "C\u00ABng ty",
+			want: []Mapping{{Input: "\u00AB", Output: "ô", Rule: RuleMapped, InputOffset: 1, OutputOffset: 1}},
+		},
+		{
+			name: "VIQR marks and escapes",
+			conv: NewVIQRConverter(),
+			text: `Vie^.t dda^u \.`,
+			want: []Mapping{
+				{Input: "e^.", Output: "ệ", Rule: RuleCombined, InputOffset: 2, OutputOffset: 2},
+				{Input: "dd", Output: "đ", Rule: RuleCombined, InputOffset: 7, OutputOffset: 5},
+				{Input: "a^", Output: "â", Rule: RuleCombined, InputOffset: 9, OutputOffset: 6},
+				{Input: `\.`, Output: ".", Rule: RuleCombined, InputOffset: 13, OutputOffset: 9},
+			},
+		},
+		{
+			name: "Plain text has no changes",
+			conv: NewVNIConverter(),
+			text: "Hello world",
+		},
+		{
+			name: "Converter that is not word by word",
+			conv: shoutConverter{},
+			text: "hi there!",
+			want: []Mapping{{Input: "hi there!", Output: "HI THERE!", Rule: RuleUnaligned}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mappings := Trace(tt.conv, tt.text)
+
+			var input, output strings.Builder
+			var changed []Mapping
+			for _, m := range mappings {
+				input.WriteString(m.Input)
+				output.WriteString(m.Output)
+				if m.Changed() {
+					changed = append(changed, m)
+				}
+			}
+			if input.String() != tt.text || output.String() != tt.conv.ToUnicode(tt.text) {
+				t.Errorf("mappings join to %q -> %q, want %q -> %q", input.String(), output.String(), tt.text, tt.conv.ToUnicode(tt.text))
+			}
+			if !reflect.DeepEqual(changed, tt.want) {
+				t.Errorf("changed mappings = %+v, want %+v", changed, tt.want)
+			}
+		})
+	}
+}
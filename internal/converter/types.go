@@ -1,6 +1,8 @@
 // Package converter provides functions to convert legacy Vietnamese encodings to Unicode.
 package converter
 
+//go:generate go run ../../scripts/gen_charset_tests -tables ../../scripts/gen_charset_tests/tables -out .
+
 // EncodingType represents the source font encoding.
 // Why: Using a typed string constant ensures type safety and prevents magic strings.
 type EncodingType string
@@ -10,6 +12,14 @@ const (
 	EncodingVNI EncodingType = "VNI"
 	// EncodingTCVN3 represents TCVN3 (ABC) encoding
 	EncodingTCVN3 EncodingType = "TCVN3"
+	// EncodingVNIDOS represents VNI for DOS encoding (dBase/FoxPro exports)
+	EncodingVNIDOS EncodingType = "VNI-DOS"
+	// EncodingVNU represents VNU (3-byte) encoding
+	EncodingVNU EncodingType = "VNU"
+	// EncodingVISCII represents VISCII (RFC 1456), one byte per letter
+	EncodingVISCII EncodingType = "VISCII"
+	// EncodingVIQR represents VIQR mnemonic notation (RFC 1456)
+	EncodingVIQR EncodingType = "VIQR"
 	// EncodingAuto represents automatic encoding detection
 	EncodingAuto EncodingType = "AUTO"
 	// EncodingUnknown represents an unknown encoding
@@ -22,3 +32,9 @@ type Converter interface {
 	// ToUnicode converts the given legacy encoded string to a Unicode string.
 	ToUnicode(text string) string
 }
+
+// UppercaseConverter is implemented by converters whose legacy fonts have capital-only variants.
+type UppercaseConverter interface {
+	// ToUnicodeUpper converts text written in the capital-only font variant.
+	ToUnicodeUpper(text string) string
+}
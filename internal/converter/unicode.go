@@ -0,0 +1,53 @@
+package converter
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// vietnameseLatin1 are the Vietnamese letters that Latin-1 (and so Windows-1252) also has.
+// Why: Legacy text read as Windows-1252 is full of them (ô is both a VNI and a TCVN3 marker),
+// so on their own they do not tell Unicode apart from legacy text.
+const vietnameseLatin1 = "ÀÁÂÃÈÉÊÌÍÒÓÔÕÙÚÝàáâãèéêìíòóôõùúý"
+
+// isVietnameseOnly reports whether r is a precomposed Vietnamese letter outside Windows-1252:
+// ă, đ, ĩ, ũ, ơ, ư (and capitals) or a letter of the Latin Extended Additional block.
+func isVietnameseOnly(r rune) bool {
+	switch r {
+	case 'Ă', 'ă', 'Đ', 'đ', 'Ĩ', 'ĩ', 'Ũ', 'ũ', 'Ơ', 'ơ', 'Ư', 'ư':
+		return true
+	}
+	return r >= '\u1EA0' && r <= '\u1EF9'
+}
+
+// IndexPrecomposedVietnamese returns the byte index of the first letter that only
+// precomposed Vietnamese Unicode uses (such as ạ, ư or đ), or -1 when text is not
+// precomposed Vietnamese Unicode. Text containing such a letter still returns -1 when it
+// also holds Latin-1 characters Vietnamese does not use, or combining marks, since those
+// come from legacy encodings (VNI, TCVN3, VNU) mixed into the cell.
+// Why: No legacy encoding read as Windows-1252 produces these letters, so text that has
+// them needs no conversion, and converting it anyway would garble it.
+func IndexPrecomposedVietnamese(text string) int {
+	index := -1
+	for i, r := range text {
+		switch {
+		case r < utf8.RuneSelf, r == '\u00A0':
+			// ASCII and no-break spaces appear in any encoding
+		case isVietnameseOnly(r):
+			if index < 0 {
+				index = i
+			}
+		case r >= '\u0080' && r <= '\u00FF' && !strings.ContainsRune(vietnameseLatin1, r):
+			return -1
+		case r >= '\u0300' && r <= '\u036F':
+			return -1
+		}
+	}
+	return index
+}
+
+// IsPrecomposedVietnamese reports whether text is already precomposed Vietnamese Unicode
+// (see IndexPrecomposedVietnamese).
+func IsPrecomposedVietnamese(text string) bool {
+	return IndexPrecomposedVietnamese(text) >= 0
+}
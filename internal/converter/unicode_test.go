@@ -0,0 +1,33 @@
+package converter
+
+import "testing"
+
+func TestIndexPrecomposedVietnamese(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{name: "Vietnamese sentence", text: "Công ty Việt Nam", want: 11},
+		{name: "Only breve", text: "ăn", want: 0},
+		{name: "Capital d-stroke", text: "Đà Nẵng", want: 0},
+		{name: "Latin-1 vowels alone", text: "Công ty", want: -1},
+		{name: "ASCII", text: "Hello", want: -1},
+		{name: "No-break space", text: "Hà\u00A0Nội", want: 6},
+		{name: "Quotes outside Latin-1", text: "“Việt”", want: 5},
+		{name: "VNI mixed in", text: "Việt Vi\u00D6t", want: -1},
+		{name: "TCVN3 mixed in", text: "Việt C\u00ABng", want: -1},
+		{name: "Decomposed marks", text: "Việt ưo\u031B", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IndexPrecomposedVietnamese(tt.text); got != tt.want {
+				t.Errorf("IndexPrecomposedVietnamese(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+			if got := IsPrecomposedVietnamese(tt.text); got != (tt.want >= 0) {
+				t.Errorf("IsPrecomposedVietnamese(%q) = %v", tt.text, got)
+			}
+		})
+	}
+}
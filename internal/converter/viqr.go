@@ -0,0 +1,102 @@
+package converter
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// VIQR (RFC 1456) writes Vietnamese in plain ASCII: a vowel is followed by an
+// optional shape mark and an optional tone mark, e.g. "Vie^.t Nam" for "Việt Nam".
+// Why: Like VNU, each mark maps to a Unicode combining character and NFC
+// composition builds the precomposed letter.
+//
+// Marks always apply after a vowel, as specified by RFC 1456; a backslash keeps
+// the next mark character literal (e.g. "Ha\." for a period after "Ha").
+
+// viqrShapeMarks maps a shape mark to its combining character and the vowels it applies to.
+var viqrShapeMarks = map[rune]struct {
+	mark   rune
+	vowels string
+}{
+	'(': {mark: '̆', vowels: "aA"},     // breve: ă
+	'^': {mark: '̂', vowels: "aAeEoO"}, // circumflex: â ê ô
+	'+': {mark: '̛', vowels: "oOuU"},   // horn: ơ ư
+	'*': {mark: '̛', vowels: "oOuU"},   // horn (alternative notation)
+}
+
+// viqrToneMarks maps VIQR tone marks to Unicode combining characters.
+var viqrToneMarks = map[rune]rune{
+	'\'': '́', // acute
+	'`':  '̀', // grave
+	'?':  '̉', // hook
+	'~':  '̃', // tilde
+	'.':  '̣', // dot below
+}
+
+const viqrVowels = "aeiouyAEIOUY"
+
+// VIQRConverter handles conversion from VIQR mnemonic notation to Unicode.
+type VIQRConverter struct{}
+
+// NewVIQRConverter creates a new instance.
+func NewVIQRConverter() *VIQRConverter {
+	return &VIQRConverter{}
+}
+
+// ToUnicode converts VIQR text to Unicode.
+func (c *VIQRConverter) ToUnicode(text string) string {
+	rs := []rune(text)
+	var b strings.Builder
+	b.Grow(len(text))
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		next := rune(0)
+		if i+1 < len(rs) {
+			next = rs[i+1]
+		}
+
+		switch {
+		case r == '\\' && isVIQRMark(next):
+			// Escaped mark is kept literally
+			b.WriteRune(next)
+			i++
+			continue
+		case (r == 'd' || r == 'D') && (next == 'd' || next == 'D'):
+			if r == 'D' {
+				b.WriteRune('Đ')
+			} else {
+				b.WriteRune('đ')
+			}
+			i++
+			continue
+		}
+
+		b.WriteRune(r)
+		if !strings.ContainsRune(viqrVowels, r) {
+			continue
+		}
+		if shape, ok := viqrShapeMarks[next]; ok && strings.ContainsRune(shape.vowels, r) {
+			b.WriteRune(shape.mark)
+			i++
+			next = 0
+			if i+1 < len(rs) {
+				next = rs[i+1]
+			}
+		}
+		if tone, ok := viqrToneMarks[next]; ok {
+			b.WriteRune(tone)
+			i++
+		}
+	}
+	return norm.NFC.String(b.String())
+}
+
+// isVIQRMark reports whether r is a VIQR shape or tone mark.
+func isVIQRMark(r rune) bool {
+	if _, ok := viqrShapeMarks[r]; ok {
+		return true
+	}
+	_, ok := viqrToneMarks[r]
+	return ok
+}
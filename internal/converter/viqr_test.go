@@ -0,0 +1,30 @@
+package converter
+
+import "testing"
+
+func TestVIQRConverter_ToUnicode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Dot below under circumflex", input: "Vie^.t Nam", want: "Việt Nam"},
+		{name: "D stroke", input: "DDa` Na(~ng dda^u", want: "Đà Nẵng đâu"},
+		{name: "Horn and breve", input: "Tru+o+`ng ho.c ca(n", want: "Trường học căn"},
+		{name: "Alternative horn", input: "tu*o*i", want: "tươi"},
+		{name: "Hook and acute", input: "Ho?i Chi'", want: "Hỏi Chí"},
+		{name: "Uppercase", input: "VIE^.T", want: "VIỆT"},
+		{name: "Escaped punctuation", input: "Ha\\. No^.i\\?", want: "Ha. Nội?"},
+		{name: "Shape mark not valid for vowel", input: "i^", want: "i^"},
+		{name: "Marks after consonants kept", input: "5+3. n'", want: "5+3. n'"},
+		{name: "Plain ASCII", input: "xyz 123", want: "xyz 123"},
+	}
+	c := NewVIQRConverter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.ToUnicode(tt.input); got != tt.want {
+				t.Errorf("ToUnicode(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
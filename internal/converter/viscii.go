@@ -0,0 +1,71 @@
+package converter
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// visciiTable maps each VISCII byte (RFC 1456) holding a Vietnamese letter to that letter.
+// Why: VISCII gives all 134 letters a byte of their own, so decoding is a byte lookup;
+// the capitals that did not fit in 0x80-0xFF take six of the C0 control codes.
+var visciiTable = map[byte]rune{
+	0x02: 'Ẳ', 0x05: 'Ẵ', 0x06: 'Ẫ', 0x14: 'Ỷ', 0x19: 'Ỹ', 0x1E: 'Ỵ',
+
+	0x80: 'Ạ', 0x81: 'Ắ', 0x82: 'Ằ', 0x83: 'Ặ', 0x84: 'Ấ', 0x85: 'Ầ', 0x86: 'Ẩ', 0x87: 'Ậ',
+	0x88: 'Ẽ', 0x89: 'Ẹ', 0x8A: 'Ế', 0x8B: 'Ề', 0x8C: 'Ể', 0x8D: 'Ễ', 0x8E: 'Ệ', 0x8F: 'Ố',
+	0x90: 'Ồ', 0x91: 'Ổ', 0x92: 'Ỗ', 0x93: 'Ộ', 0x94: 'Ợ', 0x95: 'Ớ', 0x96: 'Ờ', 0x97: 'Ở',
+	0x98: 'Ị', 0x99: 'Ỏ', 0x9A: 'Ọ', 0x9B: 'Ỉ', 0x9C: 'Ủ', 0x9D: 'Ũ', 0x9E: 'Ụ', 0x9F: 'Ỳ',
+	0xA0: 'Õ', 0xA1: 'ắ', 0xA2: 'ằ', 0xA3: 'ặ', 0xA4: 'ấ', 0xA5: 'ầ', 0xA6: 'ẩ', 0xA7: 'ậ',
+	0xA8: 'ẽ', 0xA9: 'ẹ', 0xAA: 'ế', 0xAB: 'ề', 0xAC: 'ể', 0xAD: 'ễ', 0xAE: 'ệ', 0xAF: 'ố',
+	0xB0: 'ồ', 0xB1: 'ổ', 0xB2: 'ỗ', 0xB3: 'Ỡ', 0xB4: 'Ơ', 0xB5: 'ộ', 0xB6: 'ờ', 0xB7: 'ở',
+	0xB8: 'ị', 0xB9: 'Ự', 0xBA: 'Ứ', 0xBB: 'Ừ', 0xBC: 'Ử', 0xBD: 'ơ', 0xBE: 'ớ', 0xBF: 'Ư',
+	0xC0: 'À', 0xC1: 'Á', 0xC2: 'Â', 0xC3: 'Ã', 0xC4: 'Ả', 0xC5: 'Ă', 0xC6: 'ẳ', 0xC7: 'ẵ',
+	0xC8: 'È', 0xC9: 'É', 0xCA: 'Ê', 0xCB: 'Ẻ', 0xCC: 'Ì', 0xCD: 'Í', 0xCE: 'Ĩ', 0xCF: 'ỳ',
+	0xD0: 'Đ', 0xD1: 'ứ', 0xD2: 'Ò', 0xD3: 'Ó', 0xD4: 'Ô', 0xD5: 'ạ', 0xD6: 'ỷ', 0xD7: 'ừ',
+	0xD8: 'ử', 0xD9: 'Ù', 0xDA: 'Ú', 0xDB: 'ỹ', 0xDC: 'ỵ', 0xDD: 'Ý', 0xDE: 'ỡ', 0xDF: 'ư',
+	0xE0: 'à', 0xE1: 'á', 0xE2: 'â', 0xE3: 'ã', 0xE4: 'ả', 0xE5: 'ă', 0xE6: 'ữ', 0xE7: 'ẫ',
+	0xE8: 'è', 0xE9: 'é', 0xEA: 'ê', 0xEB: 'ẻ', 0xEC: 'ì', 0xED: 'í', 0xEE: 'ĩ', 0xEF: 'ỉ',
+	0xF0: 'đ', 0xF1: 'ự', 0xF2: 'ò', 0xF3: 'ó', 0xF4: 'ô', 0xF5: 'õ', 0xF6: 'ỏ', 0xF7: 'ọ',
+	0xF8: 'ụ', 0xF9: 'ù', 0xFA: 'ú', 0xFB: 'ũ', 0xFC: 'ủ', 0xFD: 'ý', 0xFE: 'ợ', 0xFF: 'Ữ',
+}
+
+// visciiPairs is visciiTable as old/new pairs of the text Excel shows for each byte
+// (Windows-1252) and its letter. Bytes Windows-1252 already reads as the letter are left out.
+// Why: Shared by the decoder (replacer) and the reverse encoder, like tcvn3Pairs.
+var visciiPairs = func() []string {
+	pairs := make([]string, 0, 2*len(visciiTable))
+	for b := 0; b < 256; b++ {
+		letter, ok := visciiTable[byte(b)]
+		legacy := windows1252Rune(byte(b))
+		if ok && legacy != letter {
+			pairs = append(pairs, string(legacy), string(letter))
+		}
+	}
+	return pairs
+}()
+
+// windows1252Rune returns the character Windows reads b as in code page 1252. The five
+// bytes the code page leaves undefined read as the C1 control of the same value.
+func windows1252Rune(b byte) rune {
+	if r := charmap.Windows1252.DecodeByte(b); r != utf8.RuneError {
+		return r
+	}
+	return rune(b)
+}
+
+// VISCIIConverter handles conversion from VISCII to Unicode.
+type VISCIIConverter struct {
+	replacer *strings.Replacer
+}
+
+// NewVISCIIConverter creates a new instance.
+func NewVISCIIConverter() *VISCIIConverter {
+	return &VISCIIConverter{replacer: strings.NewReplacer(visciiPairs...)}
+}
+
+// ToUnicode converts VISCII text, as Excel shows it, to Unicode.
+func (c *VISCIIConverter) ToUnicode(text string) string {
+	return c.replacer.Replace(text)
+}
@@ -0,0 +1,162 @@
+//go:build charsets
+
+// Code generated by scripts/gen_charset_tests from ../../scripts/gen_charset_tests/tables/viscii.txt; DO NOT EDIT.
+
+package converter
+
+import "testing"
+
+func TestVISCIITable(t *testing.T) {
+	c, err := NewConverter(EncodingVISCII)
+	if err != nil {
+		t.Fatalf("NewConverter failed: %v", err)
+	}
+
+	tests := []struct {
+		legacy string
+		want   string
+	}{
+		{legacy: "\u0002", want: "Ẳ"},
+		{legacy: "\u0005", want: "Ẵ"},
+		{legacy: "\u0006", want: "Ẫ"},
+		{legacy: "\u0014", want: "Ỷ"},
+		{legacy: "\u0019", want: "Ỹ"},
+		{legacy: "\u001E", want: "Ỵ"},
+		{legacy: "\u20AC", want: "Ạ"},
+		{legacy: "\u0081", want: "Ắ"},
+		{legacy: "\u201A", want: "Ằ"},
+		{legacy: "\u0192", want: "Ặ"},
+		{legacy: "\u201E", want: "Ấ"},
+		{legacy: "\u2026", want: "Ầ"},
+		{legacy: "\u2020", want: "Ẩ"},
+		{legacy: "\u2021", want: "Ậ"},
+		{legacy: "\u02C6", want: "Ẽ"},
+		{legacy: "\u2030", want: "Ẹ"},
+		{legacy: "\u0160", want: "Ế"},
+		{legacy: "\u2039", want: "Ề"},
+		{legacy: "\u0152", want: "Ể"},
+		{legacy: "\u008D", want: "Ễ"},
+		{legacy: "\u017D", want: "Ệ"},
+		{legacy: "\u008F", want: "Ố"},
+		{legacy: "\u0090", want: "Ồ"},
+		{legacy: "\u2018", want: "Ổ"},
+		{legacy: "\u2019", want: "Ỗ"},
+		{legacy: "\u201C", want: "Ộ"},
+		{legacy: "\u201D", want: "Ợ"},
+		{legacy: "\u2022", want: "Ớ"},
+		{legacy: "\u2013", want: "Ờ"},
+		{legacy: "\u2014", want: "Ở"},
+		{legacy: "\u02DC", want: "Ị"},
+		{legacy: "\u2122", want: "Ỏ"},
+		{legacy: "\u0161", want: "Ọ"},
+		{legacy: "\u203A", want: "Ỉ"},
+		{legacy: "\u0153", want: "Ủ"},
+		{legacy: "\u009D", want: "Ũ"},
+		{legacy: "\u017E", want: "Ụ"},
+		{legacy: "\u0178", want: "Ỳ"},
+		{legacy: "\u00A0", want: "Õ"},
+		{legacy: "\u00A1", want: "ắ"},
+		{legacy: "\u00A2", want: "ằ"},
+		{legacy: "\u00A3", want: "ặ"},
+		{legacy: "\u00A4", want: "ấ"},
+		{legacy: "\u00A5", want: "ầ"},
+		{legacy: "\u00A6", want: "ẩ"},
+		{legacy: "\u00A7", want: "ậ"},
+		{legacy: "\u00A8", want: "ẽ"},
+		{legacy: "\u00A9", want: "ẹ"},
+		{legacy: "\u00AA", want: "ế"},
+		{legacy: "\u00AB", want: "ề"},
+		{legacy: "\u00AC", want: "ể"},
+		{legacy: "\u00AD", want: "ễ"},
+		{legacy: "\u00AE", want: "ệ"},
+		{legacy: "\u00AF", want: "ố"},
+		{legacy: "\u00B0", want: "ồ"},
+		{legacy: "\u00B1", want: "ổ"},
+		{legacy: "\u00B2", want: "ỗ"},
+		{legacy: "\u00B3", want: "Ỡ"},
+		{legacy: "\u00B4", want: "Ơ"},
+		{legacy: "\u00B5", want: "ộ"},
+		{legacy: "\u00B6", want: "ờ"},
+		{legacy: "\u00B7", want: "ở"},
+		{legacy: "\u00B8", want: "ị"},
+		{legacy: "\u00B9", want: "Ự"},
+		{legacy: "\u00BA", want: "Ứ"},
+		{legacy: "\u00BB", want: "Ừ"},
+		{legacy: "\u00BC", want: "Ử"},
+		{legacy: "\u00BD", want: "ơ"},
+		{legacy: "\u00BE", want: "ớ"},
+		{legacy: "\u00BF", want: "Ư"},
+		{legacy: "\u00C0", want: "À"},
+		{legacy: "\u00C1", want: "Á"},
+		{legacy: "\u00C2", want: "Â"},
+		{legacy: "\u00C3", want: "Ã"},
+		{legacy: "\u00C4", want: "Ả"},
+		{legacy: "\u00C5", want: "Ă"},
+		{legacy: "\u00C6", want: "ẳ"},
+		{legacy: "\u00C7", want: "ẵ"},
+		{legacy: "\u00C8", want: "È"},
+		{legacy: "\u00C9", want: "É"},
+		{legacy: "\u00CA", want: "Ê"},
+		{legacy: "\u00CB", want: "Ẻ"},
+		{legacy: "\u00CC", want: "Ì"},
+		{legacy: "\u00CD", want: "Í"},
+		{legacy: "\u00CE", want: "Ĩ"},
+		{legacy: "\u00CF", want: "ỳ"},
+		{legacy: "\u00D0", want: "Đ"},
+		{legacy: "\u00D1", want: "ứ"},
+		{legacy: "\u00D2", want: "Ò"},
+		{legacy: "\u00D3", want: "Ó"},
+		{legacy: "\u00D4", want: "Ô"},
+		{legacy: "\u00D5", want: "ạ"},
+		{legacy: "\u00D6", want: "ỷ"},
+		{legacy: "\u00D7", want: "ừ"},
+		{legacy: "\u00D8", want: "ử"},
+		{legacy: "\u00D9", want: "Ù"},
+		{legacy: "\u00DA", want: "Ú"},
+		{legacy: "\u00DB", want: "ỹ"},
+		{legacy: "\u00DC", want: "ỵ"},
+		{legacy: "\u00DD", want: "Ý"},
+		{legacy: "\u00DE", want: "ỡ"},
+		{legacy: "\u00DF", want: "ư"},
+		{legacy: "\u00E0", want: "à"},
+		{legacy: "\u00E1", want: "á"},
+		{legacy: "\u00E2", want: "â"},
+		{legacy: "\u00E3", want: "ã"},
+		{legacy: "\u00E4", want: "ả"},
+		{legacy: "\u00E5", want: "ă"},
+		{legacy: "\u00E6", want: "ữ"},
+		{legacy: "\u00E7", want: "ẫ"},
+		{legacy: "\u00E8", want: "è"},
+		{legacy: "\u00E9", want: "é"},
+		{legacy: "\u00EA", want: "ê"},
+		{legacy: "\u00EB", want: "ẻ"},
+		{legacy: "\u00EC", want: "ì"},
+		{legacy: "\u00ED", want: "í"},
+		{legacy: "\u00EE", want: "ĩ"},
+		{legacy: "\u00EF", want: "ỉ"},
+		{legacy: "\u00F0", want: "đ"},
+		{legacy: "\u00F1", want: "ự"},
+		{legacy: "\u00F2", want: "ò"},
+		{legacy: "\u00F3", want: "ó"},
+		{legacy: "\u00F4", want: "ô"},
+		{legacy: "\u00F5", want: "õ"},
+		{legacy: "\u00F6", want: "ỏ"},
+		{legacy: "\u00F7", want: "ọ"},
+		{legacy: "\u00F8", want: "ụ"},
+		{legacy: "\u00F9", want: "ù"},
+		{legacy: "\u00FA", want: "ú"},
+		{legacy: "\u00FB", want: "ũ"},
+		{legacy: "\u00FC", want: "ủ"},
+		{legacy: "\u00FD", want: "ý"},
+		{legacy: "\u00FE", want: "ợ"},
+		{legacy: "\u00FF", want: "Ữ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := c.ToUnicode(tt.legacy); got != tt.want {
+				t.Errorf("ToUnicode(%q) = %q, want %q", tt.legacy, got, tt.want)
+			}
+		})
+	}
+}
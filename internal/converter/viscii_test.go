@@ -0,0 +1,41 @@
+package converter
+
+import (
+	"testing"
+)
+
+func TestVISCIIConverter_ToUnicode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{name: "Sentence", input: []byte("Vi\xAEt Nam"), expected: "Việt Nam"},
+		{name: "Capitals in control codes", input: []byte("\x02\x05\x1E"), expected: "ẲẴỴ"},
+		{name: "Bytes Windows-1252 leaves undefined", input: []byte("\x81\x8D\x8F\x90\x9D"), expected: "ẮỄỐỒŨ"},
+		{name: "Letters shared with Latin-1", input: []byte("\xE0 \xC1 \xF4"), expected: "à Á ô"},
+		{name: "Plain text", input: []byte("Hello World"), expected: "Hello World"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertBytes(EncodingVISCII, tt.input)
+			if err != nil {
+				t.Fatalf("ConvertBytes() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("ConvertBytes() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestVISCIIEncoder_RoundTrip(t *testing.T) {
+	enc := NewVISCIIEncoder()
+	dec := NewVISCIIConverter()
+	for _, text := range []string{"Đặng Thị Hằng", "NGƯỜI VIỆT", "Ẳng Ỵ"} {
+		if got := dec.ToUnicode(enc.FromUnicode(text)); got != text {
+			t.Errorf("round trip of %q = %q", text, got)
+		}
+	}
+}
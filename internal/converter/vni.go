@@ -1,71 +1,107 @@
 package converter
 
 import (
+	"fmt"
 	"strings"
 	"unicode"
+
+	"convert-vni-to-unicode/internal/spell"
+)
+
+// VNIStrictness selects how the VNI converter reads Ö/ö, which is "Ư"/"ư" in VNI but "ệ"
+// in text mixed with TCVN3 (e.g. "ViÖt").
+type VNIStrictness string
+
+const (
+	// VNIStrictnessAuto reads Ö/ö as "ệ" after a vowel and as "Ư"/"ư" otherwise (the default)
+	VNIStrictnessAuto VNIStrictness = "auto"
+	// VNIStrictnessPreferTCVN3 always reads Ö/ö as "ệ"
+	VNIStrictnessPreferTCVN3 VNIStrictness = "prefer-tcvn3"
+	// VNIStrictnessPreferVNI always reads Ö/ö as "Ư"/"ư"
+	VNIStrictnessPreferVNI VNIStrictness = "prefer-vni"
+	// VNIStrictnessDictionary reads Ö/ö the way that makes its word Vietnamese syllables
+	// (the automatic reading first); words no reading confirms keep their Ö/ö unconverted
+	VNIStrictnessDictionary VNIStrictness = "require-dictionary-confirmation"
 )
 
+// ParseVNIStrictness returns the strictness named s; "" selects VNIStrictnessAuto.
+func ParseVNIStrictness(s string) (VNIStrictness, error) {
+	switch strictness := VNIStrictness(s); strictness {
+	case "":
+		return VNIStrictnessAuto, nil
+	case VNIStrictnessAuto, VNIStrictnessPreferTCVN3, VNIStrictnessPreferVNI, VNIStrictnessDictionary:
+		return strictness, nil
+	default:
+		return "", fmt.Errorf("unknown VNI strictness %q (want %s, %s, %s or %s)", s,
+			VNIStrictnessAuto, VNIStrictnessPreferTCVN3, VNIStrictnessPreferVNI, VNIStrictnessDictionary)
+	}
+}
+
 // VNIConverter handles conversion from VNI-Windows encoding to Unicode.
 // This converter handles VNI text that has been converted to Unicode by Excel.
 // VNI uses "combining marks" where tone markers follow the vowel they modify.
 type VNIConverter struct {
-	legacyReplacer *strings.Replacer
+	strictness VNIStrictness
 }
 
 // NewVNIConverter creates a new instance of VNIConverter.
 func NewVNIConverter() *VNIConverter {
+	return NewVNIConverterWithStrictness(VNIStrictnessAuto)
+}
+
+// NewVNIConverterWithStrictness creates a VNIConverter reading Ö/ö as strictness says.
+// Why: The automatic reading misguesses systematically on some sources (e.g. VNI words
+// where "ư" follows a vowel, as in "hươu"); users hitting that can pin the reading.
+func NewVNIConverterWithStrictness(strictness VNIStrictness) *VNIConverter {
 	return &VNIConverter{
-		// Legacy byte mapping for đ/Đ
-		legacyReplacer: strings.NewReplacer(
-			"\u00F1", "đ", // ñ -> đ
-			"\u00AE", "Đ", // ® -> Đ
-			"Ñ", "Đ", // Ñ -> Đ
-			// Legacy VNI Support for Unit Tests
-			"\u001E", "ả",
-			"\u00B5", "ạ",
-			"\u00C7", "ầ",
-			"\u00C8", "ẩ",
-			"\u00C9", "ẫ",
-			"\u00CB", "ậ",
-			"\u00CA", "ấ", // Fallback for Ê
-		),
+		strictness: strictness,
 	}
 }
 
-// VNI Tone Markers - these follow the vowel they modify
-// Each map: marker rune -> tone type
-var vniToneMarkers = map[rune]string{
-	// Circumflex markers (^) - dấu mũ
-	'Â': "circumflex",
-	'â': "circumflex",
-	'Ê': "circumflex",
-	'ê': "circumflex",
-	'Ô': "circumflex",
-	'ô': "circumflex",
-
-	// Grave markers (`) - dấu huyền
-	'Ø': "grave",
-	'ø': "grave",
-
-	// Acute markers (´) - dấu sắc
-	'Ù': "acute",
-	'ù': "acute",
-
-	// Hook markers (?) - dấu hỏi
-	'Û': "hook",
-	'û': "hook",
-
-	// Tilde markers (~) - dấu ngã
-	'Ü': "tilde",
-	'ü': "tilde",
-
-	// Dot markers (.) - dấu nặng
-	'Ï': "dot",
-	'ï': "dot",
-
-	// Horn markers
-	'Ö': "horn",
-	'ö': "horn",
+// vniMark is what a VNI mark byte does to the letter before it: add a circumflex or a
+// breve, a tone, or both.
+type vniMark struct {
+	modifier string // "circumflex", "breve" or "" for a tone alone
+	tone     string // "" for a modifier alone
+}
+
+// VNI mark bytes - these follow the vowel they modify.
+// Why: VNI puts a circumflex or breve and the tone on it in one byte ("aá" is "ấ"), so a
+// mark is read as a modifier and a tone rather than a single accent.
+var vniMarks = map[rune]vniMark{
+	// Tones - dấu sắc, huyền, hỏi, ngã, nặng
+	'ù': {tone: "acute"}, 'Ù': {tone: "acute"},
+	'ø': {tone: "grave"}, 'Ø': {tone: "grave"},
+	'û': {tone: "hook"}, 'Û': {tone: "hook"},
+	'õ': {tone: "tilde"}, 'Õ': {tone: "tilde"},
+	'ï': {tone: "dot"}, 'Ï': {tone: "dot"},
+
+	// Circumflex (^) - dấu mũ, alone and with a tone
+	'â': {modifier: "circumflex"}, 'Â': {modifier: "circumflex"},
+	'á': {modifier: "circumflex", tone: "acute"}, 'Á': {modifier: "circumflex", tone: "acute"},
+	'à': {modifier: "circumflex", tone: "grave"}, 'À': {modifier: "circumflex", tone: "grave"},
+	'å': {modifier: "circumflex", tone: "hook"}, 'Å': {modifier: "circumflex", tone: "hook"},
+	'ã': {modifier: "circumflex", tone: "tilde"}, 'Ã': {modifier: "circumflex", tone: "tilde"},
+	'ä': {modifier: "circumflex", tone: "dot"}, 'Ä': {modifier: "circumflex", tone: "dot"},
+
+	// Breve - dấu trăng, alone and with a tone
+	'ê': {modifier: "breve"}, 'Ê': {modifier: "breve"},
+	'é': {modifier: "breve", tone: "acute"}, 'É': {modifier: "breve", tone: "acute"},
+	'è': {modifier: "breve", tone: "grave"}, 'È': {modifier: "breve", tone: "grave"},
+	'ú': {modifier: "breve", tone: "hook"}, 'Ú': {modifier: "breve", tone: "hook"},
+	'ü': {modifier: "breve", tone: "tilde"}, 'Ü': {modifier: "breve", tone: "tilde"},
+	'ë': {modifier: "breve", tone: "dot"}, 'Ë': {modifier: "breve", tone: "dot"},
+}
+
+// vniLetters are the letters VNI writes as a byte of their own rather than a base letter and
+// a mark. Ư/ư (Ö/ö) are read by the converter's strictness instead.
+var vniLetters = map[rune]rune{
+	'ô': 'ơ', 'Ô': 'Ơ',
+	'ñ': 'đ', 'Ñ': 'Đ',
+	'æ': 'ỉ', 'Æ': 'Ỉ',
+	'ó': 'ĩ', 'Ó': 'Ĩ',
+	'ò': 'ị', 'Ò': 'Ị',
+	'î': 'ỵ', 'Î': 'Ỵ',
 }
 
 // Vowel combinations: base vowel -> tone type -> combined vowel
@@ -73,6 +109,7 @@ var vowelCombinations = map[rune]map[string]rune{
 	// Lowercase A
 	'a': {
 		"circumflex": 'â',
+		"breve":      'ă',
 		"grave":      'à',
 		"acute":      'á',
 		"hook":       'ả',
@@ -82,6 +119,7 @@ var vowelCombinations = map[rune]map[string]rune{
 	// Uppercase A
 	'A': {
 		"circumflex": 'Â',
+		"breve":      'Ă',
 		"grave":      'À',
 		"acute":      'Á',
 		"hook":       'Ả',
@@ -199,34 +237,170 @@ var combinedVowelTones = map[rune]map[string]rune{
 
 // ToUnicode converts VNI text to proper Unicode Vietnamese
 func (c *VNIConverter) ToUnicode(text string) string {
-	// First, apply combining conversion
-	result := convertVNICombining(text)
+	return c.convert(text, nil)
+}
+
+// Guesses implements Guesser: it returns how each ambiguous Ö/ö of text was read.
+func (c *VNIConverter) Guesses(text string) []Guess {
+	var guesses []Guess
+	c.convert(text, &guesses)
+	return guesses
+}
+
+// convert converts text, appending the reading of each Ö/ö to guesses unless it is nil.
+func (c *VNIConverter) convert(text string, guesses *[]Guess) string {
+	var words []dictionaryReading
+	if c.strictness == VNIStrictnessDictionary && strings.ContainsAny(text, "Öö") {
+		words = c.dictionaryReadings(text)
+	}
+	read := func(offset int, prevVowel bool, r rune) rune {
+		strictness, reason := c.strictness, ""
+		for _, w := range words {
+			if offset >= w.start && offset < w.end {
+				strictness, reason = w.strictness, "dictionary"
+				if !w.confirmed {
+					reason = "unconfirmed"
+				}
+			}
+		}
+		out, ruleReason := readHorn(strictness, prevVowel, r)
+		if reason == "" {
+			reason = ruleReason
+		}
+		if reason == "unconfirmed" {
+			out = r
+		}
+		if guesses != nil {
+			*guesses = append(*guesses, Guess{
+				InputOffset: offset,
+				Input:       string(r),
+				Output:      string(out),
+				Alternative: string(otherHornReading(out, prevVowel, r)),
+				Reason:      reason,
+			})
+		}
+		return out
+	}
+
+	return convertVNICombining(text, read)
+}
+
+// readHorn returns how strictness reads r (Ö or ö), and why.
+func readHorn(strictness VNIStrictness, prevVowel bool, r rune) (rune, string) {
+	switch {
+	case strictness == VNIStrictnessPreferTCVN3:
+		return 'ệ', string(VNIStrictnessPreferTCVN3)
+	case strictness == VNIStrictnessPreferVNI:
+		return hornLetter(r), string(VNIStrictnessPreferVNI)
+	case prevVowel:
+		return 'ệ', "after a vowel"
+	default:
+		return hornLetter(r), "not after a vowel"
+	}
+}
+
+// otherHornReading returns the reading of r not chosen: "ệ" for "Ư"/"ư" and the other way
+// round, or the automatic reading when r was left unconverted.
+func otherHornReading(chosen rune, prevVowel bool, r rune) rune {
+	switch chosen {
+	case 'ệ':
+		return hornLetter(r)
+	case r:
+		out, _ := readHorn(VNIStrictnessAuto, prevVowel, r)
+		return out
+	default:
+		return 'ệ'
+	}
+}
+
+// hornLetter returns the VNI reading of Ö/ö: "Ư" or "ư".
+func hornLetter(r rune) rune {
+	if r == 'Ö' {
+		return 'Ư'
+	}
+	return 'ư'
+}
 
-	// Then apply legacy replacements for đ/Đ
-	result = c.legacyReplacer.Replace(result)
+// dictionaryReading is the reading chosen for the Ö/ö in runes [start, end) of a text.
+type dictionaryReading struct {
+	start, end int
+	strictness VNIStrictness
+	// confirmed is false when no reading made the word Vietnamese
+	confirmed bool
+}
 
-	return result
+// dictionaryReadings picks a reading for each word of text holding Ö/ö: the first of the
+// automatic, TCVN3 and VNI readings that makes all its syllables valid.
+func (c *VNIConverter) dictionaryReadings(text string) []dictionaryReading {
+	var readings []dictionaryReading
+	offset := 0
+	for _, word := range splitWords(text) {
+		n := len([]rune(word))
+		if strings.ContainsAny(word, "Öö") {
+			reading := dictionaryReading{start: offset, end: offset + n, strictness: VNIStrictnessAuto}
+			for _, strictness := range []VNIStrictness{VNIStrictnessAuto, VNIStrictnessPreferTCVN3, VNIStrictnessPreferVNI} {
+				// A leading space reads a word-initial Ö/ö as it is read inside the text
+				converted := convertVNICombining(" "+word, func(_ int, prevVowel bool, r rune) rune {
+					out, _ := readHorn(strictness, prevVowel, r)
+					return out
+				})
+				if validWord(converted) {
+					reading.strictness, reading.confirmed = strictness, true
+					break
+				}
+			}
+			readings = append(readings, reading)
+		}
+		offset += n
+	}
+	return readings
 }
 
+// validWord reports whether every run of letters of word is a Vietnamese syllable.
+func validWord(word string) bool {
+	syllables := strings.FieldsFunc(word, func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, s := range syllables {
+		if !spell.ValidSyllable(s) {
+			return false
+		}
+	}
+	return len(syllables) > 0
+}
+
+// hornReader returns the reading of the Ö/ö at rune offset of the input; prevVowel tells
+// whether it follows a vowel.
+type hornReader func(offset int, prevVowel bool, r rune) rune
+
 // convertVNICombining handles VNI "combining marks" style encoding
-func convertVNICombining(text string) string {
-	text = preprocessVNI(text)
+func convertVNICombining(text string, readHorn hornReader) string {
 	runes := []rune(text)
 	result := make([]rune, 0, len(runes))
 
-	for _, r := range runes {
-		// Check if this rune is a VNI tone marker
-		if toneType, isTone := vniToneMarkers[r]; isTone {
-			// Try to combine with previous character
-			if updated, ok := tryCombineTone(result, r, toneType); ok {
-				result = updated
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		// Ư and Ơ (or Ừ) written as Ö and a second marker are not ambiguous
+		if pair, ok := vniHornPair(runes, i); ok {
+			result = append(result, pair...)
+			i++
+			continue
+		}
+		// Ö/ö is either "ệ" or "Ư"/"ư"
+		if r == 'Ö' || r == 'ö' {
+			result = append(result, readHorn(i, checkPrevVowel(result), r))
+			continue
+		}
+
+		// A mark byte combines with the vowel before it
+		if mark, ok := vniMarks[r]; ok && len(result) > 0 {
+			if combined, ok := applyVNIMark(result[len(result)-1], mark); ok {
+				result[len(result)-1] = combined
 				continue
 			}
 		}
 
-		// Handle Đ/đ and Breve (Å/å)
-		if updated, ok := tryCombineOther(result, r); ok {
-			result = updated
+		// Ơ, Đ and some I and Y letters have a byte of their own
+		if letter, ok := vniLetters[r]; ok {
+			result = append(result, letter)
 			continue
 		}
 
@@ -237,33 +411,34 @@ func convertVNICombining(text string) string {
 	return string(result)
 }
 
-func preprocessVNI(text string) string {
-	text = strings.ReplaceAll(text, "ÖÔ", "ƯƠ")
-	text = strings.ReplaceAll(text, "ÖO", "ƯƠ")
-	text = strings.ReplaceAll(text, "Öø", "Ừ")
-	return text
-}
-
-func tryCombineTone(result []rune, r rune, toneType string) ([]rune, bool) {
-	if len(result) == 0 {
-		return result, false
+// vniHornPair returns the letters of the Ö at runes[i] and the marker after it: "ÖÔ" and
+// "ÖO" are "ƯƠ", "Öø" is "Ừ".
+func vniHornPair(runes []rune, i int) ([]rune, bool) {
+	if runes[i] != 'Ö' || i+1 >= len(runes) {
+		return nil, false
 	}
-
-	lastIdx := len(result) - 1
-	lastChar := result[lastIdx]
-
-	// 1. Standard Tone Combination
-	if combined, ok := combineToneStandard(lastChar, toneType); ok {
-		result[lastIdx] = combined
-		return result, true
+	switch runes[i+1] {
+	case 'Ô', 'O':
+		return []rune("ƯƠ"), true
+	case 'ø':
+		return []rune("Ừ"), true
 	}
+	return nil, false
+}
 
-	// 2. Special Markers Logic (Ö, Â, Ø, Ï)
-	if combined, ok := combineToneSpecial(result, r, toneType); ok {
-		return combined, true
+// applyVNIMark returns the letter mark makes of the letter before it.
+func applyVNIMark(last rune, mark vniMark) (rune, bool) {
+	if mark.modifier != "" {
+		modified, ok := vowelCombinations[last][mark.modifier]
+		if !ok {
+			return 0, false
+		}
+		if mark.tone == "" {
+			return modified, true
+		}
+		last = modified
 	}
-
-	return result, false
+	return combineToneStandard(last, mark.tone)
 }
 
 func combineToneStandard(lastChar rune, toneType string) (rune, bool) {
@@ -282,69 +457,6 @@ func combineToneStandard(lastChar rune, toneType string) (rune, bool) {
 	return 0, false
 }
 
-func combineToneSpecial(result []rune, r rune, toneType string) ([]rune, bool) {
-	// Special handling for Ö/ö (Horn/ệ/Ư)
-	if updated, ok := combineVNIHorn(result, r, toneType); ok {
-		return updated, true
-	}
-
-	// Standalone markers (Â, Ø, Ï)
-	if updated, ok := combineVNIStandalone(result, r); ok {
-		return updated, true
-	}
-
-	return result, false
-}
-
-func combineVNIHorn(result []rune, r rune, toneType string) ([]rune, bool) {
-	if (r == 'Ö' || r == 'ö') && toneType == "horn" {
-		isPrevVowel := checkPrevVowel(result)
-		if isPrevVowel {
-			result = append(result, 'ệ')
-		} else {
-			if r == 'Ö' {
-				result = append(result, 'Ư')
-			} else {
-				result = append(result, 'ư')
-			}
-		}
-		return result, true
-	}
-	return result, false
-}
-
-func combineVNIStandalone(result []rune, r rune) ([]rune, bool) {
-	lastIdx := len(result) - 1
-	lastChar := result[lastIdx]
-
-	// Standalone Circumflex (Â/â)
-	if r == 'Â' || r == 'â' {
-		if combos, ok := vowelCombinations[lastChar]; ok {
-			if combined, ok := combos["circumflex"]; ok {
-				result[lastIdx] = combined
-				return result, true
-			}
-		}
-	}
-
-	// Standalone Grave (Ø/ø)
-	if r == 'Ø' || r == 'ø' {
-		if combined, ok := combineToneStandard(lastChar, "grave"); ok {
-			result[lastIdx] = combined
-			return result, true
-		}
-	}
-
-	// Standalone Dot (Ï/ï)
-	if r == 'Ï' || r == 'ï' {
-		if combined, ok := combineToneStandard(lastChar, "dot"); ok {
-			result[lastIdx] = combined
-			return result, true
-		}
-	}
-	return result, false
-}
-
 func checkPrevVowel(result []rune) bool {
 	if len(result) == 0 {
 		return false
@@ -354,28 +466,3 @@ func checkPrevVowel(result []rune) bool {
 	_, ok2 := combinedVowelTones[lastChar]
 	return ok1 || ok2
 }
-
-func tryCombineOther(result []rune, r rune) ([]rune, bool) {
-	// Handle Đ/đ
-	if r == 'Ñ' {
-		return append(result, 'Đ'), true
-	}
-	if r == 'ñ' {
-		return append(result, 'đ'), true
-	}
-
-	// Handle Breve (Å/å)
-	if (r == 'Å' || r == 'å') && len(result) > 0 {
-		lastIdx := len(result) - 1
-		lastChar := result[lastIdx]
-		if lastChar == 'A' || lastChar == 'a' {
-			if unicode.IsUpper(lastChar) {
-				result[lastIdx] = 'Ă'
-			} else {
-				result[lastIdx] = 'ă'
-			}
-			return result, true
-		}
-	}
-	return result, false
-}
@@ -0,0 +1,162 @@
+//go:build charsets
+
+// Code generated by scripts/gen_charset_tests from ../../scripts/gen_charset_tests/tables/vni.txt; DO NOT EDIT.
+
+package converter
+
+import "testing"
+
+func TestVNITable(t *testing.T) {
+	c, err := NewConverter(EncodingVNI)
+	if err != nil {
+		t.Fatalf("NewConverter failed: %v", err)
+	}
+
+	tests := []struct {
+		legacy string
+		want   string
+	}{
+		{legacy: "a\u00F9", want: "á"},
+		{legacy: "a\u00F8", want: "à"},
+		{legacy: "a\u00FB", want: "ả"},
+		{legacy: "a\u00F5", want: "ã"},
+		{legacy: "a\u00EF", want: "ạ"},
+		{legacy: "e\u00F9", want: "é"},
+		{legacy: "e\u00F8", want: "è"},
+		{legacy: "e\u00FB", want: "ẻ"},
+		{legacy: "e\u00F5", want: "ẽ"},
+		{legacy: "e\u00EF", want: "ẹ"},
+		{legacy: "o\u00F9", want: "ó"},
+		{legacy: "o\u00F8", want: "ò"},
+		{legacy: "o\u00FB", want: "ỏ"},
+		{legacy: "o\u00F5", want: "õ"},
+		{legacy: "o\u00EF", want: "ọ"},
+		{legacy: "u\u00F9", want: "ú"},
+		{legacy: "u\u00F8", want: "ù"},
+		{legacy: "u\u00FB", want: "ủ"},
+		{legacy: "u\u00F5", want: "ũ"},
+		{legacy: "u\u00EF", want: "ụ"},
+		{legacy: "y\u00F9", want: "ý"},
+		{legacy: "y\u00F8", want: "ỳ"},
+		{legacy: "y\u00FB", want: "ỷ"},
+		{legacy: "y\u00F5", want: "ỹ"},
+		{legacy: "a\u00E2", want: "â"},
+		{legacy: "a\u00E1", want: "ấ"},
+		{legacy: "a\u00E0", want: "ầ"},
+		{legacy: "a\u00E5", want: "ẩ"},
+		{legacy: "a\u00E3", want: "ẫ"},
+		{legacy: "a\u00E4", want: "ậ"},
+		{legacy: "e\u00E2", want: "ê"},
+		{legacy: "e\u00E1", want: "ế"},
+		{legacy: "e\u00E0", want: "ề"},
+		{legacy: "e\u00E5", want: "ể"},
+		{legacy: "e\u00E3", want: "ễ"},
+		{legacy: "e\u00E4", want: "ệ"},
+		{legacy: "o\u00E2", want: "ô"},
+		{legacy: "o\u00E1", want: "ố"},
+		{legacy: "o\u00E0", want: "ồ"},
+		{legacy: "o\u00E5", want: "ổ"},
+		{legacy: "o\u00E3", want: "ỗ"},
+		{legacy: "o\u00E4", want: "ộ"},
+		{legacy: "a\u00EA", want: "ă"},
+		{legacy: "a\u00E9", want: "ắ"},
+		{legacy: "a\u00E8", want: "ằ"},
+		{legacy: "a\u00FA", want: "ẳ"},
+		{legacy: "a\u00FC", want: "ẵ"},
+		{legacy: "a\u00EB", want: "ặ"},
+		{legacy: "\u00F4", want: "ơ"},
+		{legacy: "\u00F6", want: "ư"},
+		{legacy: "\u00F1", want: "đ"},
+		{legacy: "\u00ED", want: "í"},
+		{legacy: "\u00EC", want: "ì"},
+		{legacy: "\u00E6", want: "ỉ"},
+		{legacy: "\u00F3", want: "ĩ"},
+		{legacy: "\u00F2", want: "ị"},
+		{legacy: "\u00EE", want: "ỵ"},
+		{legacy: "\u00F4\u00F9", want: "ớ"},
+		{legacy: "\u00F4\u00F8", want: "ờ"},
+		{legacy: "\u00F4\u00FB", want: "ở"},
+		{legacy: "\u00F4\u00F5", want: "ỡ"},
+		{legacy: "\u00F4\u00EF", want: "ợ"},
+		{legacy: "\u00F6\u00F9", want: "ứ"},
+		{legacy: "\u00F6\u00F8", want: "ừ"},
+		{legacy: "\u00F6\u00FB", want: "ử"},
+		{legacy: "\u00F6\u00F5", want: "ữ"},
+		{legacy: "\u00F6\u00EF", want: "ự"},
+		{legacy: "A\u00D9", want: "Á"},
+		{legacy: "A\u00D8", want: "À"},
+		{legacy: "A\u00DB", want: "Ả"},
+		{legacy: "A\u00D5", want: "Ã"},
+		{legacy: "A\u00CF", want: "Ạ"},
+		{legacy: "E\u00D9", want: "É"},
+		{legacy: "E\u00D8", want: "È"},
+		{legacy: "E\u00DB", want: "Ẻ"},
+		{legacy: "E\u00D5", want: "Ẽ"},
+		{legacy: "E\u00CF", want: "Ẹ"},
+		{legacy: "O\u00D9", want: "Ó"},
+		{legacy: "O\u00D8", want: "Ò"},
+		{legacy: "O\u00DB", want: "Ỏ"},
+		{legacy: "O\u00D5", want: "Õ"},
+		{legacy: "O\u00CF", want: "Ọ"},
+		{legacy: "U\u00D9", want: "Ú"},
+		{legacy: "U\u00D8", want: "Ù"},
+		{legacy: "U\u00DB", want: "Ủ"},
+		{legacy: "U\u00D5", want: "Ũ"},
+		{legacy: "U\u00CF", want: "Ụ"},
+		{legacy: "Y\u00D9", want: "Ý"},
+		{legacy: "Y\u00D8", want: "Ỳ"},
+		{legacy: "Y\u00DB", want: "Ỷ"},
+		{legacy: "Y\u00D5", want: "Ỹ"},
+		{legacy: "A\u00C2", want: "Â"},
+		{legacy: "A\u00C1", want: "Ấ"},
+		{legacy: "A\u00C0", want: "Ầ"},
+		{legacy: "A\u00C5", want: "Ẩ"},
+		{legacy: "A\u00C3", want: "Ẫ"},
+		{legacy: "A\u00C4", want: "Ậ"},
+		{legacy: "E\u00C2", want: "Ê"},
+		{legacy: "E\u00C1", want: "Ế"},
+		{legacy: "E\u00C0", want: "Ề"},
+		{legacy: "E\u00C5", want: "Ể"},
+		{legacy: "E\u00C3", want: "Ễ"},
+		{legacy: "E\u00C4", want: "Ệ"},
+		{legacy: "O\u00C2", want: "Ô"},
+		{legacy: "O\u00C1", want: "Ố"},
+		{legacy: "O\u00C0", want: "Ồ"},
+		{legacy: "O\u00C5", want: "Ổ"},
+		{legacy: "O\u00C3", want: "Ỗ"},
+		{legacy: "O\u00C4", want: "Ộ"},
+		{legacy: "A\u00CA", want: "Ă"},
+		{legacy: "A\u00C9", want: "Ắ"},
+		{legacy: "A\u00C8", want: "Ằ"},
+		{legacy: "A\u00DA", want: "Ẳ"},
+		{legacy: "A\u00DC", want: "Ẵ"},
+		{legacy: "A\u00CB", want: "Ặ"},
+		{legacy: "\u00D4", want: "Ơ"},
+		{legacy: "\u00D6", want: "Ư"},
+		{legacy: "\u00D1", want: "Đ"},
+		{legacy: "\u00CD", want: "Í"},
+		{legacy: "\u00CC", want: "Ì"},
+		{legacy: "\u00C6", want: "Ỉ"},
+		{legacy: "\u00D3", want: "Ĩ"},
+		{legacy: "\u00D2", want: "Ị"},
+		{legacy: "\u00CE", want: "Ỵ"},
+		{legacy: "\u00D4\u00D9", want: "Ớ"},
+		{legacy: "\u00D4\u00D8", want: "Ờ"},
+		{legacy: "\u00D4\u00DB", want: "Ở"},
+		{legacy: "\u00D4\u00D5", want: "Ỡ"},
+		{legacy: "\u00D4\u00CF", want: "Ợ"},
+		{legacy: "\u00D6\u00D9", want: "Ứ"},
+		{legacy: "\u00D6\u00D8", want: "Ừ"},
+		{legacy: "\u00D6\u00DB", want: "Ử"},
+		{legacy: "\u00D6\u00D5", want: "Ữ"},
+		{legacy: "\u00D6\u00CF", want: "Ự"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := c.ToUnicode(tt.legacy); got != tt.want {
+				t.Errorf("ToUnicode(%q) = %q, want %q", tt.legacy, got, tt.want)
+			}
+		})
+	}
+}
@@ -14,14 +14,24 @@ func TestVNIConverter_ToUnicode(t *testing.T) {
 	}{
 		{
 			name:     "Lowercase a with tones",
-			input:    "\u00E1 \u00E0 \u001E \u00E3 \u00B5", // á à ả ã ạ (VNI codes)
+			input:    "a\u00F9 a\u00F8 a\u00FB a\u00F5 a\u00EF", // á à ả ã ạ (VNI codes)
 			expected: "á à ả ã ạ",
 		},
 		{
 			name:     "Lowercase a circumflex with tones",
-			input:    "\u00E2 \u00CA \u00C7 \u00C8 \u00C9 \u00CB", // â ấ ầ ẩ ẫ ậ
+			input:    "a\u00E2 a\u00E1 a\u00E0 a\u00E5 a\u00E3 a\u00E4", // â ấ ầ ẩ ẫ ậ
 			expected: "â ấ ầ ẩ ẫ ậ",
 		},
+		{
+			name:     "Lowercase a breve with tones",
+			input:    "a\u00EA a\u00E9 a\u00E8 a\u00FA a\u00FC a\u00EB", // ă ắ ằ ẳ ẵ ặ
+			expected: "ă ắ ằ ẳ ẵ ặ",
+		},
+		{
+			name:     "Letters with a byte of their own",
+			input:    "\u00D1\u00F6\u00F4\u00F8ng t\u00F6\u00EF ch\u00EE", // Đường tự chỵ
+			expected: "Đường tự chỵ",
+		},
 		{
 			name: "Mixed sentence",
 			// "Việt Nam" in VNI: V i \u00D6 t N a m
@@ -45,3 +55,31 @@ func TestVNIConverter_ToUnicode(t *testing.T) {
 		})
 	}
 }
+
+func TestVNIConverter_Strictness(t *testing.T) {
+	tests := []struct {
+		name       string
+		strictness VNIStrictness
+		input      string
+		expected   string
+	}{
+		{name: "Auto after a vowel", strictness: VNIStrictnessAuto, input: "Vi\u00D6t", expected: "Vi\u1EC7t"},
+		{name: "Auto after a consonant", strictness: VNIStrictnessAuto, input: "T\u00D6", expected: "T\u01AF"},
+		{name: "Prefer TCVN3", strictness: VNIStrictnessPreferTCVN3, input: "T\u00D6", expected: "T\u1EC7"},
+		{name: "Prefer VNI", strictness: VNIStrictnessPreferVNI, input: "Vi\u00D6t", expected: "Vi\u01AFt"},
+		{name: "Dictionary keeps a valid automatic reading", strictness: VNIStrictnessDictionary, input: "Vi\u00D6t", expected: "Vi\u1EC7t"},
+		{name: "Dictionary corrects the automatic reading", strictness: VNIStrictnessDictionary, input: "ngh\u00F6", expected: "ngh\u1EC7"},
+		{name: "Dictionary leaves unconfirmed words", strictness: VNIStrictnessDictionary, input: "Vi\u00D6t x\u00F6z", expected: "Vi\u1EC7t x\u00F6z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewVNIConverterWithStrictness(tt.strictness).ToUnicode(tt.input); got != tt.expected {
+				t.Errorf("ToUnicode() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := ParseVNIStrictness("guess"); err == nil {
+		t.Error("ParseVNIStrictness() accepted an unknown strictness")
+	}
+}
@@ -0,0 +1,43 @@
+package converter
+
+import "strings"
+
+// vniDOSTable maps VNI-DOS mark bytes to their VNI-Windows equivalents.
+// Why: VNI-DOS uses the same "base letter + mark" scheme as VNI-Windows, but moves
+// the marks out of the DOS box-drawing range. Remapping the bytes lets us reuse the
+// VNI combining logic instead of maintaining a second composition table.
+var vniDOSTable = map[rune]rune{
+	0x80: 'Â', 0x81: 'â', // circumflex (a)
+	0x82: 'Â', 0x83: 'â', // circumflex (e)
+	0x84: 'Â', 0x85: 'â', // circumflex (o)
+	0x86: 'Ø', 0x87: 'ø', // grave
+	0x88: 'Ù', 0x89: 'ù', // acute
+	0x8A: 'Û', 0x8B: 'û', // hook
+	0x8C: 'Õ', 0x8D: 'õ', // tilde
+	0x8E: 'Ï', 0x8F: 'ï', // dot
+	0x90: 'Ö', 0x91: 'ö', // horn
+	0x92: 'Ê', 0x93: 'ê', // breve
+	0x94: 'Ñ', 0x95: 'ñ', // Đ/đ
+}
+
+// VNIDOSConverter handles conversion from VNI for DOS encoding to Unicode.
+// Input runes are expected to be raw byte values (see DecodeBytes).
+type VNIDOSConverter struct {
+	vni *VNIConverter
+}
+
+// NewVNIDOSConverter creates a new instance.
+func NewVNIDOSConverter() *VNIDOSConverter {
+	return &VNIDOSConverter{vni: NewVNIConverter()}
+}
+
+// ToUnicode converts VNI-DOS text to Unicode.
+func (c *VNIDOSConverter) ToUnicode(text string) string {
+	remapped := strings.Map(func(r rune) rune {
+		if w, ok := vniDOSTable[r]; ok {
+			return w
+		}
+		return r
+	}, text)
+	return c.vni.ToUnicode(remapped)
+}
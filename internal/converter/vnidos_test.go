@@ -0,0 +1,86 @@
+package converter
+
+import (
+	"testing"
+)
+
+func TestVNIDOSConverter_ToUnicode(t *testing.T) {
+	c := NewVNIDOSConverter()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Lowercase a with tones",
+			input:    "a\u0089 a\u0087 a\u008B a\u008D a\u008F", // á à ả ã ạ
+			expected: "á à ả ã ạ",
+		},
+		{
+			name:     "Mixed sentence",
+			input:    "Vi\u0090t Nam", // Việt Nam
+			expected: "Việt Nam",
+		},
+		{
+			name:     "D stroke",
+			input:    "\u0094a\u0087 \u0095i", // Đà đi
+			expected: "Đà đi",
+		},
+		{
+			name:     "Plain text",
+			input:    "Hello World",
+			expected: "Hello World",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.ToUnicode(tt.input)
+			if got != tt.expected {
+				t.Errorf("ToUnicode() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding EncodingType
+		input    []byte
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "VNI-DOS bytes",
+			encoding: EncodingVNIDOS,
+			input:    []byte{'V', 'i', 0x90, 't'},
+			expected: "Việt",
+		},
+		{
+			name:     "TCVN3 bytes",
+			encoding: EncodingTCVN3,
+			input:    []byte{'C', 0xAB, 'n', 'g'},
+			expected: "Công",
+		},
+		{
+			name:     "Not byte-oriented",
+			encoding: EncodingAuto,
+			input:    []byte("abc"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertBytes(tt.encoding, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvertBytes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ConvertBytes() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package converter
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// VNU stores each Vietnamese letter as up to three bytes: the ASCII base letter,
+// an optional shape mark (circumflex, breve, horn) and an optional tone mark.
+// Why: Because the scheme is fully decomposed, we map each mark to its Unicode
+// combining character and let NFC composition build the precomposed letter.
+
+// vnuShapeMarks maps VNU shape mark bytes to Unicode combining characters.
+var vnuShapeMarks = map[rune]rune{
+	'\u00AA': '\u0302', // ª circumflex
+	'\u00BA': '\u0306', // º breve
+	'\u00B0': '\u031B', // ° horn
+}
+
+// vnuToneMarks maps VNU tone mark bytes to Unicode combining characters.
+var vnuToneMarks = map[rune]rune{
+	'\u00B2': '\u0300', // ² grave
+	'\u00B3': '\u0301', // ³ acute
+	'\u00BF': '\u0309', // ¿ hook
+	'\u00AC': '\u0303', // ¬ tilde
+	'\u00B1': '\u0323', // ± dot
+}
+
+// vnuStroke maps the VNU d-stroke bytes.
+var vnuStroke = map[rune]rune{
+	'\u00D0': 'Đ', // Ð
+	'\u00F0': 'đ', // ð
+}
+
+// VNUConverter handles conversion from VNU (3-byte) encoding to Unicode.
+type VNUConverter struct{}
+
+// NewVNUConverter creates a new instance.
+func NewVNUConverter() *VNUConverter {
+	return &VNUConverter{}
+}
+
+// ToUnicode converts VNU encoded text to Unicode.
+func (c *VNUConverter) ToUnicode(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	prevLetter := false
+	for _, r := range text {
+		if d, ok := vnuStroke[r]; ok {
+			b.WriteRune(d)
+			prevLetter = true
+			continue
+		}
+		// Marks only combine with a preceding letter; stray bytes are kept as-is
+		if prevLetter {
+			if m, ok := vnuShapeMarks[r]; ok {
+				b.WriteRune(m)
+				continue
+			}
+			if m, ok := vnuToneMarks[r]; ok {
+				b.WriteRune(m)
+				continue
+			}
+		}
+		b.WriteRune(r)
+		prevLetter = isASCIILetter(r)
+	}
+	return norm.NFC.String(b.String())
+}
+
+// HasVNUPattern reports whether text contains a letter followed by a VNU mark.
+// Why: Used by detection; a shape or tone byte right after a letter is characteristic of VNU.
+func HasVNUPattern(text string) bool {
+	prevLetter := false
+	for _, r := range text {
+		if prevLetter {
+			if _, ok := vnuShapeMarks[r]; ok {
+				return true
+			}
+			if _, ok := vnuToneMarks[r]; ok {
+				return true
+			}
+		}
+		prevLetter = isASCIILetter(r)
+	}
+	return false
+}
+
+func isASCIILetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
@@ -0,0 +1,59 @@
+package converter
+
+import (
+	"testing"
+)
+
+func TestVNUConverter_ToUnicode(t *testing.T) {
+	c := NewVNUConverter()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Lowercase a with tones",
+			input:    "a³ a² a¿ a¬ a±", // á à ả ã ạ
+			expected: "á à ả ã ạ",
+		},
+		{
+			name:     "Three byte letters",
+			input:    "Vieª±t Nam", // Việt Nam
+			expected: "Việt Nam",
+		},
+		{
+			name:     "Horn and breve",
+			input:    "Ðu°o°²ng maº³t", // Đường mắt
+			expected: "Đường mắt",
+		},
+		{
+			name:     "Stray mark kept",
+			input:    "³ 10",
+			expected: "³ 10",
+		},
+		{
+			name:     "Plain text",
+			input:    "Hello World",
+			expected: "Hello World",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.ToUnicode(tt.input)
+			if got != tt.expected {
+				t.Errorf("ToUnicode() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHasVNUPattern(t *testing.T) {
+	if !HasVNUPattern("Vieª±t") {
+		t.Error("expected VNU pattern in Vie^.t")
+	}
+	if HasVNUPattern("Hello ³") {
+		t.Error("stray mark after space must not match")
+	}
+}
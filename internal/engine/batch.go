@@ -0,0 +1,202 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// File states reported by RunBatch.
+const (
+	FileStarted = "started"
+	FileDone    = "done"
+	FileFailed  = "failed"
+)
+
+// FileProgress is a per-file progress update of a batch.
+type FileProgress struct {
+	Index      int    `json:"index"` // position in the processing order, 0-based
+	Total      int    `json:"total"`
+	InputPath  string `json:"inputPath"`
+	State      string `json:"state"`
+	OutputPath string `json:"outputPath,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// DuplicateOf is the file of the batch this one is a byte-identical copy of (see RunBatchDeduped)
+	DuplicateOf string `json:"duplicateOf,omitempty"`
+}
+
+// FileResult is the outcome of one file of a batch.
+type FileResult struct {
+	InputPath  string `json:"inputPath"`
+	OutputPath string `json:"outputPath,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// DuplicateOf is the file of the batch this one is a byte-identical copy of; its output
+	// was copied from that file's output instead of converted
+	DuplicateOf string `json:"duplicateOf,omitempty"`
+}
+
+// ConvertFunc converts a single file and returns its output path.
+type ConvertFunc func(ctx context.Context, inputPath string) (string, error)
+
+// NewFileConverter returns a ConvertFunc running one Processor per file.
+// configure, if not nil, is applied to every Processor before it runs.
+func NewFileConverter(sheetName string, configure func(*Processor)) ConvertFunc {
+	return func(ctx context.Context, inputPath string) (string, error) {
+		p := NewProcessor(inputPath, sheetName)
+		if configure != nil {
+			configure(p)
+		}
+		return p.Run(ctx)
+	}
+}
+
+// RunBatch converts paths in the given order, up to parallel files at a time.
+// Why: Converting hundreds of workbooks one dialog at a time is not practical.
+// A failing file never stops the batch; its error is recorded in the result.
+// Results are returned in the order of paths. progress may be nil.
+func RunBatch(ctx context.Context, paths []string, parallel int, convert ConvertFunc,
+	progress chan<- FileProgress) []FileResult {
+	return RunBatchDeduped(ctx, paths, parallel, convert, nil, progress)
+}
+
+// RunBatchDeduped is RunBatch converting byte-identical files once (see FindDuplicates):
+// each copy waits for the first file with its content and gets its output from
+// copyOutput, with DuplicateOf set in its result. A nil copyOutput converts every file.
+// Why: Legacy archives are full of copies of the same workbook under different names.
+func RunBatchDeduped(ctx context.Context, paths []string, parallel int, convert ConvertFunc,
+	copyOutput CopyOutputFunc, progress chan<- FileProgress) []FileResult {
+	if parallel < 1 {
+		parallel = 1
+	}
+	var duplicates map[string]string
+	if copyOutput != nil {
+		duplicates = FindDuplicates(paths)
+	}
+	// done[i] is closed once results[i] is final; a copy waits for the first file with
+	// its content, which always comes earlier in paths and so was started before it
+	done := make([]chan struct{}, len(paths))
+	first := make(map[string]int, len(paths))
+	for i, path := range paths {
+		done[i] = make(chan struct{})
+		if _, ok := first[path]; !ok {
+			first[path] = i
+		}
+	}
+	results := make([]FileResult, len(paths))
+	report := func(fp FileProgress) {
+		if progress != nil {
+			progress <- fp
+		}
+	}
+
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		results[i].InputPath = path
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		// Files not yet started when the batch is cancelled are marked failed
+		if err := ctx.Err(); err != nil {
+			results[i].Error = fmt.Sprintf("batch cancelled: %v", err)
+			close(done[i])
+			report(FileProgress{Index: i, Total: len(paths), InputPath: path, State: FileFailed, Error: results[i].Error})
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			original, duplicate := duplicates[path]
+			results[i].DuplicateOf = original
+			report(FileProgress{Index: i, Total: len(paths), InputPath: path, State: FileStarted, DuplicateOf: original})
+			var outputPath string
+			var err error
+			if duplicate {
+				j := first[original]
+				<-done[j]
+				if results[j].Error != "" {
+					err = fmt.Errorf("same content as %s, which failed: %s", filepath.Base(original), results[j].Error)
+				} else {
+					outputPath, err = copyOutput(path, original, results[j].OutputPath)
+				}
+			} else {
+				outputPath, err = convert(ctx, path)
+			}
+			if err != nil {
+				results[i].Error = err.Error()
+				close(done[i])
+				report(FileProgress{Index: i, Total: len(paths), InputPath: path, State: FileFailed,
+					Error: err.Error(), DuplicateOf: original})
+				return
+			}
+			results[i].OutputPath = outputPath
+			close(done[i])
+			report(FileProgress{Index: i, Total: len(paths), InputPath: path, State: FileDone,
+				OutputPath: outputPath, DuplicateOf: original})
+		}(i, path)
+	}
+	wg.Wait()
+	return results
+}
+
+// BatchProgress combines the progress of the files of a batch into one Status.
+// Why: Files converted in parallel each report their own percentage; shown as they come,
+// the progress bar jumps between files. Every file weighs the same, since cell counts
+// are only known once a file is opened. It is safe for concurrent use.
+type BatchProgress struct {
+	mu      sync.Mutex
+	started time.Time
+	files   map[string]Status // latest status by input path
+	count   int
+}
+
+// NewBatchProgress tracks the files of paths, none of them started yet.
+func NewBatchProgress(paths []string) *BatchProgress {
+	return &BatchProgress{started: time.Now(), files: make(map[string]Status, len(paths)), count: len(paths)}
+}
+
+// Update records the progress of the file at path and returns the batch progress.
+func (b *BatchProgress) Update(path string, st Status) Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.files[path] = st
+	return b.status(st.SheetName)
+}
+
+// Finish marks the file at path as complete, converted or failed, and returns the batch progress.
+func (b *BatchProgress) Finish(path string) Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st := b.files[path]
+	st.Percent = 100
+	if st.Processed < st.Total {
+		st.Processed = st.Total
+	}
+	b.files[path] = st
+	return b.status("")
+}
+
+// status sums the recorded files. The caller holds mu.
+func (b *BatchProgress) status(sheet string) Status {
+	batch := Status{SheetName: sheet}
+	if b.count == 0 {
+		return batch
+	}
+	var percent float64
+	for _, st := range b.files {
+		percent += st.Percent
+		batch.Processed += st.Processed
+		batch.Total += st.Total
+	}
+	batch.Percent = percent / float64(b.count)
+	// Cell counts of unopened files are unknown, so the ETA follows the percentage
+	batch.ETA = estimateETA(time.Since(b.started), int(batch.Percent*100), 100*100)
+	return batch
+}
@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBatch(t *testing.T) {
+	paths := []string{"a.xlsx", "bad.xlsx", "c.xlsx", "d.xlsx"}
+	convert := func(_ context.Context, path string) (string, error) {
+		if path == "bad.xlsx" {
+			return "", errors.New("broken")
+		}
+		return "out_" + path, nil
+	}
+
+	tests := []struct {
+		name     string
+		parallel int
+	}{
+		{name: "Sequential", parallel: 1},
+		{name: "Parallel", parallel: 3},
+		{name: "Zero means sequential", parallel: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := make(chan FileProgress, 2*len(paths))
+			results := RunBatch(context.Background(), paths, tt.parallel, convert, progress)
+			close(progress)
+
+			if len(results) != len(paths) {
+				t.Fatalf("got %d results, want %d", len(results), len(paths))
+			}
+			for i, r := range results {
+				if r.InputPath != paths[i] {
+					t.Errorf("result %d is for %s, want %s", i, r.InputPath, paths[i])
+				}
+				wantErr := paths[i] == "bad.xlsx"
+				if (r.Error != "") != wantErr {
+					t.Errorf("%s: error = %q, want error %v", r.InputPath, r.Error, wantErr)
+				}
+				if !wantErr && r.OutputPath != "out_"+paths[i] {
+					t.Errorf("%s: output = %q", r.InputPath, r.OutputPath)
+				}
+			}
+
+			states := map[string]int{}
+			for fp := range progress {
+				states[fp.State]++
+			}
+			if states[FileStarted] != 4 || states[FileDone] != 3 || states[FileFailed] != 1 {
+				t.Errorf("progress states = %v", states)
+			}
+		})
+	}
+}
+
+func TestRunBatch_ParallelLimit(t *testing.T) {
+	var running, peak int32
+	var mu sync.Mutex
+	convert := func(_ context.Context, path string) (string, error) {
+		n := atomic.AddInt32(&running, 1)
+		mu.Lock()
+		if n > peak {
+			peak = n
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return path, nil
+	}
+	RunBatch(context.Background(), make([]string, 8), 2, convert, nil)
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", peak)
+	}
+}
+
+func TestRunBatch_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	convert := func(context.Context, string) (string, error) {
+		called = true
+		return "", nil
+	}
+	results := RunBatch(ctx, []string{"a", "b"}, 1, convert, nil)
+	if called {
+		t.Error("convert must not run after cancellation")
+	}
+	for _, r := range results {
+		if r.Error == "" {
+			t.Errorf("%s: expected cancellation error", r.InputPath)
+		}
+	}
+}
+
+func TestNewFileConverter(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.xlsx")
+	writeWorkbook(t, input, map[string]string{"A1": "Vi\u00D6t Nam"})
+	outDir := filepath.Join(dir, "out")
+	if err := os.Mkdir(outDir, 0750); err != nil {
+		t.Fatalf("failed to create out dir: %v", err)
+	}
+
+	convert := NewFileConverter("", func(p *Processor) { p.SetOutputDir(outDir) })
+	outputPath, err := convert(context.Background(), input)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if filepath.Dir(outputPath) != outDir {
+		t.Errorf("output %s not written to %s", outputPath, outDir)
+	}
+}
+
+func TestBatchProgress(t *testing.T) {
+	b := NewBatchProgress([]string{"a.xlsx", "b.xlsx", "c.xlsx", "d.xlsx"})
+
+	tests := []struct {
+		name        string
+		step        func() Status
+		wantPercent float64
+		wantTotal   int
+	}{
+		{name: "First file halfway", step: func() Status {
+			return b.Update("a.xlsx", Status{SheetName: "S", Processed: 50, Total: 100, Percent: 50})
+		}, wantPercent: 12.5, wantTotal: 100},
+		{name: "Second file started", step: func() Status {
+			return b.Update("b.xlsx", Status{Processed: 0, Total: 300, Percent: 0})
+		}, wantPercent: 12.5, wantTotal: 400},
+		{name: "First file done", step: func() Status { return b.Finish("a.xlsx") }, wantPercent: 25, wantTotal: 400},
+		{name: "Unopened file failed", step: func() Status { return b.Finish("c.xlsx") }, wantPercent: 50, wantTotal: 400},
+		{name: "Repeated update", step: func() Status {
+			return b.Update("b.xlsx", Status{Processed: 150, Total: 300, Percent: 50})
+		}, wantPercent: 62.5, wantTotal: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.step()
+			if got.Percent != tt.wantPercent || got.Total != tt.wantTotal {
+				t.Errorf("status = %+v, want percent %v and total %d", got, tt.wantPercent, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// DefaultWriteBatchSize is the number of converted cells buffered before writing.
+const DefaultWriteBatchSize = 1000
+
+// batchWriter buffers converted cells and writes them grouped by sheet and row.
+// Why: Results arrive from workers out of order; excelize inserts cells much faster
+// when they are written in row/column order, which cuts save-phase time on big sheets.
+// Only the collector goroutine may use it (it writes to the excelize.File under mu).
+type batchWriter struct {
+	f       *excelize.File
+	mu      *sync.Mutex
+	size    int
+	pending []Result
+	// plainCells writes cells that were plain strings back as plain strings (see SetPlainCells)
+	plainCells bool
+	// styles caches the restyled cell format of each source style and font family
+	styles map[plainStyle]int
+	// errs records the cells that could not be written (nil: only logged)
+	errs *cellErrors
+}
+
+// plainStyle identifies the cell format of a plain cell whose style font was swapped.
+type plainStyle struct {
+	styleID int
+	family  string
+}
+
+func newBatchWriter(f *excelize.File, mu *sync.Mutex, size int) *batchWriter {
+	if size < 1 {
+		size = 1
+	}
+	return &batchWriter{f: f, mu: mu, size: size, pending: make([]Result, 0, size), styles: make(map[plainStyle]int)}
+}
+
+// add queues a result and flushes once the batch is full.
+func (w *batchWriter) add(res Result) {
+	w.pending = append(w.pending, res)
+	if len(w.pending) >= w.size {
+		w.flush()
+	}
+}
+
+// flush writes all queued results in sheet/row/column order.
+func (w *batchWriter) flush() {
+	sort.Slice(w.pending, func(i, j int) bool {
+		a, b := w.pending[i].Job, w.pending[j].Job
+		if a.SheetName != b.SheetName {
+			return a.SheetName < b.SheetName
+		}
+		if a.Row != b.Row {
+			return a.Row < b.Row
+		}
+		return a.Col < b.Col
+	})
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, res := range w.pending {
+		if w.plainCells && !res.Job.IsRich {
+			if err := w.writePlain(res); err != nil {
+				slog.Error("failed to write plain text", "cell", res.Job.Axis, "error", err)
+				w.fail(res, err)
+			}
+			continue
+		}
+		// Write Rich Text to enforce font/format
+		if err := w.f.SetCellRichText(res.Job.SheetName, res.Job.Axis, res.NewRuns); err != nil {
+			slog.Error("failed to write rich text", "cell", res.Job.Axis, "error", err)
+			w.fail(res, err)
+		}
+	}
+	w.pending = w.pending[:0]
+}
+
+// fail records that the cell of res could not be written.
+func (w *batchWriter) fail(res Result, err error) {
+	if w.errs != nil {
+		w.errs.add(res.Job.SheetName, res.Job.Axis, StageWrite, err)
+	}
+}
+
+// writePlain writes a converted plain cell as a plain string and moves the converted font
+// family into the cell's style. The synthetic run copies the style font, so the family is
+// the only font property conversion changes. The caller holds mu.
+func (w *batchWriter) writePlain(res Result) error {
+	sheet, axis := res.Job.SheetName, res.Job.Axis
+	if err := w.f.SetCellValue(sheet, axis, runsText(res.NewRuns)); err != nil {
+		return fmt.Errorf("failed to set value: %w", err)
+	}
+	family := runsFont(res.NewRuns)
+	if family == "" || family == runsFont(res.Job.RichText) {
+		return nil
+	}
+	styleID, err := w.f.GetCellStyle(sheet, axis)
+	if err != nil {
+		return fmt.Errorf("failed to read style: %w", err)
+	}
+	key := plainStyle{styleID: styleID, family: family}
+	newID, ok := w.styles[key]
+	if !ok {
+		style, err := w.f.GetStyle(styleID)
+		if err != nil {
+			return fmt.Errorf("failed to read style %d: %w", styleID, err)
+		}
+		font := excelize.Font{}
+		if style.Font != nil {
+			font = *style.Font
+		}
+		font.Family = family
+		style.Font = &font
+		if newID, err = w.f.NewStyle(style); err != nil {
+			return fmt.Errorf("failed to create style: %w", err)
+		}
+		w.styles[key] = newID
+	}
+	if err := w.f.SetCellStyle(sheet, axis, axis, newID); err != nil {
+		return fmt.Errorf("failed to set style: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestBatchWriter_FlushWritesAllCells(t *testing.T) {
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+
+	w := newBatchWriter(f, &sync.Mutex{}, 2)
+	// Out of order, as produced by the worker pool
+	cells := []Job{
+		{SheetName: "Sheet1", Axis: "B3", Row: 3, Col: 2},
+		{SheetName: "Sheet1", Axis: "A1", Row: 1, Col: 1},
+		{SheetName: "Sheet1", Axis: "C2", Row: 2, Col: 3},
+	}
+	for _, job := range cells {
+		w.add(Result{Job: job, NewRuns: []excelize.RichTextRun{{Text: job.Axis}}})
+	}
+	if len(w.pending) != 1 {
+		t.Fatalf("pending count mismatch. Got %d, want 1 after auto flush", len(w.pending))
+	}
+	w.flush()
+
+	for _, job := range cells {
+		val, err := f.GetCellValue("Sheet1", job.Axis)
+		if err != nil {
+			t.Fatalf("GetCellValue(%s) failed: %v", job.Axis, err)
+		}
+		if val != job.Axis {
+			t.Errorf("%s = %q, want %q", job.Axis, val, job.Axis)
+		}
+	}
+}
+
+func TestProcessor_RunWithPlainCells(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "plain.xlsx")
+	f := excelize.NewFile()
+	style, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Family: "VNI-Times", Size: 14, Bold: true}})
+	if err != nil {
+		t.Fatalf("NewStyle failed: %v", err)
+	}
+	for _, axis := range []string{"A1", "A2"} {
+		if err := f.SetCellValue("Sheet1", axis, "Vi\u00D6t Nam"); err != nil {
+			t.Fatalf("SetCellValue failed: %v", err)
+		}
+		if err := f.SetCellStyle("Sheet1", axis, axis, style); err != nil {
+			t.Fatalf("SetCellStyle failed: %v", err)
+		}
+	}
+	runs := []excelize.RichTextRun{{Text: "Vi\u00D6t ", Font: &excelize.Font{Family: "VNI-Times", Bold: true}}, {Text: "Nam"}}
+	if err := f.SetCellRichText("Sheet1", "B1", runs); err != nil {
+		t.Fatalf("SetCellRichText failed: %v", err)
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	_ = f.Close()
+
+	proc := NewProcessor(inputFile, "")
+	proc.SetPlainCells(true)
+	outputFile, err := proc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+
+	out, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+	for _, axis := range []string{"A1", "A2", "B1"} {
+		if got, _ := out.GetCellValue("Sheet1", axis); got != "Việt Nam" {
+			t.Errorf("%s = %q, want Việt Nam", axis, got)
+		}
+	}
+	if runs, _ := out.GetCellRichText("Sheet1", "A1"); hasAnyRunFont(runs) {
+		t.Errorf("A1 was written as rich text: %+v", runs)
+	}
+	if runs, _ := out.GetCellRichText("Sheet1", "B1"); !hasAnyRunFont(runs) {
+		t.Errorf("B1 lost its rich text: %+v", runs)
+	}
+
+	styleA1, _ := out.GetCellStyle("Sheet1", "A1")
+	styleA2, _ := out.GetCellStyle("Sheet1", "A2")
+	if styleA1 != styleA2 {
+		t.Errorf("A1 and A2 got styles %d and %d, want one shared style", styleA1, styleA2)
+	}
+	got, err := out.GetStyle(styleA1)
+	if err != nil || got.Font == nil {
+		t.Fatalf("GetStyle(%d) = %+v, %v", styleA1, got, err)
+	}
+	if got.Font.Family != "Times New Roman" || got.Font.Size != 14 || !got.Font.Bold {
+		t.Errorf("A1 style font = %+v, want bold 14pt Times New Roman", *got.Font)
+	}
+}
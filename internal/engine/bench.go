@@ -0,0 +1,157 @@
+package engine
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// BenchEncodings lists the converters exercised by default.
+var BenchEncodings = []converter.EncodingType{
+	converter.EncodingVNI,
+	converter.EncodingTCVN3,
+	converter.EncodingVNIDOS,
+	converter.EncodingVNU,
+}
+
+// BenchResult is the throughput of one converter over the sample set.
+type BenchResult struct {
+	Encoding   converter.EncodingType
+	Iterations int
+	Cells      int   // Cells converted across all iterations
+	Bytes      int64 // Input bytes converted across all iterations
+	Elapsed    time.Duration
+}
+
+// MBPerSec returns input throughput in megabytes per second.
+func (r BenchResult) MBPerSec() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Bytes) / (1 << 20) / r.Elapsed.Seconds()
+}
+
+// CellsPerSec returns the number of converted cells per second.
+func (r BenchResult) CellsPerSec() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Cells) / r.Elapsed.Seconds()
+}
+
+// LoadBenchSamples reads benchmark input from a workbook (every non-empty cell)
+// or a text file (every non-empty line).
+func LoadBenchSamples(path string) ([]string, error) {
+	if IsWorkbook(path) {
+		return loadWorkbookSamples(path)
+	}
+	f, err := os.Open(path) //nolint:gosec // path is provided by the user on the command line
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sample file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var samples []string
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		if line := sc.Text(); strings.TrimSpace(line) != "" {
+			samples = append(samples, line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read sample file: %w", err)
+	}
+	return samples, nil
+}
+
+func loadWorkbookSamples(path string) ([]string, error) {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workbook: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var samples []string
+	for _, sheet := range f.GetSheetList() {
+		rows, err := f.GetRows(sheet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sheet %s: %w", sheet, err)
+		}
+		for _, row := range rows {
+			for _, text := range row {
+				if strings.TrimSpace(text) != "" {
+					samples = append(samples, text)
+				}
+			}
+		}
+	}
+	return samples, nil
+}
+
+// RunBenchmark converts samples iterations times with each converter.
+// Why: Lets users compare machines and report field performance numbers.
+func RunBenchmark(ctx context.Context, samples []string, encodings []converter.EncodingType,
+	iterations int) ([]BenchResult, error) {
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no samples to benchmark")
+	}
+	if iterations < 1 {
+		return nil, fmt.Errorf("iterations must be at least 1, got %d", iterations)
+	}
+
+	var sampleBytes int64
+	for _, s := range samples {
+		sampleBytes += int64(len(s))
+	}
+
+	results := make([]BenchResult, 0, len(encodings))
+	for _, enc := range encodings {
+		conv, err := converter.NewConverter(enc)
+		if err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		for i := 0; i < iterations; i++ {
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("benchmark cancelled: %w", err)
+			}
+			for _, s := range samples {
+				_ = conv.ToUnicode(s)
+			}
+		}
+		results = append(results, BenchResult{
+			Encoding:   enc,
+			Iterations: iterations,
+			Cells:      len(samples) * iterations,
+			Bytes:      sampleBytes * int64(iterations),
+			Elapsed:    time.Since(start),
+		})
+	}
+	return results, nil
+}
+
+// WriteBenchResults prints results as an aligned table.
+func WriteBenchResults(w io.Writer, results []BenchResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	if _, err := fmt.Fprintln(tw, "encoding\tcells\tbytes\telapsed\tMB/s\tcells/s\t"); err != nil {
+		return fmt.Errorf("failed to write bench header: %w", err)
+	}
+	for _, r := range results {
+		_, err := fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%.2f\t%.0f\t\n",
+			r.Encoding, r.Cells, r.Bytes, r.Elapsed.Round(time.Microsecond), r.MBPerSec(), r.CellsPerSec())
+		if err != nil {
+			return fmt.Errorf("failed to write bench result: %w", err)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+func TestLoadBenchSamples(t *testing.T) {
+	dir := t.TempDir()
+
+	textPath := filepath.Join(dir, "samples.txt")
+	if err := os.WriteFile(textPath, []byte("Vi\u00D6t Nam\n\n  \nC\u00ABng ty\n"), 0600); err != nil {
+		t.Fatalf("failed to write text samples: %v", err)
+	}
+	bookPath := filepath.Join(dir, "samples.xlsx")
+	writeWorkbook(t, bookPath, map[string]string{"A1": "Vi\u00D6t Nam", "B2": "C\u00ABng ty", "C3": " "})
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{name: "Text file", path: textPath, want: 2},
+		{name: "Workbook", path: bookPath, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadBenchSamples(tt.path)
+			if err != nil {
+				t.Fatalf("LoadBenchSamples failed: %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("LoadBenchSamples() returned %d samples, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunBenchmark(t *testing.T) {
+	samples := []string{"Vi\u00D6t Nam", "abc"}
+	results, err := RunBenchmark(context.Background(), samples, BenchEncodings, 3)
+	if err != nil {
+		t.Fatalf("RunBenchmark failed: %v", err)
+	}
+	if len(results) != len(BenchEncodings) {
+		t.Fatalf("got %d results, want %d", len(results), len(BenchEncodings))
+	}
+	for _, r := range results {
+		if r.Cells != 6 || r.Bytes != 3*int64(len(samples[0])+len(samples[1])) {
+			t.Errorf("%s: cells=%d bytes=%d", r.Encoding, r.Cells, r.Bytes)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBenchResults(&buf, results); err != nil {
+		t.Fatalf("WriteBenchResults failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "TCVN3") {
+		t.Errorf("table missing TCVN3 row:\n%s", buf.String())
+	}
+}
+
+func TestRunBenchmark_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		samples    []string
+		encodings  []converter.EncodingType
+		iterations int
+	}{
+		{name: "No samples", samples: nil, encodings: BenchEncodings, iterations: 1},
+		{name: "Zero iterations", samples: []string{"a"}, encodings: BenchEncodings, iterations: 0},
+		{name: "Unknown encoding", samples: []string{"a"}, encodings: []converter.EncodingType{"X"}, iterations: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RunBenchmark(context.Background(), tt.samples, tt.encodings, tt.iterations); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestBenchResult_Rates(t *testing.T) {
+	r := BenchResult{Cells: 100, Bytes: 1 << 20, Elapsed: time.Second}
+	if r.MBPerSec() != 1 || r.CellsPerSec() != 100 {
+		t.Errorf("MBPerSec()=%v CellsPerSec()=%v", r.MBPerSec(), r.CellsPerSec())
+	}
+	if (BenchResult{}).MBPerSec() != 0 {
+		t.Error("zero elapsed must not divide by zero")
+	}
+}
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// findBrokenSheets records in p.broken every sheet of the workbook that excelize cannot
+// parse, with the parse error.
+// Why: The row iterator stops silently at the first XML error, so a corrupt sheet used to
+// come back half converted with no error, and a missing part aborted later passes. Broken
+// sheets are now left out of every pass, which keeps their XML byte for byte in the output.
+func (p *Processor) findBrokenSheets() {
+	p.broken = make(map[string]error)
+	for _, sheet := range p.f.GetSheetList() {
+		if err := p.parseSheet(sheet); err != nil {
+			slog.Warn("sheet cannot be parsed, copying it unchanged", "sheet", sheet, "error", err)
+			p.broken[sheet] = err
+		}
+	}
+}
+
+// parseSheet reads the whole worksheet XML of sheet and returns the first parse error.
+func (p *Processor) parseSheet(sheet string) error {
+	rows, err := p.f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	// Any cell lookup decodes the whole worksheet, which Rows leaves for later
+	if _, err := p.f.GetCellType(sheet, "A1"); err != nil {
+		// Chart sheets have no cells, but nothing is wrong with them
+		if strings.HasSuffix(err.Error(), "is not a worksheet") {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// skipBrokenSheets returns sheets without the broken ones, which are reported as errors
+// and listed by BrokenSheets.
+func (p *Processor) skipBrokenSheets(sheets []string) []string {
+	p.brokenSheets = nil
+	parsed := make([]string, 0, len(sheets))
+	for _, sheet := range sheets {
+		if err, ok := p.broken[sheet]; ok {
+			p.brokenSheets = append(p.brokenSheets, sheet)
+			p.errs.add(sheet, "", StageParse, err)
+			continue
+		}
+		parsed = append(parsed, sheet)
+	}
+	return parsed
+}
+
+// BrokenSheets returns the sheets the last Run could not parse. They were copied to the
+// output unchanged while the other sheets were converted.
+func (p *Processor) BrokenSheets() []string {
+	out := make([]string, len(p.brokenSheets))
+	copy(out, p.brokenSheets)
+	return out
+}
@@ -0,0 +1,140 @@
+package engine
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// rewriteZipPart replaces the part name of the xlsx at path with edit's result, or removes
+// it when edit returns nil.
+func rewriteZipPart(t *testing.T, path, name string, edit func([]byte) []byte) {
+	t.Helper()
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("failed to open zip: %v", err)
+	}
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, file := range r.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", file.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file.Name, err)
+		}
+		if file.Name == name {
+			if data = edit(data); data == nil {
+				continue
+			}
+		}
+		fw, err := w.Create(file.Name)
+		if err != nil {
+			t.Fatalf("failed to create %s: %v", file.Name, err)
+		}
+		if _, err := fw.Write(data); err != nil {
+			t.Fatalf("failed to write %s: %v", file.Name, err)
+		}
+	}
+	_ = r.Close()
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("failed to save zip: %v", err)
+	}
+}
+
+// readZipPart returns the part name of the xlsx at path, or nil when it is missing.
+func readZipPart(t *testing.T, path, name string) []byte {
+	t.Helper()
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("failed to open zip: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+	rc, err := r.Open(name)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", name, err)
+	}
+	return data
+}
+
+func TestRunKeepsBrokenSheet(t *testing.T) {
+	const part = "xl/worksheets/sheet2.xml"
+	tests := []struct {
+		name string
+		edit func([]byte) []byte
+	}{
+		{
+			name: "Invalid XML",
+			edit: func(b []byte) []byte {
+				return []byte(strings.Replace(string(b), "</sheetData>", "<row r=\"9\"><c r=\"A9\"></sheetData>", 1))
+			},
+		},
+		{
+			name: "Invalid row number",
+			edit: func(b []byte) []byte { return []byte(strings.Replace(string(b), `<row r="1"`, `<row r="x"`, 1)) },
+		},
+		{
+			name: "Missing part",
+			edit: func([]byte) []byte { return nil },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := filepath.Join(t.TempDir(), "input.xlsx")
+			f := excelize.NewFile()
+			_ = f.SetCellValue("Sheet1", "A1", "Vi\u00D6t Nam")
+			_, _ = f.NewSheet("Sheet2")
+			_ = f.SetCellValue("Sheet2", "A1", "Vi\u00D6t Nam")
+			if err := f.SaveAs(input); err != nil {
+				t.Fatalf("failed to save input: %v", err)
+			}
+			_ = f.Close()
+			rewriteZipPart(t, input, part, tt.edit)
+			want := readZipPart(t, input, part)
+
+			p := NewProcessor(input, "")
+			output, err := p.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if got := p.BrokenSheets(); !reflect.DeepEqual(got, []string{"Sheet2"}) {
+				t.Errorf("BrokenSheets() = %q, want [Sheet2]", got)
+			}
+			errs := p.CellErrors()
+			if len(errs) != 1 || errs[0].SheetName != "Sheet2" || errs[0].Stage != StageParse {
+				t.Errorf("CellErrors() = %v, want one parse error of Sheet2", errs)
+			}
+
+			out, err := excelize.OpenFile(output)
+			if err != nil {
+				t.Fatalf("failed to open output: %v", err)
+			}
+			defer func() { _ = out.Close() }()
+			if got, _ := out.GetCellValue("Sheet1", "A1"); got != "Vi\u1EC7t Nam" {
+				t.Errorf("Sheet1!A1 = %q, want it converted", got)
+			}
+			if got := readZipPart(t, output, part); !bytes.Equal(got, want) {
+				t.Errorf("broken sheet changed:\n got %q\nwant %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// excelizeModule is the module path used to look up the excelize version.
+const excelizeModule = "github.com/xuri/excelize/v2"
+
+// BuildInfo identifies the converter build that produced an output.
+// Why: Support must be able to tell which build produced a questionable file.
+type BuildInfo struct {
+	AppVersion      string
+	GOOS            string
+	GOARCH          string
+	GoVersion       string
+	ExcelizeVersion string
+}
+
+// NewBuildInfo collects the runtime environment for the given app version.
+func NewBuildInfo(appVersion string) BuildInfo {
+	info := BuildInfo{
+		AppVersion:      appVersion,
+		GOOS:            runtime.GOOS,
+		GOARCH:          runtime.GOARCH,
+		GoVersion:       runtime.Version(),
+		ExcelizeVersion: "unknown",
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range bi.Deps {
+			if dep.Path == excelizeModule {
+				info.ExcelizeVersion = dep.Version
+				break
+			}
+		}
+	}
+	return info
+}
+
+// String returns a one-line summary suitable for report and log headers.
+func (b BuildInfo) String() string {
+	return fmt.Sprintf("VniConverter %s (%s/%s, %s, excelize %s)",
+		b.AppVersion, b.GOOS, b.GOARCH, b.GoVersion, b.ExcelizeVersion)
+}
+
+// LogAttrs returns the build info as slog key/value pairs.
+func (b BuildInfo) LogAttrs() []any {
+	return []any{
+		"version", b.AppVersion,
+		"os", b.GOOS,
+		"arch", b.GOARCH,
+		"go", b.GoVersion,
+		"excelize", b.ExcelizeVersion,
+	}
+}
+
+// properties returns the build info as workbook custom properties.
+func (b BuildInfo) properties() map[string]string {
+	return map[string]string{
+		"ConverterVersion":  b.AppVersion,
+		"ConverterPlatform": b.GOOS + "/" + b.GOARCH,
+		"ConverterExcelize": b.ExcelizeVersion,
+	}
+}
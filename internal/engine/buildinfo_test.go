@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestBuildInfo_String(t *testing.T) {
+	b := BuildInfo{
+		AppVersion:      "v1.2.3",
+		GOOS:            "windows",
+		GOARCH:          "amd64",
+		GoVersion:       "go1.25.6",
+		ExcelizeVersion: "v2.10.0",
+	}
+	want := "VniConverter v1.2.3 (windows/amd64, go1.25.6, excelize v2.10.0)"
+	if got := b.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestTimingRecorder_BuildHeader(t *testing.T) {
+	rec := NewTimingRecorder()
+	rec.SetBuildInfo(BuildInfo{AppVersion: "v1.2.3"})
+
+	var buf bytes.Buffer
+	if err := rec.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "# VniConverter v1.2.3") {
+		t.Errorf("missing build header: %q", buf.String())
+	}
+
+	r := csv.NewReader(&buf)
+	r.Comment = '#'
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse csv: %v", err)
+	}
+	if len(records) != 1 {
+		t.Errorf("record count mismatch. Got %d, want 1", len(records))
+	}
+}
+
+func TestProcessor_StampsBuildInfo(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "stamp.xlsx")
+	f := excelize.NewFile()
+	if err := f.SetCellValue("Sheet1", "A1", "Vi\u00D6t Nam"); err != nil {
+		t.Fatalf("failed to set A1: %v", err)
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save input: %v", err)
+	}
+	_ = f.Close()
+
+	proc := NewProcessor(inputFile, "")
+	proc.SetBuildInfo(BuildInfo{AppVersion: "v9.9.9", GOOS: "windows", GOARCH: "arm64"})
+	outputFile, err := proc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	fOut, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = fOut.Close() }()
+
+	props, err := fOut.GetCustomProps()
+	if err != nil {
+		t.Fatalf("GetCustomProps failed: %v", err)
+	}
+	found := map[string]any{}
+	for _, prop := range props {
+		found[prop.Name] = prop.Value
+	}
+	if found["ConverterVersion"] != "v9.9.9" {
+		t.Errorf("ConverterVersion = %v, want v9.9.9", found["ConverterVersion"])
+	}
+	if found["ConverterPlatform"] != "windows/arm64" {
+		t.Errorf("ConverterPlatform = %v, want windows/arm64", found["ConverterPlatform"])
+	}
+}
@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Stages of a Run at which a CellError can happen.
+const (
+	StageRead     = "read"     // reading rows, columns or coordinates of a sheet
+	StageConvert  = "convert"  // converting the text of a cell
+	StageWrite    = "write"    // writing a converted cell back
+	StageFormula  = "formula"  // rewriting a formula after a sheet rename
+	StageRename   = "rename"   // renaming a legacy-encoded sheet
+	StageComments = "comments" // reading the comments of a sheet
+	StageParse    = "parse"    // parsing the worksheet XML of a sheet
+)
+
+// maxCellErrors caps the errors kept per Run; the rest are only counted.
+// Why: A corrupt sheet can fail on every one of its cells.
+const maxCellErrors = 1000
+
+// CellError records a cell, row or sheet that failed and was left unconverted.
+type CellError struct {
+	SheetName string
+	// Axis is a cell ("B7"), a row ("7:7") or empty when the whole sheet failed
+	Axis  string
+	Stage string
+	Err   error
+}
+
+func (e CellError) Error() string {
+	where := e.SheetName
+	if e.Axis != "" {
+		where += "!" + e.Axis
+	}
+	return fmt.Sprintf("%s: %s failed: %v", where, e.Stage, e.Err)
+}
+
+func (e CellError) Unwrap() error {
+	return e.Err
+}
+
+// cellErrorJSON is the JSON form of a CellError; the error is sent as its message.
+type cellErrorJSON struct {
+	SheetName string `json:"sheetName"`
+	Axis      string `json:"axis,omitempty"`
+	Stage     string `json:"stage"`
+	Error     string `json:"error"`
+}
+
+// MarshalJSON encodes the error as its message, so the UI can show it.
+func (e CellError) MarshalJSON() ([]byte, error) {
+	msg := ""
+	if e.Err != nil {
+		msg = e.Err.Error()
+	}
+	return json.Marshal(cellErrorJSON{SheetName: e.SheetName, Axis: e.Axis, Stage: e.Stage, Error: msg})
+}
+
+// UnmarshalJSON decodes a CellError encoded by MarshalJSON.
+func (e *CellError) UnmarshalJSON(data []byte) error {
+	var v cellErrorJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*e = CellError{SheetName: v.SheetName, Axis: v.Axis, Stage: v.Stage}
+	if v.Error != "" {
+		e.Err = errors.New(v.Error)
+	}
+	return nil
+}
+
+// cellErrors collects the CellErrors of a Run. The dispatcher and the collector both add
+// to it, so it is locked.
+type cellErrors struct {
+	mu      sync.Mutex
+	list    []CellError
+	dropped int
+}
+
+// add records a failure, or only counts it once maxCellErrors are kept.
+func (c *cellErrors) add(sheet, axis, stage string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.list) >= maxCellErrors {
+		if c.dropped == 0 {
+			slog.Warn("too many cell errors, only counting the rest", "kept", maxCellErrors)
+		}
+		c.dropped++
+		return
+	}
+	c.list = append(c.list, CellError{SheetName: sheet, Axis: axis, Stage: stage, Err: err})
+}
+
+// reset forgets the errors of the previous Run.
+func (c *cellErrors) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.list = nil
+	c.dropped = 0
+}
+
+// snapshot returns a copy of the kept errors.
+func (c *cellErrors) snapshot() []CellError {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]CellError, len(c.list))
+	copy(out, c.list)
+	return out
+}
+
+// rowAxis returns the Excel reference of a whole row, e.g. "7:7".
+func rowAxis(row int) string {
+	return fmt.Sprintf("%d:%d", row, row)
+}
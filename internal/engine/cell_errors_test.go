@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestCellError_JSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   CellError
+		want string
+	}{
+		{
+			name: "Cell",
+			in:   CellError{SheetName: "Sheet1", Axis: "B7", Stage: StageWrite, Err: errors.New("disk full")},
+			want: `{"sheetName":"Sheet1","axis":"B7","stage":"write","error":"disk full"}`,
+		},
+		{
+			name: "Sheet",
+			in:   CellError{SheetName: "Sheet1", Stage: StageRead, Err: errors.New("bad xml")},
+			want: `{"sheetName":"Sheet1","stage":"read","error":"bad xml"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.in)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal = %s, want %s", data, tt.want)
+			}
+			var got CellError
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if got.SheetName != tt.in.SheetName || got.Axis != tt.in.Axis || got.Stage != tt.in.Stage || got.Err.Error() != tt.in.Err.Error() {
+				t.Errorf("Unmarshal = %+v, want %+v", got, tt.in)
+			}
+		})
+	}
+}
+
+func TestCellErrors_Cap(t *testing.T) {
+	var c cellErrors
+	cause := errors.New("boom")
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < maxCellErrors; j++ {
+				c.add("Sheet1", "A1", StageConvert, cause)
+			}
+		}()
+	}
+	wg.Wait()
+
+	got := c.snapshot()
+	if len(got) != maxCellErrors || c.dropped != maxCellErrors {
+		t.Errorf("kept %d and dropped %d, want %d of each", len(got), c.dropped, maxCellErrors)
+	}
+	if !errors.Is(got[0], cause) {
+		t.Errorf("errors.Is(%v, cause) = false, want true", got[0])
+	}
+
+	c.reset()
+	if got := c.snapshot(); len(got) != 0 {
+		t.Errorf("after reset kept %d errors, want 0", len(got))
+	}
+}
+
+func TestBatchWriter_RecordsWriteErrors(t *testing.T) {
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+
+	var errs cellErrors
+	var mu sync.Mutex
+	w := newBatchWriter(f, &mu, 10)
+	w.errs = &errs
+	runs := []excelize.RichTextRun{{Text: "Việt Nam"}}
+	w.add(Result{Job: Job{SheetName: "Sheet1", Axis: "A1", Row: 1, Col: 1}, NewRuns: runs})
+	w.add(Result{Job: Job{SheetName: "Missing", Axis: "B2", Row: 2, Col: 2}, NewRuns: runs})
+	w.flush()
+
+	got := errs.snapshot()
+	if len(got) != 1 || got[0].SheetName != "Missing" || got[0].Axis != "B2" || got[0].Stage != StageWrite || got[0].Err == nil {
+		t.Errorf("errors = %+v, want one write error for Missing!B2", got)
+	}
+	if value, _ := f.GetCellValue("Sheet1", "A1"); value != "Việt Nam" {
+		t.Errorf("A1 = %q, want the other cell still written", value)
+	}
+}
@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// CellFilter restricts conversion to a rectangle of cells and/or a set of columns.
+// Both restrictions apply to every converted sheet; a cell must satisfy both.
+// Why: Users convert a description column without touching ID or code columns, which
+// may hold legacy-looking text that must stay byte-for-byte identical.
+type CellFilter struct {
+	// Inclusive rectangle; all zero when no range is set
+	startCol, startRow, endCol, endRow int
+	// columns holds the allowed column numbers; nil allows every column
+	columns map[int]bool
+	// spec is the normalized "range=...;columns=..." text, for settings fingerprints
+	spec string
+}
+
+// ParseCellFilter parses a cell range ("A1:D500" or a single cell "B2") and a column list
+// ("C", "C,E" or "C:E,H"). Either may be empty; a nil filter is returned when both are.
+func ParseCellFilter(cellRange, columns string) (*CellFilter, error) {
+	cellRange = strings.ToUpper(strings.TrimSpace(cellRange))
+	columns = strings.ToUpper(strings.ReplaceAll(columns, " ", ""))
+	if cellRange == "" && columns == "" {
+		return nil, nil
+	}
+
+	f := &CellFilter{spec: fmt.Sprintf("range=%s;columns=%s", cellRange, columns)}
+	if cellRange != "" {
+		first, last, found := strings.Cut(cellRange, ":")
+		if !found {
+			last = first
+		}
+		var err error
+		if f.startCol, f.startRow, err = excelize.CellNameToCoordinates(first); err != nil {
+			return nil, fmt.Errorf("invalid cell range %q: %w", cellRange, err)
+		}
+		if f.endCol, f.endRow, err = excelize.CellNameToCoordinates(last); err != nil {
+			return nil, fmt.Errorf("invalid cell range %q: %w", cellRange, err)
+		}
+		// Accept the corners in any order, as Excel does
+		f.startCol, f.endCol = min(f.startCol, f.endCol), max(f.startCol, f.endCol)
+		f.startRow, f.endRow = min(f.startRow, f.endRow), max(f.startRow, f.endRow)
+	}
+
+	if columns != "" {
+		f.columns = make(map[int]bool)
+		for _, part := range strings.Split(columns, ",") {
+			first, last, found := strings.Cut(part, ":")
+			if !found {
+				last = first
+			}
+			from, err := excelize.ColumnNameToNumber(first)
+			if err != nil {
+				return nil, fmt.Errorf("invalid column %q: %w", part, err)
+			}
+			to, err := excelize.ColumnNameToNumber(last)
+			if err != nil {
+				return nil, fmt.Errorf("invalid column %q: %w", part, err)
+			}
+			for col := min(from, to); col <= max(from, to); col++ {
+				f.columns[col] = true
+			}
+		}
+	}
+	return f, nil
+}
+
+// Contains reports whether the cell at col, row (1-based) may be converted.
+// A nil filter contains every cell.
+func (f *CellFilter) Contains(col, row int) bool {
+	if f == nil {
+		return true
+	}
+	if f.endRow > 0 && (col < f.startCol || col > f.endCol || row < f.startRow || row > f.endRow) {
+		return false
+	}
+	return f.columns == nil || f.columns[col]
+}
+
+// ContainsRef is Contains for a cell reference such as "B2"; invalid references are outside.
+func (f *CellFilter) ContainsRef(ref string) bool {
+	if f == nil {
+		return true
+	}
+	col, row, err := excelize.CellNameToCoordinates(ref)
+	return err == nil && f.Contains(col, row)
+}
+
+// pastLastRow reports whether row and every row after it are outside the filter.
+// Why: Scanning stops early instead of walking the rest of a huge sheet.
+func (f *CellFilter) pastLastRow(row int) bool {
+	return f != nil && f.endRow > 0 && row > f.endRow
+}
+
+// String returns the normalized filter, or "" for a nil filter.
+func (f *CellFilter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.spec
+}
+
+// SetCellFilter restricts conversion to the cells of f (nil converts every cell).
+// Cell comments outside the filter are left alone too; charts and sheet names are not cells
+// and follow their own settings.
+func (p *Processor) SetCellFilter(f *CellFilter) {
+	p.cellFilter = f
+}
@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseCellFilter(t *testing.T) {
+	type cell struct{ col, row int }
+	tests := []struct {
+		name      string
+		cellRange string
+		columns   string
+		wantNil   bool
+		wantErr   bool
+		inside    []cell
+		outside   []cell
+	}{
+		{name: "empty", wantNil: true},
+		{
+			name:      "range",
+			cellRange: "a1:d500",
+			inside:    []cell{{1, 1}, {4, 500}, {2, 250}},
+			outside:   []cell{{5, 1}, {1, 501}},
+		},
+		{
+			name:      "reversed corners",
+			cellRange: "D500:A1",
+			inside:    []cell{{1, 1}, {4, 500}},
+			outside:   []cell{{5, 500}},
+		},
+		{
+			name:      "single cell",
+			cellRange: "B2",
+			inside:    []cell{{2, 2}},
+			outside:   []cell{{2, 3}, {1, 2}},
+		},
+		{
+			name:    "columns",
+			columns: "C, E:F",
+			inside:  []cell{{3, 1}, {5, 1000}, {6, 7}},
+			outside: []cell{{1, 1}, {4, 1}, {7, 1}},
+		},
+		{
+			name:      "range and columns",
+			cellRange: "A2:Z100",
+			columns:   "C",
+			inside:    []cell{{3, 2}},
+			outside:   []cell{{3, 1}, {4, 2}},
+		},
+		{name: "bad range", cellRange: "A1:", wantErr: true},
+		{name: "bad column", columns: "C,1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseCellFilter(tt.cellRange, tt.columns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCellFilter error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (f == nil) != tt.wantNil {
+				t.Fatalf("ParseCellFilter = %v, wantNil %v", f, tt.wantNil)
+			}
+			for _, c := range tt.inside {
+				if !f.Contains(c.col, c.row) {
+					t.Errorf("Contains(%d, %d) = false, want true", c.col, c.row)
+				}
+			}
+			for _, c := range tt.outside {
+				if f.Contains(c.col, c.row) {
+					t.Errorf("Contains(%d, %d) = true, want false", c.col, c.row)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessor_RunWithCellFilter(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "codes.xlsx")
+	writeWorkbook(t, inputFile, map[string]string{
+		"A1": "Vi\u00D6t", // ID column: must stay as typed
+		"B1": "Vi\u00D6t Nam",
+		"B9": "Vi\u00D6t Nam",
+	})
+
+	filter, err := ParseCellFilter("A1:D5", "B")
+	if err != nil {
+		t.Fatalf("ParseCellFilter failed: %v", err)
+	}
+	proc := NewProcessor(inputFile, "")
+	proc.SetCellFilter(filter)
+	outputFile, err := proc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+
+	f, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	want := map[string]string{
+		"A1": "Vi\u00D6t",
+		"B1": "Việt Nam",
+		"B9": "Vi\u00D6t Nam",
+	}
+	for axis, text := range want {
+		if got, _ := f.GetCellValue("Sheet1", axis); got != text {
+			t.Errorf("%s = %q, want %q", axis, got, text)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// MaxExcelCellLength is the maximum number of characters Excel accepts in a cell.
+// Excel counts UTF-16 code units, so characters outside the BMP count twice.
+const MaxExcelCellLength = 32767
+
+// TruncatedCell is a cell whose converted text was cut to fit MaxExcelCellLength.
+type TruncatedCell struct {
+	SheetName string `json:"sheetName"`
+	Axis      string `json:"axis"`
+	Length    int    `json:"length"` // converted length before truncation
+}
+
+// utf16Len returns the length of s in UTF-16 code units.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// truncateRuns cuts runs so their combined text fits within limit.
+// It returns the runs, the original length and whether anything was cut.
+// Why: Excel refuses to open a workbook with an oversized cell, so losing the tail
+// (and reporting it) is the lesser evil.
+func truncateRuns(runs []excelize.RichTextRun, limit int) ([]excelize.RichTextRun, int, bool) {
+	total := 0
+	for _, run := range runs {
+		total += utf16Len(run.Text)
+	}
+	if total <= limit {
+		return runs, total, false
+	}
+
+	out := make([]excelize.RichTextRun, 0, len(runs))
+	remaining := limit
+	for _, run := range runs {
+		if remaining == 0 {
+			break
+		}
+		text, used := cutText(run.Text, remaining)
+		remaining -= used
+		if text == "" {
+			// Nothing of this run fits; keep it only if it is the first run so the cell stays rich
+			if len(out) == 0 {
+				run.Text = text
+				out = append(out, run)
+			}
+			break
+		}
+		run.Text = text
+		out = append(out, run)
+	}
+	return out, total, true
+}
+
+// cutText returns the longest prefix of s within limit UTF-16 units that does not
+// split a character from its combining marks, plus the units it uses.
+func cutText(s string, limit int) (string, int) {
+	used, end := 0, 0
+	for i, r := range s {
+		w := 1
+		if r >= 0x10000 {
+			w = 2
+		}
+		if used+w > limit {
+			break
+		}
+		used += w
+		end = i + utf8.RuneLen(r)
+	}
+	if end == len(s) {
+		return s, used
+	}
+	// Back off while the next rune would attach to the last kept one
+	for end > 0 {
+		next, _ := utf8.DecodeRuneInString(s[end:])
+		if !unicode.Is(unicode.Mn, next) {
+			break
+		}
+		last, size := utf8.DecodeLastRuneInString(s[:end])
+		end -= size
+		used -= utf16Len(string(last))
+	}
+	return s[:end], used
+}
@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestCutText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		want     string
+		wantUsed int
+	}{
+		{name: "Fits", text: "abc", limit: 5, want: "abc", wantUsed: 3},
+		{name: "Cut ASCII", text: "abcdef", limit: 4, want: "abcd", wantUsed: 4},
+		{name: "Precomposed Vietnamese", text: "Vi\u1EC7t Nam", limit: 4, want: "Vi\u1EC7t", wantUsed: 4},
+		{name: "Combining marks kept with base", text: "Vie\u0323\u0302t", limit: 4, want: "Vi", wantUsed: 2},
+		{name: "Astral rune counts twice", text: "a\U0001F600b", limit: 2, want: "a", wantUsed: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, used := cutText(tt.text, tt.limit)
+			if got != tt.want || used != tt.wantUsed {
+				t.Errorf("cutText() = %q, %d; want %q, %d", got, used, tt.want, tt.wantUsed)
+			}
+		})
+	}
+}
+
+func TestTruncateRuns(t *testing.T) {
+	bold := &excelize.Font{Bold: true}
+	tests := []struct {
+		name      string
+		runs      []excelize.RichTextRun
+		limit     int
+		wantTexts []string
+		wantCut   bool
+	}{
+		{
+			name:      "Within limit",
+			runs:      []excelize.RichTextRun{{Text: "ab"}, {Text: "cd", Font: bold}},
+			limit:     4,
+			wantTexts: []string{"ab", "cd"},
+		},
+		{
+			name:      "Cut inside second run",
+			runs:      []excelize.RichTextRun{{Text: "ab"}, {Text: "cdef", Font: bold}},
+			limit:     4,
+			wantTexts: []string{"ab", "cd"},
+			wantCut:   true,
+		},
+		{
+			name:      "Later runs dropped",
+			runs:      []excelize.RichTextRun{{Text: "abcd"}, {Text: "ef", Font: bold}},
+			limit:     3,
+			wantTexts: []string{"abc"},
+			wantCut:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, cut := truncateRuns(tt.runs, tt.limit)
+			if cut != tt.wantCut {
+				t.Fatalf("truncated = %v, want %v", cut, tt.wantCut)
+			}
+			texts := make([]string, len(got))
+			for i, r := range got {
+				texts[i] = r.Text
+			}
+			if strings.Join(texts, "|") != strings.Join(tt.wantTexts, "|") {
+				t.Errorf("runs = %q, want %q", texts, tt.wantTexts)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// TestProcessor_NonTextCellsUntouched checks that numbers, dates, booleans and formulas in a
+// legacy font keep their type and render identically, including Vietnamese number formats.
+func TestProcessor_NonTextCellsUntouched(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "numbers.xlsx")
+	f := excelize.NewFile()
+	font := &excelize.Font{Family: "VNI-Times"}
+	formats := []string{
+		`[$-42A]#,##0.00`,           // Vietnamese locale: renders with comma decimal separator
+		"#,##0 \"\u00F1o\u00E0ng\"", // VNI-encoded currency suffix
+		`dd/mm/yyyy`,
+		`General`,
+	}
+	cells := []struct {
+		axis   string
+		value  any
+		format int
+	}{
+		{axis: "A1", value: 1234.56, format: 0},
+		{axis: "A2", value: 1234567, format: 1},
+		{axis: "A3", value: 45292, format: 2},
+		{axis: "A4", value: true, format: 3},
+		{axis: "A5", value: -0.5, format: 0},
+	}
+	for _, c := range cells {
+		style, err := f.NewStyle(&excelize.Style{Font: font, CustomNumFmt: &formats[c.format]})
+		if err != nil {
+			t.Fatalf("failed to create style: %v", err)
+		}
+		if err := f.SetCellValue("Sheet1", c.axis, c.value); err != nil {
+			t.Fatalf("failed to set %s: %v", c.axis, err)
+		}
+		if err := f.SetCellStyle("Sheet1", c.axis, c.axis, style); err != nil {
+			t.Fatalf("failed to style %s: %v", c.axis, err)
+		}
+	}
+	if err := f.SetCellFormula("Sheet1", "A6", "A1*2"); err != nil {
+		t.Fatalf("failed to set formula: %v", err)
+	}
+	// A text cell in the same font must still be converted
+	if err := f.SetCellValue("Sheet1", "B1", "Vi\u00D6t Nam"); err != nil {
+		t.Fatalf("failed to set text: %v", err)
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	_ = f.Close()
+
+	axes := []string{"A1", "A2", "A3", "A4", "A5", "A6"}
+	before := snapshotCells(t, inputFile, axes)
+
+	proc := NewProcessor(inputFile, "")
+	status := make(chan Status, 10)
+	proc.SetStatusChan(status)
+	outputFile, err := proc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+	close(status)
+	for st := range status {
+		if st.Total != 1 || st.Percent != 100 {
+			t.Errorf("Status = %+v, want only the text cell counted", st)
+		}
+	}
+	after := snapshotCells(t, outputFile, axes)
+
+	for _, axis := range axes {
+		t.Run(axis, func(t *testing.T) {
+			if after[axis] != before[axis] {
+				t.Errorf("cell changed:\n got  %+v\n want %+v", after[axis], before[axis])
+			}
+		})
+	}
+
+	fOut, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = fOut.Close() }()
+	if got, _ := fOut.GetCellValue("Sheet1", "B1"); got != "Việt Nam" {
+		t.Errorf("B1 = %q, want converted text", got)
+	}
+}
+
+// cellSnapshot is what a reader sees of a cell: its type, stored and displayed value and formula.
+type cellSnapshot struct {
+	Type      excelize.CellType
+	Raw       string
+	Displayed string
+	Formula   string
+}
+
+func snapshotCells(t *testing.T, path string, axes []string) map[string]cellSnapshot {
+	t.Helper()
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer func() { _ = f.Close() }()
+	snapshot := make(map[string]cellSnapshot, len(axes))
+	for _, axis := range axes {
+		var s cellSnapshot
+		s.Type, _ = f.GetCellType("Sheet1", axis)
+		s.Raw, _ = f.GetCellValue("Sheet1", axis, excelize.Options{RawCellValue: true})
+		s.Displayed, _ = f.GetCellValue("Sheet1", axis)
+		s.Formula, _ = f.GetCellFormula("Sheet1", axis)
+		snapshot[axis] = s
+	}
+	return snapshot
+}
@@ -0,0 +1,189 @@
+package engine
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ReportFormat is the file format of a change report.
+type ReportFormat string
+
+// Supported change report formats.
+const (
+	ReportXLSX ReportFormat = "xlsx"
+	ReportCSV  ReportFormat = "csv"
+	ReportJSON ReportFormat = "json"
+)
+
+// ParseReportFormat validates a report format name (case-insensitive).
+func ParseReportFormat(name string) (ReportFormat, error) {
+	switch f := ReportFormat(strings.ToLower(strings.TrimSpace(name))); f {
+	case ReportXLSX, ReportCSV, ReportJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown report format %q (use xlsx, csv or json)", name)
+	}
+}
+
+// ChangeRecorder collects every cell a run modified, with its original and converted text.
+// Why: Auditors require evidence of each change when financial workbooks are converted.
+// Only the collector goroutine records, so no locking is needed.
+type ChangeRecorder struct {
+	changes []CellChange
+}
+
+// NewChangeRecorder creates an empty recorder.
+func NewChangeRecorder() *ChangeRecorder {
+	return &ChangeRecorder{}
+}
+
+// Changes returns the recorded changes in sheet, row and column order.
+func (r *ChangeRecorder) Changes() []CellChange {
+	// Results arrive in worker order
+	sort.SliceStable(r.changes, func(i, j int) bool {
+		a, b := r.changes[i], r.changes[j]
+		return cellLess(a.SheetName, a.Axis, b.SheetName, b.Axis)
+	})
+	return r.changes
+}
+
+// record adds the result if its text or font changed.
+func (r *ChangeRecorder) record(res Result, encoding converter.EncodingType) {
+	change := CellChange{
+		SheetName:     res.Job.SheetName,
+		Axis:          res.Job.Axis,
+		Before:        res.Job.Text,
+		After:         runsText(res.NewRuns),
+		Encoding:      encoding,
+		Font:          runsFont(res.Job.RichText),
+		ConvertedFont: runsFont(res.NewRuns),
+	}
+	if change.Before == change.After && change.Font == change.ConvertedFont {
+		return
+	}
+	r.changes = append(r.changes, change)
+}
+
+// WriteFile writes the report to path in the given format.
+func (r *ChangeRecorder) WriteFile(path string, format ReportFormat) (err error) {
+	if format == ReportXLSX {
+		return r.writeXLSX(path)
+	}
+	f, err := os.Create(path) //nolint:gosec // path derived from our own output path
+	if err != nil {
+		return fmt.Errorf("failed to create change report: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	if format == ReportJSON {
+		return r.WriteJSON(f)
+	}
+	return r.WriteCSV(f)
+}
+
+// changeReportHeader names the report columns, shared by the CSV and XLSX formats.
+var changeReportHeader = []string{
+	"Sheet", "Cell", "Encoding", "Original Font", "Converted Font", "Original Text", "Converted Text",
+}
+
+func (c CellChange) row() []string {
+	return []string{c.SheetName, c.Axis, string(c.Encoding), c.Font, c.ConvertedFont, c.Before, c.After}
+}
+
+// WriteCSV writes the report as CSV.
+func (r *ChangeRecorder) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(changeReportHeader); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	for _, c := range r.Changes() {
+		if err := cw.Write(c.row()); err != nil {
+			return fmt.Errorf("failed to write csv record: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the report as a JSON array of CellChange.
+func (r *ChangeRecorder) WriteJSON(w io.Writer) error {
+	changes := r.Changes()
+	if changes == nil {
+		changes = []CellChange{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(changes); err != nil {
+		return fmt.Errorf("failed to write json report: %w", err)
+	}
+	return nil
+}
+
+func (r *ChangeRecorder) writeXLSX(path string) (err error) {
+	f := excelize.NewFile()
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	sheet := f.GetSheetName(0)
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return fmt.Errorf("failed to create report sheet: %w", err)
+	}
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("failed to create header style: %w", err)
+	}
+	header := make([]any, len(changeReportHeader))
+	for i, h := range changeReportHeader {
+		header[i] = excelize.Cell{StyleID: bold, Value: h}
+	}
+	if err := sw.SetRow("A1", header); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	for i, c := range r.Changes() {
+		values := c.row()
+		row := make([]any, len(values))
+		for j, v := range values {
+			row[j] = v
+		}
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return fmt.Errorf("failed to compute cell name: %w", err)
+		}
+		if err := sw.SetRow(cell, row); err != nil {
+			return fmt.Errorf("failed to write row %d: %w", i+2, err)
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		return fmt.Errorf("failed to write report sheet: %w", err)
+	}
+	if err := f.SaveAs(path); err != nil {
+		return fmt.Errorf("failed to save change report: %w", err)
+	}
+	return nil
+}
+
+// runsFont returns the first font family named in runs.
+func runsFont(runs []excelize.RichTextRun) string {
+	for _, run := range runs {
+		if run.Font != nil && run.Font.Family != "" {
+			return run.Font.Family
+		}
+	}
+	return ""
+}
@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestProcessor_RunWithChangeReport(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "audit.xlsx")
+	writeWorkbook(t, inputFile, map[string]string{"A1": "Vi\u00D6t Nam", "A2": "Hello", "A10": "C\u00ABng ty"})
+
+	rec := NewChangeRecorder()
+	proc := NewProcessor(inputFile, "")
+	proc.SetChangeReport(rec)
+	outputFile, err := proc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+
+	want := []CellChange{
+		{SheetName: "Sheet1", Axis: "A1", Before: "Vi\u00D6t Nam", After: "Việt Nam", Encoding: converter.EncodingVNI, Font: "Calibri", ConvertedFont: "Arial"},
+		{SheetName: "Sheet1", Axis: "A10", Before: "C\u00ABng ty", After: "Công ty", Encoding: converter.EncodingTCVN3, Font: "Calibri", ConvertedFont: "Arial"},
+	}
+	got := rec.Changes()
+	if len(got) != len(want) {
+		t.Fatalf("Changes() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestChangeRecorder_WriteFile(t *testing.T) {
+	rec := NewChangeRecorder()
+	rec.record(Result{
+		Job:     Job{SheetName: "Sheet1", Axis: "B2", Text: "Vi\u00D6t", RichText: []excelize.RichTextRun{{Text: "Vi\u00D6t", Font: &excelize.Font{Family: "VNI-Times"}}}},
+		NewRuns: []excelize.RichTextRun{{Text: "Việt", Font: &excelize.Font{Family: "Times New Roman"}}},
+	}, converter.EncodingVNI)
+	// Unchanged cells are not evidence
+	rec.record(Result{
+		Job:     Job{SheetName: "Sheet1", Axis: "B3", Text: "Hello"},
+		NewRuns: []excelize.RichTextRun{{Text: "Hello"}},
+	}, converter.EncodingUnknown)
+	wantRows := [][]string{
+		changeReportHeader,
+		{"Sheet1", "B2", "VNI", "VNI-Times", "Times New Roman", "Vi\u00D6t", "Việt"},
+	}
+
+	tests := []struct {
+		format ReportFormat
+		read   func(t *testing.T, path string) [][]string
+	}{
+		{format: ReportCSV, read: readCSVRows},
+		{format: ReportXLSX, read: readXLSXRows},
+		{format: ReportJSON, read: readJSONRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "changes."+string(tt.format))
+			if err := rec.WriteFile(path, tt.format); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+			rows := tt.read(t, path)
+			if len(rows) != len(wantRows) {
+				t.Fatalf("rows = %v, want %v", rows, wantRows)
+			}
+			for i := range wantRows {
+				for j := range wantRows[i] {
+					if j >= len(rows[i]) || rows[i][j] != wantRows[i][j] {
+						t.Errorf("row %d = %v, want %v", i, rows[i], wantRows[i])
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseReportFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ReportFormat
+		wantErr bool
+	}{
+		{name: "xlsx", want: ReportXLSX},
+		{name: " CSV ", want: ReportCSV},
+		{name: "Json", want: ReportJSON},
+		{name: "pdf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReportFormat(tt.name)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseReportFormat(%q) = %q, %v; want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func readCSVRows(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path) //nolint:gosec // test file
+	if err != nil {
+		t.Fatalf("failed to open report: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse csv: %v", err)
+	}
+	return rows
+}
+
+func readXLSXRows(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("failed to open report: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	rows, err := f.GetRows(f.GetSheetName(0))
+	if err != nil {
+		t.Fatalf("failed to read rows: %v", err)
+	}
+	return rows
+}
+
+func readJSONRows(t *testing.T, path string) [][]string {
+	t.Helper()
+	data, err := os.ReadFile(path) //nolint:gosec // test file
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	var changes []CellChange
+	if err := json.Unmarshal(data, &changes); err != nil {
+		t.Fatalf("failed to parse json: %v", err)
+	}
+	rows := [][]string{changeReportHeader}
+	for _, c := range changes {
+		rows = append(rows, c.row())
+	}
+	return rows
+}
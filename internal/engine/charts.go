@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/xml"
+	"html"
+	"regexp"
+	"strings"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+// chartPartPrefix matches chart parts (chartN.xml and chartExN.xml) in the package.
+const chartPartPrefix = "xl/charts/chart"
+
+var (
+	// chartTextRe matches text runs (<a:t>) and cached values (<c:v>) with any or no prefix.
+	chartTextRe = regexp.MustCompile(`(<(?:\w+:)?[tv](?:\s[^>]*)?>)([^<]+)(</(?:\w+:)?[tv]>)`)
+	// chartFormulaRe matches series and category references (<c:f>).
+	chartFormulaRe = regexp.MustCompile(`(<(?:\w+:)?f(?:\s[^>]*)?>)([^<]+)(</(?:\w+:)?f>)`)
+	// chartTypefaceRe matches font references such as <a:latin typeface="VNI-Times"/>.
+	chartTypefaceRe = regexp.MustCompile(`typeface="([^"]*)"`)
+)
+
+// convertCharts converts titles, axis titles, legend and series-name text in every chart
+// and returns the number of charts changed. It must run on the collector goroutine.
+// Why: excelize has no chart reader, but keeps chart parts as raw XML in f.Pkg and saves
+// them unchanged, so the text is rewritten there. Charts are converted workbook-wide.
+func (p *Processor) convertCharts() int {
+	converted := 0
+	p.f.Pkg.Range(func(key, value any) bool {
+		part, ok := key.(string)
+		data, isBytes := value.([]byte)
+		if !ok || !isBytes || !strings.HasPrefix(part, chartPartPrefix) || !strings.HasSuffix(part, ".xml") {
+			return true
+		}
+		if updated := p.convertChartXML(data); !bytes.Equal(updated, data) {
+			p.f.Pkg.Store(part, updated)
+			converted++
+		}
+		return true
+	})
+	return converted
+}
+
+// convertChartXML converts the text and legacy fonts of one chart part.
+func (p *Processor) convertChartXML(data []byte) []byte {
+	// Chart text rarely carries its own font; the first legacy typeface decides the encoding
+	chartFont := ""
+	for _, m := range chartTypefaceRe.FindAllSubmatch(data, -1) {
+		if DetectEncoding(string(m[1]), "") != converter.EncodingUnknown {
+			chartFont = string(m[1])
+			break
+		}
+	}
+
+	data = chartTextRe.ReplaceAllFunc(data, func(match []byte) []byte {
+		m := chartTextRe.FindSubmatch(match)
+		text := html.UnescapeString(string(m[2]))
+		convertedText, _ := p.preserver.ConvertRun(chartFont, text)
+		if convertedText == text {
+			return match
+		}
+		return joinXMLText(m[1], convertedText, m[3])
+	})
+
+	return chartTypefaceRe.ReplaceAllFunc(data, func(match []byte) []byte {
+		face := string(chartTypefaceRe.FindSubmatch(match)[1])
+		if DetectEncoding(face, "") == converter.EncodingUnknown {
+			return match
+		}
+		if _, family := p.preserver.ConvertRun(face, ""); family != "" && family != face {
+			var b bytes.Buffer
+			b.WriteString(`typeface="`)
+			_ = xml.EscapeText(&b, []byte(family))
+			b.WriteString(`"`)
+			return b.Bytes()
+		}
+		return match
+	})
+}
+
+// rewriteChartFormulas applies replacer to the series and category references of every chart.
+func (p *Processor) rewriteChartFormulas(replacer sheetRefReplacer) {
+	p.f.Pkg.Range(func(key, value any) bool {
+		part, ok := key.(string)
+		data, isBytes := value.([]byte)
+		if !ok || !isBytes || !strings.HasPrefix(part, chartPartPrefix) {
+			return true
+		}
+		updated := chartFormulaRe.ReplaceAllFunc(data, func(match []byte) []byte {
+			m := chartFormulaRe.FindSubmatch(match)
+			formula := html.UnescapeString(string(m[2]))
+			if renamed := replacer.Replace(formula); renamed != formula {
+				return joinXMLText(m[1], renamed, m[3])
+			}
+			return match
+		})
+		if !bytes.Equal(updated, data) {
+			p.f.Pkg.Store(part, updated)
+		}
+		return true
+	})
+}
+
+// joinXMLText rebuilds an element from its tags and (escaped) text.
+func joinXMLText(open []byte, text string, closing []byte) []byte {
+	var b bytes.Buffer
+	b.Write(open)
+	_ = xml.EscapeText(&b, []byte(text))
+	b.Write(closing)
+	return b.Bytes()
+}
@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestProcessor_ConvertCharts(t *testing.T) {
+	vni := converter.NewVNIEncoder()
+	sheet := vni.FromUnicode("Báo cáo")
+	inputFile := filepath.Join(t.TempDir(), "chart.xlsx")
+	f := excelize.NewFile()
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		t.Fatalf("failed to rename sheet: %v", err)
+	}
+	for axis, v := range map[string]any{"A1": "Doanh thu", "A2": 10, "A3": 20, "B2": "Q1", "B3": "Q2"} {
+		if err := f.SetCellValue(sheet, axis, v); err != nil {
+			t.Fatalf("failed to set %s: %v", axis, err)
+		}
+	}
+	font := &excelize.Font{Family: "VNI-Times"}
+	if err := f.AddChart(sheet, "D2", &excelize.Chart{
+		Type: excelize.Col,
+		Series: []excelize.ChartSeries{{
+			Name:       "'" + sheet + "'!$A$1",
+			Categories: "'" + sheet + "'!$B$2:$B$3",
+			Values:     "'" + sheet + "'!$A$2:$A$3",
+		}},
+		Title: []excelize.RichTextRun{{Text: vni.FromUnicode("Báo cáo quý"), Font: font}},
+		XAxis: excelize.ChartAxis{Title: []excelize.RichTextRun{{Text: vni.FromUnicode("Tháng"), Font: font}}},
+	}); err != nil {
+		t.Fatalf("failed to add chart: %v", err)
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	_ = f.Close()
+
+	outputFile, err := NewProcessor(inputFile, "").Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+
+	fOut, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = fOut.Close() }()
+	data, ok := fOut.Pkg.Load("xl/charts/chart1.xml")
+	if !ok {
+		t.Fatal("chart part missing from output")
+	}
+	chart := string(data.([]byte))
+
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{name: "Title converted", want: "Báo cáo quý", ok: true},
+		{name: "Axis title converted", want: "Tháng", ok: true},
+		{name: "Font mapped", want: `typeface="Times New Roman"`, ok: true},
+		{name: "Series follows renamed sheet", want: "&#39;Báo cáo&#39;!$A$1", ok: true},
+		{name: "Legacy font removed", want: "VNI-Times", ok: false},
+		{name: "Legacy sheet reference removed", want: sheet + "&#39;!", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Contains(chart, tt.want); got != tt.ok {
+				t.Errorf("chart contains %q = %v, want %v\n%s", tt.want, got, tt.ok, chart)
+			}
+		})
+	}
+}
+
+func TestConvertChartXML_KeepsUnicodeCharts(t *testing.T) {
+	p := NewProcessor("", "")
+	in := []byte(`<c:title><a:p><a:r><a:rPr><a:latin typeface="Arial"/></a:rPr><a:t>Doanh thu &amp; chi phí</a:t></a:r></a:p></c:title>`)
+	if got := p.convertChartXML(in); string(got) != string(in) {
+		t.Errorf("convertChartXML() = %s, want unchanged", got)
+	}
+}
@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+// MaxReportedLocations caps the cell list of a CheckReport.
+// Why: Triage of thousands of files needs counts, not every address.
+const MaxReportedLocations = 1000
+
+// LegacyCell is a cell containing legacy-encoded text.
+type LegacyCell struct {
+	SheetName string                 `json:"sheetName"`
+	Axis      string                 `json:"axis"`
+	Encoding  converter.EncodingType `json:"encoding"`
+}
+
+// CheckReport summarizes legacy content found by a read-only check.
+type CheckReport struct {
+	InputPath       string                         `json:"inputPath"`
+	Sheets          []string                       `json:"sheets"`
+	CellsScanned    int                            `json:"cellsScanned"`
+	LegacyCells     int                            `json:"legacyCells"`
+	ByEncoding      map[converter.EncodingType]int `json:"byEncoding"`
+	BySheet         map[string]int                 `json:"bySheet"`
+	Locations       []LegacyCell                   `json:"locations"`
+	LocationsCapped bool                           `json:"locationsCapped"`
+}
+
+// HasLegacy reports whether any legacy-encoded cell was found.
+func (r *CheckReport) HasLegacy() bool {
+	return r.LegacyCells > 0
+}
+
+// Check scans the workbook for legacy-encoded content without converting or saving anything.
+// Why: Archivists triage thousands of files cheaply before scheduling real conversions.
+func (p *Processor) Check(ctx context.Context) (*CheckReport, error) {
+	if err := p.openInput(ctx); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := p.f.Close(); closeErr != nil {
+			slog.Error("failed to close excel file", "error", closeErr)
+		}
+	}()
+
+	sheets, err := p.resolveSheets()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CheckReport{
+		InputPath:  p.InputPath,
+		Sheets:     sheets,
+		ByEncoding: make(map[converter.EncodingType]int),
+		BySheet:    make(map[string]int),
+		Locations:  []LegacyCell{},
+	}
+	for _, sheet := range sheets {
+		p.walkCells(ctx, sheet, func(job Job) bool {
+			report.CellsScanned++
+			if enc := p.detectJobEncoding(job); enc != converter.EncodingUnknown {
+				report.add(LegacyCell{SheetName: job.SheetName, Axis: job.Axis, Encoding: enc})
+			}
+			return true
+		})
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("check cancelled: %w", err)
+	}
+	return report, nil
+}
+
+func (r *CheckReport) add(cell LegacyCell) {
+	r.LegacyCells++
+	r.ByEncoding[cell.Encoding]++
+	r.BySheet[cell.SheetName]++
+	if len(r.Locations) < MaxReportedLocations {
+		r.Locations = append(r.Locations, cell)
+	} else {
+		r.LocationsCapped = true
+	}
+}
+
+// detectJobEncoding returns the first legacy encoding detected among the cell's runs.
+func (p *Processor) detectJobEncoding(job Job) converter.EncodingType {
+	if _, ok := detectUnicode(job.Text); ok {
+		return converter.EncodingUnknown
+	}
+	for _, run := range job.RichText {
+		fontName := ""
+		if run.Font != nil {
+			fontName = run.Font.Family
+		}
+		if _, ok := detectUnicode(run.Text); ok {
+			continue
+		}
+		if enc := p.preserver.detector.Detect(fontName, run.Text).Encoding; enc != converter.EncodingUnknown {
+			return enc
+		}
+	}
+	return converter.EncodingUnknown
+}
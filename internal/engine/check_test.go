@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestProcessor_Check(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "check.xlsx")
+
+	f := excelize.NewFile()
+	values := map[string]string{
+		"A1": "Vi\u00D6t Nam", // VNI
+		"A2": "Hello",         // Plain
+		"B1": "C\u00ABng ty",  // TCVN3
+	}
+	for axis, v := range values {
+		if err := f.SetCellValue("Sheet1", axis, v); err != nil {
+			t.Fatalf("failed to set %s: %v", axis, err)
+		}
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save input: %v", err)
+	}
+	_ = f.Close()
+
+	report, err := NewProcessor(inputFile, "").Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+
+	if report.CellsScanned != 3 {
+		t.Errorf("CellsScanned = %d, want 3", report.CellsScanned)
+	}
+	if !report.HasLegacy() || report.LegacyCells != 2 {
+		t.Errorf("LegacyCells = %d, want 2", report.LegacyCells)
+	}
+	if report.ByEncoding[converter.EncodingVNI] != 1 || report.ByEncoding[converter.EncodingTCVN3] != 1 {
+		t.Errorf("ByEncoding mismatch: %v", report.ByEncoding)
+	}
+
+	// No output must be produced
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("check produced files: %d entries in temp dir", len(entries))
+	}
+}
@@ -0,0 +1,182 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"convert-vni-to-unicode/internal/cache"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// checkpointProperty is the custom document property holding the checkpoint state.
+// Why: The state travels inside the checkpoint workbook, so a crash can never leave the
+// list of completed sheets out of step with the cells actually saved (re-converting an
+// already converted sheet would garble it).
+const checkpointProperty = "VniConverterCheckpoint"
+
+// checkpointState identifies the run a checkpoint belongs to and how far it got.
+type checkpointState struct {
+	InputHash string   `json:"inputHash"`
+	Settings  string   `json:"settings"`
+	Completed []string `json:"completed"`
+}
+
+// checkpoint saves the workbook after every converted sheet.
+type checkpoint struct {
+	path  string
+	state checkpointState
+}
+
+// SetCheckpoint enables sheet-level checkpointing in Run. After each sheet's cells are
+// converted the workbook is saved to a checkpoint file next to the output; a later Run
+// with the same input and settings resumes from it, skipping the completed sheets.
+// The checkpoint is removed once the output is saved.
+// Why: On workbooks with tens of millions of cells a crash should lose at most one sheet.
+func (p *Processor) SetCheckpoint(enabled bool) {
+	p.checkpointing = enabled
+}
+
+// CheckpointPath returns the checkpoint file Run uses for the current input and output settings.
+func (p *Processor) CheckpointPath() string {
+	dir := filepath.Dir(p.outputPath(time.Time{}))
+	if p.backupMode != BackupNone {
+		dir = filepath.Dir(p.InputPath)
+	}
+	ext := filepath.Ext(p.InputPath)
+	base := strings.TrimSuffix(filepath.Base(p.InputPath), ext)
+	return filepath.Join(dir, base+"_checkpoint"+ext)
+}
+
+// checkpointSettings fingerprints the options that change converted cell contents.
+func (p *Processor) checkpointSettings() string {
+	return fmt.Sprintf("sheet=%s;encoding=%s;font=%#v;detector=%T;maxlen=%d;cells=%s;plain=%t;normalization=%s",
+		p.SheetName, p.preserver.sourceEncoding, p.preserver.policy, p.preserver.detector, p.maxCellLength,
+		p.cellFilter, p.plainCells, p.preserver.normalization)
+}
+
+// openCheckpoint prepares checkpointing for the open input. If a checkpoint of the same
+// input and settings exists, the input workbook is swapped for it so its completed sheets
+// are skipped. A stale or unreadable checkpoint is discarded.
+func (p *Processor) openCheckpoint() error {
+	hash, err := cache.HashFile(p.InputPath)
+	if err != nil {
+		return fmt.Errorf("failed to hash input for checkpoint: %w", err)
+	}
+	p.checkpoint = &checkpoint{
+		path:  p.CheckpointPath(),
+		state: checkpointState{InputHash: hash, Settings: p.checkpointSettings()},
+	}
+
+	state, f, err := readCheckpoint(p.checkpoint.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err == nil && (state.InputHash != hash || state.Settings != p.checkpoint.state.Settings) {
+		_ = f.Close()
+		err = errors.New("input or settings changed")
+	}
+	if err != nil {
+		slog.Warn("discarding checkpoint", "path", p.checkpoint.path, "error", err)
+		p.removeCheckpoint()
+		return nil
+	}
+
+	if closeErr := p.f.Close(); closeErr != nil {
+		slog.Error("failed to close excel file", "error", closeErr)
+	}
+	p.f = f
+	p.checkpoint.state.Completed = state.Completed
+	slog.Info("resuming from checkpoint", "path", p.checkpoint.path, "completed", state.Completed)
+	return nil
+}
+
+// readCheckpoint opens a checkpoint workbook and decodes its state.
+func readCheckpoint(path string) (checkpointState, *excelize.File, error) {
+	var state checkpointState
+	if _, err := os.Stat(path); err != nil {
+		return state, nil, err
+	}
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return state, nil, fmt.Errorf("failed to open checkpoint: %w", err)
+	}
+	props, err := f.GetCustomProps()
+	if err != nil {
+		_ = f.Close()
+		return state, nil, fmt.Errorf("failed to read checkpoint state: %w", err)
+	}
+	for _, prop := range props {
+		if prop.Name != checkpointProperty {
+			continue
+		}
+		if raw, ok := prop.Value.(string); ok {
+			if err := json.Unmarshal([]byte(raw), &state); err == nil {
+				return state, f, nil
+			}
+		}
+	}
+	_ = f.Close()
+	return state, nil, errors.New("checkpoint state missing")
+}
+
+// completeSheet records sheet as converted and saves the workbook to the checkpoint file.
+// The save goes to a temporary file that replaces the checkpoint, so the previous
+// checkpoint survives a crash mid-save.
+func (p *Processor) completeSheet(sheet string) error {
+	p.checkpoint.state.Completed = append(p.checkpoint.state.Completed, sheet)
+	raw, err := json.Marshal(p.checkpoint.state)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint state: %w", err)
+	}
+	if err := p.f.SetCustomProps(excelize.CustomProperty{Name: checkpointProperty, Value: string(raw)}); err != nil {
+		return fmt.Errorf("failed to set checkpoint state: %w", err)
+	}
+
+	ext := filepath.Ext(p.checkpoint.path)
+	tmp := strings.TrimSuffix(p.checkpoint.path, ext) + ".tmp" + ext
+	if err := p.f.SaveAs(tmp); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, p.checkpoint.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// sheetsToConvert drops the sheets a resumed checkpoint already completed.
+func sheetsToConvert(sheets, completed []string) []string {
+	remaining := make([]string, 0, len(sheets))
+	for _, sheet := range sheets {
+		if !slices.Contains(completed, sheet) {
+			remaining = append(remaining, sheet)
+		}
+	}
+	return remaining
+}
+
+// clearCheckpointState removes the checkpoint property so it is not saved into the output.
+func (p *Processor) clearCheckpointState() {
+	if err := p.f.SetCustomProps(excelize.CustomProperty{Name: checkpointProperty}); err != nil {
+		slog.Warn("failed to clear checkpoint state", "error", err)
+	}
+}
+
+// removeCheckpoint deletes the checkpoint file, if any.
+func (p *Processor) removeCheckpoint() {
+	if p.checkpoint == nil {
+		return
+	}
+	if err := os.Remove(p.checkpoint.path); err != nil && !os.IsNotExist(err) {
+		slog.Warn("failed to remove checkpoint", "path", p.checkpoint.path, "error", err)
+	}
+}
@@ -0,0 +1,169 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"convert-vni-to-unicode/internal/cache"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// writeTwoSheetWorkbook saves a workbook with a1 in Sheet1!A1 and a2 in Sheet2!A1.
+func writeTwoSheetWorkbook(t *testing.T, path, a1, a2 string, props ...excelize.CustomProperty) {
+	t.Helper()
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+	if _, err := f.NewSheet("Sheet2"); err != nil {
+		t.Fatalf("failed to add sheet: %v", err)
+	}
+	if err := f.SetCellValue("Sheet1", "A1", a1); err != nil {
+		t.Fatalf("failed to set Sheet1!A1: %v", err)
+	}
+	if err := f.SetCellValue("Sheet2", "A1", a2); err != nil {
+		t.Fatalf("failed to set Sheet2!A1: %v", err)
+	}
+	for _, prop := range props {
+		if err := f.SetCustomProps(prop); err != nil {
+			t.Fatalf("failed to set property: %v", err)
+		}
+	}
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("failed to save %s: %v", path, err)
+	}
+}
+
+func TestProcessor_RunWithCheckpoint(t *testing.T) {
+	const legacy = "Vi\u00D6t Nam"
+
+	tests := []struct {
+		name string
+		// checkpoint returns the checkpoint state to leave behind, or nil for none
+		checkpoint func(p *Processor, inputHash string) *checkpointState
+		wantSheet1 string
+	}{
+		{
+			name:       "no checkpoint converts every sheet",
+			checkpoint: func(*Processor, string) *checkpointState { return nil },
+			wantSheet1: "Việt Nam",
+		},
+		{
+			// Sheet1 is still legacy text in the checkpoint, so leaving it alone proves it was skipped
+			name: "resume skips completed sheets",
+			checkpoint: func(p *Processor, inputHash string) *checkpointState {
+				return &checkpointState{InputHash: inputHash, Settings: p.checkpointSettings(), Completed: []string{"Sheet1"}}
+			},
+			wantSheet1: legacy,
+		},
+		{
+			name: "stale checkpoint is discarded",
+			checkpoint: func(p *Processor, _ string) *checkpointState {
+				return &checkpointState{InputHash: "other", Settings: p.checkpointSettings(), Completed: []string{"Sheet1"}}
+			},
+			wantSheet1: "Việt Nam",
+		},
+		{
+			name: "changed settings discard the checkpoint",
+			checkpoint: func(_ *Processor, inputHash string) *checkpointState {
+				return &checkpointState{InputHash: inputHash, Settings: "other", Completed: []string{"Sheet1"}}
+			},
+			wantSheet1: "Việt Nam",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputFile := filepath.Join(t.TempDir(), "giant.xlsx")
+			writeTwoSheetWorkbook(t, inputFile, legacy, legacy)
+			inputHash, err := cache.HashFile(inputFile)
+			if err != nil {
+				t.Fatalf("HashFile failed: %v", err)
+			}
+
+			proc := NewProcessor(inputFile, "")
+			proc.SetCheckpoint(true)
+			if state := tt.checkpoint(proc, inputHash); state != nil {
+				raw, err := json.Marshal(state)
+				if err != nil {
+					t.Fatalf("failed to encode state: %v", err)
+				}
+				writeTwoSheetWorkbook(t, proc.CheckpointPath(), legacy, legacy,
+					excelize.CustomProperty{Name: checkpointProperty, Value: string(raw)})
+			}
+
+			outputFile, err := proc.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if _, err := os.Stat(proc.CheckpointPath()); !os.IsNotExist(err) {
+				t.Errorf("checkpoint not removed after success: %v", err)
+			}
+
+			f, err := excelize.OpenFile(outputFile)
+			if err != nil {
+				t.Fatalf("failed to open output: %v", err)
+			}
+			defer func() { _ = f.Close() }()
+			if got, _ := f.GetCellValue("Sheet1", "A1"); got != tt.wantSheet1 {
+				t.Errorf("Sheet1!A1 = %q, want %q", got, tt.wantSheet1)
+			}
+			if got, _ := f.GetCellValue("Sheet2", "A1"); got != "Việt Nam" {
+				t.Errorf("Sheet2!A1 = %q, want converted text", got)
+			}
+			props, err := f.GetCustomProps()
+			if err != nil {
+				t.Fatalf("GetCustomProps failed: %v", err)
+			}
+			for _, prop := range props {
+				if prop.Name == checkpointProperty {
+					t.Errorf("output still carries the checkpoint state")
+				}
+			}
+		})
+	}
+}
+
+func TestProcessor_CheckpointSurvivesCancel(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "giant.xlsx")
+	writeTwoSheetWorkbook(t, inputFile, "Vi\u00D6t Nam", "C\u00ABng ty")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	proc := NewProcessor(inputFile, "")
+	proc.SetCheckpoint(true)
+	// Cancel as soon as the second sheet starts, after the first was checkpointed
+	proc.SetTracer(&cancelOnSheet{sheet: "Sheet2", cancel: cancel})
+
+	if _, err := proc.Run(ctx); err == nil {
+		t.Fatal("Run succeeded, want cancellation")
+	}
+	state, f, err := readCheckpoint(proc.CheckpointPath())
+	if err != nil {
+		t.Fatalf("readCheckpoint failed: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	if len(state.Completed) != 1 || state.Completed[0] != "Sheet1" {
+		t.Errorf("completed = %v, want [Sheet1]", state.Completed)
+	}
+	if got, _ := f.GetCellValue("Sheet1", "A1"); got != "Việt Nam" {
+		t.Errorf("checkpoint Sheet1!A1 = %q, want converted text", got)
+	}
+}
+
+// cancelOnSheet cancels the run when a worker picks up a cell of sheet.
+type cancelOnSheet struct {
+	sheet  string
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnSheet) OnCellStart(_ context.Context, job Job) {
+	if job.SheetName == c.sheet {
+		c.cancel()
+	}
+}
+
+func (c *cancelOnSheet) OnCellEnd(context.Context, Job, time.Duration) {}
@@ -0,0 +1,154 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ColorRepairReport summarizes a RepairColors run.
+type ColorRepairReport struct {
+	// CellsRepaired is the number of cells that got at least one run color back
+	CellsRepaired int `json:"cellsRepaired"`
+	// RunsRepaired is the number of runs whose color was restored
+	RunsRepaired int `json:"runsRepaired"`
+	// CellsUnmatched counts rich text cells whose runs do not line up with the original
+	// (runs were merged), so their colors could not be compared
+	CellsUnmatched int `json:"cellsUnmatched"`
+}
+
+// RepairColors restores run colors that an earlier conversion dropped. Sheets are matched
+// by position (names may have been converted) and runs one to one; a converted run without
+// a color gets the color of the original run, or of the original cell style for runs that
+// had no font of their own. The converted workbook is saved in place when anything changed.
+// Why: Older versions gave runs without a font a new font without the style's color, so red
+// warnings in already converted workbooks turned black.
+func RepairColors(ctx context.Context, originalPath, convertedPath string) (*ColorRepairReport, error) {
+	original, err := excelize.OpenFile(originalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open original: %w", err)
+	}
+	defer func() { _ = original.Close() }()
+	converted, err := excelize.OpenFile(convertedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open converted workbook: %w", err)
+	}
+	defer func() { _ = converted.Close() }()
+
+	report := &ColorRepairReport{}
+	originalSheets, convertedSheets := original.GetSheetList(), converted.GetSheetList()
+	for i := 0; i < len(originalSheets) && i < len(convertedSheets); i++ {
+		if err := repairSheetColors(ctx, original, converted, originalSheets[i], convertedSheets[i], report); err != nil {
+			return nil, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("color repair cancelled: %w", err)
+	}
+	if report.CellsRepaired == 0 {
+		return report, nil
+	}
+	if err := converted.Save(); err != nil {
+		return nil, fmt.Errorf("failed to save repaired workbook: %w", err)
+	}
+	return report, nil
+}
+
+// repairSheetColors repairs the rich text cells of one converted sheet against the original.
+func repairSheetColors(ctx context.Context, original, converted *excelize.File, originalSheet, convertedSheet string,
+	report *ColorRepairReport) error {
+	rows, err := converted.GetRows(convertedSheet)
+	if err != nil {
+		return fmt.Errorf("failed to read sheet %q: %w", convertedSheet, err)
+	}
+	for r, row := range rows {
+		for c, text := range row {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if text == "" {
+				continue
+			}
+			axis, err := excelize.CoordinatesToCellName(c+1, r+1)
+			if err != nil {
+				continue
+			}
+			runs, err := converted.GetCellRichText(convertedSheet, axis)
+			if err != nil || !hasAnyRunFont(runs) {
+				continue
+			}
+			before, err := original.GetCellRichText(originalSheet, axis)
+			if err != nil || len(before) == 0 {
+				continue
+			}
+			if len(before) != len(runs) {
+				report.CellsUnmatched++
+				continue
+			}
+
+			var styleFont *excelize.Font
+			if !hasRunFonts(before) {
+				styleFont = styleFontOf(original, originalSheet, axis)
+			}
+			restored := restoreRunColors(runs, before, styleFont)
+			if restored == 0 {
+				continue
+			}
+			if err := converted.SetCellRichText(convertedSheet, axis, runs); err != nil {
+				slog.Error("failed to write repaired rich text", "sheet", convertedSheet, "cell", axis, "error", err)
+				continue
+			}
+			report.CellsRepaired++
+			report.RunsRepaired += restored
+		}
+	}
+	return nil
+}
+
+// styleFontOf returns the font of the cell style of axis, or nil.
+func styleFontOf(f *excelize.File, sheet, axis string) *excelize.Font {
+	styleID, err := f.GetCellStyle(sheet, axis)
+	if err != nil {
+		return nil
+	}
+	style, err := f.GetStyle(styleID)
+	if err != nil {
+		return nil
+	}
+	return style.Font
+}
+
+// restoreRunColors copies the colors of the original runs, or of styleFont for runs
+// without a font, into converted runs that lost theirs, and returns how many it restored.
+func restoreRunColors(runs, before []excelize.RichTextRun, styleFont *excelize.Font) int {
+	restored := 0
+	for i := range runs {
+		source := before[i].Font
+		if source == nil {
+			source = styleFont
+		}
+		if runs[i].Font == nil || hasFontColor(runs[i].Font) || source == nil || !hasFontColor(source) {
+			continue
+		}
+		font := *runs[i].Font
+		copyFontColor(&font, source)
+		runs[i].Font = &font
+		restored++
+	}
+	return restored
+}
+
+// hasFontColor reports whether font sets a color in any of Excel's color forms.
+func hasFontColor(font *excelize.Font) bool {
+	return font.Color != "" || font.ColorIndexed != 0 || font.ColorTheme != nil || font.ColorTint != 0
+}
+
+// copyFontColor copies every color field of src into dst.
+func copyFontColor(dst, src *excelize.Font) {
+	dst.Color = src.Color
+	dst.ColorIndexed = src.ColorIndexed
+	dst.ColorTheme = src.ColorTheme
+	dst.ColorTint = src.ColorTint
+}
@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// saveRichTextWorkbook writes cells of rich text runs to path. A non-nil style font becomes
+// the style of every cell.
+func saveRichTextWorkbook(t *testing.T, path string, styleFont *excelize.Font, cells map[string][]excelize.RichTextRun) {
+	t.Helper()
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+	for axis, runs := range cells {
+		if err := f.SetCellRichText("Sheet1", axis, runs); err != nil {
+			t.Fatalf("SetCellRichText failed: %v", err)
+		}
+		if styleFont == nil {
+			continue
+		}
+		style, err := f.NewStyle(&excelize.Style{Font: styleFont})
+		if err != nil {
+			t.Fatalf("NewStyle failed: %v", err)
+		}
+		if err := f.SetCellStyle("Sheet1", axis, axis, style); err != nil {
+			t.Fatalf("SetCellStyle failed: %v", err)
+		}
+	}
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+}
+
+func TestProcessor_KeepsRunColors(t *testing.T) {
+	theme := 5
+	inputFile := filepath.Join(t.TempDir(), "colors.xlsx")
+	saveRichTextWorkbook(t, inputFile, &excelize.Font{Family: "VNI-Times", Color: "FF0000"}, map[string][]excelize.RichTextRun{
+		"A1": {
+			{Text: "Vi\u00D6t ", Font: &excelize.Font{Family: "VNI-Times", Color: "FF0000"}},
+			{Text: "Nam ", Font: &excelize.Font{Family: "VNI-Times", ColorIndexed: 10}},
+			{Text: "C\u00ABng ty", Font: &excelize.Font{Family: "VNI-Times", ColorTheme: &theme, ColorTint: 0.4}},
+		},
+		// The first run has no font of its own and shows the style's red
+		"A2": {
+			{Text: "Vi\u00D6t "},
+			{Text: "Nam", Font: &excelize.Font{Family: "VNI-Times", Bold: true}},
+		},
+	})
+
+	outputFile, err := NewProcessor(inputFile, "").Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+
+	fOut, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = fOut.Close() }()
+
+	tests := []struct {
+		name  string
+		axis  string
+		run   int
+		check func(*excelize.Font) bool
+	}{
+		{name: "RGB", axis: "A1", run: 0, check: func(f *excelize.Font) bool { return strings.HasSuffix(f.Color, "FF0000") }},
+		{name: "Indexed", axis: "A1", run: 1, check: func(f *excelize.Font) bool { return f.ColorIndexed == 10 }},
+		{name: "Theme", axis: "A1", run: 2, check: func(f *excelize.Font) bool {
+			return f.ColorTheme != nil && *f.ColorTheme == theme && f.ColorTint == 0.4
+		}},
+		{name: "Style color of a run without font", axis: "A2", run: 0, check: func(f *excelize.Font) bool {
+			return strings.HasSuffix(f.Color, "FF0000")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, err := fOut.GetCellRichText("Sheet1", tt.axis)
+			if err != nil || len(runs) <= tt.run || runs[tt.run].Font == nil {
+				t.Fatalf("GetCellRichText(%s) = %+v, %v; want run %d with a font", tt.axis, runs, err, tt.run)
+			}
+			font := runs[tt.run].Font
+			if font.Family != "Times New Roman" || !tt.check(font) {
+				t.Errorf("run %d of %s has font %+v, want Times New Roman with the original color", tt.run, tt.axis, *font)
+			}
+		})
+	}
+}
+
+func TestRepairColors(t *testing.T) {
+	dir := t.TempDir()
+	originalFile := filepath.Join(dir, "original.xlsx")
+	convertedFile := filepath.Join(dir, "converted.xlsx")
+	saveRichTextWorkbook(t, originalFile, &excelize.Font{Family: "VNI-Times", Color: "0000FF"}, map[string][]excelize.RichTextRun{
+		"A1": {
+			{Text: "Vi\u00D6t ", Font: &excelize.Font{Family: "VNI-Times", Color: "FF0000"}},
+			{Text: "Nam", Font: &excelize.Font{Family: "VNI-Times", ColorIndexed: 10}},
+		},
+		"A2": {
+			{Text: "Vi\u00D6t "},
+			{Text: "Nam", Font: &excelize.Font{Family: "VNI-Times", Bold: true}},
+		},
+		"A3": {
+			{Text: "Vi\u00D6t ", Font: &excelize.Font{Family: "VNI-Times", Color: "FF0000"}},
+			{Text: "Nam", Font: &excelize.Font{Family: "VNI-Times", Color: "FF0000"}},
+		},
+	})
+	saveRichTextWorkbook(t, convertedFile, nil, map[string][]excelize.RichTextRun{
+		"A1": {
+			{Text: "Việt ", Font: &excelize.Font{Family: "Times New Roman"}},
+			{Text: "Nam", Font: &excelize.Font{Family: "Times New Roman"}},
+		},
+		"A2": {
+			{Text: "Việt ", Font: &excelize.Font{Family: "Times New Roman"}},
+			{Text: "Nam", Font: &excelize.Font{Family: "Times New Roman", Bold: true}},
+		},
+		"A3": {{Text: "Việt Nam", Font: &excelize.Font{Family: "Times New Roman"}}},
+	})
+
+	report, err := RepairColors(context.Background(), originalFile, convertedFile)
+	if err != nil {
+		t.Fatalf("RepairColors failed: %v", err)
+	}
+	want := ColorRepairReport{CellsRepaired: 2, RunsRepaired: 3, CellsUnmatched: 1}
+	if *report != want {
+		t.Errorf("report = %+v, want %+v", *report, want)
+	}
+
+	f, err := excelize.OpenFile(convertedFile)
+	if err != nil {
+		t.Fatalf("failed to open repaired workbook: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	a1, _ := f.GetCellRichText("Sheet1", "A1")
+	if len(a1) != 2 || !strings.HasSuffix(a1[0].Font.Color, "FF0000") || a1[1].Font.ColorIndexed != 10 {
+		t.Errorf("A1 runs = %+v, want red and indexed 10", a1)
+	}
+	a2, _ := f.GetCellRichText("Sheet1", "A2")
+	if len(a2) != 2 || !strings.HasSuffix(a2[0].Font.Color, "0000FF") || a2[0].Font.Family != "Times New Roman" {
+		t.Errorf("A2 runs = %+v, want the style's blue on the first run", a2)
+	}
+}
@@ -0,0 +1,82 @@
+package engine
+
+import "log/slog"
+
+// convertComments converts the text and author names of comments on the given sheets
+// and returns the number of comments changed. It must run on the collector goroutine.
+// Why: The comment parts are edited in place (like dedupFonts does with the styles), so
+// box position, size, visibility and run formatting stay exactly as they were; deleting
+// and re-adding comments through excelize would reset all of that.
+// Threaded comments also keep this legacy copy, which is converted here; their own text
+// is converted by convertThreadedComments.
+func (p *Processor) convertComments(sheets []string) int {
+	p.loadComments(sheets)
+	converted := 0
+	for _, cmts := range p.f.Comments {
+		if cmts == nil {
+			continue
+		}
+		for i, author := range cmts.Authors.Author {
+			cmts.Authors.Author[i], _ = p.preserver.ConvertRun("", author)
+		}
+		for i := range cmts.CommentList.Comment {
+			if !p.cellFilter.ContainsRef(cmts.CommentList.Comment[i].Ref) {
+				continue
+			}
+			text := &cmts.CommentList.Comment[i].Text
+			changed := false
+			if text.T != nil {
+				if t, _ := p.preserver.ConvertRun("", *text.T); t != *text.T {
+					*text.T = t
+					changed = true
+				}
+			}
+			for j := range text.R {
+				run := &text.R[j]
+				if run.T == nil {
+					continue
+				}
+				var font *string
+				if run.RPr != nil && run.RPr.RFont != nil {
+					font = run.RPr.RFont.Val
+				}
+				if p.convertCommentRun(&run.T.Val, font) {
+					changed = true
+				}
+			}
+			if changed {
+				converted++
+			}
+		}
+	}
+	return converted
+}
+
+// loadComments loads each sheet's comments part into p.f.Comments, which is saved with the file.
+func (p *Processor) loadComments(sheets []string) {
+	for _, sheet := range sheets {
+		if _, err := p.f.GetComments(sheet); err != nil {
+			slog.Warn("failed to read comments", "sheet", sheet, "error", err)
+			p.errs.add(sheet, "", StageComments, err)
+		}
+	}
+}
+
+// convertCommentRun converts the text of one comment run in place and reports whether it
+// changed. font is the run's font name, nil when the run has none.
+func (p *Processor) convertCommentRun(text, font *string) bool {
+	fontName := ""
+	if font != nil {
+		fontName = *font
+	}
+	t, family := p.preserver.ConvertRun(fontName, *text)
+	if t == *text {
+		return false
+	}
+	*text = t
+	// Only an existing font element can be changed; comments default to Tahoma otherwise
+	if family != "" && fontName != "" {
+		*font = family
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestProcessor_ConvertComments(t *testing.T) {
+	vni := converter.NewVNIEncoder()
+	inputFile := filepath.Join(t.TempDir(), "comments.xlsx")
+	f := excelize.NewFile()
+	if err := f.SetCellValue("Sheet1", "A1", "Hello"); err != nil {
+		t.Fatalf("failed to set cell: %v", err)
+	}
+	if err := f.AddComment("Sheet1", excelize.Comment{
+		Cell:   "A1",
+		Author: vni.FromUnicode("Nguyễn Văn"),
+		Paragraph: []excelize.RichTextRun{
+			{Text: vni.FromUnicode("Nguyễn Văn") + ": ", Font: &excelize.Font{Bold: true, Family: "VNI-Times"}},
+			{Text: vni.FromUnicode("Kiểm tra lại số liệu"), Font: &excelize.Font{Family: "VNI-Times"}},
+		},
+		Width:  200,
+		Height: 60,
+	}); err != nil {
+		t.Fatalf("failed to add comment: %v", err)
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	_ = f.Close()
+
+	outputFile, err := NewProcessor(inputFile, "").Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+
+	fOut, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = fOut.Close() }()
+	comments, err := fOut.GetComments("Sheet1")
+	if err != nil || len(comments) != 1 {
+		t.Fatalf("GetComments() = %+v, %v; want one comment", comments, err)
+	}
+	c := comments[0]
+	if c.Cell != "A1" || c.Author != "Nguyễn Văn" {
+		t.Errorf("comment = %q by %q, want A1 by %q", c.Cell, c.Author, "Nguyễn Văn")
+	}
+	if len(c.Paragraph) != 2 {
+		t.Fatalf("comment runs = %+v, want 2", c.Paragraph)
+	}
+	if got := c.Paragraph[1].Text; got != "Kiểm tra lại số liệu" {
+		t.Errorf("comment text = %q", got)
+	}
+	if font := c.Paragraph[0].Font; font == nil || !font.Bold || font.Family != "Times New Roman" {
+		t.Errorf("author run font = %+v, want bold Times New Roman", font)
+	}
+}
+
+func TestProcessor_ConvertThreadedComments(t *testing.T) {
+	const (
+		threadedPart = "xl/threadedComments/threadedComment1.xml"
+		personPart   = "xl/persons/person.xml"
+	)
+	vni := converter.NewVNIEncoder()
+	inputFile := filepath.Join(t.TempDir(), "threaded.xlsx")
+	f := excelize.NewFile()
+	if err := f.SetCellValue("Sheet1", "A1", "Hello"); err != nil {
+		t.Fatalf("failed to set cell: %v", err)
+	}
+	// excelize cannot write threaded comments, so the parts Excel 365 adds are stored as is
+	f.Pkg.Store("xl/worksheets/_rels/sheet1.xml.rels", []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+		`<Relationship Id="rId1" Type="`+threadedCommentRelType+`" Target="../threadedComments/threadedComment1.xml"/></Relationships>`))
+	f.Pkg.Store(threadedPart, []byte(`<ThreadedComments xmlns="http://schemas.microsoft.com/office/spreadsheetml/2018/threadedcomments">`+
+		`<threadedComment ref="A1" personId="{P1}" id="{C1}"><text>`+vni.FromUnicode("Kiểm tra lại")+`</text></threadedComment>`+
+		`<threadedComment ref="B2" personId="{P1}" id="{C2}"><text>`+vni.FromUnicode("Ngoài vùng")+`</text></threadedComment>`+
+		`</ThreadedComments>`))
+	f.Pkg.Store(personPart, []byte(`<personList xmlns="http://schemas.microsoft.com/office/spreadsheetml/2018/threadedcomments">`+
+		`<person displayName="`+vni.FromUnicode("Nguyễn Văn")+`" id="{P1}" providerId="None"/></personList>`))
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	_ = f.Close()
+
+	proc := NewProcessor(inputFile, "")
+	filter, err := ParseCellFilter("A1:A5", "")
+	if err != nil {
+		t.Fatalf("ParseCellFilter failed: %v", err)
+	}
+	proc.SetCellFilter(filter)
+	outputFile, err := proc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+
+	fOut, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = fOut.Close() }()
+	tests := []struct {
+		name string
+		part string
+		want string
+	}{
+		{name: "Comment in range", part: threadedPart, want: "<text>Kiểm tra lại</text>"},
+		{name: "Comment outside range", part: threadedPart, want: "<text>" + vni.FromUnicode("Ngoài vùng") + "</text>"},
+		{name: "Author", part: personPart, want: `displayName="Nguyễn Văn"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _ := fOut.Pkg.Load(tt.part)
+			if got, _ := data.([]byte); !strings.Contains(string(got), tt.want) {
+				t.Errorf("%s = %s, want it to contain %s", tt.part, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// These tests run the whole Processor on workbooks of different shapes with every
+// collector-side feature enabled. Run them with -race (make test-race) to check that only
+// the dispatcher reads the workbook, only the collector writes it, and workers share nothing.
+
+// workbookShape builds a test workbook and returns the expected text of every text cell.
+type workbookShape func(t *testing.T, f *excelize.File) map[string]string
+
+const (
+	shapeLegacy    = "Vi\u00D6t Nam"
+	shapeConverted = "Việt Nam"
+)
+
+// shapeCellKey identifies a cell in the expectations of a workbookShape.
+func shapeCellKey(sheet, axis string) string {
+	return sheet + "!" + axis
+}
+
+func setShapeCell(t *testing.T, f *excelize.File, want map[string]string, sheet string, col, row int, legacy bool) {
+	t.Helper()
+	axis, err := excelize.CoordinatesToCellName(col, row)
+	if err != nil {
+		t.Fatalf("invalid coordinates: %v", err)
+	}
+	text, expected := "Hello", "Hello"
+	if legacy {
+		text, expected = shapeLegacy, shapeConverted
+	}
+	if err := f.SetCellValue(sheet, axis, text); err != nil {
+		t.Fatalf("failed to set %s!%s: %v", sheet, axis, err)
+	}
+	want[shapeCellKey(sheet, axis)] = expected
+}
+
+func shapes(short bool) map[string]workbookShape {
+	rows, cols := 3000, 1000
+	if short {
+		rows, cols = 300, 100
+	}
+	return map[string]workbookShape{
+		"empty workbook": func(*testing.T, *excelize.File) map[string]string {
+			return map[string]string{}
+		},
+		"empty sheets between full ones": func(t *testing.T, f *excelize.File) map[string]string {
+			want := make(map[string]string)
+			for _, sheet := range []string{"Empty1", "Full", "Empty2", "Full2", "Empty3"} {
+				if _, err := f.NewSheet(sheet); err != nil {
+					t.Fatalf("failed to add sheet: %v", err)
+				}
+			}
+			for row := 1; row <= 50; row++ {
+				setShapeCell(t, f, want, "Full", 1, row, true)
+				setShapeCell(t, f, want, "Full2", 2, row, row%2 == 0)
+			}
+			return want
+		},
+		"one huge row": func(t *testing.T, f *excelize.File) map[string]string {
+			want := make(map[string]string)
+			for col := 1; col <= cols; col++ {
+				setShapeCell(t, f, want, "Sheet1", col, 1, col%3 != 0)
+			}
+			return want
+		},
+		"many rows": func(t *testing.T, f *excelize.File) map[string]string {
+			want := make(map[string]string)
+			for row := 1; row <= rows; row++ {
+				setShapeCell(t, f, want, "Sheet1", 1, row, true)
+				setShapeCell(t, f, want, "Sheet1", 3, row, false)
+			}
+			return want
+		},
+		"rich text heavy": func(t *testing.T, f *excelize.File) map[string]string {
+			want := make(map[string]string)
+			for row := 1; row <= rows/10; row++ {
+				axis := fmt.Sprintf("B%d", row)
+				runs := []excelize.RichTextRun{
+					{Text: "Vi\u00D6t ", Font: &excelize.Font{Family: "VNI-Times", Bold: true}},
+					{Text: "Nam ", Font: &excelize.Font{Family: "Arial", Italic: true}},
+					{Text: "C\u00ABng ty", Font: &excelize.Font{Family: ".VnTime"}},
+				}
+				if err := f.SetCellRichText("Sheet1", axis, runs); err != nil {
+					t.Fatalf("failed to set rich text: %v", err)
+				}
+				want[shapeCellKey("Sheet1", axis)] = "Việt Nam Công ty"
+			}
+			return want
+		},
+	}
+}
+
+func TestProcessor_ConcurrencyShapes(t *testing.T) {
+	for name, shape := range shapes(testing.Short()) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			inputFile := filepath.Join(t.TempDir(), "shape.xlsx")
+			f := excelize.NewFile()
+			want := shape(t, f)
+			if err := f.SaveAs(inputFile); err != nil {
+				t.Fatalf("failed to save: %v", err)
+			}
+			_ = f.Close()
+
+			outputFile := runWithAllFeatures(t, inputFile, len(want))
+			defer func() { _ = os.Remove(outputFile) }()
+			assertShapeOutput(t, outputFile, want)
+		})
+	}
+}
+
+func TestAutoWorkerCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		cells int
+		cpus  int
+		want  int
+	}{
+		{name: "Empty workbook", cells: 0, cpus: 8, want: 1},
+		{name: "Few cells", cells: 10, cpus: 8, want: 1},
+		{name: "Just over one worker", cells: cellsPerWorker + 1, cpus: 8, want: 2},
+		{name: "Capped by CPUs", cells: 1000000, cpus: 8, want: 8},
+		{name: "Single CPU", cells: 1000000, cpus: 1, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := autoWorkerCount(tt.cells, tt.cpus); got != tt.want {
+				t.Errorf("autoWorkerCount(%d, %d) = %d, want %d", tt.cells, tt.cpus, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestProcessor_ConcurrentRuns converts several workbooks at once through a shared throttle,
+// as batch and watch mode do.
+func TestProcessor_ConcurrentRuns(t *testing.T) {
+	dir := t.TempDir()
+	throttle := NewIOThrottle(2, 0)
+	var wg sync.WaitGroup
+	for i := range 6 {
+		inputFile := filepath.Join(dir, fmt.Sprintf("book%d.xlsx", i))
+		writeWorkbook(t, inputFile, map[string]string{"A1": shapeLegacy, "A2": "C\u00ABng ty", "B5": "Hello"})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			proc := NewProcessor(inputFile, "")
+			proc.SetIOThrottle(throttle)
+			if i%2 == 0 {
+				detector, err := NewDetector(DetectorNGram)
+				if err != nil {
+					t.Errorf("NewDetector failed: %v", err)
+					return
+				}
+				proc.SetDetector(detector)
+			}
+			outputFile, err := proc.Run(context.Background())
+			if err != nil {
+				t.Errorf("Run(%s) failed: %v", inputFile, err)
+				return
+			}
+			assertShapeOutput(t, outputFile, map[string]string{
+				shapeCellKey("Sheet1", "A1"): shapeConverted,
+				shapeCellKey("Sheet1", "A2"): "Công ty",
+				shapeCellKey("Sheet1", "B5"): "Hello",
+			})
+		}()
+	}
+	wg.Wait()
+}
+
+// runWithAllFeatures runs a Processor with every recorder, tracer and progress channel set,
+// and checks that progress reached cells updates.
+func runWithAllFeatures(t *testing.T, inputFile string, cells int) string {
+	t.Helper()
+	proc := NewProcessor(inputFile, "")
+	proc.SetWriteBatchSize(7) // odd size so batches straddle rows and sheets
+	proc.SetWorkerCount(10)   // more workers than auto mode starts for small shapes
+	proc.SetTracer(NewTimingRecorder())
+	proc.SetDetectionTrace(NewDetectionRecorder())
+	proc.SetChangeReport(NewChangeRecorder())
+	proc.SetCheckpoint(true)
+
+	statusChan := make(chan Status, JobChannelBuffer)
+	proc.SetStatusChan(statusChan)
+	updates := make(chan int)
+	go func() {
+		n := 0
+		for range statusChan {
+			n++
+		}
+		updates <- n
+	}()
+
+	outputFile, err := proc.Run(context.Background())
+	close(statusChan)
+	if n := <-updates; n != cells {
+		t.Errorf("status updates = %d, want %d", n, cells)
+	}
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	return outputFile
+}
+
+func assertShapeOutput(t *testing.T, outputFile string, want map[string]string) {
+	t.Helper()
+	f, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Errorf("failed to open output: %v", err)
+		return
+	}
+	defer func() { _ = f.Close() }()
+	for _, sheet := range f.GetSheetList() {
+		rows, err := f.GetRows(sheet)
+		if err != nil {
+			t.Errorf("failed to read %s: %v", sheet, err)
+			continue
+		}
+		for r, row := range rows {
+			for c, got := range row {
+				if got == "" {
+					continue
+				}
+				axis, _ := excelize.CoordinatesToCellName(c+1, r+1)
+				key := shapeCellKey(sheet, axis)
+				if expected, ok := want[key]; !ok || got != expected {
+					t.Errorf("%s = %q, want %q", key, got, expected)
+				}
+				delete(want, key)
+			}
+		}
+	}
+	for key := range want {
+		t.Errorf("%s is missing from the output", key)
+	}
+}
@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"container/list"
+	"sync"
+)
+
+const (
+	// DefaultConversionCacheSize is the number of converted strings each FormatPreserver keeps.
+	DefaultConversionCacheSize = 10000
+
+	// maxCachedTextLength is the longest text (in bytes) that is cached. Longer text is
+	// rarely repeated and would crowd out the short labels that are.
+	maxCachedTextLength = 256
+)
+
+// conversionKey identifies a conversion within one FormatPreserver, whose encoding is fixed.
+type conversionKey struct {
+	upper bool // converted with the TCVN3 uppercase table
+	text  string
+}
+
+type conversionEntry struct {
+	key       conversionKey
+	converted string
+}
+
+// conversionCache is a least-recently-used cache of converted strings, safe for concurrent use.
+// Why: Workbooks repeat the same legacy strings (headers, names, statuses) tens of thousands
+// of times; converting each rune again for every copy dominates the CPU time of large files.
+type conversionCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[conversionKey]*list.Element
+	order    *list.List // front is the most recently used
+	hits     int
+	misses   int
+}
+
+// newConversionCache creates a cache holding up to capacity strings (0 disables it).
+func newConversionCache(capacity int) *conversionCache {
+	return &conversionCache{
+		capacity: capacity,
+		entries:  make(map[conversionKey]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// convert returns the cached conversion of key, calling convert and caching its result on a miss.
+func (c *conversionCache) convert(key conversionKey, convert func() string) string {
+	if c.capacity <= 0 || len(key.text) > maxCachedTextLength {
+		return convert()
+	}
+
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		c.hits++
+		converted := el.Value.(*conversionEntry).converted
+		c.mu.Unlock()
+		return converted
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// Convert unlocked so workers never wait on each other's conversions; two workers
+	// missing on the same text both convert it, which is harmless
+	converted := convert()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		return converted
+	}
+	c.entries[key] = c.order.PushFront(&conversionEntry{key: key, converted: converted})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*conversionEntry).key)
+	}
+	return converted
+}
+
+// stats returns the number of cache hits and misses so far.
+func (c *conversionCache) stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+func TestConversionCache(t *testing.T) {
+	calls := 0
+	upper := func(text string) func() string {
+		return func() string {
+			calls++
+			return strings.ToUpper(text)
+		}
+	}
+	long := strings.Repeat("a", maxCachedTextLength+1)
+
+	tests := []struct {
+		name      string
+		capacity  int
+		keys      []conversionKey
+		wantCalls int
+	}{
+		{name: "Repeated text", capacity: 2, keys: []conversionKey{{text: "a"}, {text: "a"}, {text: "a"}}, wantCalls: 1},
+		{name: "Uppercase table is a separate entry", capacity: 2, keys: []conversionKey{{text: "a"}, {upper: true, text: "a"}}, wantCalls: 2},
+		{name: "Least recently used is evicted", capacity: 2, keys: []conversionKey{{text: "a"}, {text: "b"}, {text: "a"}, {text: "c"}, {text: "a"}, {text: "b"}}, wantCalls: 4},
+		{name: "Disabled", capacity: 0, keys: []conversionKey{{text: "a"}, {text: "a"}}, wantCalls: 2},
+		{name: "Long text is not cached", capacity: 2, keys: []conversionKey{{text: long}, {text: long}}, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			c := newConversionCache(tt.capacity)
+			for _, key := range tt.keys {
+				if got := c.convert(key, upper(key.text)); got != strings.ToUpper(key.text) {
+					t.Fatalf("convert(%q) = %q", key.text, got)
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("converted %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestProcessor_ConvertsRepeatedTextOnce(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "repeated.xlsx")
+	cells := make(map[string]string)
+	want := make(map[string]string)
+	for row := 1; row <= 200; row++ {
+		cells[fmt.Sprintf("A%d", row)] = "Vi\u00D6t Nam"
+		cells[fmt.Sprintf("B%d", row)] = "Vi\u00D6t"
+		want[shapeCellKey("Sheet1", fmt.Sprintf("A%d", row))] = "Việt Nam"
+		want[shapeCellKey("Sheet1", fmt.Sprintf("B%d", row))] = "Việt"
+	}
+	writeWorkbook(t, inputFile, cells)
+
+	const workers = 4
+	proc := NewProcessor(inputFile, "")
+	proc.SetWorkerCount(workers)
+	if err := proc.SetSourceEncoding(converter.EncodingVNI); err != nil {
+		t.Fatalf("SetSourceEncoding failed: %v", err)
+	}
+	outputFile, err := proc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+
+	// Workers racing on the first copy of a string may each miss once
+	hits, misses := proc.preserver.encodings[converter.EncodingVNI].cache.stats()
+	if hits < 400-2*workers {
+		t.Errorf("cache hits = %d, misses = %d, want at least %d hits", hits, misses, 400-2*workers)
+	}
+	assertShapeOutput(t, outputFile, want)
+}
@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+// DatasetSample is one text run of a processed workbook, as a training example for an
+// encoding detector. Runs that were left alone are included with EncodingUnknown as
+// negative examples.
+type DatasetSample struct {
+	Legacy    string                 `json:"legacy"`
+	Converted string                 `json:"converted"`
+	Encoding  converter.EncodingType `json:"encoding"`
+	Font      string                 `json:"font"`
+	Rule      DetectionRule          `json:"rule"`
+}
+
+// Samples detects and converts every text run of the workbook in memory and passes it to
+// visit, until visit returns false. Nothing is saved; cells over the length guard are skipped.
+// Why: Real workbooks are the best training data for a statistical detector.
+func (p *Processor) Samples(ctx context.Context, visit func(DatasetSample) bool) error {
+	if err := p.openInput(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := p.f.Close(); closeErr != nil {
+			slog.Error("failed to close excel file", "error", closeErr)
+		}
+	}()
+
+	sheets, err := p.resolveSheets()
+	if err != nil {
+		return err
+	}
+	more := true
+	for _, sheet := range sheets {
+		p.walkCells(ctx, sheet, func(job Job) bool {
+			if _, skip := p.oversized(job.Text); skip {
+				return true
+			}
+			for _, run := range job.RichText {
+				if strings.TrimSpace(run.Text) == "" {
+					continue
+				}
+				fontName := ""
+				if run.Font != nil {
+					fontName = run.Font.Family
+				}
+				detection := p.preserver.Detect(fontName, run.Text)
+				converted, _ := p.preserver.ConvertAs(detection.Encoding, fontName, run.Text)
+				more = visit(DatasetSample{
+					Legacy:    run.Text,
+					Converted: converted,
+					Encoding:  detection.Encoding,
+					Font:      fontName,
+					Rule:      detection.Rule,
+				})
+				if !more {
+					return false
+				}
+			}
+			return true
+		})
+		if !more {
+			break
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("sampling cancelled: %w", err)
+	}
+	return nil
+}
+
+// DatasetWriter writes samples as JSON Lines, dropping duplicates.
+// Why: Headers and labels repeat on every sheet; duplicates would skew the training set.
+type DatasetWriter struct {
+	enc        *json.Encoder
+	seen       map[string]struct{}
+	maxSamples int
+	written    int
+}
+
+// NewDatasetWriter creates a writer with no sample limit.
+func NewDatasetWriter(w io.Writer) *DatasetWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &DatasetWriter{enc: enc, seen: make(map[string]struct{})}
+}
+
+// SetMaxSamples caps the number of samples written (0 means unlimited).
+func (w *DatasetWriter) SetMaxSamples(n int) {
+	w.maxSamples = n
+}
+
+// Add writes the sample unless the same text in the same font was already written.
+// It reports false once the writer is full.
+func (w *DatasetWriter) Add(s DatasetSample) (bool, error) {
+	if w.Full() {
+		return false, nil
+	}
+	key := s.Font + "\x00" + s.Legacy
+	if _, ok := w.seen[key]; ok {
+		return true, nil
+	}
+	if err := w.enc.Encode(s); err != nil {
+		return false, fmt.Errorf("failed to write sample: %w", err)
+	}
+	w.seen[key] = struct{}{}
+	w.written++
+	return !w.Full(), nil
+}
+
+// Full reports whether the sample limit was reached.
+func (w *DatasetWriter) Full() bool {
+	return w.maxSamples > 0 && w.written >= w.maxSamples
+}
+
+// Written returns the number of samples written.
+func (w *DatasetWriter) Written() int {
+	return w.written
+}
+
+// ReadDataset parses a JSON Lines dataset written by DatasetWriter.
+func ReadDataset(r io.Reader) ([]DatasetSample, error) {
+	var samples []DatasetSample
+	scanner := bufio.NewScanner(r)
+	// Cells hold up to 32767 characters
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var s DatasetSample
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("failed to parse sample %d: %w", len(samples)+1, err)
+		}
+		samples = append(samples, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	return samples, nil
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+func TestProcessor_Samples(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "samples.xlsx")
+	writeWorkbook(t, inputFile, map[string]string{"A1": "Vi\u00D6t Nam", "A2": "Hello", "A3": "Vi\u00D6t Nam", "A4": " "})
+
+	var got []DatasetSample
+	if err := NewProcessor(inputFile, "").Samples(context.Background(), func(s DatasetSample) bool {
+		got = append(got, s)
+		return true
+	}); err != nil {
+		t.Fatalf("Samples failed: %v", err)
+	}
+
+	want := []DatasetSample{
+		{Legacy: "Vi\u00D6t Nam", Converted: "Việt Nam", Encoding: converter.EncodingVNI, Font: "Calibri", Rule: RuleVNIRunes},
+		{Legacy: "Hello", Converted: "Hello", Encoding: converter.EncodingUnknown, Font: "Calibri", Rule: RuleNone},
+		{Legacy: "Vi\u00D6t Nam", Converted: "Việt Nam", Encoding: converter.EncodingVNI, Font: "Calibri", Rule: RuleVNIRunes},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Samples() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("sample %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDatasetWriter_Add(t *testing.T) {
+	samples := []DatasetSample{
+		{Legacy: "Vi\u00D6t", Converted: "Việt", Encoding: converter.EncodingVNI, Font: "VNI-Times", Rule: RuleFontPrefix},
+		{Legacy: "Vi\u00D6t", Converted: "Việt", Encoding: converter.EncodingVNI, Font: "VNI-Times", Rule: RuleFontPrefix},
+		{Legacy: "Vi\u00D6t", Converted: "Việt", Encoding: converter.EncodingVNI, Font: "Arial", Rule: RuleVNIRunes},
+		{Legacy: "Hello", Converted: "Hello", Encoding: converter.EncodingUnknown, Rule: RuleNone},
+	}
+	tests := []struct {
+		name        string
+		maxSamples  int
+		wantWritten int
+	}{
+		{name: "Unlimited drops duplicates", wantWritten: 3},
+		{name: "Limit stops the writer", maxSamples: 2, wantWritten: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewDatasetWriter(&buf)
+			w.SetMaxSamples(tt.maxSamples)
+			for _, s := range samples {
+				if _, err := w.Add(s); err != nil {
+					t.Fatalf("Add failed: %v", err)
+				}
+			}
+			if w.Written() != tt.wantWritten {
+				t.Errorf("Written() = %d, want %d", w.Written(), tt.wantWritten)
+			}
+
+			// The repeated second sample is dropped
+			want := []DatasetSample{samples[0], samples[2], samples[3]}[:tt.wantWritten]
+			var got []DatasetSample
+			scanner := bufio.NewScanner(&buf)
+			for scanner.Scan() {
+				var s DatasetSample
+				if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+					t.Fatalf("line %d is not a sample: %v", len(got), err)
+				}
+				got = append(got, s)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("dataset = %+v, want %+v", got, want)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("line %d = %+v, want %+v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
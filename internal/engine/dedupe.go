@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"convert-vni-to-unicode/internal/cache"
+)
+
+// CopyOutputFunc writes the output of input, a byte-identical copy of original, from
+// originalOutput (the output converted for original) and returns its path.
+type CopyOutputFunc func(input, original, originalOutput string) (string, error)
+
+// FindDuplicates maps every path whose content is byte-identical to an earlier path of
+// paths to that earlier path. Only files sharing their size are hashed; files that cannot
+// be read are left to fail in their own conversion.
+func FindDuplicates(paths []string) map[string]string {
+	seen := make(map[string]bool, len(paths))
+	bySize := make(map[int64][]string)
+	var sizes []int64
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if _, ok := bySize[info.Size()]; !ok {
+			sizes = append(sizes, info.Size())
+		}
+		bySize[info.Size()] = append(bySize[info.Size()], path)
+	}
+
+	duplicates := make(map[string]string)
+	for _, size := range sizes {
+		group := bySize[size]
+		if len(group) < 2 {
+			continue
+		}
+		first := make(map[string]string, len(group))
+		for _, path := range group {
+			hash, err := cache.HashFile(path)
+			if err != nil {
+				continue
+			}
+			if original, ok := first[hash]; ok {
+				duplicates[path] = original
+			} else {
+				first[hash] = path
+			}
+		}
+	}
+	return duplicates
+}
+
+// CopyDuplicateOutput is the CopyOutputFunc of local batches: it copies originalOutput
+// under the output name input would have got, next to input when originalOutput was
+// written next to original, else in the same output folder.
+// Why: A copy, unlike a link, can be edited or deleted on its own, like any other output.
+func CopyDuplicateOutput(input, original, originalOutput string) (string, error) {
+	dir := filepath.Dir(originalOutput)
+	if dir == filepath.Dir(original) {
+		dir = filepath.Dir(input)
+	}
+	originalStem := strings.TrimSuffix(filepath.Base(original), filepath.Ext(original))
+	stem := strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+	name := filepath.Base(originalOutput)
+	if rest, ok := strings.CutPrefix(name, originalStem); ok {
+		name = stem + rest
+	} else {
+		name = stem + "_" + name
+	}
+
+	dst, err := reserveOutputPath(filepath.Join(dir, name))
+	if err != nil {
+		return "", err
+	}
+	if err := copyFile(originalOutput, dst); err != nil {
+		_ = os.Remove(dst)
+		return "", fmt.Errorf("failed to copy the output of %s: %w", filepath.Base(original), err)
+	}
+	return dst, nil
+}
+
+// copyFile copies the content of src over the existing file dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src) //nolint:gosec // src is an output of this batch
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_TRUNC, 0) //nolint:gosec // dst was reserved by reserveOutputPath
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,138 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// writeFiles writes content to each named file of dir and returns their paths in order.
+func writeFiles(t *testing.T, dir string, files [][2]string) []string {
+	t.Helper()
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = filepath.Join(dir, f[0])
+		if err := os.WriteFile(paths[i], []byte(f[1]), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", f[0], err)
+		}
+	}
+	return paths
+}
+
+func TestFindDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	paths := writeFiles(t, dir, [][2]string{
+		{"a.xlsx", "same"},
+		{"b.xlsx", "diff"}, // same size, other content
+		{"copy of a.xlsx", "same"},
+		{"c.xlsx", "longer content"},
+		{"a (2).xlsx", "same"},
+	})
+	paths = append(paths, paths[0], filepath.Join(dir, "missing.xlsx"))
+
+	got := FindDuplicates(paths)
+	want := map[string]string{paths[2]: paths[0], paths[4]: paths[0]}
+	if len(got) != len(want) {
+		t.Fatalf("FindDuplicates() = %v, want %v", got, want)
+	}
+	for dup, original := range want {
+		if got[dup] != original {
+			t.Errorf("duplicate %s -> %q, want %q", filepath.Base(dup), got[dup], original)
+		}
+	}
+}
+
+func TestCopyDuplicateOutput(t *testing.T) {
+	dir := t.TempDir()
+	outDir := filepath.Join(dir, "out")
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(outDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name           string
+		original       string
+		originalOutput string
+		input          string
+		want           string
+	}{
+		{name: "Next to the input", original: filepath.Join(dir, "a.xlsx"), originalOutput: filepath.Join(dir, "a_output_2024.xlsx"),
+			input: filepath.Join(dir, "sub", "b.xlsx"), want: filepath.Join(dir, "sub", "b_output_2024.xlsx")},
+		{name: "Output folder", original: filepath.Join(dir, "a.xlsx"), originalOutput: filepath.Join(outDir, "a_output_2024.xlsx"),
+			input: filepath.Join(dir, "sub", "b.xlsx"), want: filepath.Join(outDir, "b_output_2024.xlsx")},
+		{name: "Name taken", original: filepath.Join(dir, "a.xlsx"), originalOutput: filepath.Join(outDir, "a_output_2025.xlsx"),
+			input: filepath.Join(dir, "sub", "a.xlsx"), want: filepath.Join(outDir, "a_output_2025_2.xlsx")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(tt.originalOutput, []byte("converted"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := CopyDuplicateOutput(tt.input, tt.original, tt.originalOutput)
+			if err != nil {
+				t.Fatalf("CopyDuplicateOutput() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CopyDuplicateOutput() = %s, want %s", got, tt.want)
+			}
+			if data, err := os.ReadFile(got); err != nil || string(data) != "converted" {
+				t.Errorf("copied output = %q, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestRunBatchDeduped(t *testing.T) {
+	dir := t.TempDir()
+	paths := writeFiles(t, dir, [][2]string{
+		{"a.xlsx", "same"},
+		{"bad.xlsx", "oops"},
+		{"copy of a.xlsx", "same"},
+		{"copy of bad.xlsx", "oops"},
+		{"c.xlsx", "other"},
+	})
+	var converted atomic.Int32
+	convert := func(_ context.Context, path string) (string, error) {
+		converted.Add(1)
+		if strings.HasSuffix(path, "bad.xlsx") {
+			return "", errors.New("broken")
+		}
+		return path + ".out", nil
+	}
+	copyOutput := func(input, original, originalOutput string) (string, error) {
+		return input + ".copy of " + filepath.Base(originalOutput), nil
+	}
+
+	for _, parallel := range []int{1, 3} {
+		converted.Store(0)
+		results := RunBatchDeduped(context.Background(), paths, parallel, convert, copyOutput, nil)
+		if n := converted.Load(); n != 3 {
+			t.Errorf("parallel %d: converted %d files, want 3", parallel, n)
+		}
+		want := []FileResult{
+			{InputPath: paths[0], OutputPath: paths[0] + ".out"},
+			{InputPath: paths[1], Error: "broken"},
+			{InputPath: paths[2], OutputPath: paths[2] + ".copy of a.xlsx.out", DuplicateOf: paths[0]},
+			{InputPath: paths[3], Error: "same content as bad.xlsx, which failed: broken", DuplicateOf: paths[1]},
+			{InputPath: paths[4], OutputPath: paths[4] + ".out"},
+		}
+		for i, r := range results {
+			if r != want[i] {
+				t.Errorf("parallel %d: result %d = %+v, want %+v", parallel, i, r, want[i])
+			}
+		}
+	}
+
+	// Without copyOutput every file is converted
+	converted.Store(0)
+	RunBatchDeduped(context.Background(), paths, 2, convert, nil, nil)
+	if n := converted.Load(); n != int32(len(paths)) {
+		t.Errorf("converted %d files without dedupe, want %d", n, len(paths))
+	}
+}
@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// RunDetection records how one rich-text run's encoding was decided.
+type RunDetection struct {
+	SheetName string
+	Axis      string
+	Run       int // index of the run within the cell
+	FontName  string
+	Detection
+}
+
+// DetectionRecorder keeps the detection outcome of every converted run in memory.
+// Why: Wrong conversions are diagnosed from the trace (which rule fired, on which font or
+// character) without needing the customer's file. Safe for concurrent use by workers.
+type DetectionRecorder struct {
+	mu        sync.Mutex
+	entries   []RunDetection
+	buildInfo *BuildInfo
+}
+
+// NewDetectionRecorder creates an empty recorder.
+func NewDetectionRecorder() *DetectionRecorder {
+	return &DetectionRecorder{}
+}
+
+// SetBuildInfo adds a "# build" comment line to the top of the CSV trace.
+func (r *DetectionRecorder) SetBuildInfo(b BuildInfo) {
+	r.buildInfo = &b
+}
+
+// Record adds the detection of one run.
+func (r *DetectionRecorder) Record(job Job, run int, fontName string, d Detection) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, RunDetection{
+		SheetName: job.SheetName,
+		Axis:      job.Axis,
+		Run:       run,
+		FontName:  fontName,
+		Detection: d,
+	})
+}
+
+// Detections returns a copy of the recorded detections in sheet, row, column and run order.
+func (r *DetectionRecorder) Detections() []RunDetection {
+	r.mu.Lock()
+	out := make([]RunDetection, len(r.entries))
+	copy(out, r.entries)
+	r.mu.Unlock()
+
+	// Workers finish out of order; sort so traces of the same file are comparable
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.SheetName == b.SheetName && a.Axis == b.Axis {
+			return a.Run < b.Run
+		}
+		return cellLess(a.SheetName, a.Axis, b.SheetName, b.Axis)
+	})
+	return out
+}
+
+// cellLess orders cells by sheet name, then row, then column.
+func cellLess(sheetA, axisA, sheetB, axisB string) bool {
+	if sheetA != sheetB {
+		return sheetA < sheetB
+	}
+	ac, ar, _ := excelize.CellNameToCoordinates(axisA)
+	bc, br, _ := excelize.CellNameToCoordinates(axisB)
+	if ar != br {
+		return ar < br
+	}
+	return ac < bc
+}
+
+// WriteCSV writes the detection trace to w.
+// Readers should set csv.Reader.Comment to '#' when a build line is present.
+func (r *DetectionRecorder) WriteCSV(w io.Writer) error {
+	if r.buildInfo != nil {
+		if _, err := fmt.Fprintf(w, "# %s\n", r.buildInfo); err != nil {
+			return fmt.Errorf("failed to write build header: %w", err)
+		}
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"sheet", "cell", "run", "font", "encoding", "rule", "evidence"}); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	for _, d := range r.Detections() {
+		record := []string{
+			d.SheetName,
+			d.Axis,
+			strconv.Itoa(d.Run),
+			d.FontName,
+			string(d.Encoding),
+			string(d.Rule),
+			d.Evidence,
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv record: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
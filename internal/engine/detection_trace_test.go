@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+func TestDetectionRecorder_WriteCSV(t *testing.T) {
+	rec := NewDetectionRecorder()
+	rec.Record(Job{SheetName: "Sheet1", Axis: "B10"}, 0, "", Detection{Encoding: converter.EncodingUnknown, Rule: RuleNone})
+	rec.Record(Job{SheetName: "Sheet1", Axis: "A2"}, 1, "", Detection{Encoding: converter.EncodingVNI, Rule: RuleVNIRunes, Evidence: "U+00D6"})
+	rec.Record(Job{SheetName: "Sheet1", Axis: "A2"}, 0, "VNI-Times", Detection{Encoding: converter.EncodingVNI, Rule: RuleFontPrefix, Evidence: "VNI-Times"})
+	rec.SetBuildInfo(NewBuildInfo("1.2.3"))
+
+	var buf bytes.Buffer
+	if err := rec.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	r := csv.NewReader(&buf)
+	r.Comment = '#'
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse csv: %v", err)
+	}
+	want := [][]string{
+		{"sheet", "cell", "run", "font", "encoding", "rule", "evidence"},
+		{"Sheet1", "A2", "0", "VNI-Times", "VNI", "font-prefix", "VNI-Times"},
+		{"Sheet1", "A2", "1", "", "VNI", "vni-runes", "U+00D6"},
+		{"Sheet1", "B10", "0", "", "UNKNOWN", "none", ""},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("records = %v, want %v", records, want)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if records[i][j] != want[i][j] {
+				t.Errorf("record %d = %v, want %v", i, records[i], want[i])
+				break
+			}
+		}
+	}
+}
+
+func TestProcessor_RunWithDetectionTrace(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding converter.EncodingType
+		wantRule DetectionRule
+	}{
+		{name: "Auto-detect", wantRule: RuleVNIRunes},
+		{name: "Forced encoding", encoding: converter.EncodingVNI, wantRule: RuleForced},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputFile := filepath.Join(t.TempDir(), "detect.xlsx")
+			writeWorkbook(t, inputFile, map[string]string{"A1": "Vi\u00D6t Nam"})
+
+			rec := NewDetectionRecorder()
+			proc := NewProcessor(inputFile, "")
+			if err := proc.SetSourceEncoding(tt.encoding); err != nil {
+				t.Fatalf("SetSourceEncoding failed: %v", err)
+			}
+			proc.SetDetectionTrace(rec)
+			outputFile, err := proc.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			defer func() { _ = os.Remove(outputFile) }()
+
+			got := rec.Detections()
+			if len(got) != 1 {
+				t.Fatalf("Detections() = %+v, want one run", got)
+			}
+			if got[0].Axis != "A1" || got[0].Rule != tt.wantRule || got[0].Encoding != converter.EncodingVNI {
+				t.Errorf("detection = %+v, want A1 %s VNI", got[0], tt.wantRule)
+			}
+		})
+	}
+}
@@ -2,37 +2,180 @@
 package engine
 
 import (
-	"convert-vni-to-unicode/internal/converter"
+	"fmt"
 	"strings"
+	"unicode/utf8"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+// DetectionRule names the rule that decided a run's encoding.
+type DetectionRule string
+
+const (
+	// RuleNone means no rule matched; the run is left unconverted.
+	RuleNone DetectionRule = "none"
+	// RuleForced means the source encoding was chosen by the user, not detected.
+	RuleForced DetectionRule = "forced"
+	// RuleUnicode means the text is already precomposed Vietnamese Unicode; it is left alone.
+	RuleUnicode DetectionRule = "unicode"
+	// RuleFontPrefix matched a legacy font name such as "VNI-Times" or ".VnTime".
+	RuleFontPrefix DetectionRule = "font-prefix"
+	// RuleVNUPattern matched a letter followed by a VNU mark.
+	RuleVNUPattern DetectionRule = "vnu-pattern"
+	// RuleVNIRunes matched a character only VNI text contains.
+	RuleVNIRunes DetectionRule = "vni-runes"
+	// RuleTCVN3Runes matched a character only TCVN3 text contains.
+	RuleTCVN3Runes DetectionRule = "tcvn3-runes"
+	// RuleNGram means the n-gram models scored the text (see NGramDetector).
+	RuleNGram DetectionRule = "ngram"
+	// RuleEnglish means the text is clearly English; it is left alone (see detectEnglish).
+	RuleEnglish DetectionRule = "english"
+)
+
+// Detector names accepted by NewDetector.
+const (
+	DetectorRules = "rules"
+	DetectorNGram = "ngram"
+)
+
+const (
+	// vniMarkers are VNI-specific markers:
+	// Â = circumflex, Ê = breve, Ø = grave, Ù = acute, Û = hook, Ï = dot,
+	// Å/Ä/Ü = circumflex/breve with a tone, Ô/Ö = ơ/ư, ñ/Ñ = đ/Đ
+	vniMarkers = "\u00C2\u00CA\u00D4\u00D8\u00D9\u00DB\u00DC\u00CF\u00C5\u00C4\u00D6\u00F1\u00D1" +
+		"\u00E2\u00EA\u00F4\u00F8\u00F9\u00FB\u00FC\u00EF\u00E5\u00E4"
+	// tcvn3Markers are common TCVN3 letters that differ from Unicode/VNI
+	// (\u00A8-\u00AE are ă â ê ô ơ ư đ, e.g. \u00AB -> ô).
+	tcvn3Markers = "\u00A8\u00A9\u00AA\u00AB\u00AC\u00AD\u00AE\u00F6\u00F4\u00E2\u00EA\u00EE\u00B9"
 )
 
+// Detection is the outcome of encoding detection for one run.
+type Detection struct {
+	Encoding converter.EncodingType
+	Rule     DetectionRule
+	// Evidence is what the rule matched: the font name, or the code point of the marker
+	// character (never the text itself, so traces can be shared without leaking content)
+	Evidence string
+}
+
+// ruleConfidence scores how reliable each rule is, from 0 (no evidence) to 1 (user choice).
+// Why: Font names are near-certain, while the content markers overlap accented Western text
+// (TCVN3's markers more so than VNI's), so callers can route low scores to manual review.
+var ruleConfidence = map[DetectionRule]float64{
+	RuleForced:     1,
+	RuleUnicode:    1,
+	RuleFontPrefix: 0.95,
+	RuleVNUPattern: 0.9,
+	RuleVNIRunes:   0.7,
+	RuleTCVN3Runes: 0.6,
+	RuleNGram:      0.8,
+	RuleEnglish:    0.8,
+	RuleNone:       0,
+}
+
+// Confidence returns the heuristic reliability of the detection, between 0 and 1.
+func (d Detection) Confidence() float64 {
+	return ruleConfidence[d.Rule]
+}
+
+// Detector chooses the source encoding of a text run.
+// Why: The rule-based and statistical detectors are interchangeable, so they can be
+// selected in settings and A/B compared on the same corpus.
+type Detector interface {
+	Detect(fontName, text string) Detection
+}
+
+// RuleDetector is the rule-based detector (see Detect).
+type RuleDetector struct{}
+
+// Detect implements Detector.
+func (RuleDetector) Detect(fontName, text string) Detection {
+	return Detect(fontName, text)
+}
+
+// NewDetector creates a detector by name. An empty name selects the rule-based detector.
+func NewDetector(name string) (Detector, error) {
+	switch name {
+	case "", DetectorRules:
+		return RuleDetector{}, nil
+	case DetectorNGram:
+		d, err := defaultNGramDetector()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load n-gram models: %w", err)
+		}
+		return d, nil
+	default:
+		return nil, fmt.Errorf("unknown detector %q (use rules or ngram)", name)
+	}
+}
+
 // DetectEncoding attempts to identify the encoding based on font name and content.
 // Why: Allows for "Auto" mode where the system guesses the encoding.
 func DetectEncoding(fontName string, text string) converter.EncodingType {
+	return Detect(fontName, text).Encoding
+}
+
+// Detect is DetectEncoding that also reports which rule fired.
+// Why: Support can see why a cell was (not) converted from a trace, without the customer's file.
+func Detect(fontName string, text string) Detection {
 	// 1. Check Font Name (Strongest indicator)
-	if strings.HasPrefix(fontName, "VNI-") {
-		return converter.EncodingVNI
+	if d, ok := detectByFont(fontName); ok {
+		return d
+	}
+
+	// 2. Check content (Heuristic), unless the text is clearly English
+	if d, ok := detectEnglish(text); ok {
+		return d
+	}
+	// VNU marks directly follow a letter and don't overlap the VNI/TCVN3 ranges.
+	if converter.HasVNUPattern(text) {
+		return Detection{Encoding: converter.EncodingVNU, Rule: RuleVNUPattern}
 	}
-	if strings.HasPrefix(fontName, ".Vn") {
-		return converter.EncodingTCVN3
+
+	// VNI uses combining marks.
+	if i := strings.IndexAny(text, vniMarkers); i >= 0 {
+		return Detection{Encoding: converter.EncodingVNI, Rule: RuleVNIRunes, Evidence: codePointAt(text, i)}
 	}
 
-	// 2. Check content (Heuristic)
-	// VNI uses combining marks. Check for common VNI-specific markers:
-	// Â/Ê/Ô = circumflex, Ø = grave, Ù = acute, Û = hook, Ü = tilde, Ï = dot
-	// Å = breve, Ö = horn, ñ/Ñ = đ/Đ
-	if strings.ContainsAny(text, "\u00C2\u00CA\u00D4\u00D8\u00D9\u00DB\u00DC\u00CF\u00C5\u00D6\u00F1\u00D1"+
-		"\u00E2\u00EA\u00F4\u00F8\u00F9\u00FB\u00FC\u00EF\u00E5") {
-		return converter.EncodingVNI
+	// TCVN3 uses specific high-byte chars.
+	if i := strings.IndexAny(text, tcvn3Markers); i >= 0 {
+		return Detection{Encoding: converter.EncodingTCVN3, Rule: RuleTCVN3Runes, Evidence: codePointAt(text, i)}
 	}
 
-	// TCVN3 uses specific high-byte chars. Example: \u00B9, \u00AE, \u00A9 ...
-	// Cöng ty -> 'ö' is \u00F6. 'ô' in TCVN3 is \u00F4.
-	// Check for common TCVN3 vowels that differ from Unicode/VNI.
-	// TCVN3 map: \u00F6 -> ô.
-	if strings.ContainsAny(text, "\u00F6\u00F4\u00E2\u00EA\u00EE\u00B9") {
-		return converter.EncodingTCVN3
+	return Detection{Encoding: converter.EncodingUnknown, Rule: RuleNone}
+}
+
+// detectUnicode matches text that is already precomposed Vietnamese Unicode.
+// Why: It runs before the font name and forced encoding, which would otherwise convert
+// Unicode text typed into a cell that still has a legacy font.
+func detectUnicode(text string) (Detection, bool) {
+	i := converter.IndexPrecomposedVietnamese(text)
+	if i < 0 {
+		return Detection{}, false
 	}
+	return Detection{Encoding: converter.EncodingUnknown, Rule: RuleUnicode, Evidence: codePointAt(text, i)}, true
+}
+
+// detectByFont matches legacy font name prefixes such as "VNI-" and ".Vn".
+func detectByFont(fontName string) (Detection, bool) {
+	for _, prefix := range []struct {
+		prefix   string
+		encoding converter.EncodingType
+	}{
+		{"VNI-", converter.EncodingVNI},
+		{".Vn", converter.EncodingTCVN3},
+		{"VNU-", converter.EncodingVNU},
+	} {
+		if strings.HasPrefix(fontName, prefix.prefix) {
+			return Detection{Encoding: prefix.encoding, Rule: RuleFontPrefix, Evidence: fontName}, true
+		}
+	}
+	return Detection{}, false
+}
 
-	return converter.EncodingUnknown
+// codePointAt formats the rune starting at byte offset i as "U+00D6".
+func codePointAt(text string, i int) string {
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return fmt.Sprintf("U+%04X", r)
 }
@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+// Misclassification counts samples of one label detected as another encoding.
+type Misclassification struct {
+	Label    converter.EncodingType
+	Detected converter.EncodingType
+	Count    int
+}
+
+// DetectorResult is the accuracy of one detector on a labelled corpus.
+type DetectorResult struct {
+	Name    string
+	Correct int
+	Total   int
+	// Errors lists the wrong answers, most frequent first
+	Errors []Misclassification
+}
+
+// Accuracy returns the share of correctly detected samples (0 for an empty corpus).
+func (r DetectorResult) Accuracy() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Correct) / float64(r.Total)
+}
+
+// EvaluateDetector runs d on every sample's legacy text and font and compares the result
+// with the sample's encoding, which is the label.
+// Why: Detector changes are A/B compared on the same QA corpus before being switched on.
+func EvaluateDetector(name string, d Detector, samples []DatasetSample) DetectorResult {
+	result := DetectorResult{Name: name, Total: len(samples)}
+	errors := make(map[Misclassification]int)
+	for _, s := range samples {
+		detected := d.Detect(s.Font, s.Legacy).Encoding
+		if detected == s.Encoding {
+			result.Correct++
+			continue
+		}
+		errors[Misclassification{Label: s.Encoding, Detected: detected}]++
+	}
+	for m, n := range errors {
+		m.Count = n
+		result.Errors = append(result.Errors, m)
+	}
+	sort.Slice(result.Errors, func(i, j int) bool {
+		a, b := result.Errors[i], result.Errors[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Label != b.Label {
+			return a.Label < b.Label
+		}
+		return a.Detected < b.Detected
+	})
+	return result
+}
+
+// WriteDetectorResults prints an accuracy table followed by each detector's errors.
+func WriteDetectorResults(w io.Writer, results []DetectorResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	if _, err := fmt.Fprintln(tw, "detector\tcorrect\ttotal\taccuracy\t"); err != nil {
+		return fmt.Errorf("failed to write results header: %w", err)
+	}
+	for _, r := range results {
+		if _, err := fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f%%\t\n", r.Name, r.Correct, r.Total, 100*r.Accuracy()); err != nil {
+			return fmt.Errorf("failed to write result: %w", err)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, r := range results {
+		for _, m := range r.Errors {
+			_, err := fmt.Fprintf(w, "%s: %d %s sample(s) detected as %s\n", r.Name, m.Count, m.Label, m.Detected)
+			if err != nil {
+				return fmt.Errorf("failed to write errors: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		font     string
+		text     string
+		expected converter.EncodingType
+	}{
+		{name: "VNI font", font: "VNI-Times", text: "abc", expected: converter.EncodingVNI},
+		{name: "TCVN3 font", font: ".VnTime", text: "abc", expected: converter.EncodingTCVN3},
+		{name: "VNU font", font: "VNU-Times", text: "abc", expected: converter.EncodingVNU},
+		{name: "VNI content", text: "ViÖt Nam", expected: converter.EncodingVNI},
+		{name: "TCVN3 content", text: "C«ng ty", expected: converter.EncodingTCVN3},
+		{name: "VNU content", text: "Vieª±t Nam", expected: converter.EncodingVNU},
+		{name: "Plain ASCII", font: "Arial", text: "Hello", expected: converter.EncodingUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectEncoding(tt.font, tt.text); got != tt.expected {
+				t.Errorf("DetectEncoding() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetect_Rule(t *testing.T) {
+	tests := []struct {
+		name     string
+		font     string
+		text     string
+		rule     DetectionRule
+		evidence string
+	}{
+		{name: "Font prefix", font: ".VnTime", text: "abc", rule: RuleFontPrefix, evidence: ".VnTime"},
+		{name: "VNU pattern", text: "Vieª±t Nam", rule: RuleVNUPattern},
+		{name: "VNI runes", text: "Vi\u00D6t Nam", rule: RuleVNIRunes, evidence: "U+00D6"},
+		{name: "TCVN3 runes", text: "C\u00ABng ty", rule: RuleTCVN3Runes, evidence: "U+00AB"},
+		{name: "No rule", font: "Arial", text: "Hello", rule: RuleNone},
+		{name: "Unicode before font", font: "VNI-Times", text: "Công ty Việt Nam", rule: RuleUnicode, evidence: "U+1EC7"},
+		{name: "English", text: "M\u00FCller Street Total", rule: RuleEnglish, evidence: "words=2"},
+		{name: "Font before English", font: "VNI-Times", text: "Total Amount", rule: RuleFontPrefix, evidence: "VNI-Times"},
+		{name: "Legacy words outnumber English", text: "Co\u00E2ng ty Ha\u00F8 No\u00E2\u00efi Limited", rule: RuleVNIRunes, evidence: "U+00E2"},
+		{name: "Single English word", text: "Hello \u00D6", rule: RuleVNIRunes, evidence: "U+00D6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newFormatPreserver().Detect(tt.font, tt.text)
+			if got.Rule != tt.rule || got.Evidence != tt.evidence {
+				t.Errorf("Detect() = %s (%q), want %s (%q)", got.Rule, got.Evidence, tt.rule, tt.evidence)
+			}
+		})
+	}
+}
+
+func TestNewDetector(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Detector
+		wantErr bool
+	}{
+		{name: "", want: RuleDetector{}},
+		{name: DetectorRules, want: RuleDetector{}},
+		{name: DetectorNGram},
+		{name: "bayes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDetector(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewDetector(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if tt.want != nil && got != tt.want {
+				t.Errorf("NewDetector(%q) = %T, want %T", tt.name, got, tt.want)
+			}
+			if tt.name == DetectorNGram {
+				if _, ok := got.(*NGramDetector); !ok {
+					t.Errorf("NewDetector(%q) = %T, want *NGramDetector", tt.name, got)
+				}
+			}
+		})
+	}
+}
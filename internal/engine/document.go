@@ -0,0 +1,595 @@
+package engine
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// errDocumentUnsupported is returned by Run for a document or presentation combined with a
+// workbook-only setting.
+var errDocumentUnsupported = errors.New(
+	"documents and presentations cannot be converted in place, in large file mode or with checkpoints")
+
+// documentStylesPart holds the styles runs inherit their font from.
+const documentStylesPart = "word/styles.xml"
+
+// IsDocument reports whether path is a Word document Run converts as a document.
+func IsDocument(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".docx")
+}
+
+// isDocumentTextPart reports whether name is a part of a document whose runs are converted:
+// the body, headers and footers.
+func isDocumentTextPart(name string) bool {
+	if name == "word/document.xml" {
+		return true
+	}
+	header, _ := path.Match("word/header*.xml", name)
+	footer, _ := path.Match("word/footer*.xml", name)
+	return header || footer
+}
+
+// ConvertedRuns returns how many runs the last Run converted in a document or presentation.
+// It is zero after converting a workbook or a text file.
+func (p *Processor) ConvertedRuns() int {
+	return p.convertedRuns
+}
+
+// runDocument converts the Word document at InputPath and saves it under a new output
+// name, like Run does for workbooks. Each run of the body, headers and footers is
+// converted from the encoding of its font (or its content), and converted runs get the
+// font the policy maps to; every other part is copied unchanged.
+// Why: Legacy reports and contracts come as Word documents as often as workbooks, and
+// Word's own converters do not know VNI or TCVN3.
+func (p *Processor) runDocument(ctx context.Context) (string, error) {
+	return p.runPackage(ctx, "document", func(r *zip.Reader) (packageConverter, error) {
+		styles, err := readDocumentStyles(r)
+		if err != nil {
+			return packageConverter{}, err
+		}
+		return packageConverter{
+			isTextPart: isDocumentTextPart,
+			convert:    func(_ string, data []byte) ([]byte, int, error) { return p.convertDocumentPart(data, styles) },
+		}, nil
+	})
+}
+
+// packageConverter converts the text parts of an Office package (a document or a
+// presentation); its other parts are copied unchanged.
+type packageConverter struct {
+	isTextPart func(name string) bool
+	// convert returns the text part name converted, with the number of runs converted
+	convert func(name string, data []byte) ([]byte, int, error)
+}
+
+// runPackage converts the Office package at InputPath with the converter newConverter
+// prepares for it, and saves it under a new output name. kind names the package in errors.
+func (p *Processor) runPackage(ctx context.Context, kind string,
+	newConverter func(r *zip.Reader) (packageConverter, error)) (string, error) {
+	p.convertedRuns = 0
+	if p.backupMode != BackupNone || p.largeFileMode || p.checkpointing {
+		return "", errDocumentUnsupported
+	}
+	release, err := p.throttle.Acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	r, err := zip.OpenReader(p.InputPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", kind, err)
+	}
+	defer func() { _ = r.Close() }()
+
+	pc, err := newConverter(&r.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	outputPath, err := reserveOutputPath(p.outputPath(time.Now()))
+	if err != nil {
+		return "", err
+	}
+	if err := p.writePackage(ctx, &r.Reader, pc, outputPath); err != nil {
+		// Never leave a truncated file behind
+		if rmErr := os.Remove(outputPath); rmErr != nil && !os.IsNotExist(rmErr) {
+			slog.Error("failed to remove partial output", "path", outputPath, "error", rmErr)
+		}
+		return "", err
+	}
+	slog.Info("converted "+kind, "runs", p.convertedRuns)
+
+	if p.sharedOutput {
+		if err := applySharedPermissions(outputPath); err != nil {
+			slog.Warn("failed to apply shared permissions", "path", outputPath, "error", err)
+		}
+	}
+	return outputPath, nil
+}
+
+// writePackage writes the parts of r to outputPath, converting the text parts with pc.
+func (p *Processor) writePackage(ctx context.Context, r *zip.Reader, pc packageConverter, outputPath string) error {
+	out, err := os.Create(outputPath) //nolint:gosec // path built from the input path
+	if err != nil {
+		return fmt.Errorf("failed to save output file: %w", err)
+	}
+	w := zip.NewWriter(out)
+	writeErr := p.copyPackageParts(ctx, r, w, pc)
+	if closeErr := w.Close(); closeErr != nil && writeErr == nil {
+		writeErr = fmt.Errorf("failed to save output file: %w", closeErr)
+	}
+	if closeErr := out.Close(); closeErr != nil && writeErr == nil {
+		writeErr = fmt.Errorf("failed to save output file: %w", closeErr)
+	}
+	return writeErr
+}
+
+func (p *Processor) copyPackageParts(ctx context.Context, r *zip.Reader, w *zip.Writer, pc packageConverter) error {
+	for _, part := range r.File {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("conversion cancelled: %w", err)
+		}
+		if !pc.isTextPart(part.Name) {
+			// Copied compressed, so untouched parts stay byte-identical
+			if err := copyRawPart(w, part); err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := readZipFile(part)
+		if err != nil {
+			return err
+		}
+		converted, runs, err := pc.convert(part.Name, data)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s: %w", part.Name, err)
+		}
+		p.convertedRuns += runs
+		header := part.FileHeader
+		header.Method = zip.Deflate
+		dst, err := w.CreateHeader(&header)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", part.Name, err)
+		}
+		if _, err := dst.Write(converted); err != nil {
+			return fmt.Errorf("failed to write %s: %w", part.Name, err)
+		}
+	}
+	return nil
+}
+
+func copyRawPart(w *zip.Writer, part *zip.File) error {
+	src, err := part.OpenRaw()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", part.Name, err)
+	}
+	header := part.FileHeader
+	dst, err := w.CreateRaw(&header)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", part.Name, err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("failed to write %s: %w", part.Name, err)
+	}
+	return nil
+}
+
+func readZipFile(part *zip.File) ([]byte, error) {
+	src, err := part.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", part.Name, err)
+	}
+	defer func() { _ = src.Close() }()
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", part.Name, err)
+	}
+	return data, nil
+}
+
+// documentStyles resolves the font a run inherits from its character style, its
+// paragraph style or the document defaults.
+type documentStyles struct {
+	defaultFont      string
+	defaultParagraph string
+	styles           map[string]documentStyle
+}
+
+type documentStyle struct {
+	basedOn string
+	font    string
+}
+
+// xmlFonts is a w:rFonts element.
+type xmlFonts struct {
+	ASCII string `xml:"ascii,attr"`
+	HAnsi string `xml:"hAnsi,attr"`
+	CS    string `xml:"cs,attr"`
+}
+
+// family returns the font of Latin text: the ASCII font, or the high ANSI one.
+func (f *xmlFonts) family() string {
+	if f == nil {
+		return ""
+	}
+	if f.ASCII != "" {
+		return f.ASCII
+	}
+	if f.HAnsi != "" {
+		return f.HAnsi
+	}
+	return f.CS
+}
+
+type xmlStyles struct {
+	DefaultFonts *xmlFonts `xml:"docDefaults>rPrDefault>rPr>rFonts"`
+	Styles       []struct {
+		Type    string `xml:"type,attr"`
+		Default string `xml:"default,attr"`
+		ID      string `xml:"styleId,attr"`
+		BasedOn struct {
+			Val string `xml:"val,attr"`
+		} `xml:"basedOn"`
+		Fonts *xmlFonts `xml:"rPr>rFonts"`
+	} `xml:"style"`
+}
+
+// readDocumentStyles reads the styles part of r. A document without one inherits no fonts.
+func readDocumentStyles(r *zip.Reader) (*documentStyles, error) {
+	styles := &documentStyles{styles: make(map[string]documentStyle)}
+	for _, part := range r.File {
+		if part.Name != documentStylesPart {
+			continue
+		}
+		data, err := readZipFile(part)
+		if err != nil {
+			return nil, err
+		}
+		var parsed xmlStyles
+		if err := xml.Unmarshal(data, &parsed); err != nil {
+			return nil, fmt.Errorf("failed to read document styles: %w", err)
+		}
+		styles.defaultFont = parsed.DefaultFonts.family()
+		for _, s := range parsed.Styles {
+			styles.styles[s.ID] = documentStyle{basedOn: s.BasedOn.Val, font: s.Fonts.family()}
+			if s.Type == "paragraph" && (s.Default == "1" || s.Default == "true") {
+				styles.defaultParagraph = s.ID
+			}
+		}
+	}
+	return styles, nil
+}
+
+// font returns the font of a run without its own: that of its character style, else its
+// paragraph style (or the default one), else the document default.
+func (s *documentStyles) font(charStyle, paraStyle string) string {
+	if font := s.styleFont(charStyle); font != "" {
+		return font
+	}
+	if paraStyle == "" {
+		paraStyle = s.defaultParagraph
+	}
+	if font := s.styleFont(paraStyle); font != "" {
+		return font
+	}
+	return s.defaultFont
+}
+
+// styleFont follows the basedOn chain of id to the first style setting a font.
+func (s *documentStyles) styleFont(id string) string {
+	// Bounded, so a basedOn cycle in a damaged document cannot hang the conversion
+	for range len(s.styles) {
+		style, ok := s.styles[id]
+		if !ok {
+			return ""
+		}
+		if style.font != "" {
+			return style.font
+		}
+		id = style.basedOn
+	}
+	return ""
+}
+
+// documentEdit replaces data[start:end] of a part.
+type documentEdit struct {
+	start, end int
+	text       string
+}
+
+// documentRun collects what a w:r element needs for conversion.
+type documentRun struct {
+	start       int // after the <w:r> start tag
+	paraStyle   string
+	charStyle   string
+	font        string
+	fonts       *documentEdit // the w:rFonts tag, with its attributes in attrs
+	fontAttrs   []xml.Attr
+	props       int // after the <w:rPr> start tag, or -1
+	propsStart  int
+	propsClosed bool
+	styleEnd    int // after the w:rStyle tag, or -1
+	texts       []documentText
+}
+
+// documentText is the content of one w:t element.
+type documentText struct {
+	start, end int
+	text       strings.Builder
+}
+
+// convertDocumentPart converts the runs of one text part and returns it with the number
+// of runs converted. The part is scanned token by token and only the text and font of
+// converted runs is replaced, so everything else keeps its exact bytes.
+// Why: Re-encoding the whole part with encoding/xml rewrites its namespace prefixes, which
+// Word then refuses to open.
+func (p *Processor) convertDocumentPart(data []byte, styles *documentStyles) ([]byte, int, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	sc := &documentScanner{p: p, styles: styles, data: data}
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		end := int(dec.InputOffset())
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space == "w" {
+				sc.startElement(t, start, end)
+			}
+		case xml.CharData:
+			if sc.text != nil {
+				sc.text.text.Write(t)
+			}
+		case xml.EndElement:
+			if t.Name.Space == "w" {
+				sc.endElement(t, start)
+			}
+		}
+	}
+	return applyDocumentEdits(data, sc.edits), sc.converted, nil
+}
+
+// documentScanner is the state of convertDocumentPart between tokens.
+type documentScanner struct {
+	p      *Processor
+	styles *documentStyles
+	data   []byte
+	edits  []documentEdit
+	// runs are the open runs, innermost last
+	runs []*documentRun
+	// paraStyles are the styles of the open paragraphs, innermost last
+	paraStyles []string
+	inProps    bool
+	text       *documentText
+	converted  int
+}
+
+// run returns the innermost open run, or nil.
+func (sc *documentScanner) run() *documentRun {
+	if len(sc.runs) == 0 {
+		return nil
+	}
+	return sc.runs[len(sc.runs)-1]
+}
+
+// startElement records a w: element that starts at data[start:end].
+func (sc *documentScanner) startElement(t xml.StartElement, start, end int) {
+	run := sc.run()
+	selfClosing := bytes.HasSuffix(sc.data[start:end], []byte("/>"))
+	switch t.Name.Local {
+	case "p":
+		sc.paraStyles = append(sc.paraStyles, "")
+	case "pStyle":
+		if len(sc.paraStyles) > 0 {
+			sc.paraStyles[len(sc.paraStyles)-1] = attrValue(t.Attr, "val")
+		}
+	case "r":
+		paraStyle := ""
+		if len(sc.paraStyles) > 0 {
+			paraStyle = sc.paraStyles[len(sc.paraStyles)-1]
+		}
+		sc.runs = append(sc.runs, &documentRun{start: end, paraStyle: paraStyle, props: -1, styleEnd: -1})
+	case "rPr", "rStyle", "rFonts":
+		sc.runProps(run, t, start, end, selfClosing)
+	case "t":
+		if run != nil && !selfClosing {
+			run.texts = append(run.texts, documentText{start: end})
+			sc.text = &run.texts[len(run.texts)-1]
+		}
+	}
+}
+
+// runProps records the properties of run and where they are, from the rPr element or
+// one of its rStyle and rFonts children.
+func (sc *documentScanner) runProps(run *documentRun, t xml.StartElement, start, end int, selfClosing bool) {
+	switch t.Name.Local {
+	case "rPr":
+		if run != nil && run.props < 0 && run.texts == nil {
+			run.props, run.propsStart = end, start
+			run.propsClosed = selfClosing
+			sc.inProps = !selfClosing
+		}
+	case "rStyle":
+		if sc.inProps {
+			run.charStyle = attrValue(t.Attr, "val")
+			run.styleEnd = end
+		}
+	case "rFonts":
+		if sc.inProps {
+			fonts := &xmlFonts{
+				ASCII: attrValue(t.Attr, "ascii"),
+				HAnsi: attrValue(t.Attr, "hAnsi"),
+				CS:    attrValue(t.Attr, "cs"),
+			}
+			run.font = fonts.family()
+			run.fonts = &documentEdit{start: start, end: end}
+			run.fontAttrs = t.Attr
+		}
+	}
+}
+
+// endElement records the end of a w: element at start, converting a run once it closes.
+func (sc *documentScanner) endElement(t xml.EndElement, start int) {
+	switch t.Name.Local {
+	case "p":
+		if len(sc.paraStyles) > 0 {
+			sc.paraStyles = sc.paraStyles[:len(sc.paraStyles)-1]
+		}
+	case "rPr":
+		sc.inProps = false
+	case "t":
+		if sc.text != nil {
+			sc.text.end = start
+			sc.text = nil
+		}
+	case "r":
+		run := sc.run()
+		if run == nil {
+			return
+		}
+		sc.runs = sc.runs[:len(sc.runs)-1]
+		if runEdits := sc.p.convertDocumentRun(run, sc.styles); runEdits != nil {
+			sc.edits = append(sc.edits, runEdits...)
+			sc.converted++
+		}
+	}
+}
+
+// convertDocumentRun returns the edits converting run, or nil when it is left unchanged.
+func (p *Processor) convertDocumentRun(run *documentRun, styles *documentStyles) []documentEdit {
+	if len(run.texts) == 0 {
+		return nil
+	}
+	font := run.font
+	if font == "" {
+		font = styles.font(run.charStyle, run.paraStyle)
+	}
+	edits, family := p.convertRunTexts(font, run.texts)
+	if edits == nil {
+		return nil
+	}
+	if family != "" && family != font {
+		edits = append(edits, run.fontEdit(family))
+	}
+	return edits
+}
+
+// convertRunTexts returns the edits converting the texts of a run in font, detected on
+// all of them at once, and the output font family. It returns nil when no text changes.
+func (p *Processor) convertRunTexts(font string, texts []documentText) ([]documentEdit, string) {
+	var all strings.Builder
+	for i := range texts {
+		all.WriteString(texts[i].text.String())
+	}
+	enc := p.preserver.Detect(font, all.String()).Encoding
+	if !p.preserver.Supports(enc) {
+		return nil, ""
+	}
+
+	var edits []documentEdit
+	family := ""
+	for i := range texts {
+		original := texts[i].text.String()
+		convertedText, fam := p.preserver.ConvertAs(enc, font, original)
+		family = fam
+		if convertedText == original {
+			continue
+		}
+		var escaped strings.Builder
+		if err := xml.EscapeText(&escaped, []byte(convertedText)); err != nil {
+			return nil, ""
+		}
+		edits = append(edits, documentEdit{start: texts[i].start, end: texts[i].end, text: escaped.String()})
+	}
+	return edits, family
+}
+
+// fontEdit returns the edit setting the Latin fonts of run to family. Theme fonts would
+// override the family in Word, so they are dropped; other attributes are kept.
+func (run *documentRun) fontEdit(family string) documentEdit {
+	var tag strings.Builder
+	family = attrEscaper.Replace(family)
+	tag.WriteString(`<w:rFonts w:ascii="` + family + `" w:hAnsi="` + family + `" w:cs="` + family + `"`)
+	for _, attr := range run.fontAttrs {
+		switch attr.Name.Local {
+		case "ascii", "hAnsi", "cs", "asciiTheme", "hAnsiTheme", "cstheme":
+			continue
+		}
+		writeAttr(&tag, attr)
+	}
+	tag.WriteString("/>")
+
+	switch {
+	case run.fonts != nil:
+		return documentEdit{start: run.fonts.start, end: run.fonts.end, text: tag.String()}
+	case run.props >= 0 && run.propsClosed:
+		// <w:rPr/> becomes a full element holding the fonts
+		return documentEdit{start: run.propsStart, end: run.props, text: "<w:rPr>" + tag.String() + "</w:rPr>"}
+	case run.styleEnd >= 0:
+		// The schema orders w:rFonts right after w:rStyle
+		return documentEdit{start: run.styleEnd, end: run.styleEnd, text: tag.String()}
+	case run.props >= 0:
+		return documentEdit{start: run.props, end: run.props, text: tag.String()}
+	default:
+		return documentEdit{start: run.start, end: run.start, text: "<w:rPr>" + tag.String() + "</w:rPr>"}
+	}
+}
+
+// attrEscaper escapes an attribute value written in double quotes. Unlike xml.EscapeText
+// it keeps apostrophes, which OpenDocument font families are quoted with.
+var attrEscaper = strings.NewReplacer(
+	`&`, "&amp;", `<`, "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+
+// writeAttr writes attr to tag as it appeared in the part, prefix included.
+func writeAttr(tag *strings.Builder, attr xml.Attr) {
+	tag.WriteString(" ")
+	if attr.Name.Space != "" {
+		tag.WriteString(attr.Name.Space + ":")
+	}
+	tag.WriteString(attr.Name.Local + `="` + attrEscaper.Replace(attr.Value) + `"`)
+}
+
+func attrValue(attrs []xml.Attr, local string) string {
+	for _, attr := range attrs {
+		if attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// applyDocumentEdits returns data with edits applied. Edits never overlap.
+func applyDocumentEdits(data []byte, edits []documentEdit) []byte {
+	if len(edits) == 0 {
+		return data
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var out bytes.Buffer
+	out.Grow(len(data))
+	last := 0
+	for _, e := range edits {
+		out.Write(data[last:e.start])
+		out.WriteString(e.text)
+		last = e.end
+	}
+	out.Write(data[last:])
+	return out.Bytes()
+}
@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testDocumentNS = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"`
+
+// writeDocument saves a document made of parts (name to content) at path.
+func writeDocument(t *testing.T, path string, parts map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create document: %v", err)
+	}
+	w := zip.NewWriter(f)
+	for name, content := range parts {
+		dst, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("failed to add %s: %v", name, err)
+		}
+		if _, err := dst.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close document: %v", err)
+	}
+}
+
+func TestRunDocument(t *testing.T) {
+	styles := `<w:styles ` + testDocumentNS + `>` +
+		`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri"/></w:rPr></w:rPrDefault></w:docDefaults>` +
+		`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Legacy"><w:basedOn w:val="Normal"/><w:rPr><w:rFonts w:ascii=".VnTime" w:hAnsi=".VnTime"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Title"><w:basedOn w:val="Legacy"/></w:style>` +
+		`</w:styles>`
+	body := func(paragraphs string) string {
+		return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+			`<w:document ` + testDocumentNS + `><w:body>` + paragraphs + `</w:body></w:document>`
+	}
+
+	tests := []struct {
+		name      string
+		part      string
+		content   string
+		want      []string
+		unchanged bool
+		wantRuns  int
+	}{
+		{
+			name:     "Run font",
+			part:     "word/document.xml",
+			content:  body(`<w:p><w:r><w:rPr><w:b/><w:rFonts w:ascii="VNI-Times" w:hAnsi="VNI-Times" w:eastAsia="MS Mincho"/></w:rPr><w:t>` + "Vie\u00e2\u00eft Nam" + `</w:t></w:r></w:p>`),
+			want:     []string{`<w:t>Việt Nam</w:t>`, `<w:rFonts w:ascii="Times New Roman" w:hAnsi="Times New Roman" w:cs="Times New Roman" w:eastAsia="MS Mincho"/>`, `<w:b/>`},
+			wantRuns: 1,
+		},
+		{
+			name:     "Paragraph style font",
+			part:     "word/document.xml",
+			content:  body(`<w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:t xml:space="preserve">` + "C\u00ABng ty" + ` </w:t><w:tab/><w:t>&amp; ` + "C\u00ABng ty" + `</w:t></w:r></w:p>`),
+			want:     []string{`<w:r><w:rPr><w:rFonts w:ascii="Times New Roman" w:hAnsi="Times New Roman" w:cs="Times New Roman"/></w:rPr><w:t xml:space="preserve">Công ty </w:t><w:tab/><w:t>&amp; Công ty</w:t></w:r>`},
+			wantRuns: 1,
+		},
+		{
+			name:     "Header",
+			part:     "word/header1.xml",
+			content:  `<w:hdr ` + testDocumentNS + `><w:p><w:r><w:rPr><w:rStyle w:val="Strong"/></w:rPr><w:t>` + "Vi\u00D6t Nam" + `</w:t></w:r></w:p></w:hdr>`,
+			want:     []string{`<w:rStyle w:val="Strong"/><w:rFonts w:ascii="Arial" w:hAnsi="Arial" w:cs="Arial"/>`, `<w:t>Việt Nam</w:t>`},
+			wantRuns: 1,
+		},
+		{
+			name:      "English",
+			part:      "word/document.xml",
+			content:   body(`<w:p><w:r><w:t>Quarterly report</w:t></w:r></w:p>`),
+			unchanged: true,
+		},
+		{
+			name:      "Not a text part",
+			part:      "word/comments.xml",
+			content:   `<w:comments ` + testDocumentNS + `><w:comment><w:p><w:r><w:rPr><w:rFonts w:ascii="VNI-Times"/></w:rPr><w:t>` + "Vi\u00D6t" + `</w:t></w:r></w:p></w:comment></w:comments>`,
+			unchanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			input := filepath.Join(dir, "report.docx")
+			writeDocument(t, input, map[string]string{documentStylesPart: styles, tt.part: tt.content})
+
+			p := NewProcessor(input, "")
+			output, err := p.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if filepath.Ext(output) != ".docx" {
+				t.Errorf("output = %s, want a .docx file", output)
+			}
+			if p.ConvertedRuns() != tt.wantRuns {
+				t.Errorf("ConvertedRuns() = %d, want %d", p.ConvertedRuns(), tt.wantRuns)
+			}
+			got := string(readZipPart(t, output, tt.part))
+			if tt.unchanged && got != tt.content {
+				t.Errorf("%s = %s, want it unchanged", tt.part, got)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("%s = %s, want it to contain %s", tt.part, got, want)
+				}
+			}
+			if string(readZipPart(t, output, documentStylesPart)) != styles {
+				t.Errorf("styles changed, want them copied unchanged")
+			}
+		})
+	}
+}
+
+func TestRunDocument_Unsupported(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "report.docx")
+	writeDocument(t, input, map[string]string{"word/document.xml": `<w:document ` + testDocumentNS + `/>`})
+	p := NewProcessor(input, "")
+	p.SetLargeFileMode(true)
+	if _, err := p.Run(context.Background()); !errors.Is(err, errDocumentUnsupported) {
+		t.Errorf("Run() error = %v, want errDocumentUnsupported", err)
+	}
+}
@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultIgnorePatterns skip Excel lock files ("~$Book.xlsx") and temporary files.
+var DefaultIgnorePatterns = []string{"~$*", "*.tmp"}
+
+// FileFilter decides which files folder scans and the watch folder pick up.
+// Why: Excel leaves "~$" lock files and zero-byte temps next to open workbooks;
+// they fail to open and would be reported (or retried) as broken workbooks.
+type FileFilter struct {
+	patterns []string
+	minSize  int64
+}
+
+// NewFileFilter validates the ignore patterns (filepath.Match syntax, matched
+// case-insensitively against the file name) and returns the filter.
+// Files smaller than minSize bytes are skipped; empty files are always skipped.
+func NewFileFilter(patterns []string, minSize int64) (FileFilter, error) {
+	f := FileFilter{minSize: minSize}
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		if _, err := filepath.Match(p, ""); err != nil {
+			return FileFilter{}, fmt.Errorf("invalid ignore pattern %q: %w", p, err)
+		}
+		f.patterns = append(f.patterns, p)
+	}
+	return f, nil
+}
+
+// DefaultFileFilter returns the filter used when nothing is configured.
+func DefaultFileFilter() FileFilter {
+	f, _ := NewFileFilter(DefaultIgnorePatterns, 0)
+	return f
+}
+
+// Skip reports whether a file with the given base name and size should be ignored.
+func (f FileFilter) Skip(name string, size int64) bool {
+	if size < 1 || size < f.minSize {
+		return true
+	}
+	name = strings.ToLower(name)
+	for _, p := range f.patterns {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
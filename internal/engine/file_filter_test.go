@@ -0,0 +1,38 @@
+package engine
+
+import "testing"
+
+func TestFileFilter_Skip(t *testing.T) {
+	custom, err := NewFileFilter([]string{"draft_*", " "}, 100)
+	if err != nil {
+		t.Fatalf("NewFileFilter failed: %v", err)
+	}
+	tests := []struct {
+		name   string
+		filter FileFilter
+		file   string
+		size   int64
+		want   bool
+	}{
+		{name: "Workbook", filter: DefaultFileFilter(), file: "book.xlsx", size: 5000, want: false},
+		{name: "Lock file", filter: DefaultFileFilter(), file: "~$book.xlsx", size: 165, want: true},
+		{name: "Temp file", filter: DefaultFileFilter(), file: "A1B2C3.TMP", size: 5000, want: true},
+		{name: "Empty file", filter: DefaultFileFilter(), file: "book.xlsx", size: 0, want: true},
+		{name: "Custom pattern case-insensitive", filter: custom, file: "Draft_report.xlsx", size: 5000, want: true},
+		{name: "Below minimum size", filter: custom, file: "book.xlsx", size: 99, want: true},
+		{name: "Custom replaces defaults", filter: custom, file: "~$book.xlsx", size: 5000, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Skip(tt.file, tt.size); got != tt.want {
+				t.Errorf("Skip(%q, %d) = %v, want %v", tt.file, tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFileFilter_InvalidPattern(t *testing.T) {
+	if _, err := NewFileFilter([]string{"[abc"}, 0); err == nil {
+		t.Error("expected error for malformed pattern")
+	}
+}
@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"fmt"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+// Font policy names accepted by NewFontPolicy (and stored in settings).
+const (
+	FontPolicyKeepOriginal        = "keep"
+	FontPolicyMapOrDefault        = "map"
+	FontPolicyAlwaysDefault       = "default"
+	FontPolicyPerEncodingDefaults = "per-encoding"
+)
+
+// FontPolicy decides the output font family of converted text.
+// Why: Keeps the "which font after conversion" decision in one place so every
+// conversion path behaves the same.
+type FontPolicy interface {
+	// Resolve returns the font family for text converted from encoding.
+	// originalFont may be empty when the source had no font.
+	Resolve(originalFont string, encoding converter.EncodingType) string
+}
+
+// KeepOriginalPolicy leaves the source font untouched.
+type KeepOriginalPolicy struct{}
+
+// Resolve implements FontPolicy.
+func (KeepOriginalPolicy) Resolve(originalFont string, _ converter.EncodingType) string {
+	return originalFont
+}
+
+// MapOrDefaultPolicy maps known legacy fonts via FontMap and uses Default otherwise.
+// This is the historical behavior.
+type MapOrDefaultPolicy struct {
+	Default string
+}
+
+// Resolve implements FontPolicy.
+func (p MapOrDefaultPolicy) Resolve(originalFont string, _ converter.EncodingType) string {
+	if mapped, ok := FontMap[originalFont]; ok {
+		return mapped
+	}
+	return p.Default
+}
+
+// AlwaysDefaultPolicy forces a single font on all converted text.
+type AlwaysDefaultPolicy struct {
+	Default string
+}
+
+// Resolve implements FontPolicy.
+func (p AlwaysDefaultPolicy) Resolve(_ string, _ converter.EncodingType) string {
+	return p.Default
+}
+
+// PerEncodingDefaultsPolicy maps known fonts via FontMap and falls back to a
+// default chosen by the source encoding.
+type PerEncodingDefaultsPolicy struct {
+	Defaults map[converter.EncodingType]string
+	Fallback string
+}
+
+// Resolve implements FontPolicy.
+func (p PerEncodingDefaultsPolicy) Resolve(originalFont string, encoding converter.EncodingType) string {
+	if mapped, ok := FontMap[originalFont]; ok {
+		return mapped
+	}
+	if font, ok := p.Defaults[encoding]; ok {
+		return font
+	}
+	return p.Fallback
+}
+
+// DefaultFontPolicy returns the policy used when none is configured.
+func DefaultFontPolicy() FontPolicy {
+	return MapOrDefaultPolicy{Default: DefaultFont}
+}
+
+// NewFontPolicy creates a policy by name. An empty name selects the default policy.
+func NewFontPolicy(name string) (FontPolicy, error) {
+	switch name {
+	case "", FontPolicyMapOrDefault:
+		return DefaultFontPolicy(), nil
+	case FontPolicyKeepOriginal:
+		return KeepOriginalPolicy{}, nil
+	case FontPolicyAlwaysDefault:
+		return AlwaysDefaultPolicy{Default: DefaultFont}, nil
+	case FontPolicyPerEncodingDefaults:
+		return PerEncodingDefaultsPolicy{
+			Defaults: map[converter.EncodingType]string{
+				converter.EncodingVNI:   DefaultFont,
+				converter.EncodingTCVN3: "Times New Roman",
+				converter.EncodingVNU:   "Times New Roman",
+			},
+			Fallback: DefaultFont,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown font policy %q", name)
+	}
+}
@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestNewFontPolicy_Resolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		font     string
+		encoding converter.EncodingType
+		expected string
+	}{
+		{name: "Map known font", policy: FontPolicyMapOrDefault, font: "VNI-Times", expected: "Times New Roman"},
+		{name: "Map unknown font", policy: FontPolicyMapOrDefault, font: "VNI-Fancy", expected: DefaultFont},
+		{name: "Empty name is map", policy: "", font: ".VnTime", expected: "Times New Roman"},
+		{name: "Keep original", policy: FontPolicyKeepOriginal, font: "VNI-Times", expected: "VNI-Times"},
+		{name: "Always default", policy: FontPolicyAlwaysDefault, font: "VNI-Times", expected: DefaultFont},
+		{
+			name:     "Per encoding fallback",
+			policy:   FontPolicyPerEncodingDefaults,
+			font:     ".VnFancy",
+			encoding: converter.EncodingTCVN3,
+			expected: "Times New Roman",
+		},
+		{
+			name:     "Per encoding prefers map",
+			policy:   FontPolicyPerEncodingDefaults,
+			font:     "VNI-Helve",
+			encoding: converter.EncodingVNI,
+			expected: "Helvetica",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewFontPolicy(tt.policy)
+			if err != nil {
+				t.Fatalf("NewFontPolicy failed: %v", err)
+			}
+			if got := policy.Resolve(tt.font, tt.encoding); got != tt.expected {
+				t.Errorf("Resolve() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewFontPolicy_Unknown(t *testing.T) {
+	if _, err := NewFontPolicy("comic-sans"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
+
+func TestFormatPreserver_ProcessRichTextPolicy(t *testing.T) {
+	fp, err := NewFormatPreserver(WithRunFontPolicy(KeepOriginalPolicy{}), WithRunSourceEncoding(converter.EncodingVNI))
+	if err != nil {
+		t.Fatalf("NewFormatPreserver failed: %v", err)
+	}
+
+	original := &excelize.Font{Family: "VNI-Times", Bold: true}
+	runs, changed := fp.ProcessRichText([]excelize.RichTextRun{
+		{Text: "ViÖt", Font: original},
+		{Text: "Nam"},
+	}, nil, nil)
+
+	if !changed {
+		t.Fatal("ProcessRichText() reported no change")
+	}
+
+	if runs[0].Text != "Việt" {
+		t.Errorf("run 0 text = %q, want %q", runs[0].Text, "Việt")
+	}
+	if runs[0].Font.Family != "VNI-Times" || !runs[0].Font.Bold {
+		t.Errorf("run 0 font not kept: %+v", runs[0].Font)
+	}
+	if runs[1].Font != nil {
+		t.Errorf("run 1 font should stay nil with keep policy, got %+v", runs[1].Font)
+	}
+}
+
+func TestFormatPreserver_TCVN3UpperFont(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     FontPolicy
+		font       string
+		wantText   string
+		wantFamily string
+	}{
+		{name: "Regular font", policy: DefaultFontPolicy(), font: ".VnTime", wantText: "Công ty", wantFamily: "Times New Roman"},
+		{name: "Capital font mapped", policy: DefaultFontPolicy(), font: ".VnArialH", wantText: "CÔNG TY", wantFamily: DefaultFont},
+		{name: "Capital font kept as regular variant", policy: KeepOriginalPolicy{}, font: ".VnTimeH", wantText: "CÔNG TY", wantFamily: ".VnTime"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp, err := NewFormatPreserver(WithRunFontPolicy(tt.policy))
+			if err != nil {
+				t.Fatalf("NewFormatPreserver failed: %v", err)
+			}
+			text, family := fp.ConvertRun(tt.font, "C\u00ABng ty")
+			if text != tt.wantText || family != tt.wantFamily {
+				t.Errorf("ConvertRun() = (%q, %q), want (%q, %q)", text, family, tt.wantText, tt.wantFamily)
+			}
+		})
+	}
+}
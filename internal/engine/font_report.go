@@ -0,0 +1,219 @@
+package engine
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// FontKind classifies a font family in a FontReport.
+type FontKind string
+
+// Font kinds of a FontReport.
+const (
+	// FontKindLegacy is a VNI, TCVN3 or VNU font, recognised by its name prefix.
+	FontKindLegacy FontKind = "legacy"
+	// FontKindUnicode is a FontMap target or a common Unicode font.
+	FontKindUnicode FontKind = "unicode"
+	// FontKindUnknown is any other font; legacy fonts with unusual names end up here.
+	FontKindUnknown FontKind = "unknown"
+)
+
+// unicodeFonts are common Unicode fonts besides the FontMap targets.
+var unicodeFonts = map[string]bool{
+	"Aptos":           true,
+	"Aptos Narrow":    true,
+	"Arial":           true,
+	"Arial Narrow":    true,
+	"Calibri":         true,
+	"Calibri Light":   true,
+	"Cambria":         true,
+	"Consolas":        true,
+	"Courier New":     true,
+	"Georgia":         true,
+	"Segoe UI":        true,
+	"Tahoma":          true,
+	"Times New Roman": true,
+	"Verdana":         true,
+}
+
+// ClassifyFont returns whether family is a legacy, Unicode or unknown font.
+func ClassifyFont(family string) FontKind {
+	if _, legacy := detectByFont(family); legacy {
+		return FontKindLegacy
+	}
+	if unicodeFonts[family] {
+		return FontKindUnicode
+	}
+	for _, mapped := range FontMap {
+		if mapped == family {
+			return FontKindUnicode
+		}
+	}
+	return FontKindUnknown
+}
+
+// FontUsage counts the text cells using one font family before and after conversion.
+type FontUsage struct {
+	Family string   `json:"family"`
+	Kind   FontKind `json:"kind"`
+	// MappedTo is FontMap's replacement for the family, empty when it has none
+	MappedTo string `json:"mappedTo"`
+	Before   int    `json:"before"`
+	After    int    `json:"after"`
+}
+
+// FontReport lists the font families of a workbook's text cells, counted on every sheet
+// before and after conversion. A cell with several fonts counts once for each.
+// Why: Users check that no legacy font remains, and spot legacy or unknown fonts that
+// need a new FontMap entry. Only Run's own goroutine records, so no locking is needed.
+type FontReport struct {
+	usage map[string]*FontUsage
+}
+
+// NewFontReport creates an empty report.
+func NewFontReport() *FontReport {
+	return &FontReport{usage: make(map[string]*FontUsage)}
+}
+
+// SetFontReport makes Run count the fonts of the workbook into r before and after
+// conversion (nil disables it). Counting reads every sheet twice.
+func (p *Processor) SetFontReport(r *FontReport) {
+	p.fontReport = r
+}
+
+// Fonts returns the usage of every font family, sorted by name.
+func (r *FontReport) Fonts() []FontUsage {
+	fonts := make([]FontUsage, 0, len(r.usage))
+	for _, u := range r.usage {
+		fonts = append(fonts, *u)
+	}
+	sort.Slice(fonts, func(i, j int) bool { return fonts[i].Family < fonts[j].Family })
+	return fonts
+}
+
+// LegacyAfter returns the number of text cells still using a legacy font after conversion.
+func (r *FontReport) LegacyAfter() int {
+	n := 0
+	for _, u := range r.usage {
+		if u.Kind == FontKindLegacy {
+			n += u.After
+		}
+	}
+	return n
+}
+
+func (r *FontReport) add(family string, after bool) {
+	u, ok := r.usage[family]
+	if !ok {
+		u = &FontUsage{Family: family, Kind: ClassifyFont(family), MappedTo: FontMap[family]}
+		r.usage[family] = u
+	}
+	if after {
+		u.After++
+	} else {
+		u.Before++
+	}
+}
+
+// WriteCSV writes the report as CSV.
+func (r *FontReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Font", "Kind", "FontMap Entry", "Cells Before", "Cells After"}); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	for _, u := range r.Fonts() {
+		record := []string{u.Family, string(u.Kind), u.MappedTo, strconv.Itoa(u.Before), strconv.Itoa(u.After)}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv record: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// countFonts adds the font families of every text cell of the workbook to the report's
+// before or after counts. Runs without a font use the cell style's font.
+func (p *Processor) countFonts(after bool) error {
+	styleFonts := make(map[int]string)
+	for _, sheet := range p.f.GetSheetList() {
+		if _, ok := p.broken[sheet]; ok {
+			continue
+		}
+		rows, err := p.f.Rows(sheet)
+		if err != nil {
+			return fmt.Errorf("failed to read sheet %q: %w", sheet, err)
+		}
+		rowIdx := 0
+		for rows.Next() {
+			rowIdx++
+			cols, err := rows.Columns()
+			if err != nil {
+				continue
+			}
+			for colIdx, text := range cols {
+				if strings.TrimSpace(text) == "" {
+					continue
+				}
+				axis, err := excelize.CoordinatesToCellName(colIdx+1, rowIdx)
+				if err != nil {
+					continue
+				}
+				if cellType, err := p.f.GetCellType(sheet, axis); err != nil || !isTextCell(cellType) {
+					continue
+				}
+				for family := range p.cellFonts(sheet, axis, styleFonts) {
+					p.fontReport.add(family, after)
+				}
+			}
+		}
+		if err := rows.Close(); err != nil {
+			slog.Error("failed to close rows iterator", "sheet", sheet, "error", err)
+		}
+	}
+	return nil
+}
+
+// cellFonts returns the set of font families used by the runs of a text cell.
+// styleFonts caches the font family of each style ID.
+func (p *Processor) cellFonts(sheet, axis string, styleFonts map[int]string) map[string]bool {
+	styleFont := func() string {
+		styleID, err := p.f.GetCellStyle(sheet, axis)
+		if err != nil {
+			return ""
+		}
+		family, ok := styleFonts[styleID]
+		if !ok {
+			if style, err := p.f.GetStyle(styleID); err == nil && style.Font != nil {
+				family = style.Font.Family
+			}
+			styleFonts[styleID] = family
+		}
+		return family
+	}
+
+	families := make(map[string]bool)
+	runs, err := p.f.GetCellRichText(sheet, axis)
+	if err != nil || len(runs) == 0 {
+		runs = []excelize.RichTextRun{{}}
+	}
+	for _, run := range runs {
+		family := ""
+		if run.Font != nil {
+			family = run.Font.Family
+		}
+		if family == "" {
+			family = styleFont()
+		}
+		if family != "" {
+			families[family] = true
+		}
+	}
+	return families
+}
@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestClassifyFont(t *testing.T) {
+	tests := []struct {
+		family string
+		want   FontKind
+	}{
+		{family: "VNI-Times", want: FontKindLegacy},
+		{family: ".VnArialH", want: FontKindLegacy},
+		{family: "Calibri", want: FontKindUnicode},
+		{family: "Helvetica", want: FontKindUnicode}, // a FontMap target
+		{family: "Comic Sans MS", want: FontKindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.family, func(t *testing.T) {
+			if got := ClassifyFont(tt.family); got != tt.want {
+				t.Errorf("ClassifyFont(%q) = %s, want %s", tt.family, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessor_RunWithFontReport(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "fonts.xlsx")
+	f := excelize.NewFile()
+	if _, err := f.NewSheet("Other"); err != nil {
+		t.Fatalf("NewSheet failed: %v", err)
+	}
+	cells := []struct {
+		sheet, axis, text, font string
+	}{
+		{"Sheet1", "A2", "Hello", "Comic Sans MS"},
+		{"Sheet1", "A3", "Hello", ""},
+		{"Other", "B1", "Vi\u00D6t Nam", "VNI-Times"}, // outside the converted sheet
+	}
+	for _, c := range cells {
+		if err := f.SetCellValue(c.sheet, c.axis, c.text); err != nil {
+			t.Fatalf("SetCellValue failed: %v", err)
+		}
+		if c.font == "" {
+			continue
+		}
+		style, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Family: c.font}})
+		if err != nil {
+			t.Fatalf("NewStyle failed: %v", err)
+		}
+		if err := f.SetCellStyle(c.sheet, c.axis, c.axis, style); err != nil {
+			t.Fatalf("SetCellStyle failed: %v", err)
+		}
+	}
+	runs := []excelize.RichTextRun{{Text: "Vi\u00D6t Nam", Font: &excelize.Font{Family: "VNI-Times"}}}
+	if err := f.SetCellRichText("Sheet1", "A1", runs); err != nil {
+		t.Fatalf("SetCellRichText failed: %v", err)
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	_ = f.Close()
+
+	report := NewFontReport()
+	proc := NewProcessor(inputFile, "Sheet1")
+	proc.SetFontReport(report)
+	outputFile, err := proc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+
+	want := map[string]FontUsage{
+		"Calibri":         {Family: "Calibri", Kind: FontKindUnicode, Before: 1, After: 1},
+		"Comic Sans MS":   {Family: "Comic Sans MS", Kind: FontKindUnknown, Before: 1, After: 1},
+		"Times New Roman": {Family: "Times New Roman", Kind: FontKindUnicode, After: 1},
+		"VNI-Times":       {Family: "VNI-Times", Kind: FontKindLegacy, MappedTo: "Times New Roman", Before: 2, After: 1},
+	}
+	fonts := report.Fonts()
+	if len(fonts) != len(want) {
+		t.Fatalf("Fonts() = %+v, want %d fonts", fonts, len(want))
+	}
+	for _, got := range fonts {
+		if got != want[got.Family] {
+			t.Errorf("font %q = %+v, want %+v", got.Family, got, want[got.Family])
+		}
+	}
+	if got := report.LegacyAfter(); got != 1 {
+		t.Errorf("LegacyAfter() = %d, want 1", got)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "VNI-Times,legacy,Times New Roman,2,1\n") {
+		t.Errorf("CSV = %q, missing the VNI-Times row", buf.String())
+	}
+}
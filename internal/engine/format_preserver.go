@@ -19,65 +19,262 @@ var FontMap = map[string]string{
 	".VnTimeH": "Times New Roman",
 	".VnArial": DefaultFont,
 	".VnHelve": "Helvetica",
+	// VNU Fonts
+	"VNU-Times": "Times New Roman",
+	"VNU-Arial": DefaultFont,
 }
 
 // DefaultFont is the fallback font for converted text.
 const DefaultFont = "Arial"
 
-// FormatPreserver handles the preservation of styles while changing text.
-// Why: Separates formatting logic from the main processor.
+// FormatPreserver converts text run by run, detecting the encoding of each run and
+// mapping its font, and is the one conversion path of every Run, ProcessWorkbook,
+// ConvertXLSX, ConvertCells and document conversion.
+// Why: Separates formatting logic from the main processor, and keeps the GUI, the CLI
+// and library callers from drifting apart (they once disagreed on fonts of unstyled runs).
+// It is safe for concurrent use once configured.
 type FormatPreserver struct {
+	// encodings holds the converter of each encoding runs can be converted from
+	encodings map[converter.EncodingType]*encodingConverter
+	policy    FontPolicy
+	detector  Detector
+	// sourceEncoding forces one encoding for every run; empty detects per run
+	sourceEncoding converter.EncodingType
+	// normalization is the Unicode form of converted text (NFC unless set)
+	normalization converter.Normalization
+}
+
+// encodingConverter is the converter of one encoding, with its cache of short strings.
+type encodingConverter struct {
 	converter converter.Converter
+	cache     *conversionCache
+}
+
+// PreserverOption configures a FormatPreserver (see NewFormatPreserver).
+type PreserverOption func(*FormatPreserver) error
+
+// WithRunConverter converts runs of enc with c instead of the built-in converter.
+func WithRunConverter(enc converter.EncodingType, c converter.Converter) PreserverOption {
+	return func(fp *FormatPreserver) error {
+		fp.SetConverter(enc, c)
+		return nil
+	}
+}
+
+// WithRunFontPolicy sets the output font policy (see SetFontPolicy).
+func WithRunFontPolicy(policy FontPolicy) PreserverOption {
+	return func(fp *FormatPreserver) error {
+		fp.SetFontPolicy(policy)
+		return nil
+	}
+}
+
+// WithRunDetector sets the detector deciding the encoding of each run (see SetDetector).
+func WithRunDetector(d Detector) PreserverOption {
+	return func(fp *FormatPreserver) error {
+		fp.SetDetector(d)
+		return nil
+	}
+}
+
+// WithRunSourceEncoding forces the encoding of every run (see SetSourceEncoding).
+func WithRunSourceEncoding(enc converter.EncodingType) PreserverOption {
+	return func(fp *FormatPreserver) error { return fp.SetSourceEncoding(enc) }
+}
+
+// WithRunNormalization writes converted text in the Unicode form n (see SetNormalization).
+func WithRunNormalization(n converter.Normalization) PreserverOption {
+	return func(fp *FormatPreserver) error {
+		fp.SetNormalization(n)
+		return nil
+	}
+}
+
+// NewFormatPreserver creates a FormatPreserver converting the auto-detectable encodings
+// (VNI, TCVN3 and VNU) with the rule-based detector and the default font policy,
+// configured by opts.
+func NewFormatPreserver(opts ...PreserverOption) (*FormatPreserver, error) {
+	fp := newFormatPreserver()
+	for _, opt := range opts {
+		if err := opt(fp); err != nil {
+			return nil, err
+		}
+	}
+	return fp, nil
+}
+
+// newFormatPreserver creates a FormatPreserver with the defaults of NewFormatPreserver.
+func newFormatPreserver() *FormatPreserver {
+	fp := &FormatPreserver{
+		encodings: make(map[converter.EncodingType]*encodingConverter),
+		policy:    DefaultFontPolicy(),
+		detector:  RuleDetector{},
+	}
+	fp.SetConverter(converter.EncodingVNI, converter.NewVNIConverter())
+	fp.SetConverter(converter.EncodingTCVN3, converter.NewTCVN3Converter())
+	fp.SetConverter(converter.EncodingVNU, converter.NewVNUConverter())
+	return fp
+}
+
+// SetConverter converts runs of enc with c, with an empty cache.
+func (fp *FormatPreserver) SetConverter(enc converter.EncodingType, c converter.Converter) {
+	fp.encodings[enc] = &encodingConverter{converter: c, cache: newConversionCache(DefaultConversionCacheSize)}
+}
+
+// SetFontPolicy sets the policy deciding output fonts for every encoding.
+func (fp *FormatPreserver) SetFontPolicy(policy FontPolicy) {
+	fp.policy = policy
+}
+
+// SetDetector sets the detector used in auto-detect mode (see NewDetector).
+func (fp *FormatPreserver) SetDetector(d Detector) {
+	fp.detector = d
+}
+
+// SetNormalization writes converted text in the Unicode form n; the caches start empty.
+// Text already in Unicode is left as it is.
+func (fp *FormatPreserver) SetNormalization(n converter.Normalization) {
+	fp.normalization = n
+	for _, ec := range fp.encodings {
+		ec.cache = newConversionCache(DefaultConversionCacheSize)
+	}
 }
 
-// NewFormatPreserver creates a new instance.
-func NewFormatPreserver(c converter.Converter) *FormatPreserver {
-	return &FormatPreserver{converter: c}
+// SetSourceEncoding converts every run from enc instead of detecting the encoding per run.
+// EncodingAuto (or "") restores detection.
+func (fp *FormatPreserver) SetSourceEncoding(enc converter.EncodingType) error {
+	if enc == "" || enc == converter.EncodingAuto {
+		fp.sourceEncoding = ""
+		return nil
+	}
+	if !fp.Supports(enc) {
+		c, err := converter.NewConverter(enc)
+		if err != nil {
+			return err
+		}
+		fp.SetConverter(enc, c)
+	}
+	fp.sourceEncoding = enc
+	return nil
 }
 
-// ProcessRichText converts the text in runs and maps the fonts.
+// Supports reports whether runs can be converted from enc.
+func (fp *FormatPreserver) Supports(enc converter.EncodingType) bool {
+	_, ok := fp.encodings[enc]
+	return ok
+}
+
+// Converter returns the converter of enc, if runs can be converted from it.
+func (fp *FormatPreserver) Converter(enc converter.EncodingType) (converter.Converter, bool) {
+	ec, ok := fp.encodings[enc]
+	if !ok {
+		return nil, false
+	}
+	return ec.converter, true
+}
+
+// Detect returns the forced source encoding, or the detected one. Text already in
+// Vietnamese Unicode is never converted, whatever the source encoding.
+func (fp *FormatPreserver) Detect(fontName, text string) Detection {
+	if d, ok := detectUnicode(text); ok {
+		return d
+	}
+	if fp.sourceEncoding != "" {
+		return Detection{Encoding: fp.sourceEncoding, Rule: RuleForced}
+	}
+	return fp.detector.Detect(fontName, text)
+}
+
+// ProcessRichText converts the runs of one cell and maps their fonts (bold, italic, color
+// and size are kept, only the family changes). styleFont is the font of the cell style,
+// which runs without a font or font family of their own are drawn in; nil when unknown.
+// Their family is resolved against it before detection and mapping. record, if not
+// nil, receives the detection of every run. It returns the converted runs and whether
+// any text or font changed; unchanged cells get runs itself back.
 // Why: Rich Text allows multiple styles in one cell. We must iterate runs to preserve mixed styles.
-func (fp *FormatPreserver) ProcessRichText(runs []excelize.RichTextRun) []excelize.RichTextRun {
-	newRuns := make([]excelize.RichTextRun, len(runs))
+func (fp *FormatPreserver) ProcessRichText(runs []excelize.RichTextRun, styleFont *excelize.Font,
+	record func(run int, fontName string, d Detection)) ([]excelize.RichTextRun, bool) {
+	// A cell already in Vietnamese Unicode is kept whole, even runs that look legacy
+	// on their own (a lone "ô" is a VNI marker)
+	cellUnicode, isUnicode := detectUnicode(runsText(runs))
+	changed := false
+	newRuns := make([]excelize.RichTextRun, 0, len(runs))
 	for i, run := range runs {
-		// Convert text
-		convertedText := fp.converter.ToUnicode(run.Text)
-
-		// Create copy
-		newRun := run
-		newRun.Text = convertedText
-
-		// Handle Font mapping
-		if newRun.Font != nil {
-			if mapping, ok := FontMap[newRun.Font.Family]; ok {
-				newRun.Font.Family = mapping
-			} else {
-				// If no mapping found but text was converted, default to "Arial"
-				// to ensure legible Unicode display if the old font doesn't support it.
-				// However, user requested "Default font is Arial".
-				// So we enforce Arial if it was a legacy font or if we act generically?
-				// "font mặc định sau khi chuyển là Arial" -> Implies strictly Arial if not overridden?
-				// Let's respect the user's specific request: Default = Arial.
-				// But keeping original bold/italic is key.
-				newRun.Font.Family = DefaultFont
-			}
-		} else {
-			// If no font struct exists, create one with DefaultFont
-			newRun.Font = &excelize.Font{
-				Family: DefaultFont,
-				Size:   11, // Default Excel size usually
+		fontName := runFontName(run, styleFont)
+
+		detection := cellUnicode
+		if !isUnicode {
+			detection = fp.Detect(fontName, run.Text)
+		}
+		if record != nil {
+			record(i, fontName, detection)
+		}
+		text, family := fp.ConvertAs(detection.Encoding, fontName, run.Text)
+		if text != run.Text || (family != "" && family != fontName) {
+			changed = true
+		}
+		// Map Font to Unicode equivalent (decided by the font policy)
+		if family != "" {
+			// Copy: the font is shared with runs, which keeps the original.
+			// A run without a font drew with the style font; once it has a font of its
+			// own, the style's color, size and emphasis no longer apply, so copy them
+			font := excelize.Font{}
+			if run.Font != nil {
+				font = *run.Font
+			} else if styleFont != nil {
+				font = *styleFont
 			}
+			font.Family = family
+			run.Font = &font
 		}
 
-		newRuns[i] = newRun
+		run.Text = text
+		newRuns = append(newRuns, run)
+	}
+	if !changed {
+		return runs, false
+	}
+	return newRuns, true
+}
+
+// runFontName returns the font family run is drawn in: its own, or the family of the
+// style font when the run sets no font or a font without a family (e.g. only bold).
+// Why: A run left to the cell's legacy font would otherwise be detected from its text
+// alone and mapped to the default font.
+func runFontName(run excelize.RichTextRun, styleFont *excelize.Font) string {
+	if run.Font != nil && run.Font.Family != "" {
+		return run.Font.Family
+	}
+	if styleFont != nil {
+		return styleFont.Family
 	}
-	return newRuns
+	return ""
 }
 
-// GetConvertedFontFamily determines the new font family based on input.
-func (fp *FormatPreserver) GetConvertedFontFamily(originalFont string) string {
-	if mapped, ok := FontMap[originalFont]; ok {
-		return mapped
+// ConvertRun converts text from the forced or detected encoding and returns it with the
+// output font family. Text in an unknown encoding is returned unchanged with no family.
+func (fp *FormatPreserver) ConvertRun(fontName, text string) (string, string) {
+	return fp.ConvertAs(fp.Detect(fontName, text).Encoding, fontName, text)
+}
+
+// ConvertAs converts the text of one run from enc and returns it with the output font
+// family; text of an encoding without a converter is returned unchanged with no family.
+// Why: Capital-only legacy fonts (e.g. .VnTimeH) draw lowercase codes as capitals,
+// so the text is uppercased and the font resolved as its regular variant.
+// Conversions of short text are cached, so repeated strings are converted once.
+func (fp *FormatPreserver) ConvertAs(enc converter.EncodingType, fontName, text string) (string, string) {
+	ec, ok := fp.encodings[enc]
+	if !ok {
+		return text, ""
+	}
+	if upper, ok := ec.converter.(converter.UppercaseConverter); ok && converter.IsTCVN3UpperFont(fontName) {
+		converted := ec.cache.convert(conversionKey{upper: true, text: text}, func() string {
+			return fp.normalization.Apply(upper.ToUnicodeUpper(text))
+		})
+		return converted, fp.policy.Resolve(converter.TCVN3BaseFont(fontName), enc)
 	}
-	return DefaultFont
+	converted := ec.cache.convert(conversionKey{text: text}, func() string {
+		return fp.normalization.Apply(ec.converter.ToUnicode(text))
+	})
+	return converted, fp.policy.Resolve(fontName, enc)
 }
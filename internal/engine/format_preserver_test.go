@@ -0,0 +1,279 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// conversionPathCells are the cells converted by every path of TestConversionPaths.
+var conversionPathCells = []struct {
+	axis  string
+	style string // font family of the cell style, "" for none
+	runs  []excelize.RichTextRun
+}{
+	{axis: "A1", style: "VNI-Times", runs: []excelize.RichTextRun{{Text: "Vi\u00D6t Nam"}}},
+	{axis: "A2", runs: []excelize.RichTextRun{
+		{Text: "Hello ", Font: &excelize.Font{Family: DefaultFont}},
+		{Text: "Vi\u00D6t", Font: &excelize.Font{Family: "VNI-Times", Bold: true}},
+	}},
+	{axis: "A3", style: ".VnTimeH", runs: []excelize.RichTextRun{{Text: "C\u00ABng ty"}}},
+	{axis: "A4", style: "VNI-Times", runs: []excelize.RichTextRun{{Text: "Việt Nam"}}},
+	{axis: "A5", runs: []excelize.RichTextRun{{Text: "Total"}}},
+	{axis: "A6", style: ".VnTime", runs: []excelize.RichTextRun{
+		{Text: "C\u00ABng "},
+		{Text: "ty", Font: &excelize.Font{Family: ".VnTime", Italic: true}},
+	}},
+}
+
+// writeConversionPathWorkbook writes conversionPathCells to a new workbook at path.
+func writeConversionPathWorkbook(t *testing.T, path string) {
+	t.Helper()
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+	for _, c := range conversionPathCells {
+		if len(c.runs) == 1 && c.runs[0].Font == nil {
+			if err := f.SetCellValue("Sheet1", c.axis, c.runs[0].Text); err != nil {
+				t.Fatalf("failed to set %s: %v", c.axis, err)
+			}
+		} else if err := f.SetCellRichText("Sheet1", c.axis, c.runs); err != nil {
+			t.Fatalf("failed to set %s: %v", c.axis, err)
+		}
+		if c.style == "" {
+			continue
+		}
+		style, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Family: c.style, Size: 12}})
+		if err != nil {
+			t.Fatalf("failed to create style: %v", err)
+		}
+		if err := f.SetCellStyle("Sheet1", c.axis, c.axis, style); err != nil {
+			t.Fatalf("failed to style %s: %v", c.axis, err)
+		}
+	}
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("failed to save workbook: %v", err)
+	}
+}
+
+// runsSignature describes the text and font families of runs, e.g. "Việt|Times New Roman".
+func runsSignature(runs []excelize.RichTextRun) string {
+	parts := []string{runsText(runs)}
+	for _, run := range runs {
+		family := ""
+		if run.Font != nil {
+			family = run.Font.Family
+		}
+		parts = append(parts, family)
+	}
+	return strings.Join(parts, "|")
+}
+
+// workbookSignatures returns the runsSignature of every cell of conversionPathCells in f.
+// Cells written back as plain values have no runs.
+func workbookSignatures(t *testing.T, f *excelize.File) map[string]string {
+	t.Helper()
+	out := make(map[string]string)
+	for _, c := range conversionPathCells {
+		runs, err := f.GetCellRichText("Sheet1", c.axis)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", c.axis, err)
+		}
+		if len(runs) == 0 {
+			value, err := f.GetCellValue("Sheet1", c.axis)
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", c.axis, err)
+			}
+			runs = []excelize.RichTextRun{{Text: value}}
+		}
+		out[c.axis] = runsSignature(runs)
+	}
+	return out
+}
+
+// TestConversionPaths checks that the file run of the GUI and CLI, the in-memory and
+// streaming library entry points and FormatPreserver itself convert cells identically.
+func TestConversionPaths(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "paths.xlsx")
+	writeConversionPathWorkbook(t, inputFile)
+
+	// FormatPreserver on its own, given each cell's runs and style font
+	fp, err := NewFormatPreserver()
+	if err != nil {
+		t.Fatalf("NewFormatPreserver failed: %v", err)
+	}
+	want := make(map[string]string)
+	for _, c := range conversionPathCells {
+		var style *excelize.Font
+		if c.style != "" {
+			style = &excelize.Font{Family: c.style, Size: 12}
+		}
+		runs, _ := fp.ProcessRichText(c.runs, style, nil)
+		want[c.axis] = runsSignature(MergeRuns(runs))
+	}
+	if !strings.HasPrefix(want["A3"], "CÔNG TY|") || !strings.HasPrefix(want["A1"], "Việt Nam|Times New Roman") {
+		t.Fatalf("FormatPreserver converted %v", want)
+	}
+
+	paths := []struct {
+		name    string
+		convert func(t *testing.T) *excelize.File
+	}{
+		{name: "Run", convert: func(t *testing.T) *excelize.File {
+			outputFile, err := NewProcessor(inputFile, "").Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			t.Cleanup(func() { _ = os.Remove(outputFile) })
+			f, err := excelize.OpenFile(outputFile)
+			if err != nil {
+				t.Fatalf("failed to open output: %v", err)
+			}
+			return f
+		}},
+		{name: "ProcessWorkbook", convert: func(t *testing.T) *excelize.File {
+			f, err := excelize.OpenFile(inputFile)
+			if err != nil {
+				t.Fatalf("failed to open input: %v", err)
+			}
+			if _, err := ProcessWorkbook(context.Background(), f); err != nil {
+				t.Fatalf("ProcessWorkbook failed: %v", err)
+			}
+			return f
+		}},
+		{name: "ConvertXLSX", convert: func(t *testing.T) *excelize.File {
+			in, err := os.Open(inputFile)
+			if err != nil {
+				t.Fatalf("failed to open input: %v", err)
+			}
+			defer func() { _ = in.Close() }()
+			var out bytes.Buffer
+			if _, err := ConvertXLSX(context.Background(), in, &out); err != nil {
+				t.Fatalf("ConvertXLSX failed: %v", err)
+			}
+			f, err := excelize.OpenReader(&out)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			return f
+		}},
+	}
+
+	for _, tt := range paths {
+		t.Run(tt.name, func(t *testing.T) {
+			f := tt.convert(t)
+			defer func() { _ = f.Close() }()
+			got := workbookSignatures(t, f)
+			for _, c := range conversionPathCells {
+				if got[c.axis] != want[c.axis] {
+					t.Errorf("%s = %q, want %q", c.axis, got[c.axis], want[c.axis])
+				}
+			}
+		})
+	}
+}
+
+func TestProcessRichText_InheritsStyleFont(t *testing.T) {
+	fp, err := NewFormatPreserver()
+	if err != nil {
+		t.Fatalf("NewFormatPreserver failed: %v", err)
+	}
+	vniStyle := &excelize.Font{Family: "VNI-Times", Size: 12}
+	tests := []struct {
+		name       string
+		run        excelize.RichTextRun
+		style      *excelize.Font
+		wantText   string
+		wantFamily string
+		wantBold   bool
+	}{
+		{name: "Nil font", run: excelize.RichTextRun{Text: "Vi\u00D6t"}, style: vniStyle, wantText: "Việt", wantFamily: "Times New Roman"},
+		{name: "Font without family", run: excelize.RichTextRun{Text: "Vi\u00D6t", Font: &excelize.Font{Bold: true}}, style: vniStyle, wantText: "Việt", wantFamily: "Times New Roman", wantBold: true},
+		{name: "Own family", run: excelize.RichTextRun{Text: "C\u00ABng", Font: &excelize.Font{Family: ".VnTimeH"}}, style: vniStyle, wantText: "CÔNG", wantFamily: "Times New Roman"},
+		{name: "No style font", run: excelize.RichTextRun{Text: "Vi\u00D6t Nam"}, wantText: "Việt Nam", wantFamily: DefaultFont},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFont string
+			runs, _ := fp.ProcessRichText([]excelize.RichTextRun{tt.run}, tt.style, func(_ int, fontName string, _ Detection) {
+				gotFont = fontName
+			})
+			got := runs[0]
+			if got.Text != tt.wantText || got.Font == nil || got.Font.Family != tt.wantFamily || got.Font.Bold != tt.wantBold {
+				t.Errorf("ProcessRichText() = %q %+v, want %q in %s (bold %v)", got.Text, got.Font, tt.wantText, tt.wantFamily, tt.wantBold)
+			}
+			if tt.style != nil && (tt.run.Font == nil || tt.run.Font.Family == "") && gotFont != tt.style.Family {
+				t.Errorf("detected with font %q, want the style font %q", gotFont, tt.style.Family)
+			}
+		})
+	}
+}
+
+func TestProcessor_RunWithNormalization(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "nfd.xlsx")
+	writeLegacySheetWorkbook(t, inputFile)
+	f, err := excelize.OpenFile(inputFile)
+	if err != nil {
+		t.Fatalf("failed to open input: %v", err)
+	}
+	vni := converter.NewVNIEncoder()
+	if err := f.AddComment("Sheet1", excelize.Comment{
+		Cell:      "B1",
+		Author:    vni.FromUnicode("Nguyễn Văn"),
+		Paragraph: []excelize.RichTextRun{{Text: vni.FromUnicode("Kiểm tra"), Font: &excelize.Font{Family: "VNI-Times"}}},
+	}); err != nil {
+		t.Fatalf("failed to add comment: %v", err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	_ = f.Close()
+
+	tests := []struct {
+		name string
+		form converter.Normalization
+		want func(string) string
+	}{
+		{name: "NFC", form: converter.NormalizationNFC, want: norm.NFC.String},
+		{name: "NFD", form: converter.NormalizationNFD, want: norm.NFD.String},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc := NewProcessor(inputFile, "")
+			proc.SetNormalization(tt.form)
+			outputFile, err := proc.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			defer func() { _ = os.Remove(outputFile) }()
+			fOut, err := excelize.OpenFile(outputFile)
+			if err != nil {
+				t.Fatalf("failed to open output: %v", err)
+			}
+			defer func() { _ = fOut.Close() }()
+
+			sheet := tt.want("Báo cáo")
+			if got := fOut.GetSheetList(); len(got) != 2 || got[1] != sheet {
+				t.Errorf("sheets = %q, want %q", got, sheet)
+			}
+			if got, _ := fOut.GetCellValue(sheet, "A1"); got != tt.want("Việt Nam") {
+				t.Errorf("A1 = %q, want %q", got, tt.want("Việt Nam"))
+			}
+			comments, err := fOut.GetComments("Sheet1")
+			if err != nil || len(comments) != 1 {
+				t.Fatalf("GetComments() = %+v, %v; want one comment", comments, err)
+			}
+			if c := comments[0]; c.Author != tt.want("Nguyễn Văn") || runsText(c.Paragraph) != tt.want("Kiểm tra") {
+				t.Errorf("comment = %q by %q", runsText(c.Paragraph), c.Author)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package engine
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Fields of a cell compared by CompareWorkbooks.
+const (
+	GoldenValue   = "value"
+	GoldenFormula = "formula"
+	GoldenFont    = "font"
+)
+
+// GoldenDiff is one cell field that differs from the golden output.
+type GoldenDiff struct {
+	SheetName string `json:"sheetName"`
+	Axis      string `json:"axis"`
+	Field     string `json:"field"`
+	Expected  string `json:"expected"`
+	Actual    string `json:"actual"`
+}
+
+// GoldenReport is the outcome of comparing a conversion with its golden output.
+type GoldenReport struct {
+	// CellsCompared counts the cells that are non-empty in either workbook
+	CellsCompared int          `json:"cellsCompared"`
+	Differences   []GoldenDiff `json:"differences,omitempty"`
+	// MissingSheets are in the golden output only; ExtraSheets in the conversion only
+	MissingSheets []string `json:"missingSheets,omitempty"`
+	ExtraSheets   []string `json:"extraSheets,omitempty"`
+}
+
+// Passed reports whether the conversion matches the golden output exactly.
+func (r *GoldenReport) Passed() bool {
+	return len(r.Differences) == 0 && len(r.MissingSheets) == 0 && len(r.ExtraSheets) == 0
+}
+
+// WriteCSV writes the differences to w, one row per cell field.
+func (r *GoldenReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"sheet", "cell", "field", "expected", "actual"}); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	for _, name := range r.MissingSheets {
+		if err := cw.Write([]string{name, "", "sheet", name, ""}); err != nil {
+			return fmt.Errorf("failed to write csv record: %w", err)
+		}
+	}
+	for _, name := range r.ExtraSheets {
+		if err := cw.Write([]string{name, "", "sheet", "", name}); err != nil {
+			return fmt.Errorf("failed to write csv record: %w", err)
+		}
+	}
+	for _, d := range r.Differences {
+		if err := cw.Write([]string{d.SheetName, d.Axis, d.Field, d.Expected, d.Actual}); err != nil {
+			return fmt.Errorf("failed to write csv record: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// CompareGolden converts the workbook at input in memory, configured by opts, and compares
+// the result cell by cell with the golden workbook. Nothing is saved.
+// Why: Teams validating a new version re-convert a set of reference files and need every
+// cell that now converts differently, not just whether the files are byte-identical
+// (they never are: timestamps and build properties change).
+func CompareGolden(ctx context.Context, input, golden string, opts ...Option) (*GoldenReport, error) {
+	actual, err := excelize.OpenFile(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open input: %w", err)
+	}
+	defer func() { _ = actual.Close() }()
+	expected, err := excelize.OpenFile(golden)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open golden output: %w", err)
+	}
+	defer func() { _ = expected.Close() }()
+
+	if _, err := ProcessWorkbook(ctx, actual, opts...); err != nil {
+		return nil, err
+	}
+	return CompareWorkbooks(ctx, actual, expected)
+}
+
+// CompareWorkbooks compares the value, formula and font of every cell of the sheets that
+// actual and expected have in common. Sheets are matched by name.
+func CompareWorkbooks(ctx context.Context, actual, expected *excelize.File) (*GoldenReport, error) {
+	report := &GoldenReport{}
+	actualSheets := make(map[string]bool)
+	for _, sheet := range actual.GetSheetList() {
+		actualSheets[sheet] = true
+	}
+	for _, sheet := range expected.GetSheetList() {
+		if !actualSheets[sheet] {
+			report.MissingSheets = append(report.MissingSheets, sheet)
+			continue
+		}
+		delete(actualSheets, sheet)
+		if err := compareSheet(ctx, actual, expected, sheet, report); err != nil {
+			return nil, err
+		}
+	}
+	// Keep the workbook's tab order
+	for _, sheet := range actual.GetSheetList() {
+		if actualSheets[sheet] {
+			report.ExtraSheets = append(report.ExtraSheets, sheet)
+		}
+	}
+	return report, nil
+}
+
+// compareSheet adds the differences of one sheet to report.
+func compareSheet(ctx context.Context, actual, expected *excelize.File, sheet string, report *GoldenReport) error {
+	// Raw values, so a number format change is not mistaken for a conversion change
+	actualRows, err := actual.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return fmt.Errorf("failed to read converted sheet %q: %w", sheet, err)
+	}
+	expectedRows, err := expected.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return fmt.Errorf("failed to read golden sheet %q: %w", sheet, err)
+	}
+
+	for r := 0; r < max(len(actualRows), len(expectedRows)); r++ {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("comparison cancelled: %w", err)
+		}
+		actualRow, expectedRow := rowAt(actualRows, r), rowAt(expectedRows, r)
+		for c := 0; c < max(len(actualRow), len(expectedRow)); c++ {
+			axis, err := excelize.CoordinatesToCellName(c+1, r+1)
+			if err != nil {
+				slog.Error("failed to convert coordinates", "row", r+1, "col", c+1, "error", err)
+				continue
+			}
+			a, e := cellAt(actualRow, c), cellAt(expectedRow, c)
+			formulaA, _ := actual.GetCellFormula(sheet, axis)
+			formulaE, _ := expected.GetCellFormula(sheet, axis)
+			if a == "" && e == "" && formulaA == "" && formulaE == "" {
+				continue
+			}
+			report.CellsCompared++
+			diff := func(field, expected, actual string) {
+				if expected != actual {
+					report.Differences = append(report.Differences, GoldenDiff{
+						SheetName: sheet, Axis: axis, Field: field, Expected: expected, Actual: actual,
+					})
+				}
+			}
+			diff(GoldenValue, e, a)
+			diff(GoldenFormula, formulaE, formulaA)
+			diff(GoldenFont, cellFonts(expected, sheet, axis), cellFonts(actual, sheet, axis))
+		}
+	}
+	return nil
+}
+
+func rowAt(rows [][]string, i int) []string {
+	if i < len(rows) {
+		return rows[i]
+	}
+	return nil
+}
+
+func cellAt(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// cellFonts returns the font families a cell is displayed in: those of its runs, comma
+// separated, or its style font for cells without run fonts.
+func cellFonts(f *excelize.File, sheet, axis string) string {
+	if runs, err := f.GetCellRichText(sheet, axis); err == nil && hasAnyRunFont(runs) {
+		families := make([]string, 0, len(runs))
+		for _, run := range runs {
+			family := ""
+			if run.Font != nil {
+				family = run.Font.Family
+			}
+			families = append(families, family)
+		}
+		return strings.Join(families, ",")
+	}
+	styleID, err := f.GetCellStyle(sheet, axis)
+	if err != nil {
+		return ""
+	}
+	style, err := f.GetStyle(styleID)
+	if err != nil || style.Font == nil {
+		return ""
+	}
+	return style.Font.Family
+}
@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestCompareGolden(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.xlsx")
+	writeWorkbook(t, input, map[string]string{"A1": "Vi\u00D6t Nam", "A2": "Hello"})
+
+	// The golden output is the conversion itself, edited per test case
+	saveGolden := func(t *testing.T, edit func(f *excelize.File) error) string {
+		t.Helper()
+		f, err := excelize.OpenFile(input)
+		if err != nil {
+			t.Fatalf("failed to open input: %v", err)
+		}
+		defer func() { _ = f.Close() }()
+		if _, err := ProcessWorkbook(context.Background(), f); err != nil {
+			t.Fatalf("ProcessWorkbook failed: %v", err)
+		}
+		if edit != nil {
+			if err := edit(f); err != nil {
+				t.Fatalf("failed to edit golden output: %v", err)
+			}
+		}
+		path := filepath.Join(t.TempDir(), "golden.xlsx")
+		if err := f.SaveAs(path); err != nil {
+			t.Fatalf("failed to save golden output: %v", err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name        string
+		edit        func(f *excelize.File) error
+		wantDiffs   []GoldenDiff
+		wantMissing []string
+	}{
+		{name: "Match"},
+		{
+			name: "Value",
+			edit: func(f *excelize.File) error { return f.SetCellValue("Sheet1", "A2", "Hi") },
+			wantDiffs: []GoldenDiff{
+				{SheetName: "Sheet1", Axis: "A2", Field: GoldenValue, Expected: "Hi", Actual: "Hello"},
+			},
+		},
+		{
+			name: "Formula",
+			edit: func(f *excelize.File) error { return f.SetCellFormula("Sheet1", "B1", "1+1") },
+			wantDiffs: []GoldenDiff{
+				{SheetName: "Sheet1", Axis: "B1", Field: GoldenFormula, Expected: "1+1"},
+			},
+		},
+		{
+			name: "Font",
+			edit: func(f *excelize.File) error {
+				return f.SetCellRichText("Sheet1", "A1", []excelize.RichTextRun{{Text: "Việt Nam", Font: &excelize.Font{Family: "Cambria"}}})
+			},
+			wantDiffs: []GoldenDiff{
+				{SheetName: "Sheet1", Axis: "A1", Field: GoldenFont, Expected: "Cambria", Actual: "Arial"},
+			},
+		},
+		{
+			name: "Missing sheet",
+			edit: func(f *excelize.File) error {
+				_, err := f.NewSheet("Summary")
+				return err
+			},
+			wantMissing: []string{"Summary"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			golden := saveGolden(t, tt.edit)
+			report, err := CompareGolden(context.Background(), input, golden)
+			if err != nil {
+				t.Fatalf("CompareGolden failed: %v", err)
+			}
+			if !reflect.DeepEqual(report.Differences, tt.wantDiffs) {
+				t.Errorf("Differences = %+v, want %+v", report.Differences, tt.wantDiffs)
+			}
+			if !reflect.DeepEqual(report.MissingSheets, tt.wantMissing) {
+				t.Errorf("MissingSheets = %q, want %q", report.MissingSheets, tt.wantMissing)
+			}
+			wantPassed := tt.wantDiffs == nil && tt.wantMissing == nil
+			if report.Passed() != wantPassed || report.CellsCompared < 2 {
+				t.Errorf("Passed() = %v after %d cells, want %v", report.Passed(), report.CellsCompared, wantPassed)
+			}
+
+			var buf bytes.Buffer
+			if err := report.WriteCSV(&buf); err != nil {
+				t.Fatalf("WriteCSV failed: %v", err)
+			}
+			if lines := strings.Count(buf.String(), "\n"); lines != 1+len(tt.wantDiffs)+len(tt.wantMissing) {
+				t.Errorf("CSV has %d lines:\n%s", lines, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BackupMode selects how the original workbook is kept when it is overwritten in place.
+type BackupMode string
+
+const (
+	// BackupNone disables in-place conversion: a new timestamped output is written.
+	BackupNone BackupMode = ""
+	// BackupFile renames the original to "<name>.xlsx.bak" next to it.
+	BackupFile BackupMode = "bak"
+	// BackupFolder moves the original into a "backup" folder next to it.
+	BackupFolder BackupMode = "folder"
+)
+
+// backupDirName is the folder used by BackupFolder.
+const backupDirName = "backup"
+
+// ParseBackupMode validates a backup mode name (case-insensitive). An empty name or
+// "none" disables in-place conversion.
+func ParseBackupMode(name string) (BackupMode, error) {
+	switch m := BackupMode(strings.ToLower(strings.TrimSpace(name))); m {
+	case BackupNone, "none":
+		return BackupNone, nil
+	case BackupFile, BackupFolder:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown backup mode %q (use bak or folder)", name)
+	}
+}
+
+// SetInPlace overwrites the input workbook instead of writing a new output, after keeping
+// the original as decided by mode. BackupNone restores the default timestamped output.
+// Why: Some downstream systems expect the converted workbook under the same file name.
+func (p *Processor) SetInPlace(mode BackupMode) {
+	p.backupMode = mode
+}
+
+// BackupPath returns where the original was kept by the last in-place Run ("" otherwise).
+func (p *Processor) BackupPath() string {
+	return p.backupPath
+}
+
+// saveInPlace writes the converted workbook next to the input, moves the input to its
+// backup path, then moves the converted file to the input path. Until the final rename
+// the input is untouched, and it is restored if that rename fails.
+func (p *Processor) saveInPlace() (string, error) {
+	input := p.InputPath
+	info, err := os.Stat(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat input: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(input), ".vniconverter-*"+filepath.Ext(input))
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary output: %w", err)
+	}
+	tmpPath := tmp.Name()
+	_ = tmp.Close()
+	removeTmp := func() {
+		if err := os.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+			slog.Error("failed to remove temporary output", "path", tmpPath, "error", err)
+		}
+	}
+	if err := p.f.SaveAs(tmpPath); err != nil {
+		removeTmp()
+		return "", fmt.Errorf("failed to save output file: %w", err)
+	}
+	// The original must never be replaced by a workbook Excel would have to repair
+	if err := p.verifyOutput(tmpPath); err != nil {
+		removeTmp()
+		return "", err
+	}
+	// CreateTemp makes the file private; keep the original's permissions instead
+	if err := os.Chmod(tmpPath, info.Mode().Perm()); err != nil {
+		slog.Warn("failed to copy input permissions", "path", tmpPath, "error", err)
+	}
+
+	backup, err := reserveBackupPath(input, p.backupMode)
+	if err != nil {
+		removeTmp()
+		return "", err
+	}
+	if err := os.Rename(input, backup); err != nil {
+		removeTmp()
+		_ = os.Remove(backup)
+		return "", fmt.Errorf("failed to back up original: %w", err)
+	}
+	if err := os.Rename(tmpPath, input); err != nil {
+		if restoreErr := os.Rename(backup, input); restoreErr != nil {
+			slog.Error("failed to restore original from backup", "backup", backup, "error", restoreErr)
+		}
+		removeTmp()
+		return "", fmt.Errorf("failed to replace original: %w", err)
+	}
+	p.backupPath = backup
+	slog.Info("original backed up", "backup", backup)
+	return input, nil
+}
+
+// RestoreBackup moves the original kept at backup by an in-place Run back to input,
+// replacing the converted workbook.
+// Why: Converting the wrong sheet in place must not cost the user their original.
+func RestoreBackup(input, backup string) error {
+	if _, err := os.Stat(backup); err != nil {
+		return fmt.Errorf("original no longer available: %w", err)
+	}
+	if err := os.Rename(backup, input); err != nil {
+		return fmt.Errorf("failed to restore original: %w", err)
+	}
+	slog.Info("original restored", "input", input, "backup", backup)
+	return nil
+}
+
+// reserveBackupPath reserves "<name>.xlsx.bak" (BackupFile) or "backup/<name>.xlsx"
+// (BackupFolder) next to input, adding "_2", "_3", ... to the name if it is taken.
+// Why: A second in-place run must never overwrite the backup of the true original.
+func reserveBackupPath(input string, mode BackupMode) (string, error) {
+	dir, name := filepath.Split(input)
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	if mode == BackupFolder {
+		dir = filepath.Join(dir, backupDirName)
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return "", fmt.Errorf("failed to create backup folder: %w", err)
+		}
+		return reserveOutputPath(filepath.Join(dir, name))
+	}
+	return reserveFreePath(filepath.Join(dir, name+".bak"), func(n int) string {
+		return filepath.Join(dir, fmt.Sprintf("%s_%d%s.bak", base, n, ext))
+	})
+}
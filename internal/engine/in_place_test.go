@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestProcessor_RunInPlace(t *testing.T) {
+	tests := []struct {
+		name string
+		mode BackupMode
+		// existing backups created before the run, relative to the input folder
+		existing   []string
+		wantBackup string
+	}{
+		{name: "Bak file", mode: BackupFile, wantBackup: "book.xlsx.bak"},
+		{name: "Bak file taken", mode: BackupFile, existing: []string{"book.xlsx.bak"}, wantBackup: "book_2.xlsx.bak"},
+		{name: "Backup folder", mode: BackupFolder, wantBackup: filepath.Join("backup", "book.xlsx")},
+		{name: "Backup folder taken", mode: BackupFolder, existing: []string{filepath.Join("backup", "book.xlsx")}, wantBackup: filepath.Join("backup", "book_2.xlsx")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			inputFile := filepath.Join(dir, "book.xlsx")
+			writeWorkbook(t, inputFile, map[string]string{"A1": "Vi\u00D6t Nam"})
+			original, err := os.ReadFile(inputFile)
+			if err != nil {
+				t.Fatalf("failed to read input: %v", err)
+			}
+			for _, name := range tt.existing {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+					t.Fatalf("failed to create folder: %v", err)
+				}
+				if err := os.WriteFile(path, []byte("older backup"), 0600); err != nil {
+					t.Fatalf("failed to create %s: %v", name, err)
+				}
+			}
+
+			proc := NewProcessor(inputFile, "")
+			proc.SetInPlace(tt.mode)
+			outputFile, err := proc.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if outputFile != inputFile {
+				t.Errorf("Run() = %q, want the input path", outputFile)
+			}
+			if want := filepath.Join(dir, tt.wantBackup); proc.BackupPath() != want {
+				t.Errorf("BackupPath() = %q, want %q", proc.BackupPath(), want)
+			}
+
+			backup, err := os.ReadFile(proc.BackupPath())
+			if err != nil || string(backup) != string(original) {
+				t.Errorf("backup does not hold the original workbook (err %v)", err)
+			}
+			for _, name := range tt.existing {
+				if data, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(data) != "older backup" {
+					t.Errorf("existing backup %s was modified", name)
+				}
+			}
+
+			f, err := excelize.OpenFile(inputFile)
+			if err != nil {
+				t.Fatalf("failed to open converted input: %v", err)
+			}
+			defer func() { _ = f.Close() }()
+			if got, _ := f.GetCellValue("Sheet1", "A1"); got != "Việt Nam" {
+				t.Errorf("A1 = %q, want converted text", got)
+			}
+
+			// Only the workbook and its backup are left; no temporary output
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("failed to list folder: %v", err)
+			}
+			for _, e := range entries {
+				if name := e.Name(); name != "book.xlsx" && filepath.Join(dir, name) != proc.BackupPath() && name != "backup" && !slices.Contains(tt.existing, name) {
+					t.Errorf("unexpected file %s left in folder", name)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "book.xlsx")
+	writeWorkbook(t, inputFile, map[string]string{"A1": "Vi\u00D6t Nam"})
+	original, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatalf("failed to read input: %v", err)
+	}
+
+	proc := NewProcessor(inputFile, "")
+	proc.SetInPlace(BackupFolder)
+	if _, err := proc.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if err := RestoreBackup(inputFile, proc.BackupPath()); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+	if data, err := os.ReadFile(inputFile); err != nil || string(data) != string(original) {
+		t.Errorf("input does not hold the original workbook (err %v)", err)
+	}
+	if _, err := os.Stat(proc.BackupPath()); !os.IsNotExist(err) {
+		t.Errorf("backup still exists (err %v)", err)
+	}
+	if err := RestoreBackup(inputFile, proc.BackupPath()); err == nil {
+		t.Error("RestoreBackup() restored a missing backup")
+	}
+}
+
+func TestParseBackupMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    BackupMode
+		wantErr bool
+	}{
+		{name: "", want: BackupNone},
+		{name: "none", want: BackupNone},
+		{name: "BAK", want: BackupFile},
+		{name: "folder", want: BackupFolder},
+		{name: "zip", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBackupMode(tt.name)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseBackupMode(%q) = %q, %v; want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
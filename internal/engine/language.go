@@ -0,0 +1,37 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"convert-vni-to-unicode/internal/converter"
+	"convert-vni-to-unicode/internal/spell"
+)
+
+// minEnglishWords is how many English words a text needs before it can be clearly English.
+const minEnglishWords = 2
+
+// detectEnglish matches text that is clearly English: at least minEnglishWords English
+// words, and more of them than words with legacy-looking characters. A word is English
+// when it is plain ASCII, at least three letters long, not an all-caps acronym and not a
+// Vietnamese syllable ("Street", "Total"; but not "Nam", "TNHH" or "ty").
+// Why: Names and loanwords such as "Müller" or "naïve" contain VNI and TCVN3 markers, so
+// English columns next to Vietnamese ones were converted by the content heuristics. Only
+// the content rules defer to it; a legacy font name still decides first.
+func detectEnglish(text string) (Detection, bool) {
+	english, legacy := 0, 0
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		switch {
+		case !isASCII(word):
+			legacy++
+		case len(word) >= 3 && strings.ToUpper(word) != word && !spell.ValidSyllable(word):
+			english++
+		}
+	}
+	if english < minEnglishWords || english <= legacy {
+		return Detection{}, false
+	}
+	evidence := fmt.Sprintf("words=%d", english)
+	return Detection{Encoding: converter.EncodingUnknown, Rule: RuleEnglish, Evidence: evidence}, true
+}
@@ -0,0 +1,503 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// errLargeFileUnsupported is returned by Run when large file mode is combined with a
+// setting that needs the whole workbook in memory.
+var errLargeFileUnsupported = errors.New(
+	"large file mode cannot be combined with in-place output, checkpoints or font reports")
+
+// SetLargeFileMode makes Run write the output with excelize's StreamWriter, one sheet at a
+// time, instead of converting the open workbook and saving it whole.
+// Values, formulas, cell and row styles, column widths, merged cells, sheet views, tab
+// colors, hidden sheets and defined names are kept; comments, charts, images, tables,
+// hyperlinks, conditional formats and data validations are dropped.
+// Why: SaveAs builds the whole output in memory, which passed 2 GB on 500k-row workbooks.
+func (p *Processor) SetLargeFileMode(enabled bool) {
+	p.largeFileMode = enabled
+}
+
+// runLargeFile converts sheets into a new workbook written with StreamWriter and saves it
+// under a new output name. Progress counts every non-empty cell of the converted sheets.
+func (p *Processor) runLargeFile(ctx context.Context, sheets []string) (string, error) {
+	if p.backupMode != BackupNone || p.checkpointing || p.fontReport != nil {
+		return "", errLargeFileUnsupported
+	}
+	slog.Warn("large file mode drops comments, charts, images, tables, hyperlinks, " +
+		"conditional formats and data validations")
+
+	out := excelize.NewFile(excelize.Options{TmpDir: p.tempDir})
+	defer func() {
+		if closeErr := out.Close(); closeErr != nil {
+			slog.Error("failed to close output workbook", "error", closeErr)
+		}
+	}()
+
+	all := p.f.GetSheetList()
+	p.renamed = nil
+	if p.convertSheetNames {
+		p.renamed = p.planSheetRenames(all, sheets)
+	}
+	names := make(map[string]string, len(all))
+	for _, sheet := range all {
+		names[sheet] = sheet
+	}
+	for _, r := range p.renamed {
+		names[r.From] = r.To
+	}
+	if err := createSheets(out, all, names); err != nil {
+		return "", err
+	}
+
+	selected := make(map[string]bool, len(sheets))
+	for _, sheet := range sheets {
+		selected[sheet] = true
+	}
+	p.total = p.countLargeFileCells(ctx, sheets)
+	p.skipped = nil
+	p.truncated = nil
+	p.errs.reset()
+	p.processed = 0
+
+	sw := &sheetStreamer{
+		p:        p,
+		out:      out,
+		styles:   make(map[int]int),
+		plain:    make(map[plainStyle]int),
+		replacer: sheetReferenceReplacer(p.renamed),
+		started:  time.Now(),
+	}
+	for _, sheet := range all {
+		// A new workbook cannot carry a broken sheet through unchanged, and the row
+		// iterator would silently stop at its first XML error
+		if err := p.parseSheet(sheet); err != nil {
+			return "", fmt.Errorf("sheet %q cannot be parsed, "+
+				"convert without large file mode to keep it unchanged: %w", sheet, err)
+		}
+		if err := sw.stream(ctx, sheet, names[sheet], selected[sheet]); err != nil {
+			return "", err
+		}
+		// Converted sheets are never read again; only the input's parsed copy is dropped
+		dropParsedSheets(p.f)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("conversion cancelled: %w", err)
+	}
+
+	p.copyWorkbookSettings(out, all, names, sw.replacer)
+	p.stampBuildInfo(out)
+
+	release, err := p.throttle.Acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	return p.saveOutput(out)
+}
+
+// createSheets adds the sheets of all to out, in order, under their new names.
+func createSheets(out *excelize.File, all []string, names map[string]string) error {
+	for i, sheet := range all {
+		var err error
+		if i == 0 {
+			// A new workbook starts with one sheet, which becomes the first one
+			err = out.SetSheetName(out.GetSheetName(0), names[sheet])
+		} else {
+			_, err = out.NewSheet(names[sheet])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create sheet %q: %w", names[sheet], err)
+		}
+	}
+	return nil
+}
+
+// countLargeFileCells counts the non-empty cells of sheets within the cell filter.
+// Why: Unlike countCells it reads values only, so no worksheet is parsed twice.
+func (p *Processor) countLargeFileCells(ctx context.Context, sheets []string) int {
+	total := 0
+	for _, sheet := range sheets {
+		rows, err := p.f.Rows(sheet)
+		if err != nil {
+			slog.Error("failed to get rows", "sheet", sheet, "error", err)
+			continue
+		}
+		for rowIdx := 1; rows.Next() && !p.cellFilter.pastLastRow(rowIdx); rowIdx++ {
+			if ctx.Err() != nil {
+				break
+			}
+			cols, err := rows.Columns()
+			if err != nil {
+				continue
+			}
+			for colIdx, text := range cols {
+				if text != "" && p.cellFilter.Contains(colIdx+1, rowIdx) {
+					total++
+				}
+			}
+		}
+		if err := rows.Close(); err != nil {
+			slog.Error("failed to close rows iterator", "sheet", sheet, "error", err)
+		}
+	}
+	return total
+}
+
+// dropParsedSheets releases the worksheets excelize has parsed from f.
+// Only for workbooks that are not saved: unsaved changes to those sheets are lost.
+func dropParsedSheets(f *excelize.File) {
+	f.Sheet.Range(func(key, _ any) bool {
+		f.Sheet.Delete(key)
+		return true
+	})
+}
+
+// copyWorkbookSettings copies the active sheet, hidden sheets and defined names to out.
+func (p *Processor) copyWorkbookSettings(out *excelize.File, all []string, names map[string]string,
+	replacer sheetRefReplacer) {
+	out.SetActiveSheet(p.f.GetActiveSheetIndex())
+	for _, sheet := range all {
+		// The active sheet cannot be hidden, so visibility follows it
+		if visible, err := p.f.GetSheetVisible(sheet); err == nil && !visible {
+			if err := out.SetSheetVisible(names[sheet], false); err != nil {
+				slog.Warn("failed to hide sheet", "sheet", names[sheet], "error", err)
+			}
+		}
+	}
+	for _, dn := range p.f.GetDefinedName() {
+		dn.RefersTo = replacer.Replace(dn.RefersTo)
+		if name, ok := names[dn.Scope]; ok {
+			dn.Scope = name
+		}
+		if err := out.SetDefinedName(&dn); err != nil {
+			slog.Warn("failed to copy defined name", "name", dn.Name, "error", err)
+		}
+	}
+}
+
+// sheetStreamer writes the sheets of the input workbook to out with StreamWriter.
+type sheetStreamer struct {
+	p   *Processor
+	out *excelize.File
+	// styles maps input style IDs to output style IDs
+	styles map[int]int
+	// plain caches the output styles of plain cells whose font family was swapped
+	plain    map[plainStyle]int
+	replacer sheetRefReplacer
+	started  time.Time
+}
+
+// stream writes sheet to the output sheet name, converting its text cells if convert is set.
+func (s *sheetStreamer) stream(ctx context.Context, sheet, name string, convert bool) error {
+	sw, err := s.newStreamWriter(sheet, name)
+	if err != nil {
+		return err
+	}
+	rows, err := s.p.f.Rows(sheet)
+	if err != nil {
+		return fmt.Errorf("failed to get rows of %q: %w", sheet, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Error("failed to close rows iterator", "sheet", sheet, "error", err)
+		}
+	}()
+
+	for rowIdx := 1; rows.Next(); rowIdx++ {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("conversion cancelled: %w", err)
+		}
+		cols, err := rows.Columns()
+		if err != nil {
+			return fmt.Errorf("failed to get columns of %q row %d: %w", sheet, rowIdx, err)
+		}
+		opts := rows.GetRowOpts()
+		opts.StyleID = s.style(opts.StyleID)
+		values, err := s.rowValues(ctx, sheet, cols, rowIdx, convert)
+		if err != nil {
+			return err
+		}
+		// The row iterator also yields the empty rows between used ones
+		if len(values) == 0 && opts == (excelize.RowOpts{}) {
+			continue
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, rowIdx)
+		if err := sw.SetRow(cell, values, opts); err != nil {
+			return fmt.Errorf("failed to write %q row %d: %w", name, rowIdx, err)
+		}
+	}
+
+	s.copyMergedCells(sw, sheet)
+	if err := sw.Flush(); err != nil {
+		return fmt.Errorf("failed to write sheet %q: %w", name, err)
+	}
+	return nil
+}
+
+// newStreamWriter copies the sheet properties, view, panes and columns of sheet to the
+// output sheet name and returns the stream writer for its rows.
+func (s *sheetStreamer) newStreamWriter(sheet, name string) (*excelize.StreamWriter, error) {
+	in := s.p.f
+	// Sheet properties and views are written with the first row, so they are set first
+	if props, err := in.GetSheetProps(sheet); err == nil {
+		if err := s.out.SetSheetProps(name, &props); err != nil {
+			slog.Warn("failed to copy sheet properties", "sheet", sheet, "error", err)
+		}
+	}
+	if view, err := in.GetSheetView(sheet, 0); err == nil {
+		if err := s.out.SetSheetView(name, 0, &view); err != nil {
+			slog.Warn("failed to copy sheet view", "sheet", sheet, "error", err)
+		}
+	}
+
+	sw, err := s.out.NewStreamWriter(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stream writer for %q: %w", name, err)
+	}
+	if panes, err := in.GetPanes(sheet); err == nil && (panes.Freeze || panes.Split) {
+		if err := sw.SetPanes(&panes); err != nil {
+			slog.Warn("failed to copy panes", "sheet", sheet, "error", err)
+		}
+	}
+	s.copyColumns(sw, sheet)
+	return sw, nil
+}
+
+// rowValues returns the values to write for the cells cols of row rowIdx, converted if
+// convert is set.
+func (s *sheetStreamer) rowValues(ctx context.Context, sheet string, cols []string, rowIdx int,
+	convert bool) ([]interface{}, error) {
+	var jobs map[int]Job
+	if convert && !s.p.cellFilter.pastLastRow(rowIdx) {
+		jobs = make(map[int]Job)
+		for _, job := range s.p.cellJobs(ctx, sheet, cols, rowIdx) {
+			jobs[job.Col] = job
+		}
+	}
+
+	values := make([]interface{}, len(cols))
+	for colIdx, text := range cols {
+		axis, err := excelize.CoordinatesToCellName(colIdx+1, rowIdx)
+		if err != nil {
+			return nil, err
+		}
+		if convert && text != "" && s.p.cellFilter.Contains(colIdx+1, rowIdx) {
+			s.progress(sheet)
+		}
+		if job, ok := jobs[colIdx+1]; ok {
+			if value, ok := s.convert(ctx, job); ok {
+				values[colIdx] = value
+				continue
+			}
+		}
+		values[colIdx] = s.copyCell(sheet, axis, text)
+	}
+	return values, nil
+}
+
+// copyMergedCells copies the merged cells of sheet.
+func (s *sheetStreamer) copyMergedCells(sw *excelize.StreamWriter, sheet string) {
+	merged, err := s.p.f.GetMergeCells(sheet, true)
+	if err != nil {
+		return
+	}
+	for _, mc := range merged {
+		if err := sw.MergeCell(mc.GetStartAxis(), mc.GetEndAxis()); err != nil {
+			slog.Warn("failed to copy merged cell",
+				"sheet", sheet, "range", mc.GetStartAxis()+":"+mc.GetEndAxis(), "error", err)
+		}
+	}
+}
+
+// copyColumns copies the widths and styles of the columns. Columns sharing a width and
+// style are written as one range; the trailing range of unstyled columns is left out.
+// Why: The stored sheet dimension is often stale, so every column is read; that only
+// walks the sheet's column definitions.
+func (s *sheetStreamer) copyColumns(sw *excelize.StreamWriter, sheet string) {
+	type column struct {
+		width float64
+		style int
+	}
+	read := func(col int) column {
+		name, _ := excelize.ColumnNumberToName(col)
+		width, _ := s.p.f.GetColWidth(sheet, name)
+		style, _ := s.p.f.GetColStyle(sheet, name)
+		return column{width: width, style: style}
+	}
+	start, current := 1, read(1)
+	for col := 2; col <= excelize.MaxColumns+1; col++ {
+		next := column{}
+		if col <= excelize.MaxColumns {
+			if next = read(col); next == current {
+				continue
+			}
+		}
+		if col > excelize.MaxColumns && current.style == 0 {
+			break
+		}
+		if err := sw.SetColWidth(start, col-1, current.width); err != nil {
+			slog.Warn("failed to copy column width", "sheet", sheet, "error", err)
+		}
+		if current.style != 0 {
+			if err := sw.SetColStyle(start, col-1, s.style(current.style)); err != nil {
+				slog.Warn("failed to copy column style", "sheet", sheet, "error", err)
+			}
+		}
+		start, current = col, next
+	}
+}
+
+// progress counts one cell of sheet and reports progress.
+func (s *sheetStreamer) progress(sheet string) {
+	p := s.p
+	p.processed++
+	percent := progressPercent(p.processed, p.total)
+	if p.progressChan != nil {
+		p.progressChan <- percent
+	}
+	if p.statusChan != nil {
+		p.statusChan <- Status{
+			SheetName: sheet,
+			Processed: p.processed,
+			Total:     p.total,
+			Percent:   percent,
+			ETA:       estimateETA(time.Since(s.started), p.processed, p.total),
+		}
+	}
+}
+
+// convert converts the cell of job and returns its new value, or false to copy the
+// cell unchanged.
+func (s *sheetStreamer) convert(ctx context.Context, job Job) (interface{}, bool) {
+	p := s.p
+	if n, skip := p.oversized(job.Text); skip {
+		slog.Warn("skipping oversized cell", "sheet", job.SheetName, "cell", job.Axis, "length", n)
+		p.skipped = append(p.skipped, SkippedCell{SheetName: job.SheetName, Axis: job.Axis, Length: n})
+		return nil, false
+	}
+
+	start := time.Now()
+	if p.tracer != nil {
+		p.tracer.OnCellStart(ctx, job)
+	}
+	res := p.convertJob(job)
+	if p.tracer != nil {
+		p.tracer.OnCellEnd(ctx, job, time.Since(start))
+	}
+
+	if res.TruncatedFrom > 0 {
+		slog.Warn("truncated cell to Excel's limit", "sheet", job.SheetName, "cell", job.Axis, "length", res.TruncatedFrom)
+		p.truncated = append(p.truncated, TruncatedCell{SheetName: job.SheetName, Axis: job.Axis, Length: res.TruncatedFrom})
+	}
+	p.recordStats(res)
+	if p.changes != nil {
+		p.changes.record(res, p.jobEncoding(job))
+	}
+	if p.spell != nil {
+		p.spell.record(res, p.jobEncoding(job))
+	}
+	if res.Unchanged {
+		return nil, false
+	}
+
+	styleID, _ := p.f.GetCellStyle(job.SheetName, job.Axis)
+	if p.plainCells && !job.IsRich {
+		return excelize.Cell{StyleID: s.plainStyle(styleID, res), Value: runsText(res.NewRuns)}, true
+	}
+	return excelize.Cell{StyleID: s.style(styleID), Value: res.NewRuns}, true
+}
+
+// copyCell returns the value of an input cell as written by StreamWriter, keeping its
+// style, formula and type. text is the formatted value from the row iterator.
+func (s *sheetStreamer) copyCell(sheet, axis, text string) interface{} {
+	in := s.p.f
+	styleID, _ := in.GetCellStyle(sheet, axis)
+	formula, _ := in.GetCellFormula(sheet, axis)
+	if text == "" && styleID == 0 && formula == "" {
+		return nil
+	}
+	cell := excelize.Cell{StyleID: s.style(styleID), Formula: s.replacer.Replace(formula)}
+	raw, err := in.GetCellValue(sheet, axis, excelize.Options{RawCellValue: true})
+	if err != nil || raw == "" {
+		return cell
+	}
+	cellType, _ := in.GetCellType(sheet, axis)
+	switch {
+	case isTextCell(cellType):
+		if runs, err := in.GetCellRichText(sheet, axis); err == nil && hasAnyRunFont(runs) {
+			cell.Value = runs
+		} else {
+			cell.Value = raw
+		}
+	case cellType == excelize.CellTypeBool:
+		cell.Value = raw == "1"
+	default:
+		// Numbers and dates are stored as serial numbers; the style keeps their format
+		if n, err := strconv.ParseFloat(raw, 64); err == nil {
+			cell.Value = n
+		} else {
+			cell.Value = raw
+		}
+	}
+	return cell
+}
+
+// style returns the output style ID of an input style ID, copying the style on first use.
+func (s *sheetStreamer) style(id int) int {
+	if id == 0 {
+		return 0
+	}
+	if outID, ok := s.styles[id]; ok {
+		return outID
+	}
+	outID := 0
+	style, err := s.p.f.GetStyle(id)
+	if err == nil {
+		outID, err = s.out.NewStyle(style)
+	}
+	if err != nil {
+		slog.Warn("failed to copy style", "style", id, "error", err)
+	}
+	s.styles[id] = outID
+	return outID
+}
+
+// plainStyle returns the output style of a converted plain cell: its input style with
+// the converted font family, as batchWriter.writePlain does.
+func (s *sheetStreamer) plainStyle(styleID int, res Result) int {
+	family := runsFont(res.NewRuns)
+	if family == "" || family == runsFont(res.Job.RichText) {
+		return s.style(styleID)
+	}
+	key := plainStyle{styleID: styleID, family: family}
+	if outID, ok := s.plain[key]; ok {
+		return outID
+	}
+	style, err := s.p.f.GetStyle(styleID)
+	if err != nil {
+		slog.Warn("failed to read style", "style", styleID, "error", err)
+		return s.style(styleID)
+	}
+	font := excelize.Font{}
+	if style.Font != nil {
+		font = *style.Font
+	}
+	font.Family = family
+	style.Font = &font
+	outID, err := s.out.NewStyle(style)
+	if err != nil {
+		slog.Warn("failed to create style", "style", styleID, "error", err)
+		return s.style(styleID)
+	}
+	s.plain[key] = outID
+	return outID
+}
@@ -0,0 +1,146 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// writeLargeFileWorkbook creates a workbook exercising what large file mode copies: a
+// VNI-named sheet with text, numbers, a formula, a merge, a styled cell and a wide column.
+func writeLargeFileWorkbook(t *testing.T, path string) {
+	t.Helper()
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+	legacy := "Ba\u00F9o ca\u00F9o"
+	if _, err := f.NewSheet(legacy); err != nil {
+		t.Fatalf("failed to add sheet: %v", err)
+	}
+	style, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Family: "VNI-Times", Bold: true}, NumFmt: 4})
+	if err != nil {
+		t.Fatalf("failed to create style: %v", err)
+	}
+	steps := []func() error{
+		func() error { return f.SetCellValue(legacy, "A1", "Vi\u00D6t Nam") },
+		func() error { return f.SetCellStyle(legacy, "A1", "A1", style) },
+		func() error { return f.SetCellValue(legacy, "A2", 1234.5) },
+		func() error { return f.SetCellStyle(legacy, "A2", "A2", style) },
+		func() error { return f.SetCellFormula(legacy, "A3", "A2*2") },
+		func() error { return f.SetCellValue(legacy, "A4", true) },
+		func() error { return f.MergeCell(legacy, "B1", "C1") },
+		func() error { return f.SetColWidth(legacy, "B", "B", 30) },
+		func() error { return f.SetRowHeight(legacy, 2, 40) },
+		func() error { return f.SetCellFormula("Sheet1", "A1", "'"+legacy+"'!A2") },
+		func() error {
+			return f.SetSheetProps(legacy, &excelize.SheetPropsOptions{TabColorRGB: stringPtr("FF00FF00")})
+		},
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("failed to build workbook: %v", err)
+		}
+	}
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("failed to save %s: %v", path, err)
+	}
+}
+
+func stringPtr(s string) *string { return &s }
+
+func TestProcessor_LargeFileMode(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "large.xlsx")
+	writeLargeFileWorkbook(t, inputFile)
+
+	proc := NewProcessor(inputFile, "")
+	proc.SetLargeFileMode(true)
+	outputFile, err := proc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+
+	if got := proc.RenamedSheets(); len(got) != 1 || got[0].To != "Báo cáo" {
+		t.Errorf("RenamedSheets() = %+v, want the legacy sheet renamed", got)
+	}
+
+	f, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	if got, want := f.GetSheetList(), []string{"Sheet1", "Báo cáo"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("sheets = %q, want %q", got, want)
+	}
+	sheet := "Báo cáo"
+
+	values := []struct {
+		axis string
+		want string
+	}{
+		{axis: "A1", want: "Việt Nam"},
+		{axis: "A2", want: "1,234.50"},
+		{axis: "A4", want: "TRUE"},
+	}
+	for _, tt := range values {
+		t.Run(tt.axis, func(t *testing.T) {
+			if got, _ := f.GetCellValue(sheet, tt.axis); got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.axis, got, tt.want)
+			}
+		})
+	}
+
+	runs, err := f.GetCellRichText(sheet, "A1")
+	if err != nil || len(runs) != 1 || runs[0].Font == nil || runs[0].Font.Family != "Times New Roman" || !runs[0].Font.Bold {
+		t.Errorf("A1 runs = %+v (%v), want one bold Times New Roman run", runs, err)
+	}
+	if cellType, _ := f.GetCellType(sheet, "A2"); cellType == excelize.CellTypeInlineString || cellType == excelize.CellTypeSharedString {
+		t.Errorf("A2 type = %v, want a number", cellType)
+	}
+	if got, _ := f.GetCellFormula(sheet, "A3"); got != "A2*2" {
+		t.Errorf("A3 formula = %q, want A2*2", got)
+	}
+	if got, _ := f.GetCellFormula("Sheet1", "A1"); got != "'Báo cáo'!A2" {
+		t.Errorf("Sheet1!A1 formula = %q, want the renamed reference", got)
+	}
+	if merged, _ := f.GetMergeCells(sheet); len(merged) != 1 || merged[0].GetStartAxis() != "B1" || merged[0].GetEndAxis() != "C1" {
+		t.Errorf("merged cells = %v, want B1:C1", merged)
+	}
+	if width, _ := f.GetColWidth(sheet, "B"); width != 30 {
+		t.Errorf("column B width = %v, want 30", width)
+	}
+	if height, _ := f.GetRowHeight(sheet, 2); height != 40 {
+		t.Errorf("row 2 height = %v, want 40", height)
+	}
+	if props, _ := f.GetSheetProps(sheet); props.TabColorRGB == nil || *props.TabColorRGB != "FF00FF00" {
+		t.Errorf("tab color = %v, want FF00FF00", props.TabColorRGB)
+	}
+}
+
+func TestProcessor_LargeFileMode_Unsupported(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "large.xlsx")
+	writeLargeFileWorkbook(t, inputFile)
+
+	tests := []struct {
+		name  string
+		setup func(p *Processor)
+	}{
+		{name: "InPlace", setup: func(p *Processor) { p.SetInPlace(BackupFile) }},
+		{name: "Checkpoint", setup: func(p *Processor) { p.SetCheckpoint(true) }},
+		{name: "FontReport", setup: func(p *Processor) { p.SetFontReport(NewFontReport()) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc := NewProcessor(inputFile, "")
+			proc.SetLargeFileMode(true)
+			tt.setup(proc)
+			if _, err := proc.Run(context.Background()); !errors.Is(err, errLargeFileUnsupported) {
+				t.Errorf("Run error = %v, want errLargeFileUnsupported", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/richardlehane/mscfb"
+)
+
+// LegacyFormat names a spreadsheet format older than .xlsx (see DetectLegacyFormat).
+type LegacyFormat string
+
+// Formats recognized by DetectLegacyFormat.
+const (
+	LegacyFormatExcel4  LegacyFormat = "Excel 2.x-4.0"
+	LegacyFormatExcel95 LegacyFormat = "Excel 5.0/95"
+	LegacyFormatExcel97 LegacyFormat = "Excel 97-2003"
+	LegacyFormatLotus   LegacyFormat = "Lotus 1-2-3"
+)
+
+// oleMagic starts every OLE compound file, the container of Excel 5.0 to 2003 workbooks.
+var oleMagic = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// LegacyFormatError is returned when the input is an old spreadsheet format that cannot be
+// read directly; Format names it.
+type LegacyFormatError struct {
+	Path   string
+	Format LegacyFormat
+}
+
+func (e *LegacyFormatError) Error() string {
+	return fmt.Sprintf("%s is a %s file, which cannot be converted directly: open it in Excel or LibreOffice "+
+		"and save it as .xlsx, or install LibreOffice and set convertLegacyFormats in settings.json "+
+		"to convert it automatically", filepath.Base(e.Path), e.Format)
+}
+
+// DetectLegacyFormat reports the old spreadsheet format of the file at path from its
+// content, whatever its extension; "" means none was recognized.
+// Why: Archives hold decades-old workbooks, often renamed to .xlsx, and "not a zip file"
+// does not tell users what to do with them.
+func DetectLegacyFormat(path string) LegacyFormat {
+	f, err := os.Open(path) //nolint:gosec // path is the input chosen by the user
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	header := make([]byte, 8)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		return ""
+	}
+	if bytes.Equal(header, oleMagic) {
+		return oleWorkbookFormat(f)
+	}
+	return recordFormat(header)
+}
+
+// oleWorkbookFormat tells the Excel version of an OLE compound file by its workbook stream:
+// "Book" (BIFF5, Excel 5.0/95) or "Workbook" (BIFF8, Excel 97-2003).
+func oleWorkbookFormat(f *os.File) LegacyFormat {
+	doc, err := mscfb.New(f)
+	if err != nil {
+		return ""
+	}
+	format := LegacyFormat("")
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		switch entry.Name {
+		case "Workbook":
+			return LegacyFormatExcel97
+		case "Book":
+			format = LegacyFormatExcel95
+		}
+	}
+	return format
+}
+
+// recordFormat tells the formats stored as bare records by their first (BOF) record:
+// Excel 2.x-4.0 worksheets, and Lotus 1-2-3 .wk1/.wk3/.wk4/.123 files.
+func recordFormat(header []byte) LegacyFormat {
+	opcode := binary.LittleEndian.Uint16(header[0:2])
+	length := binary.LittleEndian.Uint16(header[2:4])
+	switch {
+	case (opcode == 0x0009 || opcode == 0x0209 || opcode == 0x0409) && length >= 4 && length <= 16:
+		return LegacyFormatExcel4
+	case opcode == 0x0000 && length == 2 && header[5] == 0x04:
+		// WKS/WK1: version 0x0404 to 0x0406
+		return LegacyFormatLotus
+	case opcode == 0x0000 && length == 0x1A && header[5] == 0x10:
+		// WK3, WK4 and 1-2-3 97 and later: version 0x1000 to 0x1005
+		return LegacyFormatLotus
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+)
+
+// writeCompoundFile writes a minimal OLE compound file holding one empty stream named stream.
+func writeCompoundFile(t *testing.T, path, stream string) {
+	t.Helper()
+	const sector = 512
+	data := make([]byte, 3*sector)
+	le := binary.LittleEndian
+	header := data[:sector]
+	copy(header, oleMagic)
+	le.PutUint16(header[0x18:], 0x3E) // minor version
+	le.PutUint16(header[0x1A:], 3)    // major version: 512-byte sectors
+	le.PutUint16(header[0x1C:], 0xFFFE)
+	le.PutUint16(header[0x1E:], 9) // sector shift
+	le.PutUint16(header[0x20:], 6) // mini sector shift
+	le.PutUint32(header[0x2C:], 1) // FAT sectors
+	le.PutUint32(header[0x30:], 1) // first directory sector
+	le.PutUint32(header[0x38:], 4096)
+	le.PutUint32(header[0x3C:], 0xFFFFFFFE) // no mini FAT
+	le.PutUint32(header[0x44:], 0xFFFFFFFE) // no DIFAT sectors
+	for i := 0x4C; i < sector; i += 4 {
+		le.PutUint32(header[i:], 0xFFFFFFFF)
+	}
+	le.PutUint32(header[0x4C:], 0) // the FAT is sector 0
+
+	fat := data[sector : 2*sector]
+	for i := 0; i < sector; i += 4 {
+		le.PutUint32(fat[i:], 0xFFFFFFFF)
+	}
+	le.PutUint32(fat[0:], 0xFFFFFFFD) // FAT sector
+	le.PutUint32(fat[4:], 0xFFFFFFFE) // directory: one sector
+
+	dir := data[2*sector:]
+	entry := func(i int, name string, typ byte, child uint32) {
+		e := dir[i*128 : (i+1)*128]
+		units := utf16.Encode([]rune(name))
+		for j, u := range units {
+			le.PutUint16(e[j*2:], u)
+		}
+		le.PutUint16(e[0x40:], uint16(len(units)*2+2))
+		e[0x42], e[0x43] = typ, 1
+		le.PutUint32(e[0x44:], 0xFFFFFFFF)
+		le.PutUint32(e[0x48:], 0xFFFFFFFF)
+		le.PutUint32(e[0x4C:], child)
+		le.PutUint32(e[0x74:], 0xFFFFFFFE)
+	}
+	entry(0, "Root Entry", 5, 1)
+	entry(1, stream, 2, 0xFFFFFFFF)
+	for i := 2; i < 4; i++ {
+		e := dir[i*128 : (i+1)*128]
+		le.PutUint32(e[0x44:], 0xFFFFFFFF)
+		le.PutUint32(e[0x48:], 0xFFFFFFFF)
+		le.PutUint32(e[0x4C:], 0xFFFFFFFF)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestDetectLegacyFormat(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+	excel95 := filepath.Join(dir, "budget95.xls")
+	writeCompoundFile(t, excel95, "Book")
+	excel97 := filepath.Join(dir, "renamed.xlsx")
+	writeCompoundFile(t, excel97, "Workbook")
+	word := filepath.Join(dir, "letter.doc")
+	writeCompoundFile(t, word, "WordDocument")
+
+	tests := []struct {
+		name string
+		path string
+		want LegacyFormat
+	}{
+		{name: "Excel 5.0/95", path: excel95, want: LegacyFormatExcel95},
+		{name: "Excel 97 renamed", path: excel97, want: LegacyFormatExcel97},
+		{name: "Other compound file", path: word, want: ""},
+		{name: "Excel 4.0", path: write("sheet.xls", []byte{0x09, 0x04, 0x06, 0x00, 0x00, 0x00, 0x10, 0x00}), want: LegacyFormatExcel4},
+		{name: "Lotus WK1", path: write("plan.wk1", []byte{0x00, 0x00, 0x02, 0x00, 0x06, 0x04, 0x06, 0x00}), want: LegacyFormatLotus},
+		{name: "Lotus WK4", path: write("plan.wk4", []byte{0x00, 0x00, 0x1A, 0x00, 0x02, 0x10, 0x04, 0x00}), want: LegacyFormatLotus},
+		{name: "Zip", path: write("book.xlsx", []byte("PK\x03\x04\x14\x00\x06\x00")), want: ""},
+		{name: "Too short", path: write("empty.xls", []byte{0x09}), want: ""},
+		{name: "Missing", path: filepath.Join(dir, "missing.xls"), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLegacyFormat(tt.path); got != tt.want {
+				t.Errorf("DetectLegacyFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	_, err := NewProcessor(excel95, "").Run(context.Background())
+	var legacyErr *LegacyFormatError
+	if !errors.As(err, &legacyErr) || legacyErr.Format != LegacyFormatExcel95 {
+		t.Errorf("Run() error = %v, want a LegacyFormatError for Excel 5.0/95", err)
+	}
+}
@@ -0,0 +1,188 @@
+package engine
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"path"
+	"sort"
+	"sync"
+	"unicode/utf8"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+//go:generate go run ../../scripts/train_ngram -corpus ../../scripts/train_ngram -out ngram_models
+
+// NGramOrder is the character n-gram length of the shipped models.
+const NGramOrder = 3
+
+// ngramModels holds the models trained by scripts/train_ngram, one JSON file per class.
+//
+//go:embed ngram_models/*.json
+var ngramModels embed.FS
+
+// NGramModel counts the character n-grams of one class of text. The class is a legacy
+// encoding, or EncodingUnknown for text that must be left alone (Unicode, other languages).
+type NGramModel struct {
+	Encoding converter.EncodingType `json:"encoding"`
+	N        int                    `json:"n"`
+	Total    int                    `json:"total"`
+	Counts   map[string]int         `json:"counts"`
+}
+
+// TrainNGramModels builds one model per sample encoding from the samples' legacy text,
+// sorted by encoding.
+// Why: Models can be retrained from datasets exported with the dataset subcommand.
+func TrainNGramModels(samples []DatasetSample, n int) []*NGramModel {
+	byEncoding := make(map[converter.EncodingType]*NGramModel)
+	for _, s := range samples {
+		m, ok := byEncoding[s.Encoding]
+		if !ok {
+			m = &NGramModel{Encoding: s.Encoding, N: n, Counts: make(map[string]int)}
+			byEncoding[s.Encoding] = m
+		}
+		eachNGram(s.Legacy, n, func(gram string) {
+			m.Counts[gram]++
+			m.Total++
+		})
+	}
+	models := make([]*NGramModel, 0, len(byEncoding))
+	for _, m := range byEncoding {
+		models = append(models, m)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Encoding < models[j].Encoding })
+	return models
+}
+
+// eachNGram calls visit for every n-rune window of text, padded with a space at both
+// ends so word boundaries count.
+func eachNGram(text string, n int, visit func(string)) {
+	padded := " " + text + " "
+	// starts holds the byte offsets of the last n runes seen
+	starts := make([]int, 0, n)
+	for i := range padded {
+		if len(starts) == n {
+			starts = starts[1:]
+		}
+		starts = append(starts, i)
+		if len(starts) == n {
+			_, size := utf8.DecodeRuneInString(padded[i:])
+			visit(padded[starts[0] : i+size])
+		}
+	}
+}
+
+// LoadNGramModels reads every *.json model in dir.
+func LoadNGramModels(fsys fs.FS, dir string) ([]*NGramModel, error) {
+	names, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list n-gram models: %w", err)
+	}
+	models := make([]*NGramModel, 0, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read n-gram model: %w", err)
+		}
+		var m NGramModel
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("failed to parse n-gram model %s: %w", name, err)
+		}
+		models = append(models, &m)
+	}
+	return models, nil
+}
+
+// NGramDetector classifies text by which model gives its character n-grams the highest
+// average log-probability.
+// Why: Fixed marker characters overlap accented Western text (e.g. "brûlée" contains a VNI
+// marker); n-gram statistics weigh every character in context instead.
+type NGramDetector struct {
+	models []*NGramModel
+	// vocab is the number of distinct n-grams across all models (add-one smoothing)
+	vocab int
+}
+
+// NewNGramDetector creates a detector from models of the same order. At least two classes
+// are needed to choose between.
+func NewNGramDetector(models []*NGramModel) (*NGramDetector, error) {
+	if len(models) < 2 {
+		return nil, fmt.Errorf("n-gram detector needs at least 2 models, got %d", len(models))
+	}
+	grams := make(map[string]struct{})
+	for _, m := range models {
+		if m.N != models[0].N || m.N < 1 {
+			return nil, fmt.Errorf("n-gram model %s has order %d, want %d", m.Encoding, m.N, models[0].N)
+		}
+		for g := range m.Counts {
+			grams[g] = struct{}{}
+		}
+	}
+	return &NGramDetector{models: models, vocab: len(grams) + 1}, nil
+}
+
+// defaultNGramDetector loads the embedded models once.
+var defaultNGramDetector = sync.OnceValues(func() (*NGramDetector, error) {
+	models, err := LoadNGramModels(ngramModels, "ngram_models")
+	if err != nil {
+		return nil, err
+	}
+	return NewNGramDetector(models)
+})
+
+// Detect checks the font name, English text and the VNU pattern like the rule-based detector (all are
+// structural, not statistical), then scores the text against every model. Evidence is
+// the margin between the best and second-best average log-probability.
+func (d *NGramDetector) Detect(fontName, text string) Detection {
+	if det, ok := detectByFont(fontName); ok {
+		return det
+	}
+	if det, ok := detectEnglish(text); ok {
+		return det
+	}
+	if converter.HasVNUPattern(text) {
+		return Detection{Encoding: converter.EncodingVNU, Rule: RuleVNUPattern}
+	}
+	// Auto-detectable legacy text always contains non-ASCII characters
+	if isASCII(text) {
+		return Detection{Encoding: converter.EncodingUnknown, Rule: RuleNone}
+	}
+
+	best, second := math.Inf(-1), math.Inf(-1)
+	encoding := converter.EncodingUnknown
+	for _, m := range d.models {
+		score := d.score(m, text)
+		if score > best {
+			best, second, encoding = score, best, m.Encoding
+		} else if score > second {
+			second = score
+		}
+	}
+	return Detection{Encoding: encoding, Rule: RuleNGram, Evidence: fmt.Sprintf("margin=%.2f", best-second)}
+}
+
+// score returns the average log-probability of text's n-grams under m.
+func (d *NGramDetector) score(m *NGramModel, text string) float64 {
+	sum, count := 0.0, 0
+	denominator := math.Log(float64(m.Total + d.vocab))
+	eachNGram(text, m.N, func(gram string) {
+		sum += math.Log(float64(m.Counts[gram]+1)) - denominator
+		count++
+	})
+	if count == 0 {
+		return math.Inf(-1)
+	}
+	return sum / float64(count)
+}
+
+func isASCII(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if text[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
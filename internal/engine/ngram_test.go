@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+func TestEachNGram(t *testing.T) {
+	var got []string
+	eachNGram("Vi\u00D6t", 3, func(g string) { got = append(got, g) })
+	want := []string{" Vi", "Vi\u00D6", "i\u00D6t", "\u00D6t "}
+	if len(got) != len(want) {
+		t.Fatalf("eachNGram() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("gram %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNGramDetector_Detect(t *testing.T) {
+	d, err := NewDetector(DetectorNGram)
+	if err != nil {
+		t.Fatalf("NewDetector failed: %v", err)
+	}
+	vni, tcvn3 := converter.NewVNIEncoder(), converter.NewTCVN3Encoder()
+
+	tests := []struct {
+		name     string
+		font     string
+		text     string
+		encoding converter.EncodingType
+		rule     DetectionRule
+	}{
+		{name: "Font prefix wins", font: "VNI-Times", text: "abc", encoding: converter.EncodingVNI, rule: RuleFontPrefix},
+		{name: "ASCII is left alone", text: "Hello", encoding: converter.EncodingUnknown, rule: RuleNone},
+		{name: "VNI text", text: vni.FromUnicode("Hóa đơn bán hàng"), encoding: converter.EncodingVNI, rule: RuleNGram},
+		{name: "TCVN3 text", text: tcvn3.FromUnicode("Kế toán trưởng"), encoding: converter.EncodingTCVN3, rule: RuleNGram},
+		{name: "Unicode Vietnamese", text: "Công ty cổ phần", encoding: converter.EncodingUnknown, rule: RuleNGram},
+		// The rule-based detector mistakes these for VNI
+		{name: "French", text: "Crème brûlée", encoding: converter.EncodingUnknown, rule: RuleNGram},
+		{name: "German", text: "Müller GmbH", encoding: converter.EncodingUnknown, rule: RuleNGram},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := d.Detect(tt.font, tt.text)
+			if got.Encoding != tt.encoding || got.Rule != tt.rule {
+				t.Errorf("Detect(%q) = %s (%s), want %s (%s)", tt.text, got.Encoding, got.Rule, tt.encoding, tt.rule)
+			}
+		})
+	}
+}
+
+func TestNewNGramDetector_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		models []*NGramModel
+	}{
+		{name: "Single class", models: []*NGramModel{{Encoding: converter.EncodingVNI, N: 3}}},
+		{name: "Mixed orders", models: []*NGramModel{{Encoding: converter.EncodingVNI, N: 3}, {Encoding: converter.EncodingUnknown, N: 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewNGramDetector(tt.models); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestTrainNGramModels(t *testing.T) {
+	models := TrainNGramModels([]DatasetSample{
+		{Legacy: "ab", Encoding: converter.EncodingVNI},
+		{Legacy: "ab", Encoding: converter.EncodingVNI},
+		{Legacy: "cd", Encoding: converter.EncodingUnknown},
+	}, 2)
+	if len(models) != 2 || models[0].Encoding != converter.EncodingUnknown || models[1].Encoding != converter.EncodingVNI {
+		t.Fatalf("TrainNGramModels() = %+v, want UNKNOWN and VNI models", models)
+	}
+	if vni := models[1]; vni.Total != 6 || vni.Counts["ab"] != 2 || vni.Counts[" a"] != 2 {
+		t.Errorf("VNI model = %+v, want 6 windows with \"ab\" twice", vni)
+	}
+}
+
+func TestEvaluateDetector(t *testing.T) {
+	samples := []DatasetSample{
+		{Legacy: "Vi\u00D6t", Encoding: converter.EncodingVNI},
+		{Legacy: "Crème brûlée", Encoding: converter.EncodingUnknown},
+		{Legacy: "Müller", Encoding: converter.EncodingUnknown},
+		{Legacy: "Hello", Encoding: converter.EncodingUnknown},
+	}
+
+	got := EvaluateDetector(DetectorRules, RuleDetector{}, samples)
+	if got.Correct != 2 || got.Total != 4 || got.Accuracy() != 0.5 {
+		t.Errorf("EvaluateDetector() = %+v, want 2 of 4 correct", got)
+	}
+	want := Misclassification{Label: converter.EncodingUnknown, Detected: converter.EncodingVNI, Count: 2}
+	if len(got.Errors) != 1 || got.Errors[0] != want {
+		t.Errorf("Errors = %+v, want [%+v]", got.Errors, want)
+	}
+}
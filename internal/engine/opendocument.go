@@ -0,0 +1,359 @@
+package engine
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Parts of an OpenDocument spreadsheet: the cells and automatic styles, and the common styles.
+const (
+	odsContentPart = "content.xml"
+	odsStylesPart  = "styles.xml"
+)
+
+// IsOpenDocument reports whether path is an OpenDocument spreadsheet Run converts as one.
+func IsOpenDocument(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".ods")
+}
+
+// runOpenDocument converts the OpenDocument spreadsheet at InputPath and saves it as a
+// spreadsheet of the same format under a new output name. The text of every cell is
+// converted from the encoding of its font (that of its text span, else of its cell style)
+// or its content, and the legacy fonts of converted text are replaced in the font
+// declarations with the font the policy maps to. ConvertedRuns counts converted cells.
+// Why: Offices mixing Microsoft Office and LibreOffice had to round-trip .ods files
+// through .xlsx, losing LibreOffice-only formatting on the way.
+func (p *Processor) runOpenDocument(ctx context.Context) (string, error) {
+	return p.runPackage(ctx, "spreadsheet", func(r *zip.Reader) (packageConverter, error) {
+		content, styles, err := readODSParts(r)
+		if err != nil {
+			return packageConverter{}, err
+		}
+		fonts, err := readODSStyles(content, styles)
+		if err != nil {
+			return packageConverter{}, err
+		}
+		// Converted up front, as the fonts to replace in styles.xml are only known afterwards
+		converted, cells, families, err := p.convertODSContent(ctx, content, fonts)
+		if err != nil {
+			return packageConverter{}, err
+		}
+		return packageConverter{
+			isTextPart: func(name string) bool { return name == odsContentPart || name == odsStylesPart },
+			convert: func(name string, data []byte) ([]byte, int, error) {
+				if name == odsContentPart {
+					return converted, cells, nil
+				}
+				out, err := replaceODSFontFaces(data, families)
+				return out, 0, err
+			},
+		}, nil
+	})
+}
+
+func readODSParts(r *zip.Reader) (content, styles []byte, err error) {
+	for _, part := range r.File {
+		switch part.Name {
+		case odsContentPart:
+			content, err = readZipFile(part)
+		case odsStylesPart:
+			styles, err = readZipFile(part)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if content == nil {
+		return nil, nil, fmt.Errorf("not an OpenDocument spreadsheet: %s is missing", odsContentPart)
+	}
+	return content, styles, nil
+}
+
+// odsStyles resolves the font family of a style, following its parents to the default
+// style of its family. Styles are keyed by family and name, as names are only unique
+// within a family.
+type odsStyles struct {
+	styles   map[string]odsStyle
+	defaults map[string]string
+	faces    map[string]string // font face name to font family
+}
+
+type odsStyle struct {
+	parent string
+	font   string
+}
+
+type xmlODSStyle struct {
+	Name   string `xml:"name,attr"`
+	Family string `xml:"family,attr"`
+	Parent string `xml:"parent-style-name,attr"`
+	Text   struct {
+		FontName   string `xml:"font-name,attr"`
+		FontFamily string `xml:"font-family,attr"`
+	} `xml:"text-properties"`
+}
+
+// font returns the font face name, or the family, the style sets.
+func (s xmlODSStyle) font() string {
+	if s.Text.FontName != "" {
+		return s.Text.FontName
+	}
+	return unquoteFontFamily(s.Text.FontFamily)
+}
+
+type xmlODSStylesPart struct {
+	Faces []struct {
+		Name   string `xml:"name,attr"`
+		Family string `xml:"font-family,attr"`
+	} `xml:"font-face-decls>font-face"`
+	Defaults  []xmlODSStyle `xml:"styles>default-style"`
+	Styles    []xmlODSStyle `xml:"styles>style"`
+	Automatic []xmlODSStyle `xml:"automatic-styles>style"`
+}
+
+// readODSStyles reads the font faces and styles of the content and styles parts.
+func readODSStyles(parts ...[]byte) (*odsStyles, error) {
+	s := &odsStyles{styles: make(map[string]odsStyle), defaults: make(map[string]string), faces: make(map[string]string)}
+	for _, data := range parts {
+		if data == nil {
+			continue
+		}
+		var parsed xmlODSStylesPart
+		if err := xml.Unmarshal(data, &parsed); err != nil {
+			return nil, fmt.Errorf("failed to read spreadsheet styles: %w", err)
+		}
+		for _, face := range parsed.Faces {
+			s.faces[face.Name] = unquoteFontFamily(face.Family)
+		}
+		for _, style := range parsed.Defaults {
+			s.defaults[style.Family] = style.font()
+		}
+		for _, style := range append(parsed.Styles, parsed.Automatic...) {
+			s.styles[style.Family+"/"+style.Name] = odsStyle{parent: style.Parent, font: style.font()}
+		}
+	}
+	return s, nil
+}
+
+// font returns the font family of the style name of family, or "" when none is set.
+func (s *odsStyles) font(family, name string) string {
+	// Bounded, so a parent cycle in a damaged file cannot hang the conversion
+	for range len(s.styles) {
+		style, ok := s.styles[family+"/"+name]
+		if !ok {
+			break
+		}
+		if style.font != "" {
+			return s.family(style.font)
+		}
+		name = style.parent
+	}
+	return s.family(s.defaults[family])
+}
+
+func (s *odsStyles) family(font string) string {
+	if family, ok := s.faces[font]; ok {
+		return family
+	}
+	return font
+}
+
+// unquoteFontFamily strips the quotes of a family such as "'Times New Roman'".
+func unquoteFontFamily(family string) string {
+	return strings.Trim(family, `'"`)
+}
+
+// odsSegment is one text node of a cell.
+type odsSegment struct {
+	start, end int
+	font       string
+	text       string
+}
+
+// convertODSContent converts the cells of the content part and returns it with the number
+// of cells converted and the font families to replace (legacy family to converted one).
+func (p *Processor) convertODSContent(ctx context.Context, data []byte,
+	fonts *odsStyles) ([]byte, int, map[string]string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	sc := &odsScanner{p: p, fonts: fonts, data: data, families: make(map[string]string)}
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to convert %s: %w", odsContentPart, err)
+		}
+		end := int(dec.InputOffset())
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if isODSCell(t.Name) {
+				if err := ctx.Err(); err != nil {
+					return nil, 0, nil, fmt.Errorf("conversion cancelled: %w", err)
+				}
+			}
+			sc.startElement(t, start, end)
+		case xml.CharData:
+			if sc.inCell && sc.inPara > 0 {
+				sc.segments = append(sc.segments, odsSegment{
+					start: start, end: end, font: segmentFont(fonts, sc.spans, sc.cellFont), text: string(t),
+				})
+			}
+		case xml.EndElement:
+			sc.endElement(t)
+		}
+	}
+	out, err := replaceODSFontFaces(applyDocumentEdits(data, sc.edits), sc.families)
+	return out, sc.cells, sc.families, err
+}
+
+// isODSCell reports whether name is a table cell, covered by a merged cell or not.
+func isODSCell(name xml.Name) bool {
+	return name.Space == "table" && (name.Local == "table-cell" || name.Local == "covered-table-cell")
+}
+
+// odsScanner is the state of convertODSContent between tokens.
+type odsScanner struct {
+	p        *Processor
+	fonts    *odsStyles
+	data     []byte
+	edits    []documentEdit
+	spans    []string // style names of the open text spans
+	cellFont string
+	inCell   bool
+	inPara   int
+	segments []odsSegment
+	cells    int
+	families map[string]string
+}
+
+// startElement records an element that starts at data[start:end].
+func (sc *odsScanner) startElement(t xml.StartElement, start, end int) {
+	selfClosing := bytes.HasSuffix(sc.data[start:end], []byte("/>"))
+	switch {
+	case isODSCell(t.Name):
+		style := attrValue(t.Attr, "style-name")
+		if style == "" {
+			style = "Default"
+		}
+		sc.cellFont = sc.fonts.font("table-cell", style)
+		sc.inCell, sc.segments = !selfClosing, nil
+	case !sc.inCell || t.Name.Space != "text" || selfClosing:
+	case t.Name.Local == "p":
+		sc.inPara++
+	case t.Name.Local == "span":
+		sc.spans = append(sc.spans, attrValue(t.Attr, "style-name"))
+	}
+}
+
+// endElement records the end of an element, converting a cell once it closes.
+func (sc *odsScanner) endElement(t xml.EndElement) {
+	switch {
+	case isODSCell(t.Name):
+		if cellEdits := sc.p.convertODSCell(sc.segments, sc.families); cellEdits != nil {
+			sc.edits = append(sc.edits, cellEdits...)
+			sc.cells++
+		}
+		sc.inCell, sc.inPara, sc.spans, sc.segments = false, 0, nil, nil
+	case !sc.inCell || t.Name.Space != "text":
+	case t.Name.Local == "p":
+		sc.inPara--
+	case t.Name.Local == "span" && len(sc.spans) > 0:
+		sc.spans = sc.spans[:len(sc.spans)-1]
+	}
+}
+
+// segmentFont returns the font of a text node: that of its innermost span setting one,
+// else that of its cell.
+func segmentFont(fonts *odsStyles, spans []string, cellFont string) string {
+	for i := len(spans) - 1; i >= 0; i-- {
+		if font := fonts.font("text", spans[i]); font != "" {
+			return font
+		}
+	}
+	return cellFont
+}
+
+// convertODSCell returns the edits converting the text nodes of a cell, detected per
+// font, or nil when the cell is left unchanged. The families of converted legacy fonts
+// are added to families.
+func (p *Processor) convertODSCell(segments []odsSegment, families map[string]string) []documentEdit {
+	var edits []documentEdit
+	for i := 0; i < len(segments); {
+		// Detect on the consecutive text nodes sharing a font, like the runs of a rich text cell
+		j := i
+		var text strings.Builder
+		for ; j < len(segments) && segments[j].font == segments[i].font; j++ {
+			text.WriteString(segments[j].text)
+		}
+		font := segments[i].font
+		enc := p.preserver.Detect(font, text.String()).Encoding
+		for _, seg := range segments[i:j] {
+			converted, family := p.preserver.ConvertAs(enc, font, seg.text)
+			if converted == seg.text {
+				continue
+			}
+			var escaped strings.Builder
+			_ = xml.EscapeText(&escaped, []byte(converted))
+			edits = append(edits, documentEdit{start: seg.start, end: seg.end, text: escaped.String()})
+			// Only legacy fonts are replaced: another font may also hold text left unconverted
+			if _, legacy := detectByFont(font); legacy && family != "" && family != font {
+				families[font] = family
+			}
+		}
+		i = j
+	}
+	return edits
+}
+
+// replaceODSFontFaces returns data with the family of the font faces declaring a font of
+// families replaced by the converted one.
+func replaceODSFontFaces(data []byte, families map[string]string) ([]byte, error) {
+	if len(families) == 0 {
+		return data, nil
+	}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var edits []documentEdit
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		t, ok := tok.(xml.StartElement)
+		if !ok || t.Name.Space != "style" || t.Name.Local != "font-face" {
+			continue
+		}
+		family, ok := families[unquoteFontFamily(attrValue(t.Attr, "font-family"))]
+		if !ok {
+			continue
+		}
+		end := int(dec.InputOffset())
+		var tag strings.Builder
+		tag.WriteString("<style:font-face")
+		for _, attr := range t.Attr {
+			if attr.Name.Space == "svg" && attr.Name.Local == "font-family" {
+				attr.Value = "'" + family + "'"
+			}
+			writeAttr(&tag, attr)
+		}
+		if bytes.HasSuffix(data[start:end], []byte("/>")) {
+			tag.WriteString("/>")
+		} else {
+			tag.WriteString(">")
+		}
+		edits = append(edits, documentEdit{start: start, end: end, text: tag.String()})
+	}
+	return applyDocumentEdits(data, edits), nil
+}
@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunOpenDocument(t *testing.T) {
+	const ns = `xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" ` +
+		`xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" ` +
+		`xmlns:svg="urn:oasis:names:tc:opendocument:xmlns:svg-compatible:1.0"`
+	faces := `<office:font-face-decls><style:font-face style:name="VNI-Times" svg:font-family="'VNI-Times'" style:font-pitch="variable"/>` +
+		`<style:font-face style:name=".VnTime" svg:font-family=".VnTime"/><style:font-face style:name="Liberation Sans" svg:font-family="'Liberation Sans'"/></office:font-face-decls>`
+	styles := `<office:document-styles ` + ns + `>` + faces +
+		`<office:styles><style:default-style style:family="table-cell"><style:text-properties style:font-name="Liberation Sans"/></style:default-style>` +
+		`<style:style style:name="Default" style:family="table-cell"/></office:styles></office:document-styles>`
+	content := `<office:document-content ` + ns + `>` + faces +
+		`<office:automatic-styles><style:style style:name="ce1" style:family="table-cell" style:parent-style-name="Default"><style:text-properties style:font-name="VNI-Times"/></style:style>` +
+		`<style:style style:name="T1" style:family="text"><style:text-properties style:font-name=".VnTime"/></style:style></office:automatic-styles>` +
+		`<office:body><office:spreadsheet><table:table table:name="Sheet1"><table:table-row>` +
+		`<table:table-cell table:style-name="ce1" office:value-type="string"><text:p>` + "Vie\u00e2\u00eft Nam" + `</text:p></table:table-cell>` +
+		`<table:table-cell office:value-type="string"><text:p>Total: <text:span text:style-name="T1">` + "C\u00ABng ty" + `</text:span></text:p></table:table-cell>` +
+		`<table:table-cell office:value-type="string"><text:p>Quarterly report</text:p></table:table-cell>` +
+		`<table:table-cell office:value-type="float" office:value="12"><text:p>12</text:p></table:table-cell>` +
+		`</table:table-row></table:table></office:spreadsheet></office:body></office:document-content>`
+
+	input := filepath.Join(t.TempDir(), "book.ods")
+	writeDocument(t, input, map[string]string{
+		"mimetype":     "application/vnd.oasis.opendocument.spreadsheet",
+		odsContentPart: content,
+		odsStylesPart:  styles,
+	})
+	p := NewProcessor(input, "")
+	output, err := p.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if filepath.Ext(output) != ".ods" {
+		t.Errorf("output = %s, want an .ods file", output)
+	}
+	if p.ConvertedRuns() != 2 {
+		t.Errorf("ConvertedRuns() = %d, want 2 cells", p.ConvertedRuns())
+	}
+
+	gotContent := string(readZipPart(t, output, odsContentPart))
+	gotStyles := string(readZipPart(t, output, odsStylesPart))
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "Cell style font", got: gotContent, want: `<text:p>Việt Nam</text:p>`},
+		{name: "Span font", got: gotContent, want: `<text:p>Total: <text:span text:style-name="T1">Công ty</text:span></text:p>`},
+		{name: "English kept", got: gotContent, want: `<text:p>Quarterly report</text:p>`},
+		{name: "Content font face", got: gotContent, want: `<style:font-face style:name="VNI-Times" svg:font-family="'Times New Roman'" style:font-pitch="variable"/>`},
+		{name: "TCVN3 font face", got: gotContent, want: `<style:font-face style:name=".VnTime" svg:font-family="'Times New Roman'"/>`},
+		{name: "Styles font face", got: gotStyles, want: `<style:font-face style:name="VNI-Times" svg:font-family="'Times New Roman'" style:font-pitch="variable"/>`},
+		{name: "Other font face kept", got: gotStyles, want: `<style:font-face style:name="Liberation Sans" svg:font-family="'Liberation Sans'"/>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(tt.got, tt.want) {
+				t.Errorf("part = %s, want it to contain %s", tt.got, tt.want)
+			}
+		})
+	}
+}
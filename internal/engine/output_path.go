@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxOutputSuffix bounds the numeric suffixes tried for a free output name.
+const maxOutputSuffix = 1000
+
+// reserveOutputPath creates an empty file at path, or at "name_2.ext", "name_3.ext", ...
+// if it already exists, and returns the path it reserved.
+// Why: Two runs within the same second get the same timestamped name; SaveAs would
+// silently overwrite the first output. O_EXCL makes the check race-free for parallel batches.
+func reserveOutputPath(path string) (string, error) {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	return reserveFreePath(path, func(n int) string { return fmt.Sprintf("%s_%d%s", base, n, ext) })
+}
+
+// reserveFreePath creates an empty file at path, or at the first free alternative(n) for
+// n = 2, 3, ..., and returns the path it reserved.
+func reserveFreePath(path string, alternative func(n int) string) (string, error) {
+	candidate := path
+	for n := 2; n <= maxOutputSuffix+1; n++ {
+		// 0666 minus umask matches what SaveAs would create; the output is meant to be shared
+		// The path is derived from our own output name
+		f, err := os.OpenFile(candidate, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0666) //nolint:gosec
+		if err == nil {
+			if err := f.Close(); err != nil {
+				return "", fmt.Errorf("failed to reserve output file: %w", err)
+			}
+			return candidate, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("failed to reserve output file: %w", err)
+		}
+		candidate = alternative(n)
+	}
+	return "", fmt.Errorf("failed to reserve output file: %d names after %s are taken",
+		maxOutputSuffix, filepath.Base(path))
+}
+
+// checkOutputWritable fails early with a targeted message if dir cannot be written.
+// Why: Otherwise a missing write permission on a shared folder only shows up as a
+// failed SaveAs at the end of a long run.
+func checkOutputWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".vniconverter-write-test-*")
+	if err != nil {
+		switch {
+		case errors.Is(err, fs.ErrPermission):
+			return fmt.Errorf("no permission to write to %s; choose another output folder or ask for write access: %w", dir, err)
+		case errors.Is(err, fs.ErrNotExist):
+			return fmt.Errorf("output folder %s does not exist: %w", dir, err)
+		default:
+			return fmt.Errorf("cannot write to output folder %s: %w", dir, err)
+		}
+	}
+	name := f.Name()
+	_ = f.Close()
+	if err := os.Remove(name); err != nil {
+		return fmt.Errorf("cannot clean up in output folder %s: %w", dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReserveOutputPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		want     string
+	}{
+		{name: "Free name", existing: nil, want: "book_output.xlsx"},
+		{name: "Taken once", existing: []string{"book_output.xlsx"}, want: "book_output_2.xlsx"},
+		{name: "Taken twice", existing: []string{"book_output.xlsx", "book_output_2.xlsx"}, want: "book_output_3.xlsx"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.existing {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("previous"), 0600); err != nil {
+					t.Fatalf("failed to create %s: %v", name, err)
+				}
+			}
+			got, err := reserveOutputPath(filepath.Join(dir, "book_output.xlsx"))
+			if err != nil {
+				t.Fatalf("reserveOutputPath failed: %v", err)
+			}
+			if want := filepath.Join(dir, tt.want); got != want {
+				t.Errorf("reserveOutputPath() = %q, want %q", got, want)
+			}
+			if _, err := os.Stat(got); err != nil {
+				t.Errorf("reserved file missing: %v", err)
+			}
+			for _, name := range tt.existing {
+				data, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil || string(data) != "previous" {
+					t.Errorf("existing file %s was modified", name)
+				}
+			}
+		})
+	}
+}
+
+func TestReserveOutputPath_MissingDir(t *testing.T) {
+	if _, err := reserveOutputPath(filepath.Join(t.TempDir(), "missing", "book.xlsx")); err == nil {
+		t.Error("expected error for a missing directory")
+	}
+}
+
+func TestCheckOutputWritable(t *testing.T) {
+	root := t.TempDir()
+	readOnly := filepath.Join(root, "readonly")
+	if err := os.Mkdir(readOnly, 0500); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr string
+		skip    bool
+	}{
+		{name: "Writable", dir: root},
+		{name: "Missing", dir: filepath.Join(root, "missing"), wantErr: "does not exist"},
+		// Permission bits do not apply to root or on Windows
+		{name: "Read-only", dir: readOnly, wantErr: "no permission", skip: os.Geteuid() <= 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.skip {
+				t.Skip("permission bits are not enforced for this user")
+			}
+			err := checkOutputWritable(tt.dir)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkOutputWritable() = %v", err)
+				}
+				if entries, _ := os.ReadDir(tt.dir); len(entries) != 1 { // only "readonly"
+					t.Errorf("probe file left behind: %v", entries)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkOutputWritable() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package engine
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+// Formats of a pasted range (see ConvertPastedRange).
+const (
+	PastedTSV  = "tsv"
+	PastedHTML = "html"
+)
+
+// PastedRange is a range copied from a spreadsheet, converted by ConvertPastedRange.
+type PastedRange struct {
+	// Content is the converted range, in the format it was pasted in
+	Content string `json:"content"`
+	// Format is PastedTSV or PastedHTML
+	Format string `json:"format"`
+	// Encoding is the source encoding detected from the range (EncodingUnknown when it held
+	// no legacy text); cells in a legacy font are detected from their font
+	Encoding converter.EncodingType `json:"encoding"`
+	// Cells counts the cells of the range; empty TSV cells are not counted
+	Cells int `json:"cells"`
+	// Converted is how many cells (TSV) or text runs (HTML) changed
+	Converted int `json:"converted"`
+}
+
+// ConvertPastedRange converts a range copied from Excel: the tab-separated text or the HTML
+// table it puts on the clipboard. Rows, columns and quoting are kept, so the result pastes
+// back over the same range; in HTML, legacy fonts of converted text are replaced as well.
+// Why: Converting a few cells should not take saving the workbook and converting the file.
+func ConvertPastedRange(content string, opts ...Option) (PastedRange, error) {
+	p, err := newProcessorWithOptions(opts)
+	if err != nil {
+		return PastedRange{}, err
+	}
+	if isHTMLTable(content) {
+		return p.convertHTMLTable(content), nil
+	}
+	return p.convertTSV(content), nil
+}
+
+// isHTMLTable reports whether content is an HTML table (Excel's "HTML Format").
+func isHTMLTable(content string) bool {
+	return strings.Contains(strings.ToLower(content), "<table")
+}
+
+// tableText is one cell (TSV) or text run (HTML) of a pasted range, at content[start:end].
+type tableText struct {
+	start, end int
+	text       string
+	font       string
+	// quoted is set for TSV cells written in quotes (they hold tabs, line breaks or quotes)
+	quoted bool
+}
+
+// convertTableTexts converts texts and returns the edits of the content, the source
+// encoding detected from the texts without a legacy font, how many texts changed, and the
+// output family of each legacy font whose text was converted.
+func (p *Processor) convertTableTexts(texts []tableText,
+	encode func(tableText, string) string) ([]documentEdit, converter.EncodingType, int, map[string]string) {
+	// Detect the texts without a font together, so short cells do not decide on their own
+	var legacy strings.Builder
+	for _, t := range texts {
+		if _, ok := detectByFont(t.font); ok {
+			continue
+		}
+		if _, ok := detectUnicode(t.text); !ok {
+			legacy.WriteString(t.text)
+			legacy.WriteString("\n")
+		}
+	}
+	tableEnc := p.detectText(legacy.String())
+
+	var edits []documentEdit
+	converted := 0
+	fonts := make(map[string]string)
+	for _, t := range texts {
+		if _, ok := detectUnicode(t.text); ok || strings.TrimSpace(t.text) == "" {
+			continue
+		}
+		enc := tableEnc
+		if d, ok := detectByFont(t.font); ok && p.preserver.sourceEncoding == "" {
+			enc = d.Encoding
+		}
+		text, family := p.convertTableText(enc, t.font, t.text)
+		if text == t.text {
+			continue
+		}
+		converted++
+		if t.font != "" && family != "" && ClassifyFont(t.font) == FontKindLegacy {
+			fonts[t.font] = family
+		}
+		edits = append(edits, documentEdit{start: t.start, end: t.end, text: encode(t, text)})
+	}
+	return edits, tableEnc, converted, fonts
+}
+
+// convertTableText converts text from enc like a cell in font. Encodings the preserver has
+// no converter for (VIQR) convert without a font.
+func (p *Processor) convertTableText(enc converter.EncodingType, font, text string) (string, string) {
+	if p.preserver.Supports(enc) {
+		return p.preserver.ConvertAs(enc, font, text)
+	}
+	c, err := p.textConverter(enc)
+	if err != nil {
+		return text, ""
+	}
+	return c.ToUnicode(text), ""
+}
+
+// convertTSV converts the cells of tab-separated text as Excel copies it: rows end with
+// CRLF, and cells holding tabs, line breaks or quotes are quoted with doubled quotes.
+func (p *Processor) convertTSV(content string) PastedRange {
+	cells := splitTSV(content)
+	edits, enc, converted, _ := p.convertTableTexts(cells, func(t tableText, text string) string {
+		if t.quoted || strings.ContainsAny(text, "\t\r\n\"") {
+			return `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+		}
+		return text
+	})
+	return PastedRange{
+		Content:   string(applyDocumentEdits([]byte(content), edits)),
+		Format:    PastedTSV,
+		Encoding:  enc,
+		Cells:     len(cells),
+		Converted: converted,
+	}
+}
+
+// splitTSV returns the cells of tab-separated text, each spanning its quotes if any.
+func splitTSV(content string) []tableText {
+	var cells []tableText
+	start := 0
+	for start <= len(content) {
+		cell := tableText{start: start}
+		i := start
+		if i < len(content) && content[i] == '"' {
+			cell.text, i = readQuotedTSV(content, i)
+			cell.quoted = true
+		} else {
+			for i < len(content) && content[i] != '\t' && content[i] != '\r' && content[i] != '\n' {
+				i++
+			}
+			cell.text = content[start:i]
+		}
+		cell.end = i
+		if cell.end > cell.start {
+			cells = append(cells, cell)
+		}
+		// Skip to the next cell: past a tab, or past the line break ending the row
+		for i < len(content) && content[i] != '\t' && content[i] != '\n' {
+			i++
+		}
+		start = i + 1
+	}
+	return cells
+}
+
+// readQuotedTSV returns the unquoted text of the quoted cell starting at content[start]
+// and the index just past its closing quote. A quoted cell ends at a quote not followed
+// by another one.
+func readQuotedTSV(content string, start int) (string, int) {
+	var text strings.Builder
+	i := start + 1
+	for i < len(content) {
+		if content[i] == '"' {
+			if i+1 < len(content) && content[i+1] == '"' {
+				text.WriteByte('"')
+				i += 2
+				continue
+			}
+			return text.String(), i + 1
+		}
+		text.WriteByte(content[i])
+		i++
+	}
+	return text.String(), i
+}
+
+var (
+	// htmlTag matches a tag, comment or declaration of an HTML document
+	htmlTag = regexp.MustCompile(`(?s)<!--.*?-->|<[^>]*>`)
+	// htmlTagName captures the name of a tag, with "/" for closing tags
+	htmlTagName = regexp.MustCompile(`^<(/?)([a-zA-Z0-9]+)`)
+	// cssClassFont captures the class name and font family of the CSS rules Excel writes,
+	// e.g. .xl65 {... font-family:"VNI-Times", sans-serif; ...}
+	cssClassFont = regexp.MustCompile(`\.([A-Za-z0-9_-]+)\s*\{[^}]*?font-family:\s*"?([^";,}]+)`)
+	// htmlClass and htmlFontFamily read the class and inline font of a tag
+	htmlClass      = regexp.MustCompile(`(?i)\sclass=["']?([A-Za-z0-9_-]+)`)
+	htmlFontFamily = regexp.MustCompile(`(?i)font-family:\s*(?:&quot;|["'])?([^";,}&']+)|\sface=["']?([^"'>]+)`)
+)
+
+// convertHTMLTable converts the text of the cells of an HTML table. Each text run is
+// converted in the font of its innermost <font> or <span>, or of its cell, taken from the
+// inline style or the class rules of the <style> block.
+func (p *Processor) convertHTMLTable(content string) PastedRange {
+	sc := &htmlTableScanner{classFonts: make(map[string]string)}
+	for _, m := range cssClassFont.FindAllStringSubmatch(content, -1) {
+		sc.classFonts[m[1]] = strings.TrimSpace(m[2])
+	}
+	prev := 0
+	for _, loc := range htmlTag.FindAllStringIndex(content, -1) {
+		if sc.inCell && !sc.skip && loc[0] > prev {
+			sc.texts = append(sc.texts, tableText{
+				start: prev, end: loc[0], text: html.UnescapeString(content[prev:loc[0]]), font: sc.runFont(),
+			})
+		}
+		prev = loc[1]
+		sc.tag(content[loc[0]:loc[1]])
+	}
+	texts, cells := sc.texts, sc.cells
+
+	edits, enc, converted, fonts := p.convertTableTexts(texts, func(_ tableText, text string) string {
+		return html.EscapeString(text)
+	})
+	out := string(applyDocumentEdits([]byte(content), edits))
+	// The legacy fonts would show the converted text as mojibake after pasting
+	for legacy, family := range fonts {
+		out = replaceHTMLFont(out, legacy, family)
+	}
+	return PastedRange{Content: out, Format: PastedHTML, Encoding: enc, Cells: cells, Converted: converted}
+}
+
+// htmlTableScanner is the state of convertHTMLTable between tags.
+type htmlTableScanner struct {
+	// classFonts maps the CSS classes of the <style> block to their font family
+	classFonts map[string]string
+	texts      []tableText
+	cells      int
+	cellFont   string
+	// runFonts are the fonts of the open <font> and <span> tags, innermost last
+	runFonts []string
+	inCell   bool
+	// skip is set inside <style> and <script>
+	skip bool
+}
+
+// tag records an HTML tag.
+func (sc *htmlTableScanner) tag(tag string) {
+	m := htmlTagName.FindStringSubmatch(tag)
+	if m == nil {
+		return
+	}
+	closing, name := m[1] == "/", strings.ToLower(m[2])
+	switch name {
+	case "style", "script":
+		sc.skip = !closing
+	case "td", "th":
+		sc.inCell, sc.runFonts = !closing, nil
+		if !closing {
+			sc.cells++
+			sc.cellFont = sc.tagFont(tag)
+		}
+	case "font", "span":
+		if closing {
+			if len(sc.runFonts) > 0 {
+				sc.runFonts = sc.runFonts[:len(sc.runFonts)-1]
+			}
+			return
+		}
+		font := sc.tagFont(tag)
+		if font == "" {
+			font = sc.runFont()
+		}
+		sc.runFonts = append(sc.runFonts, font)
+	}
+}
+
+// runFont returns the font of text at the current position.
+func (sc *htmlTableScanner) runFont() string {
+	if len(sc.runFonts) > 0 {
+		return sc.runFonts[len(sc.runFonts)-1]
+	}
+	return sc.cellFont
+}
+
+// tagFont returns the font a tag sets inline or through its class, or "".
+func (sc *htmlTableScanner) tagFont(tag string) string {
+	if m := htmlFontFamily.FindStringSubmatch(tag); m != nil {
+		return strings.TrimSpace(m[1] + m[2])
+	}
+	if m := htmlClass.FindStringSubmatch(tag); m != nil {
+		return sc.classFonts[m[1]]
+	}
+	return ""
+}
+
+// replaceHTMLFont replaces the font family legacy with family in the font-family
+// declarations and face attributes of content.
+func replaceHTMLFont(content, legacy, family string) string {
+	quoted := regexp.QuoteMeta(legacy)
+	re := regexp.MustCompile(`(font-family:\s*(?:&quot;|["'])?|\sface=["']?)` + quoted + `\b`)
+	return re.ReplaceAllString(content, "${1}"+strings.ReplaceAll(family, "$", "$$"))
+}
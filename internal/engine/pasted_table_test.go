@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+func TestConvertPastedRange(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		opts          []Option
+		want          string
+		wantFormat    string
+		wantEncoding  converter.EncodingType
+		wantConverted int
+	}{
+		{
+			name:          "TSV",
+			content:       "Vie\u00E2\u00EFt Nam\t42\r\nHa\u00F8 No\u00E2\u00EFi\t\"Vie\u00E2\u00EFt\r\n\"\"Nam\"\"\"\r\nVi\u1EC7t Nam\t\r\n",
+			want:          "Vi\u1EC7t Nam\t42\r\nH\u00E0 N\u1ED9i\t\"Vi\u1EC7t\r\n\"\"Nam\"\"\"\r\nVi\u1EC7t Nam\t\r\n",
+			wantFormat:    PastedTSV,
+			wantEncoding:  converter.EncodingVNI,
+			wantConverted: 3,
+		},
+		{
+			name:          "TSV forced",
+			content:       "C\u00ABng ty\t1",
+			opts:          []Option{WithSourceEncoding(converter.EncodingTCVN3)},
+			want:          "C\u00F4ng ty\t1",
+			wantFormat:    PastedTSV,
+			wantEncoding:  converter.EncodingTCVN3,
+			wantConverted: 1,
+		},
+		{
+			name:         "TSV without legacy text",
+			content:      "Name\tAge\r\nAn\t30\r\n",
+			want:         "Name\tAge\r\nAn\t30\r\n",
+			wantFormat:   PastedTSV,
+			wantEncoding: converter.EncodingUnknown,
+		},
+		{
+			name: "HTML",
+			content: `<html><style>.xl65 {mso-style-parent:style0; font-family:"VNI-Times", sans-serif;}` +
+				`.font5 {font-family:".VnTime";}</style><body><table><tr>` +
+				`<td class=xl65>` + "Vi\u00D6t &amp; Nam" + `</td>` +
+				`<td style="font-family:Arial">` + "Vi\u1EC7t" + `</td>` +
+				`<td class=xl65><font class="font5">` + "C\u00ABng" + `</font> ty</td>` +
+				`</tr></table></body></html>`,
+			want: `<html><style>.xl65 {mso-style-parent:style0; font-family:"Times New Roman", sans-serif;}` +
+				`.font5 {font-family:"Times New Roman";}</style><body><table><tr>` +
+				`<td class=xl65>` + "Vi\u1EC7t &amp; Nam" + `</td>` +
+				`<td style="font-family:Arial">` + "Vi\u1EC7t" + `</td>` +
+				`<td class=xl65><font class="font5">` + "C\u00F4ng" + `</font> ty</td>` +
+				`</tr></table></body></html>`,
+			wantFormat:    PastedHTML,
+			wantEncoding:  converter.EncodingUnknown,
+			wantConverted: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertPastedRange(tt.content, tt.opts...)
+			if err != nil {
+				t.Fatalf("ConvertPastedRange failed: %v", err)
+			}
+			if got.Content != tt.want {
+				t.Errorf("Content = %q, want %q", got.Content, tt.want)
+			}
+			if got.Format != tt.wantFormat || got.Encoding != tt.wantEncoding || got.Converted != tt.wantConverted {
+				t.Errorf("ConvertPastedRange() = %s, %s, %d converted; want %s, %s, %d",
+					got.Format, got.Encoding, got.Converted, tt.wantFormat, tt.wantEncoding, tt.wantConverted)
+			}
+		})
+	}
+}
+
+func TestSplitTSV(t *testing.T) {
+	cells := splitTSV("a\t\"b\tc\"\r\n\td\r\n")
+	var got []string
+	for _, c := range cells {
+		got = append(got, c.text)
+	}
+	if want := "a|b\tc|d"; strings.Join(got, "|") != want {
+		t.Errorf("splitTSV() = %q, want %q", strings.Join(got, "|"), want)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package engine
+
+import (
+	"fmt"
+	"os"
+)
+
+// applySharedPermissions makes the file readable and writable by the folder's group.
+// Why: The default umask (usually 022) leaves outputs writable only by their creator,
+// so colleagues on the same share cannot replace or fix them.
+func applySharedPermissions(path string) error {
+	if err := os.Chmod(path, 0664); err != nil { //nolint:gosec // shared output is meant to be group-writable
+		return fmt.Errorf("failed to set shared permissions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplySharedPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.xlsx")
+	if err := os.WriteFile(path, []byte("x"), 0600); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	if err := applySharedPermissions(path); err != nil {
+		t.Fatalf("applySharedPermissions failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat failed: %v", err)
+	}
+	if got := info.Mode().Perm(); got != 0664 {
+		t.Errorf("mode = %o, want 664", got)
+	}
+}
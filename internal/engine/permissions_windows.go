@@ -0,0 +1,26 @@
+//go:build windows
+
+package engine
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// applySharedPermissions resets the file's ACL to the permissions inherited from its
+// folder and clears the read-only attribute.
+// Why: icacls /reset drops explicit entries (e.g. owner-only access carried over from
+// the user profile), so everyone with access to the share can open the output.
+func applySharedPermissions(path string) error {
+	cmd := exec.Command("icacls", path, "/reset", "/Q") //nolint:gosec,noctx // fixed binary, path is our own output
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reset permissions: %w (%s)", err, out)
+	}
+	if err := os.Chmod(path, 0666); err != nil { // On Windows this only clears read-only
+		return fmt.Errorf("failed to clear read-only attribute: %w", err)
+	}
+	return nil
+}
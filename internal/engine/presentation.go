@@ -0,0 +1,199 @@
+package engine
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IsPresentation reports whether path is a PowerPoint presentation Run converts as a presentation.
+func IsPresentation(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".pptx")
+}
+
+// isPresentationTextPart reports whether name is a part of a presentation whose runs are
+// converted: the slides and their speaker notes.
+func isPresentationTextPart(name string) bool {
+	slide, _ := path.Match("ppt/slides/slide*.xml", name)
+	notes, _ := path.Match("ppt/notesSlides/notesSlide*.xml", name)
+	return slide || notes
+}
+
+// runPresentation converts the PowerPoint presentation at InputPath and saves it under a
+// new output name, like runDocument does for documents. Each run of the text frames of
+// every slide and its notes is converted from the encoding of its Latin font (or its
+// content), and converted runs get the font the policy maps to.
+// Why: Training decks made before Unicode use VNI fonts throughout, and retyping them is
+// the only alternative.
+func (p *Processor) runPresentation(ctx context.Context) (string, error) {
+	return p.runPackage(ctx, "presentation", func(*zip.Reader) (packageConverter, error) {
+		return packageConverter{
+			isTextPart: isPresentationTextPart,
+			convert:    func(_ string, data []byte) ([]byte, int, error) { return p.convertPresentationPart(data) },
+		}, nil
+	})
+}
+
+// presentationRunProps are the a:rPr children the schema orders after a:latin.
+var presentationRunProps = map[string]bool{
+	"ea": true, "cs": true, "sym": true, "hlinkClick": true, "hlinkMouseOver": true, "rtl": true, "extLst": true,
+}
+
+// presentationRun collects what an a:r element needs for conversion.
+type presentationRun struct {
+	start       int // after the <a:r> start tag
+	font        string
+	latin       *documentEdit // the a:latin tag, with its attributes in latinAttrs
+	latinAttrs  []xml.Attr
+	props       *documentEdit // the a:rPr start tag
+	propsClosed bool
+	propsEnd    int // before the </a:rPr> end tag
+	latinAt     int // before the first a:rPr child ordered after a:latin, or -1
+	texts       []documentText
+}
+
+// convertPresentationPart converts the runs of one slide or notes part and returns it with
+// the number of runs converted. Like convertDocumentPart, only the text and font of
+// converted runs is replaced. Runs without a Latin font of their own (they inherit one
+// from the slide layout, master or theme) are detected by their content.
+func (p *Processor) convertPresentationPart(data []byte) ([]byte, int, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	sc := &presentationScanner{p: p, data: data}
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		end := int(dec.InputOffset())
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space == "a" {
+				sc.startElement(t, start, end)
+			}
+		case xml.CharData:
+			if sc.text != nil {
+				sc.text.text.Write(t)
+			}
+		case xml.EndElement:
+			if t.Name.Space == "a" && sc.run != nil {
+				sc.endElement(t, start)
+			}
+		}
+	}
+	return applyDocumentEdits(data, sc.edits), sc.converted, nil
+}
+
+// presentationScanner is the state of convertPresentationPart between tokens.
+type presentationScanner struct {
+	p         *Processor
+	data      []byte
+	edits     []documentEdit
+	run       *presentationRun
+	inProps   bool
+	text      *documentText
+	converted int
+}
+
+// startElement records an a: element that starts at data[start:end].
+func (sc *presentationScanner) startElement(t xml.StartElement, start, end int) {
+	run := sc.run
+	selfClosing := bytes.HasSuffix(sc.data[start:end], []byte("/>"))
+	switch {
+	case t.Name.Local == "r":
+		sc.run = &presentationRun{start: end, propsEnd: -1, latinAt: -1}
+	case run == nil:
+	case t.Name.Local == "rPr" && run.props == nil && run.texts == nil:
+		run.props = &documentEdit{start: start, end: end}
+		run.propsClosed = selfClosing
+		sc.inProps = !selfClosing
+	case sc.inProps && t.Name.Local == "latin":
+		run.latin = &documentEdit{start: start, end: end}
+		run.latinAttrs = t.Attr
+		// "+mn-lt" and "+mj-lt" name the theme fonts
+		if typeface := attrValue(t.Attr, "typeface"); !strings.HasPrefix(typeface, "+") {
+			run.font = typeface
+		}
+	case sc.inProps && presentationRunProps[t.Name.Local]:
+		if run.latinAt < 0 {
+			run.latinAt = start
+		}
+	case t.Name.Local == "t" && !selfClosing:
+		run.texts = append(run.texts, documentText{start: end})
+		sc.text = &run.texts[len(run.texts)-1]
+	}
+}
+
+// endElement records the end of an a: element of the open run at start, converting the
+// run once it closes.
+func (sc *presentationScanner) endElement(t xml.EndElement, start int) {
+	switch t.Name.Local {
+	case "rPr":
+		if sc.inProps {
+			sc.run.propsEnd = start
+			sc.inProps = false
+		}
+	case "t":
+		if sc.text != nil {
+			sc.text.end = start
+			sc.text = nil
+		}
+	case "r":
+		if runEdits := sc.p.convertPresentationRun(sc.run, sc.data); runEdits != nil {
+			sc.edits = append(sc.edits, runEdits...)
+			sc.converted++
+		}
+		sc.run = nil
+	}
+}
+
+// convertPresentationRun returns the edits converting run, or nil when it is left unchanged.
+func (p *Processor) convertPresentationRun(run *presentationRun, data []byte) []documentEdit {
+	edits, family := p.convertRunTexts(run.font, run.texts)
+	if edits == nil {
+		return nil
+	}
+	if family != "" && family != run.font {
+		edits = append(edits, run.fontEdit(family, data))
+	}
+	return edits
+}
+
+// fontEdit returns the edit setting the Latin font of run to family. The panose, pitch
+// and charset attributes describe the legacy font, so they are dropped.
+func (run *presentationRun) fontEdit(family string, data []byte) documentEdit {
+	var tag strings.Builder
+	tag.WriteString(`<a:latin typeface="` + attrEscaper.Replace(family) + `"`)
+	for _, attr := range run.latinAttrs {
+		switch attr.Name.Local {
+		case "typeface", "panose", "pitchFamily", "charset":
+			continue
+		}
+		writeAttr(&tag, attr)
+	}
+	tag.WriteString("/>")
+
+	switch {
+	case run.latin != nil:
+		return documentEdit{start: run.latin.start, end: run.latin.end, text: tag.String()}
+	case run.props != nil && run.propsClosed:
+		// <a:rPr lang="vi-VN"/> becomes a full element holding the font
+		open := strings.TrimRight(strings.TrimSuffix(string(data[run.props.start:run.props.end]), "/>"), " \t\r\n")
+		return documentEdit{start: run.props.start, end: run.props.end, text: open + ">" + tag.String() + "</a:rPr>"}
+	case run.latinAt >= 0:
+		return documentEdit{start: run.latinAt, end: run.latinAt, text: tag.String()}
+	case run.props != nil:
+		return documentEdit{start: run.propsEnd, end: run.propsEnd, text: tag.String()}
+	default:
+		return documentEdit{start: run.start, end: run.start, text: "<a:rPr>" + tag.String() + "</a:rPr>"}
+	}
+}
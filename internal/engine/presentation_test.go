@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunPresentation(t *testing.T) {
+	const ns = `xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"`
+	slide := func(paragraphs string) string {
+		return `<p:sld ` + ns + `><p:cSld><p:spTree><p:sp><p:txBody><a:bodyPr/>` + paragraphs + `</p:txBody></p:sp></p:spTree></p:cSld></p:sld>`
+	}
+
+	tests := []struct {
+		name      string
+		part      string
+		content   string
+		want      []string
+		unchanged bool
+		wantRuns  int
+	}{
+		{
+			name:     "Latin font",
+			part:     "ppt/slides/slide1.xml",
+			content:  slide(`<a:p><a:r><a:rPr lang="en-US" b="1"><a:latin typeface="VNI-Times" panose="02040603050405020304" charset="0"/></a:rPr><a:t>` + "Vie\u00e2\u00eft Nam" + `</a:t></a:r></a:p>`),
+			want:     []string{`<a:rPr lang="en-US" b="1"><a:latin typeface="Times New Roman"/></a:rPr><a:t>Việt Nam</a:t>`},
+			wantRuns: 1,
+		},
+		{
+			name:     "Font inserted in schema order",
+			part:     "ppt/slides/slide2.xml",
+			content:  slide(`<a:p><a:r><a:rPr lang="vi-VN"><a:solidFill><a:srgbClr val="FF0000"/></a:solidFill><a:ea typeface="+mn-ea"/></a:rPr><a:t>` + "C\u00ABng ty" + `</a:t></a:r></a:p>`),
+			want:     []string{`</a:solidFill><a:latin typeface="Arial"/><a:ea typeface="+mn-ea"/></a:rPr><a:t>Công ty</a:t>`},
+			wantRuns: 1,
+		},
+		{
+			name:     "Self-closing properties",
+			part:     "ppt/slides/slide1.xml",
+			content:  slide(`<a:p><a:r><a:rPr lang="vi-VN" dirty="0"/><a:t>` + "Vi\u00D6t Nam" + `</a:t></a:r><a:r><a:t>` + "Vi\u00D6t" + `</a:t></a:r></a:p>`),
+			want:     []string{`<a:rPr lang="vi-VN" dirty="0"><a:latin typeface="Arial"/></a:rPr><a:t>Việt Nam</a:t>`, `<a:r><a:rPr><a:latin typeface="Arial"/></a:rPr><a:t>Việt</a:t></a:r>`},
+			wantRuns: 2,
+		},
+		{
+			name:     "Notes",
+			part:     "ppt/notesSlides/notesSlide1.xml",
+			content:  `<p:notes ` + ns + `><p:cSld><p:spTree><p:sp><p:txBody><a:p><a:r><a:rPr><a:latin typeface="+mn-lt"/></a:rPr><a:t>` + "Vi\u00D6t Nam" + `</a:t></a:r></a:p></p:txBody></p:sp></p:spTree></p:cSld></p:notes>`,
+			want:     []string{`<a:latin typeface="Arial"/>`, `<a:t>Việt Nam</a:t>`},
+			wantRuns: 1,
+		},
+		{
+			name:      "Theme font English",
+			part:      "ppt/slides/slide1.xml",
+			content:   slide(`<a:p><a:r><a:rPr><a:latin typeface="+mn-lt"/></a:rPr><a:t>Quarterly results</a:t></a:r><a:endParaRPr lang="en-US"/></a:p>`),
+			unchanged: true,
+		},
+		{
+			name:      "Layout",
+			part:      "ppt/slideLayouts/slideLayout1.xml",
+			content:   `<p:sldLayout ` + ns + `><a:p><a:r><a:t>` + "Vi\u00D6t" + `</a:t></a:r></a:p></p:sldLayout>`,
+			unchanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := filepath.Join(t.TempDir(), "deck.pptx")
+			writeDocument(t, input, map[string]string{tt.part: tt.content})
+
+			p := NewProcessor(input, "")
+			output, err := p.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if filepath.Ext(output) != ".pptx" {
+				t.Errorf("output = %s, want a .pptx file", output)
+			}
+			if p.ConvertedRuns() != tt.wantRuns {
+				t.Errorf("ConvertedRuns() = %d, want %d", p.ConvertedRuns(), tt.wantRuns)
+			}
+			got := string(readZipPart(t, output, tt.part))
+			if tt.unchanged && got != tt.content {
+				t.Errorf("%s = %s, want it unchanged", tt.part, got)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("%s = %s, want it to contain %s", tt.part, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// MaxPreviewChanges caps the change list of a PreviewReport.
+// Why: The diff table is for review; a workbook with 100k changed cells is judged by its first rows.
+const MaxPreviewChanges = 1000
+
+// CellChange is one cell a conversion would change.
+type CellChange struct {
+	SheetName string                 `json:"sheetName"`
+	Axis      string                 `json:"axis"`
+	Before    string                 `json:"before"`
+	After     string                 `json:"after"`
+	Encoding  converter.EncodingType `json:"encoding"`
+	// Font and ConvertedFont are the first font family named in the cell before and after
+	Font          string `json:"font,omitempty"`
+	ConvertedFont string `json:"convertedFont,omitempty"`
+}
+
+// PreviewReport lists what Run would change, without any file being written.
+type PreviewReport struct {
+	InputPath     string         `json:"inputPath"`
+	Sheets        []string       `json:"sheets"`
+	CellsScanned  int            `json:"cellsScanned"`
+	CellsChanged  int            `json:"cellsChanged"`
+	Changes       []CellChange   `json:"changes"`
+	ChangesCapped bool           `json:"changesCapped"`
+	RenamedSheets []RenamedSheet `json:"renamedSheets,omitempty"`
+	SkippedCells  []SkippedCell  `json:"skippedCells,omitempty"`
+}
+
+// Preview detects and converts the workbook in memory and reports every changed cell.
+// Nothing is saved. It uses the same settings as Run (encoding, font policy, length guard).
+// Why: Users review a before/after diff table before committing to a conversion.
+func (p *Processor) Preview(ctx context.Context) (*PreviewReport, error) {
+	if err := p.openInput(ctx); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := p.f.Close(); closeErr != nil {
+			slog.Error("failed to close excel file", "error", closeErr)
+		}
+	}()
+
+	sheets, err := p.resolveSheets()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PreviewReport{
+		InputPath: p.InputPath,
+		Sheets:    sheets,
+		Changes:   []CellChange{},
+	}
+	for _, sheet := range sheets {
+		p.walkCells(ctx, sheet, func(job Job) bool {
+			report.CellsScanned++
+			if n, skip := p.oversized(job.Text); skip {
+				report.SkippedCells = append(report.SkippedCells, SkippedCell{SheetName: sheet, Axis: job.Axis, Length: n})
+				return true
+			}
+			res := p.convertJob(job)
+			after := runsText(res.NewRuns)
+			if after == job.Text {
+				return true
+			}
+			report.add(CellChange{
+				SheetName:     job.SheetName,
+				Axis:          job.Axis,
+				Before:        job.Text,
+				After:         after,
+				Encoding:      p.jobEncoding(job),
+				Font:          runsFont(job.RichText),
+				ConvertedFont: runsFont(res.NewRuns),
+			})
+			return true
+		})
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("preview cancelled: %w", err)
+	}
+
+	// The workbook is discarded, so renaming it is the simplest exact preview
+	if p.convertSheetNames {
+		report.RenamedSheets = p.renameLegacySheets(sheets)
+	}
+	return report, nil
+}
+
+func (r *PreviewReport) add(change CellChange) {
+	r.CellsChanged++
+	if len(r.Changes) < MaxPreviewChanges {
+		r.Changes = append(r.Changes, change)
+	} else {
+		r.ChangesCapped = true
+	}
+}
+
+// jobEncoding returns the forced source encoding, or the first one detected in the cell.
+func (p *Processor) jobEncoding(job Job) converter.EncodingType {
+	if p.preserver.sourceEncoding != "" {
+		return p.preserver.sourceEncoding
+	}
+	return p.detectJobEncoding(job)
+}
+
+// runsText joins the text of rich-text runs.
+func runsText(runs []excelize.RichTextRun) string {
+	var b strings.Builder
+	for _, run := range runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestProcessor_Preview(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "preview.xlsx")
+	f := excelize.NewFile()
+	for axis, v := range map[string]string{"A1": "Vi\u00D6t Nam", "A2": "Hello", "B1": "C\u00ABng ty"} {
+		if err := f.SetCellValue("Sheet1", axis, v); err != nil {
+			t.Fatalf("failed to set %s: %v", axis, err)
+		}
+	}
+	if err := f.SetSheetName("Sheet1", "Ba\u00F9o ca\u00F9o"); err != nil {
+		t.Fatalf("failed to rename sheet: %v", err)
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	_ = f.Close()
+	before, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatalf("failed to read input: %v", err)
+	}
+
+	report, err := NewProcessor(inputFile, "").Preview(context.Background())
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+
+	if report.CellsScanned != 3 || report.CellsChanged != 2 || report.ChangesCapped {
+		t.Errorf("report = %+v, want 3 scanned, 2 changed", report)
+	}
+	want := map[string]CellChange{
+		"A1": {SheetName: "Ba\u00F9o ca\u00F9o", Axis: "A1", Before: "Vi\u00D6t Nam", After: "Việt Nam", Encoding: converter.EncodingVNI, Font: "Calibri", ConvertedFont: "Arial"},
+		"B1": {SheetName: "Ba\u00F9o ca\u00F9o", Axis: "B1", Before: "C\u00ABng ty", After: "Công ty", Encoding: converter.EncodingTCVN3, Font: "Calibri", ConvertedFont: "Arial"},
+	}
+	for _, c := range report.Changes {
+		if c != want[c.Axis] {
+			t.Errorf("change = %+v, want %+v", c, want[c.Axis])
+		}
+	}
+	if len(report.RenamedSheets) != 1 || report.RenamedSheets[0].To != "Báo cáo" {
+		t.Errorf("RenamedSheets = %+v, want the tab renamed to Báo cáo", report.RenamedSheets)
+	}
+
+	// A preview never writes anything
+	after, err := os.ReadFile(inputFile)
+	if err != nil || string(after) != string(before) {
+		t.Error("input file was modified")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("preview created files: %v", entries)
+	}
+}
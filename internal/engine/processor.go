@@ -6,19 +6,26 @@ import (
 	"convert-vni-to-unicode/internal/converter"
 	"fmt"
 	"log/slog"
+	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/xuri/excelize/v2"
 )
 
 // Constants for processor configuration
 const (
-	// DefaultWorkerCount is the number of concurrent workers for cell processing.
-	// This is CPU-bound work, so we use a reasonable fixed value.
-	DefaultWorkerCount = 10
+	// WorkerCountAuto sizes the worker pool from the CPU count and the number of cells
+	// (see SetWorkerCount). It is the default.
+	WorkerCountAuto = 0
+
+	// cellsPerWorker is the fewest cells the auto mode gives each worker.
+	cellsPerWorker = 500
 
 	// JobChannelBuffer is the buffer size for job and result channels.
 	JobChannelBuffer = 100
@@ -29,9 +36,13 @@ const (
 type Job struct {
 	SheetName string
 	Axis      string
+	Row       int
+	Col       int
 	Text      string
 	RichText  []excelize.RichTextRun
 	IsRich    bool
+	// StyleFont is the cell style's font when a run has no font of its own, else nil
+	StyleFont *excelize.Font
 }
 
 // Result represents the outcome of a job.
@@ -40,50 +51,336 @@ type Result struct {
 	Converted string
 	NewRuns   []excelize.RichTextRun
 	Error     error
+	// TruncatedFrom is the converted length when the text was cut to MaxExcelCellLength, else 0
+	TruncatedFrom int
+	// Unchanged is set when conversion changed neither the text nor a font; NewRuns then
+	// holds the original runs and the cell is not written back
+	Unchanged bool
+}
+
+// Status is a detailed progress update.
+// Why: Carries enough context to build human-readable progress text.
+type Status struct {
+	SheetName string
+	Processed int
+	Total     int           // 0 when unknown
+	Percent   float64       // 0-100, 0 when Total is unknown
+	ETA       time.Duration // estimated time remaining, 0 when unknown
+}
+
+// SkippedCell is a cell left unconverted by the maximum length guard.
+type SkippedCell struct {
+	SheetName string `json:"sheetName"`
+	Axis      string `json:"axis"`
+	Length    int    `json:"length"`
 }
 
 // Processor manages the conversion process.
 // Thread-safety: The `f` (*excelize.File) field is NOT thread-safe.
 // Only the dispatcher goroutine should read from `f`, and only the
-// collector goroutine should write to `f`. Workers only process data.
+// collector goroutine should write to `f`, each holding fMu. Workers only process data.
 type Processor struct {
 	InputPath string
 	SheetName string
 	// State - NOT thread-safe, access must be serialized
-	f            *excelize.File
+	f *excelize.File
+	// fMu serializes dispatcher reads and collector writes of f; even reads update
+	// shared state inside excelize (the shared strings table, cached worksheets)
+	fMu          sync.Mutex
 	jobs         chan Job
 	results      chan Result
 	progressChan chan float64
+	statusChan   chan Status
+	tracer       Tracer
+	detections   *DetectionRecorder
+	changes      *ChangeRecorder
+	fontReport   *FontReport
+	spell        *SpellReport
+	buildInfo    BuildInfo
 	processed    int
-
-	// Format Preservers for different encodings (thread-safe for reads)
-	vniPreserver   *FormatPreserver
-	tcvn3Preserver *FormatPreserver
+	total        int // cells to convert, from the pre-scan
+	// writeBatchSize is the number of converted cells buffered per write batch
+	writeBatchSize int
+	throttle       *IOThrottle
+	outputDir      string // empty saves next to the input
+	sharedOutput   bool   // applySharedPermissions after saving
+	backupMode     BackupMode
+	backupPath     string // where the last in-place Run kept the original
+	// spillThreshold and tempDir control where ConvertXLSX unzips large worksheets
+	spillThreshold int64
+	tempDir        string
+	// timestampLayout is the Go layout of the output name suffix (see ParseTimestampFormat)
+	timestampLayout string
+	// maxCellLength skips cells longer than this many characters (0 disables the guard)
+	maxCellLength int
+	// cellFilter restricts conversion to a range and/or columns (nil converts every cell)
+	cellFilter *CellFilter
+	skipped    []SkippedCell   // written by the dispatcher only
+	truncated  []TruncatedCell // written by the collector only
+	errs       cellErrors
+	// broken maps the sheets excelize cannot parse to the error (see findBrokenSheets)
+	broken       map[string]error
+	brokenSheets []string // the broken sheets selected by the last Run
+	// textEncoding is the source encoding of the last text file Run (see TextEncoding)
+	textEncoding converter.EncodingType
+	// convertedRuns counts the runs the last document Run converted (see ConvertedRuns)
+	convertedRuns int
+	// stats counts the cells the last Run converted (see Stats)
+	stats ConversionStats
+	// convertSheetNames renames legacy-encoded sheet tabs after the cells are converted
+	convertSheetNames bool
+	renamed           []RenamedSheet
+	// plainCells writes plain cells back as plain strings (see SetPlainCells)
+	plainCells bool
+	// remapStyleFonts rewrites legacy fonts of the styles table (see SetRemapStyleFonts)
+	remapStyleFonts bool
+	// checkpointing saves the workbook after every sheet in Run (see SetCheckpoint)
+	checkpointing bool
+	checkpoint    *checkpoint
+	// workerCount is the size of the worker pool (WorkerCountAuto sizes it per pass)
+	workerCount int
+	// largeFileMode streams the output sheet by sheet (see SetLargeFileMode)
+	largeFileMode bool
+	// skipVerify saves without re-opening the output to check it (see SetVerifyOutput)
+	skipVerify bool
+
+	// preserver detects and converts every run (thread-safe for reads)
+	preserver *FormatPreserver
 }
 
 // NewProcessor creates a new processor instance.
 func NewProcessor(inputPath, sheetName string) *Processor {
 	return &Processor{
-		InputPath:      inputPath,
-		SheetName:      sheetName,
-		jobs:           make(chan Job, JobChannelBuffer),
-		results:        make(chan Result, JobChannelBuffer),
-		vniPreserver:   NewFormatPreserver(converter.NewVNIConverter()),
-		tcvn3Preserver: NewFormatPreserver(converter.NewTCVN3Converter()),
+		InputPath: inputPath,
+		SheetName: sheetName,
+		preserver: newFormatPreserver(),
+		buildInfo: NewBuildInfo("unknown"),
+
+		timestampLayout: defaultTimestampLayout,
+		spillThreshold:  DefaultSpillThreshold,
+
+		convertSheetNames: true,
+
+		writeBatchSize: DefaultWriteBatchSize,
 	}
 }
 
-// SetProgressChan sets the channel for progress updates.
+// SetProgressChan sets the channel for progress updates, as a percentage (0-100).
 func (p *Processor) SetProgressChan(ch chan float64) {
 	p.progressChan = ch
 }
 
-// Run executes the conversion process.
-func (p *Processor) Run(ctx context.Context) (string, error) {
-	var err error
+// SetStatusChan sets the channel for detailed (per-sheet) progress updates.
+func (p *Processor) SetStatusChan(ch chan Status) {
+	p.statusChan = ch
+}
+
+// SetFontPolicy sets the policy deciding output fonts for every encoding.
+func (p *Processor) SetFontPolicy(policy FontPolicy) {
+	p.preserver.SetFontPolicy(policy)
+}
+
+// SetVNIStrictness sets how VNI text reads the ambiguous Ö/ö (see converter.VNIStrictness).
+func (p *Processor) SetVNIStrictness(s converter.VNIStrictness) {
+	p.preserver.SetConverter(converter.EncodingVNI, converter.NewVNIConverterWithStrictness(s))
+}
+
+// SetNormalization writes converted text in the Unicode form n (see converter.Normalization),
+// in cells, comments, charts and sheet names alike.
+func (p *Processor) SetNormalization(n converter.Normalization) {
+	p.preserver.SetNormalization(n)
+}
+
+// SetDetector sets the detector used in auto-detect mode (see NewDetector).
+func (p *Processor) SetDetector(d Detector) {
+	p.preserver.SetDetector(d)
+}
+
+// SetSourceEncoding converts every run from enc instead of detecting the encoding per run.
+// EncodingAuto (or "") restores detection.
+// Why: Some encodings (e.g. VIQR) are plain ASCII and cannot be detected from font or text.
+func (p *Processor) SetSourceEncoding(enc converter.EncodingType) error {
+	return p.preserver.SetSourceEncoding(enc)
+}
+
+// textConverter returns the converter runs from enc are converted with, so whole texts read
+// the same way as cells (e.g. with the VNI strictness and output normalization).
+func (p *Processor) textConverter(enc converter.EncodingType) (converter.Converter, error) {
+	c, ok := p.preserver.Converter(enc)
+	if !ok {
+		var err error
+		if c, err = converter.NewConverter(enc); err != nil {
+			return nil, err
+		}
+	}
+	if p.preserver.normalization == converter.NormalizationNFD {
+		c = converter.NormalizedConverter{Converter: c, Form: p.preserver.normalization}
+	}
+	return c, nil
+}
+
+// SetPlainCells writes cells that were plain strings back with SetCellValue, moving the
+// converted font into the cell style, instead of as rich text. Rich text cells stay rich.
+// Why: Rich text for every cell bloats the shared strings table, and some downstream tools
+// read rich text cells poorly.
+func (p *Processor) SetPlainCells(enabled bool) {
+	p.plainCells = enabled
+}
+
+// SetWriteBatchSize sets how many converted cells are buffered before writing.
+func (p *Processor) SetWriteBatchSize(n int) {
+	p.writeBatchSize = n
+}
+
+// SetWorkerCount sets the number of workers converting cells. WorkerCountAuto (or any
+// n < 1) uses one worker per CPU, fewer for small workbooks.
+// Why: Conversion is CPU-bound; a fixed pool oversubscribes small machines and leaves
+// cores idle on large ones.
+func (p *Processor) SetWorkerCount(n int) {
+	if n < 1 {
+		n = WorkerCountAuto
+	}
+	p.workerCount = n
+}
+
+// workers returns the worker pool size for a pass over cells cells.
+func (p *Processor) workers(cells int) int {
+	if p.workerCount != WorkerCountAuto {
+		return p.workerCount
+	}
+	return autoWorkerCount(cells, runtime.NumCPU())
+}
+
+// autoWorkerCount returns one worker per CPU, but no more than one per cellsPerWorker
+// cells, since starting workers for a handful of cells costs more than it saves.
+func autoWorkerCount(cells, cpus int) int {
+	n := (cells + cellsPerWorker - 1) / cellsPerWorker
+	return max(1, min(n, cpus))
+}
+
+// SetMaxCellLength skips cells longer than n characters instead of converting them. 0 disables the guard.
+// Why: Huge cells are embedded JSON/XML blobs that never contain Vietnamese prose
+// but cost the converters a rune-by-rune pass.
+func (p *Processor) SetMaxCellLength(n int) {
+	p.maxCellLength = n
+}
+
+// SetConvertSheetNames enables (the default) or disables converting sheet names to Unicode.
+func (p *Processor) SetConvertSheetNames(enabled bool) {
+	p.convertSheetNames = enabled
+}
+
+// RenamedSheets returns the sheets renamed to Unicode during the last Run.
+func (p *Processor) RenamedSheets() []RenamedSheet {
+	out := make([]RenamedSheet, len(p.renamed))
+	copy(out, p.renamed)
+	return out
+}
+
+// SkippedCells returns the cells skipped by the length guard during the last Run.
+func (p *Processor) SkippedCells() []SkippedCell {
+	out := make([]SkippedCell, len(p.skipped))
+	copy(out, p.skipped)
+	return out
+}
+
+// TruncatedCells returns the cells cut to MaxExcelCellLength during the last Run.
+func (p *Processor) TruncatedCells() []TruncatedCell {
+	out := make([]TruncatedCell, len(p.truncated))
+	copy(out, p.truncated)
+	return out
+}
+
+// CellErrors returns the cells, rows and sheets that failed during the last Run and were
+// left unconverted. At most 1000 are kept.
+// Why: Failures used to be logged and swallowed, so a workbook could come back half
+// converted without the user knowing which cells to check.
+func (p *Processor) CellErrors() []CellError {
+	return p.errs.snapshot()
+}
+
+// SetOutputDir saves the output into dir instead of next to the input.
+func (p *Processor) SetOutputDir(dir string) {
+	p.outputDir = dir
+}
+
+// SetTimestampFormat sets the output name suffix, e.g. "yyyyMMdd_HHmmss" (see ParseTimestampFormat).
+func (p *Processor) SetTimestampFormat(format string) error {
+	layout, err := ParseTimestampFormat(format)
+	if err != nil {
+		return err
+	}
+	p.timestampLayout = layout
+	return nil
+}
+
+// SetSharedOutput makes saved outputs accessible to everyone with access to the output folder
+// (ACL reset to inherited on Windows, group-writable elsewhere).
+func (p *Processor) SetSharedOutput(shared bool) {
+	p.sharedOutput = shared
+}
+
+// SetIOThrottle limits file opens/saves, e.g. for network shares. nil disables throttling.
+func (p *Processor) SetIOThrottle(t *IOThrottle) {
+	p.throttle = t
+}
+
+// openInput opens InputPath under the I/O throttle.
+func (p *Processor) openInput(ctx context.Context) error {
+	release, err := p.throttle.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	p.f, err = excelize.OpenFile(p.InputPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to open excel: %w", err)
+		if format := DetectLegacyFormat(p.InputPath); format != "" {
+			return &LegacyFormatError{Path: p.InputPath, Format: format}
+		}
+		return fmt.Errorf("failed to open excel: %w", err)
+	}
+	return nil
+}
+
+// SetTracer sets optional per-cell tracing hooks.
+func (p *Processor) SetTracer(t Tracer) {
+	p.tracer = t
+}
+
+// SetDetectionTrace records which detection rule fired for every converted run.
+func (p *Processor) SetDetectionTrace(r *DetectionRecorder) {
+	p.detections = r
+}
+
+// SetChangeReport records every modified cell (original and converted text) for auditing.
+func (p *Processor) SetChangeReport(r *ChangeRecorder) {
+	p.changes = r
+}
+
+// SetSpellReport spell-checks every converted cell into r, to catch wrong detections.
+func (p *Processor) SetSpellReport(r *SpellReport) {
+	p.spell = r
+}
+
+// SetBuildInfo sets the build identification stamped into logs and output properties.
+func (p *Processor) SetBuildInfo(b BuildInfo) {
+	p.buildInfo = b
+}
+
+// Run executes the conversion process. Text files (see IsTextFile) are converted as text,
+// Word documents (see IsDocument) and presentations (see IsPresentation) run by run, and
+// OpenDocument spreadsheets (see IsOpenDocument) cell by cell.
+// Cancelling ctx stops the dispatcher, workers and writer; no output file is saved in that case.
+func (p *Processor) Run(ctx context.Context) (string, error) {
+	slog.Info("conversion started", append([]any{"input", p.InputPath}, p.buildInfo.LogAttrs()...)...)
+	p.stats, p.convertedRuns, p.textEncoding = ConversionStats{}, 0, ""
+	if run := p.formatRunner(); run != nil {
+		return run(ctx)
+	}
+
+	if err := p.openInput(ctx); err != nil {
+		return "", err
 	}
 	defer func() {
 		if closeErr := p.f.Close(); closeErr != nil {
@@ -92,27 +389,219 @@ func (p *Processor) Run(ctx context.Context) (string, error) {
 	}()
 
 	// 1. Determine sheets to process
-	sheets := p.f.GetSheetList()
-	if p.SheetName != "" {
-		// Validating if sheet exists
-		found := false
-		for _, s := range sheets {
-			if s == p.SheetName {
-				found = true
-				break
+	sheets, err := p.resolveSheets()
+	if err != nil {
+		return "", err
+	}
+
+	// Fail now rather than at SaveAs, after the whole workbook was converted
+	outputDir := filepath.Dir(p.outputPath(time.Now()))
+	if p.backupMode != BackupNone {
+		outputDir = filepath.Dir(p.InputPath)
+	}
+	if err := checkOutputWritable(outputDir); err != nil {
+		return "", err
+	}
+	if p.largeFileMode {
+		return p.runLargeFile(ctx, sheets)
+	}
+	if err := p.convertWorkbook(ctx, sheets); err != nil {
+		return "", err
+	}
+	return p.save(ctx)
+}
+
+// formatRunner returns how inputs that are not workbooks are run, or nil for a workbook.
+func (p *Processor) formatRunner() func(context.Context) (string, error) {
+	switch {
+	case IsTextFile(p.InputPath):
+		return p.runText
+	case IsDocument(p.InputPath):
+		return p.runDocument
+	case IsPresentation(p.InputPath):
+		return p.runPresentation
+	case IsOpenDocument(p.InputPath):
+		return p.runOpenDocument
+	}
+	return nil
+}
+
+// convertWorkbook converts sheets of the open workbook with its font reports and
+// checkpoint, and stamps the build into it.
+func (p *Processor) convertWorkbook(ctx context.Context, sheets []string) error {
+	p.findBrokenSheets()
+	// Count before the checkpoint replaces the workbook with a partly converted one
+	if p.fontReport != nil {
+		if err := p.countFonts(false); err != nil {
+			return err
+		}
+	}
+
+	p.checkpoint = nil
+	if p.checkpointing {
+		if err := p.openCheckpoint(); err != nil {
+			return err
+		}
+	}
+
+	if err := p.convert(ctx, sheets); err != nil {
+		return err
+	}
+	if p.checkpoint != nil {
+		p.clearCheckpointState()
+	}
+	if p.fontReport != nil {
+		if err := p.countFonts(true); err != nil {
+			return err
+		}
+	}
+
+	p.stampBuildInfo(p.f)
+	return nil
+}
+
+// save saves the converted workbook in place or under a new output name and returns it.
+func (p *Processor) save(ctx context.Context) (string, error) {
+	release, err := p.throttle.Acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	p.backupPath = ""
+	if p.backupMode != BackupNone {
+		path, err := p.saveInPlace()
+		if err == nil {
+			p.removeCheckpoint()
+		}
+		return path, err
+	}
+
+	return p.saveOutput(p.f)
+}
+
+// saveOutput saves f under a new timestamped output name and returns it.
+// The caller holds the I/O throttle.
+func (p *Processor) saveOutput(f *excelize.File) (string, error) {
+	// Save with timestamp suffix; a numeric suffix is added if that name is taken
+	outputPath, err := reserveOutputPath(p.outputPath(time.Now()))
+	if err != nil {
+		return "", err
+	}
+
+	if err := f.SaveAs(outputPath); err != nil {
+		// Never leave a truncated workbook behind
+		if rmErr := os.Remove(outputPath); rmErr != nil && !os.IsNotExist(rmErr) {
+			slog.Error("failed to remove partial output", "path", outputPath, "error", rmErr)
+		}
+		return "", fmt.Errorf("failed to save output file: %w", err)
+	}
+	if err := p.verifyOutput(outputPath); err != nil {
+		if rmErr := os.Remove(outputPath); rmErr != nil && !os.IsNotExist(rmErr) {
+			slog.Error("failed to remove corrupt output", "path", outputPath, "error", rmErr)
+		}
+		return "", err
+	}
+
+	if p.sharedOutput {
+		// The output is complete; a permission failure here is reported but not fatal
+		if err := applySharedPermissions(outputPath); err != nil {
+			slog.Warn("failed to apply shared permissions", "path", outputPath, "error", err)
+		}
+	}
+	p.removeCheckpoint()
+
+	return outputPath, nil
+}
+
+// convert converts the cells of sheets with the worker pool, then the comments, charts
+// and sheet names of the open workbook. Nothing is saved, except the checkpoint after
+// each sheet when checkpointing.
+func (p *Processor) convert(ctx context.Context, sheets []string) error {
+	p.errs.reset()
+	sheets = p.skipBrokenSheets(sheets)
+	remaining := sheets
+	if p.checkpoint != nil {
+		remaining = sheetsToConvert(sheets, p.checkpoint.state.Completed)
+	}
+	// Pre-scan so progress can report a percentage
+	p.total = p.countCells(ctx, remaining)
+
+	p.skipped = nil
+	p.truncated = nil
+	p.processed = 0
+	started := time.Now()
+	if p.checkpoint == nil {
+		if err := p.convertCells(ctx, remaining, started); err != nil {
+			return err
+		}
+	} else {
+		for _, sheet := range remaining {
+			if err := p.convertCells(ctx, []string{sheet}, started); err != nil {
+				return err
+			}
+			if err := p.completeSheet(sheet); err != nil {
+				return err
 			}
 		}
-		if !found {
-			return "", fmt.Errorf("sheet %q not found", p.SheetName)
+	}
+
+	for enc, ec := range p.preserver.encodings {
+		if hits, misses := ec.cache.stats(); hits+misses > 0 {
+			slog.Debug("conversion cache", "encoding", enc, "hits", hits, "misses", misses)
+		}
+	}
+	p.convertObjects(sheets)
+	return nil
+}
+
+// convertObjects converts the comments, charts and sheet names of the open workbook,
+// then tidies its fonts.
+func (p *Processor) convertObjects(sheets []string) {
+	// Comments are read by sheet name, so they are converted before sheets are renamed
+	if n := p.convertComments(sheets); n > 0 {
+		slog.Info("converted comments", "count", n)
+	}
+	if n := p.convertThreadedComments(sheets); n > 0 {
+		slog.Info("converted threaded comments", "count", n)
+	}
+	if n := p.convertCharts(); n > 0 {
+		slog.Info("converted charts", "count", n)
+	}
+
+	p.renamed = nil
+	if p.convertSheetNames {
+		p.renamed = p.renameLegacySheets(sheets)
+	}
+
+	if p.remapStyleFonts {
+		if n, err := p.remapLegacyStyleFonts(); err != nil {
+			slog.Warn("style font remapping skipped", "error", err)
+		} else if n > 0 {
+			slog.Info("remapped legacy style fonts", "count", n)
 		}
-		sheets = []string{p.SheetName}
 	}
 
+	// Keep the styles table within Excel's limits; a failure here only costs file size
+	if removed, err := dedupFonts(p.f); err != nil {
+		slog.Warn("font deduplication skipped", "error", err)
+	} else if removed > 0 {
+		slog.Info("removed duplicate fonts", "count", removed)
+	}
+}
+
+// convertCells converts the cells of sheets with the worker pool and writes them back.
+// Progress counts continue from p.processed, with ETA measured from started.
+func (p *Processor) convertCells(ctx context.Context, sheets []string, started time.Time) error {
+	// Channels are closed at the end of each pass, so every pass gets new ones
+	p.jobs = make(chan Job, JobChannelBuffer)
+	p.results = make(chan Result, JobChannelBuffer)
+
 	// Start Workers
 	var wg sync.WaitGroup
-	for i := 0; i < DefaultWorkerCount; i++ {
+	for i := 0; i < p.workers(p.total); i++ {
 		wg.Add(1)
-		go p.worker(&wg)
+		go p.worker(ctx, &wg)
 	}
 
 	// Dispatcher - runs in a separate goroutine
@@ -124,36 +613,86 @@ func (p *Processor) Run(ctx context.Context) (string, error) {
 		close(p.results)
 	}()
 
-	p.processed = 0
+	writer := newBatchWriter(p.f, &p.fMu, p.writeBatchSize)
+	writer.plainCells = p.plainCells
+	writer.errs = &p.errs
 
 	for res := range p.results {
+		// Keep draining so workers never block, but stop writing once cancelled
+		if ctx.Err() != nil {
+			continue
+		}
 		if res.Error != nil {
 			slog.Error("failed to process cell", "cell", res.Job.Axis, "error", res.Error)
+			p.errs.add(res.Job.SheetName, res.Job.Axis, StageConvert, res.Error)
 			continue
 		}
 
-		// Always write Rich Text to enforce font/format
-		if err := p.f.SetCellRichText(res.Job.SheetName, res.Job.Axis, res.NewRuns); err != nil {
-			slog.Error("failed to write rich text", "cell", res.Job.Axis, "error", err)
+		if res.TruncatedFrom > 0 {
+			slog.Warn("truncated cell to Excel's limit",
+				"sheet", res.Job.SheetName, "cell", res.Job.Axis, "length", res.TruncatedFrom)
+			p.truncated = append(p.truncated, TruncatedCell{
+				SheetName: res.Job.SheetName, Axis: res.Job.Axis, Length: res.TruncatedFrom,
+			})
+		}
+		if !res.Unchanged {
+			writer.add(res)
+		}
+		p.recordStats(res)
+		if p.changes != nil {
+			p.changes.record(res, p.jobEncoding(res.Job))
+		}
+		if p.spell != nil {
+			p.spell.record(res, p.jobEncoding(res.Job))
 		}
 
 		p.processed++
+		percent := progressPercent(p.processed, p.total)
 		if p.progressChan != nil {
-			p.progressChan <- float64(p.processed)
+			p.progressChan <- percent
+		}
+		if p.statusChan != nil {
+			p.statusChan <- Status{
+				SheetName: res.Job.SheetName,
+				Processed: p.processed,
+				Total:     p.total,
+				Percent:   percent,
+				ETA:       estimateETA(time.Since(started), p.processed, p.total),
+			}
 		}
 	}
 
-	// Save with timestamp suffix
-	timestamp := time.Now().Format("2006_01_02_15_04_05")
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("conversion cancelled: %w", err)
+	}
+	writer.flush()
+	return nil
+}
+
+// outputPath returns the timestamped output name, next to the input unless an output dir is set.
+func (p *Processor) outputPath(now time.Time) string {
+	timestamp := now.Format(p.timestampLayout)
 	ext := filepath.Ext(p.InputPath)
 	base := strings.TrimSuffix(p.InputPath, ext)
-	outputPath := fmt.Sprintf("%s_output_%s%s", base, timestamp, ext)
-
-	if err := p.f.SaveAs(outputPath); err != nil {
-		return "", fmt.Errorf("failed to save output file: %w", err)
+	if p.outputDir != "" {
+		base = filepath.Join(p.outputDir, filepath.Base(base))
 	}
+	return base + outputMarker + timestamp + ext
+}
 
-	return outputPath, nil
+// stampBuildInfo records the converter build in the custom document properties of f.
+func (p *Processor) stampBuildInfo(f *excelize.File) {
+	props := p.buildInfo.properties()
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := f.SetCustomProps(excelize.CustomProperty{Name: name, Value: props[name]}); err != nil {
+			slog.Error("failed to set custom property", "name", name, "error", err)
+		}
+	}
 }
 
 // processSheets iterates through sheets to dispatch jobs
@@ -164,146 +703,241 @@ func (p *Processor) processSheets(ctx context.Context, sheets []string) {
 	}
 }
 
+// resolveSheets returns the sheets to process, validating SheetName if set.
+func (p *Processor) resolveSheets() ([]string, error) {
+	sheets := p.f.GetSheetList()
+	if p.SheetName == "" {
+		return sheets, nil
+	}
+	for _, s := range sheets {
+		if s == p.SheetName {
+			return []string{p.SheetName}, nil
+		}
+	}
+	return nil, fmt.Errorf("sheet %q not found", p.SheetName)
+}
+
+// isTextCell reports whether a cell stores a string, the only kind of cell that is converted.
+// Why: Rows() returns numbers, dates, booleans and formula results already formatted (e.g.
+// "1.234,56" or a VNI-encoded currency suffix); rewriting that as rich text would freeze the
+// displayed value into a string and drop the number format, locale and formula.
+func isTextCell(cellType excelize.CellType) bool {
+	return cellType == excelize.CellTypeSharedString || cellType == excelize.CellTypeInlineString
+}
+
 func (p *Processor) processSheet(ctx context.Context, sheet string) {
+	p.walkCells(ctx, sheet, func(job Job) bool {
+		if n, skip := p.oversized(job.Text); skip {
+			slog.Warn("skipping oversized cell", "sheet", sheet, "cell", job.Axis, "length", n)
+			p.skipped = append(p.skipped, SkippedCell{SheetName: sheet, Axis: job.Axis, Length: n})
+			return true
+		}
+		select {
+		case p.jobs <- job:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
+
+// oversized reports whether text exceeds the maximum cell length guard, and its length.
+func (p *Processor) oversized(text string) (int, bool) {
+	if p.maxCellLength <= 0 {
+		return 0, false
+	}
+	n := utf8.RuneCountInString(text)
+	return n, n > p.maxCellLength
+}
+
+// walkCells builds a Job for every non-empty cell of sheet and passes it to visit.
+// Walking stops when visit returns false or ctx is cancelled.
+// Why: Shared by conversion and the read-only check so both see cells identically.
+func (p *Processor) walkCells(ctx context.Context, sheet string, visit func(Job) bool) {
+	p.fMu.Lock()
 	rows, err := p.f.Rows(sheet)
+	p.fMu.Unlock()
 	if err != nil {
 		slog.Error("failed to get rows", "sheet", sheet, "error", err)
+		p.errs.add(sheet, "", StageRead, err)
 		return
 	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Error("failed to close rows iterator", "sheet", sheet, "error", err)
+		}
+	}()
 
 	rowIdx := 0
-	for rows.Next() {
+	for {
+		// The workbook is only locked while reading, never while visit may block on a channel
+		p.fMu.Lock()
+		if !rows.Next() {
+			p.fMu.Unlock()
+			return
+		}
 		rowIdx++
-		cols, err := rows.Columns()
-		if err != nil {
-			slog.Error("failed to get columns", "sheet", sheet, "row", rowIdx, "error", err)
-			continue
+		if p.cellFilter.pastLastRow(rowIdx) {
+			p.fMu.Unlock()
+			return
 		}
-		for colIdx, text := range cols {
-			// Check for cancellation
-			select {
-			case <-ctx.Done():
+		jobs := p.rowJobs(ctx, sheet, rows, rowIdx)
+		p.fMu.Unlock()
+
+		for _, job := range jobs {
+			if !visit(job) {
 				return
-			default:
 			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
 
-			axis, err := excelize.CoordinatesToCellName(colIdx+1, rowIdx)
-			if err != nil {
-				slog.Error("failed to convert coordinates", "row", rowIdx, "col", colIdx+1, "error", err)
-				continue
-			}
+// rowJobs builds the Jobs of the current row of rows. The caller holds p.fMu.
+func (p *Processor) rowJobs(ctx context.Context, sheet string, rows *excelize.Rows, rowIdx int) []Job {
+	cols, err := rows.Columns()
+	if err != nil {
+		slog.Error("failed to get columns", "sheet", sheet, "row", rowIdx, "error", err)
+		p.errs.add(sheet, rowAxis(rowIdx), StageRead, err)
+		return nil
+	}
+	return p.cellJobs(ctx, sheet, cols, rowIdx)
+}
 
-			if strings.TrimSpace(text) == "" {
-				continue
-			}
+// cellJobs builds the Jobs of the cells of one row, given its values. The caller holds p.fMu.
+func (p *Processor) cellJobs(ctx context.Context, sheet string, cols []string, rowIdx int) []Job {
+	var jobs []Job
+	for colIdx, text := range cols {
+		// Check for cancellation
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !p.cellFilter.Contains(colIdx+1, rowIdx) {
+			continue
+		}
 
-			// Strategy: Unify everything to RichText for consistent processing.
-			// 1. Try to get existing RichText
-			runs, err := p.f.GetCellRichText(sheet, axis)
-			isRich := false
-			if err == nil && len(runs) > 0 {
-				isRich = true
-			}
+		axis, err := excelize.CoordinatesToCellName(colIdx+1, rowIdx)
+		if err != nil {
+			slog.Error("failed to convert coordinates", "row", rowIdx, "col", colIdx+1, "error", err)
+			p.errs.add(sheet, rowAxis(rowIdx), StageRead, err)
+			continue
+		}
 
-			// 2. If no RichText, create synthetic RichText from Plain Text + Style Font
-			if !isRich {
-				fontName := ""
-				styleID, err := p.f.GetCellStyle(sheet, axis)
-				if err == nil {
-					style, err := p.f.GetStyle(styleID)
-					if err == nil && style.Font != nil {
-						fontName = style.Font.Family
-						slog.Debug("cell font detected", "cell", axis, "font", fontName)
-					}
-				}
-				// Create synthetic run with capacity hint
-				runs = make([]excelize.RichTextRun, 0, 1)
-				runs = append(runs, excelize.RichTextRun{
-					Text: text,
-					Font: &excelize.Font{Family: fontName, Size: 11},
-				})
-			}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if cellType, err := p.f.GetCellType(sheet, axis); err != nil || !isTextCell(cellType) {
+			continue
+		}
 
-			// Send Job
-			p.jobs <- Job{
-				SheetName: sheet,
-				Axis:      axis,
-				Text:      text,
-				RichText:  runs,
-				IsRich:    isRich,
+		// Strategy: Unify everything to RichText for consistent processing.
+		// 1. Try to get existing RichText; plain strings come back as one run without a font
+		runs, err := p.f.GetCellRichText(sheet, axis)
+		isRich := err == nil && hasAnyRunFont(runs)
+
+		// 2. If no RichText, create synthetic RichText from Plain Text + Style Font
+		var styleFont *excelize.Font
+		if !isRich {
+			// Copy the whole style font (size, color, bold, italic, underline, strike), so
+			// the rewritten cell keeps its look
+			font := excelize.Font{}
+			if sf := p.cellStyleFont(sheet, axis); sf != nil {
+				font = *sf
+				slog.Debug("cell font detected", "cell", axis, "font", font.Family)
 			}
+			// Create synthetic run with capacity hint
+			runs = make([]excelize.RichTextRun, 0, 1)
+			runs = append(runs, excelize.RichTextRun{
+				Text: text,
+				Font: &font,
+			})
+		} else if !hasRunFonts(runs) {
+			// Runs without a font of their own draw with the style font
+			styleFont = p.cellStyleFont(sheet, axis)
 		}
+
+		jobs = append(jobs, Job{
+			SheetName: sheet,
+			Axis:      axis,
+			Row:       rowIdx,
+			Col:       colIdx + 1,
+			Text:      text,
+			RichText:  runs,
+			IsRich:    isRich,
+			StyleFont: styleFont,
+		})
+	}
+	return jobs
+}
+
+// cellStyleFont returns the font of the cell's style, or nil. A style font without a
+// family gets the workbook's default font, which Excel draws it in. The caller holds p.fMu.
+func (p *Processor) cellStyleFont(sheet, axis string) *excelize.Font {
+	styleID, err := p.f.GetCellStyle(sheet, axis)
+	if err != nil {
+		return nil
+	}
+	style, err := p.f.GetStyle(styleID)
+	if err != nil || style.Font == nil {
+		return nil
 	}
-	if err := rows.Close(); err != nil {
-		slog.Error("failed to close rows iterator", "sheet", sheet, "error", err)
+	if style.Font.Family != "" {
+		return style.Font
 	}
+	font := *style.Font
+	font.Family, _ = p.f.GetDefaultFont()
+	return &font
 }
 
-func (p *Processor) worker(wg *sync.WaitGroup) {
+func (p *Processor) worker(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 	for job := range p.jobs {
-		// Worker only processes data, does NOT access p.f (not thread-safe)
-		res := Result{Job: job}
-
-		// Pre-allocate with capacity hint
-		newRuns := make([]excelize.RichTextRun, 0, len(job.RichText))
-
-		if len(job.RichText) > 0 {
-			// Rich Text Handling - process each run independently
-			for _, run := range job.RichText {
-				var text string
-				fontName := ""
-				if run.Font != nil {
-					fontName = run.Font.Family
-				}
-
-				encoding := DetectEncoding(fontName, run.Text)
-
-				// Apply conversion based on detected encoding
-				switch encoding {
-				case converter.EncodingVNI:
-					text = p.vniPreserver.converter.ToUnicode(run.Text)
-					// Map Font to Unicode equivalent
-					if mapped, ok := FontMap[fontName]; ok {
-						if run.Font == nil {
-							run.Font = &excelize.Font{}
-						}
-						run.Font.Family = mapped
-					} else {
-						if run.Font == nil {
-							run.Font = &excelize.Font{}
-						}
-						run.Font.Family = DefaultFont
-					}
-				case converter.EncodingTCVN3:
-					text = p.tcvn3Preserver.converter.ToUnicode(run.Text)
-					if mapped, ok := FontMap[fontName]; ok {
-						if run.Font == nil {
-							run.Font = &excelize.Font{}
-						}
-						run.Font.Family = mapped
-					} else {
-						if run.Font == nil {
-							run.Font = &excelize.Font{}
-						}
-						run.Font.Family = DefaultFont
-					}
-				default:
-					text = run.Text // No change for unknown encoding
-				}
-
-				run.Text = text
-				newRuns = append(newRuns, run)
-			}
-			res.NewRuns = newRuns
-			res.Job.IsRich = true
-
-		} else {
-			// Plain text fallback (should rarely happen with new dispatcher logic)
-			res.Converted = job.Text
-			res.Job.IsRich = false
+		// Drain remaining jobs without converting once cancelled
+		if ctx.Err() != nil {
+			continue
 		}
 
+		// Worker only processes data, does NOT access p.f (not thread-safe)
+		start := time.Now()
+		if p.tracer != nil {
+			p.tracer.OnCellStart(ctx, job)
+		}
+		res := p.convertJob(job)
+		if p.tracer != nil {
+			p.tracer.OnCellEnd(ctx, job, time.Since(start))
+		}
 		p.results <- res
 	}
 }
+
+// convertJob converts the runs of one cell. It does not access p.f, so workers may call it.
+func (p *Processor) convertJob(job Job) Result {
+	res := Result{Job: job}
+	if len(job.RichText) == 0 {
+		// Plain text fallback (should rarely happen with new dispatcher logic)
+		res.Converted = job.Text
+		res.Job.IsRich = false
+		return res
+	}
+
+	var record func(int, string, Detection)
+	if p.detections != nil {
+		record = func(run int, fontName string, d Detection) { p.detections.Record(job, run, fontName, d) }
+	}
+	runs, changed := p.preserver.ProcessRichText(job.RichText, job.StyleFont, record)
+	// Leave the cell exactly as it is instead of rewriting it as rich text, which would
+	// set the synthetic run's font and size on plain text
+	if !changed {
+		res.NewRuns = job.RichText
+		res.Unchanged = true
+		return res
+	}
+	// Merge only after conversion, once mapped fonts can compare equal
+	res.NewRuns = MergeRuns(runs)
+	if runs, length, cut := truncateRuns(res.NewRuns, MaxExcelCellLength); cut {
+		res.NewRuns, res.TruncatedFrom = runs, length
+	}
+	return res
+}
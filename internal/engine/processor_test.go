@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"convert-vni-to-unicode/internal/converter"
 
 	"github.com/xuri/excelize/v2"
 )
@@ -45,8 +49,8 @@ func TestProcessor_Run(t *testing.T) {
 	}
 
 	// A3: TCVN3 Text (.VnTime)
-	// "Công ty" in TCVN3: "C\u00F6ng ty"
-	if err := f.SetCellValue(sheet, "A3", "C\u00F6ng ty"); err != nil {
+	// "Công ty" in TCVN3: "C\u00ABng ty"
+	if err := f.SetCellValue(sheet, "A3", "C\u00ABng ty"); err != nil {
 		t.Fatalf("failed to set cell value A3: %v", err)
 	}
 	styleID3, _ := f.NewStyle(&excelize.Style{
@@ -150,3 +154,248 @@ func TestProcessor_Run(t *testing.T) {
 
 	fmt.Printf("Integration Test Passed! Output: %s\n", outputFile)
 }
+
+func TestProcessor_MaxCellLength(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "long.xlsx")
+	blob := strings.Repeat("{\"k\":1}", 100)
+	writeWorkbook(t, inputFile, map[string]string{"A1": "Vi\u00D6t Nam", "A2": blob})
+
+	tests := []struct {
+		name        string
+		maxLen      int
+		wantSkipped int
+	}{
+		{name: "Guard disabled", maxLen: 0, wantSkipped: 0},
+		{name: "Long cell skipped", maxLen: 100, wantSkipped: 1},
+		{name: "Limit above cell length", maxLen: len(blob), wantSkipped: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc := NewProcessor(inputFile, "")
+			proc.SetMaxCellLength(tt.maxLen)
+			outputFile, err := proc.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			defer func() { _ = os.Remove(outputFile) }()
+
+			skipped := proc.SkippedCells()
+			if len(skipped) != tt.wantSkipped {
+				t.Fatalf("SkippedCells() = %+v, want %d entries", skipped, tt.wantSkipped)
+			}
+			if tt.wantSkipped > 0 && (skipped[0].Axis != "A2" || skipped[0].Length != len(blob)) {
+				t.Errorf("SkippedCells()[0] = %+v", skipped[0])
+			}
+
+			fOut, err := excelize.OpenFile(outputFile)
+			if err != nil {
+				t.Fatalf("failed to open output: %v", err)
+			}
+			defer func() { _ = fOut.Close() }()
+			if got, _ := fOut.GetCellValue("Sheet1", "A1"); got != "Việt Nam" {
+				t.Errorf("A1 = %q, want converted text", got)
+			}
+			if got, _ := fOut.GetCellValue("Sheet1", "A2"); got != blob {
+				t.Errorf("A2 was modified")
+			}
+		})
+	}
+}
+
+func TestProcessor_OutputPath(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	input := filepath.Join("data", "in", "book.xlsx")
+	tests := []struct {
+		name      string
+		outputDir string
+		format    string
+		want      string
+	}{
+		{name: "Next to input", outputDir: "", want: filepath.Join("data", "in", "book_output_2024_05_06_07_08_09.xlsx")},
+		{name: "Output dir", outputDir: "out", want: filepath.Join("out", "book_output_2024_05_06_07_08_09.xlsx")},
+		{name: "Custom format", outputDir: "", format: "yyyy-MM-dd", want: filepath.Join("data", "in", "book_output_2024-05-06.xlsx")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProcessor(input, "")
+			p.SetOutputDir(tt.outputDir)
+			if err := p.SetTimestampFormat(tt.format); err != nil {
+				t.Fatalf("SetTimestampFormat failed: %v", err)
+			}
+			if got := p.outputPath(now); got != tt.want {
+				t.Errorf("outputPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessor_SourceEncoding(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "viqr.xlsx")
+	writeWorkbook(t, inputFile, map[string]string{"A1": "Vie^.t Nam"})
+
+	tests := []struct {
+		name     string
+		encoding converter.EncodingType
+		want     string
+	}{
+		{name: "Auto detect leaves ASCII", encoding: converter.EncodingAuto, want: "Vie^.t Nam"},
+		{name: "Forced VIQR", encoding: converter.EncodingVIQR, want: "Việt Nam"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc := NewProcessor(inputFile, "")
+			if err := proc.SetSourceEncoding(tt.encoding); err != nil {
+				t.Fatalf("SetSourceEncoding failed: %v", err)
+			}
+			outputFile, err := proc.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			defer func() { _ = os.Remove(outputFile) }()
+
+			fOut, err := excelize.OpenFile(outputFile)
+			if err != nil {
+				t.Fatalf("failed to open output: %v", err)
+			}
+			defer func() { _ = fOut.Close() }()
+			if got, _ := fOut.GetCellValue("Sheet1", "A1"); got != tt.want {
+				t.Errorf("A1 = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := NewProcessor(inputFile, "").SetSourceEncoding("EBCDIC"); err == nil {
+		t.Error("SetSourceEncoding accepted an unsupported encoding")
+	}
+}
+
+func TestProcessor_LeavesUnicodeCells(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding converter.EncodingType
+	}{
+		{name: "Auto detect", encoding: converter.EncodingAuto},
+		{name: "Forced VNI", encoding: converter.EncodingVNI},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputFile := filepath.Join(t.TempDir(), "mixed.xlsx")
+			f := excelize.NewFile()
+			vni, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Family: "VNI-Times", Size: 14}})
+			if err != nil {
+				t.Fatalf("NewStyle failed: %v", err)
+			}
+			// A1 was retyped in Unicode but kept its legacy font; A2 is still VNI
+			cells := map[string]string{"A1": "Công ty Việt Nam", "A2": "Vi\u00D6t Nam"}
+			for axis, text := range cells {
+				if err := f.SetCellValue("Sheet1", axis, text); err != nil {
+					t.Fatalf("SetCellValue failed: %v", err)
+				}
+				if err := f.SetCellStyle("Sheet1", axis, axis, vni); err != nil {
+					t.Fatalf("SetCellStyle failed: %v", err)
+				}
+			}
+			if err := f.SaveAs(inputFile); err != nil {
+				t.Fatalf("failed to save: %v", err)
+			}
+			_ = f.Close()
+
+			proc := NewProcessor(inputFile, "")
+			if err := proc.SetSourceEncoding(tt.encoding); err != nil {
+				t.Fatalf("SetSourceEncoding failed: %v", err)
+			}
+			outputFile, err := proc.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			defer func() { _ = os.Remove(outputFile) }()
+
+			fOut, err := excelize.OpenFile(outputFile)
+			if err != nil {
+				t.Fatalf("failed to open output: %v", err)
+			}
+			defer func() { _ = fOut.Close() }()
+			if got, _ := fOut.GetCellValue("Sheet1", "A1"); got != "Công ty Việt Nam" {
+				t.Errorf("A1 = %q, want it left alone", got)
+			}
+			if runs, _ := fOut.GetCellRichText("Sheet1", "A1"); hasRunFonts(runs) {
+				t.Errorf("A1 was rewritten as rich text: %+v", runs)
+			}
+			if got := cellStyleFont(t, fOut, "A1"); got != "VNI-Times" {
+				t.Errorf("A1 font = %q, want VNI-Times", got)
+			}
+			if got, _ := fOut.GetCellValue("Sheet1", "A2"); got != "Việt Nam" {
+				t.Errorf("A2 = %q, want Việt Nam", got)
+			}
+		})
+	}
+}
+
+func TestProcessor_KeepsStyleFontOfPlainCells(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "styled.xlsx")
+	f := excelize.NewFile()
+	styleFont := excelize.Font{
+		Family: "VNI-Times", Size: 14, Bold: true, Italic: true,
+		Underline: "single", Strike: true, Color: "FF0000",
+	}
+	style, err := f.NewStyle(&excelize.Style{Font: &styleFont})
+	if err != nil {
+		t.Fatalf("NewStyle failed: %v", err)
+	}
+	if err := f.SetCellValue("Sheet1", "A1", "Vi\u00D6t Nam"); err != nil {
+		t.Fatalf("SetCellValue failed: %v", err)
+	}
+	if err := f.SetCellStyle("Sheet1", "A1", "A1", style); err != nil {
+		t.Fatalf("SetCellStyle failed: %v", err)
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	_ = f.Close()
+
+	outputFile, err := NewProcessor(inputFile, "").Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+
+	fOut, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = fOut.Close() }()
+	runs, err := fOut.GetCellRichText("Sheet1", "A1")
+	if err != nil || len(runs) != 1 || runs[0].Font == nil {
+		t.Fatalf("GetCellRichText = %+v, %v; want one run with a font", runs, err)
+	}
+	if runs[0].Text != "Việt Nam" {
+		t.Errorf("text = %q, want Việt Nam", runs[0].Text)
+	}
+	got := runs[0].Font
+	if got.Family != "Times New Roman" || got.Size != 14 || !got.Bold || !got.Italic ||
+		got.Underline != "single" || !got.Strike || !strings.HasSuffix(got.Color, "FF0000") {
+		t.Errorf("font = %+v, want the style font with family Times New Roman", *got)
+	}
+}
+
+func TestProcessor_RepeatedRunsKeepOutputs(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "repeat.xlsx")
+	writeWorkbook(t, inputFile, map[string]string{"A1": "Vi\u00D6t Nam"})
+
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		outputFile, err := NewProcessor(inputFile, "").Run(context.Background())
+		if err != nil {
+			t.Fatalf("Run %d failed: %v", i, err)
+		}
+		if seen[outputFile] {
+			t.Fatalf("Run %d overwrote %s", i, outputFile)
+		}
+		seen[outputFile] = true
+		fOut, err := excelize.OpenFile(outputFile)
+		if err != nil {
+			t.Fatalf("output %s is not a valid workbook: %v", outputFile, err)
+		}
+		_ = fOut.Close()
+	}
+}
@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// countCells returns the number of cells Run will convert in sheets.
+// Why: A fast pre-scan gives progress a real denominator, so the UI can show
+// a percentage and an ETA instead of a bare counter.
+// It reads cell values only (no styles or rich text), which is much cheaper than conversion.
+func (p *Processor) countCells(ctx context.Context, sheets []string) int {
+	total := 0
+	for _, sheet := range sheets {
+		rows, err := p.f.Rows(sheet)
+		if err != nil {
+			slog.Error("failed to pre-scan rows", "sheet", sheet, "error", err)
+			continue
+		}
+		rowIdx := 0
+		for rows.Next() {
+			rowIdx++
+			if ctx.Err() != nil || p.cellFilter.pastLastRow(rowIdx) {
+				break
+			}
+			cols, err := rows.Columns()
+			if err != nil {
+				continue
+			}
+			for colIdx, text := range cols {
+				if strings.TrimSpace(text) == "" || !p.cellFilter.Contains(colIdx+1, rowIdx) {
+					continue
+				}
+				if _, skip := p.oversized(text); skip {
+					continue
+				}
+				// Must match walkCells, or progress never reaches 100%
+				axis, err := excelize.CoordinatesToCellName(colIdx+1, rowIdx)
+				if err != nil {
+					continue
+				}
+				if cellType, err := p.f.GetCellType(sheet, axis); err != nil || !isTextCell(cellType) {
+					continue
+				}
+				total++
+			}
+		}
+		if err := rows.Close(); err != nil {
+			slog.Error("failed to close rows iterator", "sheet", sheet, "error", err)
+		}
+	}
+	return total
+}
+
+// progressPercent returns processed/total as a percentage in [0, 100].
+func progressPercent(processed, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	pct := float64(processed) * 100 / float64(total)
+	if pct > 100 {
+		return 100
+	}
+	return pct
+}
+
+// estimateETA extrapolates the remaining time from the rate so far.
+// Returns 0 when there is not enough information yet.
+func estimateETA(elapsed time.Duration, processed, total int) time.Duration {
+	if processed <= 0 || total <= processed {
+		return 0
+	}
+	perCell := elapsed / time.Duration(processed)
+	return perCell * time.Duration(total-processed)
+}
@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestProgressPercent(t *testing.T) {
+	tests := []struct {
+		name             string
+		processed, total int
+		want             float64
+	}{
+		{name: "Unknown total", processed: 5, total: 0, want: 0},
+		{name: "Half", processed: 5, total: 10, want: 50},
+		{name: "Done", processed: 10, total: 10, want: 100},
+		{name: "Clamped", processed: 11, total: 10, want: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := progressPercent(tt.processed, tt.total); got != tt.want {
+				t.Errorf("progressPercent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateETA(t *testing.T) {
+	tests := []struct {
+		name             string
+		elapsed          time.Duration
+		processed, total int
+		want             time.Duration
+	}{
+		{name: "Nothing processed", elapsed: time.Second, processed: 0, total: 10, want: 0},
+		{name: "Quarter done", elapsed: time.Second, processed: 25, total: 100, want: 3 * time.Second},
+		{name: "Finished", elapsed: time.Second, processed: 10, total: 10, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimateETA(tt.elapsed, tt.processed, tt.total); got != tt.want {
+				t.Errorf("estimateETA() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessor_CountCells(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "count.xlsx")
+	writeWorkbook(t, path, map[string]string{
+		"A1": "Vi\u00D6t Nam",
+		"B1": "abc",
+		"C1": "   ",
+		"A2": strings.Repeat("x", 50),
+	})
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	tests := []struct {
+		name   string
+		maxLen int
+		want   int
+	}{
+		{name: "All non-empty cells", maxLen: 0, want: 3},
+		{name: "Oversized cells excluded", maxLen: 10, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProcessor(path, "")
+			p.f = f
+			p.SetMaxCellLength(tt.maxLen)
+			if got := p.countCells(context.Background(), []string{"Sheet1"}); got != tt.want {
+				t.Errorf("countCells() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessor_RunReportsPercent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pct.xlsx")
+	writeWorkbook(t, path, map[string]string{"A1": "Vi\u00D6t Nam", "A2": "abc", "A3": "C\u00ABng ty"})
+
+	p := NewProcessor(path, "")
+	progress := make(chan float64, 10)
+	status := make(chan Status, 10)
+	p.SetProgressChan(progress)
+	p.SetStatusChan(status)
+	if _, err := p.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	close(progress)
+	close(status)
+
+	var last float64
+	for pct := range progress {
+		if pct < last {
+			t.Errorf("progress went backwards: %v after %v", pct, last)
+		}
+		last = pct
+	}
+	if last != 100 {
+		t.Errorf("final progress = %v, want 100", last)
+	}
+	for st := range status {
+		if st.Total != 3 {
+			t.Errorf("Status.Total = %d, want 3", st.Total)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// QueueOrder controls the order in which batch inputs are processed.
+// Why: Operators want quick wins (small files) or the newest files done first in long batches.
+type QueueOrder string
+
+const (
+	// OrderManual keeps the order the paths were given in
+	OrderManual QueueOrder = "manual"
+	// OrderSmallestFirst processes the smallest files first
+	OrderSmallestFirst QueueOrder = "smallest-first"
+	// OrderNewestFirst processes the most recently modified files first
+	OrderNewestFirst QueueOrder = "newest-first"
+)
+
+// ParseQueueOrder validates a queue order name. An empty name selects OrderManual.
+func ParseQueueOrder(s string) (QueueOrder, error) {
+	switch QueueOrder(s) {
+	case "", OrderManual:
+		return OrderManual, nil
+	case OrderSmallestFirst, OrderNewestFirst:
+		return QueueOrder(s), nil
+	default:
+		return "", fmt.Errorf("unknown queue order %q", s)
+	}
+}
+
+// OrderPaths returns a reordered copy of paths. Files that cannot be stat'ed
+// keep their relative order and go last, so the batch itself reports the error.
+func OrderPaths(paths []string, order QueueOrder) []string {
+	out := make([]string, len(paths))
+	copy(out, paths)
+	if order == OrderManual || order == "" {
+		return out
+	}
+
+	infos := make(map[string]os.FileInfo, len(out))
+	for _, path := range out {
+		if info, err := os.Stat(path); err == nil {
+			infos[path] = info
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		a, aok := infos[out[i]]
+		b, bok := infos[out[j]]
+		if !aok || !bok {
+			return aok && !bok
+		}
+		switch order {
+		case OrderSmallestFirst:
+			return a.Size() < b.Size()
+		case OrderNewestFirst:
+			return a.ModTime().After(b.ModTime())
+		default:
+			return false
+		}
+	})
+	return out
+}
@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOrderPaths(t *testing.T) {
+	dir := t.TempDir()
+	big := filepath.Join(dir, "big.xlsx")
+	small := filepath.Join(dir, "small.xlsx")
+	missing := filepath.Join(dir, "missing.xlsx")
+
+	if err := os.WriteFile(big, make([]byte, 100), 0600); err != nil {
+		t.Fatalf("failed to write big: %v", err)
+	}
+	if err := os.WriteFile(small, make([]byte, 10), 0600); err != nil {
+		t.Fatalf("failed to write small: %v", err)
+	}
+	// big is the newest file
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(small, old, old); err != nil {
+		t.Fatalf("failed to set mtime: %v", err)
+	}
+
+	input := []string{missing, big, small}
+	tests := []struct {
+		name     string
+		order    QueueOrder
+		expected []string
+	}{
+		{name: "Manual", order: OrderManual, expected: []string{missing, big, small}},
+		{name: "Smallest first", order: OrderSmallestFirst, expected: []string{small, big, missing}},
+		{name: "Newest first", order: OrderNewestFirst, expected: []string{big, small, missing}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OrderPaths(input, tt.order)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("OrderPaths() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseQueueOrder(t *testing.T) {
+	if order, err := ParseQueueOrder(""); err != nil || order != OrderManual {
+		t.Errorf("ParseQueueOrder(\"\") = %s, %v", order, err)
+	}
+	if _, err := ParseQueueOrder("random"); err == nil {
+		t.Error("expected error for unknown order")
+	}
+}
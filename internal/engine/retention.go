@@ -0,0 +1,169 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// outputMarker separates the source name from the timestamp in output names (see outputPath).
+const outputMarker = "_output_"
+
+// RetentionPolicy limits the outputs kept in an output folder. Zero fields keep everything.
+type RetentionPolicy struct {
+	// KeepLast keeps the newest KeepLast outputs of each source workbook
+	KeepLast int
+	// MaxAge deletes outputs last modified longer ago than MaxAge
+	MaxAge time.Duration
+	// TimestampLayout is the Go layout of the outputs' name suffix (see
+	// ParseTimestampFormat); empty means the default one
+	TimestampLayout string
+}
+
+// Enabled reports whether the policy deletes anything at all.
+func (r RetentionPolicy) Enabled() bool {
+	return r.KeepLast > 0 || r.MaxAge > 0
+}
+
+// Validate rejects negative limits.
+func (r RetentionPolicy) Validate() error {
+	if r.KeepLast < 0 {
+		return fmt.Errorf("invalid retention: keep last %d outputs", r.KeepLast)
+	}
+	if r.MaxAge < 0 {
+		return fmt.Errorf("invalid retention: maximum age %s", r.MaxAge)
+	}
+	return nil
+}
+
+// outputReportSuffixes are the reports the converter writes next to an output, named
+// "<output stem><suffix>".
+var outputReportSuffixes = []string{
+	"_timing.csv", "_detection.csv", "_fonts.csv", "_spelling.csv",
+	"_changes.xlsx", "_changes.csv", "_changes.json", ".pdf",
+}
+
+// retainedOutput is an output workbook found by ApplyRetention, with its reports.
+type retainedOutput struct {
+	path     string
+	source   string
+	modTime  time.Time
+	sidecars []string
+}
+
+// ApplyRetention deletes the outputs in dir that policy no longer keeps, together with the
+// reports the converter wrote next to them (e.g. "<output>_timing.csv"), and returns the
+// deleted paths. Only workbooks named exactly like outputs are considered: the suffix after
+// "_output_" must parse with policy.TimestampLayout, optionally followed by the "_N" added
+// on a name collision. Other files and subfolders are left alone. Files that cannot be
+// deleted are skipped and reported in the error after the rest are cleaned up.
+// Why: Watch folders convert every new version of a workbook; without a limit the output
+// folder grows until the share runs out of space.
+func ApplyRetention(dir string, policy RetentionPolicy, now time.Time) ([]string, error) {
+	if !policy.Enabled() {
+		return nil, nil
+	}
+	layout := policy.TimestampLayout
+	if layout == "" {
+		layout = defaultTimestampLayout
+	}
+	outputs, err := listOutputs(dir, layout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Newest first, so the first KeepLast of each source are the ones kept
+	sort.Slice(outputs, func(i, j int) bool {
+		if !outputs[i].modTime.Equal(outputs[j].modTime) {
+			return outputs[i].modTime.After(outputs[j].modTime)
+		}
+		return outputs[i].path > outputs[j].path
+	})
+	kept := make(map[string]int)
+	var removed []string
+	var errs []error
+	for _, out := range outputs {
+		expired := policy.MaxAge > 0 && now.Sub(out.modTime) > policy.MaxAge
+		overLimit := policy.KeepLast > 0 && kept[out.source] >= policy.KeepLast
+		if !expired && !overLimit {
+			kept[out.source]++
+			continue
+		}
+		for _, path := range append([]string{out.path}, out.sidecars...) {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+				continue
+			}
+			removed = append(removed, path)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return removed, fmt.Errorf("failed to delete old outputs: %w", err)
+	}
+	return removed, nil
+}
+
+// listOutputs returns the output workbooks in dir, named with the timestamp layout, with
+// their reports attached.
+func listOutputs(dir, layout string) ([]*retainedOutput, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read output folder: %w", err)
+	}
+
+	files := make(map[string]bool, len(entries))
+	var outputs []*retainedOutput
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		name := e.Name()
+		files[name] = true
+		stem := strings.TrimSuffix(name, filepath.Ext(name))
+		i := strings.LastIndex(stem, outputMarker)
+		if !IsWorkbook(name) || i <= 0 || !isOutputTimestamp(stem[i+len(outputMarker):], layout) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue // Deleted since ReadDir
+		}
+		outputs = append(outputs, &retainedOutput{
+			path:    filepath.Join(dir, name),
+			source:  stem[:i] + strings.ToLower(filepath.Ext(name)),
+			modTime: info.ModTime(),
+		})
+	}
+
+	for _, out := range outputs {
+		stem := strings.TrimSuffix(filepath.Base(out.path), filepath.Ext(out.path))
+		for _, suffix := range outputReportSuffixes {
+			if files[stem+suffix] {
+				out.sidecars = append(out.sidecars, filepath.Join(dir, stem+suffix))
+			}
+		}
+	}
+	return outputs, nil
+}
+
+// isOutputTimestamp reports whether suffix is a timestamp in layout, optionally followed
+// by the "_N" collision suffix (see reserveOutputPath).
+func isOutputTimestamp(suffix, layout string) bool {
+	if _, err := time.Parse(layout, suffix); err == nil {
+		return true
+	}
+	i := strings.LastIndexByte(suffix, '_')
+	if i <= 0 {
+		return false
+	}
+	if n, err := strconv.Atoi(suffix[i+1:]); err != nil || n < 2 {
+		return false
+	}
+	_, err := time.Parse(layout, suffix[:i])
+	return err == nil
+}
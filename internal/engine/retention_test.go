@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestApplyRetention(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	files := []struct {
+		name string
+		age  time.Duration
+	}{
+		{name: "book_output_2024_06_01.xlsx", age: time.Hour},
+		{name: "book_output_2024_05_31.xlsx", age: day},
+		{name: "book_output_2024_05_31_timing.csv", age: day},
+		{name: "book_output_2024_05_31_2.xlsx", age: day + time.Hour},
+		{name: "book_output_2024_05_31_2_changes.json", age: day + time.Hour},
+		{name: "book_output_2024_04_01.xlsx", age: 61 * day},
+		{name: "other_output_2024_04_01.xlsx", age: 61 * day},
+		{name: "book_output_2024_04_01_notes.txt", age: 61 * day}, // not a report the converter writes
+		{name: "book.xlsm", age: 90 * day},                        // not an output
+		{name: "notes_output_2024.txt", age: 90 * day},            // no output it belongs to
+		{name: "budget_output_final.xlsx", age: 90 * day},         // suffix is not a timestamp
+		{name: "budget_output_final_notes.txt", age: 90 * day},
+		{name: "budget_output_2024_04_01_x.xlsx", age: 90 * day}, // not a collision suffix
+	}
+
+	tests := []struct {
+		name    string
+		policy  RetentionPolicy
+		removed []string
+	}{
+		{name: "Disabled", policy: RetentionPolicy{}},
+		{
+			name:   "KeepLast",
+			policy: RetentionPolicy{KeepLast: 2},
+			removed: []string{
+				"book_output_2024_04_01.xlsx",
+				"book_output_2024_05_31_2.xlsx",
+				"book_output_2024_05_31_2_changes.json",
+			},
+		},
+		{
+			name:    "MaxAge",
+			policy:  RetentionPolicy{MaxAge: 30 * day},
+			removed: []string{"book_output_2024_04_01.xlsx", "other_output_2024_04_01.xlsx"},
+		},
+		{
+			name:   "Both",
+			policy: RetentionPolicy{KeepLast: 1, MaxAge: 30 * day},
+			removed: []string{
+				"book_output_2024_04_01.xlsx",
+				"book_output_2024_05_31.xlsx",
+				"book_output_2024_05_31_2.xlsx",
+				"book_output_2024_05_31_2_changes.json",
+				"book_output_2024_05_31_timing.csv",
+				"other_output_2024_04_01.xlsx",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.policy.TimestampLayout = "2006_01_02"
+			dir := t.TempDir()
+			for _, f := range files {
+				path := filepath.Join(dir, f.name)
+				if err := os.WriteFile(path, nil, 0600); err != nil {
+					t.Fatalf("failed to write %s: %v", f.name, err)
+				}
+				modTime := now.Add(-f.age)
+				if err := os.Chtimes(path, modTime, modTime); err != nil {
+					t.Fatalf("failed to set time of %s: %v", f.name, err)
+				}
+			}
+
+			removed, err := ApplyRetention(dir, tt.policy, now)
+			if err != nil {
+				t.Fatalf("ApplyRetention failed: %v", err)
+			}
+			var got []string
+			for _, path := range removed {
+				got = append(got, filepath.Base(path))
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.removed) {
+				t.Errorf("removed = %q, want %q", got, tt.removed)
+			}
+			for _, name := range got {
+				if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+					t.Errorf("%s still exists", name)
+				}
+			}
+		})
+	}
+}
+
+func TestRetentionPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetentionPolicy
+		wantErr bool
+	}{
+		{name: "Zero", policy: RetentionPolicy{}},
+		{name: "Valid", policy: RetentionPolicy{KeepLast: 3, MaxAge: time.Hour}},
+		{name: "Negative count", policy: RetentionPolicy{KeepLast: -1}, wantErr: true},
+		{name: "Negative age", policy: RetentionPolicy{MaxAge: -time.Hour}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package engine
+
+import "github.com/xuri/excelize/v2"
+
+// MergeRuns joins adjacent runs whose fonts are identical.
+// Why: Old editors split text into dozens of identically formatted runs; after conversion
+// (and font mapping) they are redundant, bloat the output and are awkward to edit.
+// Empty runs are dropped unless the cell would otherwise have no runs at all.
+func MergeRuns(runs []excelize.RichTextRun) []excelize.RichTextRun {
+	if len(runs) < 2 {
+		return runs
+	}
+	merged := make([]excelize.RichTextRun, 0, len(runs))
+	for _, run := range runs {
+		if run.Text == "" {
+			continue
+		}
+		if last := len(merged) - 1; last >= 0 && fontsEqual(merged[last].Font, run.Font) {
+			merged[last].Text += run.Text
+			continue
+		}
+		merged = append(merged, run)
+	}
+	if len(merged) == 0 {
+		return runs[:1]
+	}
+	return merged
+}
+
+// fontsEqual compares two run fonts by value. A nil font only equals another nil font.
+func fontsEqual(a, b *excelize.Font) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Bold == b.Bold &&
+		a.Italic == b.Italic &&
+		a.Underline == b.Underline &&
+		a.Family == b.Family &&
+		a.Size == b.Size &&
+		a.Strike == b.Strike &&
+		a.Color == b.Color &&
+		a.ColorIndexed == b.ColorIndexed &&
+		intPtrEqual(a.ColorTheme, b.ColorTheme) &&
+		a.ColorTint == b.ColorTint &&
+		a.VertAlign == b.VertAlign &&
+		intPtrEqual(a.Charset, b.Charset)
+}
+
+func intPtrEqual(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
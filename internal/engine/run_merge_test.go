@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestMergeRuns(t *testing.T) {
+	theme1, theme1b, theme2 := 1, 1, 2
+	times := func() *excelize.Font { return &excelize.Font{Family: "Times New Roman", Size: 12} }
+	bold := &excelize.Font{Family: "Times New Roman", Size: 12, Bold: true}
+
+	tests := []struct {
+		name  string
+		input []excelize.RichTextRun
+		want  []string // texts of the resulting runs
+	}{
+		{
+			name:  "Single run untouched",
+			input: []excelize.RichTextRun{{Text: "a", Font: times()}},
+			want:  []string{"a"},
+		},
+		{
+			name:  "Equal fonts merged",
+			input: []excelize.RichTextRun{{Text: "Vi", Font: times()}, {Text: "ệt", Font: times()}, {Text: " Nam", Font: times()}},
+			want:  []string{"Việt Nam"},
+		},
+		{
+			name:  "Different fonts kept apart",
+			input: []excelize.RichTextRun{{Text: "a", Font: times()}, {Text: "b", Font: bold}, {Text: "c", Font: times()}},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "Nil fonts merged only with nil",
+			input: []excelize.RichTextRun{{Text: "a"}, {Text: "b"}, {Text: "c", Font: times()}},
+			want:  []string{"ab", "c"},
+		},
+		{
+			name: "Pointer fields compared by value",
+			input: []excelize.RichTextRun{
+				{Text: "a", Font: &excelize.Font{ColorTheme: &theme1}},
+				{Text: "b", Font: &excelize.Font{ColorTheme: &theme1b}},
+				{Text: "c", Font: &excelize.Font{ColorTheme: &theme2}},
+			},
+			want: []string{"ab", "c"},
+		},
+		{
+			name:  "Empty runs dropped",
+			input: []excelize.RichTextRun{{Text: "a", Font: times()}, {Text: "", Font: bold}, {Text: "b", Font: times()}},
+			want:  []string{"ab"},
+		},
+		{
+			name:  "All empty keeps one run",
+			input: []excelize.RichTextRun{{Text: "", Font: times()}, {Text: "", Font: bold}},
+			want:  []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeRuns(tt.input)
+			if len(got) != len(tt.want) {
+				t.Fatalf("MergeRuns() returned %d runs, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, run := range got {
+				if run.Text != tt.want[i] {
+					t.Errorf("run %d text = %q, want %q", i, run.Text, tt.want[i])
+				}
+			}
+		})
+	}
+}
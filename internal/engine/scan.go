@@ -0,0 +1,155 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Recommended triage actions.
+const (
+	ActionConvert = "convert"
+	ActionSkip    = "skip"
+	ActionReview  = "review"
+)
+
+// TriageEntry is one row of a folder triage report.
+type TriageEntry struct {
+	Path             string                 `json:"path"`
+	Size             int64                  `json:"size"`
+	Sheets           int                    `json:"sheets"`
+	CellsScanned     int                    `json:"cellsScanned"`
+	LegacyCells      int                    `json:"legacyCells"`
+	DominantEncoding converter.EncodingType `json:"dominantEncoding"`
+	Action           string                 `json:"action"`
+	Error            string                 `json:"error,omitempty"`
+}
+
+// ScanFolder walks root recursively and checks every workbook it finds.
+// Why: The first step of every migration project is knowing which files need work.
+// Unreadable workbooks are reported with ActionReview instead of aborting the scan.
+// Files matched by filter (lock files, temps) are left out. throttle may be nil.
+func ScanFolder(ctx context.Context, root string, filter FileFilter, throttle *IOThrottle) ([]TriageEntry, error) {
+	var entries []TriageEntry
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if d.IsDir() || !IsWorkbook(path) {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || filter.Skip(d.Name(), info.Size()) {
+			return nil
+		}
+		entries = append(entries, triageFile(ctx, path, d, throttle))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan folder: %w", err)
+	}
+	return entries, nil
+}
+
+// IsWorkbook reports whether path has an extension excelize can open.
+func IsWorkbook(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx", ".xlsm":
+		return true
+	default:
+		return false
+	}
+}
+
+func triageFile(ctx context.Context, path string, d fs.DirEntry, throttle *IOThrottle) TriageEntry {
+	entry := TriageEntry{Path: path, DominantEncoding: converter.EncodingUnknown}
+	if info, err := d.Info(); err == nil {
+		entry.Size = info.Size()
+	}
+
+	proc := NewProcessor(path, "")
+	proc.SetIOThrottle(throttle)
+	report, err := proc.Check(ctx)
+	if err != nil {
+		entry.Action = ActionReview
+		entry.Error = err.Error()
+		return entry
+	}
+
+	entry.Sheets = len(report.Sheets)
+	entry.CellsScanned = report.CellsScanned
+	entry.LegacyCells = report.LegacyCells
+	entry.DominantEncoding = dominantEncoding(report.ByEncoding)
+	entry.Action = ActionSkip
+	if report.HasLegacy() {
+		entry.Action = ActionConvert
+	}
+	return entry
+}
+
+// dominantEncoding returns the encoding with the most cells (ties broken by name).
+func dominantEncoding(counts map[converter.EncodingType]int) converter.EncodingType {
+	encodings := make([]converter.EncodingType, 0, len(counts))
+	for enc := range counts {
+		encodings = append(encodings, enc)
+	}
+	sort.Slice(encodings, func(i, j int) bool { return encodings[i] < encodings[j] })
+
+	best, bestCount := converter.EncodingUnknown, 0
+	for _, enc := range encodings {
+		if counts[enc] > bestCount {
+			best, bestCount = enc, counts[enc]
+		}
+	}
+	return best
+}
+
+// WriteTriageReport saves the entries as a spreadsheet at outputPath.
+func WriteTriageReport(outputPath string, entries []TriageEntry) (err error) {
+	f := excelize.NewFile()
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	sheet := f.GetSheetName(0)
+	header := []any{"File", "Size (bytes)", "Sheets", "Cells Scanned", "Legacy Cells", "Dominant Encoding",
+		"Recommended Action", "Error"}
+	if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("failed to create header style: %w", err)
+	}
+	if err := f.SetRowStyle(sheet, 1, 1, bold); err != nil {
+		return fmt.Errorf("failed to style header: %w", err)
+	}
+
+	for i, e := range entries {
+		row := []any{e.Path, e.Size, e.Sheets, e.CellsScanned, e.LegacyCells, string(e.DominantEncoding),
+			e.Action, e.Error}
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return fmt.Errorf("failed to compute cell name: %w", err)
+		}
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return fmt.Errorf("failed to write row %d: %w", i+2, err)
+		}
+	}
+
+	if err := f.SaveAs(outputPath); err != nil {
+		return fmt.Errorf("failed to save triage report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func writeWorkbook(t *testing.T, path string, cells map[string]string) {
+	t.Helper()
+	f := excelize.NewFile()
+	for axis, v := range cells {
+		if err := f.SetCellValue("Sheet1", axis, v); err != nil {
+			t.Fatalf("failed to set %s: %v", axis, err)
+		}
+	}
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("failed to save %s: %v", path, err)
+	}
+	_ = f.Close()
+}
+
+func TestScanFolder(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0750); err != nil {
+		t.Fatalf("failed to create subdir: %v", err)
+	}
+	writeWorkbook(t, filepath.Join(root, "legacy.xlsx"), map[string]string{"A1": "Vi\u00D6t Nam", "A2": "Vi\u00D6t"})
+	writeWorkbook(t, filepath.Join(root, "sub", "clean.xlsx"), map[string]string{"A1": "Hello"})
+	if err := os.WriteFile(filepath.Join(root, "broken.xlsx"), []byte("not a zip"), 0600); err != nil {
+		t.Fatalf("failed to write broken file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "notes.txt"), []byte("ignored"), 0600); err != nil {
+		t.Fatalf("failed to write txt file: %v", err)
+	}
+	// Excel lock file and a zero-byte temp are filtered out
+	if err := os.WriteFile(filepath.Join(root, "~$legacy.xlsx"), []byte("lock"), 0600); err != nil {
+		t.Fatalf("failed to write lock file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "sub", "empty.xlsx"), nil, 0600); err != nil {
+		t.Fatalf("failed to write empty file: %v", err)
+	}
+
+	entries, err := ScanFolder(context.Background(), root, DefaultFileFilter(), nil)
+	if err != nil {
+		t.Fatalf("ScanFolder failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("entry count mismatch. Got %d, want 3", len(entries))
+	}
+
+	byName := map[string]TriageEntry{}
+	for _, e := range entries {
+		byName[filepath.Base(e.Path)] = e
+	}
+	if e := byName["legacy.xlsx"]; e.Action != ActionConvert || e.LegacyCells != 2 ||
+		e.DominantEncoding != converter.EncodingVNI {
+		t.Errorf("legacy.xlsx entry wrong: %+v", e)
+	}
+	if e := byName["clean.xlsx"]; e.Action != ActionSkip {
+		t.Errorf("clean.xlsx action = %s, want %s", e.Action, ActionSkip)
+	}
+	if e := byName["broken.xlsx"]; e.Action != ActionReview || e.Error == "" {
+		t.Errorf("broken.xlsx entry wrong: %+v", e)
+	}
+
+	reportPath := filepath.Join(t.TempDir(), "triage.xlsx")
+	if err := WriteTriageReport(reportPath, entries); err != nil {
+		t.Fatalf("WriteTriageReport failed: %v", err)
+	}
+	f, err := excelize.OpenFile(reportPath)
+	if err != nil {
+		t.Fatalf("failed to open report: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	rows, err := f.GetRows("Sheet1")
+	if err != nil {
+		t.Fatalf("GetRows failed: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Errorf("report row count = %d, want 4", len(rows))
+	}
+}
@@ -0,0 +1,41 @@
+package engine
+
+// SelectedCell is one cell of a selection converted by ConvertCells.
+type SelectedCell struct {
+	Text string
+	// Font is the font family of the cell; empty when the caller does not know it
+	Font string
+}
+
+// ConvertedCell is the conversion of one SelectedCell.
+type ConvertedCell struct {
+	Text string
+	// Font is the font family to apply to the cell; empty when the text was not converted
+	Font      string
+	Detection Detection
+}
+
+// ConvertCells converts cells the way a workbook run converts string cells, each detected
+// on its own text and font, configured by opts.
+// Why: An Office add-in converting the selected range of an open workbook has cell values
+// and fonts, not a file, and needs the same result as converting the saved workbook.
+func ConvertCells(cells []SelectedCell, opts ...Option) ([]ConvertedCell, error) {
+	p, err := newProcessorWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]ConvertedCell, len(cells))
+	for i, cell := range cells {
+		converted[i] = ConvertedCell{Text: cell.Text}
+		if cell.Text == "" {
+			continue
+		}
+		d := p.preserver.Detect(cell.Font, cell.Text)
+		converted[i].Detection = d
+		if !p.preserver.Supports(d.Encoding) {
+			continue
+		}
+		converted[i].Text, converted[i].Font = p.preserver.ConvertAs(d.Encoding, cell.Font, cell.Text)
+	}
+	return converted, nil
+}
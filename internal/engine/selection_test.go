@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+func TestConvertCells(t *testing.T) {
+	cells := []SelectedCell{
+		{Text: "Vi\u00D6t Nam", Font: "VNI-Times"},
+		{Text: "C\u00ABng ty", Font: ".VnTime"},
+		{Text: "Vi\u1EC7t Nam", Font: "Arial"},
+		{Text: "Hello"},
+		{},
+	}
+	want := []ConvertedCell{
+		{Text: "Vi\u1EC7t Nam", Font: "Times New Roman", Detection: Detection{Encoding: converter.EncodingVNI, Rule: RuleFontPrefix}},
+		{Text: "C\u00F4ng ty", Font: "Times New Roman", Detection: Detection{Encoding: converter.EncodingTCVN3, Rule: RuleFontPrefix}},
+		{Text: "Vi\u1EC7t Nam", Detection: Detection{Encoding: converter.EncodingUnknown, Rule: RuleUnicode}},
+		{Text: "Hello", Detection: Detection{Encoding: converter.EncodingUnknown, Rule: RuleNone}},
+		{},
+	}
+	got, err := ConvertCells(cells)
+	if err != nil {
+		t.Fatalf("ConvertCells failed: %v", err)
+	}
+	for i := range want {
+		if got[i].Text != want[i].Text || got[i].Font != want[i].Font ||
+			got[i].Detection.Encoding != want[i].Detection.Encoding || got[i].Detection.Rule != want[i].Detection.Rule {
+			t.Errorf("cell %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := ConvertCells(cells, WithSourceEncoding("EBCDIC")); err == nil {
+		t.Error("ConvertCells() accepted an unknown encoding")
+	}
+}
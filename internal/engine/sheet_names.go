@@ -0,0 +1,326 @@
+package engine
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// RenamedSheet records a sheet tab renamed from a legacy encoding to Unicode.
+type RenamedSheet struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// renameLegacySheets renames legacy-encoded sheets to Unicode and updates the formulas,
+// defined names, data validations, conditional formats and charts referring to them. It
+// must run on the collector goroutine, after all cells are written.
+// Why: Tabs like "Baùo caùo" are as unreadable as the cells; excelize only updates plain
+// ranges in defined names on rename, so every reference is rewritten here.
+func (p *Processor) renameLegacySheets(sheets []string) []RenamedSheet {
+	plans := p.planSheetRenames(p.f.GetSheetList(), sheets)
+	if len(plans) == 0 {
+		return nil
+	}
+	// Defined names are rewritten from their text before the renames, not from what
+	// SetSheetName left behind, so one rename's output is never taken for another's input
+	definedNames := p.definedNameFormulas()
+
+	var renamed []RenamedSheet
+	for _, r := range plans {
+		if err := p.f.SetSheetName(r.From, r.To); err != nil {
+			slog.Warn("failed to rename sheet", "sheet", r.From, "target", r.To, "error", err)
+			p.errs.add(r.From, "", StageRename, err)
+			continue
+		}
+		renamed = append(renamed, r)
+	}
+
+	if len(renamed) > 0 {
+		p.renameFormulaReferences(renamed, definedNames)
+	}
+	return renamed
+}
+
+// planSheetRenames returns the Unicode names of the legacy-encoded sheets among sheets,
+// skipping names already taken in all.
+func (p *Processor) planSheetRenames(all, sheets []string) []RenamedSheet {
+	existing := make(map[string]bool)
+	for _, name := range all {
+		existing[strings.ToLower(name)] = true
+	}
+
+	var renames []RenamedSheet
+	for _, sheet := range sheets {
+		target, _ := p.preserver.ConvertRun("", sheet)
+		if target == sheet {
+			continue
+		}
+		// Excel compares sheet names case-insensitively
+		if existing[strings.ToLower(target)] {
+			slog.Warn("sheet name already taken, keeping legacy name", "sheet", sheet, "target", target)
+			continue
+		}
+		delete(existing, strings.ToLower(sheet))
+		existing[strings.ToLower(target)] = true
+		renames = append(renames, RenamedSheet{From: sheet, To: target})
+	}
+	return renames
+}
+
+// renameFormulaReferences rewrites "'Old'!A1" and "Old!A1" references in every sheet,
+// defined name and chart. definedNames holds the defined names' text before the renames.
+func (p *Processor) renameFormulaReferences(renamed []RenamedSheet, definedNames []string) {
+	replacer := sheetReferenceReplacer(renamed)
+	p.rewriteDefinedNames(definedNames, replacer)
+	for _, sheet := range p.f.GetSheetList() {
+		if _, ok := p.broken[sheet]; ok {
+			continue
+		}
+		p.rewriteFormulas(sheet, replacer)
+		p.rewriteDataValidations(sheet, replacer)
+		p.rewriteConditionalFormats(sheet, replacer)
+	}
+	p.rewriteChartFormulas(replacer)
+}
+
+// sheetRefReplacer rewrites references to renamed sheets in formulas. It maps each old
+// name, in lower case, to the quoted new reference including the "!".
+type sheetRefReplacer map[string]string
+
+// referenceDelimiters are the bytes that end an unquoted sheet name in a formula.
+const referenceDelimiters = " \t\r\n+-*/^&=<>(),;:{}[]!\"'%#@"
+
+// sheetReferenceReplacer returns a sheetRefReplacer for the renamed sheets.
+func sheetReferenceReplacer(renamed []RenamedSheet) sheetRefReplacer {
+	refs := make(sheetRefReplacer, len(renamed))
+	for _, r := range renamed {
+		// Renamed sheets contain non-ASCII letters, so the new reference is always quoted.
+		// Excel compares sheet names case-insensitively.
+		refs[strings.ToLower(r.From)] = "'" + strings.ReplaceAll(r.To, "'", "''") + "'!"
+	}
+	return refs
+}
+
+// Replace returns formula with its references to renamed sheets pointing at the new names.
+// Only whole references are rewritten: a quoted name, or an unquoted name at the start or
+// after an operator, followed by "!". Text inside string literals is left alone, as are
+// references into other workbooks ("[1]Old!A1").
+// Why: Replacing "Old!" as plain text also rewrote "MyOld!A1" and "Old!" inside "..." text.
+func (refs sheetRefReplacer) Replace(formula string) string {
+	if len(refs) == 0 || !strings.Contains(formula, "!") {
+		return formula
+	}
+	var b strings.Builder
+	for i := 0; i < len(formula); {
+		switch c := formula[i]; {
+		case c == '"':
+			end := quotedEnd(formula, i)
+			b.WriteString(formula[i:end])
+			i = end
+		case c == '\'':
+			var text string
+			text, i = refs.quoted(formula, i)
+			b.WriteString(text)
+		case strings.IndexByte(referenceDelimiters, c) >= 0:
+			b.WriteByte(c)
+			i++
+		default:
+			var text string
+			text, i = refs.unquoted(formula, i)
+			b.WriteString(text)
+		}
+	}
+	return b.String()
+}
+
+// quoted returns the quoted name starting at formula[start], replaced if it is a
+// reference to a renamed sheet, and the index just past it.
+func (refs sheetRefReplacer) quoted(formula string, start int) (string, int) {
+	end := quotedEnd(formula, start)
+	if end < len(formula) && formula[end] == '!' {
+		name := strings.ReplaceAll(formula[start+1:end-1], "''", "'")
+		if to, ok := refs[strings.ToLower(name)]; ok {
+			return to, end + 1
+		}
+	}
+	return formula[start:end], end
+}
+
+// unquoted returns the unquoted token starting at formula[start], replaced if it is a
+// reference to a renamed sheet, and the index just past it.
+func (refs sheetRefReplacer) unquoted(formula string, start int) (string, int) {
+	end := start + 1
+	for end < len(formula) && strings.IndexByte(referenceDelimiters, formula[end]) < 0 {
+		end++
+	}
+	if end == len(formula) || formula[end] != '!' {
+		return formula[start:end], end
+	}
+	// "]" starts a sheet of another workbook and "#" an error value such as #REF!
+	if start > 0 && (formula[start-1] == ']' || formula[start-1] == '#') {
+		return formula[start:end], end
+	}
+	if to, ok := refs[strings.ToLower(formula[start:end])]; ok {
+		return to, end + 1
+	}
+	return formula[start:end], end
+}
+
+// quotedEnd returns the index just past the quoted text starting at s[start], where a
+// doubled quote stands for one quote character, or len(s) if the quote is not closed.
+func quotedEnd(s string, start int) int {
+	q := s[start]
+	for i := start + 1; i < len(s); i++ {
+		if s[i] != q {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == q {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(s)
+}
+
+// definedNameFormulas returns the text of every defined name, in workbook order.
+func (p *Processor) definedNameFormulas() []string {
+	wb := p.f.WorkBook
+	if wb == nil || wb.DefinedNames == nil {
+		return nil
+	}
+	formulas := make([]string, len(wb.DefinedNames.DefinedName))
+	for i, dn := range wb.DefinedNames.DefinedName {
+		formulas[i] = dn.Data
+	}
+	return formulas
+}
+
+// rewriteDefinedNames sets every defined name to its text from before the renames with
+// replacer applied.
+// Why: excelize's own update only matches plain ranges like "Old!A1:B2", not references
+// inside formulas such as "SUM(Old!A1:A9)".
+func (p *Processor) rewriteDefinedNames(before []string, replacer sheetRefReplacer) {
+	wb := p.f.WorkBook
+	if wb == nil || wb.DefinedNames == nil || len(wb.DefinedNames.DefinedName) != len(before) {
+		return
+	}
+	for i := range wb.DefinedNames.DefinedName {
+		wb.DefinedNames.DefinedName[i].Data = replacer.Replace(before[i])
+	}
+}
+
+// dataValidationEscaper escapes a data validation formula the way excelize stores it.
+var dataValidationEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// rewriteDataValidations applies replacer to the formulas of every data validation in
+// sheet, such as a drop-down list read from another sheet.
+func (p *Processor) rewriteDataValidations(sheet string, replacer sheetRefReplacer) {
+	validations, err := p.f.GetDataValidations(sheet)
+	if err != nil {
+		slog.Warn("failed to read data validations for formula update", "sheet", sheet, "error", err)
+		p.errs.add(sheet, "", StageFormula, err)
+		return
+	}
+	for _, dv := range validations {
+		// A quoted list of values holds no references
+		if strings.HasPrefix(dv.Formula1, `"`) {
+			continue
+		}
+		formula1, formula2 := replacer.Replace(dv.Formula1), replacer.Replace(dv.Formula2)
+		if formula1 == dv.Formula1 && formula2 == dv.Formula2 {
+			continue
+		}
+		dv.Formula1, dv.Formula2 = dataValidationEscaper.Replace(formula1), dataValidationEscaper.Replace(formula2)
+		if err := p.f.DeleteDataValidation(sheet, dv.Sqref); err != nil {
+			slog.Warn("failed to update data validation", "sheet", sheet, "range", dv.Sqref, "error", err)
+			p.errs.add(sheet, dv.Sqref, StageFormula, err)
+			continue
+		}
+		if err := p.f.AddDataValidation(sheet, dv); err != nil {
+			slog.Warn("failed to update data validation", "sheet", sheet, "range", dv.Sqref, "error", err)
+			p.errs.add(sheet, dv.Sqref, StageFormula, err)
+		}
+	}
+}
+
+// rewriteConditionalFormats applies replacer to the formulas of the cell value and
+// formula rules in sheet. A range is set again only when one of its formulas changed.
+func (p *Processor) rewriteConditionalFormats(sheet string, replacer sheetRefReplacer) {
+	formats, err := p.f.GetConditionalFormats(sheet)
+	if err != nil {
+		slog.Warn("failed to read conditional formats for formula update", "sheet", sheet, "error", err)
+		p.errs.add(sheet, "", StageFormula, err)
+		return
+	}
+	ranges := make([]string, 0, len(formats))
+	for rangeRef := range formats {
+		ranges = append(ranges, rangeRef)
+	}
+	sort.Strings(ranges)
+
+	for _, rangeRef := range ranges {
+		opts := formats[rangeRef]
+		changed := false
+		for i := range opts {
+			// Other rule types hold colors, thresholds or plain text rather than formulas
+			if opts[i].Type != "cell" && opts[i].Type != "formula" {
+				continue
+			}
+			for _, formula := range []*string{&opts[i].Criteria, &opts[i].Value, &opts[i].MinValue, &opts[i].MaxValue} {
+				if updated := replacer.Replace(*formula); updated != *formula {
+					*formula = updated
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := p.f.UnsetConditionalFormat(sheet, rangeRef); err != nil {
+			slog.Warn("failed to update conditional format", "sheet", sheet, "range", rangeRef, "error", err)
+			p.errs.add(sheet, rangeRef, StageFormula, err)
+			continue
+		}
+		if err := p.f.SetConditionalFormat(sheet, rangeRef, opts); err != nil {
+			slog.Warn("failed to update conditional format", "sheet", sheet, "range", rangeRef, "error", err)
+			p.errs.add(sheet, rangeRef, StageFormula, err)
+		}
+	}
+}
+
+// rewriteFormulas applies replacer to every formula in sheet.
+// Why: The stored sheet dimension is often stale, so the row iterator decides the extent.
+func (p *Processor) rewriteFormulas(sheet string, replacer sheetRefReplacer) {
+	rows, err := p.f.Rows(sheet)
+	if err != nil {
+		slog.Warn("failed to read rows for formula update", "sheet", sheet, "error", err)
+		p.errs.add(sheet, "", StageFormula, err)
+		return
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rowIdx := 1; rows.Next(); rowIdx++ {
+		cols, err := rows.Columns()
+		if err != nil {
+			p.errs.add(sheet, rowAxis(rowIdx), StageFormula, err)
+			continue
+		}
+		for colIdx := range cols {
+			axis, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx)
+			formula, err := p.f.GetCellFormula(sheet, axis)
+			if err != nil || formula == "" {
+				continue
+			}
+			if updated := replacer.Replace(formula); updated != formula {
+				if err := p.f.SetCellFormula(sheet, axis, updated); err != nil {
+					slog.Warn("failed to update formula", "sheet", sheet, "cell", axis, "error", err)
+					p.errs.add(sheet, axis, StageFormula, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// writeLegacySheetWorkbook creates a workbook with a VNI-named sheet and a formula referring to it.
+func writeLegacySheetWorkbook(t *testing.T, path string) {
+	t.Helper()
+	f := excelize.NewFile()
+	if _, err := f.NewSheet("Ba\u00F9o ca\u00F9o"); err != nil {
+		t.Fatalf("failed to add sheet: %v", err)
+	}
+	if err := f.SetCellValue("Ba\u00F9o ca\u00F9o", "A1", "Vi\u00D6t Nam"); err != nil {
+		t.Fatalf("failed to set cell: %v", err)
+	}
+	if err := f.SetCellFormula("Sheet1", "B1", "'Ba\u00F9o ca\u00F9o'!A1"); err != nil {
+		t.Fatalf("failed to set formula: %v", err)
+	}
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("failed to save %s: %v", path, err)
+	}
+	_ = f.Close()
+}
+
+func TestProcessor_ConvertSheetNames(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "sheets.xlsx")
+	writeLegacySheetWorkbook(t, inputFile)
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		wantSheets  []string
+		wantFormula string
+		wantRenamed int
+	}{
+		{name: "Converted", enabled: true, wantSheets: []string{"Sheet1", "Báo cáo"}, wantFormula: "'Báo cáo'!A1", wantRenamed: 1},
+		{name: "Disabled", enabled: false, wantSheets: []string{"Sheet1", "Ba\u00F9o ca\u00F9o"}, wantFormula: "'Ba\u00F9o ca\u00F9o'!A1", wantRenamed: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc := NewProcessor(inputFile, "")
+			proc.SetConvertSheetNames(tt.enabled)
+			outputFile, err := proc.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			defer func() { _ = os.Remove(outputFile) }()
+
+			if got := proc.RenamedSheets(); len(got) != tt.wantRenamed {
+				t.Errorf("RenamedSheets() = %+v, want %d entries", got, tt.wantRenamed)
+			}
+
+			fOut, err := excelize.OpenFile(outputFile)
+			if err != nil {
+				t.Fatalf("failed to open output: %v", err)
+			}
+			defer func() { _ = fOut.Close() }()
+			if got := fOut.GetSheetList(); !reflect.DeepEqual(got, tt.wantSheets) {
+				t.Errorf("sheets = %q, want %q", got, tt.wantSheets)
+			}
+			if got, _ := fOut.GetCellFormula("Sheet1", "B1"); got != tt.wantFormula {
+				t.Errorf("formula = %q, want %q", got, tt.wantFormula)
+			}
+			if got, _ := fOut.GetCellValue(tt.wantSheets[1], "A1"); got != "Việt Nam" {
+				t.Errorf("A1 = %q, want converted text", got)
+			}
+		})
+	}
+}
+
+func TestProcessor_ConvertSheetNames_TargetTaken(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "taken.xlsx")
+	f := excelize.NewFile()
+	for _, name := range []string{"Ba\u00F9o ca\u00F9o", "Báo cáo"} {
+		if _, err := f.NewSheet(name); err != nil {
+			t.Fatalf("failed to add sheet: %v", err)
+		}
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	_ = f.Close()
+
+	proc := NewProcessor(inputFile, "")
+	outputFile, err := proc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+	if got := proc.RenamedSheets(); len(got) != 0 {
+		t.Errorf("RenamedSheets() = %+v, want none when the target name exists", got)
+	}
+}
+
+func TestSheetReferenceReplacer(t *testing.T) {
+	replacer := sheetReferenceReplacer([]RenamedSheet{
+		{From: "Data", To: "Dữ liệu"},
+		{From: "It's", To: "Báo cáo"},
+	})
+	tests := []struct {
+		name    string
+		formula string
+		want    string
+	}{
+		{name: "Unquoted", formula: "Data!A1+1", want: "'Dữ liệu'!A1+1"},
+		{name: "Quoted", formula: "SUM('Data'!A1:A3)", want: "SUM('Dữ liệu'!A1:A3)"},
+		{name: "Quoted with escaped quote", formula: "'It''s'!B2", want: "'Báo cáo'!B2"},
+		{name: "Case-insensitive", formula: "data!A1", want: "'Dữ liệu'!A1"},
+		{name: "Longer name untouched", formula: "MyData!A1+Data2!A1", want: "MyData!A1+Data2!A1"},
+		{name: "Other quoted name untouched", formula: "'My Data'!A1", want: "'My Data'!A1"},
+		{name: "String literal untouched", formula: `CONCAT("Data!A1",Data!B1)`, want: `CONCAT("Data!A1",'Dữ liệu'!B1)`},
+		{name: "Other workbook untouched", formula: "[1]Data!A1", want: "[1]Data!A1"},
+		{name: "Name without reference untouched", formula: "Data+1", want: "Data+1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replacer.Replace(tt.formula); got != tt.want {
+				t.Errorf("Replace(%q) = %q, want %q", tt.formula, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessor_ConvertSheetNames_UpdatesReferences(t *testing.T) {
+	const legacy = "Ba\u00F9o ca\u00F9o"
+	inputFile := filepath.Join(t.TempDir(), "references.xlsx")
+	f := excelize.NewFile()
+	if _, err := f.NewSheet(legacy); err != nil {
+		t.Fatalf("failed to add sheet: %v", err)
+	}
+	if err := f.SetDefinedName(&excelize.DefinedName{Name: "Total", RefersTo: "SUM('" + legacy + "'!$A$1:$A$3)"}); err != nil {
+		t.Fatalf("failed to add defined name: %v", err)
+	}
+	dv := excelize.NewDataValidation(true)
+	dv.Sqref = "A1:A5"
+	dv.SetSqrefDropList("'" + legacy + "'!$A$1:$A$3")
+	if err := f.AddDataValidation("Sheet1", dv); err != nil {
+		t.Fatalf("failed to add data validation: %v", err)
+	}
+	format := 0
+	if err := f.SetConditionalFormat("Sheet1", "B1:B5", []excelize.ConditionalFormatOptions{
+		{Type: "formula", Criteria: "B1>'" + legacy + "'!$A$1", Format: &format},
+	}); err != nil {
+		t.Fatalf("failed to add conditional format: %v", err)
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	_ = f.Close()
+
+	proc := NewProcessor(inputFile, "")
+	proc.SetConvertSheetNames(true)
+	outputFile, err := proc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	defer func() { _ = os.Remove(outputFile) }()
+
+	fOut, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = fOut.Close() }()
+
+	if got := fOut.GetDefinedName(); len(got) != 1 || got[0].RefersTo != "SUM('Báo cáo'!$A$1:$A$3)" {
+		t.Errorf("defined names = %+v, want Total referring to 'Báo cáo'", got)
+	}
+	validations, err := fOut.GetDataValidations("Sheet1")
+	if err != nil || len(validations) != 1 || validations[0].Formula1 != "'Báo cáo'!$A$1:$A$3" {
+		t.Errorf("data validations = %+v (err %v), want one list from 'Báo cáo'", validations, err)
+	}
+	formats, err := fOut.GetConditionalFormats("Sheet1")
+	if err != nil || len(formats["B1:B5"]) != 1 || formats["B1:B5"][0].Criteria != "B1>'Báo cáo'!$A$1" {
+		t.Errorf("conditional formats = %+v (err %v), want a rule comparing with 'Báo cáo'", formats, err)
+	}
+}
@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// sheetViewState is what users see of a sheet when opening a workbook.
+type sheetViewState struct {
+	TabColorRGB   string
+	TabColorTheme int
+	ZoomScale     float64
+	ShowGridLines bool
+	Panes         excelize.Panes
+}
+
+// writeViewWorkbook writes a workbook with a legacy-named second sheet, tab colors, zoom,
+// frozen panes and selections, with the second sheet active.
+func writeViewWorkbook(t *testing.T) []byte {
+	t.Helper()
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+	if _, err := f.NewSheet("Vi\u00D6t"); err != nil {
+		t.Fatalf("NewSheet failed: %v", err)
+	}
+	for _, sheet := range f.GetSheetList() {
+		if err := f.SetCellValue(sheet, "A1", "Vi\u00D6t Nam"); err != nil {
+			t.Fatalf("SetCellValue failed: %v", err)
+		}
+	}
+
+	red, theme := "FF0000", 4
+	zoom, noGrid := 85.0, false
+	steps := []error{
+		f.SetSheetProps("Sheet1", &excelize.SheetPropsOptions{TabColorRGB: &red}),
+		f.SetSheetProps("Vi\u00D6t", &excelize.SheetPropsOptions{TabColorTheme: &theme}),
+		f.SetSheetView("Sheet1", 0, &excelize.ViewOptions{ZoomScale: &zoom, ShowGridLines: &noGrid}),
+		f.SetPanes("Sheet1", &excelize.Panes{
+			Selection: []excelize.Selection{{SQRef: "D10 F2:G4", ActiveCell: "D10"}},
+		}),
+		f.SetPanes("Vi\u00D6t", &excelize.Panes{
+			Freeze: true, XSplit: 1, YSplit: 2, TopLeftCell: "B3", ActivePane: "bottomRight",
+			Selection: []excelize.Selection{{SQRef: "C5", ActiveCell: "C5", Pane: "bottomRight"}},
+		}),
+	}
+	for _, err := range steps {
+		if err != nil {
+			t.Fatalf("failed to set up views: %v", err)
+		}
+	}
+	f.SetActiveSheet(1)
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// readViewState returns the view state of every sheet (by position) and the active sheet index.
+func readViewState(t *testing.T, f *excelize.File) ([]sheetViewState, int) {
+	t.Helper()
+	var states []sheetViewState
+	for _, sheet := range f.GetSheetList() {
+		props, err := f.GetSheetProps(sheet)
+		if err != nil {
+			t.Fatalf("GetSheetProps(%s) failed: %v", sheet, err)
+		}
+		view, err := f.GetSheetView(sheet, 0)
+		if err != nil {
+			t.Fatalf("GetSheetView(%s) failed: %v", sheet, err)
+		}
+		panes, err := f.GetPanes(sheet)
+		if err != nil {
+			t.Fatalf("GetPanes(%s) failed: %v", sheet, err)
+		}
+		var state sheetViewState
+		if props.TabColorRGB != nil {
+			state.TabColorRGB = *props.TabColorRGB
+		}
+		if props.TabColorTheme != nil {
+			state.TabColorTheme = *props.TabColorTheme
+		}
+		if view.ZoomScale != nil {
+			state.ZoomScale = *view.ZoomScale
+		}
+		state.ShowGridLines = view.ShowGridLines == nil || *view.ShowGridLines
+		state.Panes = panes
+		states = append(states, state)
+	}
+	return states, f.GetActiveSheetIndex()
+}
+
+func TestConversionKeepsSheetViews(t *testing.T) {
+	input := writeViewWorkbook(t)
+	original, err := excelize.OpenReader(bytes.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to open input: %v", err)
+	}
+	wantStates, wantActive := readViewState(t, original)
+	_ = original.Close()
+
+	tests := []struct {
+		name    string
+		convert func(t *testing.T) []byte
+	}{
+		{name: "Run", convert: func(t *testing.T) []byte {
+			inputFile := filepath.Join(t.TempDir(), "views.xlsx")
+			if err := os.WriteFile(inputFile, input, 0600); err != nil {
+				t.Fatalf("failed to write input: %v", err)
+			}
+			proc := NewProcessor(inputFile, "")
+			proc.SetCheckpoint(true)
+			outputFile, err := proc.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			data, err := os.ReadFile(outputFile) //nolint:gosec // test output
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			return data
+		}},
+		{name: "ConvertXLSX spilled to disk", convert: func(t *testing.T) []byte {
+			var output bytes.Buffer
+			_, err := ConvertXLSX(context.Background(), bytes.NewReader(input), &output,
+				WithSpillThreshold(1), WithTempDir(t.TempDir()))
+			if err != nil {
+				t.Fatalf("ConvertXLSX failed: %v", err)
+			}
+			return output.Bytes()
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := excelize.OpenReader(bytes.NewReader(tt.convert(t)))
+			if err != nil {
+				t.Fatalf("failed to open output: %v", err)
+			}
+			defer func() { _ = f.Close() }()
+			if got := f.GetSheetList(); !reflect.DeepEqual(got, []string{"Sheet1", "Việt"}) {
+				t.Errorf("sheets = %v, want the legacy sheet renamed", got)
+			}
+			states, active := readViewState(t, f)
+			if !reflect.DeepEqual(states, wantStates) {
+				t.Errorf("views = %+v, want %+v", states, wantStates)
+			}
+			if active != wantActive {
+				t.Errorf("active sheet = %d, want %d", active, wantActive)
+			}
+		})
+	}
+}
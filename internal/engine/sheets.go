@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+// SheetInfo describes one sheet of a workbook for the sheet picker.
+type SheetInfo struct {
+	Name string `json:"name"`
+	// Rows is the number of the last row with data
+	Rows      int  `json:"rows"`
+	HasLegacy bool `json:"hasLegacy"`
+}
+
+// Sheets lists every sheet of the workbook with its row count and whether it contains
+// legacy-encoded text. The legacy scan stops at the first legacy cell of each sheet.
+// Why: Users pick a sheet from a list instead of typing its name blindly.
+func (p *Processor) Sheets(ctx context.Context) ([]SheetInfo, error) {
+	if err := p.openInput(ctx); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := p.f.Close(); closeErr != nil {
+			slog.Error("failed to close excel file", "error", closeErr)
+		}
+	}()
+
+	names := p.f.GetSheetList()
+	sheets := make([]SheetInfo, 0, len(names))
+	for _, name := range names {
+		rows, err := p.countRows(name)
+		if err != nil {
+			return nil, err
+		}
+		info := SheetInfo{Name: name, Rows: rows}
+		p.walkCells(ctx, name, func(job Job) bool {
+			info.HasLegacy = p.detectJobEncoding(job) != converter.EncodingUnknown
+			return !info.HasLegacy
+		})
+		sheets = append(sheets, info)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("listing sheets cancelled: %w", err)
+	}
+	return sheets, nil
+}
+
+// countRows returns the number of the last row of sheet, without loading its cells.
+func (p *Processor) countRows(sheet string) (int, error) {
+	rows, err := p.f.Rows(sheet)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read sheet %q: %w", sheet, err)
+	}
+	defer func() { _ = rows.Close() }()
+	n := 0
+	for rows.Next() {
+		n++
+	}
+	if err := rows.Error(); err != nil {
+		return 0, fmt.Errorf("failed to read sheet %q: %w", sheet, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestProcessor_Sheets(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "picker.xlsx")
+	f := excelize.NewFile()
+	if _, err := f.NewSheet("Clean"); err != nil {
+		t.Fatalf("failed to add sheet: %v", err)
+	}
+	if _, err := f.NewSheet("Empty"); err != nil {
+		t.Fatalf("failed to add sheet: %v", err)
+	}
+	cells := []struct{ sheet, axis, value string }{
+		{"Sheet1", "A1", "Hello"},
+		{"Sheet1", "B3", "Vi\u00D6t Nam"},
+		{"Clean", "A5", "Hello"},
+	}
+	for _, c := range cells {
+		if err := f.SetCellValue(c.sheet, c.axis, c.value); err != nil {
+			t.Fatalf("failed to set %s!%s: %v", c.sheet, c.axis, err)
+		}
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	_ = f.Close()
+
+	got, err := NewProcessor(inputFile, "").Sheets(context.Background())
+	if err != nil {
+		t.Fatalf("Sheets failed: %v", err)
+	}
+	want := []SheetInfo{
+		{Name: "Sheet1", Rows: 3, HasLegacy: true},
+		{Name: "Clean", Rows: 5},
+		{Name: "Empty"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Sheets() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("sheet %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
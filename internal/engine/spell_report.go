@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"convert-vni-to-unicode/internal/converter"
+	"convert-vni-to-unicode/internal/spell"
+)
+
+// SpellIssue is a converted cell containing words that are not Vietnamese.
+type SpellIssue struct {
+	SheetName string                 `json:"sheetName"`
+	Axis      string                 `json:"axis"`
+	Encoding  converter.EncodingType `json:"encoding"`
+	Words     []string               `json:"words"`
+	Text      string                 `json:"text"`
+}
+
+// SpellReport spell-checks every cell a run converted.
+// Why: Cells converted with the wrong encoding table turn into improbable syllables, so
+// the report points QA at wrong detections without comparing every cell by eye.
+// Only the collector goroutine records, so no locking is needed.
+type SpellReport struct {
+	checker *spell.Checker
+	checked int
+	issues  []SpellIssue
+}
+
+// NewSpellReport creates an empty report checking words with checker (see spell.New and
+// spell.LoadDictionary).
+func NewSpellReport(checker *spell.Checker) *SpellReport {
+	return &SpellReport{checker: checker}
+}
+
+// record checks the converted text of res; unconverted cells are not checked.
+func (r *SpellReport) record(res Result, encoding converter.EncodingType) {
+	if res.Unchanged || encoding == converter.EncodingUnknown {
+		return
+	}
+	r.checked++
+	text := runsText(res.NewRuns)
+	if words := r.checker.Improbable(text); len(words) > 0 {
+		r.issues = append(r.issues, SpellIssue{
+			SheetName: res.Job.SheetName, Axis: res.Job.Axis, Encoding: encoding, Words: words, Text: text,
+		})
+	}
+}
+
+// Checked returns the number of converted cells checked.
+func (r *SpellReport) Checked() int {
+	return r.checked
+}
+
+// Issues returns the cells with improbable words in sheet, row and column order.
+func (r *SpellReport) Issues() []SpellIssue {
+	sort.SliceStable(r.issues, func(i, j int) bool {
+		a, b := r.issues[i], r.issues[j]
+		return cellLess(a.SheetName, a.Axis, b.SheetName, b.Axis)
+	})
+	return r.issues
+}
+
+// WriteCSV writes one row per cell with improbable words.
+func (r *SpellReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Sheet", "Cell", "Encoding", "Improbable Words", "Converted Text"}); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	for _, issue := range r.Issues() {
+		record := []string{issue.SheetName, issue.Axis, string(issue.Encoding), strings.Join(issue.Words, " "), issue.Text}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv record: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
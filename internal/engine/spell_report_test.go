@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+	"convert-vni-to-unicode/internal/spell"
+)
+
+func TestSpellReport(t *testing.T) {
+	tests := []struct {
+		name      string
+		encoding  converter.EncodingType
+		wantWords []string
+	}{
+		{name: "Detected", encoding: converter.EncodingAuto},
+		// The VNI text read with the TCVN3 table
+		{name: "Wrong table", encoding: converter.EncodingTCVN3, wantWords: []string{"Vie\u1ECDt", "Ha\u1EE9", "No\u1ECDi"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := filepath.Join(t.TempDir(), "input.xlsx")
+			writeWorkbook(t, input, map[string]string{"A1": "Vie\u00E4t Nam", "A2": "Ha\u00F8 No\u00E4i", "A3": "Hello"})
+			report := NewSpellReport(spell.New())
+			p := NewProcessor(input, "")
+			if err := p.SetSourceEncoding(tt.encoding); err != nil {
+				t.Fatalf("SetSourceEncoding failed: %v", err)
+			}
+			p.SetSpellReport(report)
+			if _, err := p.Run(context.Background()); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			var words []string
+			for _, issue := range report.Issues() {
+				words = append(words, issue.Words...)
+			}
+			if !reflect.DeepEqual(words, tt.wantWords) {
+				t.Errorf("improbable words = %q, want %q", words, tt.wantWords)
+			}
+			if report.Checked() < 2 {
+				t.Errorf("Checked() = %d, want at least the 2 legacy cells", report.Checked())
+			}
+			var buf bytes.Buffer
+			if err := report.WriteCSV(&buf); err != nil {
+				t.Fatalf("WriteCSV failed: %v", err)
+			}
+			if lines := strings.Count(buf.String(), "\n"); lines != 1+len(report.Issues()) {
+				t.Errorf("CSV has %d lines:\n%s", lines, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"slices"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+// ConversionStats summarizes what the last Run converted (see Processor.Stats).
+type ConversionStats struct {
+	// Sheets lists the sheets with at least one converted cell, in the order they were reached
+	Sheets []string `json:"sheets"`
+	// Converted counts the converted cells, or the converted runs of a document,
+	// presentation or OpenDocument spreadsheet
+	Converted int `json:"converted"`
+	// ByEncoding counts the converted cells by source encoding; a text file counts once,
+	// under the encoding it was converted from
+	ByEncoding map[converter.EncodingType]int `json:"byEncoding"`
+}
+
+// recordStats counts a converted cell. Only the collector records, so no locking is needed.
+func (p *Processor) recordStats(res Result) {
+	if res.Unchanged {
+		return
+	}
+	if p.stats.ByEncoding == nil {
+		p.stats.ByEncoding = make(map[converter.EncodingType]int)
+	}
+	if !slices.Contains(p.stats.Sheets, res.Job.SheetName) {
+		p.stats.Sheets = append(p.stats.Sheets, res.Job.SheetName)
+	}
+	p.stats.Converted++
+	p.stats.ByEncoding[p.jobEncoding(res.Job)]++
+}
+
+// Stats returns what the last Run converted. A Run resumed from a checkpoint only counts
+// the sheets it converted itself.
+// Why: The conversion history shows how much of a workbook changed, and from which encoding.
+func (p *Processor) Stats() ConversionStats {
+	out := ConversionStats{
+		Sheets:     append([]string(nil), p.stats.Sheets...),
+		Converted:  p.stats.Converted + p.convertedRuns,
+		ByEncoding: make(map[converter.EncodingType]int, len(p.stats.ByEncoding)),
+	}
+	for enc, n := range p.stats.ByEncoding {
+		out.ByEncoding[enc] = n
+	}
+	if enc := p.textEncoding; enc != "" && enc != converter.EncodingUnknown {
+		out.ByEncoding[enc]++
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+func TestProcessor_Stats(t *testing.T) {
+	tests := []struct {
+		name      string
+		largeFile bool
+	}{
+		{name: "Workbook"},
+		{name: "Large file mode", largeFile: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputFile := filepath.Join(t.TempDir(), "stats.xlsx")
+			writeWorkbook(t, inputFile, map[string]string{"A1": "Vi\u00D6t Nam", "A2": "Hello", "A3": "C\u00ABng ty", "A4": "Tr\u00F6\u00F4\u00F8ng"})
+
+			proc := NewProcessor(inputFile, "")
+			proc.SetLargeFileMode(tt.largeFile)
+			outputFile, err := proc.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			defer func() { _ = os.Remove(outputFile) }()
+
+			want := ConversionStats{
+				Sheets:     []string{"Sheet1"},
+				Converted:  3,
+				ByEncoding: map[converter.EncodingType]int{converter.EncodingVNI: 2, converter.EncodingTCVN3: 1},
+			}
+			if got := proc.Stats(); !reflect.DeepEqual(got, want) {
+				t.Errorf("Stats() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"convert-vni-to-unicode/internal/storage"
+)
+
+// NewStorageConverter returns a ConvertFunc for inputs on any storage backend (local
+// paths, UNC paths, http(s):// URLs or any registered scheme, see storage.Resolve).
+// Each workbook is streamed through ConvertXLSX and saved as
+// "<name>_output_<timestamp><ext>" in outDir, or next to the input if outDir is empty.
+// Why: RunBatch can read from and write to remote storage without the Processor
+// knowing about it.
+func NewStorageConverter(outDir string, opts ...Option) ConvertFunc {
+	return func(ctx context.Context, input string) (string, error) {
+		output, _, err := ConvertStorage(ctx, input, outDir, opts...)
+		return output, err
+	}
+}
+
+// ConvertStorage converts one workbook like the ConvertFunc of NewStorageConverter and
+// also returns what the conversion changed or left alone.
+func ConvertStorage(ctx context.Context, input, outDir string, opts ...Option) (string, WorkbookResult, error) {
+	src, name, err := storage.Resolve(input)
+	if err != nil {
+		return "", WorkbookResult{}, err
+	}
+	output := storageOutputName(input, outDir, time.Now())
+	dst, outName, err := storage.Resolve(output)
+	if err != nil {
+		return "", WorkbookResult{}, err
+	}
+
+	r, err := src.Open(ctx, name)
+	if err != nil {
+		return "", WorkbookResult{}, err
+	}
+	defer func() { _ = r.Close() }()
+	w, err := dst.Create(ctx, outName)
+	if err != nil {
+		return "", WorkbookResult{}, err
+	}
+	result, err := ConvertXLSX(ctx, r, w, opts...)
+	if err != nil {
+		_ = w.Abort()
+		return "", WorkbookResult{}, err
+	}
+	if err := w.Commit(); err != nil {
+		return "", WorkbookResult{}, fmt.Errorf("failed to save output: %w", err)
+	}
+	return output, result, nil
+}
+
+// storageOutputName returns the output location of input, in outDir if it is set.
+func storageOutputName(input, outDir string, now time.Time) string {
+	base := storage.Base(input)
+	ext := filepath.Ext(base)
+	name := fmt.Sprintf("%s_output_%s%s", strings.TrimSuffix(base, ext), now.Format(defaultTimestampLayout), ext)
+
+	switch {
+	case outDir == "":
+		// Replace the last element, keeping the input's folder (and scheme)
+		return strings.TrimSuffix(stripQuery(input), base) + name
+	case storage.IsURI(outDir):
+		return strings.TrimRight(outDir, "/") + "/" + name
+	default:
+		return filepath.Join(outDir, name)
+	}
+}
+
+// stripQuery drops the query string of a URI (e.g. a pre-signed URL's signature).
+func stripQuery(location string) string {
+	if storage.IsURI(location) {
+		if i := strings.IndexByte(location, '?'); i >= 0 {
+			return location[:i]
+		}
+	}
+	return location
+}
@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestStorageOutputName(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	tests := []struct {
+		name   string
+		input  string
+		outDir string
+		want   string
+	}{
+		{name: "Local next to input", input: filepath.Join("in", "book.xlsx"), want: filepath.Join("in", "book_output_2024_05_06_07_08_09.xlsx")},
+		{name: "Local out dir", input: "https://example.com/a/book.xlsx", outDir: "out", want: filepath.Join("out", "book_output_2024_05_06_07_08_09.xlsx")},
+		{name: "URL next to input drops query", input: "https://example.com/a/book.xlsx?sig=1", want: "https://example.com/a/book_output_2024_05_06_07_08_09.xlsx"},
+		{name: "URL out dir", input: filepath.Join("in", "book.xlsx"), outDir: "https://example.com/out/", want: "https://example.com/out/book_output_2024_05_06_07_08_09.xlsx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := storageOutputName(tt.input, tt.outDir, now); got != tt.want {
+				t.Errorf("storageOutputName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewStorageConverter_HTTP(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "book.xlsx")
+	writeWorkbook(t, inputFile, map[string]string{"A1": "Vi\u00D6t Nam"})
+	input, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatalf("failed to read input: %v", err)
+	}
+
+	var mu sync.Mutex
+	uploads := make(map[string][]byte)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write(input)
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			uploads[r.URL.Path] = data
+			mu.Unlock()
+		}
+	}))
+	defer srv.Close()
+
+	outDir := t.TempDir()
+	results := RunBatch(context.Background(), []string{srv.URL + "/in/book.xlsx"}, 1, NewStorageConverter(outDir), nil)
+	if results[0].Error != "" {
+		t.Fatalf("conversion failed: %s", results[0].Error)
+	}
+	if filepath.Dir(results[0].OutputPath) != outDir || !strings.HasPrefix(filepath.Base(results[0].OutputPath), "book_output_") {
+		t.Errorf("OutputPath = %q, want book_output_* in %s", results[0].OutputPath, outDir)
+	}
+	assertConvertedA1(t, results[0].OutputPath)
+
+	// Uploading the output back to the server
+	results = RunBatch(context.Background(), []string{srv.URL + "/in/book.xlsx"}, 1, NewStorageConverter(""), nil)
+	if results[0].Error != "" {
+		t.Fatalf("conversion failed: %s", results[0].Error)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(uploads) != 1 {
+		t.Fatalf("uploads = %d, want 1", len(uploads))
+	}
+	for path, data := range uploads {
+		if !strings.HasPrefix(path, "/in/book_output_") {
+			t.Errorf("uploaded to %s, want next to the input", path)
+		}
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("upload is not a workbook: %v", err)
+		}
+		if got, _ := f.GetCellValue("Sheet1", "A1"); got != "Việt Nam" {
+			t.Errorf("A1 = %q, want converted text", got)
+		}
+		_ = f.Close()
+	}
+}
+
+func assertConvertedA1(t *testing.T, path string) {
+	t.Helper()
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	if got, _ := f.GetCellValue("Sheet1", "A1"); got != "Việt Nam" {
+		t.Errorf("A1 = %q, want converted text", got)
+	}
+}
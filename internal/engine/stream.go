@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// DefaultSpillThreshold is the unzipped worksheet size above which ConvertXLSX keeps the
+// worksheet in a temporary file instead of memory.
+const DefaultSpillThreshold int64 = 16 << 20
+
+// WithSpillThreshold sets the unzipped worksheet size (in bytes) above which ConvertXLSX
+// spills worksheets to temporary files; 0 or less restores DefaultSpillThreshold.
+func WithSpillThreshold(n int64) Option {
+	return func(p *Processor) error {
+		if n <= 0 {
+			n = DefaultSpillThreshold
+		}
+		p.spillThreshold = n
+		return nil
+	}
+}
+
+// WithTempDir sets the directory for spilled worksheets (default: the OS temp directory).
+func WithTempDir(dir string) Option {
+	return func(p *Processor) error {
+		p.tempDir = dir
+		return nil
+	}
+}
+
+// WithBuildInfo sets the build identification stamped into the output (see SetBuildInfo).
+func WithBuildInfo(b BuildInfo) Option {
+	return func(p *Processor) error {
+		p.SetBuildInfo(b)
+		return nil
+	}
+}
+
+// ConvertXLSX reads a workbook from r, converts it and writes the result to w.
+// The upload itself is read into memory, as excelize does for any workbook it opens,
+// even from a file. Only worksheets larger than the spill threshold (see
+// WithSpillThreshold) are unzipped to temporary files, removed on return, which keeps
+// their XML out of memory while the workbook is open. Every sheet is still decoded to be
+// checked and converted, so peak memory grows with the size of the workbook.
+// Why: Server mode and cloud connectors convert uploads without managing temp files.
+func ConvertXLSX(ctx context.Context, r io.Reader, w io.Writer, opts ...Option) (WorkbookResult, error) {
+	p, err := newProcessorWithOptions(opts)
+	if err != nil {
+		return WorkbookResult{}, err
+	}
+	slog.Info("stream conversion started", p.buildInfo.LogAttrs()...)
+
+	f, err := excelize.OpenReader(r, p.openOptions())
+	if err != nil {
+		return WorkbookResult{}, fmt.Errorf("failed to open excel: %w", err)
+	}
+	defer func() {
+		// Close also deletes the spilled worksheets
+		if closeErr := f.Close(); closeErr != nil {
+			slog.Error("failed to close excel file", "error", closeErr)
+		}
+	}()
+
+	result, err := p.processOpen(ctx, f)
+	if err != nil {
+		return WorkbookResult{}, err
+	}
+	p.stampBuildInfo(f)
+	if err := f.Write(w); err != nil {
+		return WorkbookResult{}, fmt.Errorf("failed to write output: %w", err)
+	}
+	return result, nil
+}
+
+// openOptions returns the excelize options ConvertXLSX opens uploads with.
+func (p *Processor) openOptions() excelize.Options {
+	return excelize.Options{UnzipXMLSizeLimit: p.spillThreshold, TmpDir: p.tempDir}
+}
@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// tempDirProbe is a Tracer that records whether dir held spilled files during conversion.
+type tempDirProbe struct {
+	dir     string
+	spilled atomic.Bool
+}
+
+func (t *tempDirProbe) OnCellStart(_ context.Context, _ Job) {
+	if entries, err := os.ReadDir(t.dir); err == nil && len(entries) > 0 {
+		t.spilled.Store(true)
+	}
+}
+
+func (t *tempDirProbe) OnCellEnd(_ context.Context, _ Job, _ time.Duration) {}
+
+func TestConvertXLSX(t *testing.T) {
+	tests := []struct {
+		name        string
+		threshold   int64
+		wantSpilled bool
+	}{
+		{name: "In memory", threshold: 0, wantSpilled: false},
+		{name: "Spilled to disk", threshold: 1, wantSpilled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := excelize.NewFile()
+			if err := f.SetCellValue("Sheet1", "A1", "Vi\u00D6t Nam"); err != nil {
+				t.Fatalf("failed to set cell: %v", err)
+			}
+			input, err := f.WriteToBuffer()
+			if err != nil {
+				t.Fatalf("failed to write input: %v", err)
+			}
+			_ = f.Close()
+
+			probe := &tempDirProbe{dir: t.TempDir()}
+			var output bytes.Buffer
+			_, err = ConvertXLSX(context.Background(), input, &output,
+				WithSpillThreshold(tt.threshold), WithTempDir(probe.dir), WithTracer(probe))
+			if err != nil {
+				t.Fatalf("ConvertXLSX failed: %v", err)
+			}
+			if got := probe.spilled.Load(); got != tt.wantSpilled {
+				t.Errorf("spilled = %v, want %v", got, tt.wantSpilled)
+			}
+			if entries, _ := os.ReadDir(probe.dir); len(entries) > 0 {
+				t.Errorf("temporary files left behind: %v", entries)
+			}
+
+			fOut, err := excelize.OpenReader(&output)
+			if err != nil {
+				t.Fatalf("failed to open output: %v", err)
+			}
+			defer func() { _ = fOut.Close() }()
+			if got, _ := fOut.GetCellValue("Sheet1", "A1"); got != "Việt Nam" {
+				t.Errorf("A1 = %q, want %q", got, "Việt Nam")
+			}
+		})
+	}
+}
+
+func TestConvertXLSX_InvalidInput(t *testing.T) {
+	var output bytes.Buffer
+	if _, err := ConvertXLSX(context.Background(), strings.NewReader("not a zip"), &output); err == nil {
+		t.Error("expected error for a non-workbook input")
+	}
+	if output.Len() != 0 {
+		t.Errorf("wrote %d bytes for a failed conversion", output.Len())
+	}
+}
+
+func TestProcessor_OpenOptionsMemory(t *testing.T) {
+	f := excelize.NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		t.Fatalf("failed to create stream writer: %v", err)
+	}
+	for row := 1; row <= 40000; row++ {
+		cell, _ := excelize.CoordinatesToCellName(1, row)
+		if err := sw.SetRow(cell, []any{row, "Vi\u00D6t Nam", "B\u00B8o c\u00B8o"}); err != nil {
+			t.Fatalf("failed to set row: %v", err)
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+	input, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	_ = f.Close()
+	upload := input.Bytes()
+	const sheetSize = 4 << 20 // the unzipped Sheet1 XML is a little over this
+
+	tests := []struct {
+		name      string
+		threshold int64
+		wantBelow bool
+	}{
+		{name: "Worksheet kept in memory", threshold: 64 << 20, wantBelow: false},
+		{name: "Worksheet spilled to disk", threshold: 1 << 20, wantBelow: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Processor{spillThreshold: tt.threshold, tempDir: t.TempDir()}
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+			opened, err := excelize.OpenReader(bytes.NewReader(upload), p.openOptions())
+			if err != nil {
+				t.Fatalf("failed to open upload: %v", err)
+			}
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+			runtime.KeepAlive(opened)
+			_ = opened.Close()
+
+			// The compressed upload stays in memory either way; it is far smaller than the sheet
+			grown := int64(after.HeapAlloc) - int64(before.HeapAlloc)
+			if below := grown < sheetSize; below != tt.wantBelow {
+				t.Errorf("heap grew by %d bytes with the workbook open, want below %d = %v",
+					grown, sheetSize, tt.wantBelow)
+			}
+		})
+	}
+}
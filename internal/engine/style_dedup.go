@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// dedupFonts collapses identical font records in the workbook's styles table and
+// rewrites the font references of every cell format. It returns the number of fonts removed.
+// Why: Legacy editors leave many duplicate fonts behind, and large converted workbooks
+// can otherwise hit Excel's font/style limits and fail to open.
+// Fonts are only referenced by index from cell formats, so sheets need no changes.
+func dedupFonts(f *excelize.File) (int, error) {
+	// GetStyle loads the styles part into f.Styles
+	if _, err := f.GetStyle(0); err != nil {
+		return 0, fmt.Errorf("failed to read styles: %w", err)
+	}
+	if f.Styles == nil || f.Styles.Fonts == nil {
+		return 0, nil
+	}
+
+	fonts := f.Styles.Fonts.Font
+	remap := make([]int, len(fonts))
+	seen := make(map[string]int, len(fonts))
+	kept := fonts[:0:0]
+	for i, font := range fonts {
+		data, err := xml.Marshal(font)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode font %d: %w", i, err)
+		}
+		key := string(data)
+		if idx, ok := seen[key]; ok {
+			remap[i] = idx
+			continue
+		}
+		// First occurrence wins, so font 0 (the workbook default) never moves
+		seen[key] = len(kept)
+		remap[i] = len(kept)
+		kept = append(kept, font)
+	}
+	removed := len(fonts) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	if xfs := f.Styles.CellXfs; xfs != nil {
+		for i := range xfs.Xf {
+			remapFontID(xfs.Xf[i].FontID, remap)
+		}
+	}
+	if xfs := f.Styles.CellStyleXfs; xfs != nil {
+		for i := range xfs.Xf {
+			remapFontID(xfs.Xf[i].FontID, remap)
+		}
+	}
+	f.Styles.Fonts.Font = kept
+	f.Styles.Fonts.Count = len(kept)
+	return removed, nil
+}
+
+// remapFontID rewrites a font reference in place. Out-of-range references are left alone.
+func remapFontID(id *int, remap []int) {
+	if id != nil && *id >= 0 && *id < len(remap) {
+		*id = remap[*id]
+	}
+}
@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestDedupFonts(t *testing.T) {
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+
+	// Build styles that each get their own font record
+	styles := []*excelize.Style{
+		{Font: &excelize.Font{Family: "VNI-Times", Size: 12}, Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FF0000"}}},
+		{Font: &excelize.Font{Family: "VNI-Times", Size: 12}, Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"00FF00"}}},
+		{Font: &excelize.Font{Family: ".VnTime", Bold: true}},
+	}
+	axes := []string{"A1", "A2", "A3"}
+	for i, s := range styles {
+		id, err := f.NewStyle(s)
+		if err != nil {
+			t.Fatalf("NewStyle failed: %v", err)
+		}
+		if err := f.SetCellStyle("Sheet1", axes[i], axes[i], id); err != nil {
+			t.Fatalf("SetCellStyle failed: %v", err)
+		}
+	}
+	// Inject a duplicate of the first custom font, as legacy editors do
+	fonts := f.Styles.Fonts
+	dup := *fonts.Font[1]
+	fonts.Font = append(fonts.Font, &dup)
+	fonts.Count = len(fonts.Font)
+	dupID := len(fonts.Font) - 1
+	f.Styles.CellXfs.Xf[len(f.Styles.CellXfs.Xf)-1].FontID = &dupID
+	before := len(fonts.Font)
+
+	removed, err := dedupFonts(f)
+	if err != nil {
+		t.Fatalf("dedupFonts failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("dedupFonts() removed %d fonts, want 1", removed)
+	}
+	if got := len(f.Styles.Fonts.Font); got != before-1 || f.Styles.Fonts.Count != got {
+		t.Errorf("fonts = %d (count %d), want %d", got, f.Styles.Fonts.Count, before-1)
+	}
+
+	// Formats must still resolve to the same fonts after a save/reload
+	path := filepath.Join(t.TempDir(), "dedup.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("SaveAs failed: %v", err)
+	}
+	out, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	tests := []struct {
+		axis       string
+		wantFamily string
+		wantBold   bool
+	}{
+		{axis: "A1", wantFamily: "VNI-Times"},
+		{axis: "A2", wantFamily: "VNI-Times"},
+		{axis: "A3", wantFamily: "VNI-Times"}, // re-pointed at the injected duplicate
+	}
+	for _, tt := range tests {
+		t.Run(tt.axis, func(t *testing.T) {
+			id, err := out.GetCellStyle("Sheet1", tt.axis)
+			if err != nil {
+				t.Fatalf("GetCellStyle failed: %v", err)
+			}
+			style, err := out.GetStyle(id)
+			if err != nil || style.Font == nil {
+				t.Fatalf("GetStyle failed: %v", err)
+			}
+			if style.Font.Family != tt.wantFamily || style.Font.Bold != tt.wantBold {
+				t.Errorf("font = %+v, want family %s bold %v", style.Font, tt.wantFamily, tt.wantBold)
+			}
+		})
+	}
+}
+
+func TestDedupFonts_NoDuplicates(t *testing.T) {
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+	removed, err := dedupFonts(f)
+	if err != nil || removed != 0 {
+		t.Errorf("dedupFonts() = %d, %v; want 0, nil", removed, err)
+	}
+}
@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// SetRemapStyleFonts rewrites legacy fonts in the workbook's styles table after conversion,
+// so empty cells (and row, column and named styles) stop carrying VNI/TCVN3 fonts.
+// Fonts still used by plain non-ASCII text, which is legacy text left unconverted (other
+// sheets, skipped or filtered cells), are kept, or that text would turn unreadable.
+// Why: Typing into an empty cell formatted with a legacy font later produces mojibake.
+func (p *Processor) SetRemapStyleFonts(enabled bool) {
+	p.remapStyleFonts = enabled
+}
+
+// remapLegacyStyleFonts applies the font policy to every legacy font record of the styles
+// table that no unconverted text uses. It returns the number of fonts rewritten.
+func (p *Processor) remapLegacyStyleFonts() (int, error) {
+	// GetStyle loads the styles part into f.Styles
+	if _, err := p.f.GetStyle(0); err != nil {
+		return 0, fmt.Errorf("failed to read styles: %w", err)
+	}
+	if p.f.Styles == nil || p.f.Styles.Fonts == nil {
+		return 0, nil
+	}
+	protected, err := p.fontsOfPlainText()
+	if err != nil {
+		return 0, err
+	}
+
+	remapped := 0
+	for i, font := range p.f.Styles.Fonts.Font {
+		if font == nil || font.Name == nil || font.Name.Val == nil || protected[i] {
+			continue
+		}
+		family := *font.Name.Val
+		det, legacy := detectByFont(family)
+		if !legacy {
+			continue
+		}
+		if mapped := p.preserver.policy.Resolve(family, det.Encoding); mapped != "" && mapped != family {
+			font.Name.Val = &mapped
+			remapped++
+		}
+	}
+	return remapped, nil
+}
+
+// fontsOfPlainText returns the font records used by the cell formats of plain (not rich)
+// text cells with non-ASCII text, across every sheet of the workbook.
+func (p *Processor) fontsOfPlainText() (map[int]bool, error) {
+	protected := make(map[int]bool)
+	xfs := p.f.Styles.CellXfs
+	if xfs == nil {
+		return protected, nil
+	}
+	for _, sheet := range p.f.GetSheetList() {
+		if _, ok := p.broken[sheet]; ok {
+			continue
+		}
+		rows, err := p.f.Rows(sheet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sheet %q: %w", sheet, err)
+		}
+		rowIdx := 0
+		for rows.Next() {
+			rowIdx++
+			cols, err := rows.Columns()
+			if err != nil {
+				continue
+			}
+			for colIdx, text := range cols {
+				// Unicode text left alone reads better once its legacy font is remapped
+				if isASCII(text) || strings.TrimSpace(text) == "" || converter.IsPrecomposedVietnamese(text) {
+					continue
+				}
+				if fontID, ok := p.plainTextFont(sheet, colIdx+1, rowIdx); ok {
+					protected[fontID] = true
+				}
+			}
+		}
+		if err := rows.Close(); err != nil {
+			slog.Error("failed to close rows iterator", "sheet", sheet, "error", err)
+		}
+	}
+	return protected, nil
+}
+
+// plainTextFont returns the font record of the cell format of a plain text cell, and false
+// for other cells.
+func (p *Processor) plainTextFont(sheet string, col, row int) (int, bool) {
+	axis, err := excelize.CoordinatesToCellName(col, row)
+	if err != nil {
+		return 0, false
+	}
+	// Converted cells were rewritten as rich text with their own fonts; plain
+	// non-ASCII text was left alone and still renders with the style's font
+	if cellType, err := p.f.GetCellType(sheet, axis); err != nil || !isTextCell(cellType) {
+		return 0, false
+	}
+	if runs, err := p.f.GetCellRichText(sheet, axis); err == nil && hasRunFonts(runs) {
+		return 0, false
+	}
+	xfs := p.f.Styles.CellXfs
+	styleID, err := p.f.GetCellStyle(sheet, axis)
+	if err != nil || styleID < 0 || styleID >= len(xfs.Xf) || xfs.Xf[styleID].FontID == nil {
+		return 0, false
+	}
+	return *xfs.Xf[styleID].FontID, true
+}
+
+// hasAnyRunFont reports whether any run sets its own font properties.
+func hasAnyRunFont(runs []excelize.RichTextRun) bool {
+	for _, run := range runs {
+		if run.Font != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRunFonts reports whether every run sets its own font. Plain strings come back from
+// GetCellRichText as a single run without a font.
+func hasRunFonts(runs []excelize.RichTextRun) bool {
+	for _, run := range runs {
+		if run.Font == nil || run.Font.Family == "" {
+			return false
+		}
+	}
+	return len(runs) > 0
+}
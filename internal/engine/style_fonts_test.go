@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestProcessor_RunWithRemapStyleFonts(t *testing.T) {
+	tests := []struct {
+		name      string
+		remap     bool
+		wantEmpty string // font of the empty styled cell B2
+	}{
+		{name: "disabled keeps legacy fonts", remap: false, wantEmpty: "VNI-Times"},
+		{name: "enabled remaps empty cell fonts", remap: true, wantEmpty: "Times New Roman"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputFile := filepath.Join(t.TempDir(), "styled.xlsx")
+			f := excelize.NewFile()
+			vni, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Family: "VNI-Times"}})
+			if err != nil {
+				t.Fatalf("NewStyle failed: %v", err)
+			}
+			tcvn3, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Family: ".VnTime"}})
+			if err != nil {
+				t.Fatalf("NewStyle failed: %v", err)
+			}
+			if err := f.SetCellValue("Sheet1", "A1", "Vi\u00D6t Nam"); err != nil {
+				t.Fatalf("SetCellValue failed: %v", err)
+			}
+			// C1 is outside the converted columns, so its legacy text must keep its font
+			if err := f.SetCellValue("Sheet1", "C1", "C\u00ABng ty"); err != nil {
+				t.Fatalf("SetCellValue failed: %v", err)
+			}
+			for _, s := range []struct {
+				axis  string
+				style int
+			}{{"A1", vni}, {"B2", vni}, {"C1", tcvn3}, {"D5", tcvn3}} {
+				if err := f.SetCellStyle("Sheet1", s.axis, s.axis, s.style); err != nil {
+					t.Fatalf("SetCellStyle failed: %v", err)
+				}
+			}
+			if err := f.SaveAs(inputFile); err != nil {
+				t.Fatalf("failed to save: %v", err)
+			}
+			_ = f.Close()
+
+			filter, err := ParseCellFilter("", "A:B")
+			if err != nil {
+				t.Fatalf("ParseCellFilter failed: %v", err)
+			}
+			proc := NewProcessor(inputFile, "")
+			proc.SetCellFilter(filter)
+			proc.SetRemapStyleFonts(tt.remap)
+			outputFile, err := proc.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			defer func() { _ = os.Remove(outputFile) }()
+
+			out, err := excelize.OpenFile(outputFile)
+			if err != nil {
+				t.Fatalf("failed to open output: %v", err)
+			}
+			defer func() { _ = out.Close() }()
+			want := map[string]string{"B2": tt.wantEmpty, "C1": ".VnTime", "D5": ".VnTime"}
+			for axis, font := range want {
+				if got := cellStyleFont(t, out, axis); got != font {
+					t.Errorf("%s font = %q, want %q", axis, got, font)
+				}
+			}
+			if got, _ := out.GetCellValue("Sheet1", "C1"); got != "C\u00ABng ty" {
+				t.Errorf("C1 = %q, want it left alone", got)
+			}
+		})
+	}
+}
+
+func cellStyleFont(t *testing.T, f *excelize.File, axis string) string {
+	t.Helper()
+	styleID, err := f.GetCellStyle("Sheet1", axis)
+	if err != nil {
+		t.Fatalf("GetCellStyle(%s) failed: %v", axis, err)
+	}
+	style, err := f.GetStyle(styleID)
+	if err != nil {
+		t.Fatalf("GetStyle(%d) failed: %v", styleID, err)
+	}
+	if style.Font == nil {
+		return ""
+	}
+	return style.Font.Family
+}
@@ -0,0 +1,189 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+// errTextUnsupported is returned by Run for a text file combined with a workbook-only setting.
+var errTextUnsupported = errors.New("text files cannot be converted in place, in large file mode or with checkpoints")
+
+// utf8BOM starts every converted text file.
+// Why: Notepad before Windows 10 1903 and Excel's text import read BOM-less files in the
+// ANSI code page, which would garble the converted Vietnamese again.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// viqrMinMarkedWords is the share of words that must carry a VIQR mark for a text to be
+// detected as VIQR. Nearly every syllable of VIQR text is marked, while English only has
+// the odd "I'm" or "you?".
+const viqrMinMarkedWords = 0.3
+
+// IsTextFile reports whether path is a plain text file Run converts as text.
+func IsTextFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".txt")
+}
+
+// TextEncoding returns the encoding the last Run converted a text file from, or
+// EncodingUnknown when no legacy text was found. It is empty after converting a workbook.
+func (p *Processor) TextEncoding() converter.EncodingType {
+	return p.textEncoding
+}
+
+// runText converts the text file at InputPath and saves it as UTF-8 under a new output
+// name, like Run does for workbooks. The encoding is forced or detected from the whole
+// file; lines already in Vietnamese Unicode are kept as they are.
+// Why: Legacy documents also come as .txt exports, which users used to paste cell by cell
+// into the text converter.
+func (p *Processor) runText(ctx context.Context) (string, error) {
+	p.textEncoding = ""
+	if p.backupMode != BackupNone || p.largeFileMode || p.checkpointing {
+		return "", errTextUnsupported
+	}
+	release, err := p.throttle.Acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	data, err := os.ReadFile(p.InputPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read text file: %w", err)
+	}
+	text, enc := p.convertTextContent(data)
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("conversion cancelled: %w", err)
+	}
+	p.textEncoding = enc
+	slog.Info("converted text file", "encoding", enc, "bytes", len(data))
+
+	outputPath, err := reserveOutputPath(p.outputPath(time.Now()))
+	if err != nil {
+		return "", err
+	}
+	// The output is meant to be shared
+	if err := os.WriteFile(outputPath, append(utf8BOM, text...), 0666); err != nil { //nolint:gosec
+		if rmErr := os.Remove(outputPath); rmErr != nil && !os.IsNotExist(rmErr) {
+			slog.Error("failed to remove partial output", "path", outputPath, "error", rmErr)
+		}
+		return "", fmt.Errorf("failed to save output file: %w", err)
+	}
+	if p.sharedOutput {
+		if err := applySharedPermissions(outputPath); err != nil {
+			slog.Warn("failed to apply shared permissions", "path", outputPath, "error", err)
+		}
+	}
+	return outputPath, nil
+}
+
+// ConvertString converts pasted text like the content of a text file (see runText),
+// configured by opts, and returns it with the source encoding: the forced one, the one
+// detected from the whole text, or EncodingUnknown when no legacy text was found.
+// Why: Users fixing a snippet from an email or a chat should not have to save it as a file.
+func ConvertString(text string, opts ...Option) (string, converter.EncodingType, error) {
+	p, err := newProcessorWithOptions(opts)
+	if err != nil {
+		return "", "", err
+	}
+	converted, enc := p.convertTextContent([]byte(text))
+	return converted, enc, nil
+}
+
+// convertTextContent converts the content of a text file to Unicode and returns it with the
+// source encoding. Valid UTF-8 is read as is (e.g. VNI text pasted into a UTF-8 editor);
+// anything else is read as legacy bytes.
+func (p *Processor) convertTextContent(data []byte) (string, converter.EncodingType) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	text := string(data)
+	if !utf8.Valid(data) {
+		// Every byte-oriented encoding decodes the same way, so the choice of VNI here
+		// does not affect detection
+		text, _ = converter.DecodeBytes(converter.EncodingVNI, data)
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	// Detect on the legacy lines only, so a few Unicode lines do not hide the rest
+	var legacy strings.Builder
+	for _, line := range lines {
+		if _, ok := detectUnicode(line); !ok {
+			legacy.WriteString(line)
+		}
+	}
+	enc := p.detectText(legacy.String())
+	c, err := p.textConverter(enc)
+	if err != nil {
+		return text, converter.EncodingUnknown
+	}
+
+	var out strings.Builder
+	out.Grow(len(text))
+	for _, line := range lines {
+		if _, ok := detectUnicode(line); ok {
+			out.WriteString(line)
+			continue
+		}
+		out.WriteString(c.ToUnicode(line))
+	}
+	return out.String(), enc
+}
+
+// detectText returns the forced source encoding, or the encoding detected from text.
+// Text without VNI/TCVN3 markers is checked for VIQR, which cells never use.
+func (p *Processor) detectText(text string) converter.EncodingType {
+	if p.preserver.sourceEncoding != "" {
+		return p.preserver.sourceEncoding
+	}
+	if enc := p.preserver.detector.Detect("", text).Encoding; enc != converter.EncodingUnknown {
+		return enc
+	}
+	if looksLikeVIQR(text) {
+		return converter.EncodingVIQR
+	}
+	return converter.EncodingUnknown
+}
+
+// looksLikeVIQR reports whether enough words of text carry a VIQR mark: a shape mark
+// after its vowel ("a^"), a tone mark after a vowel ("Vie^.t", "Ha`") or a leading "dd".
+func looksLikeVIQR(text string) bool {
+	words, marked := 0, 0
+	for _, word := range strings.Fields(text) {
+		if !strings.ContainsAny(word, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+			continue
+		}
+		words++
+		if hasVIQRMark(word) {
+			marked++
+		}
+	}
+	return words > 0 && float64(marked) >= viqrMinMarkedWords*float64(words)
+}
+
+// hasVIQRMark reports whether word contains a VIQR mark (see looksLikeVIQR).
+func hasVIQRMark(word string) bool {
+	if len(word) > 2 && strings.EqualFold(word[:2], "dd") {
+		return true
+	}
+	for i := 1; i < len(word); i++ {
+		vowel := strings.IndexByte("aeiouyAEIOUY", word[i-1]) >= 0
+		switch {
+		case vowel && strings.IndexByte("^(+*", word[i]) >= 0:
+			return true
+		case (vowel || strings.IndexByte("^(+*", word[i-1]) >= 0) && strings.IndexByte("'`?~.", word[i]) >= 0:
+			// A period or question mark ends many English sentences; only count it inside a word
+			if (word[i] == '.' || word[i] == '?') && i == len(word)-1 {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
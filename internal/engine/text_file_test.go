@@ -0,0 +1,162 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+)
+
+func TestRunTextFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []byte
+		force   converter.EncodingType
+		want    string
+		wantEnc converter.EncodingType
+	}{
+		{
+			name:    "VNI bytes",
+			input:   []byte("Vie\xe2\xeft Nam\r\nHa\xf8 No\xe2\xefi\r\n"),
+			want:    "Việt Nam\r\nHà Nội\r\n",
+			wantEnc: converter.EncodingVNI,
+		},
+		{
+			name:    "TCVN3 bytes",
+			input:   []byte("C\xabng ty\n"),
+			want:    "Công ty\n",
+			wantEnc: converter.EncodingTCVN3,
+		},
+		{
+			name:    "VIQR",
+			input:   []byte("Vie^.t Nam\nHa` No^.i\n"),
+			want:    "Việt Nam\nHà Nội\n",
+			wantEnc: converter.EncodingVIQR,
+		},
+		{
+			name:    "Unicode lines kept",
+			input:   []byte("\xef\xbb\xbfViệt Nam\nVi\u00D6t Nam\n"),
+			want:    "Việt Nam\nViệt Nam\n",
+			wantEnc: converter.EncodingVNI,
+		},
+		{
+			name:    "English",
+			input:   []byte("Is this the report? I'm not sure.\n"),
+			want:    "Is this the report? I'm not sure.\n",
+			wantEnc: converter.EncodingUnknown,
+		},
+		{
+			name:    "Forced encoding",
+			input:   []byte("Ha` No^.i"),
+			force:   converter.EncodingVIQR,
+			want:    "Hà Nội",
+			wantEnc: converter.EncodingVIQR,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := filepath.Join(t.TempDir(), "notes.txt")
+			if err := os.WriteFile(input, tt.input, 0o600); err != nil {
+				t.Fatalf("failed to write input: %v", err)
+			}
+			p := NewProcessor(input, "")
+			if err := p.SetSourceEncoding(tt.force); err != nil {
+				t.Fatalf("SetSourceEncoding failed: %v", err)
+			}
+			output, err := p.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if filepath.Ext(output) != ".txt" {
+				t.Errorf("output = %s, want a .txt file", output)
+			}
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			if !bytes.HasPrefix(data, utf8BOM) {
+				t.Errorf("output does not start with a UTF-8 BOM")
+			}
+			if got := string(bytes.TrimPrefix(data, utf8BOM)); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+			if got := p.TextEncoding(); got != tt.wantEnc {
+				t.Errorf("TextEncoding() = %s, want %s", got, tt.wantEnc)
+			}
+		})
+	}
+}
+
+func TestRunTextFileInPlace(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(input, []byte("Ha` No^.i"), 0o600); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	p := NewProcessor(input, "")
+	p.SetInPlace(BackupFile)
+	if _, err := p.Run(context.Background()); !errors.Is(err, errTextUnsupported) {
+		t.Errorf("Run error = %v, want errTextUnsupported", err)
+	}
+}
+
+func TestLooksLikeVIQR(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "Nguye^~n Va(n A", want: true},
+		{text: "ddi ho.c", want: true},
+		{text: "Hello world.", want: false},
+		{text: "What's up? Add it to the list.", want: false},
+		{text: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := looksLikeVIQR(tt.text); got != tt.want {
+				t.Errorf("looksLikeVIQR(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertString(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		opts    []Option
+		want    string
+		wantEnc converter.EncodingType
+	}{
+		{name: "Detected", text: "Vi\u00D6t Nam\nVi\u1EC7t Nam", want: "Vi\u1EC7t Nam\nVi\u1EC7t Nam", wantEnc: converter.EncodingVNI},
+		{name: "VIQR detected", text: "Ha` No^.i", want: "H\u00E0 N\u1ED9i", wantEnc: converter.EncodingVIQR},
+		{name: "Forced", text: "C\u00ABng ty", opts: []Option{WithSourceEncoding(converter.EncodingTCVN3)}, want: "C\u00F4ng ty", wantEnc: converter.EncodingTCVN3},
+		{name: "No legacy text", text: "Hello", want: "Hello", wantEnc: converter.EncodingUnknown},
+		{
+			name: "VNI strictness", text: "T\u00D6",
+			opts: []Option{WithSourceEncoding(converter.EncodingVNI), WithVNIStrictness(converter.VNIStrictnessPreferTCVN3)},
+			want: "T\u1EC7", wantEnc: converter.EncodingVNI,
+		},
+		{
+			name: "NFD", text: "Vi\u00D6t Nam", opts: []Option{WithNormalization(converter.NormalizationNFD)},
+			want: "Vie\u0323\u0302t Nam", wantEnc: converter.EncodingVNI,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, enc, err := ConvertString(tt.text, tt.opts...)
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if got != tt.want || enc != tt.wantEnc {
+				t.Errorf("ConvertString() = %q, %s; want %q, %s", got, enc, tt.want, tt.wantEnc)
+			}
+		})
+	}
+	if _, _, err := ConvertString("x", WithSourceEncoding("EBCDIC")); err == nil {
+		t.Error("ConvertString() accepted an unknown encoding")
+	}
+}
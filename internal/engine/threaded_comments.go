@@ -0,0 +1,164 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/xml"
+	"html"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Threaded comment parts of the package, as Excel 365 writes them.
+const (
+	threadedCommentRelType = "http://schemas.microsoft.com/office/2017/10/relationships/threadedComment"
+	personPartPrefix       = "xl/persons/"
+)
+
+var (
+	// threadedCommentRe matches one threaded comment or reply with its attributes and body.
+	threadedCommentRe = regexp.MustCompile(`(?s)<threadedComment\b([^>]*)>(.*?)</threadedComment>`)
+	// threadedTextRe matches the plain text of a threaded comment.
+	threadedTextRe = regexp.MustCompile(`(<text>)([^<]+)(</text>)`)
+	// threadedRefRe matches the cell a threaded comment is attached to.
+	threadedRefRe = regexp.MustCompile(`\bref="([^"]*)"`)
+	// personNameRe matches the display name of a threaded comment author.
+	personNameRe = regexp.MustCompile(`(\bdisplayName=")([^"]*)(")`)
+)
+
+// packageRels is the part of a relationships part read to find threaded comments.
+type packageRels struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// convertThreadedComments converts the text of threaded comments and replies on the
+// given sheets, and the display names of their authors, and returns the number of
+// comments changed. It must run on the collector goroutine.
+// Why: excelize does not read threaded comments but keeps their parts as raw XML in
+// f.Pkg, like charts, so they are rewritten there. Excel shows the threaded text, not the
+// legacy copy convertComments converts.
+func (p *Processor) convertThreadedComments(sheets []string) int {
+	converted := 0
+	for _, part := range p.threadedCommentParts(sheets) {
+		data, ok := p.loadPart(part)
+		if !ok {
+			continue
+		}
+		updated := threadedCommentRe.ReplaceAllFunc(data, func(match []byte) []byte {
+			m := threadedCommentRe.FindSubmatch(match)
+			if ref := threadedRefRe.FindSubmatch(m[1]); ref != nil && !p.cellFilter.ContainsRef(string(ref[1])) {
+				return match
+			}
+			comment := threadedTextRe.ReplaceAllFunc(match, func(text []byte) []byte {
+				t := threadedTextRe.FindSubmatch(text)
+				plain := html.UnescapeString(string(t[2]))
+				convertedText, _ := p.preserver.ConvertRun("", plain)
+				if convertedText == plain {
+					return text
+				}
+				return joinXMLText(t[1], convertedText, t[3])
+			})
+			if !bytes.Equal(comment, match) {
+				converted++
+			}
+			return comment
+		})
+		if !bytes.Equal(updated, data) {
+			p.f.Pkg.Store(part, updated)
+		}
+	}
+
+	// Authors are shared by all sheets, like the authors of legacy comments
+	p.f.Pkg.Range(func(key, value any) bool {
+		part, ok := key.(string)
+		data, isBytes := value.([]byte)
+		if !ok || !isBytes || !strings.HasPrefix(part, personPartPrefix) || !strings.HasSuffix(part, ".xml") {
+			return true
+		}
+		updated := personNameRe.ReplaceAllFunc(data, func(match []byte) []byte {
+			m := personNameRe.FindSubmatch(match)
+			name := html.UnescapeString(string(m[2]))
+			convertedName, _ := p.preserver.ConvertRun("", name)
+			if convertedName == name {
+				return match
+			}
+			var b bytes.Buffer
+			b.Write(m[1])
+			_ = xml.EscapeText(&b, []byte(convertedName))
+			b.Write(m[3])
+			return b.Bytes()
+		})
+		if !bytes.Equal(updated, data) {
+			p.f.Pkg.Store(part, updated)
+		}
+		return true
+	})
+	return converted
+}
+
+// threadedCommentParts returns the threaded comment parts of the given sheets, found
+// through the workbook and worksheet relationships.
+func (p *Processor) threadedCommentParts(sheets []string) []string {
+	wb := p.f.WorkBook
+	if wb == nil {
+		return nil
+	}
+	selected := make(map[string]bool, len(sheets))
+	for _, sheet := range sheets {
+		selected[sheet] = true
+	}
+	sheetParts := p.relTargets("xl/workbook.xml", "")
+
+	var parts []string
+	for _, sheet := range wb.Sheets.Sheet {
+		sheetPart, ok := sheetParts[sheet.ID]
+		if !selected[sheet.Name] || !ok {
+			continue
+		}
+		for _, part := range p.relTargets(sheetPart, threadedCommentRelType) {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// relTargets returns the package paths of the relationships of part, by relationship
+// ID, keeping only those of relType unless it is empty.
+func (p *Processor) relTargets(part, relType string) map[string]string {
+	dir, file := path.Split(part)
+	data, ok := p.loadPart(dir + "_rels/" + file + ".rels")
+	if !ok {
+		return nil
+	}
+	var rels packageRels
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return nil
+	}
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		if relType != "" && rel.Type != relType {
+			continue
+		}
+		// Targets are relative to the part's folder, or to the package root with a leading "/"
+		target := path.Join(dir, rel.Target)
+		if strings.HasPrefix(rel.Target, "/") {
+			target = strings.TrimPrefix(rel.Target, "/")
+		}
+		targets[rel.ID] = target
+	}
+	return targets
+}
+
+// loadPart returns the raw content of a package part.
+func (p *Processor) loadPart(part string) ([]byte, bool) {
+	value, ok := p.f.Pkg.Load(part)
+	if !ok {
+		return nil, false
+	}
+	data, ok := value.([]byte)
+	return data, ok
+}
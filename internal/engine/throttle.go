@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// IOThrottle limits concurrent file opens and spaces them out in time.
+// Why: Batches reading from/writing to SMB shares must not saturate the office
+// file server during working hours. A nil *IOThrottle never blocks.
+type IOThrottle struct {
+	slots chan struct{}
+	delay time.Duration
+
+	mu       sync.Mutex
+	lastDone time.Time
+}
+
+// NewIOThrottle allows at most maxOpen concurrent file operations (minimum 1)
+// with at least delay between the end of one and the start of the next.
+func NewIOThrottle(maxOpen int, delay time.Duration) *IOThrottle {
+	if maxOpen < 1 {
+		maxOpen = 1
+	}
+	return &IOThrottle{slots: make(chan struct{}, maxOpen), delay: delay}
+}
+
+// Acquire waits for a free slot and the inter-file delay.
+// The returned release function must be called when the file operation is done.
+func (t *IOThrottle) Acquire(ctx context.Context) (func(), error) {
+	if t == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case t.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	t.mu.Lock()
+	wait := time.Until(t.lastDone.Add(t.delay))
+	t.mu.Unlock()
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			<-t.slots
+			return nil, ctx.Err()
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			t.lastDone = time.Now()
+			t.mu.Unlock()
+			<-t.slots
+		})
+	}, nil
+}
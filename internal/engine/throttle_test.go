@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIOThrottle_NilNeverBlocks(t *testing.T) {
+	var th *IOThrottle
+	release, err := th.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	release()
+}
+
+func TestIOThrottle_LimitsConcurrency(t *testing.T) {
+	th := NewIOThrottle(1, 0)
+	release, err := th.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := th.Acquire(ctx); err == nil {
+		t.Fatal("second Acquire should block until the slot is released")
+	}
+
+	release()
+	release2, err := th.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire after release failed: %v", err)
+	}
+	release2()
+}
+
+func TestIOThrottle_Delay(t *testing.T) {
+	delay := 30 * time.Millisecond
+	th := NewIOThrottle(2, delay)
+
+	release, err := th.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	release()
+
+	start := time.Now()
+	release, err = th.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	release()
+	if elapsed := time.Since(start); elapsed < delay/2 {
+		t.Errorf("inter-file delay not applied, waited %v", elapsed)
+	}
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultTimestampFormat is the output name suffix used when none is configured.
+const DefaultTimestampFormat = "yyyy_MM_dd_HH_mm_ss"
+
+// defaultTimestampLayout is DefaultTimestampFormat as a Go layout.
+const defaultTimestampLayout = "2006_01_02_15_04_05"
+
+// TimestampPresets are the suggested output suffix formats, most precise first.
+var TimestampPresets = []string{
+	DefaultTimestampFormat,
+	"yyyyMMdd_HHmmss",
+	"yyyy-MM-dd_HH-mm",
+	"yyyy-MM-dd",
+	"dd-MM-yyyy",
+}
+
+// timestampTokens maps format tokens to Go layout elements. Longer tokens come first
+// so "yyyy" is not read as "yy" twice.
+var timestampTokens = []struct{ token, layout string }{
+	{"yyyy", "2006"},
+	{"yy", "06"},
+	{"MM", "01"},
+	{"dd", "02"},
+	{"HH", "15"},
+	{"mm", "04"},
+	{"ss", "05"},
+}
+
+// timestampSeparators are the only literal characters allowed between tokens.
+const timestampSeparators = "_-."
+
+// ParseTimestampFormat converts a format such as "yyyy_MM_dd_HH_mm_ss" to a Go time layout.
+// An empty format selects DefaultTimestampFormat.
+// Why: Users think in yyyy/MM/dd tokens, not Go's reference time, and the result ends up
+// in a file name, so only separators that are valid on every filesystem are accepted.
+func ParseTimestampFormat(format string) (string, error) {
+	if format == "" {
+		format = DefaultTimestampFormat
+	}
+	var layout strings.Builder
+	tokens := 0
+	for rest := format; rest != ""; {
+		matched := false
+		for _, t := range timestampTokens {
+			if strings.HasPrefix(rest, t.token) {
+				layout.WriteString(t.layout)
+				rest = rest[len(t.token):]
+				tokens++
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if !strings.ContainsRune(timestampSeparators, rune(rest[0])) {
+			return "", fmt.Errorf("invalid timestamp format %q: unsupported character %q "+
+				"(use yyyy, yy, MM, dd, HH, mm, ss and %s)", format, rest[0], timestampSeparators)
+		}
+		layout.WriteByte(rest[0])
+		rest = rest[1:]
+	}
+	if tokens == 0 {
+		return "", fmt.Errorf("invalid timestamp format %q: no date or time fields", format)
+	}
+	return layout.String(), nil
+}
@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestampFormat(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{name: "Empty uses default", format: "", want: "2024_05_06_07_08_09"},
+		{name: "Compact", format: "yyyyMMdd_HHmmss", want: "20240506_070809"},
+		{name: "Short year", format: "yy.MM.dd", want: "24.05.06"},
+		{name: "Day first", format: "dd-MM-yyyy", want: "06-05-2024"},
+		{name: "Slash not allowed", format: "yyyy/MM/dd", wantErr: true},
+		{name: "Unknown token", format: "yyyy_MMM", wantErr: true},
+		{name: "Only separators", format: "__", wantErr: true},
+		{name: "Go layout rejected", format: "2006_01_02", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := ParseTimestampFormat(tt.format)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseTimestampFormat(%q) expected error, got layout %q", tt.format, layout)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTimestampFormat(%q) failed: %v", tt.format, err)
+			}
+			if got := now.Format(layout); got != tt.want {
+				t.Errorf("formatted = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimestampPresetsAreValid(t *testing.T) {
+	for _, preset := range TimestampPresets {
+		if _, err := ParseTimestampFormat(preset); err != nil {
+			t.Errorf("preset %q is invalid: %v", preset, err)
+		}
+	}
+}
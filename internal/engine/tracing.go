@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Tracer receives per-cell timing hooks from the worker pool.
+// Why: Lets us locate hotspots in real customer files without a profiler.
+// Implementations must be safe for concurrent use; hooks are called from every worker.
+type Tracer interface {
+	// OnCellStart is called when a worker picks up a cell.
+	OnCellStart(ctx context.Context, job Job)
+	// OnCellEnd is called once the cell has been converted.
+	OnCellEnd(ctx context.Context, job Job, elapsed time.Duration)
+}
+
+// CellTiming is a single entry of a timing report.
+type CellTiming struct {
+	SheetName string
+	Axis      string
+	TextLen   int
+	Elapsed   time.Duration
+}
+
+// TimingRecorder is a Tracer that keeps every cell timing in memory.
+// Why: The collected timings can be exported as a CSV report and opened in Excel.
+type TimingRecorder struct {
+	mu        sync.Mutex
+	timings   []CellTiming
+	buildInfo *BuildInfo
+}
+
+// NewTimingRecorder creates an empty recorder.
+func NewTimingRecorder() *TimingRecorder {
+	return &TimingRecorder{}
+}
+
+// SetBuildInfo adds a "# build" comment line to the top of the CSV report.
+func (r *TimingRecorder) SetBuildInfo(b BuildInfo) {
+	r.buildInfo = &b
+}
+
+// OnCellStart implements Tracer. The recorder only needs the end event.
+func (r *TimingRecorder) OnCellStart(_ context.Context, _ Job) {}
+
+// OnCellEnd implements Tracer.
+func (r *TimingRecorder) OnCellEnd(_ context.Context, job Job, elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timings = append(r.timings, CellTiming{
+		SheetName: job.SheetName,
+		Axis:      job.Axis,
+		TextLen:   len(job.Text),
+		Elapsed:   elapsed,
+	})
+}
+
+// Timings returns a copy of the recorded timings, slowest first.
+func (r *TimingRecorder) Timings() []CellTiming {
+	r.mu.Lock()
+	out := make([]CellTiming, len(r.timings))
+	copy(out, r.timings)
+	r.mu.Unlock()
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Elapsed > out[j].Elapsed })
+	return out
+}
+
+// WriteCSV writes the timing report (slowest cells first) to w.
+// Readers should set csv.Reader.Comment to '#' when a build line is present.
+func (r *TimingRecorder) WriteCSV(w io.Writer) error {
+	if r.buildInfo != nil {
+		if _, err := fmt.Fprintf(w, "# %s\n", r.buildInfo); err != nil {
+			return fmt.Errorf("failed to write build header: %w", err)
+		}
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"sheet", "cell", "text_bytes", "elapsed_us"}); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	for _, t := range r.Timings() {
+		record := []string{
+			t.SheetName,
+			t.Axis,
+			strconv.Itoa(t.TextLen),
+			strconv.FormatInt(t.Elapsed.Microseconds(), 10),
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv record: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
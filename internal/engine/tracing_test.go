@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestTimingRecorder_WriteCSV(t *testing.T) {
+	rec := NewTimingRecorder()
+	ctx := context.Background()
+	rec.OnCellEnd(ctx, Job{SheetName: "Sheet1", Axis: "A1", Text: "abc"}, 5*time.Microsecond)
+	rec.OnCellEnd(ctx, Job{SheetName: "Sheet1", Axis: "B2", Text: "x"}, 20*time.Microsecond)
+
+	var buf bytes.Buffer
+	if err := rec.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse csv: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("record count mismatch. Got %d, want 3", len(records))
+	}
+	// Slowest cell first
+	if records[1][1] != "B2" || records[1][3] != "20" {
+		t.Errorf("first record wrong: %v", records[1])
+	}
+	if records[2][1] != "A1" || records[2][2] != "3" {
+		t.Errorf("second record wrong: %v", records[2])
+	}
+}
+
+func TestProcessor_RunWithTracer(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "trace.xlsx")
+	f := excelize.NewFile()
+	for _, axis := range []string{"A1", "A2", "B3"} {
+		if err := f.SetCellValue("Sheet1", axis, "Vi\u00D6t Nam"); err != nil {
+			t.Fatalf("failed to set %s: %v", axis, err)
+		}
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save input: %v", err)
+	}
+	_ = f.Close()
+
+	rec := NewTimingRecorder()
+	proc := NewProcessor(inputFile, "")
+	proc.SetTracer(rec)
+	if _, err := proc.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if got := len(rec.Timings()); got != 3 {
+		t.Errorf("traced cell count mismatch. Got %d, want 3", got)
+	}
+}
+
+func TestProcessor_RunCancelled(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "cancel.xlsx")
+	f := excelize.NewFile()
+	if err := f.SetCellValue("Sheet1", "A1", "Vi\u00D6t Nam"); err != nil {
+		t.Fatalf("failed to set A1: %v", err)
+	}
+	if err := f.SaveAs(inputFile); err != nil {
+		t.Fatalf("failed to save input: %v", err)
+	}
+	_ = f.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	outputPath, err := NewProcessor(inputFile, "").Run(ctx)
+	if err == nil {
+		t.Fatalf("expected cancellation error, got output %s", outputPath)
+	}
+}
@@ -0,0 +1,138 @@
+package engine
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ErrCorruptOutput is wrapped by the error of a Run whose saved workbook failed
+// verification (see SetVerifyOutput); the corrupt output is not kept.
+var ErrCorruptOutput = errors.New("the converted workbook is corrupt")
+
+// SetVerifyOutput sets whether Run re-opens a saved workbook to check it before reporting
+// success (the default). In place, the check runs before the original is replaced.
+// Why: Rare rich-text writes produced files Excel only opens after "repairing" them,
+// which users noticed long after the conversion reported success.
+func (p *Processor) SetVerifyOutput(enabled bool) {
+	p.skipVerify = !enabled
+}
+
+// verifyOutput checks the workbook saved at path unless verification is off, and wraps
+// ErrCorruptOutput in the error of a workbook failing the check.
+func (p *Processor) verifyOutput(path string) error {
+	if p.skipVerify {
+		return nil
+	}
+	if err := verifyWorkbook(path, p.InputPath, p.broken, p.tempDir); err != nil {
+		return fmt.Errorf("%w: %w", ErrCorruptOutput, err)
+	}
+	return nil
+}
+
+// verifyWorkbook checks that every XML part of the workbook at output is well-formed (which
+// includes characters XML does not allow), then that excelize opens it and reads every row
+// of every sheet through the shared strings. Only what the conversion wrote is checked:
+// parts copied byte for byte from input and the broken sheets of input are skipped.
+func verifyWorkbook(output, input string, broken map[string]error, tempDir string) error {
+	zr, err := zip.OpenReader(output)
+	if err != nil {
+		return fmt.Errorf("not a valid package: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+	var original map[string]*zip.File
+	if in, err := zip.OpenReader(input); err == nil {
+		defer func() { _ = in.Close() }()
+		original = make(map[string]*zip.File, len(in.File))
+		for _, file := range in.File {
+			original[file.Name] = file
+		}
+	}
+	found := make(map[string]bool)
+	for _, file := range zr.File {
+		found[file.Name] = true
+		switch strings.ToLower(path.Ext(file.Name)) {
+		case ".xml", ".rels":
+			if err := checkXMLPart(file); err != nil && !samePart(file, original[file.Name]) {
+				return fmt.Errorf("part %s: %w", file.Name, err)
+			}
+		}
+	}
+	for _, name := range []string{"[Content_Types].xml", "xl/workbook.xml"} {
+		if !found[name] {
+			return fmt.Errorf("part %s is missing", name)
+		}
+	}
+
+	f, err := excelize.OpenFile(output, excelize.Options{TmpDir: tempDir})
+	if err != nil {
+		return fmt.Errorf("cannot be opened: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	for _, sheet := range f.GetSheetList() {
+		if _, ok := broken[sheet]; ok {
+			continue
+		}
+		if err := checkSheetRows(f, sheet); err != nil {
+			return fmt.Errorf("sheet %q: %w", sheet, err)
+		}
+	}
+	return nil
+}
+
+// checkXMLPart reads every token of an XML part of a package, checking that tags match.
+func checkXMLPart(file *zip.File) error {
+	r, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+	d := xml.NewDecoder(r)
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// samePart reports whether the parts a and b hold the same bytes; b may be nil.
+func samePart(a, b *zip.File) bool {
+	if b == nil || a.CRC32 != b.CRC32 || a.UncompressedSize64 != b.UncompressedSize64 {
+		return false
+	}
+	read := func(file *zip.File) ([]byte, error) {
+		r, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = r.Close() }()
+		return io.ReadAll(r)
+	}
+	da, errA := read(a)
+	db, errB := read(b)
+	return errA == nil && errB == nil && bytes.Equal(da, db)
+}
+
+// checkSheetRows reads every row of sheet.
+func checkSheetRows(f *excelize.File, sheet string) error {
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		if _, err := rows.Columns(); err != nil {
+			return err
+		}
+	}
+	return rows.Error()
+}
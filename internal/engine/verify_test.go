@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyWorkbook(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		part    string
+		edit    func([]byte) []byte
+		wantErr bool
+	}{
+		{name: "Valid", wantErr: false},
+		{name: "Truncated sheet", part: "xl/worksheets/sheet1.xml", edit: func(b []byte) []byte { return b[:len(b)/2] }, wantErr: true},
+		{name: "Illegal character in shared strings", part: "xl/sharedStrings.xml", edit: func(b []byte) []byte {
+			return bytes.Replace(b, []byte("Total"), []byte("To\x01tal"), 1)
+		}, wantErr: true},
+		{name: "Mismatched tags", part: "xl/styles.xml", edit: func(b []byte) []byte {
+			return bytes.Replace(b, []byte("</fonts>"), []byte("</font>"), 1)
+		}, wantErr: true},
+		{name: "Missing workbook part", part: "xl/workbook.xml", edit: func([]byte) []byte { return nil }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".xlsx")
+			writeConversionPathWorkbook(t, path)
+			if tt.part != "" {
+				rewriteZipPart(t, path, tt.part, tt.edit)
+			}
+			if err := verifyWorkbook(path, "", nil, ""); (err != nil) != tt.wantErr {
+				t.Errorf("verifyWorkbook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("Not a package", func(t *testing.T) {
+		path := filepath.Join(dir, "text.xlsx")
+		if err := os.WriteFile(path, []byte("not a workbook"), 0o600); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+		if err := verifyWorkbook(path, "", nil, ""); err == nil {
+			t.Error("verifyWorkbook() accepted a text file")
+		}
+	})
+}
+
+func TestProcessor_VerifyOutput(t *testing.T) {
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.xlsx")
+	valid := filepath.Join(dir, "valid.xlsx")
+	writeConversionPathWorkbook(t, valid)
+	writeConversionPathWorkbook(t, corrupt)
+	rewriteZipPart(t, corrupt, "docProps/app.xml", func(b []byte) []byte { return b[:len(b)-10] })
+
+	p := NewProcessor(valid, "")
+	if err := p.verifyOutput(corrupt); !errors.Is(err, ErrCorruptOutput) {
+		t.Errorf("verifyOutput() = %v, want ErrCorruptOutput", err)
+	}
+	if err := p.verifyOutput(valid); err != nil {
+		t.Errorf("verifyOutput() of a valid workbook = %v", err)
+	}
+	// A part copied unchanged from the input was not broken by the conversion
+	if err := NewProcessor(corrupt, "").verifyOutput(corrupt); err != nil {
+		t.Errorf("verifyOutput() of a part corrupt in the input = %v", err)
+	}
+	p.SetVerifyOutput(false)
+	if err := p.verifyOutput(corrupt); err != nil {
+		t.Errorf("verifyOutput() with verification off = %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Option configures the conversion run by ProcessWorkbook.
+// Why: In-process callers never see the Processor, so the setters are offered as options.
+type Option func(*Processor) error
+
+// WithSheet converts only the named sheet (default: all sheets).
+func WithSheet(name string) Option {
+	return func(p *Processor) error {
+		p.SheetName = name
+		return nil
+	}
+}
+
+// WithSourceEncoding forces the source encoding (see SetSourceEncoding).
+func WithSourceEncoding(enc converter.EncodingType) Option {
+	return func(p *Processor) error { return p.SetSourceEncoding(enc) }
+}
+
+// WithFontPolicy sets the output font policy (see SetFontPolicy).
+func WithFontPolicy(policy FontPolicy) Option {
+	return func(p *Processor) error {
+		p.SetFontPolicy(policy)
+		return nil
+	}
+}
+
+// WithVNIStrictness sets how VNI text reads the ambiguous Ö/ö (see SetVNIStrictness).
+func WithVNIStrictness(s converter.VNIStrictness) Option {
+	return func(p *Processor) error {
+		p.SetVNIStrictness(s)
+		return nil
+	}
+}
+
+// WithNormalization writes converted text in the Unicode form n (see SetNormalization).
+func WithNormalization(n converter.Normalization) Option {
+	return func(p *Processor) error {
+		p.SetNormalization(n)
+		return nil
+	}
+}
+
+// WithDetector sets the detector used in auto-detect mode (see NewDetector).
+func WithDetector(d Detector) Option {
+	return func(p *Processor) error {
+		p.SetDetector(d)
+		return nil
+	}
+}
+
+// WithMaxCellLength skips cells longer than n characters (see SetMaxCellLength).
+func WithMaxCellLength(n int) Option {
+	return func(p *Processor) error {
+		p.SetMaxCellLength(n)
+		return nil
+	}
+}
+
+// WithWorkerCount sets the number of workers converting cells (see SetWorkerCount).
+func WithWorkerCount(n int) Option {
+	return func(p *Processor) error {
+		p.SetWorkerCount(n)
+		return nil
+	}
+}
+
+// WithCellFilter restricts conversion to a cell range and/or columns (see ParseCellFilter).
+func WithCellFilter(f *CellFilter) Option {
+	return func(p *Processor) error {
+		p.SetCellFilter(f)
+		return nil
+	}
+}
+
+// WithPlainCells writes plain cells back as plain strings (see SetPlainCells).
+func WithPlainCells(enabled bool) Option {
+	return func(p *Processor) error {
+		p.SetPlainCells(enabled)
+		return nil
+	}
+}
+
+// WithRemapStyleFonts rewrites legacy fonts of the styles table (see SetRemapStyleFonts).
+func WithRemapStyleFonts(enabled bool) Option {
+	return func(p *Processor) error {
+		p.SetRemapStyleFonts(enabled)
+		return nil
+	}
+}
+
+// WithConvertSheetNames enables or disables renaming legacy-encoded sheets (default on).
+func WithConvertSheetNames(enabled bool) Option {
+	return func(p *Processor) error {
+		p.SetConvertSheetNames(enabled)
+		return nil
+	}
+}
+
+// WithStatusChan streams progress updates to ch (see SetStatusChan).
+func WithStatusChan(ch chan Status) Option {
+	return func(p *Processor) error {
+		p.SetStatusChan(ch)
+		return nil
+	}
+}
+
+// WithTracer sets per-cell tracing hooks (see SetTracer).
+func WithTracer(t Tracer) Option {
+	return func(p *Processor) error {
+		p.SetTracer(t)
+		return nil
+	}
+}
+
+// WorkbookResult lists what ProcessWorkbook changed or left alone.
+type WorkbookResult struct {
+	BrokenSheets   []string        `json:"brokenSheets,omitempty"`
+	RenamedSheets  []RenamedSheet  `json:"renamedSheets,omitempty"`
+	SkippedCells   []SkippedCell   `json:"skippedCells,omitempty"`
+	TruncatedCells []TruncatedCell `json:"truncatedCells,omitempty"`
+	CellErrors     []CellError     `json:"cellErrors,omitempty"`
+}
+
+// ProcessWorkbook converts an open workbook in place, exactly like Run but without reading
+// or writing any file. The caller keeps ownership of f: it is neither saved nor closed, and
+// must not be used by other goroutines until ProcessWorkbook returns.
+// Why: Programs that already hold a workbook (e.g. report generators) convert it in-process
+// instead of saving it, converting the file and reading the result back.
+func ProcessWorkbook(ctx context.Context, f *excelize.File, opts ...Option) (WorkbookResult, error) {
+	if f == nil {
+		return WorkbookResult{}, fmt.Errorf("no workbook to convert")
+	}
+	p, err := newProcessorWithOptions(opts)
+	if err != nil {
+		return WorkbookResult{}, err
+	}
+	slog.Info("in-memory conversion started", p.buildInfo.LogAttrs()...)
+	return p.processOpen(ctx, f)
+}
+
+// newProcessorWithOptions creates a Processor without an input path and applies opts.
+func newProcessorWithOptions(opts []Option) (*Processor, error) {
+	p := NewProcessor("", "")
+	for _, opt := range opts {
+		if err := opt(p); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// processOpen converts the open workbook f in place without saving it.
+func (p *Processor) processOpen(ctx context.Context, f *excelize.File) (WorkbookResult, error) {
+	p.f = f
+	sheets, err := p.resolveSheets()
+	if err != nil {
+		return WorkbookResult{}, err
+	}
+	p.findBrokenSheets()
+	if err := p.convert(ctx, sheets); err != nil {
+		return WorkbookResult{}, err
+	}
+	return WorkbookResult{
+		BrokenSheets:   p.BrokenSheets(),
+		RenamedSheets:  p.RenamedSheets(),
+		SkippedCells:   p.SkippedCells(),
+		TruncatedCells: p.TruncatedCells(),
+		CellErrors:     p.CellErrors(),
+	}, nil
+}
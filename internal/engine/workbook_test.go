@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestProcessWorkbook(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantA1  string
+		wantB1  string
+		wantErr bool
+	}{
+		{name: "All sheets", wantA1: "Việt Nam", wantB1: "Công ty"},
+		{name: "Only the first sheet", opts: []Option{WithSheet("Sheet1")}, wantA1: "Việt Nam", wantB1: "C\u00ABng ty"},
+		{name: "Forced encoding", opts: []Option{WithSourceEncoding(converter.EncodingVIQR)}, wantA1: "Vi\u00D6t Nam", wantB1: "C\u00ABng ty"},
+		{name: "Unknown sheet", opts: []Option{WithSheet("Missing")}, wantErr: true},
+		{name: "Invalid option", opts: []Option{WithSourceEncoding("EBCDIC")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := excelize.NewFile()
+			defer func() { _ = f.Close() }()
+			if _, err := f.NewSheet("Sheet2"); err != nil {
+				t.Fatalf("failed to add sheet: %v", err)
+			}
+			if err := f.SetCellValue("Sheet1", "A1", "Vi\u00D6t Nam"); err != nil {
+				t.Fatalf("failed to set A1: %v", err)
+			}
+			if err := f.SetCellValue("Sheet2", "B1", "C\u00ABng ty"); err != nil {
+				t.Fatalf("failed to set B1: %v", err)
+			}
+
+			_, err := ProcessWorkbook(context.Background(), f, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProcessWorkbook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got, _ := f.GetCellValue("Sheet1", "A1"); got != tt.wantA1 {
+				t.Errorf("Sheet1!A1 = %q, want %q", got, tt.wantA1)
+			}
+			if got, _ := f.GetCellValue("Sheet2", "B1"); got != tt.wantB1 {
+				t.Errorf("Sheet2!B1 = %q, want %q", got, tt.wantB1)
+			}
+		})
+	}
+}
+
+func TestProcessWorkbook_ReportsRenamedSheets(t *testing.T) {
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+	legacy := "Ba\u00F9o ca\u00F9o"
+	if err := f.SetSheetName("Sheet1", legacy); err != nil {
+		t.Fatalf("failed to rename sheet: %v", err)
+	}
+
+	result, err := ProcessWorkbook(context.Background(), f)
+	if err != nil {
+		t.Fatalf("ProcessWorkbook failed: %v", err)
+	}
+	if len(result.RenamedSheets) != 1 || result.RenamedSheets[0] != (RenamedSheet{From: legacy, To: "Báo cáo"}) {
+		t.Errorf("RenamedSheets = %+v, want %q -> %q", result.RenamedSheets, legacy, "Báo cáo")
+	}
+	if got := f.GetSheetList(); len(got) != 1 || got[0] != "Báo cáo" {
+		t.Errorf("sheets = %v, want the workbook itself renamed", got)
+	}
+}
@@ -0,0 +1,55 @@
+// Package eventlog writes short service summaries to the operating system log
+// (Windows Event Log, syslog elsewhere).
+// Why: IT monitoring already watches the OS log; unattended runs should surface
+// failures there without custom tooling.
+package eventlog
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Source is the event source / syslog tag used by the converter.
+const Source = "VniConverter"
+
+// Event IDs for the Windows Event Log (the EventCreate.exe message file covers 1-1000).
+const (
+	EventIDInfo  = 1
+	EventIDError = 2
+)
+
+// maxMessageLength keeps entries readable in event viewers.
+const maxMessageLength = 2000
+
+// Logger writes entries to the OS log.
+type Logger interface {
+	Info(msg string) error
+	Error(msg string) error
+	Close() error
+}
+
+// Nop discards every entry; used when the OS log is unavailable.
+type Nop struct{}
+
+// Info implements Logger.
+func (Nop) Info(string) error { return nil }
+
+// Error implements Logger.
+func (Nop) Error(string) error { return nil }
+
+// Close implements Logger.
+func (Nop) Close() error { return nil }
+
+// sanitize flattens msg to one bounded line, cutting long messages on a rune boundary
+// so Vietnamese file names are never split into invalid UTF-8.
+func sanitize(msg string) string {
+	msg = strings.Join(strings.Fields(msg), " ")
+	if len(msg) > maxMessageLength {
+		cut := maxMessageLength - 3
+		for cut > 0 && !utf8.RuneStart(msg[cut]) {
+			cut--
+		}
+		msg = msg[:cut] + "..."
+	}
+	return msg
+}
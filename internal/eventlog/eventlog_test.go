@@ -0,0 +1,50 @@
+package eventlog
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{name: "Short message unchanged", msg: "conversion started", want: "conversion started"},
+		{name: "Flattened to one line", msg: "FAIL a.xlsx:\n  failed to open excel", want: "FAIL a.xlsx: failed to open excel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitize(tt.msg); got != tt.want {
+				t.Errorf("sanitize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitize_Truncates(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+	}{
+		{name: "ASCII", msg: strings.Repeat("x", maxMessageLength+10)},
+		{name: "Multi-byte letters", msg: strings.Repeat("ệ", maxMessageLength)},
+		{name: "Multi-byte after ASCII prefix", msg: "x" + strings.Repeat("ệ", maxMessageLength)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sanitize(tt.msg)
+			if len(got) > maxMessageLength || !strings.HasSuffix(got, "...") {
+				t.Errorf("sanitize() length = %d, want at most %d ending in ...", len(got), maxMessageLength)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("sanitize() returned invalid UTF-8 ending in %q", got[len(got)-8:])
+			}
+			if len(got) < maxMessageLength-utf8.UTFMax {
+				t.Errorf("sanitize() cut too much: length %d", len(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+//go:build !windows && !plan9 && !js && !wasip1
+
+package eventlog
+
+import (
+	"fmt"
+	"log/syslog"
+)
+
+// syslogLog writes to the local syslog daemon.
+type syslogLog struct {
+	w *syslog.Writer
+}
+
+// Open returns a Logger writing to syslog with the given tag (daemon facility).
+func Open(source string) (Logger, error) {
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return &syslogLog{w: w}, nil
+}
+
+// Info implements Logger.
+func (l *syslogLog) Info(msg string) error { return l.w.Info(sanitize(msg)) }
+
+// Error implements Logger.
+func (l *syslogLog) Error(msg string) error { return l.w.Err(sanitize(msg)) }
+
+// Close implements Logger.
+func (l *syslogLog) Close() error { return l.w.Close() }
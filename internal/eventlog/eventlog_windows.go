@@ -0,0 +1,57 @@
+//go:build windows
+
+package eventlog
+
+import (
+	"fmt"
+	"strings"
+
+	winlog "golang.org/x/sys/windows/svc/eventlog"
+)
+
+// windowsLog writes to the Application event log through the Event Log API.
+type windowsLog struct {
+	log *winlog.Log
+}
+
+// Open returns a Logger writing to the Windows Application event log. It tries to
+// register source first; that needs administrator rights, and without it entries are
+// still written but Event Viewer shows them without their message text formatted.
+func Open(source string) (Logger, error) {
+	_ = Install(source)
+	l, err := winlog.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	return &windowsLog{log: l}, nil
+}
+
+// Install registers source in the Application log, using the EventCreate.exe message
+// file so the plain-text entries and IDs 1-1000 display as written. A source that is
+// already registered is not an error.
+// Why: Registration writes under HKLM, so it belongs in the (elevated) service install
+// rather than in every unattended run.
+func Install(source string) error {
+	err := winlog.InstallAsEventCreate(source, winlog.Error|winlog.Warning|winlog.Info)
+	if err != nil && !strings.HasSuffix(err.Error(), "registry key already exists") {
+		return fmt.Errorf("failed to register event source: %w", err)
+	}
+	return nil
+}
+
+// Uninstall removes the registration made by Install.
+func Uninstall(source string) error {
+	if err := winlog.Remove(source); err != nil {
+		return fmt.Errorf("failed to remove event source: %w", err)
+	}
+	return nil
+}
+
+// Info implements Logger.
+func (l *windowsLog) Info(msg string) error { return l.log.Info(EventIDInfo, sanitize(msg)) }
+
+// Error implements Logger.
+func (l *windowsLog) Error(msg string) error { return l.log.Error(EventIDError, sanitize(msg)) }
+
+// Close implements Logger.
+func (l *windowsLog) Close() error { return l.log.Close() }
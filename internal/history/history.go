@@ -0,0 +1,217 @@
+// Package history keeps a log of completed conversions.
+package history
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// ErrNotFound is returned by Get for an ID that is not in the history.
+var ErrNotFound = errors.New("conversion not found in history")
+
+// Entry is one completed conversion.
+type Entry struct {
+	ID         int    `json:"id"`
+	InputPath  string `json:"inputPath"`
+	OutputPath string `json:"outputPath"`
+	// Sheets lists the sheets with converted cells
+	Sheets []string `json:"sheets,omitempty"`
+	// CellsConverted counts the converted cells (runs for documents and presentations)
+	CellsConverted int `json:"cellsConverted"`
+	// DurationMs is how long the conversion took, in milliseconds
+	DurationMs int64 `json:"durationMs"`
+	// ByEncoding counts the converted cells by source encoding
+	ByEncoding map[string]int `json:"byEncoding,omitempty"`
+	Timestamp  time.Time      `json:"timestamp"`
+}
+
+// schema creates the conversions table. search_text holds the lower-cased paths and
+// sheets, so searches match case-insensitively beyond ASCII, unlike LIKE.
+const schema = `
+CREATE TABLE IF NOT EXISTS conversions (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	input_path      TEXT    NOT NULL,
+	output_path     TEXT    NOT NULL,
+	sheets          TEXT    NOT NULL,
+	cells_converted INTEGER NOT NULL,
+	duration_ms     INTEGER NOT NULL,
+	by_encoding     TEXT    NOT NULL,
+	timestamp       TEXT    NOT NULL,
+	search_text     TEXT    NOT NULL
+)`
+
+// entryColumns are the columns scanned into an Entry, in scanEntry order.
+const entryColumns = `id, input_path, output_path, sheets, cells_converted, duration_ms, by_encoding, timestamp`
+
+// Store is the conversion history kept in a local SQLite database.
+// Why: SQLite keeps searches fast however long the history grows, and a crash during a
+// write rolls back that entry only. The driver is pure Go, so builds need no cgo.
+// It is safe for concurrent use.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the history database at path, creating it if needed.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create history dir: %w", err)
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	// One connection serializes writers, which SQLite would otherwise reject as busy
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Add stamps e with the current time if it has none, stores it and returns it with its
+// assigned ID.
+func (s *Store) Add(e Entry) (Entry, error) {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	sheets, err := json.Marshal(e.Sheets)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to encode history entry: %w", err)
+	}
+	byEncoding, err := json.Marshal(e.ByEncoding)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to encode history entry: %w", err)
+	}
+	res, err := s.db.Exec(`INSERT INTO conversions (input_path, output_path, sheets, cells_converted, duration_ms,
+		by_encoding, timestamp, search_text) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.InputPath, e.OutputPath, string(sheets), e.CellsConverted, e.DurationMs, string(byEncoding),
+		e.Timestamp.Format(time.RFC3339Nano), e.searchText())
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to write history: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to write history: %w", err)
+	}
+	e.ID = int(id)
+	return e, nil
+}
+
+// ImportJSONL adds the entries of a history.jsonl file, the format the history was kept in
+// before SQLite, in file order, and returns how many it added. Lines that cannot be read
+// are skipped; IDs are assigned anew.
+func (s *Store) ImportJSONL(path string) (int, error) {
+	f, err := os.Open(path) //nolint:gosec // path is next to the settings file
+	if err != nil {
+		return 0, fmt.Errorf("failed to open old history: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	added := 0
+	lines := bufio.NewScanner(f)
+	lines.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lines.Scan() {
+		var e Entry
+		if json.Unmarshal(lines.Bytes(), &e) != nil {
+			continue
+		}
+		if _, err = s.Add(e); err != nil {
+			return added, err
+		}
+		added++
+	}
+	if err = lines.Err(); err != nil {
+		return added, fmt.Errorf("failed to read old history: %w", err)
+	}
+	return added, nil
+}
+
+// List returns up to limit entries, newest first; limit < 1 returns them all.
+func (s *Store) List(limit int) ([]Entry, error) {
+	return s.Search("", limit)
+}
+
+// Search returns up to limit entries whose input or output path, or one of whose sheets,
+// contains query (case-insensitive), newest first; limit < 1 returns every match.
+func (s *Store) Search(query string, limit int) ([]Entry, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if limit < 1 {
+		limit = -1 // no limit in SQLite
+	}
+	rows, err := s.db.Query(`SELECT `+entryColumns+` FROM conversions
+		WHERE ? = '' OR instr(search_text, ?) > 0 ORDER BY id DESC LIMIT ?`, query, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search history: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	var out []Entry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search history: %w", err)
+	}
+	return out, nil
+}
+
+// Get returns the entry with the given ID, or ErrNotFound.
+func (s *Store) Get(id int) (Entry, error) {
+	e, err := scanEntry(s.db.QueryRow(`SELECT `+entryColumns+` FROM conversions WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Entry{}, fmt.Errorf("conversion %d: %w", id, ErrNotFound)
+	}
+	return e, err
+}
+
+// scanner is a *sql.Row or *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanEntry reads an entry selected with entryColumns.
+func scanEntry(row scanner) (Entry, error) {
+	var (
+		e                  Entry
+		sheets, byEncoding string
+		timestamp          string
+	)
+	err := row.Scan(&e.ID, &e.InputPath, &e.OutputPath, &sheets, &e.CellsConverted, &e.DurationMs, &byEncoding,
+		&timestamp)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Entry{}, err
+		}
+		return Entry{}, fmt.Errorf("failed to read history: %w", err)
+	}
+	// Fields that cannot be decoded are left empty rather than hiding the whole entry
+	_ = json.Unmarshal([]byte(sheets), &e.Sheets)
+	_ = json.Unmarshal([]byte(byEncoding), &e.ByEncoding)
+	e.Timestamp, _ = time.Parse(time.RFC3339Nano, timestamp)
+	return e, nil
+}
+
+// searchText returns the lower-case text Search matches queries against: the paths and
+// sheets of e, one per line.
+func (e Entry) searchText() string {
+	fields := append([]string{e.InputPath, e.OutputPath}, e.Sheets...)
+	return strings.ToLower(strings.Join(fields, "\n"))
+}
@@ -0,0 +1,130 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestStore_AddSearch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "history.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for _, e := range []Entry{
+		{InputPath: `C:\Docs\report.xlsx`, OutputPath: `C:\Docs\report_output.xlsx`, Sheets: []string{"Sheet1"}, CellsConverted: 3},
+		{InputPath: `C:\Docs\budget.xlsx`, OutputPath: `C:\Docs\budget_output.xlsx`, Sheets: []string{"Báo cáo"}, ByEncoding: map[string]int{"VNI": 2}},
+		{InputPath: `C:\Notes\notes.txt`, OutputPath: `C:\Notes\notes_output.txt`},
+	} {
+		if _, err := s.Add(e); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Reopen to verify persistence
+	s2, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	defer func() { _ = s2.Close() }()
+	tests := []struct {
+		name    string
+		query   string
+		limit   int
+		wantIDs []int
+	}{
+		{name: "All newest first", wantIDs: []int{3, 2, 1}},
+		{name: "Limit", limit: 2, wantIDs: []int{3, 2}},
+		{name: "Folder", query: `c:\docs`, wantIDs: []int{2, 1}},
+		{name: "Sheet", query: "báo", wantIDs: []int{2}},
+		{name: "Non-ASCII case", query: "BÁO CÁO", wantIDs: []int{2}},
+		{name: "No match", query: "invoice", wantIDs: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s2.Search(tt.query, tt.limit)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("Search() = %+v, want IDs %v", got, tt.wantIDs)
+			}
+			for i, id := range tt.wantIDs {
+				if got[i].ID != id {
+					t.Errorf("entry %d ID = %d, want %d", i, got[i].ID, id)
+				}
+			}
+		})
+	}
+
+	e, err := s2.Get(2)
+	if err != nil || e.ByEncoding["VNI"] != 2 || e.Timestamp.IsZero() || len(e.Sheets) != 1 {
+		t.Errorf("Get(2) = %+v, %v", e, err)
+	}
+	if _, err := s2.Get(9); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(9) error = %v, want ErrNotFound", err)
+	}
+	if next, err := s2.Add(Entry{InputPath: "x.xlsx"}); err != nil || next.ID != 4 {
+		t.Errorf("Add() after reopen = %+v, %v; want ID 4", next, err)
+	}
+}
+
+func TestStore_ConcurrentAdd(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	// Parallel batch jobs record their files at the same time
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Add(Entry{InputPath: "a.xlsx"}); err != nil {
+				t.Errorf("Add failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got, err := s.List(0); err != nil || len(got) != 20 {
+		t.Errorf("List() = %d entries, %v; want 20", len(got), err)
+	}
+}
+
+func TestStore_ImportJSONL(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "history.jsonl")
+	lines := `{"id":1,"inputPath":"a.xlsx","outputPath":"a_output.xlsx","cellsConverted":2,"timestamp":"2024-05-01T10:00:00Z"}
+not json
+{"id":2,"inputPath":"b.xlsx","outputPath":"b_output.xlsx","sheets":["Sheet1"]}
+`
+	if err := os.WriteFile(old, []byte(lines), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(filepath.Join(dir, "history.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	n, err := s.ImportJSONL(old)
+	if err != nil || n != 2 {
+		t.Fatalf("ImportJSONL() = %d, %v; want 2", n, err)
+	}
+	got, err := s.List(0)
+	if err != nil || len(got) != 2 {
+		t.Fatalf("List() = %+v, %v; want 2 entries", got, err)
+	}
+	if got[1].InputPath != "a.xlsx" || got[1].Timestamp.Year() != 2024 || got[0].Sheets[0] != "Sheet1" {
+		t.Errorf("imported entries = %+v", got)
+	}
+}
@@ -0,0 +1,68 @@
+// Package i18n provides localized user-facing text generated by the backend.
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Lang is a supported UI language.
+type Lang string
+
+const (
+	// LangEnglish is the default language
+	LangEnglish Lang = "en"
+	// LangVietnamese is Vietnamese
+	LangVietnamese Lang = "vi"
+)
+
+// Parse returns the matching Lang, falling back to English.
+func Parse(s string) Lang {
+	if Lang(s) == LangVietnamese {
+		return LangVietnamese
+	}
+	return LangEnglish
+}
+
+// FormatCount formats n with the language's thousands separator.
+// Why: Screen readers announce "12,300" (en) and "12.300" (vi) naturally.
+func FormatCount(lang Lang, n int) string {
+	sep := ','
+	if lang == LangVietnamese {
+		sep = '.'
+	}
+
+	digits := strconv.Itoa(n)
+	neg := n < 0
+	if neg {
+		digits = digits[1:]
+	}
+
+	out := make([]rune, 0, len(digits)+len(digits)/3+1)
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			out = append(out, sep)
+		}
+		out = append(out, d)
+	}
+	if neg {
+		return "-" + string(out)
+	}
+	return string(out)
+}
+
+// ProgressText builds a human-readable progress sentence.
+// total <= 0 means the total is unknown.
+func ProgressText(lang Lang, sheet string, processed, total int) string {
+	done := FormatCount(lang, processed)
+	if lang == LangVietnamese {
+		if total > 0 {
+			return fmt.Sprintf("Đang chuyển đổi trang tính %s, %s trên %s ô", sheet, done, FormatCount(lang, total))
+		}
+		return fmt.Sprintf("Đang chuyển đổi trang tính %s, đã xử lý %s ô", sheet, done)
+	}
+	if total > 0 {
+		return fmt.Sprintf("Converting sheet %s, %s of %s cells", sheet, done, FormatCount(lang, total))
+	}
+	return fmt.Sprintf("Converting sheet %s, %s cells processed", sheet, done)
+}
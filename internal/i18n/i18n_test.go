@@ -0,0 +1,61 @@
+package i18n
+
+import "testing"
+
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     Lang
+		input    int
+		expected string
+	}{
+		{name: "Small number", lang: LangEnglish, input: 999, expected: "999"},
+		{name: "English thousands", lang: LangEnglish, input: 12300, expected: "12,300"},
+		{name: "Vietnamese thousands", lang: LangVietnamese, input: 48000, expected: "48.000"},
+		{name: "Millions", lang: LangEnglish, input: 1234567, expected: "1,234,567"},
+		{name: "Negative", lang: LangEnglish, input: -1500, expected: "-1,500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatCount(tt.lang, tt.input); got != tt.expected {
+				t.Errorf("FormatCount() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProgressText(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     Lang
+		total    int
+		expected string
+	}{
+		{
+			name:     "English with total",
+			lang:     LangEnglish,
+			total:    48000,
+			expected: "Converting sheet Báo cáo, 12,300 of 48,000 cells",
+		},
+		{
+			name:     "English without total",
+			lang:     LangEnglish,
+			expected: "Converting sheet Báo cáo, 12,300 cells processed",
+		},
+		{
+			name:     "Vietnamese with total",
+			lang:     LangVietnamese,
+			total:    48000,
+			expected: "Đang chuyển đổi trang tính Báo cáo, 12.300 trên 48.000 ô",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProgressText(tt.lang, "Báo cáo", 12300, tt.total); got != tt.expected {
+				t.Errorf("ProgressText() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
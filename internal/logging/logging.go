@@ -0,0 +1,82 @@
+// Package logging sets up the application logger: leveled, text or JSON, written to a
+// rotating file in the app data folder.
+// Why: The GUI has no console, so without a file the slog output of the engine, the
+// updater and the app is lost exactly when a user reports a problem.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// FileName is the name of the current log file; rotated files get ".1", ".2", ... appended.
+const FileName = "VniConverter.log"
+
+// Defaults for Options.
+const (
+	DefaultMaxSize    = 5 << 20 // bytes
+	DefaultMaxBackups = 3
+)
+
+// Options configures Setup.
+type Options struct {
+	// Level is the minimum level written
+	Level slog.Level
+	// JSON writes one JSON object per entry instead of key=value text
+	JSON bool
+	// Dir is the folder of the log file; empty logs to Console only
+	Dir string
+	// MaxSize is the file size in bytes that triggers a rotation (0: DefaultMaxSize)
+	MaxSize int64
+	// MaxBackups is the number of rotated files kept (0: DefaultMaxBackups)
+	MaxBackups int
+	// Console also receives every entry, e.g. os.Stderr for headless subcommands (nil: none)
+	Console io.Writer
+}
+
+// ParseLevel parses "debug", "info", "warn" or "error" (case-insensitive); empty is info.
+func ParseLevel(s string) (slog.Level, error) {
+	if strings.TrimSpace(s) == "" {
+		return slog.LevelInfo, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q (use debug, info, warn or error)", s)
+	}
+	return level, nil
+}
+
+// Setup builds the logger described by opts. The returned Closer closes the log file.
+// Callers usually install the logger with slog.SetDefault, so the engine logs to it too.
+func Setup(opts Options) (*slog.Logger, io.Closer, error) {
+	var writers []io.Writer
+	var closer io.Closer = nopCloser{}
+	if opts.Dir != "" {
+		file, err := OpenRotatingFile(opts.Dir, opts.MaxSize, opts.MaxBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+		writers = append(writers, file)
+		closer = file
+	}
+	if opts.Console != nil {
+		writers = append(writers, opts.Console)
+	}
+
+	w := io.Discard
+	if len(writers) > 0 {
+		w = io.MultiWriter(writers...)
+	}
+	handlerOpts := &slog.HandlerOptions{Level: opts.Level}
+	var handler slog.Handler = slog.NewTextHandler(w, handlerOpts)
+	if opts.JSON {
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	}
+	return slog.New(handler), closer, nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
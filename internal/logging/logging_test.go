@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    slog.Level
+		wantErr bool
+	}{
+		{in: "", want: slog.LevelInfo},
+		{in: "debug", want: slog.LevelDebug},
+		{in: "WARN", want: slog.LevelWarn},
+		{in: " error ", want: slog.LevelError},
+		{in: "verbose", want: slog.LevelInfo, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLevel(tt.in)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("ParseLevel(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  bool
+		check func(t *testing.T, line string)
+	}{
+		{
+			name: "Text",
+			check: func(t *testing.T, line string) {
+				if !strings.Contains(line, "level=WARN") || !strings.Contains(line, "msg=converted") || !strings.Contains(line, "cells=3") {
+					t.Errorf("line = %q, want a text entry", line)
+				}
+			},
+		},
+		{
+			name: "JSON",
+			json: true,
+			check: func(t *testing.T, line string) {
+				var entry map[string]any
+				if err := json.Unmarshal([]byte(line), &entry); err != nil || entry["msg"] != "converted" || entry["cells"] != float64(3) {
+					t.Errorf("line = %q (%v), want a JSON entry", line, err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var console bytes.Buffer
+			logger, closer, err := Setup(Options{Level: slog.LevelWarn, JSON: tt.json, Dir: dir, Console: &console})
+			if err != nil {
+				t.Fatalf("Setup failed: %v", err)
+			}
+			logger.Info("below the level")
+			logger.Warn("converted", "cells", 3)
+			if err := closer.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, FileName))
+			if err != nil {
+				t.Fatalf("failed to read log file: %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+			if len(lines) != 1 {
+				t.Fatalf("log file has %d entries, want 1: %q", len(lines), data)
+			}
+			tt.check(t, lines[0])
+			if console.String() != string(data) {
+				t.Errorf("console = %q, want the same entries as the file", console.String())
+			}
+		})
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	r, err := OpenRotatingFile(dir, 10, 2)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	// Every entry but the first forces a rotation; only two old files are kept
+	for _, entry := range []string{"one-----\n", "two-----\n", "three---\n", "four----\n"} {
+		if _, err := r.Write([]byte(entry)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	want := map[string]string{
+		FileName:        "four----\n",
+		FileName + ".1": "three---\n",
+		FileName + ".2": "two-----\n",
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != len(want) {
+		t.Errorf("log folder has %d files, want %d", len(entries), len(want))
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != content {
+			t.Errorf("%s = %q (%v), want %q", name, data, err, content)
+		}
+	}
+	if _, err := r.Write([]byte("late\n")); err == nil {
+		t.Error("Write after Close succeeded, want an error")
+	}
+}
@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFile is a log file that is renamed to "<name>.1" once it reaches its maximum
+// size, shifting older files up to MaxBackups. It is safe for concurrent use.
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// OpenRotatingFile opens (or creates) FileName in dir, creating dir if needed.
+// maxSize and maxBackups of 0 select DefaultMaxSize and DefaultMaxBackups.
+func OpenRotatingFile(dir string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if maxBackups <= 0 {
+		maxBackups = DefaultMaxBackups
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create log folder: %w", err)
+	}
+	r := &RotatingFile{path: filepath.Join(dir, FileName), maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Path returns the path of the current log file.
+func (r *RotatingFile) Path() string {
+	return r.path
+}
+
+// Write appends p, rotating first if p would take the file past its maximum size.
+// An entry is never split across files. If rotating fails (e.g. another process holds
+// the file on Windows), the entry is appended to the current file instead of being lost.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil && r.file == nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the current log file.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// open opens the current log file for appending. The caller holds mu (or owns r).
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	r.file, r.size = f, info.Size()
+	return nil
+}
+
+// rotate shifts "<name>.N" to "<name>.N+1", dropping the oldest, and starts a new file.
+// The current file is reopened even when a rename fails. The caller holds mu.
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	r.file = nil
+	renameErr := r.shiftBackups()
+	if err := r.open(); err != nil {
+		return err
+	}
+	return renameErr
+}
+
+// shiftBackups renames the current file and its backups one number up.
+func (r *RotatingFile) shiftBackups() error {
+	for n := r.maxBackups - 1; n >= 1; n-- {
+		from := fmt.Sprintf("%s.%d", r.path, n)
+		if err := os.Rename(from, fmt.Sprintf("%s.%d", r.path, n+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+	// Rename replaces the target on Windows too
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	return nil
+}
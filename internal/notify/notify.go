@@ -0,0 +1,178 @@
+// Package notify reports the progress and outcome of conversion jobs to people and tools
+// outside the converter: the GUI, a console, desktop notifications or chat webhooks.
+// Why: Long unattended batches finish while nobody watches; automation users want a
+// Slack/Teams message instead of polling the output folder.
+package notify
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxListedFailures is how many failed files a summary text names.
+const maxListedFailures = 5
+
+// Start describes a job that is starting.
+type Start struct {
+	JobID string   `json:"jobId"`
+	Files []string `json:"files"`
+}
+
+// Progress is a progress update of the file a job is converting.
+type Progress struct {
+	JobID     string  `json:"jobId"`
+	File      string  `json:"file"`
+	Percent   float64 `json:"percent"`
+	Processed int     `json:"processed"`
+	Total     int     `json:"total"`
+}
+
+// Failure is a file a job failed to convert. File is empty when the job failed as a whole.
+type Failure struct {
+	JobID string `json:"jobId"`
+	File  string `json:"file,omitempty"`
+	Error string `json:"error"`
+}
+
+// Text returns the failure as one line.
+func (f Failure) Text() string {
+	if f.File == "" {
+		return "Conversion failed: " + f.Error
+	}
+	return fmt.Sprintf("Failed to convert %s: %s", f.File, f.Error)
+}
+
+// Summary describes a finished job.
+type Summary struct {
+	JobID    string        `json:"jobId"`
+	Files    int           `json:"files"`
+	Failures []Failure     `json:"failures,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Text returns the summary as a short message, naming the first failed files.
+func (s Summary) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d file(s) converted in %s.", s.Files-len(s.Failures), s.Files, s.Duration.Round(time.Second))
+	for i, f := range s.Failures {
+		if i == maxListedFailures {
+			fmt.Fprintf(&b, "\n... and %d more failure(s).", len(s.Failures)-i)
+			break
+		}
+		b.WriteString("\n" + f.Text())
+	}
+	return b.String()
+}
+
+// Notifier receives the events of conversion jobs. Methods are called from the goroutine
+// running the job and should return quickly; delivery is best effort, so implementations
+// log their own errors instead of failing the job.
+type Notifier interface {
+	OnStart(Start)
+	OnProgress(Progress)
+	OnComplete(Summary)
+	OnError(Failure)
+}
+
+// Multi sends every event to each of its notifiers in order.
+type Multi []Notifier
+
+// OnStart implements Notifier.
+func (m Multi) OnStart(s Start) {
+	for _, n := range m {
+		n.OnStart(s)
+	}
+}
+
+// OnProgress implements Notifier.
+func (m Multi) OnProgress(p Progress) {
+	for _, n := range m {
+		n.OnProgress(p)
+	}
+}
+
+// OnComplete implements Notifier.
+func (m Multi) OnComplete(s Summary) {
+	for _, n := range m {
+		n.OnComplete(s)
+	}
+}
+
+// OnError implements Notifier.
+func (m Multi) OnError(f Failure) {
+	for _, n := range m {
+		n.OnError(f)
+	}
+}
+
+// Nop discards every event.
+type Nop struct{}
+
+// OnStart implements Notifier.
+func (Nop) OnStart(Start) {}
+
+// OnProgress implements Notifier.
+func (Nop) OnProgress(Progress) {}
+
+// OnComplete implements Notifier.
+func (Nop) OnComplete(Summary) {}
+
+// OnError implements Notifier.
+func (Nop) OnError(Failure) {}
+
+// Console writes one line per event to W.
+type Console struct {
+	W io.Writer
+}
+
+// OnStart implements Notifier.
+func (c Console) OnStart(s Start) {
+	_, _ = fmt.Fprintf(c.W, "[%s] started: %d file(s)\n", s.JobID, len(s.Files))
+}
+
+// OnProgress implements Notifier.
+func (c Console) OnProgress(p Progress) {
+	_, _ = fmt.Fprintf(c.W, "[%s] %s: %.0f%% (%d/%d cells)\n", p.JobID, p.File, p.Percent, p.Processed, p.Total)
+}
+
+// OnComplete implements Notifier.
+func (c Console) OnComplete(s Summary) {
+	_, _ = fmt.Fprintf(c.W, "[%s] %s\n", s.JobID, strings.ReplaceAll(s.Text(), "\n", "\n    "))
+}
+
+// OnError implements Notifier.
+func (c Console) OnError(f Failure) {
+	_, _ = fmt.Fprintf(c.W, "[%s] %s\n", f.JobID, f.Text())
+}
+
+// Event names emitted by Events.
+const (
+	EventStart    = "notify:start"
+	EventProgress = "notify:progress"
+	EventComplete = "notify:complete"
+	EventError    = "notify:error"
+)
+
+// Events forwards every event to Emit under the Event* names, e.g. to the Wails runtime:
+//
+//	notify.Events{Emit: func(name string, data any) { runtime.EventsEmit(ctx, name, data) }}
+//
+// Why: The notify package stays free of GUI dependencies, so the CLI and service builds
+// do not link the Wails runtime.
+type Events struct {
+	Emit func(name string, data any)
+}
+
+// OnStart implements Notifier.
+func (e Events) OnStart(s Start) { e.Emit(EventStart, s) }
+
+// OnProgress implements Notifier.
+func (e Events) OnProgress(p Progress) { e.Emit(EventProgress, p) }
+
+// OnComplete implements Notifier.
+func (e Events) OnComplete(s Summary) { e.Emit(EventComplete, s) }
+
+// OnError implements Notifier.
+func (e Events) OnError(f Failure) { e.Emit(EventError, f) }
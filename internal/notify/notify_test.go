@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummary_Text(t *testing.T) {
+	failures := func(n int) []Failure {
+		var out []Failure
+		for i := range n {
+			out = append(out, Failure{File: fmt.Sprintf("f%d.xlsx", i), Error: "locked"})
+		}
+		return out
+	}
+	tests := []struct {
+		name    string
+		summary Summary
+		want    string
+	}{
+		{
+			name:    "All converted",
+			summary: Summary{Files: 3, Duration: 2500 * time.Millisecond},
+			want:    "3 of 3 file(s) converted in 3s.",
+		},
+		{
+			name:    "Failures listed",
+			summary: Summary{Files: 3, Failures: failures(1), Duration: time.Minute},
+			want:    "2 of 3 file(s) converted in 1m0s.\nFailed to convert f0.xlsx: locked",
+		},
+		{
+			name:    "Job failure",
+			summary: Summary{Files: 1, Failures: []Failure{{Error: "cancelled"}}},
+			want:    "0 of 1 file(s) converted in 0s.\nConversion failed: cancelled",
+		},
+		{
+			name:    "Long failure lists are cut",
+			summary: Summary{Files: 10, Failures: failures(7)},
+			want: "3 of 10 file(s) converted in 0s.\n" +
+				"Failed to convert f0.xlsx: locked\nFailed to convert f1.xlsx: locked\nFailed to convert f2.xlsx: locked\n" +
+				"Failed to convert f3.xlsx: locked\nFailed to convert f4.xlsx: locked\n... and 2 more failure(s).",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.summary.Text(); got != tt.want {
+				t.Errorf("Text() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMulti(t *testing.T) {
+	var emitted []string
+	events := Events{Emit: func(name string, _ any) { emitted = append(emitted, name) }}
+	var console bytes.Buffer
+	n := Multi{events, Console{W: &console}, Nop{}}
+
+	n.OnStart(Start{JobID: "job-1", Files: []string{"a.xlsx"}})
+	n.OnProgress(Progress{JobID: "job-1", File: "a.xlsx", Percent: 50, Processed: 5, Total: 10})
+	n.OnError(Failure{JobID: "job-1", File: "a.xlsx", Error: "locked"})
+	n.OnComplete(Summary{JobID: "job-1", Files: 1, Failures: []Failure{{File: "a.xlsx", Error: "locked"}}})
+
+	if want := []string{EventStart, EventProgress, EventError, EventComplete}; !reflect.DeepEqual(emitted, want) {
+		t.Errorf("emitted %v, want %v", emitted, want)
+	}
+	want := "[job-1] started: 1 file(s)\n" +
+		"[job-1] a.xlsx: 50% (5/10 cells)\n" +
+		"[job-1] Failed to convert a.xlsx: locked\n" +
+		"[job-1] 0 of 1 file(s) converted in 0s.\n    Failed to convert a.xlsx: locked\n"
+	if got := console.String(); got != want {
+		t.Errorf("console = %q, want %q", got, want)
+	}
+}
+
+func TestQuotePowerShell(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Báo cáo.xlsx", want: "'Báo cáo.xlsx'"},
+		{in: "it's", want: "'it''s'"},
+		{in: "it’s", want: "'it’’s'"},
+		{in: "$(Remove-Item x)", want: "'$(Remove-Item x)'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := quotePowerShell(tt.in); got != tt.want {
+				t.Errorf("quotePowerShell(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToastScript_EscapesMessage(t *testing.T) {
+	script := toastScript(toastTitle, "Failed to convert <a&b>.xlsx: it's locked")
+	if !strings.Contains(script, "<text>Failed to convert &lt;a&amp;b&gt;.xlsx: it&#39;s locked</text>") {
+		t.Errorf("message not escaped in script:\n%s", script)
+	}
+}
@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// toastAppID is the AppUserModelID toasts are shown under. Unpackaged apps have none
+// registered, so toasts borrow PowerShell's, which every Windows install has.
+const toastAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// toastTitle is the first line of every toast.
+const toastTitle = "VniConverter"
+
+// toastScript returns the PowerShell script showing a toast with title and message.
+func toastScript(title, message string) string {
+	payload := `<toast><visual><binding template="ToastGeneric"><text>` + escapeXML(title) +
+		`</text><text>` + escapeXML(message) + `</text></binding></visual></toast>`
+	return strings.Join([]string{
+		`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null`,
+		`[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] > $null`,
+		`$xml = New-Object Windows.Data.Xml.Dom.XmlDocument`,
+		`$xml.LoadXml(` + quotePowerShell(payload) + `)`,
+		`[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier(` + quotePowerShell(toastAppID) +
+			`).Show([Windows.UI.Notifications.ToastNotification]::new($xml))`,
+	}, "\n")
+}
+
+// escapeXML escapes text for an XML element.
+func escapeXML(text string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+// quotePowerShell returns s as a single-quoted PowerShell string, in which only quotes
+// are special. Typographic quotes count as quotes in PowerShell, so they are doubled too.
+func quotePowerShell(s string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\'', '‘', '’', '‚', '‛':
+			b.WriteRune(r)
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
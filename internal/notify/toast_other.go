@@ -0,0 +1,25 @@
+//go:build !windows
+
+package notify
+
+import "errors"
+
+// Toast shows a desktop notification when a job finishes. It is only available on Windows.
+type Toast struct{}
+
+// NewToast returns an error: toasts are only supported on Windows.
+func NewToast() (*Toast, error) {
+	return nil, errors.New("toast notifications are only supported on Windows")
+}
+
+// OnStart implements Notifier.
+func (t *Toast) OnStart(Start) {}
+
+// OnProgress implements Notifier.
+func (t *Toast) OnProgress(Progress) {}
+
+// OnError implements Notifier.
+func (t *Toast) OnError(Failure) {}
+
+// OnComplete implements Notifier.
+func (t *Toast) OnComplete(Summary) {}
@@ -0,0 +1,44 @@
+//go:build windows
+
+package notify
+
+import (
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"syscall"
+)
+
+// Toast shows a Windows toast notification when a job finishes.
+type Toast struct {
+	powershell string
+}
+
+// NewToast returns a Toast, or an error when PowerShell is not available.
+func NewToast() (*Toast, error) {
+	path, err := exec.LookPath("powershell")
+	if err != nil {
+		return nil, fmt.Errorf("powershell not available: %w", err)
+	}
+	return &Toast{powershell: path}, nil
+}
+
+// OnStart implements Notifier.
+func (t *Toast) OnStart(Start) {}
+
+// OnProgress implements Notifier.
+func (t *Toast) OnProgress(Progress) {}
+
+// OnError implements Notifier.
+func (t *Toast) OnError(Failure) {}
+
+// OnComplete implements Notifier.
+func (t *Toast) OnComplete(s Summary) {
+	// A fixed binary; the message is quoted by toastScript
+	script := toastScript(toastTitle, s.Text())
+	cmd := exec.Command(t.powershell, "-NoProfile", "-NonInteractive", "-Command", script) //nolint:gosec,noctx
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		slog.Warn("toast notification failed", "error", err, "output", string(out))
+	}
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultWebhookTimeout bounds one webhook request.
+const DefaultWebhookTimeout = 10 * time.Second
+
+// Webhook POSTs a JSON message to URL when a job finishes. Progress and per-file errors
+// are not posted: failures are listed in the summary, so a batch posts exactly once.
+// The "text" field is what Slack and Microsoft Teams incoming webhooks display; the other
+// fields are for automation.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// WebhookMessage is the body posted by Webhook.
+type WebhookMessage struct {
+	Text    string  `json:"text"`
+	Event   string  `json:"event"`
+	Summary Summary `json:"summary"`
+}
+
+// NewWebhook returns a Webhook posting to url with DefaultWebhookTimeout.
+func NewWebhook(url string) *Webhook {
+	return &Webhook{URL: url, Client: &http.Client{Timeout: DefaultWebhookTimeout}}
+}
+
+// OnStart implements Notifier.
+func (w *Webhook) OnStart(Start) {}
+
+// OnProgress implements Notifier.
+func (w *Webhook) OnProgress(Progress) {}
+
+// OnError implements Notifier.
+func (w *Webhook) OnError(Failure) {}
+
+// OnComplete implements Notifier.
+func (w *Webhook) OnComplete(s Summary) {
+	msg := WebhookMessage{Text: "VniConverter: " + s.Text(), Event: "complete", Summary: s}
+	if err := w.post(context.Background(), msg); err != nil {
+		slog.Warn("webhook notification failed", "error", err)
+	}
+}
+
+// post sends msg and fails on any non-2xx status.
+func (w *Webhook) post(ctx context.Context, msg WebhookMessage) error {
+	return PostJSON(ctx, w.Client, w.URL, msg)
+}
+
+// PostJSON POSTs v as JSON to rawURL with client (nil uses http.DefaultClient) and fails
+// on any non-2xx status. Errors leave out the URL: webhook URLs are often secrets.
+func PostJSON(ctx context.Context, client *http.Client, rawURL string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("webhook POST failed: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook POST failed: %s", resp.Status)
+	}
+	return nil
+}
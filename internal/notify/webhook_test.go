@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhook_PostsSummary(t *testing.T) {
+	var posts []WebhookMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		var msg WebhookMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		posts = append(posts, msg)
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(srv.URL)
+	w.OnStart(Start{JobID: "job-1", Files: []string{"a.xlsx", "b.xlsx"}})
+	w.OnProgress(Progress{JobID: "job-1", Percent: 50})
+	w.OnError(Failure{JobID: "job-1", File: "b.xlsx", Error: "locked"})
+	w.OnComplete(Summary{JobID: "job-1", Files: 2, Failures: []Failure{{JobID: "job-1", File: "b.xlsx", Error: "locked"}}})
+
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want one for the summary", len(posts))
+	}
+	msg := posts[0]
+	if msg.Event != "complete" || msg.Summary.Files != 2 || len(msg.Summary.Failures) != 1 {
+		t.Errorf("message = %+v", msg)
+	}
+	if !strings.HasPrefix(msg.Text, "VniConverter: 1 of 2 file(s) converted") || !strings.Contains(msg.Text, "b.xlsx: locked") {
+		t.Errorf("text = %q", msg.Text)
+	}
+}
+
+func TestWebhook_ErrorsHideURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	for _, url := range []string{srv.URL + "/secret-token", "http://127.0.0.1:1/secret-token"} {
+		err := NewWebhook(url).post(t.Context(), WebhookMessage{Text: "x"})
+		if err == nil {
+			t.Errorf("post(%s) succeeded, want an error", url)
+			continue
+		}
+		if strings.Contains(err.Error(), "secret-token") {
+			t.Errorf("error %q reveals the webhook URL", err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Package pdf exports converted files to PDF with a headless LibreOffice, and imports
+// old spreadsheet formats to .xlsx with it.
+// Why: The usual next step after converting is printing or archiving as PDF, and a pure Go
+// renderer would not lay out pages (print areas, scaling, page breaks) the way office
+// suites do.
+package pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrOfficeNotFound is returned by NewExporter when LibreOffice is not installed.
+var ErrOfficeNotFound = errors.New("LibreOffice not found; install it or set officePath in settings.json")
+
+// officeCommands are the LibreOffice launchers looked up in PATH.
+var officeCommands = []string{"soffice", "libreoffice"}
+
+// officeLocations are the default install locations checked when PATH has no launcher
+// (the Windows installer does not add LibreOffice to PATH).
+var officeLocations = []string{
+	`C:\Program Files\LibreOffice\program\soffice.exe`,
+	`C:\Program Files (x86)\LibreOffice\program\soffice.exe`,
+	"/Applications/LibreOffice.app/Contents/MacOS/soffice",
+	"/usr/lib/libreoffice/program/soffice",
+}
+
+// Exporter converts files to PDF with one LibreOffice installation.
+type Exporter struct {
+	office string
+}
+
+// NewExporter returns an Exporter running the LibreOffice at officePath, or the one found
+// in PATH or a default install location when officePath is empty.
+func NewExporter(officePath string) (*Exporter, error) {
+	if officePath != "" {
+		if _, err := os.Stat(officePath); err != nil {
+			return nil, fmt.Errorf("LibreOffice not found at %s: %w", officePath, err)
+		}
+		return &Exporter{office: officePath}, nil
+	}
+	for _, name := range officeCommands {
+		if path, err := exec.LookPath(name); err == nil {
+			return &Exporter{office: path}, nil
+		}
+	}
+	for _, path := range officeLocations {
+		if _, err := os.Stat(path); err == nil {
+			return &Exporter{office: path}, nil
+		}
+	}
+	return nil, ErrOfficeNotFound
+}
+
+// Path returns the PDF name Export saves input as: input with a .pdf extension.
+func Path(input string) string {
+	return strings.TrimSuffix(input, filepath.Ext(input)) + ".pdf"
+}
+
+// Export saves every sheet (or page) of input as a PDF next to it and returns the PDF
+// path (see Path). An existing PDF of that name is replaced.
+func (e *Exporter) Export(ctx context.Context, input string) (string, error) {
+	work, err := os.MkdirTemp("", "vniconverter-pdf-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create pdf work folder: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(work) }()
+
+	exported, err := e.convert(ctx, input, "pdf", work)
+	if err != nil {
+		return "", err
+	}
+	target := Path(input)
+	if err := moveFile(exported, target); err != nil {
+		return "", fmt.Errorf("failed to save pdf: %w", err)
+	}
+	return target, nil
+}
+
+// ImportXLSX saves input, a spreadsheet LibreOffice can open (e.g. Excel 5.0/95 or
+// Lotus 1-2-3), as <name>.xlsx in dir and returns its path.
+// Why: The converter only reads .xlsx, and LibreOffice still imports the formats Excel
+// dropped long ago.
+func (e *Exporter) ImportXLSX(ctx context.Context, input, dir string) (string, error) {
+	return e.convert(ctx, input, "xlsx", dir)
+}
+
+// convert saves input in format (a LibreOffice --convert-to target) in dir and returns
+// the written file.
+// Why: Each conversion runs with its own LibreOffice profile, because a second instance
+// sharing the user's profile (an open LibreOffice window, or a parallel batch) exits at
+// once without converting anything.
+func (e *Exporter) convert(ctx context.Context, input, format, dir string) (string, error) {
+	work, err := os.MkdirTemp("", "vniconverter-office-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create LibreOffice profile folder: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(work) }()
+
+	profile := "file://" + filepath.ToSlash(filepath.Join(work, "profile"))
+	if !strings.HasPrefix(profile, "file:///") {
+		// Windows paths start with a drive letter
+		profile = "file:///" + strings.TrimPrefix(profile, "file://")
+	}
+	cmd := exec.CommandContext(ctx, e.office, //nolint:gosec // the office binary comes from the user's settings or PATH
+		"-env:UserInstallation="+profile, "--headless", "--norestore",
+		"--convert-to", format, "--outdir", dir, input)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", fmt.Errorf("%s conversion cancelled: %w", format, ctxErr)
+		}
+		return "", fmt.Errorf("LibreOffice failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	name := filepath.Base(input)
+	converted := filepath.Join(dir, strings.TrimSuffix(name, filepath.Ext(name))+"."+format)
+	if _, err := os.Stat(converted); err != nil {
+		return "", fmt.Errorf("LibreOffice did not write a %s of %s", format, name)
+	}
+	return converted, nil
+}
+
+// moveFile moves src to dst, copying when they are on different volumes (the temporary
+// folder and a network share).
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(src) //nolint:gosec // our own work folder
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0666) //nolint:gosec // the output is meant to be shared
+}
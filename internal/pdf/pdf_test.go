@@ -0,0 +1,104 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeOffice writes a script that acts like soffice --convert-to: it writes the input
+// name into <outdir>/<base>.<format>, or fails when the input is named "broken.xlsx".
+func fakeOffice(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake LibreOffice is a shell script")
+	}
+	script := `#!/bin/sh
+while [ $# -gt 1 ]; do
+  case "$1" in --outdir) outdir="$2"; shift ;; --convert-to) format="$2"; shift ;; esac
+  shift
+done
+case "$1" in *broken.xlsx) echo "Error: source file could not be loaded" >&2; exit 1 ;; esac
+base=$(basename "$1"); echo "$1" > "$outdir/${base%.*}.$format"
+`
+	path := filepath.Join(t.TempDir(), "soffice")
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil { //nolint:gosec // test script
+		t.Fatalf("failed to write fake office: %v", err)
+	}
+	return path
+}
+
+func TestExport(t *testing.T) {
+	office := fakeOffice(t)
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "Workbook", input: "report_output_2024.xlsx"},
+		{name: "Document", input: "letter.docx"},
+		{name: "Failure", input: "broken.xlsx", wantErr: "could not be loaded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			input := filepath.Join(dir, tt.input)
+			e, err := NewExporter(office)
+			if err != nil {
+				t.Fatalf("NewExporter failed: %v", err)
+			}
+			got, err := e.Export(context.Background(), input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Export() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+			if got != Path(input) || filepath.Dir(got) != dir {
+				t.Errorf("Export() = %s, want %s", got, Path(input))
+			}
+			if data, err := os.ReadFile(got); err != nil || strings.TrimSpace(string(data)) != input {
+				t.Errorf("pdf = %q (%v), want the export of %s", data, err, input)
+			}
+		})
+	}
+}
+
+func TestImportXLSX(t *testing.T) {
+	e, err := NewExporter(fakeOffice(t))
+	if err != nil {
+		t.Fatalf("NewExporter failed: %v", err)
+	}
+	input := filepath.Join(t.TempDir(), "budget95.xls")
+	dir := t.TempDir()
+	got, err := e.ImportXLSX(context.Background(), input, dir)
+	if err != nil {
+		t.Fatalf("ImportXLSX failed: %v", err)
+	}
+	if want := filepath.Join(dir, "budget95.xlsx"); got != want {
+		t.Errorf("ImportXLSX() = %s, want %s", got, want)
+	}
+	if data, err := os.ReadFile(got); err != nil || strings.TrimSpace(string(data)) != input {
+		t.Errorf("xlsx = %q (%v), want the import of %s", data, err, input)
+	}
+}
+
+func TestNewExporter_Missing(t *testing.T) {
+	if _, err := NewExporter(filepath.Join(t.TempDir(), "soffice")); err == nil {
+		t.Error("NewExporter() succeeded for a missing office binary")
+	}
+	t.Setenv("PATH", t.TempDir())
+	saved := officeLocations
+	officeLocations = nil
+	defer func() { officeLocations = saved }()
+	if _, err := NewExporter(""); !errors.Is(err, ErrOfficeNotFound) {
+		t.Errorf("NewExporter() error = %v, want ErrOfficeNotFound", err)
+	}
+}
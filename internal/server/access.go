@@ -0,0 +1,124 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"convert-vni-to-unicode/internal/storage"
+)
+
+// checkRequest returns why a job may not run: its input or output folder is outside the
+// job roots, or its callback goes to a host that is not allowed.
+// Why: Without these limits a job could read any file the server can, write anywhere, and
+// make the server call internal addresses (SSRF).
+func (r *jobRunner) checkRequest(req JobRequest) error {
+	if !withinRoots(req.Input, r.roots) {
+		return fmt.Errorf("input %q is outside the job roots", req.Input)
+	}
+	if req.OutDir != "" && !withinRoots(req.OutDir, r.roots) {
+		return fmt.Errorf("outDir %q is outside the job roots", req.OutDir)
+	}
+	if req.CallbackURL != "" {
+		if err := checkCallback(req.CallbackURL, r.callbackHosts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withinRoots reports whether location is inside one of roots. Local paths are compared
+// after resolving symbolic links; URLs must share a root's scheme and host and lie under
+// its path.
+func withinRoots(location string, roots []string) bool {
+	for _, root := range roots {
+		var ok bool
+		switch {
+		case storage.IsURI(location) && storage.IsURI(root):
+			ok = withinURL(location, root)
+		case !storage.IsURI(location) && !storage.IsURI(root):
+			ok = withinDir(location, root)
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// withinURL reports whether location lies under the URL prefix root.
+func withinURL(location, root string) bool {
+	u, err := url.Parse(location)
+	if err != nil || u.User != nil {
+		return false
+	}
+	r, err := url.Parse(root)
+	if err != nil {
+		return false
+	}
+	if !strings.EqualFold(u.Scheme, r.Scheme) || !strings.EqualFold(u.Host, r.Host) {
+		return false
+	}
+	return withinPath(path.Clean("/"+u.Path), path.Clean("/"+r.Path), "/")
+}
+
+// withinDir reports whether the local path location is root or inside it.
+func withinDir(location, root string) bool {
+	loc, err := resolvePath(location)
+	if err != nil {
+		return false
+	}
+	dir, err := resolvePath(root)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		loc, dir = strings.ToLower(loc), strings.ToLower(dir)
+	}
+	return withinPath(loc, dir, string(filepath.Separator))
+}
+
+// withinPath reports whether the clean path p is dir or inside it.
+func withinPath(p, dir, sep string) bool {
+	return p == dir || strings.HasPrefix(p, strings.TrimSuffix(dir, sep)+sep)
+}
+
+// resolvePath returns the absolute form of p with symbolic links resolved. A missing
+// tail (an output folder not created yet) is kept as written below its existing parent.
+func resolvePath(p string) (string, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err == nil {
+		return resolved, nil
+	}
+	parent := filepath.Dir(abs)
+	if parent == abs {
+		return "", err
+	}
+	resolvedParent, err := resolvePath(parent)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(resolvedParent, filepath.Base(abs)), nil
+}
+
+// checkCallback returns an error unless callbackURL is an http(s) URL to an allowed host.
+func checkCallback(callbackURL string, hosts []string) error {
+	u, err := url.Parse(callbackURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("callbackUrl must be an http or https URL")
+	}
+	allowed := func(h string) bool { return strings.EqualFold(h, u.Host) || strings.EqualFold(h, u.Hostname()) }
+	if !slices.ContainsFunc(hosts, allowed) {
+		return fmt.Errorf("callbackUrl host %q is not an allowed callback host", u.Host)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+
+	"convert-vni-to-unicode/internal/engine"
+	"convert-vni-to-unicode/internal/notify"
+)
+
+// Defaults for the jobs API.
+const (
+	DefaultJobWorkers = 1
+	DefaultMaxQueued  = 100
+	// maxFinishedJobs is how many finished jobs GET /v1/jobs/{id} still knows about
+	maxFinishedJobs = 1000
+	// callbackBuffer is how many callbacks may wait for delivery; further ones are dropped
+	callbackBuffer = 1000
+)
+
+// Job states, in the order a job goes through them.
+const (
+	JobQueued  = "queued"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// JobConvertFunc converts the workbook at input into outDir (empty: next to the input) and
+// returns the output location and what the conversion changed or left alone.
+type JobConvertFunc func(ctx context.Context, input, outDir string) (string, engine.WorkbookResult, error)
+
+// JobRequest is the body of POST /v1/jobs.
+type JobRequest struct {
+	// Input is a path or URL the server can read (see storage.Resolve)
+	Input string `json:"input"`
+	// OutDir is where the output is saved; empty saves it next to the input
+	OutDir string `json:"outDir,omitempty"`
+	// CallbackURL receives a POST of the Job on every state change
+	CallbackURL string `json:"callbackUrl,omitempty"`
+}
+
+// Job is the status of a workbook conversion, returned by the jobs API and posted to
+// the job's callback URL on every state change.
+type Job struct {
+	ID     string `json:"id"`
+	State  string `json:"state"`
+	Input  string `json:"input"`
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Report is set once the job is done
+	Report  *engine.WorkbookResult `json:"report,omitempty"`
+	Created time.Time              `json:"created"`
+	Updated time.Time              `json:"updated"`
+}
+
+// redirectPolicy is the type of http.Client.CheckRedirect.
+type redirectPolicy func(req *http.Request, via []*http.Request) error
+
+// callback is one Job snapshot waiting to be posted.
+type callback struct {
+	url string
+	job Job
+}
+
+// jobRunner queues workbook jobs, converts them in the background and posts callbacks.
+// Why: A workbook takes far longer than an HTTP request should stay open, and workflow
+// systems would rather be called back than poll.
+type jobRunner struct {
+	convert       JobConvertFunc
+	client        *http.Client
+	token         string
+	roots         []string
+	callbackHosts []string
+
+	mu       sync.Mutex
+	nextID   int
+	jobs     map[string]*Job
+	requests map[string]JobRequest
+	finished []string // IDs of finished jobs, oldest first
+
+	queue     chan string
+	callbacks chan callback
+}
+
+// newJobRunner starts the workers and the callback sender; they stop when ctx is done.
+func newJobRunner(ctx context.Context, opts Options) *jobRunner {
+	r := &jobRunner{
+		convert:       opts.Convert,
+		client:        opts.CallbackClient,
+		token:         opts.JobToken,
+		roots:         opts.JobRoots,
+		callbackHosts: opts.CallbackHosts,
+		jobs:          make(map[string]*Job),
+		requests:      make(map[string]JobRequest),
+		queue:         make(chan string, opts.MaxQueuedJobs),
+		callbacks:     make(chan callback, callbackBuffer),
+	}
+	if r.client == nil {
+		r.client = &http.Client{Timeout: notify.DefaultWebhookTimeout}
+	}
+	// A copy, so the caller's client keeps its own redirect policy
+	client := *r.client
+	client.CheckRedirect = r.checkCallbackRedirect(client.CheckRedirect)
+	r.client = &client
+	for i := 0; i < opts.JobWorkers; i++ {
+		go r.work(ctx)
+	}
+	go r.sendCallbacks(ctx)
+	return r
+}
+
+// submit queues a job, or returns false when the queue is full.
+func (r *jobRunner) submit(req JobRequest) (Job, bool) {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Jobs are only queued here, under r.mu, so the free slot found now is still free below
+	if len(r.queue) >= cap(r.queue) {
+		return Job{}, false
+	}
+	r.nextID++
+	job := &Job{ID: fmt.Sprintf("job-%d", r.nextID), State: JobQueued, Input: req.Input, Created: now, Updated: now}
+	r.jobs[job.ID] = job
+	r.requests[job.ID] = req
+	snapshot := *job
+
+	// Queued only once its "queued" callback is on its way, so callbacks stay in order
+	r.notify(req.CallbackURL, snapshot)
+	r.queue <- job.ID
+	return snapshot, true
+}
+
+// get returns the current status of a job.
+func (r *jobRunner) get(id string) (Job, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// work converts queued jobs until ctx is done.
+func (r *jobRunner) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-r.queue:
+			r.run(ctx, id)
+		}
+	}
+}
+
+// run converts one job, reporting each state change.
+func (r *jobRunner) run(ctx context.Context, id string) {
+	r.mu.Lock()
+	req := r.requests[id]
+	r.mu.Unlock()
+
+	r.update(id, req.CallbackURL, func(j *Job) { j.State = JobRunning })
+	output, result, err := r.convert(ctx, req.Input, req.OutDir)
+	r.update(id, req.CallbackURL, func(j *Job) {
+		if err != nil {
+			j.State, j.Error = JobFailed, err.Error()
+			return
+		}
+		j.State, j.Output, j.Report = JobDone, output, &result
+	})
+}
+
+// update applies fn to a job, forgets the oldest finished jobs and posts the new status.
+func (r *jobRunner) update(id, callbackURL string, fn func(*Job)) {
+	r.mu.Lock()
+	job := r.jobs[id]
+	fn(job)
+	job.Updated = time.Now()
+	if job.State == JobDone || job.State == JobFailed {
+		delete(r.requests, id)
+		r.finished = append(r.finished, id)
+		if len(r.finished) > maxFinishedJobs {
+			delete(r.jobs, r.finished[0])
+			r.finished = r.finished[1:]
+		}
+	}
+	snapshot := *job
+	r.mu.Unlock()
+
+	r.notify(callbackURL, snapshot)
+}
+
+// notify queues a callback of job, if the job has a callback URL. It never blocks: when
+// callbackBuffer callbacks are already waiting, this one is dropped and logged.
+// Why: A slow or unreachable callback receiver must not stall submissions or workers.
+func (r *jobRunner) notify(callbackURL string, job Job) {
+	if callbackURL == "" {
+		return
+	}
+	select {
+	case r.callbacks <- callback{url: callbackURL, job: job}:
+	default:
+		slog.Warn("job callback dropped, too many callbacks pending", "job", job.ID, "state", job.State)
+	}
+}
+
+// checkCallbackRedirect returns a redirect policy that follows a callback's redirects only
+// to allowed callback hosts, then applies next (the default policy when nil).
+// Why: An allowed receiver redirecting elsewhere would otherwise carry job details to any
+// host, and reach internal services the server can see.
+func (r *jobRunner) checkCallbackRedirect(next redirectPolicy) redirectPolicy {
+	return func(req *http.Request, via []*http.Request) error {
+		if err := checkCallback(req.URL.String(), r.callbackHosts); err != nil {
+			return fmt.Errorf("callback redirect refused: %w", err)
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+}
+
+// sendCallbacks posts queued callbacks in order until ctx is done.
+// Delivery is best effort: a failed callback is logged, not retried.
+func (r *jobRunner) sendCallbacks(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case cb := <-r.callbacks:
+			if err := notify.PostJSON(ctx, r.client, cb.url, cb.job); err != nil {
+				slog.Warn("job callback failed", "job", cb.job.ID, "state", cb.job.State, "error", err)
+			}
+		}
+	}
+}
+
+// authorized wraps a jobs handler so it only runs for requests carrying the job token.
+// Why: Jobs read and write files with the server's rights; without a token any local
+// process, or any web page through the user's browser, could use them.
+func (r *jobRunner) authorized(handler http.HandlerFunc) http.HandlerFunc {
+	want := []byte("Bearer " + r.token)
+	return func(w http.ResponseWriter, req *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="jobs"`)
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "missing or invalid job token"})
+			return
+		}
+		handler(w, req)
+	}
+}
+
+// handleSubmit implements POST /v1/jobs.
+func (r *jobRunner) handleSubmit(w http.ResponseWriter, req *http.Request, maxBody int64) {
+	// A browser sends form-encoded or text/plain bodies cross-site without asking first
+	mediaType, _, mediaErr := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaErr != nil || mediaType != "application/json" {
+		writeJSON(w, http.StatusUnsupportedMediaType, errorResponse{Error: "Content-Type must be application/json"})
+		return
+	}
+	var body JobRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxBody)).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+		return
+	}
+	if body.Input == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "input is required"})
+		return
+	}
+	if err := r.checkRequest(body); err != nil {
+		writeJSON(w, http.StatusForbidden, errorResponse{Error: err.Error()})
+		return
+	}
+	job, ok := r.submit(body)
+	if !ok {
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: "too many queued jobs, retry later"})
+		return
+	}
+	w.Header().Set("Location", "/v1/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// handleGet implements GET /v1/jobs/{id}.
+func (r *jobRunner) handleGet(w http.ResponseWriter, req *http.Request) {
+	job, ok := r.get(req.PathValue("id"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "unknown job"})
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
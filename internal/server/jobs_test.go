@@ -0,0 +1,431 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"convert-vni-to-unicode/internal/engine"
+)
+
+// callbackRecorder collects the Jobs posted to its URL.
+type callbackRecorder struct {
+	mu   sync.Mutex
+	jobs []Job
+	done chan struct{}
+}
+
+func newCallbackRecorder(t *testing.T) (*callbackRecorder, string) {
+	rec := &callbackRecorder{done: make(chan struct{}, 10)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var job Job
+		if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+			t.Errorf("invalid callback body: %v", err)
+		}
+		rec.mu.Lock()
+		rec.jobs = append(rec.jobs, job)
+		rec.mu.Unlock()
+		if job.State == JobDone || job.State == JobFailed {
+			rec.done <- struct{}{}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return rec, srv.URL
+}
+
+// wait returns the posted jobs once a job has finished.
+func (rec *callbackRecorder) wait(t *testing.T) []Job {
+	t.Helper()
+	select {
+	case <-rec.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the final callback")
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]Job(nil), rec.jobs...)
+}
+
+// testJobToken is the job token of the test servers.
+const testJobToken = "secret"
+
+// jobOptions returns Options running jobs with convert inside root, posting callbacks to
+// callbackURL's host.
+func jobOptions(t *testing.T, convert JobConvertFunc, root, callbackURL string) Options {
+	opts := Options{Convert: convert, Context: t.Context(), JobToken: testJobToken, JobRoots: []string{root}}
+	if callbackURL != "" {
+		u, err := url.Parse(callbackURL)
+		if err != nil {
+			t.Fatalf("invalid callback URL: %v", err)
+		}
+		opts.CallbackHosts = []string{u.Host}
+	}
+	return opts
+}
+
+func submitJob(t *testing.T, url, body string) (*http.Response, Job) {
+	t.Helper()
+	return sendJob(t, url, "application/json", "Bearer "+testJobToken, body)
+}
+
+func sendJob(t *testing.T, url, contentType, authorization, body string) (*http.Response, Job) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url+"/v1/jobs", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var job Job
+	if resp.StatusCode == http.StatusAccepted {
+		if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+			t.Fatalf("failed to decode job: %v", err)
+		}
+	}
+	return resp, job
+}
+
+func TestJobs_Callbacks(t *testing.T) {
+	report := engine.WorkbookResult{RenamedSheets: []engine.RenamedSheet{{From: "Ba\u00F9o ca\u00F9o", To: "Báo cáo"}}}
+	convert := func(_ context.Context, input, outDir string) (string, engine.WorkbookResult, error) {
+		if filepath.Base(input) == "bad.xlsx" {
+			return "", engine.WorkbookResult{}, errors.New("not a workbook")
+		}
+		return outDir + "/out.xlsx", report, nil
+	}
+
+	tests := []struct {
+		name       string
+		input      string
+		wantStates []string
+		wantOutput string
+		wantError  string
+	}{
+		{name: "Done", input: "book.xlsx", wantStates: []string{JobQueued, JobRunning, JobDone}, wantOutput: "/out.xlsx"},
+		{name: "Failed", input: "bad.xlsx", wantStates: []string{JobQueued, JobRunning, JobFailed}, wantError: "not a workbook"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			rec, callbackURL := newCallbackRecorder(t)
+			srv := httptest.NewServer(NewHandler(jobOptions(t, convert, root, callbackURL)))
+			defer srv.Close()
+
+			body, _ := json.Marshal(JobRequest{Input: filepath.Join(root, tt.input), OutDir: filepath.Join(root, "converted"), CallbackURL: callbackURL})
+			resp, job := submitJob(t, srv.URL, string(body))
+			if resp.StatusCode != http.StatusAccepted {
+				t.Fatalf("status = %d, want 202", resp.StatusCode)
+			}
+			if got := resp.Header.Get("Location"); got != "/v1/jobs/"+job.ID {
+				t.Errorf("Location = %q, want the job URL", got)
+			}
+
+			posted := rec.wait(t)
+			var states []string
+			for _, j := range posted {
+				states = append(states, j.State)
+			}
+			if !reflect.DeepEqual(states, tt.wantStates) {
+				t.Errorf("callback states = %v, want %v", states, tt.wantStates)
+			}
+			final := posted[len(posted)-1]
+			wantOutput := ""
+			if tt.wantOutput != "" {
+				wantOutput = filepath.Join(root, "converted") + tt.wantOutput
+			}
+			if final.ID != job.ID || final.Output != wantOutput || final.Error != tt.wantError {
+				t.Errorf("final callback = %+v, want output %q and error %q", final, wantOutput, tt.wantError)
+			}
+			if tt.wantError == "" && (final.Report == nil || !reflect.DeepEqual(*final.Report, report)) {
+				t.Errorf("final report = %+v, want %+v", final.Report, report)
+			}
+
+			// The status endpoint returns the same final state
+			getReq, err := http.NewRequest(http.MethodGet, srv.URL+"/v1/jobs/"+job.ID, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			getReq.Header.Set("Authorization", "Bearer "+testJobToken)
+			getResp, err := http.DefaultClient.Do(getReq)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer func() { _ = getResp.Body.Close() }()
+			var got Job
+			if err := json.NewDecoder(getResp.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode job: %v", err)
+			}
+			if got.State != final.State || got.Output != final.Output {
+				t.Errorf("GET job = %+v, want %+v", got, final)
+			}
+		})
+	}
+}
+
+func TestJobs_Rejected(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	convert := func(_ context.Context, _, _ string) (string, engine.WorkbookResult, error) {
+		<-release
+		return "", engine.WorkbookResult{}, nil
+	}
+	root := t.TempDir()
+	opts := jobOptions(t, convert, root, "")
+	opts.MaxQueuedJobs, opts.CallbackHosts = 1, []string{"hooks.example.com"}
+	srv := httptest.NewServer(NewHandler(opts))
+	defer srv.Close()
+	book, _ := json.Marshal(filepath.Join(root, "book.xlsx"))
+	outside, _ := json.Marshal(filepath.Join(t.TempDir(), "book.xlsx"))
+	escaped, _ := json.Marshal(filepath.Join(root, "..", "book.xlsx"))
+
+	// The first job is running, the second waits; the queue is then full
+	for i := 0; i < 2; i++ {
+		if resp, _ := submitJob(t, srv.URL, `{"input": `+string(book)+`}`); resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("job %d: status = %d, want 202", i, resp.StatusCode)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	tests := []struct {
+		name          string
+		contentType   string
+		authorization string
+		body          string
+		want          int
+	}{
+		{name: "Queue full", body: `{"input": ` + string(book) + `}`, want: http.StatusServiceUnavailable},
+		{name: "Missing input", body: `{}`, want: http.StatusBadRequest},
+		{name: "Invalid JSON", body: `{`, want: http.StatusBadRequest},
+		{name: "No token", authorization: "-", body: `{"input": ` + string(book) + `}`, want: http.StatusUnauthorized},
+		{name: "Wrong token", authorization: "Bearer guess", body: `{"input": ` + string(book) + `}`, want: http.StatusUnauthorized},
+		{name: "Form post", contentType: "text/plain", body: `{"input": ` + string(book) + `}`, want: http.StatusUnsupportedMediaType},
+		{name: "Input outside the roots", body: `{"input": ` + string(outside) + `}`, want: http.StatusForbidden},
+		{name: "Input escaping the root", body: `{"input": ` + string(escaped) + `}`, want: http.StatusForbidden},
+		{name: "URL input", body: `{"input": "http://169.254.169.254/book.xlsx"}`, want: http.StatusForbidden},
+		{name: "Output outside the roots", body: `{"input": ` + string(book) + `, "outDir": ` + string(outside) + `}`, want: http.StatusForbidden},
+		{name: "Callback host not allowed", body: `{"input": ` + string(book) + `, "callbackUrl": "http://127.0.0.1:22/"}`, want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, authorization := "application/json", "Bearer "+testJobToken
+			if tt.contentType != "" {
+				contentType = tt.contentType
+			}
+			if tt.authorization == "-" {
+				authorization = ""
+			} else if tt.authorization != "" {
+				authorization = tt.authorization
+			}
+			if resp, _ := sendJob(t, srv.URL, contentType, authorization, tt.body); resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/v1/jobs/job-99", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testJobToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown job status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestJobs_Disabled(t *testing.T) {
+	convert := func(context.Context, string, string) (string, engine.WorkbookResult, error) {
+		return "", engine.WorkbookResult{}, nil
+	}
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{name: "No converter", opts: Options{JobToken: testJobToken}},
+		{name: "No token", opts: Options{Convert: convert, Context: t.Context()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(NewHandler(tt.opts))
+			defer srv.Close()
+			if resp, _ := submitJob(t, srv.URL, `{"input": "book.xlsx"}`); resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusMethodNotAllowed {
+				t.Errorf("status = %d, want the jobs API disabled", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestWithinRoots(t *testing.T) {
+	root := t.TempDir()
+	roots := []string{root, "https://files.example.com/vni/"}
+	tests := []struct {
+		name     string
+		location string
+		want     bool
+	}{
+		{name: "File in a root", location: filepath.Join(root, "book.xlsx"), want: true},
+		{name: "Folder not created yet", location: filepath.Join(root, "out", "2024"), want: true},
+		{name: "The root itself", location: root, want: true},
+		{name: "Sibling with the root as prefix", location: root + "-other", want: false},
+		{name: "Parent traversal", location: filepath.Join(root, "..", "x.xlsx"), want: false},
+		{name: "URL under the prefix", location: "https://files.example.com/vni/book.xlsx", want: true},
+		{name: "URL escaping the prefix", location: "https://files.example.com/vni/../secret.xlsx", want: false},
+		{name: "URL on another host", location: "https://files.example.com.evil.test/vni/book.xlsx", want: false},
+		{name: "URL with credentials", location: "https://user@files.example.com/vni/book.xlsx", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withinRoots(tt.location, roots); got != tt.want {
+				t.Errorf("withinRoots(%q) = %t, want %t", tt.location, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobRunner_SubmitNeverBlocks(t *testing.T) {
+	// No worker takes jobs and no sender posts callbacks, so both channels fill up
+	r := &jobRunner{
+		jobs:      make(map[string]*Job),
+		requests:  make(map[string]JobRequest),
+		queue:     make(chan string, 2),
+		callbacks: make(chan callback, 1),
+	}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "Queued, callback buffered", want: true},
+		{name: "Queued, callback dropped", want: true},
+		{name: "Queue full", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done := make(chan bool)
+			go func() {
+				_, ok := r.submit(JobRequest{Input: "book.xlsx", CallbackURL: "http://hooks.example.com/"})
+				done <- ok
+			}()
+			select {
+			case ok := <-done:
+				if ok != tt.want {
+					t.Errorf("submit() = %v, want %v", ok, tt.want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("submit blocked")
+			}
+		})
+	}
+	if len(r.callbacks) != 1 || len(r.queue) != 2 {
+		t.Errorf("%d callbacks and %d jobs waiting, want 1 and 2", len(r.callbacks), len(r.queue))
+	}
+}
+
+func TestJobRunner_CheckCallbackRedirect(t *testing.T) {
+	r := &jobRunner{callbackHosts: []string{"hooks.example.com", "127.0.0.1:8080"}}
+	errNext := errors.New("refused by the client's own policy")
+	tests := []struct {
+		name    string
+		target  string
+		via     int
+		next    redirectPolicy
+		wantErr error // nil: any error when wantRefused
+		// wantRefused is whether the redirect is not followed
+		wantRefused bool
+	}{
+		{name: "Allowed host", target: "https://hooks.example.com/moved"},
+		{name: "Allowed host and port", target: "http://127.0.0.1:8080/moved"},
+		{name: "Other port", target: "http://127.0.0.1:9090/moved", wantRefused: true},
+		{name: "Other host", target: "http://169.254.169.254/latest/meta-data", wantRefused: true},
+		{name: "Other scheme", target: "ftp://hooks.example.com/moved", wantRefused: true},
+		{name: "Too many redirects", target: "https://hooks.example.com/moved", via: 10, wantRefused: true},
+		{
+			name:   "Client policy kept",
+			target: "https://hooks.example.com/moved",
+			next:   func(*http.Request, []*http.Request) error { return errNext },
+			// The client's own policy replaces the default limit
+			wantErr: errNext, wantRefused: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, tt.target, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			err = r.checkCallbackRedirect(tt.next)(req, make([]*http.Request, tt.via))
+			if (err != nil) != tt.wantRefused {
+				t.Fatalf("redirect error = %v, want refused = %v", err, tt.wantRefused)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("redirect error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJobs_CallbackRedirectRefused(t *testing.T) {
+	// The allowed receiver redirects every callback to a host that is not allowed
+	var leaked sync.Mutex
+	leakedPosts := 0
+	other := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		leaked.Lock()
+		leakedPosts++
+		leaked.Unlock()
+	}))
+	defer other.Close()
+	redirected := make(chan string, 10)
+	allowed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var job Job
+		_ = json.NewDecoder(r.Body).Decode(&job)
+		redirected <- job.State
+		http.Redirect(w, r, other.URL+"/steal", http.StatusTemporaryRedirect)
+	}))
+	defer allowed.Close()
+
+	convert := func(context.Context, string, string) (string, engine.WorkbookResult, error) {
+		return "out.xlsx", engine.WorkbookResult{}, nil
+	}
+	root := t.TempDir()
+	srv := httptest.NewServer(NewHandler(jobOptions(t, convert, root, allowed.URL)))
+	defer srv.Close()
+	body, _ := json.Marshal(JobRequest{Input: filepath.Join(root, "book.xlsx"), CallbackURL: allowed.URL})
+	if resp, _ := submitJob(t, srv.URL, string(body)); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202", resp.StatusCode)
+	}
+
+	// Callbacks are sent one at a time, so once the final one reached the allowed host,
+	// the earlier redirects have been refused
+	for state := ""; state != JobDone; {
+		select {
+		case state = <-redirected:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the final callback")
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	leaked.Lock()
+	defer leaked.Unlock()
+	if leakedPosts != 0 {
+		t.Errorf("%d callbacks followed the redirect to a host that is not allowed", leakedPosts)
+	}
+}
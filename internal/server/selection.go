@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"convert-vni-to-unicode/internal/converter"
+	"convert-vni-to-unicode/internal/engine"
+)
+
+// DefaultMaxRangeCells is the default of Options.MaxRangeCells.
+const DefaultMaxRangeCells = 100000
+
+// RangeRequest is the body of POST /v1/convert-range: the values of a selected range, row
+// by row, as Office.js returns them from Range.values.
+type RangeRequest struct {
+	// Values holds the cell values; strings are converted, numbers, booleans and nulls are
+	// returned as they are
+	Values [][]any `json:"values"`
+	// Fonts optionally holds the font family of each cell, in the shape of Values; it lets
+	// the font decide the encoding the way a workbook run does
+	Fonts [][]string `json:"fonts,omitempty"`
+	// Encoding forces the source encoding; empty or "AUTO" detects it cell by cell
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// RangeResponse is the response of POST /v1/convert-range.
+type RangeResponse struct {
+	// Values are the converted values, in the shape of the request
+	Values [][]any `json:"values"`
+	// Fonts holds the font family to apply to each cell; "" keeps the font of the cell
+	Fonts [][]string `json:"fonts"`
+	// Converted is how many cells changed
+	Converted int `json:"converted"`
+}
+
+// handleConvertRange converts the selected range of an open workbook.
+// Why: An Excel add-in offers "convert selection" without saving the workbook and
+// converting the file; it sends the values and fonts of the selection and writes back the
+// result.
+func handleConvertRange(opts Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req RangeRequest
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, opts.MaxBodyBytes))
+		// Numbers are echoed back as sent, without a round trip through float64
+		dec.UseNumber()
+		if err := dec.Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+		count := 0
+		for _, row := range req.Values {
+			count += len(row)
+		}
+		if count > opts.MaxRangeCells {
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{
+				Error: fmt.Sprintf("range of %d cells exceeds the limit of %d", count, opts.MaxRangeCells),
+			})
+			return
+		}
+
+		cells := make([]engine.SelectedCell, 0, count)
+		for i, row := range req.Values {
+			for j, value := range row {
+				text, _ := value.(string)
+				cells = append(cells, engine.SelectedCell{Text: text, Font: cellFont(req.Fonts, i, j)})
+			}
+		}
+		runOpts := []engine.Option{engine.WithDetector(opts.Detector)}
+		if enc := converter.EncodingType(req.Encoding); enc != "" && enc != converter.EncodingAuto {
+			runOpts = append(runOpts, engine.WithSourceEncoding(enc))
+		}
+		converted, err := engine.ConvertCells(cells, runOpts...)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+			return
+		}
+
+		resp := RangeResponse{Values: make([][]any, len(req.Values)), Fonts: make([][]string, len(req.Values))}
+		k := 0
+		for i, row := range req.Values {
+			resp.Values[i] = slices.Clone(row)
+			resp.Fonts[i] = make([]string, len(row))
+			for j, value := range row {
+				cell := converted[k]
+				k++
+				if text, ok := value.(string); !ok || cell.Text == text {
+					continue
+				}
+				resp.Values[i][j] = cell.Text
+				resp.Fonts[i][j] = cell.Font
+				resp.Converted++
+			}
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// cellFont returns the font of cell (i, j), or "" when fonts does not cover it.
+func cellFont(fonts [][]string, i, j int) string {
+	if i < len(fonts) && j < len(fonts[i]) {
+		return fonts[i][j]
+	}
+	return ""
+}
+
+// withCORS lets pages of the allowed origins call handler from a browser, as Office.js
+// add-ins (web pages hosted inside Office) do; "*" allows any origin. Preflight requests
+// are answered here, since the routes of handler only accept their own methods.
+func withCORS(handler http.Handler, origins []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || (!slices.Contains(origins, origin) && !slices.Contains(origins, "*")) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+		if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		if r.Header.Get("Access-Control-Request-Private-Network") == "true" {
+			// Chromium-based hosts ask before a public page calls a loopback address
+			w.Header().Set("Access-Control-Allow-Private-Network", "true")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
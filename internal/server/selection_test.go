@@ -0,0 +1,121 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConvertRange(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(Options{}))
+	defer srv.Close()
+
+	body := `{
+		"values": [["Vi\u00D6t Nam", 12345678901234567], ["C\u00ABng ty", true], [null, "Hello"]],
+		"fonts": [["VNI-Times", "Arial"], [".VnTime"]]
+	}`
+	resp, err := http.Post(srv.URL+"/v1/convert-range", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var got struct {
+		Values    [][]json.RawMessage `json:"values"`
+		Fonts     [][]string          `json:"fonts"`
+		Converted int                 `json:"converted"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	wantValues := [][]string{{"\"Vi\u1EC7t Nam\"", "12345678901234567"}, {"\"C\u00F4ng ty\"", "true"}, {"null", `"Hello"`}}
+	wantFonts := [][]string{{"Times New Roman", ""}, {"Times New Roman", ""}, {"", ""}}
+	values := make([][]string, len(got.Values))
+	for i, row := range got.Values {
+		for _, v := range row {
+			values[i] = append(values[i], string(v))
+		}
+	}
+	if !reflect.DeepEqual(values, wantValues) {
+		t.Errorf("values = %v, want %v", values, wantValues)
+	}
+	if !reflect.DeepEqual(got.Fonts, wantFonts) {
+		t.Errorf("fonts = %v, want %v", got.Fonts, wantFonts)
+	}
+	if got.Converted != 2 {
+		t.Errorf("converted = %d, want 2", got.Converted)
+	}
+}
+
+func TestConvertRange_Rejected(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(Options{MaxRangeCells: 2}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "Malformed JSON", body: `{"values": [`, status: http.StatusBadRequest},
+		{name: "Range too large", body: `{"values": [["a", "b"], ["c"]]}`, status: http.StatusRequestEntityTooLarge},
+		{name: "Unknown encoding", body: `{"values": [["a"]], "encoding": "EBCDIC"}`, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(srv.URL+"/v1/convert-range", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	const addin = "https://addin.example.com"
+	srv := httptest.NewServer(NewHandler(Options{AllowedOrigins: []string{addin}}))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		origin     string
+		status     int
+		wantOrigin string
+	}{
+		{name: "Preflight", method: http.MethodOptions, origin: addin, status: http.StatusNoContent, wantOrigin: addin},
+		{name: "Allowed origin", method: http.MethodPost, origin: addin, status: http.StatusOK, wantOrigin: addin},
+		{name: "Other origin", method: http.MethodPost, origin: "https://evil.example.com", status: http.StatusOK},
+		{name: "Other origin preflight", method: http.MethodOptions, origin: "https://evil.example.com", status: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, srv.URL+"/v1/convert-range", strings.NewReader(`{"values": [["a"]]}`))
+			if err != nil {
+				t.Fatalf("failed to build request: %v", err)
+			}
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if got := resp.Header.Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Package server exposes the converters and workbook conversion jobs over HTTP for batch
+// integrations.
+// Why: Data-cleaning pipelines (e.g. Spark jobs) convert millions of strings and need a
+// network endpoint rather than a desktop app or one CLI process per file.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"convert-vni-to-unicode/internal/converter"
+	"convert-vni-to-unicode/internal/engine"
+)
+
+// Defaults for Options.
+const (
+	DefaultMaxBatch     = 1000
+	DefaultMaxBodyBytes = 8 << 20
+)
+
+// Options configures the HTTP handler.
+type Options struct {
+	// MaxBatch is the maximum number of strings per request (0 uses DefaultMaxBatch)
+	MaxBatch int
+	// MaxBodyBytes caps the request body size (0 uses DefaultMaxBodyBytes)
+	MaxBodyBytes int64
+	// Detector guesses the encoding of items without a hint (nil uses the rule-based detector)
+	Detector engine.Detector
+	// MaxRangeCells is the maximum number of cells per /v1/convert-range request (0 uses
+	// DefaultMaxRangeCells)
+	MaxRangeCells int
+	// AllowedOrigins are the browser origins (e.g. of an Office.js add-in) allowed to call
+	// the API; "*" allows any. Empty allows none, which does not affect non-browser clients.
+	AllowedOrigins []string
+
+	// Convert runs the workbook jobs of /v1/jobs; nil disables the jobs API
+	Convert JobConvertFunc
+	// JobToken must be sent as "Authorization: Bearer <token>" to use the jobs API; empty
+	// disables the jobs API
+	JobToken string
+	// JobRoots are the local directories and URL prefixes (e.g. "https://files.example.com/vni/")
+	// that job inputs and output folders must be inside; empty rejects every job
+	JobRoots []string
+	// CallbackHosts are the hosts ("host" or "host:port") job callbacks may be posted to;
+	// empty rejects every job with a callback URL
+	CallbackHosts []string
+	// JobWorkers is how many jobs convert at once (0 uses DefaultJobWorkers)
+	JobWorkers int
+	// MaxQueuedJobs is how many jobs may wait for a worker (0 uses DefaultMaxQueued)
+	MaxQueuedJobs int
+	// CallbackClient posts job callbacks (nil uses a client with notify.DefaultWebhookTimeout)
+	CallbackClient *http.Client
+	// Context stops the job workers when done (nil: they run as long as the process)
+	Context context.Context
+}
+
+// TextItem is one string to convert, with an optional encoding hint.
+type TextItem struct {
+	Text string `json:"text"`
+	// Encoding forces the source encoding ("VNI", "TCVN3", "VIQR", ...); empty or "AUTO" detects it
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// TextRequest is the body of POST /v1/convert-text.
+type TextRequest struct {
+	Items []TextItem `json:"items"`
+}
+
+// TextResult is the conversion of one TextItem, in request order.
+type TextResult struct {
+	Text     string                 `json:"text"`
+	Encoding converter.EncodingType `json:"encoding"`
+	Rule     engine.DetectionRule   `json:"rule"`
+	// Confidence is how reliable the encoding choice is, from 0 (unchanged) to 1 (hinted)
+	Confidence float64 `json:"confidence"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// TextResponse is the response of POST /v1/convert-text.
+type TextResponse struct {
+	Results []TextResult `json:"results"`
+}
+
+// errorResponse is returned with every non-200 status.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewHandler returns the HTTP API:
+//
+//	POST /v1/convert-text  converts a batch of strings (TextRequest -> TextResponse)
+//	POST /v1/convert-range converts the selected range of a workbook (RangeRequest -> RangeResponse)
+//	POST /v1/jobs          queues a workbook conversion (JobRequest -> Job), if opts.Convert and opts.JobToken are set
+//	GET  /v1/jobs/{id}     returns the status of a job (Job)
+func NewHandler(opts Options) http.Handler {
+	if opts.MaxBatch <= 0 {
+		opts.MaxBatch = DefaultMaxBatch
+	}
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if opts.Detector == nil {
+		opts.Detector = engine.RuleDetector{}
+	}
+	if opts.MaxRangeCells <= 0 {
+		opts.MaxRangeCells = DefaultMaxRangeCells
+	}
+	// Converters are safe for concurrent use, so one set serves every request
+	converters := make(map[converter.EncodingType]converter.Converter)
+	for _, enc := range []converter.EncodingType{
+		converter.EncodingVNI, converter.EncodingTCVN3, converter.EncodingVNIDOS, converter.EncodingVNU,
+		converter.EncodingVISCII, converter.EncodingVIQR,
+	} {
+		converters[enc] = converter.NewConverterOrNoop(enc)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/convert-text", func(w http.ResponseWriter, r *http.Request) {
+		var req TextRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, opts.MaxBodyBytes)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+		if len(req.Items) > opts.MaxBatch {
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{
+				Error: fmt.Sprintf("batch of %d strings exceeds the limit of %d", len(req.Items), opts.MaxBatch),
+			})
+			return
+		}
+		results := make([]TextResult, len(req.Items))
+		for i, item := range req.Items {
+			results[i] = convertItem(converters, opts.Detector, item)
+		}
+		writeJSON(w, http.StatusOK, TextResponse{Results: results})
+	})
+	mux.HandleFunc("POST /v1/convert-range", handleConvertRange(opts))
+
+	if opts.Convert != nil && opts.JobToken != "" {
+		if opts.JobWorkers <= 0 {
+			opts.JobWorkers = DefaultJobWorkers
+		}
+		if opts.MaxQueuedJobs <= 0 {
+			opts.MaxQueuedJobs = DefaultMaxQueued
+		}
+		if opts.Context == nil {
+			opts.Context = context.Background()
+		}
+		jobs := newJobRunner(opts.Context, opts)
+		mux.HandleFunc("POST /v1/jobs", jobs.authorized(func(w http.ResponseWriter, r *http.Request) {
+			jobs.handleSubmit(w, r, opts.MaxBodyBytes)
+		}))
+		mux.HandleFunc("GET /v1/jobs/{id}", jobs.authorized(jobs.handleGet))
+	}
+	if len(opts.AllowedOrigins) > 0 {
+		return withCORS(mux, opts.AllowedOrigins)
+	}
+	return mux
+}
+
+// convertItem converts one string; a bad hint fails only that item, not the batch.
+func convertItem(converters map[converter.EncodingType]converter.Converter, detector engine.Detector,
+	item TextItem) TextResult {
+	enc := converter.EncodingType(item.Encoding)
+	detection := engine.Detection{Encoding: enc, Rule: engine.RuleForced}
+	if enc == "" || enc == converter.EncodingAuto {
+		detection = detector.Detect("", item.Text)
+	}
+	result := TextResult{
+		Text:       item.Text,
+		Encoding:   detection.Encoding,
+		Rule:       detection.Rule,
+		Confidence: detection.Confidence(),
+	}
+	if detection.Encoding == converter.EncodingUnknown {
+		return result
+	}
+	c, ok := converters[detection.Encoding]
+	if !ok {
+		result.Confidence = 0
+		result.Error = fmt.Sprintf("unsupported encoding type: %s", detection.Encoding)
+		return result
+	}
+	result.Text = c.ToUnicode(item.Text)
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("failed to write response", "error", err)
+	}
+}
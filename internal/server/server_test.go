@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"convert-vni-to-unicode/internal/converter"
+	"convert-vni-to-unicode/internal/engine"
+)
+
+func TestConvertText(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(Options{}))
+	defer srv.Close()
+
+	body := `{"items": [
+		{"text": "Vi\u00D6t Nam"},
+		{"text": "Vie^.t Nam", "encoding": "VIQR"},
+		{"text": "Hello"},
+		{"text": "abc", "encoding": "EBCDIC"}
+	]}`
+	resp, err := http.Post(srv.URL+"/v1/convert-text", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var got TextResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	want := []TextResult{
+		{Text: "Việt Nam", Encoding: converter.EncodingVNI, Rule: engine.RuleVNIRunes, Confidence: 0.7},
+		{Text: "Việt Nam", Encoding: converter.EncodingVIQR, Rule: engine.RuleForced, Confidence: 1},
+		{Text: "Hello", Encoding: converter.EncodingUnknown, Rule: engine.RuleNone, Confidence: 0},
+		{Text: "abc", Encoding: "EBCDIC", Rule: engine.RuleForced, Error: "unsupported encoding type: EBCDIC"},
+	}
+	if len(got.Results) != len(want) {
+		t.Fatalf("results = %+v, want %d results", got.Results, len(want))
+	}
+	for i := range want {
+		if got.Results[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, got.Results[i], want[i])
+		}
+	}
+}
+
+func TestConvertText_Rejected(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(Options{MaxBatch: 2, MaxBodyBytes: 64}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+	}{
+		{name: "Malformed JSON", method: http.MethodPost, body: `{"items": [`, status: http.StatusBadRequest},
+		{name: "Batch too large", method: http.MethodPost, body: `{"items": [{"text":"a"},{"text":"b"},{"text":"c"}]}`, status: http.StatusRequestEntityTooLarge},
+		{name: "Body too large", method: http.MethodPost, body: `{"items": [{"text":"` + strings.Repeat("a", 100) + `"}]}`, status: http.StatusBadRequest},
+		{name: "Wrong method", method: http.MethodGet, status: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, srv.URL+"/v1/convert-text", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("failed to build request: %v", err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
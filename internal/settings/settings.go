@@ -0,0 +1,286 @@
+// Package settings persists user preferences between application launches.
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Theme names understood by the frontend.
+const (
+	ThemeDark  = "dark"
+	ThemeLight = "light"
+)
+
+// Languages for backend-generated text.
+const (
+	LangEnglish    = "en"
+	LangVietnamese = "vi"
+)
+
+// Window defaults and lower bounds, matching the options in main.go.
+const (
+	DefaultWidth  = 900
+	DefaultHeight = 835
+	MinWidth      = 700
+	MinHeight     = 600
+)
+
+// IO throttling defaults.
+const (
+	DefaultMaxOpenFiles     = 2
+	DefaultInterFileDelayMs = 250
+)
+
+// DefaultMaxJobs is how many conversions the GUI runs at once; later ones are queued.
+const DefaultMaxJobs = 2
+
+// DefaultMaxCellLength is the cell length (in characters) above which cells are skipped.
+const DefaultMaxCellLength = 10000
+
+// appDirName is the folder created under the user's config directory.
+const appDirName = "VniConverter"
+
+// WindowState is the last known window geometry.
+type WindowState struct {
+	Width     int  `json:"width"`
+	Height    int  `json:"height"`
+	X         int  `json:"x"`
+	Y         int  `json:"y"`
+	HasPos    bool `json:"hasPosition"`
+	Maximized bool `json:"maximized"`
+}
+
+// JobTemplate is a named set of conversion options that conversion links
+// (vniconv://convert?path=...&profile=<name>) refer to. Empty fields use the defaults.
+type JobTemplate struct {
+	// Encoding forces a source encoding (empty or "AUTO" detects it)
+	Encoding  string `json:"encoding,omitempty"`
+	SheetName string `json:"sheetName,omitempty"`
+	// ChangeReport writes <output>_changes.<format> ("xlsx", "csv" or "json")
+	ChangeReport string `json:"changeReport,omitempty"`
+	// InPlace overwrites the input after keeping the original ("bak" or "folder")
+	InPlace    string `json:"inPlace,omitempty"`
+	SpellCheck bool   `json:"spellCheck,omitempty"`
+	ExportPDF  bool   `json:"exportPDF,omitempty"`
+}
+
+// Settings holds every persisted preference.
+// Why: One JSON document keeps the store trivial to inspect and back up.
+type Settings struct {
+	Theme    string `json:"theme"`
+	Language string `json:"language"`
+	// FontPolicy selects how output fonts are chosen (see engine.NewFontPolicy)
+	FontPolicy string `json:"fontPolicy"`
+	// Detector selects the auto-detect implementation, "rules" or "ngram" (see engine.NewDetector)
+	Detector string `json:"detector"`
+	// VNIStrictness selects how VNI text reads the ambiguous Ö/ö (see converter.ParseVNIStrictness)
+	VNIStrictness string `json:"vniStrictness,omitempty"`
+	// OutputNormalization is the Unicode form of converted text, "nfc" (default) or "nfd"
+	// (see converter.ParseNormalization)
+	OutputNormalization string `json:"outputNormalization,omitempty"`
+	// QueueOrder is the batch processing order (see engine.ParseQueueOrder)
+	QueueOrder string `json:"queueOrder"`
+	// IO throttling for network shares (disabled when ThrottleIO is false)
+	ThrottleIO       bool `json:"throttleIO"`
+	MaxOpenFiles     int  `json:"maxOpenFiles"`
+	InterFileDelayMs int  `json:"interFileDelayMs"`
+
+	// MaxJobs is how many conversions run at once; later ones wait in the queue
+	MaxJobs int `json:"maxJobs"`
+	// MaxCellLength skips longer cells during conversion (0 disables the guard)
+	MaxCellLength int `json:"maxCellLength"`
+	// SharedOutput gives outputs the output folder's permissions (for shared network folders)
+	SharedOutput bool `json:"sharedOutput"`
+	// IgnorePatterns are file name patterns skipped by folder scans and the watch folder
+	// (see engine.NewFileFilter); nil selects the defaults, an empty list disables them
+	IgnorePatterns []string `json:"ignorePatterns"`
+	// MinFileSize skips smaller files (bytes) in folder scans and the watch folder
+	MinFileSize int64 `json:"minFileSize"`
+	// KeepSheetNames disables converting legacy-encoded sheet names to Unicode
+	KeepSheetNames bool `json:"keepSheetNames"`
+	// RemapStyleFonts rewrites legacy fonts of the styles table so empty cells are safe to type into
+	RemapStyleFonts bool `json:"remapStyleFonts"`
+	// PlainCells writes plain cells back as plain strings instead of rich text
+	PlainCells bool `json:"plainCells"`
+	// RetentionKeepLast keeps only the newest outputs of each source in watch-folder and
+	// command line output folders (0 keeps all, see engine.RetentionPolicy)
+	RetentionKeepLast int `json:"retentionKeepLast"`
+	// RetentionMaxAgeDays deletes outputs older than this many days from those folders (0 keeps all)
+	RetentionMaxAgeDays int `json:"retentionMaxAgeDays"`
+	// WatchInputDir and WatchOutputDir are the folders the app's watch folder last watched
+	// and saved to, offered again on the next start
+	WatchInputDir  string `json:"watchInputDir,omitempty"`
+	WatchOutputDir string `json:"watchOutputDir,omitempty"`
+	// TimestampFormat is the output name suffix, e.g. "yyyy_MM_dd_HH_mm_ss" (see engine.ParseTimestampFormat)
+	TimestampFormat string `json:"timestampFormat"`
+	// NotifyWebhook receives a JSON summary (POST) when a conversion finishes, e.g. a Slack or Teams incoming webhook
+	NotifyWebhook string `json:"notifyWebhook,omitempty"`
+	// NotifyToast shows a Windows notification when a conversion finishes
+	NotifyToast bool `json:"notifyToast"`
+	// SpellDictionary is a hunspell .dic file for spell-check reports (empty: built-in syllable rules)
+	SpellDictionary string `json:"spellDictionary,omitempty"`
+	// OfficePath is the LibreOffice soffice binary for PDF export (empty: found in PATH or the default install folder)
+	OfficePath string `json:"officePath,omitempty"`
+	// ConvertLegacyFormats imports Excel 95 and older and Lotus 1-2-3 files to .xlsx with
+	// LibreOffice (see OfficePath) before converting them; otherwise they are refused with advice
+	ConvertLegacyFormats bool `json:"convertLegacyFormats"`
+	// JobTemplates are the profiles conversion links name, by profile name
+	JobTemplates map[string]JobTemplate `json:"jobTemplates,omitempty"`
+	// AllowRemoteLinkPaths lets conversion links name files on network shares and drives
+	AllowRemoteLinkPaths bool `json:"allowRemoteLinkPaths"`
+	// LogLevel is the minimum level of the log file: "debug", "info" (default), "warn" or "error"
+	LogLevel string `json:"logLevel,omitempty"`
+	// LogJSON writes the log file as one JSON object per line
+	LogJSON bool `json:"logJSON"`
+	// UpdateMirrors are tried in order when the GitHub download fails (see updater.Sources)
+	UpdateMirrors []string    `json:"updateMirrors,omitempty"`
+	Window        WindowState `json:"window"`
+}
+
+// Default returns the settings used on first launch.
+func Default() Settings {
+	return Settings{
+		Theme:    ThemeDark,
+		Language: LangEnglish,
+		// Throttling is off by default; these values apply once it is enabled
+		MaxOpenFiles:     DefaultMaxOpenFiles,
+		InterFileDelayMs: DefaultInterFileDelayMs,
+		MaxJobs:          DefaultMaxJobs,
+		MaxCellLength:    DefaultMaxCellLength,
+		Window:           WindowState{Width: DefaultWidth, Height: DefaultHeight},
+	}
+}
+
+// Normalize replaces invalid values with defaults.
+// Why: A hand-edited or corrupted file must never produce an unusable window.
+func (s Settings) Normalize() Settings {
+	if s.Theme != ThemeDark && s.Theme != ThemeLight {
+		s.Theme = ThemeDark
+	}
+	if s.Language != LangEnglish && s.Language != LangVietnamese {
+		s.Language = LangEnglish
+	}
+	if s.MaxOpenFiles < 1 {
+		s.MaxOpenFiles = DefaultMaxOpenFiles
+	}
+	if s.InterFileDelayMs < 0 {
+		s.InterFileDelayMs = 0
+	}
+	if s.MaxJobs < 1 {
+		s.MaxJobs = DefaultMaxJobs
+	}
+	if s.MaxCellLength < 0 {
+		s.MaxCellLength = DefaultMaxCellLength
+	}
+	s.UpdateMirrors = nonEmpty(s.UpdateMirrors)
+	if s.MinFileSize < 0 {
+		s.MinFileSize = 0
+	}
+	if s.RetentionKeepLast < 0 {
+		s.RetentionKeepLast = 0
+	}
+	if s.RetentionMaxAgeDays < 0 {
+		s.RetentionMaxAgeDays = 0
+	}
+	if s.Window.Width < MinWidth {
+		s.Window.Width = DefaultWidth
+	}
+	if s.Window.Height < MinHeight {
+		s.Window.Height = DefaultHeight
+	}
+	return s
+}
+
+// nonEmpty drops blank entries, returning nil when nothing is left.
+func nonEmpty(list []string) []string {
+	var out []string
+	for _, v := range list {
+		if strings.TrimSpace(v) != "" {
+			out = append(out, strings.TrimSpace(v))
+		}
+	}
+	return out
+}
+
+// Store loads and saves Settings as JSON on disk. It is safe for concurrent use.
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewStore creates a store backed by the given file path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns the settings file location in the user's config directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config dir: %w", err)
+	}
+	return filepath.Join(dir, appDirName, "settings.json"), nil
+}
+
+// Path returns the file backing the store.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load reads the settings. A missing file yields Default() without error.
+func (s *Store) Load() (Settings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return Default(), fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	cfg := Default()
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Default(), fmt.Errorf("failed to parse settings: %w", err)
+	}
+	return cfg.Normalize(), nil
+}
+
+// Save writes the settings atomically (temp file + rename).
+func (s *Store) Save(cfg Settings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(cfg.Normalize(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0750); err != nil {
+		return fmt.Errorf("failed to create settings dir: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write settings: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace settings: %w", err)
+	}
+	return nil
+}
+
+// Update loads the settings, applies fn and saves the result.
+func (s *Store) Update(fn func(*Settings)) error {
+	cfg, err := s.Load()
+	if err != nil {
+		return err
+	}
+	fn(&cfg)
+	return s.Save(cfg)
+}
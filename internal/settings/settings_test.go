@@ -0,0 +1,81 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStore_LoadMissingReturnsDefault(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "missing.json"))
+	cfg, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("Load() = %+v, want defaults", cfg)
+	}
+}
+
+func TestStore_SaveLoadRoundTrip(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "nested", "settings.json"))
+	want := Default()
+	want.Theme = ThemeLight
+	want.Language = LangVietnamese
+	want.ThrottleIO = true
+	want.UpdateMirrors = []string{"https://mirror.example.com/vni", `\\fileserver\share\VniConverter`}
+	want.Window = WindowState{Width: 1200, Height: 900, X: 10, Y: 20, HasPos: true, Maximized: true}
+
+	if err := s.Save(want); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	got, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSettings_Normalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Settings)
+	}{
+		{name: "Unknown theme falls back to dark", mutate: func(s *Settings) { s.Theme = "neon" }},
+		{name: "Unknown language falls back to English", mutate: func(s *Settings) { s.Language = "fr" }},
+		{name: "Tiny window restored to defaults", mutate: func(s *Settings) { s.Window.Width, s.Window.Height = 10, 10 }},
+		{name: "Invalid open file limit", mutate: func(s *Settings) { s.MaxOpenFiles = 0 }},
+		{name: "No concurrent jobs", mutate: func(s *Settings) { s.MaxJobs = 0 }},
+		{name: "Negative max cell length", mutate: func(s *Settings) { s.MaxCellLength = -1 }},
+		{name: "Blank mirrors dropped", mutate: func(s *Settings) { s.UpdateMirrors = []string{" ", ""} }},
+		{name: "Negative minimum file size", mutate: func(s *Settings) { s.MinFileSize = -5 }},
+		{name: "Negative retention", mutate: func(s *Settings) { s.RetentionKeepLast, s.RetentionMaxAgeDays = -1, -30 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := Default()
+			tt.mutate(&input)
+			if got := input.Normalize(); !reflect.DeepEqual(got, Default()) {
+				t.Errorf("Normalize() = %+v, want %+v", got, Default())
+			}
+		})
+	}
+}
+
+func TestStore_LoadCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	cfg, err := NewStore(path).Load()
+	if err == nil {
+		t.Error("expected parse error")
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("Load() = %+v, want defaults on error", cfg)
+	}
+}
@@ -0,0 +1,127 @@
+// Package spell checks converted Vietnamese text for improbable words.
+// Why: Text converted with the wrong encoding table still looks like Vietnamese at a
+// glance ("Vieät", "nguyÔn"), but its syllables are not Vietnamese. Flagging them is the
+// cheapest way to catch a wrong detection without reading every cell.
+package spell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Checker decides whether words are Vietnamese. The zero value is not usable; create one
+// with New or LoadDictionary.
+type Checker struct {
+	// words holds the lowercase NFC syllables of a dictionary; nil checks syllable rules
+	words map[string]bool
+}
+
+// New returns a checker that accepts every well-formed Vietnamese syllable (see
+// ValidSyllable), so no dictionary file is needed.
+func New() *Checker {
+	return &Checker{}
+}
+
+// LoadDictionary reads a hunspell dictionary (.dic) file, such as vi_VN.dic.
+func LoadDictionary(path string) (*Checker, error) {
+	f, err := os.Open(path) //nolint:gosec // path chosen by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dictionary: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return ReadDictionary(f)
+}
+
+// ReadDictionary reads a hunspell .dic file: an optional word count on the first line,
+// then one entry per line. Affix flags ("word/AB") and morphological fields after a tab
+// are ignored, and entries of several syllables add each syllable.
+func ReadDictionary(r io.Reader) (*Checker, error) {
+	c := &Checker{words: make(map[string]bool)}
+	sc := bufio.NewScanner(r)
+	first := true
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if first {
+			first = false
+			if isCount(line) {
+				continue
+			}
+		}
+		if i := strings.IndexAny(line, "/\t"); i >= 0 {
+			line = line[:i]
+		}
+		for _, word := range words(line) {
+			c.words[normalize(word)] = true
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dictionary: %w", err)
+	}
+	if len(c.words) == 0 {
+		return nil, fmt.Errorf("dictionary has no words")
+	}
+	return c, nil
+}
+
+// Known reports whether word is in the dictionary, or a valid syllable without one.
+func (c *Checker) Known(word string) bool {
+	if c.words != nil {
+		return c.words[normalize(word)]
+	}
+	return ValidSyllable(word)
+}
+
+// Improbable returns the distinct words of text that are not Vietnamese, in order.
+// Words of plain ASCII letters are never reported: they are as likely to be English,
+// codes or names as Vietnamese without accents, and conversion does not produce them.
+func (c *Checker) Improbable(text string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, word := range words(text) {
+		if isASCII(word) || seen[word] || c.Known(word) {
+			continue
+		}
+		seen[word] = true
+		out = append(out, word)
+	}
+	return out
+}
+
+// words splits text into runs of letters and combining marks.
+func words(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.Is(unicode.Mn, r)
+	})
+}
+
+func normalize(word string) string {
+	return norm.NFC.String(strings.ToLower(word))
+}
+
+func isCount(line string) bool {
+	if line == "" {
+		return false
+	}
+	for _, r := range line {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
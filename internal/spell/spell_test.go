@@ -0,0 +1,73 @@
+package spell
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidSyllable(t *testing.T) {
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{word: "Việt", want: true},
+		{word: "Nguyễn", want: true},
+		{word: "người", want: true},
+		{word: "nghiêng", want: true},
+		{word: "giếng", want: true},
+		{word: "gìn", want: true},
+		{word: "quốc", want: true},
+		{word: "khuya", want: true},
+		{word: "đường", want: true},
+		{word: "kể", want: true},
+		{word: "Vie\u00E4t", want: false},  // VNI read as Unicode
+		{word: "nguy\u00D4n", want: false}, // legacy letter left unconverted
+		{word: "việtt", want: false},
+		{word: "vìệt", want: false}, // two tones
+		{word: "viềt", want: false}, // stop final with huyền
+		{word: "ka", want: false},
+		{word: "ce", want: false},
+		{word: "ngi", want: false},
+		{word: "quo", want: false},
+		{word: "café", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := ValidSyllable(tt.word); got != tt.want {
+				t.Errorf("ValidSyllable(%q) = %v, want %v", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImprobable(t *testing.T) {
+	dict, err := ReadDictionary(strings.NewReader("3\nviệt/A\nnam\nhà nội\tpo:noun\n"))
+	if err != nil {
+		t.Fatalf("ReadDictionary failed: %v", err)
+	}
+	tests := []struct {
+		name    string
+		checker *Checker
+		text    string
+		want    []string
+	}{
+		{name: "Rules", checker: New(), text: "Công ty TNHH Việt Nam", want: nil},
+		{name: "Rules mojibake", checker: New(), text: "Co\u00E2ng ty Vie\u00E4t Nam, Vie\u00E4t", want: []string{"Co\u00E2ng", "Vie\u00E4t"}},
+		{name: "Dictionary", checker: dict, text: "Việt Nam, Hà Nội", want: nil},
+		{name: "Dictionary unknown word", checker: dict, text: "Việt Nam, Huế", want: []string{"Huế"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.checker.Improbable(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Improbable(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadDictionaryEmpty(t *testing.T) {
+	if _, err := ReadDictionary(strings.NewReader("0\n")); err == nil {
+		t.Error("ReadDictionary succeeded on an empty dictionary, want an error")
+	}
+}
@@ -0,0 +1,147 @@
+package spell
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Tone marks, as combining characters after NFD decomposition.
+const (
+	toneGrave = '\u0300' // huyền
+	toneAcute = '\u0301' // sắc
+	toneTilde = '\u0303' // ngã
+	toneHook  = '\u0309' // hỏi
+	toneDot   = '\u0323' // nặng
+)
+
+// shapeMarks are the other combining marks of Vietnamese letters: â ê ô, ă and ơ ư.
+const shapeMarks = "\u0302\u0306\u031B"
+
+// onsets are the initial consonants, longest first so "ngh" is tried before "ng".
+var onsets = []string{
+	"ngh", "ng", "nh", "ch", "gh", "gi", "kh", "ph", "qu", "th", "tr",
+	"b", "c", "d", "đ", "g", "h", "k", "l", "m", "n", "p", "r", "s", "t", "v", "x", "",
+}
+
+// rhymes are the toneless rhymes (medial, nucleus and final) of Vietnamese syllables.
+var rhymes = makeSet(`
+	a e ê i o ô ơ u ư y
+	ai ao au ay âu ây eo êu ia iu oa oe oi ôi ơi ua uê ui uy ưa ưi ưu uơ
+	oai oao oay oeo uây uôi ươi ươu yêu iêu uya uyu
+	ac ach am an ang anh ap at
+	ăc ăm ăn ăng ăp ăt
+	âc âm ân âng âp ât
+	ec em en eng ep et
+	êch êm ên ênh êp êt
+	ich im in inh ip it
+	oc om on ong op ot oong ooc
+	ôc ôm ôn ông ôp ôt
+	ơm ơn ơp ơt
+	uc um un ung up ut
+	ưc ưm ưn ưng ưt
+	oac oach oam oan oang oanh oap oat
+	oăc oăm oăn oăng oăt
+	oem oen oet
+	uân uâng uât
+	uêch uên uênh
+	uych uyn uynh uyp uyt
+	iêc iêm iên iêng iêp iêt
+	yêm yên yêt
+	uyên uyêt
+	uôc uôm uôn uông uôt
+	ươc ươm ươn ương ươp ươt
+`)
+
+func makeSet(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, r := range strings.Fields(list) {
+		set[r] = true
+	}
+	return set
+}
+
+// ValidSyllable reports whether word is a well-formed Vietnamese syllable: a known
+// initial consonant and rhyme, at most one tone mark, and only the sắc or nặng tone on
+// rhymes ending in c, ch, p or t. Spelling rules such as "k" only before i, e, ê and y are
+// checked too, so "ca" is valid but "ka" and "ce" are not.
+func ValidSyllable(word string) bool {
+	base, tone, ok := splitTone(strings.ToLower(word))
+	if !ok || base == "" {
+		return false
+	}
+	for _, onset := range onsets {
+		if strings.HasPrefix(base, onset) && validRhyme(onset, base[len(onset):], tone) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTone returns word without its tone mark (in NFC) and the tone mark (0 for the
+// level tone). ok is false for several tone marks or marks foreign to Vietnamese.
+func splitTone(word string) (base string, tone rune, ok bool) {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(word) {
+		switch {
+		case r == toneGrave || r == toneAcute || r == toneTilde || r == toneHook || r == toneDot:
+			if tone != 0 {
+				return "", 0, false
+			}
+			tone = r
+		case strings.ContainsRune(shapeMarks, r):
+			b.WriteRune(r)
+		case (r >= 'a' && r <= 'z') || r == 'đ':
+			b.WriteRune(r)
+		default:
+			return "", 0, false
+		}
+	}
+	return norm.NFC.String(b.String()), tone, true
+}
+
+// validRhyme checks rhyme and tone after onset.
+func validRhyme(onset, rhyme string, tone rune) bool {
+	// "gi" absorbs the i of iê: "giếng" is gi + iêng
+	if onset == "gi" && strings.HasPrefix(rhyme, "ê") {
+		rhyme = "i" + rhyme
+	}
+	if !rhymes[rhyme] || !onsetFits(onset, rhyme) {
+		return false
+	}
+	if isStopRhyme(rhyme) {
+		return tone == toneAcute || tone == toneDot
+	}
+	return true
+}
+
+// onsetFits checks the spelling rules tying an onset to the vowel that follows it.
+func onsetFits(onset, rhyme string) bool {
+	front := strings.ContainsAny(firstLetter(rhyme), "ieêy")
+	switch onset {
+	case "k", "gh", "ngh":
+		return front
+	case "c", "ng":
+		return !front
+	case "g":
+		// "gì" and "gìn" are g + i; ge and gê are spelled gh
+		return !strings.HasPrefix(rhyme, "e") && !strings.HasPrefix(rhyme, "ê")
+	case "qu":
+		// The u of qu is the medial; "quo" and "quu" do not exist
+		return !strings.HasPrefix(rhyme, "o") && !strings.HasPrefix(rhyme, "u")
+	}
+	return true
+}
+
+// isStopRhyme reports whether rhyme ends in a stop consonant: c, ch, p or t.
+func isStopRhyme(rhyme string) bool {
+	return strings.HasSuffix(rhyme, "ch") || strings.HasSuffix(rhyme, "c") ||
+		strings.HasSuffix(rhyme, "p") || strings.HasSuffix(rhyme, "t")
+}
+
+func firstLetter(s string) string {
+	for _, r := range s {
+		return string(r)
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTP reads objects with GET and writes them with PUT (e.g. WebDAV or pre-signed URLs).
+// Listing is not supported.
+type HTTP struct {
+	Client *http.Client
+}
+
+// DefaultHTTPTimeout bounds a single request of the default HTTP backend.
+const DefaultHTTPTimeout = 5 * time.Minute
+
+func httpFactory(u *url.URL) (Storage, string, error) {
+	return HTTP{Client: &http.Client{Timeout: DefaultHTTPTimeout}}, u.String(), nil
+}
+
+func (h HTTP) client() *http.Client {
+	if h.Client != nil {
+		return h.Client
+	}
+	return http.DefaultClient
+}
+
+// do sends a request and fails on any non-2xx status.
+func (h HTTP) do(ctx context.Context, method, name string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, name, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := h.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w", method, name, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%s %s failed: %s", method, name, resp.Status)
+	}
+	return resp, nil
+}
+
+// Open implements Storage.
+func (h HTTP) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	resp, err := h.do(ctx, http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Create implements Storage. The content is buffered and uploaded on Commit.
+func (h HTTP) Create(ctx context.Context, name string) (Writer, error) {
+	return &httpWriter{h: h, ctx: ctx, name: name}, nil
+}
+
+// Stat implements Storage with a HEAD request.
+func (h HTTP) Stat(ctx context.Context, name string) (FileInfo, error) {
+	resp, err := h.do(ctx, http.MethodHead, name, nil)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	_ = resp.Body.Close()
+	info := FileInfo{Name: name, Size: resp.ContentLength}
+	if modified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.ModTime = modified
+	}
+	return info, nil
+}
+
+// List implements Storage; HTTP has no standard listing.
+func (HTTP) List(context.Context, string) ([]FileInfo, error) {
+	return nil, ErrNotSupported
+}
+
+type httpWriter struct {
+	h    HTTP
+	ctx  context.Context //nolint:containedctx // the upload happens on Commit
+	name string
+	buf  bytes.Buffer
+}
+
+func (w *httpWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *httpWriter) Commit() error {
+	resp, err := w.h.do(w.ctx, http.MethodPut, w.name, bytes.NewReader(w.buf.Bytes()))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (w *httpWriter) Abort() error {
+	w.buf.Reset()
+	return nil
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// maxTempAttempts bounds the random temporary names tried by Local.Create.
+const maxTempAttempts = 100
+
+// Local stores objects in the local file system, including mapped drives and UNC paths.
+type Local struct{}
+
+// Open implements Storage.
+func (Local) Open(_ context.Context, name string) (io.ReadCloser, error) {
+	f, err := os.Open(name) //nolint:gosec // caller-supplied input path
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	return f, nil
+}
+
+// Create implements Storage. Content is written to a temporary file in the target folder
+// and renamed over name on Commit.
+func (Local) Create(_ context.Context, name string) (Writer, error) {
+	dir := filepath.Dir(name)
+	for attempt := 0; attempt < maxTempAttempts; attempt++ {
+		// Neither name is security sensitive: both are in the caller's output folder
+		tmp := filepath.Join(dir, fmt.Sprintf(".vniconverter-%08x.tmp", rand.Uint32())) //nolint:gosec
+		// Unlike os.CreateTemp (0600), 0666 minus umask matches what os.Create gives the output
+		f, err := os.OpenFile(tmp, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0666) //nolint:gosec
+		if err == nil {
+			return &localWriter{f: f, name: name}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create %s: %w", name, err)
+		}
+	}
+	return nil, fmt.Errorf("failed to create %s: no free temporary name", name)
+}
+
+// Stat implements Storage.
+func (Local) Stat(_ context.Context, name string) (FileInfo, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return FileInfo{}, fmt.Errorf("failed to stat %s: %w", name, err)
+	}
+	return FileInfo{Name: name, Size: info.Size(), ModTime: info.ModTime(), IsDir: info.IsDir()}, nil
+}
+
+// List implements Storage.
+func (Local) List(_ context.Context, dir string) ([]FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	files := make([]FileInfo, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			// Removed since ReadDir; skip it
+			continue
+		}
+		files = append(files, FileInfo{
+			Name:    filepath.Join(dir, e.Name()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			IsDir:   e.IsDir(),
+		})
+	}
+	return files, nil
+}
+
+type localWriter struct {
+	f    *os.File
+	name string
+}
+
+func (w *localWriter) Write(p []byte) (int, error) {
+	return w.f.Write(p)
+}
+
+// Commit makes the file visible under its name, replacing any existing file.
+func (w *localWriter) Commit() error {
+	if err := w.f.Close(); err != nil {
+		_ = os.Remove(w.f.Name())
+		return fmt.Errorf("failed to write %s: %w", w.name, err)
+	}
+	if err := os.Rename(w.f.Name(), w.name); err != nil {
+		_ = os.Remove(w.f.Name())
+		return fmt.Errorf("failed to save %s: %w", w.name, err)
+	}
+	return nil
+}
+
+func (w *localWriter) Abort() error {
+	_ = w.f.Close()
+	if err := os.Remove(w.f.Name()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to discard %s: %w", w.name, err)
+	}
+	return nil
+}
+
+// localPath converts a file:// URL to an OS path (file://server/share/x is a UNC path).
+func localPath(u *url.URL) string {
+	path := filepath.FromSlash(u.Path)
+	if u.Host != "" && u.Host != "localhost" {
+		return `\\` + u.Host + path
+	}
+	// file:///C:/book.xlsx
+	if runtime.GOOS == "windows" && len(u.Path) > 2 && u.Path[0] == '/' && u.Path[2] == ':' {
+		return strings.TrimPrefix(path, `\`)
+	}
+	return path
+}
@@ -0,0 +1,124 @@
+// Package storage abstracts where workbooks are read from and written to.
+//
+// Inputs and outputs are addressed by URI. Plain paths (including UNC paths such as
+// \\server\share\book.xlsx) use the local file system; other schemes are served by the
+// backend registered for them, so new backends (S3, Google Drive, ...) plug in with
+// Register without changes to the engine. The command line batch and serve jobs go
+// through it (see engine.NewStorageConverter); the GUI batch and the watch folder work on
+// local and UNC paths only.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotSupported is returned by backends for operations they cannot perform
+// (e.g. listing an HTTP server).
+var ErrNotSupported = errors.New("operation not supported by this storage backend")
+
+// FileInfo describes a stored object.
+type FileInfo struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+	IsDir   bool
+}
+
+// Writer receives an object's content. Nothing is visible under the name until Commit
+// succeeds; Abort discards what was written.
+// Why: A failed conversion must never leave a truncated workbook behind, on any backend.
+type Writer interface {
+	io.Writer
+	Commit() error
+	Abort() error
+}
+
+// Storage is a place workbooks are read from and written to. Names are backend-specific
+// (a path for Local, a URL for HTTP).
+type Storage interface {
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	Create(ctx context.Context, name string) (Writer, error)
+	Stat(ctx context.Context, name string) (FileInfo, error)
+	// List returns the objects directly inside dir
+	List(ctx context.Context, dir string) ([]FileInfo, error)
+}
+
+// Factory returns the backend serving u, and the name of u within it.
+type Factory func(u *url.URL) (Storage, string, error)
+
+var (
+	mu       sync.RWMutex
+	backends = map[string]Factory{
+		"file": func(u *url.URL) (Storage, string, error) {
+			return Local{}, localPath(u), nil
+		},
+		"http":  httpFactory,
+		"https": httpFactory,
+	}
+)
+
+// Register makes a backend available for a URI scheme, replacing any previous one.
+func Register(scheme string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	backends[strings.ToLower(scheme)] = factory
+}
+
+// Schemes returns the registered URI schemes, sorted.
+func Schemes() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	schemes := make([]string, 0, len(backends))
+	for s := range backends {
+		schemes = append(schemes, s)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// IsURI reports whether location names a backend ("scheme://...") rather than a local path.
+func IsURI(location string) bool {
+	scheme, _, ok := strings.Cut(location, "://")
+	// A single letter is a Windows drive, not a scheme
+	return ok && len(scheme) > 1 && !strings.ContainsAny(scheme, `/\`)
+}
+
+// Resolve returns the backend serving location and the name of the object within it.
+func Resolve(location string) (Storage, string, error) {
+	if !IsURI(location) {
+		return Local{}, location, nil
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid storage location %q: %w", location, err)
+	}
+	mu.RLock()
+	factory, ok := backends[strings.ToLower(u.Scheme)]
+	mu.RUnlock()
+	if !ok {
+		return nil, "", fmt.Errorf("no storage backend for %q (supported: %s)", u.Scheme, strings.Join(Schemes(), ", "))
+	}
+	return factory(u)
+}
+
+// Base returns the last element of a path or URI, without any query string.
+func Base(location string) string {
+	if IsURI(location) {
+		if u, err := url.Parse(location); err == nil {
+			location = u.Path
+		}
+	}
+	location = strings.TrimRight(location, `/\`)
+	if i := strings.LastIndexAny(location, `/\`); i >= 0 {
+		return location[i+1:]
+	}
+	return location
+}
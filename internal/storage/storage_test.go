@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestIsURI(t *testing.T) {
+	tests := []struct {
+		location string
+		want     bool
+	}{
+		{location: "book.xlsx", want: false},
+		{location: `C:\data\book.xlsx`, want: false},
+		{location: `\\server\share\book.xlsx`, want: false},
+		{location: "C://data/book.xlsx", want: false},
+		{location: "https://example.com/book.xlsx", want: true},
+		{location: "s3://bucket/key.xlsx", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			if got := IsURI(tt.location); got != tt.want {
+				t.Errorf("IsURI(%q) = %v, want %v", tt.location, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	Register("mem", func(u *url.URL) (Storage, string, error) {
+		return Local{}, u.Host + u.Path, nil
+	})
+
+	tests := []struct {
+		location string
+		wantType Storage
+		wantName string
+		wantErr  bool
+	}{
+		{location: "book.xlsx", wantType: Local{}, wantName: "book.xlsx"},
+		{location: "file:///tmp/book.xlsx", wantType: Local{}, wantName: filepath.FromSlash("/tmp/book.xlsx")},
+		{location: "https://example.com/a/book.xlsx?sig=1", wantType: HTTP{}, wantName: "https://example.com/a/book.xlsx?sig=1"},
+		{location: "mem://bucket/book.xlsx", wantType: Local{}, wantName: "bucket/book.xlsx"},
+		{location: "gdrive://folder/book.xlsx", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			s, name, err := Resolve(tt.location)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve(%q) error = %v, wantErr %v", tt.location, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if _, ok := s.(HTTP); ok != (tt.wantType == HTTP{}) {
+				t.Errorf("Resolve(%q) backend = %T, want %T", tt.location, s, tt.wantType)
+			}
+			if name != tt.wantName {
+				t.Errorf("Resolve(%q) name = %q, want %q", tt.location, name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestBase(t *testing.T) {
+	tests := []struct {
+		location string
+		want     string
+	}{
+		{location: "book.xlsx", want: "book.xlsx"},
+		{location: `\\server\share\book.xlsx`, want: "book.xlsx"},
+		{location: "dir/sub/book.xlsx", want: "book.xlsx"},
+		{location: "https://example.com/a/book.xlsx?sig=1", want: "book.xlsx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			if got := Base(tt.location); got != tt.want {
+				t.Errorf("Base(%q) = %q, want %q", tt.location, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocal_CreateCommitAbort(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	committed := filepath.Join(dir, "committed.xlsx")
+	w, err := Local{}.Create(ctx, committed)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := io.WriteString(w, "data"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := os.Stat(committed); !errors.Is(err, os.ErrNotExist) {
+		t.Error("output visible before Commit")
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if data, err := os.ReadFile(committed); err != nil || string(data) != "data" {
+		t.Errorf("committed file = %q, %v", data, err)
+	}
+
+	w, err = Local{}.Create(ctx, filepath.Join(dir, "aborted.xlsx"))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	_, _ = io.WriteString(w, "partial")
+	if err := w.Abort(); err != nil {
+		t.Fatalf("Abort failed: %v", err)
+	}
+
+	files, err := Local{}.List(ctx, dir)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != committed || files[0].Size != 4 {
+		t.Errorf("List() = %+v, want only the committed file", files)
+	}
+}
+
+func TestHTTP_RoundTrip(t *testing.T) {
+	var mu sync.Mutex
+	stored := map[string][]byte{"/in.xlsx": []byte("workbook")}
+	uploaded := func(path string) ([]byte, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		data, ok := stored[path]
+		return data, ok
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			data, ok := stored[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(data)
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			stored[r.URL.Path] = data
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	h := HTTP{Client: srv.Client()}
+
+	r, err := h.Open(ctx, srv.URL+"/in.xlsx")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	_ = r.Close()
+	if string(data) != "workbook" {
+		t.Errorf("Open() read %q", data)
+	}
+	if info, err := h.Stat(ctx, srv.URL+"/in.xlsx"); err != nil || info.Size != int64(len("workbook")) {
+		t.Errorf("Stat() = %+v, %v", info, err)
+	}
+	if _, err := h.Open(ctx, srv.URL+"/missing.xlsx"); err == nil {
+		t.Error("expected error for a missing object")
+	}
+
+	w, err := h.Create(ctx, srv.URL+"/out.xlsx")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	_, _ = io.WriteString(w, "converted")
+	if _, ok := uploaded("/out.xlsx"); ok {
+		t.Error("uploaded before Commit")
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if data, _ := uploaded("/out.xlsx"); string(data) != "converted" {
+		t.Errorf("uploaded %q", data)
+	}
+	if _, err := h.List(ctx, srv.URL); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("List() error = %v, want ErrNotSupported", err)
+	}
+}
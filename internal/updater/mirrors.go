@@ -0,0 +1,130 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// Placeholders expanded in mirror templates.
+const (
+	placeholderVersion = "{version}"
+	placeholderAsset   = "{asset}"
+)
+
+// Sources returns the download locations to try, in order: the release URL first,
+// then every mirror. A mirror is a URL or file path template where {version} is the
+// release tag and {asset} the file name; without {asset}, the file name is appended.
+// Why: Some networks block github.com downloads; enterprises can host releases on
+// an internal web server or file share instead.
+func Sources(downloadURL, version string, mirrors []string) []string {
+	// Mirrors are named after the release asset, so there is nothing to try without it
+	if downloadURL == "" {
+		return nil
+	}
+	asset := path.Base(downloadURL)
+	sources := make([]string, 0, len(mirrors)+1)
+	sources = append(sources, downloadURL)
+	for _, m := range mirrors {
+		m = strings.TrimSpace(m)
+		if m == "" {
+			continue
+		}
+		if !strings.Contains(m, placeholderAsset) {
+			sep := "/"
+			if isFilePath(m) && strings.Contains(m, `\`) {
+				sep = `\`
+			}
+			m = strings.TrimRight(m, `/\`) + sep + placeholderAsset
+		}
+		m = strings.ReplaceAll(m, placeholderVersion, version)
+		m = strings.ReplaceAll(m, placeholderAsset, asset)
+		sources = append(sources, m)
+	}
+	return sources
+}
+
+// DownloadFirst tries each source in order and returns the one that succeeded.
+// Sources without a URL scheme are read as (local or UNC) file paths.
+func DownloadFirst(ctx context.Context, sources []string, dest string, progress ProgressFunc) (string, error) {
+	if len(sources) == 0 {
+		return "", fmt.Errorf("no download URL provided")
+	}
+	var errs []error
+	for _, src := range sources {
+		var err error
+		if isFilePath(src) {
+			err = copyFile(ctx, src, dest, progress)
+		} else {
+			err = Download(ctx, src, dest, progress)
+		}
+		if err == nil {
+			return src, nil
+		}
+		// Cancellation stops the whole attempt, not just this source
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", src, err))
+	}
+	return "", fmt.Errorf("all download sources failed: %w", errors.Join(errs...))
+}
+
+// isFilePath reports whether src has no URL scheme.
+func isFilePath(src string) bool {
+	return !strings.Contains(src, "://")
+}
+
+// copyFile copies a release binary from a file share, removing dest on failure.
+func copyFile(ctx context.Context, src, dest string, progress ProgressFunc) (err error) {
+	in, err := os.Open(src) //nolint:gosec // src comes from the administrator's mirror settings
+	if err != nil {
+		return fmt.Errorf("failed to open mirror file: %w", err)
+	}
+	defer func() { _ = in.Close() }()
+
+	total := int64(-1)
+	if st, statErr := in.Stat(); statErr == nil {
+		total = st.Size()
+	}
+
+	out, err := os.Create(dest) //nolint:gosec // dest is constructed safely from os.TempDir
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(dest)
+		}
+	}()
+
+	var w io.Writer = out
+	if progress != nil {
+		w = &progressWriter{w: out, total: total, fn: progress}
+	}
+	_, err = io.Copy(w, &ctxReader{ctx: ctx, r: io.LimitReader(in, maxDownloadSize)})
+	if closeErr := out.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy update: %w", err)
+	}
+	return nil
+}
+
+// ctxReader stops reading once ctx is cancelled.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *ctxReader) Read(b []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(b)
+}
@@ -0,0 +1,103 @@
+package updater
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSources(t *testing.T) {
+	const url = "https://github.com/o/r/releases/download/v1.2.0/VniConverter.exe"
+	tests := []struct {
+		name    string
+		mirrors []string
+		want    []string
+	}{
+		{name: "No mirrors", mirrors: nil, want: []string{url}},
+		{
+			name:    "Base URL gets asset appended",
+			mirrors: []string{"https://mirror.example.com/vni/"},
+			want:    []string{url, "https://mirror.example.com/vni/VniConverter.exe"},
+		},
+		{
+			name:    "Template placeholders",
+			mirrors: []string{"https://mirror.example.com/{version}/{asset}"},
+			want:    []string{url, "https://mirror.example.com/v1.2.0/VniConverter.exe"},
+		},
+		{
+			name:    "UNC share",
+			mirrors: []string{`\\fileserver\share\VniConverter`, "  "},
+			want:    []string{url, `\\fileserver\share\VniConverter\VniConverter.exe`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sources(url, "v1.2.0", tt.mirrors); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sources() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := Sources("", "v1.2.0", []string{"https://mirror.example.com"}); got != nil {
+		t.Errorf("Sources() without a release asset = %q, want nil", got)
+	}
+}
+
+func TestDownloadFirst(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/blocked/app.exe" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("from-http"))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	shareFile := filepath.Join(dir, "share", "app.exe")
+	if err := os.MkdirAll(filepath.Dir(shareFile), 0750); err != nil {
+		t.Fatalf("failed to create share: %v", err)
+	}
+	if err := os.WriteFile(shareFile, []byte("from-share"), 0600); err != nil {
+		t.Fatalf("failed to write share file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		sources  []string
+		wantFrom int // index of the source expected to succeed, -1 for failure
+		wantData string
+	}{
+		{name: "Primary works", sources: []string{srv.URL + "/ok/app.exe", shareFile}, wantFrom: 0, wantData: "from-http"},
+		{name: "Falls back to share", sources: []string{srv.URL + "/blocked/app.exe", shareFile}, wantFrom: 1, wantData: "from-share"},
+		{name: "All fail", sources: []string{srv.URL + "/blocked/app.exe", filepath.Join(dir, "missing.exe")}, wantFrom: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "update.exe")
+			used, err := DownloadFirst(context.Background(), tt.sources, dest, nil)
+			if tt.wantFrom < 0 {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				if _, statErr := os.Stat(dest); !os.IsNotExist(statErr) {
+					t.Errorf("partial file left behind")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadFirst failed: %v", err)
+			}
+			if used != tt.sources[tt.wantFrom] {
+				t.Errorf("used %s, want %s", used, tt.sources[tt.wantFrom])
+			}
+			if data, _ := os.ReadFile(dest); string(data) != tt.wantData {
+				t.Errorf("downloaded %q, want %q", data, tt.wantData)
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+// Package updater checks GitHub releases and installs new versions of the application.
+// Why: Shared by the GUI (Wails events) and headless builds (stdout progress),
+// so it must not depend on the Wails runtime.
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"time"
+)
+
+// GitHub repository info
+const (
+	GitHubOwner = "hoangtran1411"
+	GitHubRepo  = "convert-vni-to-unicode"
+
+	// HTTP client configuration
+	httpTimeout     = 30 * time.Second
+	downloadTimeout = 5 * time.Minute
+	maxDownloadSize = 200 * 1024 * 1024 // 200MB max download size
+)
+
+// ErrUnsupportedPlatform is returned when self-update cannot run on this OS.
+var ErrUnsupportedPlatform = errors.New("self-update is only supported on Windows")
+
+// ErrNoAsset is returned when a release has no binary for this OS/architecture.
+var ErrNoAsset = errors.New("no release asset for this platform")
+
+// Info holds information about available updates
+type Info struct {
+	Available   bool
+	CurrentVer  string
+	LatestVer   string
+	DownloadURL string
+	ReleaseURL  string
+}
+
+// Asset is a downloadable file attached to a release.
+type Asset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// GitHubRelease represents a GitHub release API response
+type GitHubRelease struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// ProgressFunc receives download progress. total is -1 when the size is unknown.
+type ProgressFunc func(written, total int64)
+
+// Check asks GitHub for the latest release and compares it with currentVersion.
+func Check(ctx context.Context, currentVersion string) (Info, error) {
+	info := Info{CurrentVer: currentVersion}
+
+	// Create HTTP client with timeout to prevent hanging
+	client := &http.Client{Timeout: httpTimeout}
+
+	// Call GitHub API
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", GitHubOwner, GitHubRepo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return info, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return info, fmt.Errorf("failed to check update: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close() // Error is non-critical after decoding
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return info, fmt.Errorf("update check failed with status: %d", resp.StatusCode)
+	}
+
+	var release GitHubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return info, fmt.Errorf("failed to decode release: %w", err)
+	}
+
+	info.LatestVer = release.TagName
+	info.ReleaseURL = release.HTMLURL
+
+	// Compare versions
+	if info.LatestVer == "" || !CompareVersions(info.LatestVer, currentVersion) {
+		return info, nil
+	}
+	info.Available = true
+
+	asset, err := SelectAsset(release.Assets, goruntime.GOOS, goruntime.GOARCH)
+	if err != nil {
+		return info, err
+	}
+	info.DownloadURL = asset.BrowserDownloadURL
+	return info, nil
+}
+
+// archAliases lists the names used for each GOARCH in asset file names.
+var archAliases = map[string][]string{
+	"amd64": {"amd64", "x64", "x86_64"},
+	"arm64": {"arm64", "aarch64"},
+	"386":   {"386", "x86", "i386"},
+}
+
+// SelectAsset picks the release binary for goos/goarch.
+// Why: With several architectures per release, "the first .exe" may be the wrong binary.
+// Assets follow the "VniConverter_<goos>_<goarch>.exe" convention; a legacy asset without
+// any architecture in its name (e.g. "VniConverter.exe") is accepted for amd64 only,
+// since all releases before multi-arch builds were amd64.
+func SelectAsset(assets []Asset, goos, goarch string) (Asset, error) {
+	ext := ""
+	if goos == "windows" {
+		ext = ".exe"
+	}
+
+	var legacy *Asset
+	for i, a := range assets {
+		name := strings.ToLower(a.Name)
+		if !strings.HasSuffix(name, ext) {
+			continue
+		}
+		tokens := strings.FieldsFunc(strings.TrimSuffix(name, ext), func(r rune) bool {
+			return r == '_' || r == '-' || r == '.'
+		})
+		arch := assetArch(tokens)
+		if arch == "" {
+			if legacy == nil {
+				legacy = &assets[i]
+			}
+			continue
+		}
+		if arch == goarch && hasToken(tokens, goos) {
+			return a, nil
+		}
+	}
+	if legacy != nil && goarch == "amd64" {
+		return *legacy, nil
+	}
+	return Asset{}, fmt.Errorf("%w: %s/%s", ErrNoAsset, goos, goarch)
+}
+
+// assetArch returns the GOARCH named by tokens, or "" if none is named.
+func assetArch(tokens []string) string {
+	for arch, aliases := range archAliases {
+		for _, alias := range aliases {
+			if hasToken(tokens, alias) {
+				return arch
+			}
+		}
+	}
+	return ""
+}
+
+func hasToken(tokens []string, want string) bool {
+	for _, t := range tokens {
+		if t == want {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareVersions returns true if v1 is newer than v2
+func CompareVersions(v1, v2 string) bool {
+	v1 = strings.TrimPrefix(v1, "v")
+	v2 = strings.TrimPrefix(v2, "v")
+
+	parts1 := parseVersion(v1)
+	parts2 := parseVersion(v2)
+
+	for i := 0; i < 3; i++ {
+		if parts1[i] > parts2[i] {
+			return true
+		}
+		if parts1[i] < parts2[i] {
+			return false
+		}
+	}
+	return false
+}
+
+func parseVersion(v string) [3]int {
+	var result [3]int
+	parts := strings.Split(v, ".")
+	for i := 0; i < len(parts) && i < 3; i++ {
+		_, _ = fmt.Sscanf(parts[i], "%d", &result[i])
+	}
+	return result
+}
+
+// Download saves url to dest, removing the partial file on any failure.
+// Why: A cancelled or broken download must never be picked up by the swap script.
+// progress may be nil.
+func Download(ctx context.Context, url, dest string, progress ProgressFunc) (err error) {
+	// Create HTTP client with timeout for download
+	client := &http.Client{Timeout: downloadTimeout}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close() // Error is non-critical during update
+	}()
+
+	// Validate response status
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	}
+
+	// Limit download size to prevent memory exhaustion attacks
+	limitedReader := io.LimitReader(resp.Body, maxDownloadSize)
+
+	out, err := os.Create(dest) //nolint:gosec // dest is constructed safely from os.TempDir
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(dest)
+		}
+	}()
+
+	var w io.Writer = out
+	if progress != nil {
+		w = &progressWriter{w: out, total: resp.ContentLength, fn: progress}
+	}
+	_, err = io.Copy(w, limitedReader)
+	if closeErr := out.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save update: %w", err)
+	}
+	return nil
+}
+
+// progressWriter reports the running byte count after every write.
+type progressWriter struct {
+	w       io.Writer
+	written int64
+	total   int64
+	fn      ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	p.fn(p.written, p.total)
+	return n, err
+}
+
+// Prepare downloads the new version from the first working source (see Sources)
+// and writes the swap script, returning its path.
+// When restart is true the script relaunches the app after swapping.
+func Prepare(ctx context.Context, sources []string, restart bool, progress ProgressFunc) (string, error) {
+	if len(sources) == 0 {
+		return "", fmt.Errorf("no download URL provided")
+	}
+	if goruntime.GOOS != "windows" {
+		return "", ErrUnsupportedPlatform
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to get executable path: %w", err)
+	}
+
+	// Resolve to absolute path safely
+	exePath, err = filepath.Abs(exePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve absolute path: %w", err)
+	}
+
+	// Security: Validate the executable path doesn't contain shell-dangerous characters
+	// This prevents command injection attacks
+	if strings.ContainsAny(exePath, `"&|<>^`) {
+		return "", fmt.Errorf("executable path contains unsafe characters")
+	}
+
+	tempDir := os.TempDir()
+	tempFile := filepath.Join(tempDir, "vni_update.exe")
+
+	if _, err := DownloadFirst(ctx, sources, tempFile, progress); err != nil {
+		return "", err
+	}
+
+	// Create batch script to swap files (and optionally restart)
+	// Note: Paths are validated above to prevent command injection
+	batchPath := filepath.Join(tempDir, "update_vni.bat")
+
+	// Use 0600 permission for security (owner read/write only)
+	if err := os.WriteFile(batchPath, []byte(SwapScript(exePath, tempFile, restart)), 0600); err != nil {
+		return "", fmt.Errorf("failed to create update script: %w", err)
+	}
+	return batchPath, nil
+}
+
+// SwapScript returns the batch script replacing exePath with newExe.
+// It waits briefly so the running process can exit first.
+func SwapScript(exePath, newExe string, restart bool) string {
+	relaunch := ""
+	if restart {
+		relaunch = fmt.Sprintf("start \"\" \"%s\"\n", exePath)
+	}
+	return fmt.Sprintf(`@echo off
+timeout /t 2 /nobreak >nul
+del "%s"
+move /y "%s" "%s"
+%sdel "%%~f0"
+`, exePath, newExe, exePath, relaunch)
+}
+
+// StartScript launches the swap script detached from this process.
+func StartScript(batchPath string) error {
+	cmd := exec.Command("cmd", "/c", "start", "/min", "", batchPath) //nolint:gosec,noctx // safe detached proc
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start update script: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		name   string
+		v1, v2 string
+		want   bool
+	}{
+		{name: "Newer patch", v1: "v1.2.4", v2: "1.2.3", want: true},
+		{name: "Newer minor", v1: "1.3.0", v2: "v1.2.9", want: true},
+		{name: "Equal", v1: "v1.2.3", v2: "v1.2.3", want: false},
+		{name: "Older", v1: "1.2.3", v2: "2.0.0", want: false},
+		{name: "Dev build", v1: "v0.1.0", v2: "0.0.0", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompareVersions(tt.v1, tt.v2); got != tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %v, want %v", tt.v1, tt.v2, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSwapScript(t *testing.T) {
+	tests := []struct {
+		name        string
+		restart     bool
+		wantRestart bool
+	}{
+		{name: "Restart", restart: true, wantRestart: true},
+		{name: "Install on exit", restart: false, wantRestart: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := SwapScript(`C:\App\app.exe`, `C:\Temp\new.exe`, tt.restart)
+			if !strings.Contains(script, `move /y "C:\Temp\new.exe" "C:\App\app.exe"`) {
+				t.Errorf("script missing move:\n%s", script)
+			}
+			if got := strings.Contains(script, `start "" "C:\App\app.exe"`); got != tt.wantRestart {
+				t.Errorf("relaunch = %v, want %v:\n%s", got, tt.wantRestart, script)
+			}
+		})
+	}
+}
+
+func TestDownload(t *testing.T) {
+	payload := strings.Repeat("x", 4096)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer srv.Close()
+
+	t.Run("Success reports progress", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "update.exe")
+		var last int64
+		err := Download(context.Background(), srv.URL+"/app.exe", dest, func(written, _ int64) { last = written })
+		if err != nil {
+			t.Fatalf("Download failed: %v", err)
+		}
+		if last != int64(len(payload)) {
+			t.Errorf("last progress = %d, want %d", last, len(payload))
+		}
+		data, err := os.ReadFile(dest)
+		if err != nil || string(data) != payload {
+			t.Errorf("downloaded file mismatch: %v", err)
+		}
+	})
+
+	t.Run("Bad status leaves no file", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "update.exe")
+		if err := Download(context.Background(), srv.URL+"/missing", dest, nil); err == nil {
+			t.Fatal("expected error")
+		}
+		if _, err := os.Stat(dest); !os.IsNotExist(err) {
+			t.Errorf("partial file left behind: %v", err)
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		dest := filepath.Join(t.TempDir(), "update.exe")
+		if err := Download(ctx, srv.URL+"/app.exe", dest, nil); err == nil {
+			t.Fatal("expected error")
+		}
+		if _, err := os.Stat(dest); !os.IsNotExist(err) {
+			t.Errorf("partial file left behind: %v", err)
+		}
+	})
+}
+
+func TestSelectAsset(t *testing.T) {
+	multiArch := []Asset{
+		{Name: "checksums.txt", BrowserDownloadURL: "sums"},
+		{Name: "VniConverter_windows_amd64.exe", BrowserDownloadURL: "amd64"},
+		{Name: "VniConverter_windows_arm64.exe", BrowserDownloadURL: "arm64"},
+	}
+	legacyOnly := []Asset{{Name: "VniConverter.exe", BrowserDownloadURL: "legacy"}}
+	aliases := []Asset{{Name: "VniConverter-windows-x64.exe", BrowserDownloadURL: "x64"}}
+
+	tests := []struct {
+		name    string
+		assets  []Asset
+		goarch  string
+		wantURL string
+		wantErr bool
+	}{
+		{name: "amd64 picks amd64", assets: multiArch, goarch: "amd64", wantURL: "amd64"},
+		{name: "arm64 picks arm64", assets: multiArch, goarch: "arm64", wantURL: "arm64"},
+		{name: "Legacy asset for amd64", assets: legacyOnly, goarch: "amd64", wantURL: "legacy"},
+		{name: "Legacy asset refused for arm64", assets: legacyOnly, goarch: "arm64", wantErr: true},
+		{name: "Architecture alias", assets: aliases, goarch: "amd64", wantURL: "x64"},
+		{name: "No assets", assets: nil, goarch: "amd64", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectAsset(tt.assets, "windows", tt.goarch)
+			if tt.wantErr {
+				if !errors.Is(err, ErrNoAsset) {
+					t.Fatalf("SelectAsset() error = %v, want ErrNoAsset", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectAsset() error = %v", err)
+			}
+			if got.BrowserDownloadURL != tt.wantURL {
+				t.Errorf("SelectAsset() = %s, want %s", got.BrowserDownloadURL, tt.wantURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"convert-vni-to-unicode/internal/engine"
+	"convert-vni-to-unicode/internal/notify"
+)
+
+// job is a single conversion tracked by the JobManager.
+// Why: Job IDs let several UI tabs run and monitor conversions in parallel.
+type job struct {
+	id     string
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	// run converts the job once the manager starts it
+	run func(*job) ProcessResult
+	// notifier receives the job's events; set by execute before the job starts converting
+	notifier notify.Notifier
+	// batch combines the progress of the files of a batch job (nil for a single file)
+	batch *engine.BatchProgress
+
+	// Latest progress, guarded by mu (see GetJobStatus)
+	mu     sync.Mutex
+	status JobStatus
+}
+
+// Job stages reported by GetJobStatus.
+const (
+	JobStageQueued     = "queued"
+	JobStageRunning    = "running"
+	JobStageCancelling = "cancelling"
+	JobStageDone       = "done"
+)
+
+// maxFinishedJobs is how many finished jobs GetJobStatus still knows about.
+const maxFinishedJobs = 20
+
+// errJobCancelled is the result message of a job cancelled while it was queued.
+const errJobCancelled = "Cancelled before it started"
+
+// JobStatus is a snapshot of a job's progress.
+// Why: A reloaded webview has lost the event stream and re-attaches from this snapshot.
+type JobStatus struct {
+	JobID string `json:"jobId"`
+	Stage string `json:"stage"`
+	ProgressUpdate
+	SheetName string `json:"sheetName"`
+	Processed int    `json:"processed"`
+	Total     int    `json:"total"`
+	// FilesDone and FilesTotal count the files of a batch (both 0 for a single file)
+	FilesDone  int `json:"filesDone"`
+	FilesTotal int `json:"filesTotal"`
+	// Result is set once Stage is JobStageDone
+	Result *ProcessResult `json:"result,omitempty"`
+	// Reverted is set once RevertConversion undid every file of the job
+	Reverted bool `json:"reverted,omitempty"`
+
+	// converted lists the files the job wrote, for RevertConversion
+	converted []convertedFile
+}
+
+// updateStatus applies fn to the job's status under its lock.
+func (j *job) updateStatus(fn func(*JobStatus)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	fn(&j.status)
+}
+
+// snapshot returns a copy of the job's status.
+func (j *job) snapshot() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// ProgressUpdate is the payload of the "progress" event.
+type ProgressUpdate struct {
+	Percent    float64 `json:"percent"`    // 0-100
+	ETASeconds float64 `json:"etaSeconds"` // 0 when unknown
+}
+
+// JobProgress is the payload of the "job:progress" event.
+type JobProgress struct {
+	JobID string `json:"jobId"`
+	ProgressUpdate
+	Processed int `json:"processed"`
+	Total     int `json:"total"`
+}
+
+// JobProgressText is the payload of the "progressText" event.
+type JobProgressText struct {
+	JobID string `json:"jobId"`
+	Text  string `json:"text"`
+}
+
+// JobFileProgress is the payload of the "job:file" event emitted for each file of a batch.
+type JobFileProgress struct {
+	JobID string `json:"jobId"`
+	engine.FileProgress
+}
+
+// JobErrors is the payload of the "errors" event, emitted after a file whose cells, rows
+// or sheets failed to convert.
+type JobErrors struct {
+	JobID  string             `json:"jobId"`
+	File   string             `json:"file"`
+	Errors []engine.CellError `json:"errors"`
+}
+
+// JobDone is the payload of the "job:done" event.
+type JobDone struct {
+	JobID  string        `json:"jobId"`
+	Result ProcessResult `json:"result"`
+}
+
+// JobEvent is the payload of the "job:queued" and "job:started" events.
+type JobEvent struct {
+	JobID string `json:"jobId"`
+	Stage string `json:"stage"`
+}
+
+// JobManager queues, runs and tracks the conversions of the GUI: up to a limit run at once,
+// later ones wait in order, and each can be queried and cancelled by its ID. It emits
+// "job:queued", "job:started" and "job:done" for every job.
+// Why: Conversions started from several tabs, links and quick actions all ran at once,
+// competing for CPU and the network share; a queue keeps each of them fast.
+type JobManager struct {
+	// limit returns how many jobs may run at once; it is read on every dispatch, so a
+	// changed setting applies to the next job
+	limit func() int
+	emit  func(name string, data any)
+
+	mu      sync.Mutex
+	nextID  int
+	jobs    map[string]*job // queued and running jobs
+	queue   []*job          // queued jobs, oldest first
+	running int
+	// Final status of the last maxFinishedJobs jobs, oldest first in finishedOrder
+	finished      map[string]JobStatus
+	finishedOrder []string
+
+	// revertMu serializes Revert, so a file is never undone twice
+	revertMu sync.Mutex
+}
+
+// newJobManager returns a manager running up to limit() jobs at once and sending its
+// events through emit.
+func newJobManager(limit func() int, emit func(name string, data any)) *JobManager {
+	return &JobManager{
+		limit:    limit,
+		emit:     emit,
+		jobs:     make(map[string]*job),
+		finished: make(map[string]JobStatus),
+	}
+}
+
+// Submit queues a job running run and returns it; cancelling parent cancels the job.
+func (m *JobManager) Submit(parent context.Context, run func(*job) ProcessResult) *job {
+	ctx, cancel := context.WithCancel(parent)
+
+	m.mu.Lock()
+	m.nextID++
+	j := &job{
+		id:     fmt.Sprintf("job-%d", m.nextID),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		run:    run,
+	}
+	j.status = JobStatus{JobID: j.id, Stage: JobStageQueued}
+	m.jobs[j.id] = j
+	m.queue = append(m.queue, j)
+	m.mu.Unlock()
+
+	m.emit("job:queued", JobEvent{JobID: j.id, Stage: JobStageQueued})
+	m.dispatch()
+	return j
+}
+
+// dispatch starts queued jobs, oldest first, while fewer than the limit run.
+func (m *JobManager) dispatch() {
+	limit := max(m.limit(), 1)
+	var start []*job
+	m.mu.Lock()
+	for len(m.queue) > 0 && m.running < limit {
+		start = append(start, m.queue[0])
+		m.queue = m.queue[1:]
+		m.running++
+	}
+	m.mu.Unlock()
+
+	for _, j := range start {
+		j.updateStatus(func(s *JobStatus) {
+			// A job cancelled as it left the queue stays "cancelling"
+			if s.Stage == JobStageQueued {
+				s.Stage = JobStageRunning
+			}
+		})
+		m.emit("job:started", JobEvent{JobID: j.id, Stage: JobStageRunning})
+		go func() {
+			res := j.run(j)
+			m.mu.Lock()
+			m.running--
+			m.mu.Unlock()
+			m.finish(j, res)
+			m.dispatch()
+		}()
+	}
+}
+
+// finish releases the job's context, keeps its final status for Status, emits "job:done"
+// and signals waiters that it has stopped.
+func (m *JobManager) finish(j *job, res ProcessResult) {
+	j.cancel()
+	j.updateStatus(func(s *JobStatus) {
+		s.Stage = JobStageDone
+		s.Result = &res
+	})
+
+	m.mu.Lock()
+	delete(m.jobs, j.id)
+	m.finished[j.id] = j.snapshot()
+	m.finishedOrder = append(m.finishedOrder, j.id)
+	if len(m.finishedOrder) > maxFinishedJobs {
+		delete(m.finished, m.finishedOrder[0])
+		m.finishedOrder = m.finishedOrder[1:]
+	}
+	m.mu.Unlock()
+
+	m.emit("job:done", JobDone{JobID: j.id, Result: res})
+	close(j.done)
+}
+
+// Cancel cancels a queued or running job. Returns false if the job is not active.
+func (m *JobManager) Cancel(id string) bool {
+	m.mu.Lock()
+	j, ok := m.jobs[id]
+	queued := false
+	if ok {
+		for i, q := range m.queue {
+			if q == j {
+				m.queue = append(m.queue[:i], m.queue[i+1:]...)
+				queued = true
+				break
+			}
+		}
+	}
+	m.mu.Unlock()
+	if !ok {
+		return false
+	}
+	if queued {
+		m.finish(j, ProcessResult{Success: false, Message: errJobCancelled})
+		return true
+	}
+	j.updateStatus(func(s *JobStatus) { s.Stage = JobStageCancelling })
+	j.cancel()
+	return true
+}
+
+// Status returns the status of an active job or of one of the recently finished jobs.
+func (m *JobManager) Status(id string) (JobStatus, bool) {
+	m.mu.Lock()
+	j, active := m.jobs[id]
+	finished, ok := m.finished[id]
+	m.mu.Unlock()
+	if active {
+		return j.snapshot(), true
+	}
+	return finished, ok
+}
+
+// List returns the status of the active and recently finished jobs, in start order.
+func (m *JobManager) List() []JobStatus {
+	m.mu.Lock()
+	out := make([]JobStatus, 0, len(m.jobs)+len(m.finished))
+	for _, id := range m.finishedOrder {
+		out = append(out, m.finished[id])
+	}
+	active := make([]*job, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		active = append(active, j)
+	}
+	m.mu.Unlock()
+
+	for _, j := range active {
+		out = append(out, j.snapshot())
+	}
+	sort.SliceStable(out, func(i, k int) bool { return jobNumber(out[i].JobID) < jobNumber(out[k].JobID) })
+	return out
+}
+
+// Active returns the queued and running jobs.
+func (m *JobManager) Active() []*job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]*job, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		out = append(out, j)
+	}
+	return out
+}
+
+// jobNumber returns the sequence number of a job ID ("job-12" -> 12).
+func jobNumber(id string) int {
+	n, _ := strconv.Atoi(strings.TrimPrefix(id, "job-"))
+	return n
+}
+
+// lastOutputPath returns the output of a single-file result, or the last output of a batch.
+func lastOutputPath(res ProcessResult) string {
+	if res.OutputPath != "" {
+		return res.OutputPath
+	}
+	for i := len(res.Files) - 1; i >= 0; i-- {
+		if res.Files[i].OutputPath != "" {
+			return res.Files[i].OutputPath
+		}
+	}
+	return ""
+}
+
+// StartJob queues a conversion and returns its job ID. It starts once fewer than the
+// MaxJobs setting run; progress is emitted as "job:progress" and the result as "job:done".
+func (a *App) StartJob(cfg Config) (string, error) {
+	if cfg.InputPath == "" && len(cfg.InputPaths) == 0 {
+		return "", fmt.Errorf("please select an input file")
+	}
+	return a.submitJob(cfg).id, nil
+}
+
+// submitJob queues cfg as a job of the JobManager.
+func (a *App) submitJob(cfg Config) *job {
+	a.rememberJob(cfg)
+	return a.jobs.Submit(a.ctx, func(j *job) ProcessResult {
+		res := a.execute(j, cfg)
+		if output := lastOutputPath(res); output != "" {
+			a.mu.Lock()
+			a.lastOutput = output
+			a.mu.Unlock()
+		}
+		return res
+	})
+}
+
+// CancelJob cancels a queued or running job. Returns false if the job is not active.
+func (a *App) CancelJob(jobID string) bool {
+	return a.jobs.Cancel(jobID)
+}
+
+// GetJobStatus returns the progress of a queued or running job, or the result of one of
+// the recently finished jobs.
+// Why: After a webview reload the frontend re-attaches to running jobs (see ListJobs).
+func (a *App) GetJobStatus(jobID string) (JobStatus, error) {
+	if status, ok := a.jobs.Status(jobID); ok {
+		return status, nil
+	}
+	return JobStatus{}, fmt.Errorf("unknown job %q", jobID)
+}
+
+// GetJobs returns the status of the queued, running and recently finished jobs, in the
+// order they were started.
+func (a *App) GetJobs() []JobStatus {
+	return a.jobs.List()
+}
+
+// ListJobs returns the IDs of all queued and running jobs, in the order they were started.
+func (a *App) ListJobs() []string {
+	active := a.jobs.Active()
+	ids := make([]string, 0, len(active))
+	for _, j := range active {
+		ids = append(ids, j.id)
+	}
+	sort.Slice(ids, func(i, k int) bool { return jobNumber(ids[i]) < jobNumber(ids[k]) })
+	return ids
+}
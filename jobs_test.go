@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// jobEvents records the events a JobManager emits.
+type jobEvents struct {
+	mu     sync.Mutex
+	events []string // "name job-N"
+	// onEmit, if set, is called after an event is recorded
+	onEmit func(name string, data any)
+}
+
+func (e *jobEvents) emit(name string, data any) {
+	id := ""
+	switch d := data.(type) {
+	case JobEvent:
+		id = d.JobID
+	case JobDone:
+		id = d.JobID
+	}
+	e.mu.Lock()
+	e.events = append(e.events, name+" "+id)
+	e.mu.Unlock()
+	if e.onEmit != nil {
+		e.onEmit(name, data)
+	}
+}
+
+// named returns the job IDs of the recorded events called name, in order.
+func (e *jobEvents) named(name string) []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var ids []string
+	for _, ev := range e.events {
+		if id, ok := strings.CutPrefix(ev, name+" "); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// blockingRun returns a job body that waits for release or for the job to be cancelled,
+// and reports the result it finished with.
+func blockingRun(release <-chan struct{}) func(*job) ProcessResult {
+	return func(j *job) ProcessResult {
+		select {
+		case <-release:
+			return ProcessResult{Success: true, Message: "converted " + j.id}
+		case <-j.ctx.Done():
+			return ProcessResult{Success: false, Message: "cancelled " + j.id}
+		}
+	}
+}
+
+// waitJob waits for j to finish, failing the test after a while.
+func waitJob(t *testing.T, j *job) {
+	t.Helper()
+	select {
+	case <-j.done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s did not finish", j.id)
+	}
+}
+
+func TestJobManager_Queue(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int
+		jobs        int
+		wantRunning int
+	}{
+		{name: "One at a time", limit: 1, jobs: 3, wantRunning: 1},
+		{name: "Two at a time", limit: 2, jobs: 3, wantRunning: 2},
+		{name: "Limit below one runs one", limit: 0, jobs: 2, wantRunning: 1},
+		{name: "Limit above the jobs", limit: 5, jobs: 3, wantRunning: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &jobEvents{}
+			m := newJobManager(func() int { return tt.limit }, events.emit)
+			release := make(chan struct{})
+			var submitted []*job
+			for i := 0; i < tt.jobs; i++ {
+				submitted = append(submitted, m.Submit(context.Background(), blockingRun(release)))
+			}
+
+			for i, j := range submitted {
+				want := JobStageQueued
+				if i < tt.wantRunning {
+					want = JobStageRunning
+				}
+				if status, ok := m.Status(j.id); !ok || status.Stage != want {
+					t.Errorf("%s stage = %q, want %q", j.id, status.Stage, want)
+				}
+			}
+			if got := len(m.Active()); got != tt.jobs {
+				t.Errorf("Active() = %d jobs, want %d", got, tt.jobs)
+			}
+
+			close(release)
+			for _, j := range submitted {
+				waitJob(t, j)
+			}
+			// Queued jobs start in the order they were submitted
+			started := events.named("job:started")
+			for i, j := range submitted {
+				if i >= len(started) || started[i] != j.id {
+					t.Fatalf("started = %v, want the submission order", started)
+				}
+			}
+			for _, status := range m.List() {
+				if status.Stage != JobStageDone || status.Result == nil || !status.Result.Success {
+					t.Errorf("finished status = %+v", status)
+				}
+			}
+		})
+	}
+}
+
+func TestJobManager_Cancel(t *testing.T) {
+	tests := []struct {
+		name string
+		// cancel picks the job to cancel among a running and a queued job
+		cancel      func(running, queued *job) string
+		want        bool
+		wantMessage string // result of the cancelled job
+		wantStarted bool
+	}{
+		{
+			name:        "Queued job",
+			cancel:      func(_, queued *job) string { return queued.id },
+			want:        true,
+			wantMessage: errJobCancelled,
+		},
+		{
+			name:        "Running job",
+			cancel:      func(running, _ *job) string { return running.id },
+			want:        true,
+			wantMessage: "cancelled job-1",
+			wantStarted: true,
+		},
+		{
+			name:   "Unknown job",
+			cancel: func(_, _ *job) string { return "job-99" },
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &jobEvents{}
+			m := newJobManager(func() int { return 1 }, events.emit)
+			release := make(chan struct{})
+			running := m.Submit(context.Background(), blockingRun(release))
+			queued := m.Submit(context.Background(), blockingRun(release))
+
+			id := tt.cancel(running, queued)
+			if got := m.Cancel(id); got != tt.want {
+				t.Fatalf("Cancel(%q) = %v, want %v", id, got, tt.want)
+			}
+			if tt.want {
+				cancelled := running
+				if id == queued.id {
+					cancelled = queued
+				}
+				waitJob(t, cancelled)
+				status, _ := m.Status(id)
+				if status.Stage != JobStageDone || status.Result == nil || status.Result.Message != tt.wantMessage {
+					t.Errorf("status after Cancel = %+v, want done with %q", status, tt.wantMessage)
+				}
+				if m.Cancel(id) {
+					t.Error("Cancel() of a finished job = true, want false")
+				}
+			}
+
+			close(release)
+			waitJob(t, running)
+			waitJob(t, queued)
+			if tt.want && !tt.wantStarted && slices.Contains(events.named("job:started"), id) {
+				t.Errorf("%s started after it was cancelled in the queue", id)
+			}
+			if got := len(events.named("job:done")); got != 2 {
+				t.Errorf("job:done emitted %d times, want 2", got)
+			}
+		})
+	}
+}
+
+func TestJobManager_CancelWhileDispatching(t *testing.T) {
+	// The job is cancelled after it left the queue but before its body runs, as when
+	// CancelJob arrives while the dispatcher starts it
+	events := &jobEvents{}
+	m := newJobManager(func() int { return 1 }, events.emit)
+	events.onEmit = func(name string, data any) {
+		if name == "job:started" {
+			m.Cancel(data.(JobEvent).JobID)
+		}
+	}
+
+	var stageInRun string
+	j := m.Submit(context.Background(), func(j *job) ProcessResult {
+		stageInRun = j.snapshot().Stage
+		<-j.ctx.Done()
+		return ProcessResult{Message: "cancelled"}
+	})
+	waitJob(t, j)
+
+	if stageInRun != JobStageCancelling {
+		t.Errorf("stage seen by the job = %q, want %q", stageInRun, JobStageCancelling)
+	}
+	status, ok := m.Status(j.id)
+	if !ok || status.Stage != JobStageDone || status.Result.Message != "cancelled" {
+		t.Errorf("Status() = %+v, %v; want done with the job's own result", status, ok)
+	}
+	if got := len(events.named("job:done")); got != 1 {
+		t.Errorf("job:done emitted %d times, want 1", got)
+	}
+}
+
+func TestJobManager_FinishedEviction(t *testing.T) {
+	tests := []struct {
+		name string
+		jobs int
+	}{
+		{name: "Below the cap", jobs: maxFinishedJobs - 1},
+		{name: "At the cap", jobs: maxFinishedJobs},
+		{name: "Past the cap", jobs: maxFinishedJobs + 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newJobManager(func() int { return 1 }, func(string, any) {})
+			var ids []string
+			for i := 0; i < tt.jobs; i++ {
+				j := m.Submit(context.Background(), func(*job) ProcessResult { return ProcessResult{Success: true} })
+				waitJob(t, j)
+				ids = append(ids, j.id)
+			}
+
+			evicted := max(tt.jobs-maxFinishedJobs, 0)
+			for i, id := range ids {
+				if _, ok := m.Status(id); ok != (i >= evicted) {
+					t.Errorf("Status(%q) known = %v, want %v", id, ok, i >= evicted)
+				}
+			}
+			list := m.List()
+			if len(list) != tt.jobs-evicted {
+				t.Fatalf("List() = %d jobs, want %d", len(list), tt.jobs-evicted)
+			}
+			if list[0].JobID != ids[evicted] || list[len(list)-1].JobID != ids[len(ids)-1] {
+				t.Errorf("List() runs from %s to %s, want %s to %s",
+					list[0].JobID, list[len(list)-1].JobID, ids[evicted], ids[len(ids)-1])
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"convert-vni-to-unicode/internal/engine"
+	"convert-vni-to-unicode/internal/pdf"
+)
+
+// legacyWorkbookPattern matches the old spreadsheet files offered by the file dialogs.
+const legacyWorkbookPattern = "*.xls;*.wk1;*.wk3;*.wk4;*.wks;*.123"
+
+// isLegacyWorkbook reports whether path has an extension of legacyWorkbookPattern.
+func isLegacyWorkbook(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext != "" && strings.Contains(legacyWorkbookPattern+";", "*"+ext+";")
+}
+
+// importLegacyWorkbook converts input to .xlsx with LibreOffice when it is an old
+// spreadsheet format (see engine.DetectLegacyFormat) and enabled is set. It returns the
+// workbook to convert (input itself when nothing was imported), the imported format and a
+// cleanup deleting the imported copy. Without enabled, the conversion of an old format
+// fails with an engine.LegacyFormatError telling the user what to do.
+func importLegacyWorkbook(ctx context.Context, input string, enabled bool, officePath string,
+	backupMode engine.BackupMode) (string, engine.LegacyFormat, func(), error) {
+	noop := func() {}
+	if !enabled || engine.IsTextFile(input) || engine.IsDocument(input) || engine.IsPresentation(input) ||
+		engine.IsOpenDocument(input) {
+		return input, "", noop, nil
+	}
+	format := engine.DetectLegacyFormat(input)
+	if format == "" {
+		return input, "", noop, nil
+	}
+	if backupMode != engine.BackupNone {
+		return "", format, noop, fmt.Errorf(
+			"%s files cannot be overwritten in place; convert them to a new .xlsx instead", format)
+	}
+	exporter, err := pdf.NewExporter(officePath)
+	if err != nil {
+		return "", format, noop, fmt.Errorf("cannot import the %s file: %w", format, err)
+	}
+	dir, err := os.MkdirTemp("", "vniconverter-import-")
+	if err != nil {
+		return "", format, noop, fmt.Errorf("failed to create import folder: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	imported, err := exporter.ImportXLSX(ctx, input, dir)
+	if err != nil {
+		cleanup()
+		return "", format, noop, fmt.Errorf("failed to import the %s file: %w", format, err)
+	}
+	return imported, format, cleanup, nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"convert-vni-to-unicode/internal/settings"
+
+	"github.com/wailsapp/wails/v2/pkg/options"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// linkScheme is the URI scheme of conversion links:
+// vniconv://convert?path=C:\Docs\report.xlsx&profile=archive
+const linkScheme = "vniconv"
+
+// singleInstanceID identifies the GUI for the single instance lock, so a link or file opened
+// while the app runs is handed to the running window instead of starting a second one.
+const singleInstanceID = "convert-vni-to-unicode.vniconverter"
+
+// LinkEvent is emitted as "link" when a conversion link was opened.
+type LinkEvent struct {
+	Path  string `json:"path"`
+	JobID string `json:"jobId,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// convertLink is a parsed conversion link.
+type convertLink struct {
+	path    string
+	profile string
+}
+
+// linkArg returns the first argument that is a conversion link, or "".
+func linkArg(args []string) string {
+	for _, arg := range args {
+		if strings.HasPrefix(strings.ToLower(arg), linkScheme+":") {
+			return arg
+		}
+	}
+	return ""
+}
+
+// errLinkDeclined is returned when the user declines the conversion a link asks for.
+var errLinkDeclined = errors.New("the conversion was not confirmed")
+
+// parseConvertLink parses a vniconv://convert link. The path must be an absolute path to
+// a file the converter reads, on this computer unless allowRemote is set; the profile is
+// optional.
+// Why: Any web page can open a link, and merely opening a network path makes Windows
+// send the user's credentials to that server, so remote paths are refused before the
+// file system is touched.
+func parseConvertLink(raw string, allowRemote bool) (convertLink, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return convertLink{}, fmt.Errorf("invalid link: %w", err)
+	}
+	if !strings.EqualFold(u.Scheme, linkScheme) || !strings.EqualFold(u.Host, "convert") {
+		return convertLink{}, fmt.Errorf("unsupported link %q: want %s://convert?path=...", raw, linkScheme)
+	}
+	query := u.Query()
+	link := convertLink{path: query.Get("path"), profile: query.Get("profile")}
+	if link.path == "" {
+		return convertLink{}, errors.New("the link names no file: add path=...")
+	}
+	if !filepath.IsAbs(link.path) {
+		return convertLink{}, fmt.Errorf("the link path %q is not absolute", link.path)
+	}
+	if !allowRemote && isRemotePath(link.path) {
+		return convertLink{}, fmt.Errorf("%s is not on this computer: "+
+			"set allowRemoteLinkPaths in settings.json to convert network files from links", link.path)
+	}
+	if !readsFile(link.path) {
+		return convertLink{}, fmt.Errorf("%s is not a file the converter reads", filepath.Base(link.path))
+	}
+	return link, nil
+}
+
+// isRemotePath reports whether path is a UNC path (\\server\share), a device path
+// (\\?\..., which may name a share) or on a network drive.
+func isRemotePath(path string) bool {
+	isSeparator := func(c byte) bool { return c == '\\' || c == '/' }
+	if len(path) >= 2 && isSeparator(path[0]) && isSeparator(path[1]) {
+		return true
+	}
+	return isNetworkDrive(path)
+}
+
+// linkConfig returns the job a link starts: the job template named by the link (the
+// defaults when it names none) applied to its file.
+func linkConfig(link convertLink, templates map[string]settings.JobTemplate) (Config, error) {
+	t, ok := templates[link.profile]
+	if !ok && link.profile != "" {
+		return Config{}, fmt.Errorf("unknown profile %q: add it to jobTemplates in settings.json", link.profile)
+	}
+	return Config{
+		InputPath:    link.path,
+		SheetName:    t.SheetName,
+		Encoding:     t.Encoding,
+		ChangeReport: t.ChangeReport,
+		InPlace:      t.InPlace,
+		SpellCheck:   t.SpellCheck,
+		ExportPDF:    t.ExportPDF,
+	}, nil
+}
+
+// linkPrompt returns the question confirming the conversion a link asks for, naming the
+// file, the profile and what the profile does besides writing a new output.
+func linkPrompt(link convertLink, cfg Config) string {
+	profile := link.profile
+	if profile == "" {
+		profile = "default settings"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "A link asks to convert\n\n%s\n\nwith the profile: %s.", link.path, profile)
+	if cfg.InPlace != "" {
+		b.WriteString("\n\nThe file will be overwritten; the original is kept as a backup.")
+	}
+	if cfg.ExportPDF {
+		b.WriteString("\n\nA PDF copy will be saved with LibreOffice.")
+	}
+	b.WriteString("\n\nConvert it?")
+	return b.String()
+}
+
+// openLink starts the conversion a link describes once the user confirms it, brings the
+// window forward and emits a "link" event; the result follows as "job:done".
+// Why: The document management system triggers conversions with a link next to each
+// document, without users picking the file and the settings again. Any web page can
+// open a link too, so nothing runs without the user's consent.
+func (a *App) openLink(raw string) {
+	runtime.WindowUnminimise(a.ctx)
+	runtime.WindowShow(a.ctx)
+	event := LinkEvent{}
+	err := func() error {
+		prefs := a.loadSettings()
+		link, err := parseConvertLink(raw, prefs.AllowRemoteLinkPaths)
+		if err != nil {
+			return err
+		}
+		event.Path = link.path
+		cfg, err := linkConfig(link, prefs.JobTemplates)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(link.path); err != nil {
+			return fmt.Errorf("cannot open %s: %w", link.path, err)
+		}
+		choice, err := runtime.MessageDialog(a.ctx, runtime.MessageDialogOptions{
+			Type:          runtime.QuestionDialog,
+			Title:         "Convert from link",
+			Message:       linkPrompt(link, cfg),
+			Buttons:       []string{"Yes", "No"},
+			DefaultButton: "No",
+		})
+		if err != nil || choice != "Yes" {
+			return errLinkDeclined
+		}
+		event.JobID, err = a.StartJob(cfg)
+		return err
+	}()
+	switch {
+	case errors.Is(err, errLinkDeclined):
+		slog.Info("conversion link declined", "path", event.Path)
+		event.Error = err.Error()
+	case err != nil:
+		slog.Warn("conversion link rejected", "link", raw, "error", err)
+		event.Error = err.Error()
+	default:
+		slog.Info("conversion link opened", "path", event.Path, "job", event.JobID)
+	}
+	runtime.EventsEmit(a.ctx, "link", event)
+}
+
+// onSecondInstanceLaunch handles a launch while the app already runs: a link is converted
+// and a file is opened by this instance; any other launch just brings the window forward.
+func (a *App) onSecondInstanceLaunch(data options.SecondInstanceData) {
+	if link := linkArg(data.Args); link != "" {
+		a.openLink(link)
+		return
+	}
+	if path := fileArg(data.Args, data.WorkingDirectory); path != "" {
+		a.openFile(path)
+		return
+	}
+	runtime.WindowUnminimise(a.ctx)
+	runtime.WindowShow(a.ctx)
+}
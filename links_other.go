@@ -0,0 +1,21 @@
+//go:build !windows
+
+package main
+
+// registerLinkScheme does nothing outside Windows: macOS reads the URL schemes of an app
+// from its Info.plist, and Linux desktops from the x-scheme-handler of its .desktop file.
+func registerLinkScheme() error {
+	return nil
+}
+
+// isNetworkDrive reports false outside Windows, where shares are mounted into the file
+// system and a link to them is no different from a local path.
+func isNetworkDrive(string) bool {
+	return false
+}
+
+// registerOpenWith does nothing outside Windows: macOS reads the document types of an app
+// from its Info.plist, and Linux desktops from the MimeType of its .desktop file.
+func registerOpenWith() error {
+	return nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"convert-vni-to-unicode/internal/settings"
+)
+
+func TestParseConvertLink(t *testing.T) {
+	local := filepath.Join(t.TempDir(), "report.xlsx")
+	link := func(path, profile string) string {
+		q := url.Values{"path": {path}}
+		if profile != "" {
+			q.Set("profile", profile)
+		}
+		return "vniconv://convert?" + q.Encode()
+	}
+
+	tests := []struct {
+		name        string
+		raw         string
+		allowRemote bool
+		want        convertLink
+		wantErr     string
+	}{
+		{name: "Local file", raw: link(local, ""), want: convertLink{path: local}},
+		{name: "Profile", raw: link(local, "archive"), want: convertLink{path: local, profile: "archive"}},
+		{name: "Scheme is case-insensitive", raw: strings.Replace(link(local, ""), "vniconv://convert", "VNICONV://Convert", 1),
+			want: convertLink{path: local}},
+		{name: "Other host", raw: "vniconv://open?path=" + url.QueryEscape(local), wantErr: "unsupported link"},
+		{name: "Other scheme", raw: "https://convert?path=" + url.QueryEscape(local), wantErr: "unsupported link"},
+		{name: "No path", raw: "vniconv://convert?profile=archive", wantErr: "names no file"},
+		{name: "Relative path", raw: link("report.xlsx", ""), wantErr: "not absolute"},
+		{name: "Unsupported file", raw: link(filepath.Join(filepath.Dir(local), "setup.exe"), ""), wantErr: "not a file"},
+		{name: "UNC path", raw: link("//server/share/report.xlsx", ""), wantErr: "not on this computer"},
+		{name: "UNC path allowed", raw: link("//server/share/report.xlsx", ""), allowRemote: true,
+			want: convertLink{path: "//server/share/report.xlsx"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseConvertLink(tt.raw, tt.allowRemote)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseConvertLink() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseConvertLink() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parseConvertLink() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRemotePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: `\\server\share\report.xlsx`, want: true},
+		{path: "//server/share/report.xlsx", want: true},
+		{path: `\\?\UNC\server\share\report.xlsx`, want: true},
+		{path: `\\?\C:\Docs\report.xlsx`, want: true},
+		{path: "/home/user/report.xlsx", want: false},
+		{path: `\Docs\report.xlsx`, want: false},
+	}
+	for _, tt := range tests {
+		if got := isRemotePath(tt.path); got != tt.want {
+			t.Errorf("isRemotePath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLinkConfig(t *testing.T) {
+	templates := map[string]settings.JobTemplate{
+		"archive": {ChangeReport: "csv", ExportPDF: true},
+		"inplace": {InPlace: "bak", Encoding: "VNI", SheetName: "Data", SpellCheck: true},
+	}
+	tests := []struct {
+		name    string
+		profile string
+		want    Config
+		wantErr bool
+	}{
+		{name: "Defaults", want: Config{InputPath: "report.xlsx"}},
+		{name: "Archive", profile: "archive",
+			want: Config{InputPath: "report.xlsx", ChangeReport: "csv", ExportPDF: true}},
+		{name: "In place", profile: "inplace",
+			want: Config{InputPath: "report.xlsx", InPlace: "bak", Encoding: "VNI", SheetName: "Data", SpellCheck: true}},
+		{name: "Unknown profile", profile: "missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := linkConfig(convertLink{path: "report.xlsx", profile: tt.profile}, templates)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("linkConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !linkConfigEqual(got, tt.want) {
+				t.Errorf("linkConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkPrompt(t *testing.T) {
+	link := convertLink{path: "/docs/report.xlsx", profile: "archive"}
+	tests := []struct {
+		name    string
+		link    convertLink
+		cfg     Config
+		want    []string
+		notWant []string
+	}{
+		{name: "Default profile", link: convertLink{path: link.path}, want: []string{link.path, "default settings"},
+			notWant: []string{"overwritten", "PDF"}},
+		{name: "Named profile", link: link, want: []string{link.path, "archive"}},
+		{name: "In place", link: link, cfg: Config{InPlace: "bak"}, want: []string{"overwritten"}},
+		{name: "PDF", link: link, cfg: Config{ExportPDF: true}, want: []string{"PDF"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := linkPrompt(tt.link, tt.cfg)
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("linkPrompt() = %q, want it to contain %q", got, s)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(got, s) {
+					t.Errorf("linkPrompt() = %q, want no %q", got, s)
+				}
+			}
+		})
+	}
+}
+
+// linkConfigEqual compares the fields linkConfig sets.
+func linkConfigEqual(a, b Config) bool {
+	return a.InputPath == b.InputPath && a.SheetName == b.SheetName && a.Encoding == b.Encoding &&
+		a.ChangeReport == b.ChangeReport && a.InPlace == b.InPlace && a.SpellCheck == b.SpellCheck &&
+		a.ExportPDF == b.ExportPDF
+}
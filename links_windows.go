@@ -0,0 +1,92 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// registerLinkScheme registers this executable as the handler of vniconv:// links for the
+// current user. It runs on every GUI start, so the registration follows the executable
+// when it is moved or updated.
+func registerLinkScheme() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the executable: %w", err)
+	}
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, `Software\Classes\`+linkScheme,
+		registry.SET_VALUE|registry.CREATE_SUB_KEY)
+	if err != nil {
+		return fmt.Errorf("failed to register %s links: %w", linkScheme, err)
+	}
+	defer func() { _ = key.Close() }()
+	if err := key.SetStringValue("", "URL:VNI Converter link"); err != nil {
+		return fmt.Errorf("failed to register %s links: %w", linkScheme, err)
+	}
+	if err := key.SetStringValue("URL Protocol", ""); err != nil {
+		return fmt.Errorf("failed to register %s links: %w", linkScheme, err)
+	}
+
+	command, _, err := registry.CreateKey(key, `shell\open\command`, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("failed to register %s links: %w", linkScheme, err)
+	}
+	defer func() { _ = command.Close() }()
+	if err := command.SetStringValue("", fmt.Sprintf(`"%s" "%%1"`, exe)); err != nil {
+		return fmt.Errorf("failed to register %s links: %w", linkScheme, err)
+	}
+	return nil
+}
+
+// isNetworkDrive reports whether path is on a drive letter mapped to a network share.
+func isNetworkDrive(path string) bool {
+	volume := filepath.VolumeName(path)
+	if len(volume) != 2 || volume[1] != ':' {
+		return false
+	}
+	root, err := windows.UTF16PtrFromString(volume + `\`)
+	if err != nil {
+		return false
+	}
+	return windows.GetDriveType(root) == windows.DRIVE_REMOTE
+}
+
+// openWithProgID is the file type class the "Open with" entries of openWithExtensions use.
+const openWithProgID = "VniConverter.File"
+
+// registerOpenWith adds the app to the "Open with" menu of openWithExtensions for the current
+// user, leaving their default program alone. Like registerLinkScheme, it runs on every GUI start.
+func registerOpenWith() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the executable: %w", err)
+	}
+	command, _, err := registry.CreateKey(registry.CURRENT_USER,
+		`Software\Classes\`+openWithProgID+`\shell\open\command`, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("failed to register %s: %w", openWithProgID, err)
+	}
+	defer func() { _ = command.Close() }()
+	if err := command.SetStringValue("", fmt.Sprintf(`"%s" "%%1"`, exe)); err != nil {
+		return fmt.Errorf("failed to register %s: %w", openWithProgID, err)
+	}
+
+	for _, ext := range openWithExtensions {
+		key, _, err := registry.CreateKey(registry.CURRENT_USER, `Software\Classes\`+ext+`\OpenWithProgids`,
+			registry.SET_VALUE)
+		if err != nil {
+			return fmt.Errorf("failed to register %s files: %w", ext, err)
+		}
+		err = key.SetStringValue(openWithProgID, "")
+		_ = key.Close()
+		if err != nil {
+			return fmt.Errorf("failed to register %s files: %w", ext, err)
+		}
+	}
+	return nil
+}
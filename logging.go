@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"convert-vni-to-unicode/internal/logging"
+	"convert-vni-to-unicode/internal/settings"
+)
+
+// logFolder returns the folder of the log files, next to settings.json.
+func logFolder() (string, error) {
+	path, err := settings.DefaultPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "logs"), nil
+}
+
+// setupLogging installs the logger configured in the settings as the slog default, so
+// the app, the updater and the engine all write to the rotating log file. console also
+// receives every entry (nil for the GUI, which has none). The returned Closer closes the
+// log file.
+// Why: A missing or unwritable log folder must never stop the app; it then logs to
+// console only.
+func setupLogging(prefs settings.Settings, console io.Writer) io.Closer {
+	level, levelErr := logging.ParseLevel(prefs.LogLevel)
+	opts := logging.Options{Level: level, JSON: prefs.LogJSON, Console: console}
+	dir, dirErr := logFolder()
+	if dirErr == nil {
+		opts.Dir = dir
+	}
+	logger, closer, err := logging.Setup(opts)
+	if err != nil {
+		opts.Dir = ""
+		logger, closer, _ = logging.Setup(opts)
+	}
+	slog.SetDefault(logger)
+
+	if levelErr != nil {
+		slog.Warn("using the info log level", "error", levelErr)
+	}
+	if dirErr != nil {
+		slog.Warn("log folder unavailable, not writing a log file", "error", dirErr)
+	} else if err != nil {
+		slog.Warn("failed to open the log file", "error", err)
+	}
+	return closer
+}
+
+// wailsLogger forwards the messages of the Wails runtime to slog.
+type wailsLogger struct {
+	l *slog.Logger
+}
+
+// levelTrace sits below slog.LevelDebug, so Wails trace messages are only written on request.
+const levelTrace = slog.LevelDebug - 4
+
+func (w wailsLogger) Print(message string)   { w.l.Info(message) }
+func (w wailsLogger) Trace(message string)   { w.l.Log(context.Background(), levelTrace, message) }
+func (w wailsLogger) Debug(message string)   { w.l.Debug(message) }
+func (w wailsLogger) Info(message string)    { w.l.Info(message) }
+func (w wailsLogger) Warning(message string) { w.l.Warn(message) }
+func (w wailsLogger) Error(message string)   { w.l.Error(message) }
+
+// Fatal logs message and exits, like the default Wails logger.
+func (w wailsLogger) Fatal(message string) {
+	w.l.Error(message)
+	os.Exit(1)
+}
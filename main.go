@@ -3,11 +3,17 @@ package main
 import (
 	"embed"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 
+	"convert-vni-to-unicode/internal/settings"
+
 	"github.com/wailsapp/wails/v2"
+	"github.com/wailsapp/wails/v2/pkg/logger"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
+	"github.com/wailsapp/wails/v2/pkg/options/mac"
 	"github.com/wailsapp/wails/v2/pkg/options/windows"
 )
 
@@ -18,35 +24,126 @@ var assets embed.FS
 // Why: It initializes the Wails application, configures the window properties,
 // and binds the backend logic (App) to the frontend.
 func main() {
+	// Load persisted preferences (logging, window state, theme)
+	store, prefs := loadSettings()
+
+	// Subcommands and flags run headless, without starting the GUI, and also log to the console
+	var run func(args []string, stdout, stderr io.Writer) int
+	if len(os.Args) > 1 {
+		run = subcommands[os.Args[1]]
+	}
+	if run != nil || isCLIInvocation(os.Args[1:]) {
+		_ = setupLogging(prefs, os.Stderr) // Closed by the process exit
+		if run != nil {
+			os.Exit(run(os.Args[2:], os.Stdout, os.Stderr))
+		}
+		os.Exit(runConvert(os.Args[1:], os.Stdout, os.Stderr))
+	}
+	logFile := setupLogging(prefs, nil)
+	defer func() { _ = logFile.Close() }()
+
 	// Create an instance of the app structure
-	app := NewApp()
+	app := NewApp(store)
+	app.startLink = linkArg(os.Args[1:])
+	if wd, err := os.Getwd(); err == nil {
+		app.startFile = fileArg(os.Args[1:], wd)
+	}
+
+	startState := options.Normal
+	if prefs.Window.Maximized {
+		startState = options.Maximised
+	}
 
 	// Create application with options
 	// Why: Defines the window dimensions, title, and theme to match the minimal aesthetic requested.
 	err := wails.Run(&options.App{
-		Title:         "VNI to Unicode Converter",
-		Width:         900,
-		Height:        835,
-		DisableResize: false, // Allow resizing for better UX on different screens
-		MinWidth:      700,
-		MinHeight:     600,
+		Title:            "VNI to Unicode Converter",
+		Width:            prefs.Window.Width,
+		Height:           prefs.Window.Height,
+		DisableResize:    false, // Allow resizing for better UX on different screens
+		MinWidth:         settings.MinWidth,
+		MinHeight:        settings.MinHeight,
+		WindowStartState: startState,
 		AssetServer: &assetserver.Options{
 			Assets: assets,
 		},
-		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1}, // Matches the dark theme background
+		BackgroundColour: backgroundColour(prefs.Theme),
 		OnStartup:        app.startup,
+		OnDomReady:       app.domReady,
+		OnBeforeClose:    app.beforeClose,
+		OnShutdown:       app.shutdown,
+		// The Wails runtime logs through slog too; slog decides what is written
+		Logger:             wailsLogger{l: slog.Default()},
+		LogLevel:           logger.TRACE,
+		LogLevelProduction: logger.TRACE,
 		Bind: []interface{}{
 			app,
 		},
+		// Links opened while the app runs are converted by the running window
+		SingleInstanceLock: &options.SingleInstanceLock{
+			UniqueId:               singleInstanceID,
+			OnSecondInstanceLaunch: app.onSecondInstanceLaunch,
+		},
+		// Files opened from Finder arrive here instead of in os.Args
+		Mac: &mac.Options{
+			OnFileOpen: app.openFile,
+		},
 		Windows: &windows.Options{
 			WebviewIsTransparent: false,
 			WindowIsTranslucent:  false,
 			DisableWindowIcon:    false,
+			Theme:                windowsTheme(prefs.Theme),
 		},
 	})
 
 	if err != nil {
+		slog.Error("application failed", "error", err)
 		_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
 }
+
+// subcommands maps the first argument to the headless subcommand it runs.
+var subcommands = map[string]func(args []string, stdout, stderr io.Writer) int{
+	"bench":             runBench,
+	"selfupdate":        runSelfUpdate,
+	"watch":             runWatch,
+	"service":           runService,
+	"serve":             runServe,
+	"dataset":           runDataset,
+	"compare-detectors": runCompareDetectors,
+	"repair-colors":     runRepairColors,
+	"golden":            runGolden,
+}
+
+// loadSettings opens the settings store, falling back to defaults on any error.
+// Why: A broken settings file must never prevent the app from starting.
+func loadSettings() (*settings.Store, settings.Settings) {
+	path, err := settings.DefaultPath()
+	if err != nil {
+		slog.Warn("settings unavailable, using defaults", "error", err)
+		return nil, settings.Default()
+	}
+	store := settings.NewStore(path)
+	prefs, err := store.Load()
+	if err != nil {
+		slog.Warn("failed to load settings, using defaults", "error", err)
+	}
+	return store, prefs
+}
+
+// backgroundColour matches the window background to the frontend theme to avoid a flash on startup.
+func backgroundColour(theme string) *options.RGBA {
+	if theme == settings.ThemeLight {
+		return &options.RGBA{R: 240, G: 244, B: 248, A: 1}
+	}
+	return &options.RGBA{R: 27, G: 38, B: 54, A: 1} // Matches the dark theme background
+}
+
+// windowsTheme maps the stored theme to the native title bar theme.
+func windowsTheme(theme string) windows.Theme {
+	if theme == settings.ThemeLight {
+		return windows.Light
+	}
+	return windows.Dark
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"convert-vni-to-unicode/internal/engine"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// openWithExtensions are the file types the app offers to open from the file manager's
+// "Open with" menu. It never takes over their default program.
+var openWithExtensions = []string{".xlsx", ".xlsm", ".docx", ".pptx", ".ods", ".xls"}
+
+// OpenFileEvent is emitted as "openFile" when the app was asked to open a file: the
+// frontend selects it, ready to convert.
+type OpenFileEvent struct {
+	Path  string `json:"path"`
+	Error string `json:"error,omitempty"`
+}
+
+// readsFile reports whether path is a file type the converter reads, by its extension.
+func readsFile(path string) bool {
+	return engine.IsWorkbook(path) || engine.IsTextFile(path) || engine.IsDocument(path) ||
+		engine.IsPresentation(path) || engine.IsOpenDocument(path)
+}
+
+// fileArg returns the first argument naming a file the converter reads (legacy workbooks
+// included), made absolute against dir, or "". Flags and links are skipped.
+func fileArg(args []string, dir string) string {
+	for _, arg := range args {
+		if arg == "" || strings.HasPrefix(arg, "-") || linkArg([]string{arg}) != "" {
+			continue
+		}
+		if !readsFile(arg) && !isLegacyWorkbook(arg) {
+			continue
+		}
+		if !filepath.IsAbs(arg) && dir != "" {
+			arg = filepath.Join(dir, arg)
+		}
+		return arg
+	}
+	return ""
+}
+
+// openFile selects the file at path in the window, brings the window forward and emits an
+// "openFile" event. Before the page loaded, the file is kept and opened by domReady.
+// Why: Double-clicking a workbook associated with the app, or launching it on one while
+// it already runs, must load that file instead of showing an empty window.
+func (a *App) openFile(path string) {
+	a.mu.Lock()
+	if !a.domLoaded {
+		a.startFile = path
+		a.mu.Unlock()
+		return
+	}
+	a.mu.Unlock()
+
+	event := OpenFileEvent{Path: path}
+	if info, err := os.Stat(path); err != nil {
+		event.Error = fmt.Sprintf("cannot open %s: %v", path, err)
+	} else if info.IsDir() {
+		event.Error = fmt.Sprintf("%s is a folder", path)
+	}
+	if event.Error != "" {
+		slog.Warn("file not opened", "path", path, "error", event.Error)
+	} else {
+		slog.Info("file opened", "path", path)
+	}
+	runtime.WindowUnminimise(a.ctx)
+	runtime.WindowShow(a.ctx)
+	runtime.EventsEmit(a.ctx, "openFile", event)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"convert-vni-to-unicode/internal/engine"
+)
+
+// runRepairColors implements the "repair-colors" subcommand and returns the process exit code.
+// Why: Workbooks converted by older versions can have lost run colors; they are repaired
+// in place against the original instead of being converted again.
+func runRepairColors(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("repair-colors", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	original := fs.String("original", "", "the workbook before conversion")
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: VniConverter repair-colors -original input.xlsx converted.xlsx")
+		_, _ = fmt.Fprintln(stderr, "Restores run colors that the converted workbook lost; it is saved in place.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *original == "" || fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	report, err := engine.RepairColors(context.Background(), *original, fs.Arg(0))
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	_, _ = fmt.Fprintf(stdout, "Restored %d run colors in %d cells\n", report.RunsRepaired, report.CellsRepaired)
+	if report.CellsUnmatched > 0 {
+		_, _ = fmt.Fprintf(stdout, "%d cells have merged runs and were not checked\n", report.CellsUnmatched)
+	}
+	return 0
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"convert-vni-to-unicode/internal/engine"
+)
+
+// retentionFlags are the output retention flags of "watch" and the command line converter.
+type retentionFlags struct {
+	keepLast   *int
+	maxAgeDays *int
+}
+
+// addRetentionFlags registers -keep-last and -max-age-days on fs.
+func addRetentionFlags(fs *flag.FlagSet) retentionFlags {
+	return retentionFlags{
+		keepLast: fs.Int("keep-last", 0,
+			"keep only the newest N outputs of each source in the output folder (0 keeps all)"),
+		maxAgeDays: fs.Int("max-age-days", 0,
+			"delete outputs older than this many days from the output folder (0 keeps all)"),
+	}
+}
+
+// policy returns the parsed retention policy.
+func (r retentionFlags) policy() (engine.RetentionPolicy, error) {
+	policy := retentionPolicy(*r.keepLast, *r.maxAgeDays)
+	return policy, policy.Validate()
+}
+
+// retentionPolicy builds a policy from a per-source output count and an age in days.
+func retentionPolicy(keepLast, maxAgeDays int) engine.RetentionPolicy {
+	return engine.RetentionPolicy{KeepLast: keepLast, MaxAge: time.Duration(maxAgeDays) * 24 * time.Hour}
+}
+
+// pruneOutputs applies policy to the output folder dir, printing every deleted file.
+func pruneOutputs(dir string, policy engine.RetentionPolicy, stdout io.Writer) error {
+	removed, err := engine.ApplyRetention(dir, policy, time.Now())
+	for _, path := range removed {
+		_, _ = fmt.Fprintf(stdout, "DEL  %s\n", path)
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"convert-vni-to-unicode/internal/engine"
+)
+
+// convertedFile is a file written by a job, with what RevertConversion needs to undo it.
+type convertedFile struct {
+	input  string
+	output string
+	// backup is where an in-place run kept the original ("" when output is a new file)
+	backup string
+	// size and modTime of output once written; an output changed since is not reverted
+	size    int64
+	modTime time.Time
+}
+
+// recordConverted remembers output, written for input, as converted by the job.
+func (j *job) recordConverted(input, output, backup string) {
+	info, err := os.Stat(output)
+	if err != nil {
+		slog.Warn("conversion cannot be reverted", "output", output, "error", err)
+		return
+	}
+	j.updateStatus(func(s *JobStatus) {
+		s.converted = append(s.converted, convertedFile{
+			input:   input,
+			output:  output,
+			backup:  backup,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	})
+}
+
+// Revert undoes the files converted by a finished job with undo. Files that cannot be
+// undone stay recorded, so the revert can be retried; their errors are returned.
+func (m *JobManager) Revert(id string, undo func(convertedFile) error) error {
+	m.revertMu.Lock()
+	defer m.revertMu.Unlock()
+
+	m.mu.Lock()
+	_, active := m.jobs[id]
+	status, ok := m.finished[id]
+	m.mu.Unlock()
+	switch {
+	case active:
+		return fmt.Errorf("job %q is still running", id)
+	case !ok:
+		return fmt.Errorf("unknown job %q", id)
+	case status.Reverted:
+		return fmt.Errorf("job %q was already reverted", id)
+	case len(status.converted) == 0:
+		return fmt.Errorf("job %q wrote no file to revert", id)
+	}
+
+	var failed []convertedFile
+	var errs []error
+	for _, f := range status.converted {
+		if err := undo(f); err != nil {
+			failed = append(failed, f)
+			errs = append(errs, err)
+		}
+	}
+
+	m.mu.Lock()
+	if s, ok := m.finished[id]; ok {
+		s.converted = failed
+		s.Reverted = len(failed) == 0
+		m.finished[id] = s
+	}
+	m.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// revertFile restores the original of an in-place conversion, or deletes the new output
+// of any other conversion (its input was never modified). Reports written next to the
+// output are kept.
+func revertFile(f convertedFile) error {
+	info, err := os.Stat(f.output)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && f.backup == "":
+		// Deleted already; nothing left to undo
+		return nil
+	case err == nil && (info.Size() != f.size || !info.ModTime().Equal(f.modTime)):
+		return fmt.Errorf("%s was modified after the conversion; not reverting it", f.output)
+	}
+	if f.backup != "" {
+		return engine.RestoreBackup(f.input, f.backup)
+	}
+	if err := os.Remove(f.output); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", f.output, err)
+	}
+	slog.Info("conversion reverted", "output", f.output)
+	return nil
+}
+
+// RevertConversion undoes a recently finished job: outputs written in place are replaced
+// by the original kept as backup, and new outputs are deleted, leaving the untouched
+// input. An output modified since the conversion is left alone and reported.
+// Why: Converting the wrong sheet or the wrong file should be one click to undo.
+func (a *App) RevertConversion(jobID string) error {
+	return a.jobs.Revert(jobID, revertFile)
+}
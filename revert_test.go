@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConverted writes content to path and returns it recorded as a converted file.
+func writeConverted(t *testing.T, input, output, backup, content string) convertedFile {
+	t.Helper()
+	if err := os.WriteFile(output, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return convertedFile{input: input, output: output, backup: backup, size: info.Size(), modTime: info.ModTime()}
+}
+
+func TestRevertFile(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup writes the files in dir and returns the recorded conversion
+		setup   func(t *testing.T, dir string) convertedFile
+		wantErr string
+		// want maps file names in dir to their content after the revert ("" = gone)
+		want map[string]string
+	}{
+		{
+			name: "New output deleted",
+			setup: func(t *testing.T, dir string) convertedFile {
+				input := filepath.Join(dir, "report.xlsx")
+				if err := os.WriteFile(input, []byte("original"), 0600); err != nil {
+					t.Fatal(err)
+				}
+				return writeConverted(t, input, filepath.Join(dir, "report_output.xlsx"), "", "converted")
+			},
+			want: map[string]string{"report.xlsx": "original", "report_output.xlsx": ""},
+		},
+		{
+			name: "In place restored from backup",
+			setup: func(t *testing.T, dir string) convertedFile {
+				input := filepath.Join(dir, "report.xlsx")
+				backup := filepath.Join(dir, "report.xlsx.bak")
+				if err := os.WriteFile(backup, []byte("original"), 0600); err != nil {
+					t.Fatal(err)
+				}
+				return writeConverted(t, input, input, backup, "converted")
+			},
+			want: map[string]string{"report.xlsx": "original", "report.xlsx.bak": ""},
+		},
+		{
+			name: "Output deleted already",
+			setup: func(t *testing.T, dir string) convertedFile {
+				f := writeConverted(t, "", filepath.Join(dir, "report_output.xlsx"), "", "converted")
+				if err := os.Remove(f.output); err != nil {
+					t.Fatal(err)
+				}
+				return f
+			},
+			want: map[string]string{"report_output.xlsx": ""},
+		},
+		{
+			name: "Output modified since",
+			setup: func(t *testing.T, dir string) convertedFile {
+				f := writeConverted(t, "", filepath.Join(dir, "report_output.xlsx"), "", "converted")
+				if err := os.WriteFile(f.output, []byte("edited by the user"), 0600); err != nil {
+					t.Fatal(err)
+				}
+				return f
+			},
+			wantErr: "modified after the conversion",
+			want:    map[string]string{"report_output.xlsx": "edited by the user"},
+		},
+		{
+			name: "In place output touched since",
+			setup: func(t *testing.T, dir string) convertedFile {
+				input := filepath.Join(dir, "report.xlsx")
+				backup := filepath.Join(dir, "report.xlsx.bak")
+				if err := os.WriteFile(backup, []byte("original"), 0600); err != nil {
+					t.Fatal(err)
+				}
+				f := writeConverted(t, input, input, backup, "converted")
+				later := f.modTime.Add(time.Minute)
+				if err := os.Chtimes(input, later, later); err != nil {
+					t.Fatal(err)
+				}
+				return f
+			},
+			wantErr: "modified after the conversion",
+			want:    map[string]string{"report.xlsx": "converted", "report.xlsx.bak": "original"},
+		},
+		{
+			name: "Backup gone",
+			setup: func(t *testing.T, dir string) convertedFile {
+				input := filepath.Join(dir, "report.xlsx")
+				return writeConverted(t, input, input, filepath.Join(dir, "report.xlsx.bak"), "converted")
+			},
+			wantErr: "original no longer available",
+			want:    map[string]string{"report.xlsx": "converted"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := revertFile(tt.setup(t, dir))
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("revertFile() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("revertFile() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			for name, want := range tt.want {
+				data, readErr := os.ReadFile(filepath.Join(dir, name))
+				if got := string(data); got != want || (want == "" && !errors.Is(readErr, os.ErrNotExist)) {
+					t.Errorf("%s = %q (%v), want %q", name, got, readErr, want)
+				}
+			}
+		})
+	}
+}
+
+// finishedJob runs a job that records files as converted and waits for it to finish.
+func finishedJob(t *testing.T, m *JobManager, files ...convertedFile) string {
+	t.Helper()
+	j := m.Submit(context.Background(), func(j *job) ProcessResult {
+		j.updateStatus(func(s *JobStatus) { s.converted = files })
+		return ProcessResult{Success: true}
+	})
+	waitJob(t, j)
+	return j.id
+}
+
+func TestJobManager_Revert(t *testing.T) {
+	a := convertedFile{output: "a_output.xlsx"}
+	b := convertedFile{output: "b_output.xlsx"}
+	errLocked := errors.New("file is open in Excel")
+
+	tests := []struct {
+		name string
+		// job returns the ID to revert
+		job func(t *testing.T, m *JobManager) string
+		// failing lists the outputs undo fails for on each call
+		failing [][]string
+		// wantErrs is whether each call fails
+		wantErrs []bool
+		// wantUndone lists the outputs undo was called for, over all calls
+		wantUndone   []string
+		wantReverted bool
+	}{
+		{
+			name:         "Every file undone",
+			job:          func(t *testing.T, m *JobManager) string { return finishedJob(t, m, a, b) },
+			failing:      [][]string{nil},
+			wantErrs:     []bool{false},
+			wantUndone:   []string{"a_output.xlsx", "b_output.xlsx"},
+			wantReverted: true,
+		},
+		{
+			name:       "Reverted twice",
+			job:        func(t *testing.T, m *JobManager) string { return finishedJob(t, m, a) },
+			failing:    [][]string{nil, nil},
+			wantErrs:   []bool{false, true},
+			wantUndone: []string{"a_output.xlsx"},
+			// The second call is refused, the first one reverted the job
+			wantReverted: true,
+		},
+		{
+			name:         "Failed file retried alone",
+			job:          func(t *testing.T, m *JobManager) string { return finishedJob(t, m, a, b) },
+			failing:      [][]string{{"b_output.xlsx"}, nil},
+			wantErrs:     []bool{true, false},
+			wantUndone:   []string{"a_output.xlsx", "b_output.xlsx", "b_output.xlsx"},
+			wantReverted: true,
+		},
+		{
+			name:       "Failed file still failing",
+			job:        func(t *testing.T, m *JobManager) string { return finishedJob(t, m, a) },
+			failing:    [][]string{{"a_output.xlsx"}, {"a_output.xlsx"}},
+			wantErrs:   []bool{true, true},
+			wantUndone: []string{"a_output.xlsx", "a_output.xlsx"},
+		},
+		{
+			name:     "No file written",
+			job:      func(t *testing.T, m *JobManager) string { return finishedJob(t, m) },
+			failing:  [][]string{nil},
+			wantErrs: []bool{true},
+		},
+		{
+			name:     "Unknown job",
+			job:      func(*testing.T, *JobManager) string { return "job-99" },
+			failing:  [][]string{nil},
+			wantErrs: []bool{true},
+		},
+		{
+			name: "Running job",
+			job: func(t *testing.T, m *JobManager) string {
+				release := make(chan struct{})
+				j := m.Submit(context.Background(), blockingRun(release))
+				t.Cleanup(func() { close(release); <-j.done })
+				return j.id
+			},
+			failing:  [][]string{nil},
+			wantErrs: []bool{true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newJobManager(func() int { return 1 }, func(string, any) {})
+			id := tt.job(t, m)
+
+			var undone []string
+			for call, failing := range tt.failing {
+				err := m.Revert(id, func(f convertedFile) error {
+					undone = append(undone, f.output)
+					for _, output := range failing {
+						if f.output == output {
+							return errLocked
+						}
+					}
+					return nil
+				})
+				if (err != nil) != tt.wantErrs[call] {
+					t.Fatalf("Revert() call %d error = %v, wantErr %v", call+1, err, tt.wantErrs[call])
+				}
+				if len(failing) > 0 && !errors.Is(err, errLocked) {
+					t.Errorf("Revert() call %d error = %v, want the undo error", call+1, err)
+				}
+			}
+			if strings.Join(undone, ",") != strings.Join(tt.wantUndone, ",") {
+				t.Errorf("undone = %v, want %v", undone, tt.wantUndone)
+			}
+			if status, _ := m.Status(id); status.Reverted != tt.wantReverted {
+				t.Errorf("Reverted = %v, want %v", status.Reverted, tt.wantReverted)
+			}
+		})
+	}
+}
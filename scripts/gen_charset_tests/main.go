@@ -0,0 +1,193 @@
+// Package main generates exhaustive converter tests from the charset tables in tables/.
+//
+// Each table lists every letter of one legacy encoding as its bytes and Unicode code points.
+// The bytes are decoded as Windows-1252, which is how Excel shows text typed in a legacy
+// font, and one test case is written per letter to internal/converter/<table>_table_test.go.
+// The generated tests carry the "charsets" build tag (run them with make test-charsets),
+// since they also list the letters a converter does not handle yet.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// encodings maps the encoding named by a table to its converter.EncodingType constant.
+// Why: A table can only be added once the converter package supports its encoding.
+var encodings = map[string]string{
+	"VNI":    "EncodingVNI",
+	"TCVN3":  "EncodingTCVN3",
+	"VISCII": "EncodingVISCII",
+}
+
+// mapping is one letter of a table.
+type mapping struct {
+	legacy string // as Excel shows it (Windows-1252)
+	want   string
+}
+
+type table struct {
+	name     string // file name without extension
+	encoding string // converter.EncodingType constant
+	mappings []mapping
+}
+
+func main() {
+	tables := flag.String("tables", "scripts/gen_charset_tests/tables", "directory holding the charset tables (*.txt)")
+	out := flag.String("out", "internal/converter", "directory to write the tests to")
+	flag.Parse()
+
+	paths, err := filepath.Glob(filepath.Join(*tables, "*.txt"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(paths) == 0 {
+		log.Fatalf("no charset tables in %s", *tables)
+	}
+	for _, path := range paths {
+		t, err := readTable(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		src, err := generate(t, filepath.ToSlash(path))
+		if err != nil {
+			log.Fatal(err)
+		}
+		target := filepath.Join(*out, t.name+"_table_test.go")
+		if err := os.WriteFile(target, src, 0600); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s: %d letters\n", target, len(t.mappings))
+	}
+}
+
+// readTable parses a charset table: an "encoding <name>" line, then one
+// "<hex bytes> <code points> [# comment]" line per letter.
+func readTable(path string) (table, error) {
+	f, err := os.Open(path) //nolint:gosec // developer-supplied table
+	if err != nil {
+		return table{}, fmt.Errorf("failed to open table: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	t := table{name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))}
+	seen := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "encoding" {
+			if len(fields) != 2 || encodings[fields[1]] == "" {
+				return table{}, fmt.Errorf("%s:%d: unsupported encoding line %q", path, lineNo, line)
+			}
+			t.encoding = encodings[fields[1]]
+			continue
+		}
+
+		var raw []byte
+		var want []rune
+		for _, field := range fields {
+			if hexCode, ok := strings.CutPrefix(field, "U+"); ok {
+				r, err := strconv.ParseUint(hexCode, 16, 32)
+				if err != nil {
+					return table{}, fmt.Errorf("%s:%d: invalid code point %q: %w", path, lineNo, field, err)
+				}
+				want = append(want, rune(r))
+				continue
+			}
+			if len(want) > 0 {
+				return table{}, fmt.Errorf("%s:%d: byte %q after the code points", path, lineNo, field)
+			}
+			b, err := hex.DecodeString(field)
+			if err != nil || len(b) != 1 {
+				return table{}, fmt.Errorf("%s:%d: invalid byte %q", path, lineNo, field)
+			}
+			raw = append(raw, b[0])
+		}
+		if len(raw) == 0 || len(want) == 0 {
+			return table{}, fmt.Errorf("%s:%d: want bytes and code points", path, lineNo)
+		}
+		var legacy []rune
+		for _, b := range raw {
+			// Windows reads the five bytes code page 1252 leaves undefined as C1 controls
+			r := charmap.Windows1252.DecodeByte(b)
+			if r == utf8.RuneError {
+				r = rune(b)
+			}
+			legacy = append(legacy, r)
+		}
+		if prev, ok := seen[string(raw)]; ok {
+			return table{}, fmt.Errorf("%s:%d: bytes already mapped on line %d", path, lineNo, prev)
+		}
+		seen[string(raw)] = lineNo
+		t.mappings = append(t.mappings, mapping{legacy: string(legacy), want: string(want)})
+	}
+	if err := scanner.Err(); err != nil {
+		return table{}, fmt.Errorf("failed to read table: %w", err)
+	}
+	if t.encoding == "" {
+		return table{}, fmt.Errorf("%s: missing encoding line", path)
+	}
+	return t, nil
+}
+
+// generate returns the formatted test file of t.
+func generate(t table, source string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "//go:build charsets\n\n")
+	fmt.Fprintf(&b, "// Code generated by scripts/gen_charset_tests from %s; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package converter\n\nimport \"testing\"\n\n")
+	fmt.Fprintf(&b, "func Test%sTable(t *testing.T) {\n", strings.TrimPrefix(t.encoding, "Encoding"))
+	fmt.Fprintf(&b, "\tc, err := NewConverter(%s)\n", t.encoding)
+	fmt.Fprintf(&b, "\tif err != nil {\n\t\tt.Fatalf(\"NewConverter failed: %%v\", err)\n\t}\n\n")
+	fmt.Fprintf(&b, "\ttests := []struct {\n\t\tlegacy string\n\t\twant   string\n\t}{\n")
+	for _, m := range t.mappings {
+		fmt.Fprintf(&b, "\t\t{legacy: %s, want: %q},\n", quoteLegacy(m.legacy), m.want)
+	}
+	fmt.Fprintf(&b, "\t}\n\n")
+	fmt.Fprintf(&b, "\tfor _, tt := range tests {\n\t\tt.Run(tt.want, func(t *testing.T) {\n")
+	fmt.Fprintf(&b, "\t\t\tif got := c.ToUnicode(tt.legacy); got != tt.want {\n")
+	fmt.Fprintf(&b, "\t\t\t\tt.Errorf(\"ToUnicode(%%q) = %%q, want %%q\", tt.legacy, got, tt.want)\n")
+	fmt.Fprintf(&b, "\t\t\t}\n\t\t})\n\t}\n}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated test: %w", err)
+	}
+	return src, nil
+}
+
+// quoteLegacy quotes legacy text with its non-ASCII characters escaped ("a\u00F9"), as in
+// the hand-written tests.
+func quoteLegacy(text string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range text {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= ' ' && r < 0x7F:
+			b.WriteRune(r)
+		default:
+			fmt.Fprintf(&b, "\\u%04X", r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -61,8 +61,8 @@ func main() {
 	}
 
 	// 2. TCVN3 Cell
-	// "Công ty" -> "C\u00F6ng ty"
-	if err = f.SetCellValue(sheet, "B2", "C\u00F6ng ty"); err != nil {
+	// "Công ty" -> "C\u00ABng ty"
+	if err = f.SetCellValue(sheet, "B2", "C\u00ABng ty"); err != nil {
 		log.Fatal(err)
 	}
 	var styleTCVN3 int
@@ -0,0 +1,96 @@
+// Package main trains the n-gram detector models embedded in internal/engine.
+//
+// The seed corpus is Unicode text: corpus_vi.txt is encoded to every legacy encoding the
+// detector chooses between, and both files train the "leave alone" (UNKNOWN) class.
+// Samples exported with "VniConverter dataset" can be added with -dataset.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"convert-vni-to-unicode/internal/converter"
+	"convert-vni-to-unicode/internal/engine"
+)
+
+func main() {
+	corpus := flag.String("corpus", "scripts/train_ngram", "directory holding corpus_vi.txt and corpus_other.txt")
+	dataset := flag.String("dataset", "", "optional JSON Lines dataset exported by the dataset subcommand")
+	out := flag.String("out", "internal/engine/ngram_models", "directory to write the models to")
+	flag.Parse()
+
+	vietnamese, err := readLines(filepath.Join(*corpus, "corpus_vi.txt"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	other, err := readLines(filepath.Join(*corpus, "corpus_other.txt"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var samples []engine.DatasetSample
+	for _, enc := range []converter.EncodingType{converter.EncodingVNI, converter.EncodingTCVN3} {
+		encoder, err := converter.NewEncoder(enc)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, line := range vietnamese {
+			samples = append(samples, engine.DatasetSample{Legacy: encoder.FromUnicode(line), Encoding: enc})
+		}
+	}
+	for _, line := range append(vietnamese, other...) {
+		samples = append(samples, engine.DatasetSample{Legacy: line, Encoding: converter.EncodingUnknown})
+	}
+	if *dataset != "" {
+		extra, err := readDataset(*dataset)
+		if err != nil {
+			log.Fatal(err)
+		}
+		samples = append(samples, extra...)
+	}
+
+	if err := os.MkdirAll(*out, 0750); err != nil {
+		log.Fatal(err)
+	}
+	for _, m := range engine.TrainNGramModels(samples, engine.NGramOrder) {
+		data, err := json.Marshal(m)
+		if err != nil {
+			log.Fatal(err)
+		}
+		path := filepath.Join(*out, strings.ToLower(string(m.Encoding))+".json")
+		if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s: %d n-grams from %d windows\n", path, len(m.Counts), m.Total)
+	}
+}
+
+// readLines returns the non-blank lines of a text file.
+func readLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // developer-supplied corpus
+	if err != nil {
+		return nil, fmt.Errorf("failed to read corpus: %w", err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// readDataset loads a dataset exported by the dataset subcommand.
+func readDataset(path string) ([]engine.DatasetSample, error) {
+	f, err := os.Open(path) //nolint:gosec // developer-supplied dataset
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return engine.ReadDataset(f)
+}
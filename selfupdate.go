@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"convert-vni-to-unicode/internal/updater"
+)
+
+// runSelfUpdate implements the "selfupdate" subcommand and returns the process exit code.
+// Why: Headless installs have no GUI to show the update bar, and the GUI updater
+// depends on Wails runtime events.
+func runSelfUpdate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("selfupdate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	checkOnly := fs.Bool("check", false, "only report whether an update is available")
+	var mirrors stringList
+	fs.Var(&mirrors, "mirror",
+		"URL or file share path to try if GitHub is unreachable (repeatable, supports {version} and {asset})")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	// Mirrors from the settings file come first, then the command line
+	_, prefs := loadSettings()
+	mirrors = append(prefs.UpdateMirrors, mirrors...)
+
+	// Ctrl+C cancels the download; partial files are removed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	info, err := updater.Check(ctx, CurrentVersion)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	if !info.Available {
+		_, _ = fmt.Fprintf(stdout, "Already up to date (%s).\n", CurrentVersion)
+		return 0
+	}
+	_, _ = fmt.Fprintf(stdout, "Update available: %s -> %s\n", CurrentVersion, info.LatestVer)
+	if *checkOnly {
+		return 0
+	}
+
+	lastPct := -1
+	progress := func(written, total int64) {
+		if total <= 0 {
+			return
+		}
+		if pct := int(written * 100 / total); pct/10 != lastPct/10 {
+			lastPct = pct
+			_, _ = fmt.Fprintf(stdout, "Downloading... %d%%\n", pct)
+		}
+	}
+	sources := updater.Sources(info.DownloadURL, info.LatestVer, mirrors)
+	batchPath, err := updater.Prepare(ctx, sources, false, progress)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	if err := updater.StartScript(batchPath); err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	_, _ = fmt.Fprintf(stdout, "Installing %s; the executable is replaced in a few seconds.\n", info.LatestVer)
+	return 0
+}
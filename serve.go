@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"convert-vni-to-unicode/internal/engine"
+	"convert-vni-to-unicode/internal/server"
+)
+
+// serveShutdownTimeout bounds how long in-flight requests may finish after a stop signal.
+const serveShutdownTimeout = 10 * time.Second
+
+// runServe implements the "serve" subcommand and returns the process exit code.
+// Why: Batch integrations (data-cleaning jobs) call the converters over HTTP.
+func runServe(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", "127.0.0.1:8080", "address to listen on (use :8080 to accept remote clients)")
+	maxBatch := fs.Int("max-batch", server.DefaultMaxBatch, "maximum number of strings per request")
+	detectorName := fs.String("detector", engine.DetectorRules, "encoding detector for items without a hint: rules, ngram")
+	jobs := fs.Bool("jobs", false, "accept workbook conversion jobs (POST /v1/jobs); requires -job-token and -job-roots")
+	jobToken := fs.String("job-token", "", "bearer token clients must send to use the jobs API")
+	jobRoots := fs.String("job-roots", "",
+		"comma-separated folders and URL prefixes job inputs and output folders must be inside")
+	callbackHosts := fs.String("callback-hosts", "",
+		"comma-separated hosts (host or host:port) job callbacks may be posted to")
+	jobWorkers := fs.Int("job-workers", server.DefaultJobWorkers, "workbook jobs converted at once")
+	allowOrigin := fs.String("allow-origin", "", "comma-separated browser origins allowed to call the API, "+
+		"e.g. the host of an Office.js add-in (* allows any)")
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: VniConverter serve [-addr 127.0.0.1:8080] [-max-batch 1000] [-jobs]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *maxBatch < 1 {
+		_, _ = fmt.Fprintln(stderr, "Error: -max-batch must be at least 1")
+		return 2
+	}
+	detector, err := engine.NewDetector(*detectorName)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+
+	if *jobWorkers < 1 {
+		_, _ = fmt.Fprintln(stderr, "Error: -job-workers must be at least 1")
+		return 2
+	}
+	if *jobs && (*jobToken == "" || *jobRoots == "") {
+		_, _ = fmt.Fprintln(stderr, "Error: -jobs requires -job-token and -job-roots")
+		return 2
+	}
+
+	// SIGTERM is how systemd and container runtimes stop the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts := server.Options{MaxBatch: *maxBatch, Detector: detector}
+	opts.AllowedOrigins = splitList(*allowOrigin)
+	endpoints := "POST /v1/convert-text, POST /v1/convert-range"
+	if *jobs {
+		buildInfo := engine.NewBuildInfo(CurrentVersion)
+		opts.Convert = func(ctx context.Context, input, outDir string) (string, engine.WorkbookResult, error) {
+			return engine.ConvertStorage(ctx, input, outDir, engine.WithDetector(detector), engine.WithBuildInfo(buildInfo))
+		}
+		opts.JobWorkers, opts.Context = *jobWorkers, ctx
+		opts.JobToken, opts.JobRoots, opts.CallbackHosts = *jobToken, splitList(*jobRoots), splitList(*callbackHosts)
+		endpoints += ", POST /v1/jobs, GET /v1/jobs/{id}"
+	}
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.NewHandler(opts),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			slog.Warn("server shutdown", "error", err)
+		}
+	}()
+
+	_, _ = fmt.Fprintf(stdout, "Listening on %s (%s)\n", *addr, endpoints)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
+
+// splitList returns the non-empty, trimmed elements of a comma-separated flag value.
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// runService reports that services are Windows-only.
+// On Linux/macOS, run the "watch" subcommand under systemd or launchd instead.
+func runService(_ []string, _, stderr io.Writer) int {
+	_, _ = fmt.Fprintln(stderr,
+		"Error: the service subcommand is only available on Windows; run 'watch' under systemd or launchd instead")
+	return 2
+}
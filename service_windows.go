@@ -0,0 +1,252 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"convert-vni-to-unicode/internal/eventlog"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// Windows service registration.
+const (
+	serviceName        = "VniConverter"
+	serviceDisplayName = "VNI to Unicode Converter"
+	serviceDescription = "Converts legacy Vietnamese workbooks dropped into a watch folder to Unicode."
+	// serviceStopTimeout bounds how long "service stop" waits for the service to exit.
+	serviceStopTimeout = 30 * time.Second
+)
+
+// runService implements the "service" subcommand and returns the process exit code.
+// Why: The watch folder must keep running on departmental machines without a logged-in user,
+// and come back automatically after crashes or reboots.
+func runService(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printServiceUsage(stderr)
+		return 2
+	}
+	var err error
+	switch args[0] {
+	case "install":
+		err = installService(args[1:], stderr)
+	case "uninstall":
+		err = uninstallService()
+	case "start":
+		err = startService()
+	case "stop":
+		err = stopService()
+	case "run":
+		return runServiceHost(args[1:], stderr)
+	default:
+		printServiceUsage(stderr)
+		return 2
+	}
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			_, _ = fmt.Fprintln(stderr, "Error:", err)
+		}
+		return 1
+	}
+	_, _ = fmt.Fprintf(stdout, "Service %s: %s done.\n", serviceName, args[0])
+	return 0
+}
+
+func printServiceUsage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "Usage: VniConverter service install -in incoming -out converted [-interval 10s]")
+	_, _ = fmt.Fprintln(w, "       VniConverter service start | stop | uninstall")
+}
+
+// installService registers the service to run "watch" with the given flags at boot.
+func installService(args []string, stderr io.Writer) error {
+	opts, err := parseWatchArgs(args, stderr)
+	if err != nil {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the service manager (run as administrator): %w", err)
+	}
+	defer func() { _ = m.Disconnect() }()
+
+	if s, err := m.OpenService(serviceName); err == nil {
+		_ = s.Close()
+		return fmt.Errorf("service %s is already installed", serviceName)
+	}
+
+	// The service always writes its summaries to the event log; it has no console
+	runArgs := []string{"service", "run", "-in", opts.inputDir, "-out", opts.outDir,
+		"-interval", opts.interval.String(), "-event-log"}
+	s, err := m.CreateService(serviceName, exe, mgr.Config{
+		DisplayName: serviceDisplayName,
+		Description: serviceDescription,
+		StartType:   mgr.StartAutomatic,
+	}, runArgs...)
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	// Restart after failures: quickly at first, then every minute; the count resets daily
+	actions := []mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: 5 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: 30 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: time.Minute},
+	}
+	if err := s.SetRecoveryActions(actions, uint32((24 * time.Hour).Seconds())); err != nil {
+		return fmt.Errorf("failed to set recovery actions: %w", err)
+	}
+	// Also restart when the service stops with an error instead of crashing
+	if err := s.SetRecoveryActionsOnNonCrashFailures(true); err != nil {
+		return fmt.Errorf("failed to set recovery actions: %w", err)
+	}
+	// Register the event source now, while elevated, so the service's entries display
+	return eventlog.Install(eventlog.Source)
+}
+
+// uninstallService stops (if needed) and removes the service.
+func uninstallService() error {
+	m, s, err := openService()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.Disconnect() }()
+	defer func() { _ = s.Close() }()
+
+	if status, err := s.Query(); err == nil && status.State != svc.Stopped {
+		if err := waitForStop(s); err != nil {
+			return err
+		}
+	}
+	if err := s.Delete(); err != nil {
+		return fmt.Errorf("failed to delete service: %w", err)
+	}
+	return eventlog.Uninstall(eventlog.Source)
+}
+
+// startService asks the service manager to start the service.
+func startService() error {
+	m, s, err := openService()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.Disconnect() }()
+	defer func() { _ = s.Close() }()
+
+	if err := s.Start(); err != nil {
+		return fmt.Errorf("failed to start service: %w", err)
+	}
+	return nil
+}
+
+// stopService stops the service and waits for it to exit.
+func stopService() error {
+	m, s, err := openService()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.Disconnect() }()
+	defer func() { _ = s.Close() }()
+	return waitForStop(s)
+}
+
+// openService connects to the service manager and opens the installed service.
+func openService() (*mgr.Mgr, *mgr.Service, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to the service manager (run as administrator): %w", err)
+	}
+	s, err := m.OpenService(serviceName)
+	if err != nil {
+		_ = m.Disconnect()
+		return nil, nil, fmt.Errorf("service %s is not installed: %w", serviceName, err)
+	}
+	return m, s, nil
+}
+
+// waitForStop sends a stop request and waits until the service reports Stopped.
+func waitForStop(s *mgr.Service) error {
+	status, err := s.Control(svc.Stop)
+	if err != nil {
+		return fmt.Errorf("failed to stop service: %w", err)
+	}
+	deadline := time.Now().Add(serviceStopTimeout)
+	for status.State != svc.Stopped {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("service did not stop within %s", serviceStopTimeout)
+		}
+		time.Sleep(300 * time.Millisecond)
+		if status, err = s.Query(); err != nil {
+			return fmt.Errorf("failed to query service: %w", err)
+		}
+	}
+	return nil
+}
+
+// runServiceHost is the entry point used by the service manager ("service run ...").
+func runServiceHost(args []string, stderr io.Writer) int {
+	opts, err := parseWatchArgs(args, stderr)
+	if err != nil {
+		return 2
+	}
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		_, _ = fmt.Fprintln(stderr, "Error: 'service run' is started by the service manager; use 'watch' to run in a console")
+		return 2
+	}
+	if err := svc.Run(serviceName, &watchService{opts: opts}); err != nil {
+		return 1
+	}
+	return 0
+}
+
+// watchService runs the watch loop under the service manager.
+type watchService struct {
+	opts watchOptions
+}
+
+// Execute implements svc.Handler.
+func (s *watchService) Execute(_ []string, requests <-chan svc.ChangeRequest,
+	changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- watchFolder(ctx, s.opts, io.Discard, io.Discard) }()
+
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for {
+		select {
+		case err := <-done:
+			// watchFolder only returns early on setup errors (already in the event log);
+			// a service-specific exit code makes the service manager apply the recovery actions
+			if err != nil {
+				return true, 1
+			}
+			return false, 0
+		case c := <-requests:
+			switch c.Cmd {
+			case svc.Interrogate:
+				changes <- c.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				cancel()
+				<-done
+				return false, 0
+			}
+		}
+	}
+}
@@ -1,15 +1,12 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"strings"
-	"time"
+	"log/slog"
+
+	"convert-vni-to-unicode/internal/updater"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
@@ -18,17 +15,6 @@ import (
 // Default is "0.0.0" for local development.
 var CurrentVersion = "0.0.0"
 
-// GitHub repository info
-const (
-	GitHubOwner = "hoangtran1411"
-	GitHubRepo  = "convert-vni-to-unicode"
-
-	// HTTP client configuration
-	httpTimeout     = 30 * time.Second
-	downloadTimeout = 5 * time.Minute
-	maxDownloadSize = 200 * 1024 * 1024 // 200MB max download size
-)
-
 // UpdateInfo holds information about available updates
 type UpdateInfo struct {
 	Available   bool   `json:"available"`
@@ -36,16 +22,8 @@ type UpdateInfo struct {
 	LatestVer   string `json:"latestVersion"`
 	DownloadURL string `json:"downloadUrl"`
 	ReleaseURL  string `json:"releaseUrl"`
-}
-
-// GitHubRelease represents a GitHub release API response
-type GitHubRelease struct {
-	TagName string `json:"tag_name"`
-	HTMLURL string `json:"html_url"`
-	Assets  []struct {
-		Name               string `json:"name"`
-		BrowserDownloadURL string `json:"browser_download_url"`
-	} `json:"assets"`
+	// Error explains why an available update cannot be installed (e.g. no build for this CPU)
+	Error string `json:"error,omitempty"`
 }
 
 // GetCurrentVersion returns the current app version
@@ -55,177 +33,113 @@ func (a *App) GetCurrentVersion() string {
 
 // CheckForUpdate checks GitHub for newer versions
 func (a *App) CheckForUpdate() UpdateInfo {
-	info := UpdateInfo{
-		Available:  false,
-		CurrentVer: CurrentVersion,
-	}
-
-	// Create HTTP client with timeout to prevent hanging
-	client := &http.Client{Timeout: httpTimeout}
-
-	// Call GitHub API
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", GitHubOwner, GitHubRepo)
-	req, err := http.NewRequestWithContext(a.ctx, http.MethodGet, url, nil)
+	info, err := updater.Check(a.ctx, CurrentVersion)
 	if err != nil {
-		runtime.LogErrorf(a.ctx, "Failed to create request: %v", err)
-		return info
+		slog.Error("failed to check update", "error", err)
+	}
+	// Remembered to expand {version} in mirror templates
+	a.mu.Lock()
+	a.latestVersion = info.LatestVer
+	a.mu.Unlock()
+	result := UpdateInfo{
+		Available:   info.Available,
+		CurrentVer:  info.CurrentVer,
+		LatestVer:   info.LatestVer,
+		DownloadURL: info.DownloadURL,
+		ReleaseURL:  info.ReleaseURL,
+	}
+	// Only surface errors about a release we know exists; network failures stay silent
+	if info.Available && err != nil {
+		result.Error = err.Error()
 	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		runtime.LogErrorf(a.ctx, "Failed to check update: %v", err)
-		return info
-	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			runtime.LogErrorf(a.ctx, "Failed to close response body: %v", closeErr)
-		}
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return info
-	}
-
-	var release GitHubRelease
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-		return info
-	}
-
-	info.LatestVer = release.TagName
-	info.ReleaseURL = release.HTMLURL
-
-	// Find Windows exe asset
-	for _, asset := range release.Assets {
-		if strings.HasSuffix(strings.ToLower(asset.Name), ".exe") {
-			info.DownloadURL = asset.BrowserDownloadURL
-			break
-		}
-	}
-
-	// Compare versions
-	if info.LatestVer != "" && CompareVersions(info.LatestVer, CurrentVersion) {
-		info.Available = true
-	}
-
-	return info
-}
-
-// CompareVersions returns true if v1 is newer than v2
-func CompareVersions(v1, v2 string) bool {
-	v1 = strings.TrimPrefix(v1, "v")
-	v2 = strings.TrimPrefix(v2, "v")
-
-	parts1 := parseVersion(v1)
-	parts2 := parseVersion(v2)
-
-	for i := 0; i < 3; i++ {
-		if parts1[i] > parts2[i] {
-			return true
-		}
-		if parts1[i] < parts2[i] {
-			return false
-		}
-	}
-	return false
+	return result
 }
 
-func parseVersion(v string) [3]int {
-	var result [3]int
-	parts := strings.Split(v, ".")
-	for i := 0; i < len(parts) && i < 3; i++ {
-		_, _ = fmt.Sscanf(parts[i], "%d", &result[i])
+// CancelUpdate stops a running update download. Returns false if no download is in progress.
+func (a *App) CancelUpdate() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.updateCancel == nil {
+		return false
 	}
-	return result
+	a.updateCancel()
+	return true
 }
 
 // PerformUpdate downloads and installs the new version
 func (a *App) PerformUpdate(downloadURL string) (bool, error) {
-	if downloadURL == "" {
-		return false, fmt.Errorf("no download URL provided")
-	}
-
-	exePath, err := os.Executable()
-	if err != nil {
-		return false, fmt.Errorf("failed to get executable path: %w", err)
-	}
-
-	// Resolve to absolute path safely
-	exePath, err = filepath.Abs(exePath)
+	batchPath, err := a.prepareUpdate(downloadURL, true)
 	if err != nil {
-		return false, fmt.Errorf("failed to resolve absolute path: %w", err)
+		return false, err
 	}
 
-	// Security: Validate the executable path doesn't contain shell-dangerous characters
-	// This prevents command injection attacks
-	if strings.ContainsAny(exePath, `"&|<>^`) {
-		return false, fmt.Errorf("executable path contains unsafe characters")
+	runtime.EventsEmit(a.ctx, "updateProgress", "Installing update...")
+	if err := updater.StartScript(batchPath); err != nil {
+		return false, err
 	}
 
-	tempDir := os.TempDir()
-	tempFile := filepath.Join(tempDir, "vni_update.exe")
-
-	runtime.EventsEmit(a.ctx, "updateProgress", "Downloading update...")
-
-	// Create HTTP client with timeout for download
-	client := &http.Client{Timeout: downloadTimeout}
+	runtime.Quit(a.ctx)
+	return true, nil
+}
 
-	req, err := http.NewRequestWithContext(a.ctx, http.MethodGet, downloadURL, nil)
+// ScheduleUpdateOnExit downloads the new version now and installs it when the app closes.
+// Why: Users mid-workday should not be forced into an immediate restart.
+func (a *App) ScheduleUpdateOnExit(downloadURL string) error {
+	batchPath, err := a.prepareUpdate(downloadURL, false)
 	if err != nil {
-		return false, fmt.Errorf("failed to create request: %w", err)
+		return err
 	}
+	a.mu.Lock()
+	a.pendingUpdate = batchPath
+	a.mu.Unlock()
+	runtime.EventsEmit(a.ctx, "updateProgress", "Update will be installed when you close the app.")
+	return nil
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return false, fmt.Errorf("failed to download: %w", err)
+// runPendingUpdate starts the swap script scheduled by ScheduleUpdateOnExit, if any.
+// It is called on shutdown; the script waits for the process to exit before swapping.
+func (a *App) runPendingUpdate() {
+	a.mu.Lock()
+	batchPath := a.pendingUpdate
+	a.pendingUpdate = ""
+	a.mu.Unlock()
+	if batchPath == "" {
+		return
 	}
-	defer func() {
-		_ = resp.Body.Close() // Error is non-critical during update
-	}()
-
-	// Validate response status
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	if err := updater.StartScript(batchPath); err != nil {
+		slog.Error("failed to install update on exit", "error", err)
 	}
+}
 
-	// Limit download size to prevent memory exhaustion attacks
-	limitedReader := io.LimitReader(resp.Body, maxDownloadSize)
+// prepareUpdate runs a cancellable (see CancelUpdate) download, falling back to the
+// configured mirrors, and writes the swap script.
+func (a *App) prepareUpdate(downloadURL string, restart bool) (string, error) {
+	ctx, cancel := context.WithCancel(a.ctx)
+	defer cancel()
+	mirrors := a.loadSettings().UpdateMirrors
+	a.mu.Lock()
+	sources := updater.Sources(downloadURL, a.latestVersion, mirrors)
+	if a.updateCancel != nil {
+		a.mu.Unlock()
+		return "", fmt.Errorf("an update is already in progress")
+	}
+	a.updateCancel = cancel
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		a.updateCancel = nil
+		a.mu.Unlock()
+	}()
 
-	out, err := os.Create(tempFile) //nolint:gosec // tempFile is constructed safely from os.TempDir
-	if err != nil {
-		return false, fmt.Errorf("failed to create temp file: %w", err)
-	}
+	runtime.EventsEmit(a.ctx, "updateProgress", "Downloading update...")
 
-	_, err = io.Copy(out, limitedReader)
-	if closeErr := out.Close(); closeErr != nil && err == nil {
-		err = closeErr
-	}
+	batchPath, err := updater.Prepare(ctx, sources, restart, nil)
 	if err != nil {
-		return false, fmt.Errorf("failed to save update: %w", err)
-	}
-
-	runtime.EventsEmit(a.ctx, "updateProgress", "Installing update...")
-
-	// Create batch script to swap files and restart
-	// Note: Paths are validated above to prevent command injection
-	batchPath := filepath.Join(tempDir, "update_vni.bat")
-	batchContent := fmt.Sprintf(`@echo off
-timeout /t 2 /nobreak >nul
-del "%s"
-move /y "%s" "%s"
-start "" "%s"
-del "%%~f0"
-`, exePath, tempFile, exePath, exePath)
-
-	// Use 0600 permission for security (owner read/write only)
-	if err := os.WriteFile(batchPath, []byte(batchContent), 0600); err != nil {
-		return false, fmt.Errorf("failed to create update script: %w", err)
-	}
-
-	cmd := exec.Command("cmd", "/c", "start", "/min", "", batchPath) //nolint:gosec,noctx // safe detached proc
-	if err := cmd.Start(); err != nil {
-		return false, fmt.Errorf("failed to start update script: %w", err)
+		if errors.Is(ctx.Err(), context.Canceled) {
+			runtime.EventsEmit(a.ctx, "updateProgress", "Update cancelled.")
+			return "", fmt.Errorf("update cancelled: %w", ctx.Err())
+		}
+		return "", err
 	}
-
-	runtime.Quit(a.ctx)
-	return true, nil
+	return batchPath, nil
 }
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"convert-vni-to-unicode/internal/cache"
+	"convert-vni-to-unicode/internal/converter"
+	"convert-vni-to-unicode/internal/engine"
+	"convert-vni-to-unicode/internal/eventlog"
+	"convert-vni-to-unicode/internal/settings"
+	"convert-vni-to-unicode/internal/storage"
+)
+
+// watchSettleTime is how long a file must stay unmodified before it is converted.
+// Why: Files copied into the folder are picked up by the poll while still being written.
+const watchSettleTime = 2 * time.Second
+
+// watchOptions configures the watch-folder loop.
+type watchOptions struct {
+	inputDir string
+	outDir   string
+	interval time.Duration
+	eventLog bool
+	// retention prunes old outputs after every poll; retentionSet is false when neither
+	// retention flag was given, so the persisted settings apply
+	retention    engine.RetentionPolicy
+	retentionSet bool
+}
+
+// parseWatchArgs parses the "watch" flags; the service subcommand reuses it to validate its arguments.
+func parseWatchArgs(args []string, stderr io.Writer) (watchOptions, error) {
+	var opts watchOptions
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.inputDir, "in", "", "folder to watch for workbooks (required)")
+	fs.StringVar(&opts.outDir, "out", "", "folder for converted workbooks (required, must differ from -in)")
+	fs.DurationVar(&opts.interval, "interval", 10*time.Second, "how often the folder is checked")
+	fs.BoolVar(&opts.eventLog, "event-log", false,
+		"write start/stop/error summaries to the Windows Event Log (syslog elsewhere)")
+	retention := addRetentionFlags(fs)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: VniConverter watch -in incoming -out converted [-interval 10s]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if opts.inputDir == "" || opts.outDir == "" {
+		fs.Usage()
+		return opts, errors.New("-in and -out are required")
+	}
+	// The loop lists, converts and prunes folders on disk (UNC shares included); storage
+	// URLs are only read and written by the command line batch and serve
+	if storage.IsURI(opts.inputDir) || storage.IsURI(opts.outDir) {
+		return opts, errors.New("-in and -out must be local or UNC folders, not URLs")
+	}
+	in, errIn := filepath.Abs(opts.inputDir)
+	out, errOut := filepath.Abs(opts.outDir)
+	if errIn != nil || errOut != nil || in == out {
+		return opts, errors.New("-out must be a different folder than -in")
+	}
+	if opts.interval < time.Second {
+		return opts, errors.New("-interval must be at least 1s")
+	}
+	policy, err := retention.policy()
+	if err != nil {
+		return opts, err
+	}
+	opts.retention = policy
+	fs.Visit(func(f *flag.Flag) {
+		opts.retentionSet = opts.retentionSet || f.Name == "keep-last" || f.Name == "max-age-days"
+	})
+	opts.inputDir, opts.outDir = in, out
+	return opts, nil
+}
+
+// runWatch implements the "watch" subcommand and returns the process exit code.
+// Why: Departments drop legacy workbooks into a shared folder and expect Unicode
+// copies to appear without anyone starting the GUI.
+func runWatch(args []string, stdout, stderr io.Writer) int {
+	opts, err := parseWatchArgs(args, stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			_, _ = fmt.Fprintln(stderr, "Error:", err)
+		}
+		return 2
+	}
+
+	// SIGTERM is how systemd and container runtimes stop the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := watchFolder(ctx, opts, stdout, stderr); err != nil {
+		_, _ = fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
+
+// watchFolder converts every new or changed workbook in opts.inputDir until ctx is cancelled.
+// Converted files are remembered by size and modification time while the loop runs, and in
+// the result cache across restarts, so pruned outputs are not regenerated.
+func watchFolder(ctx context.Context, opts watchOptions, stdout, stderr io.Writer) error {
+	w, err := newFolderWatcher(opts, stdout, stderr)
+	if err != nil {
+		return err
+	}
+	w.loop(ctx)
+	return nil
+}
+
+// newFolderWatcher prepares a watch loop configured by opts and the persisted settings.
+// The caller runs it with loop.
+func newFolderWatcher(opts watchOptions, stdout, stderr io.Writer) (*folderWatcher, error) {
+	if err := os.MkdirAll(opts.outDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	sysLog := openEventLog(opts.eventLog, stderr)
+
+	w := &folderWatcher{
+		opts:      opts,
+		stdout:    stdout,
+		stderr:    stderr,
+		sysLog:    sysLog,
+		buildInfo: engine.NewBuildInfo(CurrentVersion),
+		failed:    make(map[string]time.Time),
+		handled:   make(map[string]fileVersion),
+	}
+	store, prefs := loadSettings()
+	w.prefs = prefs
+	if !opts.retentionSet {
+		w.opts.retention = retentionPolicy(prefs.RetentionKeepLast, prefs.RetentionMaxAgeDays)
+	}
+	// An invalid format fails every conversion anyway; retention then uses the default
+	w.opts.retention.TimestampLayout, _ = engine.ParseTimestampFormat(prefs.TimestampFormat)
+	filter, err := fileFilter(prefs)
+	if err != nil {
+		_ = sysLog.Close()
+		return nil, err
+	}
+	w.filter = filter
+	if store != nil {
+		results, err := cache.Open(filepath.Join(filepath.Dir(store.Path()), "results.json"))
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, "Warning: result cache unavailable:", err)
+		}
+		w.results = results
+	}
+	return w, nil
+}
+
+// loop checks the input folder every interval until ctx is cancelled.
+func (w *folderWatcher) loop(ctx context.Context) {
+	defer func() { _ = w.sysLog.Close() }()
+	_, _ = fmt.Fprintln(w.stdout, w.buildInfo)
+	logEvent(w.sysLog, false,
+		fmt.Sprintf("Watching %s, saving to %s. %s", w.opts.inputDir, w.opts.outDir, w.buildInfo), w.stderr)
+
+	ticker := time.NewTicker(w.opts.interval)
+	defer ticker.Stop()
+	for {
+		if err := w.poll(ctx); err != nil {
+			logEvent(w.sysLog, true, fmt.Sprintf("Failed to read %s: %v", w.opts.inputDir, err), w.stderr)
+		}
+		w.prune()
+		select {
+		case <-ctx.Done():
+			logEvent(w.sysLog, false, fmt.Sprintf("Stopped watching %s: %d converted, %d failed.",
+				w.opts.inputDir, w.converted, w.failures), w.stderr)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fileVersion identifies one version of an input file without reading it.
+type fileVersion struct {
+	size    int64
+	modTime time.Time
+}
+
+// folderWatcher holds the state of one watch loop. It is used by a single goroutine.
+type folderWatcher struct {
+	opts      watchOptions
+	stdout    io.Writer
+	stderr    io.Writer
+	sysLog    eventlog.Logger
+	buildInfo engine.BuildInfo
+	prefs     settings.Settings
+	filter    engine.FileFilter
+	results   *cache.ResultCache // nil disables skipping files converted before a restart
+	// failed remembers the modification time of files that failed, so they are
+	// retried only after they change
+	failed map[string]time.Time
+	// handled remembers the version of each input converted (now or before a restart), so
+	// unchanged files are neither rehashed nor reconverted on every poll
+	handled   map[string]fileVersion
+	converted int
+	failures  int
+	// onFile, if not nil, is called after each conversion with its output or error
+	onFile func(input, output string, err error)
+}
+
+// poll converts the settled workbooks currently in the input folder.
+func (w *folderWatcher) poll(ctx context.Context) error {
+	entries, err := os.ReadDir(w.opts.inputDir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if e.IsDir() || !engine.IsWorkbook(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || time.Since(info.ModTime()) < watchSettleTime || w.filter.Skip(e.Name(), info.Size()) {
+			continue
+		}
+		path := filepath.Join(w.opts.inputDir, e.Name())
+		if modTime, ok := w.failed[path]; ok && modTime.Equal(info.ModTime()) {
+			continue
+		}
+		version := fileVersion{size: info.Size(), modTime: info.ModTime()}
+		if handled, ok := w.handled[path]; ok && handled.size == version.size && handled.modTime.Equal(version.modTime) {
+			continue
+		}
+		w.convert(ctx, path, version)
+	}
+	return nil
+}
+
+// prune deletes the outputs the retention policy no longer keeps.
+func (w *folderWatcher) prune() {
+	if err := pruneOutputs(w.opts.outDir, w.opts.retention, w.stdout); err != nil {
+		logEvent(w.sysLog, true, fmt.Sprintf("Failed to clean up %s: %v", w.opts.outDir, err), w.stderr)
+	}
+}
+
+// convert converts one workbook unless an identical input was converted before, even if
+// that output has since been deleted.
+func (w *folderWatcher) convert(ctx context.Context, path string, version fileVersion) {
+	settingsKey := fmt.Sprintf("watch;out=%s;font=%s;detector=%s;vni=%s;normalization=%s;maxlen=%d;"+
+		"keepsheetnames=%t;remapstylefonts=%t;plaincells=%t;version=%s",
+		w.opts.outDir, w.prefs.FontPolicy, w.prefs.Detector, w.prefs.VNIStrictness, w.prefs.OutputNormalization,
+		w.prefs.MaxCellLength, w.prefs.KeepSheetNames, w.prefs.RemapStyleFonts, w.prefs.PlainCells,
+		w.buildInfo.AppVersion)
+	inputHash, err := cache.HashFile(path)
+	if err == nil && w.results != nil {
+		if w.results.Seen(inputHash, settingsKey) {
+			w.handled[path] = version
+			return
+		}
+	}
+
+	outputPath, err := w.run(ctx, path)
+	if err != nil {
+		if ctx.Err() != nil {
+			return // Stopping; the file is picked up again on the next start
+		}
+		w.failures++
+		w.failed[path] = version.modTime
+		_, _ = fmt.Fprintf(w.stderr, "FAIL %s: %v\n", path, err)
+		logEvent(w.sysLog, true, fmt.Sprintf("Failed to convert %s: %v", path, err), w.stderr)
+		if w.onFile != nil {
+			w.onFile(path, "", err)
+		}
+		return
+	}
+	w.converted++
+	delete(w.failed, path)
+	w.handled[path] = version
+	_, _ = fmt.Fprintf(w.stdout, "OK   %s -> %s\n", path, outputPath)
+	if w.onFile != nil {
+		w.onFile(path, outputPath, nil)
+	}
+	if w.results != nil && inputHash != "" {
+		if err := w.results.Store(inputHash, settingsKey, outputPath); err != nil {
+			_, _ = fmt.Fprintln(w.stderr, "Warning: failed to update result cache:", err)
+		}
+	}
+}
+
+// run configures a processor from the persisted settings and converts path.
+func (w *folderWatcher) run(ctx context.Context, path string) (string, error) {
+	policy, err := engine.NewFontPolicy(w.prefs.FontPolicy)
+	if err != nil {
+		return "", err
+	}
+	detector, err := engine.NewDetector(w.prefs.Detector)
+	if err != nil {
+		return "", err
+	}
+	strictness, err := converter.ParseVNIStrictness(w.prefs.VNIStrictness)
+	if err != nil {
+		return "", err
+	}
+	normalization, err := converter.ParseNormalization(w.prefs.OutputNormalization)
+	if err != nil {
+		return "", err
+	}
+	p := engine.NewProcessor(path, "")
+	p.SetBuildInfo(w.buildInfo)
+	p.SetFontPolicy(policy)
+	p.SetVNIStrictness(strictness)
+	p.SetNormalization(normalization)
+	p.SetDetector(detector)
+	if err := p.SetTimestampFormat(w.prefs.TimestampFormat); err != nil {
+		return "", err
+	}
+	p.SetMaxCellLength(w.prefs.MaxCellLength)
+	p.SetConvertSheetNames(!w.prefs.KeepSheetNames)
+	p.SetRemapStyleFonts(w.prefs.RemapStyleFonts)
+	p.SetPlainCells(w.prefs.PlainCells)
+	p.SetSharedOutput(w.prefs.SharedOutput)
+	p.SetOutputDir(w.opts.outDir)
+	return p.Run(ctx)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"convert-vni-to-unicode/internal/settings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// WatchStatus describes the watch folder of the GUI. It is sent with "watch:status" events.
+type WatchStatus struct {
+	Running   bool   `json:"running"`
+	InputDir  string `json:"inputDir"`
+	OutputDir string `json:"outputDir"`
+	// Converted and Failed count the files of the current or last watch
+	Converted int `json:"converted"`
+	Failed    int `json:"failed"`
+	// LastInput is the last file converted or failed, with its output or error
+	LastInput  string    `json:"lastInput,omitempty"`
+	LastOutput string    `json:"lastOutput,omitempty"`
+	LastError  string    `json:"lastError,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+}
+
+// WatchFile is the outcome of one watched file, sent with "watch:file" events.
+type WatchFile struct {
+	Input  string `json:"input"`
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// StartWatch converts every workbook dropped into inputDir in the background, saving the
+// outputs to outputDir, until StopWatch or the app closes. It works like the "watch"
+// subcommand with the persisted settings, and the folders are remembered for next time.
+// Why: Left running, the app becomes a drop box that converts for a whole office.
+func (a *App) StartWatch(inputDir, outputDir string) error {
+	a.watchMu.Lock()
+	defer a.watchMu.Unlock()
+	if a.watchCancel != nil {
+		return fmt.Errorf("already watching %s", a.watchStatus.InputDir)
+	}
+	opts, err := parseWatchArgs([]string{"-in", inputDir, "-out", outputDir}, io.Discard)
+	if err != nil {
+		return err
+	}
+	w, err := newFolderWatcher(opts, io.Discard, io.Discard)
+	if err != nil {
+		return err
+	}
+	w.onFile = a.watchedFile
+
+	if a.settings != nil {
+		if err := a.settings.Update(func(s *settings.Settings) {
+			s.WatchInputDir, s.WatchOutputDir = opts.inputDir, opts.outDir
+		}); err != nil {
+			slog.Warn("failed to save watch folders", "error", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	a.watchCancel, a.watchDone = cancel, done
+	a.watchStatus = WatchStatus{Running: true, InputDir: opts.inputDir, OutputDir: opts.outDir, StartedAt: time.Now()}
+	go func() {
+		defer close(done)
+		w.loop(ctx)
+	}()
+	slog.Info("watch folder started", "input", opts.inputDir, "output", opts.outDir)
+	runtime.EventsEmit(a.ctx, "watch:status", a.watchStatus)
+	return nil
+}
+
+// StopWatch stops the watch folder and waits for the file being converted, if any.
+func (a *App) StopWatch() {
+	a.watchMu.Lock()
+	cancel, done := a.watchCancel, a.watchDone
+	a.watchMu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+
+	a.watchMu.Lock()
+	// A concurrent StopWatch may have finished first
+	if a.watchDone != done {
+		a.watchMu.Unlock()
+		return
+	}
+	a.watchCancel, a.watchDone = nil, nil
+	a.watchStatus.Running = false
+	status := a.watchStatus
+	a.watchMu.Unlock()
+	slog.Info("watch folder stopped", "input", status.InputDir, "converted", status.Converted, "failed", status.Failed)
+	runtime.EventsEmit(a.ctx, "watch:status", status)
+}
+
+// GetWatchStatus returns the state of the watch folder. Before the first start, the
+// folders are those of the last session.
+func (a *App) GetWatchStatus() WatchStatus {
+	a.watchMu.Lock()
+	status := a.watchStatus
+	a.watchMu.Unlock()
+	if !status.Running && status.InputDir == "" {
+		prefs := a.loadSettings()
+		status.InputDir, status.OutputDir = prefs.WatchInputDir, prefs.WatchOutputDir
+	}
+	return status
+}
+
+// watchedFile records the outcome of a watched file and reports it to the frontend.
+// It is called from the watch loop.
+func (a *App) watchedFile(input, output string, err error) {
+	file := WatchFile{Input: input, Output: output}
+	if err != nil {
+		file.Error = err.Error()
+		slog.Warn("watch folder conversion failed", "file", input, "error", err)
+	}
+
+	a.watchMu.Lock()
+	if err != nil {
+		a.watchStatus.Failed++
+	} else {
+		a.watchStatus.Converted++
+	}
+	a.watchStatus.LastInput, a.watchStatus.LastOutput, a.watchStatus.LastError = input, output, file.Error
+	status := a.watchStatus
+	a.watchMu.Unlock()
+
+	if err == nil {
+		a.mu.Lock()
+		a.lastOutput = output
+		a.mu.Unlock()
+	}
+	runtime.EventsEmit(a.ctx, "watch:file", file)
+	runtime.EventsEmit(a.ctx, "watch:status", status)
+}